@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// ErrAccessDenied is the error a FieldAccessFn returns to deny the access to a
+// field, the evaluation failing with an error matching it
+var ErrAccessDenied = errors.New("access denied")
+
+// FieldAccess is the resolution of a selector in a datum, see FieldAccessFn
+type FieldAccess struct {
+	// Path is the path of the selector, such as [Meta env]. The selectors
+	// starting with names bound by let expressions are not resolved in the
+	// datum, the value bound having been resolved with the selector it is
+	// bound to.
+	Path []string
+	// Datum is the datum the selector is resolved in
+	Datum interface{}
+	// Value is the value found, nil if the selector is not Found
+	Value interface{}
+	Found bool
+}
+
+// FieldAccessFn authorizes the access to the value found at a selector: it
+// returns the value as is to allow it, a redacted value to substitute it, or
+// an error, such as ErrAccessDenied, to fail the evaluation. The value found
+// at a selector holds the values below it, so that a function guarding
+// Meta.secret is to redact it from the value of Meta too. It is called for
+// the selectors not found too, returning a nil value keeping them missing,
+// so that it can deny probing for fields.
+type FieldAccessFn func(access FieldAccess) (interface{}, error)
+
+// WithFieldAccess authorizes the access to the fields of the datums with the
+// function, called each time a selector is resolved. Given to Evaluate, it
+// applies the access control list of the user the evaluation is made for.
+func WithFieldAccess(fn FieldAccessFn) Option {
+	return func(o *options) {
+		o.withFieldAccess = fn
+	}
+}
+
+// getAccessedValue resolves the selector in the datum and passes the value
+// found to the FieldAccessFn of the options
+func getAccessedValue(fn FieldAccessFn, expressionValue *grammar.MatchValue, datum interface{}, opt ...Option) (interface{}, error) {
+	opt = append(opt[:len(opt):len(opt)], func(o *options) {
+		o.withFieldAccess = nil
+	})
+	val, err := getValue(expressionValue, datum, opt...)
+	if err != nil && !errors.Is(err, ErrUnknownSelector) {
+		return val, err
+	}
+	access := FieldAccess{Path: expressionValue.Selector.Path, Datum: datum, Value: val, Found: err == nil && !isUndefined(val)}
+	if !access.Found {
+		access.Value = nil
+	}
+	result, accessErr := fn(access)
+	if accessErr != nil {
+		return &undefined, fmt.Errorf("error accessing %s: %w", expressionValue.Selector, accessErr)
+	}
+	if !access.Found && result == nil {
+		return val, err
+	}
+	return convertNumber(result, getOpts(opt...).withDecimal)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// aclAccess denies the fields below Internal, redacts Meta.secret and the
+// secrets of Meta, and records the paths accessed
+func aclAccess(mu *sync.Mutex, accessed *[]string) FieldAccessFn {
+	return func(access FieldAccess) (interface{}, error) {
+		path := strings.Join(access.Path, ".")
+		mu.Lock()
+		*accessed = append(*accessed, path)
+		mu.Unlock()
+		switch {
+		case strings.HasPrefix(path, "Internal"):
+			return nil, ErrAccessDenied
+		case path == "Meta.secret":
+			return "redacted", nil
+		case path == "Meta":
+			meta := map[string]interface{}{}
+			for k, v := range access.Value.(map[string]interface{}) {
+				if k != "secret" {
+					meta[k] = v
+				}
+			}
+			return meta, nil
+		case path == "Missing":
+			return "default", nil
+		}
+		return access.Value, nil
+	}
+}
+
+func TestFieldAccess(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Name":     "web",
+		"Port":     80,
+		"Meta":     map[string]interface{}{"env": "prod", "secret": "s3cr3t"},
+		"Internal": map[string]interface{}{"ID": 1},
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		accessed   []string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"allowed":        {expression: `Name == "web" and Port > 8`, result: true, accessed: []string{"Name", "Port"}},
+		"redacted":       {expression: `Meta.secret == "s3cr3t"`, result: false, accessed: []string{"Meta.secret"}},
+		"redacted value": {expression: `Meta.secret == "redacted"`, result: true, accessed: []string{"Meta.secret"}},
+		"redacted map":   {expression: `"secret" in Meta`, result: false, accessed: []string{"Meta"}},
+		"let":            {expression: `let m = Meta in m.secret is empty`, result: true, accessed: []string{"Meta"}},
+		"denied":         {expression: `Name == "web" and Internal.ID == 1`, accessed: []string{"Name", "Internal.ID"}, err: `1:19 (18): Internal.ID == 1: error accessing Internal.ID: access denied`},
+		"missing":        {expression: `Other is empty`, accessed: []string{"Other"}, err: `1:1 (0): Other is empty: error finding value in datum: /Other at part 0: couldn't find key "Other"`},
+		"missing nested": {expression: `Meta.region is empty`, result: true, accessed: []string{"Meta.region"}},
+		"denied missing": {expression: `Internal.Other == 1`, accessed: []string{"Internal.Other"}, err: `1:1 (0): Internal.Other == 1: error accessing Internal.Other: access denied`},
+		"substituted":    {expression: `Missing == "default"`, result: true, accessed: []string{"Missing"}},
+		"right operand":  {expression: `Name == Meta.env`, result: false, accessed: []string{"Name", "Meta.env"}},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var accessed []string
+			eval, err := CreateEvaluator(tc.expression, WithFieldAccess(aclAccess(&mu, &accessed)))
+			require.NoError(t, err)
+			result, err := eval.Evaluate(datum)
+			require.Equal(t, tc.accessed, accessed)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				if strings.HasSuffix(tc.err, "access denied") {
+					require.ErrorIs(t, err, ErrAccessDenied)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
+
+func TestFieldAccess_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Meta.secret == "s3cr3t"`)
+	require.NoError(t, err)
+	datum := map[string]interface{}{"Meta": map[string]interface{}{"secret": "s3cr3t"}}
+
+	result, err := eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	// the function given to an evaluation applies to it only
+	var mu sync.Mutex
+	var accessed []string
+	result, err = eval.Evaluate(datum, WithFieldAccess(aclAccess(&mu, &accessed)))
+	require.NoError(t, err)
+	require.Equal(t, false, result)
+	require.Equal(t, []string{"Meta.secret"}, accessed)
+
+	result, err = eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+}
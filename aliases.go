@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// fieldAlias is the path the selectors starting with the alias select
+type fieldAlias struct {
+	alias selectorPattern
+	path  selectorPattern
+}
+
+// WithFieldAliases maps the names of the selectors users write to the paths
+// of the datum, such as "region" to "Meta.labels.region": `region == "eu"`
+// selects Meta.labels.region, and `region.code` Meta.labels.region.code. An
+// alias is a selector, such as "region" or "Meta.region", matching the
+// selectors it is a prefix of, the longest alias matching a selector
+// applying. The aliases are expanded when the evaluator is created, after the
+// macros and before the selector prefix, so that the selectors of the
+// evaluator, as listed by Fields and reported by errors, are the paths. The
+// selectors anchored at the root of the datum with "$", the references to the
+// names bound by let expressions and the paths are not aliases.
+func WithFieldAliases(aliases map[string]string) Option {
+	keys := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		keys = append(keys, alias)
+	}
+	sort.Strings(keys)
+	return func(o *options) {
+		for _, alias := range keys {
+			o.withFieldAliases = append(o.withFieldAliases, fieldAlias{
+				alias: newSelectorPattern(alias),
+				path:  newSelectorPattern(aliases[alias]),
+			})
+		}
+	}
+}
+
+// validate reports the aliases and the paths which are not selectors
+func (a fieldAlias) validate() error {
+	if err := a.alias.validate(); err != nil {
+		return err
+	}
+	if err := a.path.validate(); err != nil {
+		return fmt.Errorf("invalid path of %q: %w", a.alias.pattern, err)
+	}
+	return nil
+}
+
+// aliasSelectors expands the aliases of the selectors of the expression
+func aliasSelectors(ast grammar.Expression, aliases []fieldAlias) grammar.Expression {
+	return rewriteSelectors(ast, func(sel grammar.Selector) grammar.Selector {
+		var match *fieldAlias
+		for i := range aliases {
+			a := &aliases[i]
+			if len(a.alias.parts) > len(sel.Path) || (match != nil && len(a.alias.parts) <= len(match.alias.parts)) {
+				continue
+			}
+			if equalParts(a.alias.parts, sel.Path[:len(a.alias.parts)]) && !rangeWithin(sel.Ranges, len(a.alias.parts)) {
+				match = a
+			}
+		}
+		if match == nil {
+			return sel
+		}
+		rest := sel.Path[len(match.alias.parts):]
+		path := append(append(make([]string, 0, len(match.path.parts)+len(rest)), match.path.parts...), rest...)
+		return grammar.Selector{Type: sel.Type, Path: path, Ranges: shiftRanges(sel.Ranges, len(match.path.parts)-len(match.alias.parts))}
+	}, nil)
+}
+
+// rangeWithin reports whether a range splits the first n parts of a path,
+// such as the one of a[0:1].b for the alias a.b
+func rangeWithin(ranges []grammar.SelectorRange, n int) bool {
+	for _, r := range ranges {
+		if r.At < n {
+			return true
+		}
+	}
+	return false
+}
+
+func equalParts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFieldAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string]string{
+		"region":      "Meta.labels.region",
+		"region.name": "Meta.regionName",
+		"owner":       "/Meta/labels/team.owner",
+	}
+	datum := map[string]interface{}{
+		"Name": "web",
+		"Meta": map[string]interface{}{
+			"labels":     map[string]interface{}{"region": map[string]interface{}{"code": "eu"}, "team.owner": "ops"},
+			"regionName": "Europe",
+		},
+		"region": "top",
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		formatted  string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"alias": {
+			expression: `region.code == "eu"`,
+			formatted:  `Meta.labels.region.code == "eu"`,
+			result:     true,
+		},
+		"longest alias": {
+			expression: `region.name == "Europe"`,
+			formatted:  `Meta.regionName == "Europe"`,
+			result:     true,
+		},
+		"json pointer path": {
+			expression: `owner == "ops"`,
+			result:     true,
+		},
+		"not an alias": {
+			expression: `Name == "web" and regional is empty`,
+			err:        `1:19 (18): regional is empty: error finding value in datum: /regional at part 0: couldn't find key "regional"`,
+		},
+		"anchored": {
+			expression: `$.region == "top"`,
+			formatted:  `$.region == "top"`,
+			result:     true,
+		},
+		"let": {
+			expression: `let region = Name in region == "web"`,
+			formatted:  `let region = Name in region == "web"`,
+			result:     true,
+		},
+		"let value": {
+			expression: `let r = region in r.code == "eu"`,
+			formatted:  `let r = Meta.labels.region in r.code == "eu"`,
+			result:     true,
+		},
+		"error": {
+			expression: `region.zone.id == "a"`,
+			err:        `1:1 (0): Meta.labels.region.zone.id == "a": error finding value in datum: /Meta/labels/region/zone/id at part 3: couldn't find key "zone"`,
+		},
+		"prefix": {
+			expression: `region.code == "eu"`,
+			opts:       []Option{WithSelectorPrefix("Spec")},
+			formatted:  `$.Spec.Meta.labels.region.code == "eu"`,
+			err:        `1:1 (0): $.Spec.Meta.labels.region.code == "eu": error finding value in datum: /Spec/Meta/labels/region/code at part 0: couldn't find key "Spec"`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append([]Option{WithFieldAliases(aliases)}, tcase.opts...)...)
+			require.NoError(t, err)
+			if tcase.formatted != "" {
+				require.Equal(t, tcase.formatted, formatExpression(eval.ast))
+			}
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestWithFieldAliases_Fields(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`region == "eu" and env in tags`,
+		WithFieldAliases(map[string]string{"region": "Meta.region", "tags": "Meta.tags"}))
+	require.NoError(t, err)
+
+	var fields []string
+	for _, sel := range eval.Fields() {
+		fields = append(fields, sel.String())
+	}
+	require.Equal(t, []string{"Meta.region", "Meta.tags", "env"}, fields)
+}
+
+func TestWithFieldAliases_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluator(`region == "eu"`, WithFieldAliases(map[string]string{"": "Meta.region"}))
+	require.EqualError(t, err, `invalid options: invalid field alias: the pattern cannot be empty`)
+	_, err = CreateEvaluator(`region == "eu"`, WithFieldAliases(map[string]string{"region": "Meta..region"}))
+	require.EqualError(t, err, `invalid options: invalid field alias: invalid path of "region": the pattern "Meta..region" has empty parts`)
+}
@@ -0,0 +1,14 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package api holds the types bexpr reports to its callers: the match
+// operators, the errors of the evaluation, the outcomes of three-valued logic
+// and the nodes of traces and explanations. The bexpr and grammar packages
+// alias them, so that they can be used interchangeably.
+//
+// The package only depends on the standard library, so that projects can
+// expose these types in their own public APIs without depending on the
+// evaluator. Its types follow semantic versioning within the major version of
+// the module: fields and constants are added, never renamed, removed or
+// changed, and the strings their String and Error methods return are stable.
+package api
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrUnsupportedOperator matches the *UnsupportedOperatorError errors
+	// with errors.Is
+	ErrUnsupportedOperator = errors.New("unsupported operator")
+	// ErrTypeMismatch matches the *TypeMismatchError errors with errors.Is
+	ErrTypeMismatch = errors.New("type mismatch")
+)
+
+// UnsupportedOperatorError is returned when a match operator is applied to a
+// value of a type it does not support.
+type UnsupportedOperatorError struct {
+	Operator MatchOperator
+	Type     reflect.Type
+}
+
+func (e *UnsupportedOperatorError) Error() string {
+	return fmt.Sprintf("operator %q cannot be used with values of type %s", e.Operator, e.Type)
+}
+
+func (e *UnsupportedOperatorError) Is(target error) bool {
+	return target == ErrUnsupportedOperator
+}
+
+// TypeMismatchError is returned, with strict types, when a match operator is
+// applied to values of types it does not compare without coercion.
+type TypeMismatchError struct {
+	Operator MatchOperator
+	// Left is the type of the left hand side of the operator, or the type
+	// of the elements or keys of the collection for "in" and "contains".
+	Left  reflect.Type
+	Right reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf("operator %q cannot compare values of type %s and %s with strict types", e.Operator, e.Left, e.Right)
+}
+
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
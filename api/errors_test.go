@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	require.EqualError(t,
+		&UnsupportedOperatorError{Operator: MatchLower, Type: reflect.TypeOf("")},
+		`operator "Lower" cannot be used with values of type string`)
+	require.EqualError(t,
+		&TypeMismatchError{Operator: MatchEqual, Left: reflect.TypeOf(""), Right: reflect.TypeOf(1)},
+		`operator "Equal" cannot compare values of type string and int with strict types`)
+
+	require.ErrorIs(t, &UnsupportedOperatorError{Operator: MatchLower, Type: reflect.TypeOf("")}, ErrUnsupportedOperator)
+	require.ErrorIs(t, &TypeMismatchError{Operator: MatchEqual, Left: reflect.TypeOf(""), Right: reflect.TypeOf(1)}, ErrTypeMismatch)
+	require.NotErrorIs(t, &TypeMismatchError{Operator: MatchEqual, Left: reflect.TypeOf(""), Right: reflect.TypeOf(1)}, ErrUnsupportedOperator)
+}
@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+// Clause is a clause of an expression, a match expression or a value used as
+// a boolean, with its outcome
+type Clause struct {
+	// Clause is the clause in the bexpr syntax
+	Clause string
+	Result bool
+}
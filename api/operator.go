@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+// MatchOperator is the operator of a match expression, such as == or "in"
+type MatchOperator int
+
+const (
+	MatchEqual MatchOperator = iota
+	MatchNotEqual
+	MatchIn
+	MatchNotIn
+	MatchIsEmpty
+	MatchIsNotEmpty
+	MatchMatches
+	MatchNotMatches
+	MatchLower
+	MatchLowerOrEqual
+	MatchHigher
+	MatchHigherOrEqual
+	MatchIsNull
+	MatchIsNotNull
+	MatchStartsWith
+	MatchNotStartsWith
+	MatchEndsWith
+	MatchNotEndsWith
+	MatchLike
+	MatchNotLike
+)
+
+func (op MatchOperator) String() string {
+	switch op {
+	case MatchEqual:
+		return "Equal"
+	case MatchNotEqual:
+		return "Not Equal"
+	case MatchIn:
+		return "In"
+	case MatchNotIn:
+		return "Not In"
+	case MatchIsEmpty:
+		return "Is Empty"
+	case MatchIsNotEmpty:
+		return "Is Not Empty"
+	case MatchMatches:
+		return "Matches"
+	case MatchNotMatches:
+		return "Not Matches"
+	case MatchLower:
+		return "Lower"
+	case MatchHigher:
+		return "Higher"
+	case MatchLowerOrEqual:
+		return "Lower or Equal"
+	case MatchHigherOrEqual:
+		return "Higher or Equal"
+	case MatchIsNull:
+		return "Is Null"
+	case MatchIsNotNull:
+		return "Is Not Null"
+	case MatchStartsWith:
+		return "Starts With"
+	case MatchNotStartsWith:
+		return "Not Starts With"
+	case MatchEndsWith:
+		return "Ends With"
+	case MatchNotEndsWith:
+		return "Not Ends With"
+	case MatchLike:
+		return "Like"
+	case MatchNotLike:
+		return "Not Like"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// NotPresentDisposition is called during evaluation when Selector fails to
+// find a map key to determine the operator's behavior.
+func (op MatchOperator) NotPresentDisposition() bool {
+	// For a selector M["x"] against a map M that lacks an "x" key...
+	switch op {
+	case MatchEqual:
+		// ...M["x"] == <anything> is false. Nothing is equal to a missing key
+		return false
+	case MatchNotEqual:
+		// ...M["x"] != <anything> is true. Nothing is equal to a missing key
+		return true
+	case MatchIn:
+		// "a" in M["x"] is false. Missing keys contain no values
+		return false
+	case MatchNotIn:
+		// "a" not in M["x"] is true. Missing keys contain no values
+		return true
+	case MatchIsEmpty:
+		// M["x"] is empty is true. Missing keys contain no values
+		return true
+	case MatchIsNotEmpty:
+		// M["x"] is not empty is false. Missing keys contain no values
+		return false
+	case MatchMatches:
+		// M["x"] matches <anything> is false. Nothing matches a missing key
+		return false
+	case MatchNotMatches:
+		// M["x"] not matches <anything> is true. Nothing matches a missing key
+		return true
+	case MatchLower:
+		// ...M["x"] < <anything> is false. Nothing is higher than a missing key
+		return true
+	case MatchHigher:
+		// ...M["x"] > <anything> is false. Nothing is higher than a missing key
+		return false
+	case MatchLowerOrEqual:
+		// ...M["x"] <= <anything> is false. Nothing is higher than a missing key
+		return true
+	case MatchHigherOrEqual:
+		// ...M["x"] => <anything> is false. Nothing is higher than a missing key
+		return false
+	case MatchIsNull:
+		// M["x"] is null is true. Missing keys have no value
+		return true
+	case MatchIsNotNull:
+		// M["x"] is not null is false. Missing keys have no value
+		return false
+	case MatchStartsWith, MatchEndsWith:
+		// M["x"] startswith <anything> is false. A missing key has no prefix or suffix
+		return false
+	case MatchNotStartsWith, MatchNotEndsWith:
+		// M["x"] not startswith <anything> is true. A missing key has no prefix or suffix
+		return true
+	case MatchLike:
+		// M["x"] like <anything> is false. Nothing matches a missing key
+		return false
+	case MatchNotLike:
+		// M["x"] not like <anything> is true. Nothing matches a missing key
+		return true
+	default:
+		// Should never be reached as every operator should explicitly define its
+		// behavior.
+		return false
+	}
+}
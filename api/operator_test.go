@@ -0,0 +1,23 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchOperator_String(t *testing.T) {
+	t.Parallel()
+
+	names := make(map[string]bool)
+	for op := MatchEqual; op <= MatchNotLike; op++ {
+		name := op.String()
+		require.NotEqual(t, "UNKNOWN", name, "operator %d", op)
+		require.False(t, names[name], "operator %d is named %s like another one", op, name)
+		names[name] = true
+	}
+	require.Equal(t, "UNKNOWN", MatchOperator(-1).String())
+}
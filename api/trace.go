@@ -0,0 +1,21 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+// TraceEvent is a node of the trace of an evaluation, flattened into the list
+// of the nodes in the order they were evaluated, parents first.
+type TraceEvent struct {
+	// Depth is the depth of the node in the trace, 0 for the root
+	Depth int
+	// Expression is the node in the bexpr syntax
+	Expression string
+	// Left and Right are the values the operands of a match expression
+	// resolved to, Left being the value bound by a let expression. Selectors
+	// which were not found are reported as nil.
+	Left  interface{}
+	Right interface{}
+	// Result is the outcome of the node. It is false when Err is set.
+	Result bool
+	Err    error
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import "fmt"
+
+// Truth is the outcome of an expression evaluated with three-valued logic
+type Truth int
+
+const (
+	False Truth = iota
+	True
+	// Unknown is the outcome of a match expression whose selectors are
+	// missing, and of the logical operators it cannot be decided without.
+	Unknown
+)
+
+func (t Truth) String() string {
+	switch t {
+	case False:
+		return "false"
+	case True:
+		return "true"
+	case Unknown:
+		return "unknown"
+	}
+	return fmt.Sprintf("Truth(%d)", int(t))
+}
+
+// Bool returns the outcome as a bool, Unknown being mapped to unknownResult
+func (t Truth) Bool(unknownResult bool) bool {
+	if t == Unknown {
+		return unknownResult
+	}
+	return t == True
+}
+
+// Not returns the negation of the outcome, Unknown being kept
+func (t Truth) Not() Truth {
+	switch t {
+	case True:
+		return False
+	case False:
+		return True
+	}
+	return Unknown
+}
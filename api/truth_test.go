@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruth(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		truth Truth
+		str   string
+		not   Truth
+		bools [2]bool
+	}
+
+	tests := map[string]testCase{
+		"false":   {truth: False, str: "false", not: True, bools: [2]bool{false, false}},
+		"true":    {truth: True, str: "true", not: False, bools: [2]bool{true, true}},
+		"unknown": {truth: Unknown, str: "unknown", not: Unknown, bools: [2]bool{false, true}},
+		"invalid": {truth: Truth(7), str: "Truth(7)", not: Unknown, bools: [2]bool{false, false}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tcase.str, tcase.truth.String())
+			require.Equal(t, tcase.not, tcase.truth.Not())
+			require.Equal(t, tcase.bools[0], tcase.truth.Bool(false))
+			require.Equal(t, tcase.bools[1], tcase.truth.Bool(true))
+		})
+	}
+}
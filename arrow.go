@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/pointerstructure"
+)
+
+// ArrowArray is the subset of the arrow.Array interface of the Apache Arrow
+// Go module used to read the values of a column. Arrow arrays implement it
+// as is, so no dependency on Arrow is needed.
+type ArrowArray interface {
+	Len() int
+	IsNull(i int) bool
+	GetOneForMarshal(i int) interface{}
+}
+
+// arrowColumns exposes the columns of an Arrow record batch
+type arrowColumns struct {
+	rows   int
+	arrays map[string]ArrowArray
+}
+
+// NewArrowColumns returns the Columns of an Arrow record batch given the name
+// and array of each of its columns, to be filtered with EvaluateBatch:
+//
+//	names := make([]string, rec.NumCols())
+//	arrays := make([]bexpr.ArrowArray, rec.NumCols())
+//	for i := range arrays {
+//		names[i], arrays[i] = rec.ColumnName(i), rec.Column(i)
+//	}
+//	columns, err := bexpr.NewArrowColumns(names, arrays)
+//
+// A selector is resolved against the column whose name is the whole selector
+// joined with dots, such as "meta.port", or else against the column named
+// after the first part of the selector. In the latter case the rest of the
+// selector is looked up in the values of struct, list and map columns, and
+// values it cannot be found in are handled like missing map keys.
+func NewArrowColumns(names []string, arrays []ArrowArray) (Columns, error) {
+	if len(names) != len(arrays) {
+		return nil, fmt.Errorf("got %d column names for %d arrays", len(names), len(arrays))
+	}
+	columns := &arrowColumns{arrays: make(map[string]ArrowArray, len(arrays))}
+	for i, array := range arrays {
+		if i == 0 {
+			columns.rows = array.Len()
+		} else if array.Len() != columns.rows {
+			return nil, fmt.Errorf("column %q has %d rows, expected %d", names[i], array.Len(), columns.rows)
+		}
+		columns.arrays[names[i]] = array
+	}
+	return columns, nil
+}
+
+func (c *arrowColumns) Len() int {
+	return c.rows
+}
+
+func (c *arrowColumns) Column(path []string) (Column, bool, error) {
+	if array, ok := c.arrays[strings.Join(path, ".")]; ok {
+		return arrowColumn{array: array}, true, nil
+	}
+	if array, ok := c.arrays[path[0]]; ok {
+		return arrowColumn{array: array, path: path[1:]}, true, nil
+	}
+	return nil, false, nil
+}
+
+// arrowColumn reads the values of an array, optionally looking up a path
+// inside of each value.
+type arrowColumn struct {
+	array ArrowArray
+	path  []string
+}
+
+func (c arrowColumn) Value(row int) interface{} {
+	if c.array.IsNull(row) {
+		return nil
+	}
+	value := arrowValue(c.array.GetOneForMarshal(row))
+	if len(c.path) == 0 || value == nil {
+		return value
+	}
+
+	ptr := pointerstructure.Pointer{Parts: c.path}
+	value, err := ptr.Get(value)
+	if err != nil {
+		// handled the same as a missing map key
+		return &undefined
+	}
+	return value
+}
+
+// arrowValue decodes the JSON Arrow uses to marshal list and map values
+func arrowValue(value interface{}) interface{} {
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		return value
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return value
+	}
+	return decoded
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testArrowArray mimics an Arrow array. Like Arrow's list arrays, slices are
+// marshaled to JSON by GetOneForMarshal.
+type testArrowArray []interface{}
+
+func (a testArrowArray) Len() int { return len(a) }
+
+func (a testArrowArray) IsNull(i int) bool { return a[i] == nil }
+
+func (a testArrowArray) GetOneForMarshal(i int) interface{} {
+	switch v := a[i].(type) {
+	case []interface{}:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			panic(err)
+		}
+		return json.RawMessage(raw)
+	default:
+		return v
+	}
+}
+
+func TestArrowColumns(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"id", "name", "meta", "tags", "meta.port"}
+	arrays := []ArrowArray{
+		testArrowArray{int64(1), int64(2), int64(3), int64(4)},
+		testArrowArray{"web", "db", nil, "cache"},
+		testArrowArray{
+			map[string]interface{}{"env": "prod", "zone": int32(1)},
+			map[string]interface{}{"env": "dev", "zone": int32(2)},
+			nil,
+			map[string]interface{}{"env": "prod", "zone": nil},
+		},
+		testArrowArray{
+			[]interface{}{"a", "b"},
+			[]interface{}{},
+			[]interface{}{1, 2},
+			nil,
+		},
+		testArrowArray{uint16(80), uint16(5432), uint16(443), nil},
+	}
+	columns, err := NewArrowColumns(names, arrays)
+	require.NoError(t, err)
+	require.Equal(t, 4, columns.Len())
+
+	type testCase struct {
+		expression string
+		rows       []int
+	}
+
+	tests := map[string]testCase{
+		"int column":          {expression: `id >= 3`, rows: []int{2, 3}},
+		"string column":       {expression: `name like "*b*"`, rows: []int{0, 1}},
+		"null values":         {expression: `name is null or meta.port is null`, rows: []int{2, 3}},
+		"struct field":        {expression: `meta.env == "prod" and meta.zone == 1`, rows: []int{0}},
+		"missing field":       {expression: `meta.region is empty`, rows: []int{0, 1, 2, 3}},
+		"list column":         {expression: `"b" in tags or 2 in tags`, rows: []int{0, 2}},
+		"list index":          {expression: `tags.0 == "a"`, rows: []int{0}},
+		"dotted column name":  {expression: `meta.port > 100`, rows: []int{1, 2}},
+		"missing column":      {expression: `owner != "x"`, rows: []int{0, 1, 2, 3}},
+		"empty list is empty": {expression: `tags is empty`, rows: []int{1, 3}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			mask, err := eval.EvaluateBatch(columns)
+			require.NoError(t, err)
+			require.Equal(t, tcase.rows, mask.Rows())
+		})
+	}
+}
+
+func TestNewArrowColumns_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewArrowColumns([]string{"a"}, nil)
+	require.EqualError(t, err, "got 1 column names for 0 arrays")
+
+	_, err = NewArrowColumns([]string{"a", "b"}, []ArrowArray{testArrowArray{1, 2}, testArrowArray{1}})
+	require.EqualError(t, err, `column "b" has 1 rows, expected 2`)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Columns is a batch of rows stored column by column, such as Arrow record
+// batches or decoded Parquet row groups.
+type Columns interface {
+	// Len returns the number of rows in the batch
+	Len() int
+	// Column returns the values of the selector for every row of the batch.
+	// The second return value is false when the batch has no such column, in
+	// which case the selector is handled like a missing map key.
+	Column(path []string) (Column, bool, error)
+}
+
+// Column is a vector holding one value per row
+type Column interface {
+	// Value returns the value of the row. Null values are returned as nil.
+	Value(row int) interface{}
+}
+
+// SliceColumn is a Column backed by a slice of values
+type SliceColumn []interface{}
+
+func (c SliceColumn) Value(row int) interface{} {
+	return c[row]
+}
+
+// Bitmask holds one bit per row of a batch, set for the rows which matched
+type Bitmask []uint64
+
+func newBitmask(n int) Bitmask {
+	return make(Bitmask, (n+63)/64)
+}
+
+// allRows returns a bitmask with the first n bits set
+func allRows(n int) Bitmask {
+	b := newBitmask(n)
+	for i := range b {
+		b[i] = ^uint64(0)
+	}
+	if rem := n % 64; rem != 0 {
+		b[len(b)-1] = (uint64(1) << rem) - 1
+	}
+	return b
+}
+
+// Get reports whether the bit of the row is set
+func (b Bitmask) Get(row int) bool {
+	return b[row/64]&(uint64(1)<<(row%64)) != 0
+}
+
+func (b Bitmask) set(row int) {
+	b[row/64] |= uint64(1) << (row % 64)
+}
+
+// Count returns the number of bits set
+func (b Bitmask) Count() int {
+	count := 0
+	for _, word := range b {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// Rows returns the indexes of the rows whose bit is set, in ascending order
+func (b Bitmask) Rows() []int {
+	rows := make([]int, 0, b.Count())
+	for i, word := range b {
+		for word != 0 {
+			rows = append(rows, i*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+	return rows
+}
+
+func (b Bitmask) and(other Bitmask) Bitmask {
+	result := make(Bitmask, len(b))
+	for i := range b {
+		result[i] = b[i] & other[i]
+	}
+	return result
+}
+
+func (b Bitmask) or(other Bitmask) Bitmask {
+	result := make(Bitmask, len(b))
+	for i := range b {
+		result[i] = b[i] | other[i]
+	}
+	return result
+}
+
+func (b Bitmask) andNot(other Bitmask) Bitmask {
+	result := make(Bitmask, len(b))
+	for i := range b {
+		result[i] = b[i] &^ other[i]
+	}
+	return result
+}
+
+// EvaluateBatch evaluates the expression against every row of the batch and
+// returns the bitmask of the rows which matched. Logical operators are applied
+// to whole bitmasks, and the right hand side of "and" and "or" is only
+// evaluated for the rows whose outcome it can still change. The values bound
+// by let expressions are looked up once per reference.
+func (eval *Evaluator) EvaluateBatch(batch Columns) (Bitmask, error) {
+	columns := &batchColumns{batch: batch, columns: make(map[string]Column)}
+	return evaluateBatch(grammar.InlineLets(eval.ast), columns, allRows(batch.Len()), eval.evaluateOpts()...)
+}
+
+// batchColumns fetches each column of the batch once
+type batchColumns struct {
+	batch   Columns
+	columns map[string]Column
+}
+
+func (c *batchColumns) column(path []string) (Column, bool, error) {
+	key := strings.Join(path, "\x00")
+	if column, ok := c.columns[key]; ok {
+		return column, column != nil, nil
+	}
+	column, ok, err := c.batch.Column(path)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		column = nil
+	}
+	c.columns[key] = column
+	return column, ok, nil
+}
+
+// batchRow is the SelectorSource of a single row of the batch
+type batchRow struct {
+	columns *batchColumns
+	row     int
+}
+
+func (r batchRow) GetPath(path []string) (interface{}, bool, error) {
+	column, ok, err := r.columns.column(path)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return column.Value(r.row), true, nil
+}
+
+func evaluateBatch(ast grammar.Expression, columns *batchColumns, rows Bitmask, opt ...Option) (Bitmask, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		switch node.Operator {
+		case grammar.UnaryOpNot:
+			result, err := evaluateBatch(node.Operand, columns, rows, opt...)
+			if err != nil {
+				return nil, err
+			}
+			return rows.andNot(result), nil
+		}
+	case *grammar.BinaryExpression:
+		left, err := evaluateBatch(node.Left, columns, rows, opt...)
+		if err != nil {
+			return nil, err
+		}
+		switch node.Operator {
+		case grammar.BinaryOpAnd:
+			return evaluateBatch(node.Right, columns, rows.and(left), opt...)
+		case grammar.BinaryOpOr:
+			right, err := evaluateBatch(node.Right, columns, rows.andNot(left), opt...)
+			if err != nil {
+				return nil, err
+			}
+			return left.or(right), nil
+		}
+	case *grammar.MatchExpression:
+		result := make(Bitmask, len(rows))
+		for _, row := range rows.Rows() {
+			match, err := evaluateMatchExpression(node, batchRow{columns: columns, row: row}, opt...)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row, matchError(node, err))
+			}
+			if match {
+				result.set(row)
+			}
+		}
+		return result, nil
+	case *grammar.LetExpression, *grammar.ExpressionValue:
+		// boolean operands such as Enabled, the rows holding missing or null
+		// values not matching
+		result := make(Bitmask, len(rows))
+		for _, row := range rows.Rows() {
+			value, err := evaluateValue(node, batchRow{columns: columns, row: row}, opt...)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", row, err)
+			}
+			if isUndefined(value) || isNull(value) {
+				continue
+			}
+			match, err := CoerceBool(indirect(value))
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %s is not a boolean: %w", row, formatExpression(node), err)
+			}
+			if match {
+				result.set(row)
+			}
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("invalid AST node")
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBatch struct {
+	rows    int
+	columns map[string]Column
+	fetched map[string]int
+}
+
+func (b *testBatch) Len() int { return b.rows }
+
+func (b *testBatch) Column(path []string) (Column, bool, error) {
+	key := strings.Join(path, ".")
+	if key == "broken" {
+		return nil, false, errors.New("column is corrupt")
+	}
+	b.fetched[key]++
+	column, ok := b.columns[key]
+	return column, ok, nil
+}
+
+// countingColumn counts the rows whose value was read
+type countingColumn struct {
+	Column
+	reads *int
+}
+
+func (c countingColumn) Value(row int) interface{} {
+	*c.reads++
+	return c.Column.Value(row)
+}
+
+func newTestBatch(rows int) *testBatch {
+	names := make(SliceColumn, rows)
+	ports := make(SliceColumn, rows)
+	tags := make(SliceColumn, rows)
+	on := make(SliceColumn, rows)
+	for i := 0; i < rows; i++ {
+		on[i] = i%4 == 0
+		names[i] = fmt.Sprintf("svc-%d", i%7)
+		if i%5 == 0 {
+			ports[i] = nil
+		} else {
+			ports[i] = 8000 + i
+		}
+		tags[i] = []string{fmt.Sprintf("zone-%d", i%3)}
+	}
+	return &testBatch{
+		rows:    rows,
+		columns: map[string]Column{"name": names, "meta.port": ports, "tags": tags, "on": on},
+		fetched: make(map[string]int),
+	}
+}
+
+func (b *testBatch) row(i int) map[string]interface{} {
+	return map[string]interface{}{
+		"name": b.columns["name"].Value(i),
+		"meta": map[string]interface{}{"port": b.columns["meta.port"].Value(i)},
+		"tags": b.columns["tags"].Value(i),
+		"on":   b.columns["on"].Value(i),
+	}
+}
+
+func TestEvaluateBatch(t *testing.T) {
+	t.Parallel()
+
+	expressions := []string{
+		`name == "svc-3"`,
+		`meta.port > 8100 and "zone-1" in tags`,
+		`name == "svc-1" or meta.port is null`,
+		`not (name matches "svc-[0-2]" or meta.port < 8010)`,
+		`meta.missing is empty and not name like "*-6"`,
+		`on`,
+		`not on`,
+		`on and meta.port > 8050`,
+		`let enabled = on in enabled or name == "svc-2"`,
+	}
+
+	for _, expression := range expressions {
+		expression := expression
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			batch := newTestBatch(130)
+			eval, err := CreateEvaluator(expression)
+			require.NoError(t, err)
+
+			mask, err := eval.EvaluateBatch(batch)
+			require.NoError(t, err)
+
+			var expected []int
+			for i := 0; i < batch.rows; i++ {
+				result, err := eval.Evaluate(batch.row(i))
+				require.NoError(t, err)
+				require.Equal(t, result, mask.Get(i), "row %d", i)
+				if result.(bool) {
+					expected = append(expected, i)
+				}
+			}
+			require.Equal(t, len(expected), mask.Count())
+			if expected == nil {
+				expected = []int{}
+			}
+			require.Equal(t, expected, mask.Rows())
+
+			for key, fetched := range batch.fetched {
+				require.Equal(t, 1, fetched, key)
+			}
+		})
+	}
+}
+
+func TestEvaluateBatch_ShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	batch := newTestBatch(100)
+	reads := 0
+	batch.columns["tags"] = countingColumn{Column: batch.columns["tags"], reads: &reads}
+
+	eval, err := CreateEvaluator(`name == "svc-0" and "zone-0" in tags`)
+	require.NoError(t, err)
+
+	mask, err := eval.EvaluateBatch(batch)
+	require.NoError(t, err)
+	require.Equal(t, 5, mask.Count())
+	// tags are only read for the rows named svc-0
+	require.Equal(t, 15, reads)
+}
+
+func TestEvaluateBatch_Errors(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`name == "x" or broken == "y"`)
+	require.NoError(t, err)
+	_, err = eval.EvaluateBatch(newTestBatch(3))
+	require.EqualError(t, err, `row 0: 1:16 (15): broken == "y": error finding value in datum: column is corrupt`)
+
+	eval, err = CreateEvaluator(`tags < 3`)
+	require.NoError(t, err)
+	_, err = eval.EvaluateBatch(newTestBatch(3))
+	require.EqualError(t, err, `row 0: 1:1 (0): tags < 3: operator "Lower" cannot be used with values of type []string`)
+
+	eval, err = CreateEvaluator(`tags`)
+	require.NoError(t, err)
+	_, err = eval.EvaluateBatch(newTestBatch(3))
+	require.EqualError(t, err, `row 0: tags is not a boolean: strconv.ParseBool: parsing "[zone-0]": invalid syntax`)
+}
+
+func TestEvaluateBatch_Empty(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`name == "x"`)
+	require.NoError(t, err)
+	mask, err := eval.EvaluateBatch(newTestBatch(0))
+	require.NoError(t, err)
+	require.Zero(t, mask.Count())
+	require.Empty(t, mask.Rows())
+}
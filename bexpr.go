@@ -11,6 +11,10 @@ package bexpr
 //go:generate goimports -w grammar/grammar.go
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/gterranova/go-bexpr/grammar"
 	"github.com/mitchellh/pointerstructure"
 )
@@ -21,44 +25,309 @@ import (
 // helpful, for example, when working with protocol buffers' well-known types.
 type ValueTransformationHookFn = pointerstructure.ValueTransformationHookFn
 
+// Evaluator evaluates an expression against datums. Once created, it is safe
+// for concurrent use by multiple goroutines, provided the hooks, converters
+// and callbacks given to its options are.
 type Evaluator struct {
 	// The syntax tree
 	ast                     grammar.Expression
 	tagName                 string
+	tagNames                []string
 	valueTransformationHook ValueTransformationHookFn
+	selectorHooks           []selectorHook
+	valueConverters         []valueConverter
 	unknownVal              *interface{}
+	defaults                []selectorDefault
+	traceFn                 func(*Trace)
+	strictTypes             bool
+	strictSelectors         bool
+	fieldDocs               FieldDocs
+	unknownResult           *bool
+	params                  map[string]interface{}
+	coercions               map[grammar.ValueType]Coercion
+	selectorCoercions       []selectorCoercion
+	stats                   *Stats
+	statsRule               string
+	shadow                  *shadow
+	decimal                 bool
+	recorder                *Recorder
+	mutationCheck           bool
+	dialect                 grammar.SelectorDialect
+	deterministic           bool
+	fast                    fastExpression
+	compiled                compiledNode
+	fieldCache              *fieldCache
+	regexLimits             regexLimits
+	fieldAccess             FieldAccessFn
+	expression              string
+	observer                observer
+	tracer                  Tracer
+	logger                  Logger
+	spanAttributes          spanAttributes
 }
 
 func CreateEvaluator(expression string, opts ...Option) (*Evaluator, error) {
 	parsedOpts := getOpts(opts...)
+	obs, tracer, logger := parsedOpts.withObserver, parsedOpts.withTracer, parsedOpts.withLogger
+	if !obs.observesParses() && tracer == nil && logger == nil {
+		return createEvaluator(expression, opts...)
+	}
+	var span Span
+	if tracer != nil {
+		_, span = tracer.Start(context.Background(), SpanParse)
+	}
+	start := time.Now()
+	eval, err := createEvaluator(expression, opts...)
+	obs.parsed(expression, time.Since(start), err)
+	if span != nil {
+		endParseSpan(span, eval, err)
+	}
+	if logger != nil && err != nil {
+		logCreateFailure(logger, expression, err)
+	}
+	return eval, err
+}
+
+func createEvaluator(expression string, opts ...Option) (*Evaluator, error) {
+	parsedOpts := getOpts(opts...)
+	if err := parsedOpts.validate(); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
 	var parserOpts []grammar.Option
 	if parsedOpts.withMaxExpressions != 0 {
 		parserOpts = append(parserOpts, grammar.MaxExpressions(parsedOpts.withMaxExpressions))
 	}
+	if len(parsedOpts.withKeywordAliases) > 0 {
+		if err := grammar.ValidateKeywordAliases(parsedOpts.withKeywordAliases); err != nil {
+			return nil, fmt.Errorf("invalid keyword aliases: %w", err)
+		}
+		parserOpts = append(parserOpts, grammar.KeywordAliases(parsedOpts.withKeywordAliases))
+	}
+	if parsedOpts.withReservedKeywords {
+		parserOpts = append(parserOpts, grammar.ReservedKeywords())
+	}
+	if parsedOpts.withSelectorDialect != grammar.SelectorDialectBexpr {
+		parserOpts = append(parserOpts, grammar.Dialect(parsedOpts.withSelectorDialect))
+	}
+	// the literals of the macros are trusted, and the positions of their
+	// nodes are not within the expression
+	exprOpts := append(parserOpts[:len(parserOpts):len(parserOpts)], grammar.Positions())
+	if parsedOpts.withMaxLiteralLength != 0 {
+		exprOpts = append(exprOpts, grammar.MaxLiteralLength(parsedOpts.withMaxLiteralLength))
+	}
+	if parsedOpts.withMaxLiteralBytes != 0 {
+		exprOpts = append(exprOpts, grammar.MaxLiteralBytes(parsedOpts.withMaxLiteralBytes))
+	}
 
-	ast, err := grammar.Parse("", []byte(expression), parserOpts...)
+	parsed, err := grammar.Parse("", []byte(expression), exprOpts...)
 	if err != nil {
+		return nil, newSyntaxError(err)
+	}
+	ast := parsed.(grammar.Expression)
+	if len(parsedOpts.withMacros) > 0 {
+		expander := &macroExpander{
+			macros:       parsedOpts.withMacros,
+			parserOpts:   parserOpts,
+			selectorType: parsedOpts.withSelectorDialect.SelectorType(),
+			expanding:    make(map[string]bool),
+		}
+		if ast, err = expander.expand(ast); err != nil {
+			return nil, err
+		}
+	}
+	if len(parsedOpts.withFieldAliases) > 0 {
+		ast = aliasSelectors(ast, parsedOpts.withFieldAliases)
+	}
+	if len(parsedOpts.withSelectorPrefix) > 0 {
+		ast = prefixSelectors(ast, parsedOpts.withSelectorPrefix)
+	}
+	policy := selectorPolicy{allowed: parsedOpts.withAllowedSelectors, denied: parsedOpts.withDeniedSelectors}
+	if err := policy.check(ast); err != nil {
 		return nil, err
 	}
 
 	eval := &Evaluator{
-		ast:                     ast.(grammar.Expression),
+		ast:                     foldConstants(ast, opts...),
 		tagName:                 parsedOpts.withTagName,
+		tagNames:                parsedOpts.withTagNames,
 		valueTransformationHook: parsedOpts.withHookFn,
+		selectorHooks:           parsedOpts.withSelectorHooks,
+		valueConverters:         parsedOpts.withValueConverters,
 		unknownVal:              parsedOpts.withUnknown,
+		defaults:                parsedOpts.withDefaults,
+		traceFn:                 parsedOpts.withTrace,
+		strictTypes:             parsedOpts.withStrictTypes,
+		strictSelectors:         parsedOpts.withStrictSelectors,
+		fieldDocs:               parsedOpts.withFieldDocs,
+		unknownResult:           parsedOpts.withUnknownResult,
+		params:                  parsedOpts.withParams,
+		coercions:               parsedOpts.withCoercions,
+		selectorCoercions:       parsedOpts.withSelectorCoercions,
+		stats:                   parsedOpts.withStats,
+		statsRule:               parsedOpts.withStatsRule,
+		decimal:                 parsedOpts.withDecimal,
+		recorder:                parsedOpts.withRecorder,
+		mutationCheck:           parsedOpts.withMutationCheck,
+		dialect:                 parsedOpts.withSelectorDialect,
+		deterministic:           parsedOpts.withDeterministic,
+		fieldCache:              new(fieldCache),
+		regexLimits:             parsedOpts.withRegexLimits,
+		fieldAccess:             parsedOpts.withFieldAccess,
+		expression:              expression,
+		observer:                parsedOpts.withObserver,
+		tracer:                  parsedOpts.withTracer,
+		logger:                  parsedOpts.withLogger,
+	}
+
+	if err := eval.regexLimits.checkPatterns(eval.ast); err != nil {
+		return nil, err
+	}
+
+	if parsedOpts.withSchema != nil {
+		if err := eval.Validate(parsedOpts.withSchema); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkGlobPatterns(eval.ast); err != nil {
+		return nil, err
+	}
+
+	eval.compile()
+	if eval.tracer != nil {
+		eval.spanAttributes = newSpanAttributes(eval)
+	}
+
+	if parsedOpts.withShadow != nil {
+		if eval.shadow, err = newShadow(parsedOpts.withShadow, opts); err != nil {
+			return nil, err
+		}
 	}
 
 	return eval, nil
 }
 
-func (eval *Evaluator) Evaluate(datum interface{}) (interface{}, error) {
+// Evaluate evaluates the expression against the datum. The options given
+// apply to this evaluation only, on top of the ones the evaluator was created
+// with: they are meant for the options resolving values, such as WithParams.
+func (eval *Evaluator) Evaluate(datum interface{}, opts ...Option) (interface{}, error) {
+	return eval.EvaluateContext(context.Background(), datum, opts...)
+}
+
+// EvaluateContext evaluates the expression like Evaluate, giving up with the
+// error of the context once it is done. The context is checked before each
+// node of the expression is evaluated: a match expression being evaluated,
+// including the hooks resolving its selectors, runs to completion.
+func (eval *Evaluator) EvaluateContext(ctx context.Context, datum interface{}, opts ...Option) (interface{}, error) {
+	var hash uint64
+	if eval.mutationCheck {
+		hash = hashDatum(datum)
+	}
+	var span Span
+	if eval.tracer != nil {
+		ctx, span = eval.tracer.Start(ctx, SpanEvaluate)
+	}
+	var start time.Time
+	if eval.observer.observesEvaluations() {
+		start = time.Now()
+	}
+	result, err := eval.evaluate(ctx, datum, opts...)
+	var elapsed time.Duration
+	if !start.IsZero() {
+		elapsed = time.Since(start)
+	}
+	if eval.shadow != nil {
+		eval.shadow.compare(ctx, datum, result, err, opts...)
+	}
+	if eval.mutationCheck && hashDatum(datum) != hash {
+		result, err = false, errDatumMutated
+	}
+	if !start.IsZero() {
+		eval.observer.evaluated(eval.expression, elapsed, result, err)
+	}
+	if span != nil {
+		eval.endEvaluateSpan(span, result, err)
+	}
+	if eval.recorder != nil {
+		eval.recorder.record(ctx, eval.Fingerprint(), datum, result, err)
+	}
+	return result, err
+}
+
+func (eval *Evaluator) evaluate(ctx context.Context, datum interface{}, opts ...Option) (interface{}, error) {
+	call := compiledCall{ctx: ctx, datum: datum}
+	if len(opts) == 0 {
+		if result, ok := eval.evaluateFast(ctx, datum); ok {
+			return result, nil
+		}
+		if eval.fastOptions() {
+			call.fastData, call.fast = fastDatumOf(datum)
+		}
+	}
+	opts = append(eval.evaluateOpts(), opts...)
+	if eval.unknownResult != nil {
+		result, err := evaluateTristate(ctx, eval.ast, datum, opts...)
+		if err != nil {
+			return false, err
+		}
+		return result.Bool(*eval.unknownResult), nil
+	}
+	if eval.traceFn != nil || eval.stats != nil {
+		trace := evaluateTrace(ctx, eval.ast, datum, opts...)
+		if eval.deterministic {
+			trace.setDeterministic()
+		}
+		if eval.stats != nil {
+			eval.stats.record(eval.statsRule, eval.ast, trace)
+		}
+		if eval.traceFn != nil {
+			eval.traceFn(trace)
+		}
+		return trace.Result, trace.Err
+	}
+	call.opt = opts
+	return eval.compiled(call)
+}
+
+// evaluateOpts returns the options selectors are resolved with
+func (eval *Evaluator) evaluateOpts() []Option {
 	opts := []Option{
 		WithTagName(eval.tagName),
 		WithHookFn(eval.valueTransformationHook),
 	}
+	if len(eval.tagNames) > 0 {
+		opts = append(opts, WithTagNames(eval.tagNames...))
+	}
+	for _, hook := range eval.selectorHooks {
+		opts = append(opts, WithSelectorHook(hook.pattern, hook.fn))
+	}
+	opts = append(opts, func(o *options) {
+		o.withValueConverters = eval.valueConverters
+		o.withFieldCache = eval.fieldCache
+		o.withRegexLimits = eval.regexLimits
+		o.withFieldAccess = eval.fieldAccess
+		o.withLogger = eval.logger
+		o.withDefaults = eval.defaults[:len(eval.defaults):len(eval.defaults)]
+	})
 	if eval.unknownVal != nil {
 		opts = append(opts, WithUnknownValue(*eval.unknownVal))
 	}
-	result, err := evaluate(eval.ast, datum, opts...)
-	return result, err
+	if eval.strictTypes {
+		opts = append(opts, WithStrictTypes())
+	}
+	if eval.strictSelectors {
+		opts = append(opts, WithStrictSelectors())
+	}
+	if len(eval.params) > 0 {
+		opts = append(opts, WithParams(eval.params))
+	}
+	for typ, fn := range eval.coercions {
+		opts = append(opts, WithCoercion(typ, fn))
+	}
+	for _, c := range eval.selectorCoercions {
+		opts = append(opts, WithSelectorCoercion(c.pattern, c.valueType, c.fn))
+	}
+	if eval.decimal {
+		opts = append(opts, WithDecimalArithmetic())
+	}
+	return opts
 }
@@ -4,8 +4,13 @@
 package bexpr
 
 import (
+	"context"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gterranova/go-bexpr/grammar"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +26,17 @@ func TestCreateEvaluator(t *testing.T) {
 		"basic": {
 			expression: "foo == 3",
 		},
+		"invalid glob pattern": {
+			expression: "String like `[`",
+			err:        `String like "[": invalid glob pattern "[": syntax error in pattern`,
+		},
+		"invalid negated glob pattern": {
+			expression: `foo == 3 and not (String not like "[a-")`,
+			err:        `String not like "[a-": invalid glob pattern "[a-": syntax error in pattern`,
+		},
+		"selected glob pattern": {
+			expression: `String like Pattern`,
+		},
 	}
 
 	for name, tcase := range tests {
@@ -30,10 +46,366 @@ func TestCreateEvaluator(t *testing.T) {
 			t.Parallel()
 
 			expr, err := CreateEvaluator(tcase.expression)
-			if tcase.err == "" {
-				require.NoError(t, err)
-				require.NotNil(t, expr)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
 			}
+			require.NoError(t, err)
+			require.NotNil(t, expr)
 		})
 	}
 }
+
+func TestEvaluateContext(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{"a": 1, "b": 2, "c": 3}
+
+	type testCase struct {
+		expression string
+		// cancel cancels the context when the selector is resolved
+		cancel   string
+		canceled bool
+		trace    bool
+		err      error
+		result   bool
+		resolved []string
+	}
+
+	tests := map[string]testCase{
+		"not canceled": {
+			expression: `a == 1 and b == 2`,
+			result:     true,
+			resolved:   []string{"a", "b"},
+		},
+		"canceled": {
+			expression: `a == 1`,
+			canceled:   true,
+			err:        context.Canceled,
+		},
+		"canceled during evaluation": {
+			expression: `a == 1 and b == 2 and c == 3`,
+			cancel:     "a",
+			err:        context.Canceled,
+			resolved:   []string{"a"},
+		},
+		"canceled in last node": {
+			expression: `a == 1 and b == 2`,
+			cancel:     "b",
+			result:     true,
+			resolved:   []string{"a", "b"},
+		},
+		"canceled in negation": {
+			expression: `not (a == 1 and b == 2)`,
+			cancel:     "a",
+			err:        context.Canceled,
+			resolved:   []string{"a"},
+		},
+		"canceled with trace": {
+			expression: `a == 1 and b == 2 and c == 3`,
+			cancel:     "a",
+			trace:      true,
+			err:        context.Canceled,
+			// the trace records the values of the operands separately
+			resolved: []string{"a", "a"},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tcase.canceled {
+				cancel()
+			}
+
+			var resolved []string
+			opts := []Option{
+				WithHookFn(func(v reflect.Value) reflect.Value {
+					if i, ok := v.Interface().(int); ok {
+						name := string(rune('a' + i - 1))
+						resolved = append(resolved, name)
+						if name == tcase.cancel {
+							cancel()
+						}
+					}
+					return v
+				}),
+			}
+			if tcase.trace {
+				opts = append(opts, WithTrace(func(*Trace) {}))
+			}
+
+			eval, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			result, err := eval.EvaluateContext(ctx, datum)
+			require.Equal(t, tcase.resolved, resolved)
+			if tcase.err != nil {
+				require.ErrorIs(t, err, tcase.err)
+				require.Equal(t, false, result)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+
+	t.Run("deadline", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		eval, err := CreateEvaluator(`a == 1`)
+		require.NoError(t, err)
+		_, err = eval.EvaluateContext(ctx, datum)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWithParams(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		create     map[string]interface{}
+		params     map[string]interface{}
+		result     bool
+		err        string
+	}
+
+	datum := map[string]interface{}{
+		"Owner": "alice",
+		"Team":  "infra",
+		"Port":  8080,
+	}
+
+	tests := map[string]testCase{
+		"equal": {
+			expression: `Owner == $user`,
+			params:     map[string]interface{}{"user": "alice"},
+			result:     true,
+		},
+		"not equal": {
+			expression: `Owner == $user`,
+			params:     map[string]interface{}{"user": "bob"},
+			result:     false,
+		},
+		"in": {
+			expression: `Owner == $user and Team in $teams`,
+			params:     map[string]interface{}{"user": "alice", "teams": []string{"infra", "web"}},
+			result:     true,
+		},
+		"number": {
+			expression: `Port > $min`,
+			params:     map[string]interface{}{"min": 8000},
+			result:     true,
+		},
+		"math": {
+			expression: `Port == $base + 80`,
+			params:     map[string]interface{}{"base": 8000},
+			result:     true,
+		},
+		"bound at creation": {
+			expression: `Owner == $user and Team == $team`,
+			create:     map[string]interface{}{"user": "bob", "team": "infra"},
+			params:     map[string]interface{}{"user": "alice"},
+			result:     true,
+		},
+		"not interpolated": {
+			expression: `Owner == $user`,
+			params:     map[string]interface{}{"user": `alice" or Owner != "`},
+			result:     false,
+		},
+		"unbound": {
+			expression: `Owner == $user`,
+			err:        `1:1 (0): Owner == $user: no value bound to parameter $user`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if tcase.create != nil {
+				opts = append(opts, WithParams(tcase.create))
+			}
+			eval, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum, WithParams(tcase.params))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestCreateEvaluator_LiteralLimits(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluator(`Name == "abcdef"`, WithMaxLiteralLength(5))
+	require.EqualError(t, err, "1:9 (8): rule \"string\": String literal longer than 5 bytes")
+
+	_, err = CreateEvaluator(`Name == "abc" or Name == "def"`, WithMaxLiteralBytes(5))
+	require.EqualError(t, err, "1:26 (25): rule \"string\": String literals longer than 5 bytes in total")
+
+	// the literals of the macros are not limited
+	eval, err := CreateEvaluator(`admin`,
+		WithMacros(map[string]string{"admin": `Role == "administrator"`}),
+		WithMaxLiteralLength(5))
+	require.NoError(t, err)
+	result, err := eval.Evaluate(map[string]string{"Role": "administrator"})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+}
+
+func TestCreateEvaluator_KeywordAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases := WithKeywordAliases(map[string]string{"et": "and", "ou": "or", "contient": "contains"})
+	eval, err := CreateEvaluator(`Name contient "ali" et (Role == "admin" ou is_owner)`,
+		aliases,
+		WithMacros(map[string]string{"is_owner": `Role == "owner" et Name != "bob"`}))
+	require.NoError(t, err)
+	result, err := eval.Evaluate(map[string]string{"Name": "alice", "Role": "owner"})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	english, err := CreateEvaluator(`Name contains "ali" and (Role == "admin" or is_owner)`,
+		WithMacros(map[string]string{"is_owner": `Role == "owner" and Name != "bob"`}))
+	require.NoError(t, err)
+	require.Equal(t, english.Fingerprint(), eval.Fingerprint())
+
+	_, err = CreateEvaluator(`Name == "alice"`, WithKeywordAliases(map[string]string{"und": "und"}))
+	require.EqualError(t, err, `invalid keyword aliases: alias "und" of unknown keyword "und"`)
+}
+
+func TestCreateEvaluator_ReservedKeywords(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{"in": 1, "empty": []string{}, "not": map[string]int{"and": 2}}
+
+	eval, err := CreateEvaluator(`["in"] == 1 and ["empty"] is empty and ["not"].and == 2`, WithReservedKeywords())
+	require.NoError(t, err)
+	result, err := eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	_, err = CreateEvaluator(`in == 1`, WithReservedKeywords())
+	require.EqualError(t, err, `1:3 (2): rule "value": "in" is a reserved keyword, fields named "in" are written ["in"]`)
+
+	// aliases are reserved as well
+	_, err = CreateEvaluator(`dans == 1`, WithReservedKeywords(), WithKeywordAliases(map[string]string{"dans": "in"}))
+	require.EqualError(t, err, `1:5 (4): rule "value": "dans" is a reserved keyword, fields named "dans" are written ["dans"]`)
+
+	// without the option, keywords are read as selectors where they fit
+	eval, err = CreateEvaluator(`in == 1`)
+	require.NoError(t, err)
+	result, err = eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+}
+
+func TestCreateEvaluator_SelectorDialect(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Meta": map[string]interface{}{"env": "prod", "a/b": 1, "a~b": 2, "a b": 3},
+		"Tags": []string{"web", "db"},
+	}
+
+	type testCase struct {
+		expression string
+		dialect    grammar.SelectorDialect
+		opts       []Option
+		result     bool
+		written    string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"json pointer": {
+			expression: `/Meta/env == "prod" and /Meta/a~1b == 1 and /Meta/a~0b == 2 and /Tags/1 == "db"`,
+			dialect:    grammar.SelectorDialectJSONPointer,
+			result:     true,
+			written:    `/Meta/env == "prod" and /Meta/a~1b == 1 and /Meta/a~0b == 2 and /Tags/1 == "db"`,
+		},
+		"json pointer missing key": {
+			expression: `/Meta/owner is empty`,
+			dialect:    grammar.SelectorDialectJSONPointer,
+			result:     true,
+			written:    `/Meta/owner is empty`,
+		},
+		"jsonpath": {
+			expression: `$.Meta.env == "prod" and $['Meta']['a b'] == 3 and $.Meta["a/b"] == 1 and $.Tags[0] == "web"`,
+			dialect:    grammar.SelectorDialectJSONPath,
+			result:     true,
+			written:    `$.Meta.env == "prod" and $.Meta["a b"] == 3 and $.Meta["a/b"] == 1 and $.Tags[0] == "web"`,
+		},
+		"let": {
+			expression: `let m = $.Meta in $.m.env == "prod"`,
+			dialect:    grammar.SelectorDialectJSONPath,
+			result:     true,
+			written:    `let m = $.Meta in $.m.env == "prod"`,
+		},
+		"macros": {
+			expression: `/is_prod and /Tags contains "web"`,
+			dialect:    grammar.SelectorDialectJSONPointer,
+			opts:       []Option{WithMacros(map[string]string{"is_prod": `/Meta/env == "prod"`})},
+			result:     true,
+			written:    `/Meta/env == "prod" and /Tags contains "web"`,
+		},
+		"prefix": {
+			expression: `/env == "prod"`,
+			dialect:    grammar.SelectorDialectJSONPointer,
+			opts:       []Option{WithSelectorPrefix("Meta")},
+			result:     true,
+			written:    `/Meta/env == "prod"`,
+		},
+		"bexpr selector in json pointer dialect": {
+			expression: `Meta.env == "prod"`,
+			dialect:    grammar.SelectorDialectJSONPointer,
+			err:        `1:5 (4): rule "value": selectors are written as JSON Pointers, such as /Meta`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append(tcase.opts, WithSelectorDialect(tcase.dialect))...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			result, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+
+			var b strings.Builder
+			_, err = eval.WriteTo(&b)
+			require.NoError(t, err)
+			require.Equal(t, tcase.written, b.String())
+		})
+	}
+
+	// JSON Pointers cannot select keys holding spaces
+	eval, err := CreateEvaluator(`Meta["a b"] == 3`)
+	require.NoError(t, err)
+	eval.dialect = grammar.SelectorDialectJSONPointer
+	_, err = eval.WriteTo(&strings.Builder{})
+	require.EqualError(t, err, `cannot write "a b" as a part of a JSON Pointer`)
+}
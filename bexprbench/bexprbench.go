@@ -0,0 +1,305 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package bexprbench provides ready-made benchmarks of the evaluation of
+// expressions, parameterized by the shape of the expression, the size of the
+// datum and the kind of the datum, so that performance work on the evaluator,
+// and on the hooks, converters and coercions given as options, is measured the
+// same way each time:
+//
+//	func BenchmarkEvaluate(b *testing.B) {
+//		bexprbench.Run(b, bexprbench.DefaultCases(), bexprbench.WithOptions(bexpr.WithHookFn(hook)))
+//	}
+//
+// Each benchmark is run with the pprof labels of its case, see Case.Labels, so
+// that CPU profiles taken with -cpuprofile can be broken down per case with
+// pprof -tagfocus.
+package bexprbench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"testing"
+
+	bexpr "github.com/gterranova/go-bexpr"
+)
+
+// Shape is the shape of the expressions of the benchmarks, all of which match
+// the datum after evaluating each of their clauses
+type Shape int
+
+const (
+	// ShapeMatch is a single match expression, such as F0 == 0
+	ShapeMatch Shape = iota
+	// ShapeAnd is a conjunction of matching clauses
+	ShapeAnd
+	// ShapeOr is a disjunction of clauses, only the last one matching
+	ShapeOr
+	// ShapeNested is a conjunction of disjunctions of two clauses, the
+	// second one matching, such as (F0 == -1 or F1 == "v1") and ..., the
+	// number of clauses of the case being the number of disjunctions
+	ShapeNested
+)
+
+func (s Shape) String() string {
+	switch s {
+	case ShapeMatch:
+		return "match"
+	case ShapeAnd:
+		return "and"
+	case ShapeOr:
+		return "or"
+	case ShapeNested:
+		return "nested"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Kind is the kind of the datum of the benchmarks
+type Kind int
+
+const (
+	// KindStruct is a struct with a field per value
+	KindStruct Kind = iota
+	// KindMap is a map[string]interface{}
+	KindMap
+	// KindJSON is the datum decoded by encoding/json into an interface{},
+	// its numbers being float64
+	KindJSON
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindStruct:
+		return "struct"
+	case KindMap:
+		return "map"
+	case KindJSON:
+		return "json"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Case is a benchmark: an expression of the shape with the number of clauses,
+// evaluated against a datum of the kind with the number of fields. The fields
+// are named F0, F1 and so on, the even ones holding their index and the odd
+// ones the string "v" followed by their index. Clauses beyond the number of
+// fields compare the fields again, from the first one.
+type Case struct {
+	Shape   Shape
+	Clauses int
+	Fields  int
+	Kind    Kind
+}
+
+// Name returns the name of the sub-benchmark of the case, such as
+// shape=and/clauses=4/fields=16/kind=map
+func (c Case) Name() string {
+	return fmt.Sprintf("shape=%s/clauses=%d/fields=%d/kind=%s", c.Shape, c.Clauses, c.Fields, c.Kind)
+}
+
+// Labels returns the pprof labels the case is run with
+func (c Case) Labels() pprof.LabelSet {
+	return pprof.Labels(
+		"bexpr.shape", c.Shape.String(),
+		"bexpr.clauses", strconv.Itoa(c.Clauses),
+		"bexpr.fields", strconv.Itoa(c.Fields),
+		"bexpr.kind", c.Kind.String(),
+	)
+}
+
+// Expression returns the expression of the case
+func (c Case) Expression() string {
+	clauses := c.Clauses
+	if c.Shape == ShapeMatch || clauses < 1 {
+		clauses = 1
+	}
+	parts := make([]string, 0, clauses)
+	for i := 0; i < clauses; i++ {
+		switch c.Shape {
+		case ShapeOr:
+			parts = append(parts, c.clause(i, i == clauses-1))
+		case ShapeNested:
+			parts = append(parts, fmt.Sprintf("(%s or %s)", c.clause(2*i, false), c.clause(2*i+1, true)))
+		default:
+			parts = append(parts, c.clause(i, true))
+		}
+	}
+	if c.Shape == ShapeOr {
+		return strings.Join(parts, " or ")
+	}
+	return strings.Join(parts, " and ")
+}
+
+// clause compares a field with its value, or with another value when the
+// clause does not match
+func (c Case) clause(i int, matches bool) string {
+	field := 0
+	if c.Fields > 0 {
+		field = i % c.Fields
+	}
+	if field%2 == 0 {
+		if !matches {
+			return fmt.Sprintf("F%d == %d", field, -1)
+		}
+		return fmt.Sprintf("F%d == %d", field, field)
+	}
+	if !matches {
+		return fmt.Sprintf("F%d == \"x\"", field)
+	}
+	return fmt.Sprintf("F%d == \"v%d\"", field, field)
+}
+
+// Datum returns the datum of the case
+func (c Case) Datum() interface{} {
+	fields := c.Fields
+	if fields < 1 {
+		fields = 1
+	}
+	values := make(map[string]interface{}, fields)
+	for i := 0; i < fields; i++ {
+		values[fmt.Sprintf("F%d", i)] = fieldValue(i)
+	}
+
+	switch c.Kind {
+	case KindStruct:
+		structFields := make([]reflect.StructField, fields)
+		for i := range structFields {
+			structFields[i] = reflect.StructField{Name: fmt.Sprintf("F%d", i), Type: reflect.TypeOf(fieldValue(i))}
+		}
+		datum := reflect.New(reflect.StructOf(structFields)).Elem()
+		for i := 0; i < fields; i++ {
+			datum.Field(i).Set(reflect.ValueOf(fieldValue(i)))
+		}
+		return datum.Interface()
+	case KindJSON:
+		raw, err := json.Marshal(values)
+		if err != nil {
+			panic(err)
+		}
+		var datum interface{}
+		if err := json.Unmarshal(raw, &datum); err != nil {
+			panic(err)
+		}
+		return datum
+	default:
+		return values
+	}
+}
+
+func fieldValue(i int) interface{} {
+	if i%2 == 0 {
+		return i
+	}
+	return "v" + strconv.Itoa(i)
+}
+
+// Cases returns the cases of every combination of the shapes, the numbers of
+// clauses, the numbers of fields and the kinds
+func Cases(shapes []Shape, clauses []int, fields []int, kinds []Kind) []Case {
+	var cases []Case
+	for _, shape := range shapes {
+		for _, nClauses := range clauses {
+			if shape == ShapeMatch && nClauses != clauses[0] {
+				// the number of clauses does not change single matches
+				continue
+			}
+			for _, nFields := range fields {
+				for _, kind := range kinds {
+					cases = append(cases, Case{Shape: shape, Clauses: nClauses, Fields: nFields, Kind: kind})
+				}
+			}
+		}
+	}
+	return cases
+}
+
+// DefaultCases returns the cases of every shape with 1, 4 and 16 clauses,
+// against datums of every kind with 4 and 64 fields
+func DefaultCases() []Case {
+	return Cases(
+		[]Shape{ShapeMatch, ShapeAnd, ShapeOr, ShapeNested},
+		[]int{1, 4, 16},
+		[]int{4, 64},
+		[]Kind{KindStruct, KindMap, KindJSON},
+	)
+}
+
+// RunOption - how RunOptions are passed as arguments
+type RunOption func(*runOptions)
+
+type runOptions struct {
+	withOptions []bexpr.Option
+	withDatum   func(Case) interface{}
+}
+
+// WithOptions creates the evaluators of the benchmarks with the options, such
+// as hooks or converters whose cost is to be measured
+func WithOptions(opts ...bexpr.Option) RunOption {
+	return func(o *runOptions) {
+		o.withOptions = append(o.withOptions, opts...)
+	}
+}
+
+// WithDatum evaluates the expressions against the datums returned by fn
+// rather than the ones of the cases, such as types of your own holding the
+// values of Case.Datum. The expressions must match the datums.
+func WithDatum(fn func(Case) interface{}) RunOption {
+	return func(o *runOptions) {
+		o.withDatum = fn
+	}
+}
+
+// Run runs a sub-benchmark per case, reporting the allocations. The evaluator
+// is created and the datum built before the timer starts. Evaluations failing
+// or not matching fail the benchmark.
+func Run(b *testing.B, cases []Case, opt ...RunOption) {
+	var opts runOptions
+	for _, o := range opt {
+		o(&opts)
+	}
+	for _, c := range cases {
+		c := c
+		b.Run(c.Name(), func(b *testing.B) {
+			datum := c.Datum()
+			if opts.withDatum != nil {
+				datum = opts.withDatum(c)
+			}
+			benchmark(b, c.Labels(), c.Expression(), datum, opts.withOptions...)
+		})
+	}
+}
+
+// Benchmark benchmarks the evaluation of an expression of your own against the
+// datum, with the pprof label "bexpr.expression" set to the expression. The
+// evaluations must match the datum.
+func Benchmark(b *testing.B, expression string, datum interface{}, opts ...bexpr.Option) {
+	benchmark(b, pprof.Labels("bexpr.expression", expression), expression, datum, opts...)
+}
+
+func benchmark(b *testing.B, labels pprof.LabelSet, expression string, datum interface{}, opts ...bexpr.Option) {
+	eval, err := bexpr.CreateEvaluator(expression, opts...)
+	if err != nil {
+		b.Fatalf("failed to create the evaluator of %q: %v", expression, err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		for n := 0; n < b.N; n++ {
+			result, err := eval.Evaluate(datum)
+			if err != nil {
+				b.Fatalf("failed to evaluate %q: %v", expression, err)
+			}
+			if result != true {
+				b.Fatalf("%q evaluated to %v", expression, result)
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexprbench
+
+import (
+	"testing"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCase_Expression(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		c          Case
+		expression string
+	}
+
+	tests := map[string]testCase{
+		"match":  {c: Case{Shape: ShapeMatch, Clauses: 4, Fields: 4}, expression: `F0 == 0`},
+		"and":    {c: Case{Shape: ShapeAnd, Clauses: 3, Fields: 2}, expression: `F0 == 0 and F1 == "v1" and F0 == 0`},
+		"or":     {c: Case{Shape: ShapeOr, Clauses: 3, Fields: 4}, expression: `F0 == -1 or F1 == "x" or F2 == 2`},
+		"nested": {c: Case{Shape: ShapeNested, Clauses: 2, Fields: 4}, expression: `(F0 == -1 or F1 == "v1") and (F2 == -1 or F3 == "v3")`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tcase.expression, tcase.c.Expression())
+		})
+	}
+}
+
+func TestDefaultCases(t *testing.T) {
+	t.Parallel()
+
+	cases := DefaultCases()
+	// single matches are not repeated for each number of clauses
+	require.Len(t, cases, (1+3*3)*2*3)
+	for _, c := range cases {
+		eval, err := bexpr.CreateEvaluator(c.Expression())
+		require.NoError(t, err, c.Name())
+		result, err := eval.Evaluate(c.Datum())
+		require.NoError(t, err, c.Name())
+		require.Equal(t, true, result, c.Name())
+	}
+}
+
+func TestCase_Name(t *testing.T) {
+	t.Parallel()
+
+	c := Case{Shape: ShapeNested, Clauses: 4, Fields: 16, Kind: KindJSON}
+	require.Equal(t, "shape=nested/clauses=4/fields=16/kind=json", c.Name())
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	Run(b, DefaultCases())
+}
+
+func BenchmarkEvaluate_Datum(b *testing.B) {
+	type datum struct {
+		F0 int
+		F1 string
+	}
+	Run(b, Cases([]Shape{ShapeAnd}, []int{4}, []int{2}, []Kind{KindStruct}), WithDatum(func(Case) interface{} {
+		return &datum{F0: 0, F1: "v1"}
+	}))
+}
+
+func BenchmarkBenchmark(b *testing.B) {
+	Benchmark(b, `Name == "web" and Port > 8000`, map[string]interface{}{"Name": "web", "Port": 8080},
+		bexpr.WithStrictTypes())
+}
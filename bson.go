@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+)
+
+var (
+	stringTyp       = reflect.TypeOf("")
+	bsonDocumentTyp = reflect.TypeOf(map[string]interface{}{})
+)
+
+// BSON values are recognized by their shape rather than their type, so that
+// datums decoded by the MongoDB driver can be evaluated without depending on
+// it:
+//
+//   - ordered documents, such as bson.D, are slices of structs holding a Key
+//     string and a Value. Selectors look up their elements by key, the first
+//     element with the key winning, and the "in" and "is empty" operators
+//     apply to their keys like they do for bson.M.
+//   - object IDs, such as primitive.ObjectID, are 12 byte arrays with a Hex
+//     method. They resolve to their hexadecimal string.
+//
+// Date times, such as primitive.DateTime, are integers holding milliseconds
+// since the Unix epoch and are compared as such.
+
+// isBSONDocument reports whether the type is an ordered BSON document
+func isBSONDocument(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Slice || typ.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	elem := typ.Elem()
+	if elem.NumField() != 2 {
+		return false
+	}
+	key, value := elem.Field(0), elem.Field(1)
+	return key.Name == "Key" && key.Type == stringTyp &&
+		value.Name == "Value" && value.Type == interfaceTyp
+}
+
+// isBSONObjectID reports whether the type is a BSON object ID
+func isBSONObjectID(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Array || typ.Len() != 12 || typ.Elem().Kind() != reflect.Uint8 {
+		return false
+	}
+	hex, ok := typ.MethodByName("Hex")
+	return ok && hex.Type.NumIn() == 1 && hex.Type.NumOut() == 1 && hex.Type.Out(0) == stringTyp
+}
+
+// bsonHookFn converts ordered BSON documents to maps and BSON object IDs to
+// strings.
+func bsonHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if !e.IsValid() || !e.CanInterface() {
+		return v
+	}
+	switch typ := e.Type(); {
+	case isBSONDocument(typ):
+		if e.IsNil() {
+			return reflect.Zero(interfaceTyp)
+		}
+		doc := make(map[string]interface{}, e.Len())
+		for i := 0; i < e.Len(); i++ {
+			key := e.Index(i).Field(0).String()
+			if _, ok := doc[key]; !ok {
+				doc[key] = e.Index(i).Field(1).Interface()
+			}
+		}
+		return reflect.ValueOf(doc)
+	case isBSONObjectID(typ):
+		return e.MethodByName("Hex").Call(nil)[0]
+	}
+	return v
+}
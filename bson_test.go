@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testE and testD have the shape of bson.E and bson.D
+type testE struct {
+	Key   string
+	Value interface{}
+}
+
+type testD []testE
+
+// testM has the shape of bson.M
+type testM map[string]interface{}
+
+func TestBSON(t *testing.T) {
+	t.Parallel()
+
+	// the shape of a change stream event
+	event := testD{
+		{Key: "_id", Value: testD{{Key: "_data", Value: "8263"}}},
+		{Key: "operationType", Value: "update"},
+		{Key: "clusterTime", Value: testDateTime(1600000000000)},
+		{Key: "documentKey", Value: testD{{Key: "_id", Value: testObjectID{0xde, 0xad, 0xbe, 0xef}}}},
+		{Key: "fullDocument", Value: testM{
+			"name":  "web",
+			"ports": []interface{}{int32(80), int32(443)},
+			"meta":  testD{{Key: "env", Value: "prod"}, {Key: "env", Value: "dev"}},
+		}},
+		{Key: "updateDescription", Value: testD{
+			{Key: "updatedFields", Value: testD{{Key: "name", Value: "web"}}},
+			{Key: "removedFields", Value: []interface{}{}},
+		}},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"ordered document": {
+			expression: `operationType == "update"`,
+			result:     true,
+		},
+		"nested ordered documents": {
+			expression: `"/documentKey/_id" == "deadbeef0000000000000000"`,
+			result:     true,
+		},
+		"object id": {
+			expression: `"/documentKey/_id" startswith "deadbeef"`,
+			result:     true,
+		},
+		"date time": {
+			expression: `clusterTime >= 1600000000000 and clusterTime < 1700000000000`,
+			result:     true,
+		},
+		"unordered document": {
+			expression: `fullDocument.name == "web" and 443 in fullDocument.ports`,
+			result:     true,
+		},
+		"first key wins": {
+			expression: `fullDocument.meta.env == "prod"`,
+			result:     true,
+		},
+		"document keys": {
+			expression: `"name" in updateDescription.updatedFields`,
+			result:     true,
+		},
+		"missing key": {
+			expression: `"missing" in updateDescription.updatedFields`,
+			result:     false,
+		},
+		"missing element": {
+			expression: `updateDescription.updatedFields.port == 80`,
+			result:     false,
+		},
+		"empty array": {
+			expression: `updateDescription.removedFields is empty`,
+			result:     true,
+		},
+		"nil document": {
+			expression: `fullDocument.meta is null`,
+			datum:      testD{{Key: "fullDocument", Value: testD{{Key: "meta", Value: testD(nil)}}}},
+			result:     true,
+		},
+		"document pointer": {
+			expression: `fullDocument.name != "db"`,
+			datum:      &event,
+			result:     true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			datum := tcase.datum
+			if datum == nil {
+				datum = event
+			}
+			match, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
+
+func TestBSON_Schema(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		ID     testObjectID
+		Owner  *testObjectID
+		Meta   testD
+		Parent struct{ ID testObjectID }
+	}
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"object id":        {expression: `ID == "deadbeef0000000000000000" and ID startswith "dead"`},
+		"object id ptr":    {expression: `Owner matches "^[0-9a-f]{24}$"`},
+		"nested object id": {expression: `Parent.ID != "deadbeef0000000000000000"`},
+		"document":         {expression: `Meta.env == "prod" and "env" in Meta`},
+		"unsupported":      {expression: `ID > 3`, err: `ID: operator "Higher" cannot be used with values of type string`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(record{})))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
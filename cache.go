@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+)
+
+// Cache memoizes the evaluators created from expressions with the options of
+// the cache, keeping the ones of the most recently used expressions, so that
+// the filters received over and over are parsed once. Expressions which fail
+// to parse or validate are not cached. Cache is safe for concurrent use and
+// implements expvar.Var, publishing its CacheStats.
+type Cache struct {
+	opts []Option
+	size int
+
+	mu         sync.Mutex
+	evaluators map[string]*list.Element
+	// recent holds the cached evaluators, the most recently used first
+	recent *list.List
+	stats  CacheStats
+}
+
+// CacheStats are the counters of a Cache
+type CacheStats struct {
+	// Hits and Misses are the number of evaluators found in the cache and
+	// created, Evictions the number of evaluators dropped to make room for
+	// others
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	// Len is the number of evaluators cached
+	Len int
+}
+
+type cacheEntry struct {
+	expression string
+	eval       *Evaluator
+}
+
+// NewCache returns a cache of at most size evaluators, created with the
+// options. The evaluators created with other options are cached in other
+// caches. A size lower than 1 is a size of 1.
+func NewCache(size int, opts ...Option) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{
+		opts:       opts,
+		size:       size,
+		evaluators: make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// CreateEvaluator returns the cached evaluator of the expression, or creates
+// it with CreateEvaluator and caches it, evicting the least recently used
+// evaluator when the cache is full. The evaluators being shared, the ones
+// created with options holding state, such as WithStats, share it.
+func (c *Cache) CreateEvaluator(expression string) (*Evaluator, error) {
+	c.mu.Lock()
+	if elem, ok := c.evaluators[expression]; ok {
+		c.recent.MoveToFront(elem)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return elem.Value.(*cacheEntry).eval, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// parsed without holding the lock, the expressions missed concurrently
+	// being parsed once each
+	eval, err := CreateEvaluator(expression, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.evaluators[expression]; ok {
+		c.recent.MoveToFront(elem)
+		return elem.Value.(*cacheEntry).eval, nil
+	}
+	c.evaluators[expression] = c.recent.PushFront(&cacheEntry{expression: expression, eval: eval})
+	if c.recent.Len() > c.size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.evaluators, oldest.Value.(*cacheEntry).expression)
+		c.stats.Evictions++
+	}
+	return eval, nil
+}
+
+// Stats returns the counters of the cache
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Len = c.recent.Len()
+	return stats
+}
+
+// String returns the counters of the cache as JSON, for expvar
+func (c *Cache) String() string {
+	b, err := json.Marshal(c.Stats())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(2, WithParams(map[string]interface{}{"env": "prod"}))
+
+	a, err := cache.CreateEvaluator(`Env == $env`)
+	require.NoError(t, err)
+	again, err := cache.CreateEvaluator(`Env == $env`)
+	require.NoError(t, err)
+	require.Same(t, a, again)
+	result, err := a.Evaluate(map[string]string{"Env": "prod"})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	_, err = cache.CreateEvaluator(`Port > 80`)
+	require.NoError(t, err)
+	_, err = cache.CreateEvaluator(`Env == $env`)
+	require.NoError(t, err)
+	// evicts Port > 80, the least recently used
+	_, err = cache.CreateEvaluator(`Name == "web"`)
+	require.NoError(t, err)
+	again, err = cache.CreateEvaluator(`Env == $env`)
+	require.NoError(t, err)
+	require.Same(t, a, again)
+
+	_, err = cache.CreateEvaluator(`Env ==`)
+	require.ErrorIs(t, err, ErrSyntax)
+
+	require.Equal(t, CacheStats{Hits: 3, Misses: 4, Evictions: 1, Len: 2}, cache.Stats())
+	require.JSONEq(t, `{"Hits": 3, "Misses": 4, "Evictions": 1, "Len": 2}`, cache.String())
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(4)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				eval, err := cache.CreateEvaluator(fmt.Sprintf("Port > %d", (i+j)%6))
+				require.NoError(t, err)
+				_, err = eval.Evaluate(map[string]int{"Port": 3})
+				require.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	require.Equal(t, uint64(800), stats.Hits+stats.Misses)
+	require.Equal(t, 4, stats.Len)
+}
@@ -0,0 +1,984 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// CEL, the Common Expression Language, is translated to and from as text, on
+// the part of the language whose semantics overlap with bexpr's: logical
+// operators, comparisons, membership, field selection and the string
+// functions. See CELExpression and FromCEL.
+
+var celIdentifierRe = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
+
+// celReserved are the reserved words of CEL, which cannot be identifiers
+var celReserved = map[string]bool{
+	"as": true, "break": true, "const": true, "continue": true, "else": true, "false": true,
+	"for": true, "function": true, "if": true, "import": true, "in": true, "let": true,
+	"loop": true, "package": true, "namespace": true, "null": true, "return": true,
+	"true": true, "var": true, "void": true, "while": true,
+}
+
+// celFunctions are the CEL functions of strings taking a single string
+// argument, with the operators they translate to
+var celFunctions = map[string]grammar.MatchOperator{
+	"contains":   grammar.MatchIn,
+	"matches":    grammar.MatchMatches,
+	"startsWith": grammar.MatchStartsWith,
+	"endsWith":   grammar.MatchEndsWith,
+}
+
+// CELExpression translates the expression to a CEL expression, such as
+// `Meta.env == "prod" && "web" in Tags && Name.startsWith("a")`, so that
+// filters written in bexpr can be accepted by services standardized on CEL.
+// The options are the ones the parameters of the expression are resolved
+// with, see WithParams: CEL expressions do not reference them.
+//
+// Selectors become field selections, the parts which are not CEL identifiers
+// being written as indexes, such as Meta["a-b"], and numeric parts list
+// indexes, such as Tags[0]. "is empty" is translated to size comparisons,
+// "like" to anchored regular expressions and "in" to the CEL "in" operator,
+// which does not look up substrings: "contains" on strings is not translated
+// to the contains function, as the type of the operands is not known. Let
+// expressions are inlined. Expressions which cannot be written in CEL, such as
+// the ones with selectors starting with a reserved word, return an error.
+func (eval *Evaluator) CELExpression(opts ...Option) (string, error) {
+	w := &celWriter{opts: append(eval.evaluateOpts(), opts...)}
+	w.expression(grammar.InlineLets(eval.ast), 0)
+	if w.err != nil {
+		return "", w.err
+	}
+	return w.b.String(), nil
+}
+
+// the precedences of CEL operators written by celWriter
+const (
+	celPrecedenceOr = iota + 1
+	celPrecedenceAnd
+	celPrecedenceRelation
+	celPrecedenceAdd
+	celPrecedenceMul
+	celPrecedenceUnary
+)
+
+type celWriter struct {
+	b    strings.Builder
+	opts []Option
+	err  error
+}
+
+func (w *celWriter) fail(format string, args ...interface{}) {
+	if w.err == nil {
+		w.err = fmt.Errorf(format, args...)
+	}
+}
+
+// expression writes the expression, in parentheses when it binds looser than
+// the operator it is an operand of
+func (w *celWriter) expression(ast grammar.Expression, precedence int) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		w.b.WriteString("!")
+		w.expression(node.Operand, celPrecedenceUnary)
+	case *grammar.BinaryExpression:
+		op, own := " && ", celPrecedenceAnd
+		if node.Operator == grammar.BinaryOpOr {
+			op, own = " || ", celPrecedenceOr
+		}
+		w.open(own, precedence)
+		w.expression(node.Left, own)
+		w.b.WriteString(op)
+		// && and || are associative
+		w.expression(node.Right, own)
+		w.close(own, precedence)
+	case *grammar.MatchExpression:
+		w.match(node, precedence)
+	case *grammar.ExpressionValue:
+		w.value(node, precedence)
+	default:
+		w.fail("cannot translate %T to CEL", ast)
+	}
+}
+
+func (w *celWriter) open(own, precedence int) {
+	if own < precedence {
+		w.b.WriteString("(")
+	}
+}
+
+func (w *celWriter) close(own, precedence int) {
+	if own < precedence {
+		w.b.WriteString(")")
+	}
+}
+
+func (w *celWriter) match(node *grammar.MatchExpression, precedence int) {
+	negated := false
+	operator := node.Operator
+	switch operator {
+	case grammar.MatchNotEqual, grammar.MatchIsNotEmpty, grammar.MatchIsNotNull:
+		// written with !=
+	default:
+		if positive, ok := positiveOperators[operator]; ok {
+			operator, negated = positive, true
+		}
+	}
+	if negated {
+		w.b.WriteString("!")
+		precedence = celPrecedenceUnary
+	}
+
+	switch operator {
+	case grammar.MatchEqual, grammar.MatchNotEqual, grammar.MatchLower, grammar.MatchLowerOrEqual, grammar.MatchHigher, grammar.MatchHigherOrEqual:
+		w.open(celPrecedenceRelation, precedence)
+		w.value(node.Left, celPrecedenceRelation+1)
+		w.b.WriteString(" " + matchOperatorSyntax[operator] + " ")
+		w.value(node.Right, celPrecedenceRelation+1)
+		w.close(celPrecedenceRelation, precedence)
+	case grammar.MatchIn:
+		// the left operand of in expressions is the container
+		w.open(celPrecedenceRelation, precedence)
+		w.value(node.Right, celPrecedenceRelation+1)
+		w.b.WriteString(" in ")
+		w.value(node.Left, celPrecedenceRelation+1)
+		w.close(celPrecedenceRelation, precedence)
+	case grammar.MatchIsEmpty, grammar.MatchIsNotEmpty:
+		w.open(celPrecedenceRelation, precedence)
+		w.b.WriteString("size(")
+		w.value(node.Left, 0)
+		if operator == grammar.MatchIsEmpty {
+			w.b.WriteString(") == 0")
+		} else {
+			w.b.WriteString(") != 0")
+		}
+		w.close(celPrecedenceRelation, precedence)
+	case grammar.MatchIsNull, grammar.MatchIsNotNull:
+		w.open(celPrecedenceRelation, precedence)
+		w.value(node.Left, celPrecedenceRelation+1)
+		if operator == grammar.MatchIsNull {
+			w.b.WriteString(" == null")
+		} else {
+			w.b.WriteString(" != null")
+		}
+		w.close(celPrecedenceRelation, precedence)
+	case grammar.MatchMatches, grammar.MatchStartsWith, grammar.MatchEndsWith:
+		function := map[grammar.MatchOperator]string{
+			grammar.MatchMatches:    "matches",
+			grammar.MatchStartsWith: "startsWith",
+			grammar.MatchEndsWith:   "endsWith",
+		}[operator]
+		w.value(node.Left, celPrecedenceUnary+1)
+		w.b.WriteString("." + function + "(")
+		w.value(node.Right, 0)
+		w.b.WriteString(")")
+	case grammar.MatchLike:
+		pattern, err := w.literal(node.Right)
+		if err != nil {
+			w.fail("cannot translate %s to CEL: %w", formatExpression(node), err)
+			return
+		}
+		glob, ok := pattern.(string)
+		if !ok {
+			w.fail("cannot translate %s to CEL: %v is not a string", formatExpression(node), pattern)
+			return
+		}
+		w.value(node.Left, celPrecedenceUnary+1)
+		w.b.WriteString(".matches(" + strconv.Quote(globToRegexp(glob)) + ")")
+	default:
+		w.fail("cannot translate %s to CEL: unsupported operator %s", formatExpression(node), node.Operator)
+	}
+}
+
+// value writes an operand of a match expression or of math
+func (w *celWriter) value(expr *grammar.ExpressionValue, precedence int) {
+	if expr == nil {
+		return
+	}
+	if expr.Operator == grammar.MathOpValue {
+		w.mathOperand(expr.Left, precedence)
+		return
+	}
+	own := celPrecedenceAdd
+	if expr.Operator == grammar.MathOpMul || expr.Operator == grammar.MathOpDiv {
+		own = celPrecedenceMul
+	}
+	w.open(own, precedence)
+	w.mathOperand(expr.Left, own)
+	w.b.WriteString(" " + expr.Operator.String() + " ")
+	// - and / are not associative
+	w.mathOperand(expr.Right, own+1)
+	w.close(own, precedence)
+}
+
+func (w *celWriter) mathOperand(operand interface{}, precedence int) {
+	switch node := operand.(type) {
+	case *grammar.ExpressionValue:
+		w.value(node, precedence)
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeReflect:
+			w.selector(node.Selector)
+		case grammar.ValueTypeParam:
+			value, err := getValue(node, nil, w.opts...)
+			if err != nil {
+				w.fail("cannot translate $%s to CEL: %w", node.Raw, err)
+				return
+			}
+			w.constant(value)
+		case grammar.ValueTypeUndefined:
+			w.fail("cannot translate undefined to CEL")
+		case grammar.ValueTypeCall:
+			if (node.Raw == "len" || node.Raw == "count") && refersToDatum(node) {
+				w.b.WriteString("size(")
+				w.value(node.Args[0], 0)
+				w.b.WriteString(")")
+				return
+			}
+			if refersToDatum(node) {
+				w.fail("cannot translate %s to CEL: the functions of bexpr are not supported", node)
+				return
+			}
+			value, err := getValue(node, nil, w.opts...)
+			if err != nil {
+				w.fail("cannot translate %s to CEL: %w", node, err)
+				return
+			}
+			w.constant(value)
+		default:
+			value, err := getValue(node, nil)
+			if err != nil {
+				w.fail("cannot translate %s to CEL: %w", node.Raw, err)
+				return
+			}
+			w.constant(value)
+		}
+	}
+}
+
+// literal resolves an operand which does not depend on the datum
+func (w *celWriter) literal(expr *grammar.ExpressionValue) (interface{}, error) {
+	return translatedValue(expr, w.opts...)
+}
+
+func (w *celWriter) constant(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		w.b.WriteString("null")
+	case bool:
+		w.b.WriteString(strconv.FormatBool(v))
+	case int64:
+		w.b.WriteString(strconv.FormatInt(v, 10))
+	case uint64:
+		w.b.WriteString(strconv.FormatUint(v, 10) + "u")
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEnN") {
+			s += ".0"
+		}
+		w.b.WriteString(s)
+	case string:
+		w.b.WriteString(strconv.Quote(v))
+	default:
+		w.fail("cannot translate values of type %T to CEL", value)
+	}
+}
+
+func (w *celWriter) selector(sel grammar.Selector) {
+	if len(sel.Ranges) > 0 {
+		w.fail("cannot translate %s to CEL: the ranges of selectors are not supported", formatExpression(&grammar.ExpressionValue{Left: &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: sel}}))
+		return
+	}
+	if len(sel.Path) == 0 || !celIdentifierRe.MatchString(sel.Path[0]) || celReserved[sel.Path[0]] {
+		w.fail("cannot translate %s to CEL: %q is not a CEL identifier", formatExpression(&grammar.ExpressionValue{Left: &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: sel}}), sel.Path[0])
+		return
+	}
+	w.b.WriteString(sel.Path[0])
+	for _, part := range sel.Path[1:] {
+		switch {
+		case celIdentifierRe.MatchString(part) && !celReserved[part]:
+			w.b.WriteString("." + part)
+		case indexRe.MatchString(part):
+			w.b.WriteString("[" + part + "]")
+		default:
+			w.b.WriteString("[" + strconv.Quote(part) + "]")
+		}
+	}
+}
+
+// globToRegexp translates the patterns of the like operator, see path.Match,
+// to anchored regular expressions
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	inClass := false
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			if c == '\\' && i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+				continue
+			}
+			b.WriteByte(c)
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			inClass = true
+			b.WriteByte('[')
+			if i+1 < len(glob) && glob[i+1] == '^' {
+				i++
+				b.WriteByte('^')
+			}
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// FromCEL translates a CEL expression to a bexpr expression, so that services
+// standardized on CEL can hand their filters to bexpr. The part of CEL
+// translated is the one written by CELExpression: the logical operators, the
+// comparisons, the in operator, field selections and indexes with constant
+// keys, size(x), comparisons with null, the contains, matches,
+// startsWith and endsWith functions of strings, and arithmetic on numbers.
+// Other expressions, such as conditionals, macros or function calls, return
+// an error, as do the ones bexpr cannot express, such as arithmetic on both
+// operands of a comparison.
+func FromCEL(expression string) (string, error) {
+	tokens, err := celTokenize(expression)
+	if err != nil {
+		return "", err
+	}
+	p := &celParser{tokens: tokens}
+	node, err := p.parseExpression()
+	if err != nil {
+		return "", err
+	}
+	if tok := p.peek(); tok.kind != celEOF {
+		return "", fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+	ast, err := celToBexpr(node)
+	if err != nil {
+		return "", err
+	}
+	result := formatExpression(ast)
+	if _, err := grammar.Parse("", []byte(result)); err != nil {
+		return "", fmt.Errorf("cannot translate %s: the translation %s is not a valid expression: %w", expression, result, err)
+	}
+	return result, nil
+}
+
+type celTokenKind int
+
+const (
+	celEOF celTokenKind = iota
+	celIdent
+	celString
+	celInt
+	celUint
+	celFloat
+	celPunct
+)
+
+type celToken struct {
+	kind celTokenKind
+	text string
+	// value is the value of literals
+	value interface{}
+	pos   int
+}
+
+func (t celToken) String() string {
+	if t.kind == celEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+var celPunctuation = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ".", ",", "+", "-", "*", "/", "%", "?", ":", "{", "}"}
+
+func celTokenize(s string) ([]celToken, error) {
+	var tokens []celToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			tok, err := celScanString(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case c >= '0' && c <= '9':
+			tok, err := celScanNumber(s, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i += len(tok.text)
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j]))) {
+				j++
+			}
+			tokens = append(tokens, celToken{kind: celIdent, text: s[i:j], pos: i})
+			i = j
+		default:
+			found := false
+			for _, punct := range celPunctuation {
+				if strings.HasPrefix(s[i:], punct) {
+					tokens = append(tokens, celToken{kind: celPunct, text: punct, pos: i})
+					i += len(punct)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+		}
+	}
+	return append(tokens, celToken{kind: celEOF, pos: len(s)}), nil
+}
+
+// celScanString scans the quoted string starting at the offset. The escape
+// sequences of CEL are the ones of Go.
+func celScanString(s string, start int) (celToken, error) {
+	quote := s[start]
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			text := s[start : i+1]
+			value, err := celUnquote(text[1 : len(text)-1])
+			if err != nil {
+				return celToken{}, fmt.Errorf("invalid string literal %s at offset %d", text, start)
+			}
+			return celToken{kind: celString, text: text, value: value, pos: start}, nil
+		}
+	}
+	return celToken{}, fmt.Errorf("unterminated string literal at offset %d", start)
+}
+
+// celUnquote unescapes the body of a string literal quoted with either double
+// or single quotes
+func celUnquote(body string) (string, error) {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body) && body[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case body[i] == '\\' && i+1 < len(body):
+			b.WriteString(body[i : i+2])
+			i++
+		case body[i] == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(body[i])
+		}
+	}
+	b.WriteByte('"')
+	return strconv.Unquote(b.String())
+}
+
+func celScanNumber(s string, start int) (celToken, error) {
+	i := start
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	isFloat := false
+	if i+1 < len(s) && s[i] == '.' && s[i+1] >= '0' && s[i+1] <= '9' {
+		isFloat = true
+		i++
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		isFloat = true
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+	}
+	text := s[start:i]
+	switch {
+	case isFloat:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return celToken{}, fmt.Errorf("invalid number %s at offset %d", text, start)
+		}
+		return celToken{kind: celFloat, text: text, value: f, pos: start}, nil
+	case i < len(s) && (s[i] == 'u' || s[i] == 'U'):
+		u, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return celToken{}, fmt.Errorf("invalid number %s at offset %d", text, start)
+		}
+		return celToken{kind: celUint, text: s[start : i+1], value: u, pos: start}, nil
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return celToken{}, fmt.Errorf("invalid number %s at offset %d", text, start)
+	}
+	return celToken{kind: celInt, text: text, value: n, pos: start}, nil
+}
+
+// celNode is a node of the AST of CEL expressions
+type celNode struct {
+	// op is the operator or function, empty for identifiers and literals
+	op string
+	// name is the name of identifiers and of the fields selected
+	name     string
+	literal  *celToken
+	operands []*celNode
+}
+
+type celParser struct {
+	tokens []celToken
+	pos    int
+}
+
+func (p *celParser) peek() celToken {
+	return p.tokens[p.pos]
+}
+
+func (p *celParser) next() celToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != celEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *celParser) accept(punct ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != celPunct && !(tok.kind == celIdent && tok.text == "in") {
+		return "", false
+	}
+	for _, want := range punct {
+		if tok.text == want {
+			p.pos++
+			return want, true
+		}
+	}
+	return "", false
+}
+
+func (p *celParser) expect(punct string) error {
+	if _, ok := p.accept(punct); !ok {
+		tok := p.peek()
+		return fmt.Errorf("expected %q, found %s at offset %d", punct, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *celParser) parseExpression() (*celNode, error) {
+	node, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind == celPunct && tok.text == "?" {
+		return nil, fmt.Errorf("conditional expressions cannot be translated, at offset %d", tok.pos)
+	}
+	return node, nil
+}
+
+// celBinaryOperators are the binary operators by precedence, loosest first
+var celBinaryOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<=", ">=", "<", ">", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *celParser) parseBinary(level int) (*celNode, error) {
+	if level == len(celBinaryOperators) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(celBinaryOperators[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &celNode{op: op, operands: []*celNode{left, right}}
+	}
+}
+
+func (p *celParser) parseUnary() (*celNode, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" && operand.literal != nil {
+			// negative number literals
+			switch v := operand.literal.value.(type) {
+			case int64:
+				return &celNode{literal: &celToken{kind: celInt, value: -v}}, nil
+			case float64:
+				return &celNode{literal: &celToken{kind: celFloat, value: -v}}, nil
+			}
+		}
+		return &celNode{op: "unary" + op, operands: []*celNode{operand}}, nil
+	}
+	return p.parseMember()
+}
+
+func (p *celParser) parseMember() (*celNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("."); ok {
+			tok := p.next()
+			if tok.kind != celIdent {
+				return nil, fmt.Errorf("expected a field name, found %s at offset %d", tok, tok.pos)
+			}
+			if _, ok := p.accept("("); ok {
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+				node = &celNode{op: "call", name: tok.text, operands: append([]*celNode{node}, args...)}
+				continue
+			}
+			node = &celNode{op: "select", name: tok.text, operands: []*celNode{node}}
+			continue
+		}
+		if _, ok := p.accept("["); ok {
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = &celNode{op: "index", operands: []*celNode{node, index}}
+			continue
+		}
+		return node, nil
+	}
+}
+
+func (p *celParser) parseArguments() ([]*celNode, error) {
+	var args []*celNode
+	if _, ok := p.accept(")"); ok {
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if _, ok := p.accept(")"); ok {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *celParser) parsePrimary() (*celNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case celString, celInt, celUint, celFloat:
+		return &celNode{literal: &tok}, nil
+	case celIdent:
+		switch tok.text {
+		case "true", "false":
+			return &celNode{literal: &celToken{kind: celIdent, text: tok.text, value: tok.text == "true"}}, nil
+		case "null":
+			return &celNode{literal: &celToken{kind: celIdent, text: tok.text}}, nil
+		}
+		if celReserved[tok.text] {
+			return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+		}
+		if _, ok := p.accept("("); ok {
+			args, err := p.parseArguments()
+			if err != nil {
+				return nil, err
+			}
+			return &celNode{op: "call", name: tok.text, operands: append([]*celNode{nil}, args...)}, nil
+		}
+		return &celNode{name: tok.text}, nil
+	case celPunct:
+		if tok.text == "(" {
+			node, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			return node, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+}
+
+// celToBexpr translates the AST of a boolean CEL expression
+func celToBexpr(node *celNode) (grammar.Expression, error) {
+	switch node.op {
+	case "||", "&&":
+		left, err := celToBexpr(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := celToBexpr(node.operands[1])
+		if err != nil {
+			return nil, err
+		}
+		operator := grammar.BinaryOpAnd
+		if node.op == "||" {
+			operator = grammar.BinaryOpOr
+		}
+		return &grammar.BinaryExpression{Operator: operator, Left: left, Right: right}, nil
+	case "unary!":
+		operand, err := celToBexpr(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.UnaryExpression{Operator: grammar.UnaryOpNot, Operand: operand}, nil
+	case "==", "!=", "<", "<=", ">", ">=":
+		return celRelation(node)
+	case "in":
+		left, err := celValue(node.operands[1])
+		if err != nil {
+			return nil, err
+		}
+		right, err := celValue(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.MatchExpression{Operator: grammar.MatchIn, Left: left, Right: right}, nil
+	case "call":
+		operator, ok := celFunctions[node.name]
+		if !ok || node.operands[0] == nil || len(node.operands) != 2 {
+			return nil, fmt.Errorf("cannot translate the function %s", node.name)
+		}
+		left, err := celValue(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := celValue(node.operands[1])
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.MatchExpression{Operator: operator, Left: left, Right: right}, nil
+	}
+	value, err := celValue(node)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// celRelation translates comparisons, along with the size and null checks
+func celRelation(node *celNode) (grammar.Expression, error) {
+	operators := map[string]grammar.MatchOperator{
+		"==": grammar.MatchEqual, "!=": grammar.MatchNotEqual,
+		"<": grammar.MatchLower, "<=": grammar.MatchLowerOrEqual,
+		">": grammar.MatchHigher, ">=": grammar.MatchHigherOrEqual,
+	}
+	left, right := node.operands[0], node.operands[1]
+	operator := operators[node.op]
+	if celIsZero(left) && celSize(right) != nil {
+		// 0 < size(x)
+		left, right = right, left
+		operator = mirroredOperators[operator]
+	}
+	if sized := celSize(left); sized != nil && celIsZero(right) {
+		value, err := celValue(sized)
+		if err != nil {
+			return nil, err
+		}
+		switch operator {
+		case grammar.MatchEqual, grammar.MatchLowerOrEqual:
+			return &grammar.MatchExpression{Operator: grammar.MatchIsEmpty, Left: value}, nil
+		case grammar.MatchNotEqual, grammar.MatchHigher:
+			return &grammar.MatchExpression{Operator: grammar.MatchIsNotEmpty, Left: value}, nil
+		}
+	}
+	if right.literal != nil && right.literal.kind == celIdent && right.literal.value == nil {
+		value, err := celValue(left)
+		if err != nil {
+			return nil, err
+		}
+		switch operator {
+		case grammar.MatchEqual:
+			return &grammar.MatchExpression{Operator: grammar.MatchIsNull, Left: value}, nil
+		case grammar.MatchNotEqual:
+			return &grammar.MatchExpression{Operator: grammar.MatchIsNotNull, Left: value}, nil
+		}
+	}
+
+	leftValue, err := celValue(left)
+	if err != nil {
+		return nil, err
+	}
+	rightValue, err := celValue(right)
+	if err != nil {
+		return nil, err
+	}
+	return &grammar.MatchExpression{Operator: operator, Left: leftValue, Right: rightValue}, nil
+}
+
+// celSize returns the operand of size(x) and x.size()
+func celSize(node *celNode) *celNode {
+	if node.op != "call" || node.name != "size" {
+		return nil
+	}
+	switch {
+	case node.operands[0] == nil && len(node.operands) == 2:
+		return node.operands[1]
+	case node.operands[0] != nil && len(node.operands) == 1:
+		return node.operands[0]
+	}
+	return nil
+}
+
+func celIsZero(node *celNode) bool {
+	if node.literal == nil {
+		return false
+	}
+	switch v := node.literal.value.(type) {
+	case int64:
+		return v == 0
+	case uint64:
+		return v == 0
+	}
+	return false
+}
+
+// celValue translates operands of comparisons and math
+func celValue(node *celNode) (*grammar.ExpressionValue, error) {
+	switch node.op {
+	case "+", "-", "*", "/":
+		left, err := celValue(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := celValue(node.operands[1])
+		if err != nil {
+			return nil, err
+		}
+		operator := map[string]grammar.MathOperator{
+			"+": grammar.MathOpPlus, "-": grammar.MathOpMinus, "*": grammar.MathOpMul, "/": grammar.MathOpDiv,
+		}[node.op]
+		return &grammar.ExpressionValue{Operator: operator, Left: celOperand(left), Right: celOperand(right)}, nil
+	case "", "select", "index":
+		if node.literal != nil {
+			return &grammar.ExpressionValue{Left: celLiteral(node.literal)}, nil
+		}
+		path, err := celPath(node)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.ExpressionValue{Left: &grammar.MatchValue{
+			Type:     grammar.ValueTypeReflect,
+			Selector: grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: path},
+		}}, nil
+	case "call":
+		if sized := celSize(node); sized != nil {
+			value, err := celValue(sized)
+			if err != nil {
+				return nil, err
+			}
+			return &grammar.ExpressionValue{Left: &grammar.MatchValue{
+				Type: grammar.ValueTypeCall,
+				Raw:  "len",
+				Args: []*grammar.ExpressionValue{value},
+			}}, nil
+		}
+		return nil, fmt.Errorf("cannot translate the function %s", node.name)
+	}
+	return nil, fmt.Errorf("cannot translate the operator %s", strings.TrimPrefix(node.op, "unary"))
+}
+
+// celOperand unwraps the values which are not math
+func celOperand(value *grammar.ExpressionValue) interface{} {
+	if value.Operator == grammar.MathOpValue {
+		return value.Left
+	}
+	return value
+}
+
+// celPath returns the path of field selections and constant indexes
+func celPath(node *celNode) ([]string, error) {
+	switch node.op {
+	case "":
+		if node.literal != nil {
+			return nil, fmt.Errorf("cannot select fields of literals")
+		}
+		return []string{node.name}, nil
+	case "select":
+		path, err := celPath(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		return append(path, node.name), nil
+	case "index":
+		path, err := celPath(node.operands[0])
+		if err != nil {
+			return nil, err
+		}
+		index := node.operands[1].literal
+		if index == nil || (index.kind != celString && index.kind != celInt && index.kind != celUint) {
+			return nil, fmt.Errorf("cannot translate indexes which are not constant strings or integers")
+		}
+		return append(path, fmt.Sprint(index.value)), nil
+	}
+	return nil, fmt.Errorf("cannot translate the operator %s within selectors", strings.TrimPrefix(node.op, "unary"))
+}
+
+func celLiteral(tok *celToken) *grammar.MatchValue {
+	switch v := tok.value.(type) {
+	case nil:
+		return &grammar.MatchValue{Type: grammar.ValueTypeNull, Raw: "null"}
+	case float64:
+		raw := strconv.FormatFloat(v, 'f', -1, 64)
+		if !strings.Contains(raw, ".") {
+			raw += ".0"
+		}
+		return &grammar.MatchValue{Type: grammar.ValueTypeFloat64, Raw: raw}
+	}
+	literal, _ := literalValue(tok.value)
+	return literal
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCELExpression(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		cel        string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"comparisons":      {expression: `Port >= 8000 and Name != "web" and Ratio < 0.5`, cel: `Port >= 8000 && Name != "web" && Ratio < 0.5`},
+		"precedence":       {expression: `(a == 1 or b == 2) and not (c == 3 and d == 4)`, cel: `(a == 1 || b == 2) && !(c == 3 && d == 4)`},
+		"in":               {expression: `"web" in Tags and Tags not contains "db"`, cel: `"web" in Tags && !("db" in Tags)`},
+		"empty and null":   {expression: `Tags is empty or Tags is not empty and Owner is null or Owner is not null`, cel: `size(Tags) == 0 || size(Tags) != 0 && Owner == null || Owner != null`},
+		"string functions": {expression: `Name startswith "a" and Name not endswith "b" and Name matches "^c"`, cel: `Name.startsWith("a") && !Name.endsWith("b") && Name.matches("^c")`},
+		"like":             {expression: `Name like "web-?.*"`, cel: `Name.matches("^web-[^/]\\.[^/]*$")`},
+		"selectors":        {expression: `Meta["a-b"].c == 1 and Tags.0 == "x" and Meta.in == 2`, cel: `Meta["a-b"].c == 1 && Tags[0] == "x" && Meta["in"] == 2`},
+		"literals":         {expression: `a == true and b == null and c == 18446744073709551615 and d == -1.5`, cel: `a == true && b == null && c == 18446744073709551615u && d == -1.5`},
+		"math":             {expression: `Port + 1 == 8081 and Ratio * 2 > 1`, cel: `Port + 1 == 8081 && Ratio * 2 > 1`},
+		"boolean selector": {expression: `Enabled and not Deleted`, cel: `Enabled && !Deleted`},
+		"let":              {expression: `let t = Meta.tier in t == "gold" or t == "platinum"`, cel: `Meta.tier == "gold" || Meta.tier == "platinum"`},
+		"params":           {expression: `Owner == $user`, opts: []Option{WithParams(map[string]interface{}{"user": "alice"})}, cel: `Owner == "alice"`},
+		"missing param":    {expression: `Owner == $user`, err: `cannot translate $user to CEL: no value bound to parameter $user`},
+		"reserved":         {expression: `"/for/x" == 1`, err: `cannot translate for.x to CEL: "for" is not a CEL identifier`},
+		"undefined":        {expression: `a == undefined`, err: `cannot translate undefined to CEL`},
+		"len":              {expression: `len(Tags) >= 2 and count(Name) < 64`, cel: `size(Tags) >= 2 && size(Name) < 64`},
+		"function":         {expression: `"a" in keys(Meta)`, err: `cannot translate keys(Meta) to CEL: the functions of bexpr are not supported`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			cel, err := eval.CELExpression(tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.cel, cel)
+		})
+	}
+}
+
+func TestFromCEL(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		cel        string
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"comparisons":      {cel: `Port >= 8000 && Name != 'web'`, expression: `Port >= 8000 and Name != "web"`},
+		"precedence":       {cel: `a == 1 || b == 2 && !(c == 3)`, expression: `a == 1 or b == 2 and not c == 3`},
+		"parentheses":      {cel: `(a == 1 || b == 2) && c == 3`, expression: `(a == 1 or b == 2) and c == 3`},
+		"in":               {cel: `"web" in Tags && !("db" in Tags)`, expression: `Tags contains "web" and not Tags contains "db"`},
+		"size":             {cel: `size(Tags) == 0 || Tags.size() > 0 || 0 != size(Owners)`, expression: `Tags is empty or Tags is not empty or Owners is not empty`},
+		"size compared":    {cel: `size(Tags) >= 2 && Name.size() + 1 < 64`, expression: `len(Tags) >= 2 and len(Name) + 1 < 64`},
+		"null":             {cel: `Owner == null && Manager != null`, expression: `Owner is null and Manager is not null`},
+		"string functions": {cel: `Name.startsWith("a") && Name.endsWith("b") && Name.matches("^c") && Name.contains("d")`, expression: `Name startswith "a" and Name endswith "b" and Name matches "^c" and Name contains "d"`},
+		"selectors":        {cel: `Meta["a b"].c == 1 && Tags[0] == "x" && Meta.tier == "gold"`, expression: `Meta["a b"].c == 1 and Tags.0 == "x" and Meta.tier == "gold"`},
+		"literals":         {cel: `a == true && b == 1u && c == -2 && d == 1e3 && e == 'it\'s "x"'`, expression: `a == true and b == 1 and c == -2 and d == 1000.0 and e == "it's \x22x\x22"`},
+		"math":             {cel: `Port + 1 == 8081`, expression: `Port + 1 == 8081`},
+		"boolean selector": {cel: `Enabled && !Deleted`, expression: `Enabled and not Deleted`},
+		"conditional":      {cel: `a ? b : c`, err: `conditional expressions cannot be translated, at offset 2`},
+		"macro":            {cel: `Tags.exists(t, t == "x")`, err: `cannot translate the function exists`},
+		"dynamic index":    {cel: `Meta[key] == 1`, err: `cannot translate indexes which are not constant strings or integers`},
+		"unterminated":     {cel: `a == "x`, err: `unterminated string literal at offset 5`},
+		"trailing":         {cel: `a == 1 )`, err: `unexpected ")" at offset 7`},
+		"nested math":      {cel: `a + 1 + 2 == 3`, err: `cannot translate a + 1 + 2 == 3: the translation (a + 1) + 2 == 3 is not a valid expression: 1:9 (8): no match found, expected: "and", "or", [ \t\r\n] or EOF`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := FromCEL(tcase.cel)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expression, expression)
+		})
+	}
+}
+
+func TestCEL_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, expression := range []string{
+		`Port >= 8000 and (Name == "web" or "web" in Tags) and not Deleted`,
+		`Tags is not empty and Owner is null and Name startswith "a"`,
+		`Meta["a b"].c == 1 and Tags.0 == "x"`,
+	} {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		cel, err := eval.CELExpression()
+		require.NoError(t, err)
+		translated, err := FromCEL(cel)
+		require.NoError(t, err)
+		equivalent, err := Equivalent(expression, translated)
+		require.NoError(t, err)
+		require.True(t, equivalent, "%s translated to %s and back to %s", expression, cel, translated)
+	}
+}
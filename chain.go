@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// EvaluateChained evaluates the expression like Evaluate, looking up the
+// selectors missing from the primary datum in the fallback datums, in the
+// order they are given. This resolves layered data such as configuration set
+// at the instance, group and global levels. A selector missing from every
+// datum takes the value set with WithUnknownValue, if any. It is otherwise
+// handled as a missing map key when it is one in any of the datums, and
+// reported as missing from the primary datum if not.
+func (eval *Evaluator) EvaluateChained(primary interface{}, fallbacks ...interface{}) (interface{}, error) {
+	return eval.Evaluate(append(chainedDatum{primary}, fallbacks...))
+}
+
+// chainedDatum is a datum whose selectors are resolved in the first of the
+// datums they can be found in
+type chainedDatum []interface{}
+
+func (c chainedDatum) getValue(expressionValue *grammar.MatchValue, opt ...Option) (interface{}, error) {
+	// the unknown value only stands for selectors missing from every datum
+	lookupOpts := append(opt[:len(opt):len(opt)], func(o *options) {
+		o.withUnknown = nil
+	})
+	var notFoundErr error
+	missingKey := false
+	for i, datum := range c {
+		val, err := getValue(expressionValue, datum, lookupOpts...)
+		switch {
+		case !isUndefined(val) || (err != nil && !isNotFound(err)):
+			return val, err
+		case err == nil:
+			missingKey = true
+		case i == 0:
+			notFoundErr = err
+		}
+	}
+
+	if unknown := getOpts(opt...).withUnknown; unknown != nil {
+		return convertJSONNumber(*unknown)
+	}
+	// a selector handled as a missing map key in any of the datums is
+	// handled as such, else the error of the primary datum is reported
+	if missingKey {
+		return &undefined, nil
+	}
+	return &undefined, notFoundErr
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateChained(t *testing.T) {
+	t.Parallel()
+
+	type group struct {
+		Region string
+		Limits map[string]int
+	}
+
+	instance := map[string]interface{}{
+		"Name":   "web-1",
+		"Limits": map[string]interface{}{"cpu": 4},
+		"Owner":  nil,
+	}
+	parent := group{
+		Region: "eu",
+		Limits: map[string]int{"cpu": 2, "memory": 512},
+	}
+	global := map[string]interface{}{
+		"Region": "us",
+		"Owner":  "ops",
+		"Tier":   "free",
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"primary":                {expression: `Name == "web-1"`, result: true},
+		"primary wins":           {expression: `Limits.cpu == 4`, result: true},
+		"nested fallback":        {expression: `Limits.memory == 512`, result: true},
+		"first fallback wins":    {expression: `Region == "eu"`, result: true},
+		"last fallback":          {expression: `Tier == "free"`, result: true},
+		"null is not missing":    {expression: `Owner is null`, result: true},
+		"missing everywhere":     {expression: `Zone == "a"`, err: `couldn't find key "Zone"`},
+		"missing nested":         {expression: `Limits.disk != 1`, result: true},
+		"unknown value last":     {expression: `Zone == "none" and Region == "eu"`, opts: []Option{WithUnknownValue("none")}, result: true},
+		"mixed layers":           {expression: `Name == "web-1" and Region == "eu" and Tier == "free"`, result: true},
+		"fallback not consulted": {expression: `Limits.cpu == 2`, result: false},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression, tc.opts...)
+			require.NoError(t, err)
+
+			result, err := eval.EvaluateChained(instance, parent, global)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command bexpr-gen generates the accessors resolving the selectors of
+// boolean expressions in Go struct types without reflection.
+//
+//	bexpr-gen -type Service,Meta [-tag bexpr] [-output service_bexpr.go] [dir]
+//
+// It is meant to be run by go generate in the package declaring the types:
+//
+//	//go:generate bexpr-gen -type Service,Meta
+//
+// The pointers to the types implement bexpr.TypedSelectorSource: evaluators
+// resolve the selectors of the pointers given to them with the GetPath method
+// generated, and compare their strings, booleans, integers and float64s
+// without reflection nor allocations, see bexpr.TypedSelectorSource. Fields
+// are named as the tag names them, like with bexpr.WithTagName. The fields of
+// the predeclared types, of the types generated and the maps keyed by strings
+// holding predeclared types are resolved by the generated code, the others
+// being resolved with reflection, see bexpr.LookupPath. As for any
+// bexpr.SelectorSource, hooks are not called on the values resolved.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+func run(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bexpr-gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	types := flags.String("type", "", "comma separated names of the struct types to generate accessors for")
+	tag := flags.String("tag", "bexpr", "the struct tag naming the fields")
+	output := flags.String("output", "", "the file to write, <first type>_bexpr.go by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *types == "" || flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "usage: bexpr-gen -type T1,T2 [-tag bexpr] [-output file] [dir]")
+		return 2
+	}
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+	names := strings.Split(*types, ",")
+
+	src, err := generate(dir, names, *tag)
+	if err != nil {
+		fmt.Fprintf(stderr, "bexpr-gen: %v\n", err)
+		return 1
+	}
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(names[0])+"_bexpr.go")
+	}
+	if err := ioutil.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintf(stderr, "bexpr-gen: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// fieldKind is how the generated code resolves a field
+type fieldKind int
+
+const (
+	// fieldReflect fields are resolved with bexpr.LookupPath
+	fieldReflect fieldKind = iota
+	// fieldBasic fields are of a predeclared type
+	fieldBasic
+	// fieldMap fields are maps keyed by strings holding a predeclared type
+	fieldMap
+	// fieldStruct fields are of a type generated
+	fieldStruct
+	// fieldPointer fields are pointers to a type generated
+	fieldPointer
+)
+
+// field is a field of a struct, named by a selector part
+type field struct {
+	key  string
+	name string
+	kind fieldKind
+	// basic is the predeclared type of the basic fields, and of the values
+	// of the maps
+	basic string
+}
+
+// typedValues are the constructors of the bexpr.TypedValue of the predeclared
+// types, by type
+var typedValues = map[string]string{
+	"string":  "TypedString",
+	"bool":    "TypedBool",
+	"int":     "TypedInt",
+	"int8":    "TypedInt",
+	"int16":   "TypedInt",
+	"int32":   "TypedInt",
+	"int64":   "TypedInt",
+	"rune":    "TypedInt",
+	"uint":    "TypedUint",
+	"uint8":   "TypedUint",
+	"uint16":  "TypedUint",
+	"uint32":  "TypedUint",
+	"uint64":  "TypedUint",
+	"byte":    "TypedUint",
+	"float64": "TypedFloat",
+}
+
+// basicTypes are the predeclared types the generated code returns as is
+var basicTypes = map[string]bool{"float32": true, "uintptr": true, "complex64": true, "complex128": true}
+
+func init() {
+	for typ := range typedValues {
+		basicTypes[typ] = true
+	}
+}
+
+// generate returns the source of the accessors of the types declared by the
+// package in the directory
+func generate(dir string, names []string, tag string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+
+	structs := make(map[string]*ast.StructType)
+	for _, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+	generated := make(map[string]bool)
+	for _, name := range names {
+		if structs[name] == nil {
+			return nil, fmt.Errorf("no struct type %s in %s", name, dir)
+		}
+		generated[name] = true
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by bexpr-gen; DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	fmt.Fprintf(&buf, "import bexpr %q\n", "github.com/gterranova/go-bexpr")
+	for _, name := range names {
+		fields, err := structFields(structs[name], tag, generated)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", name, err)
+		}
+		writeGetPath(&buf, name, fields, tag)
+		writeGetTypedPath(&buf, name, fields)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// structFields returns the fields the parts of selectors name, the way
+// pointerstructure finds them: the first field whose tag is the part, or else
+// the last untagged field whose name is the part, unless a field with that
+// name is ignored with the "-" tag. They are sorted by key.
+func structFields(st *ast.StructType, tag string, generated map[string]bool) ([]field, error) {
+	byTag := make(map[string]field)
+	byName := make(map[string]field)
+	ignored := make(map[string]bool)
+	for _, f := range st.Fields.List {
+		names := f.Names
+		if len(names) == 0 {
+			// embedded fields are named after their type
+			names = []*ast.Ident{embeddedName(f.Type)}
+		}
+		var fieldTag string
+		if f.Tag != nil {
+			lit, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			fieldTag = reflect.StructTag(lit).Get(tag)
+			if idx := strings.Index(fieldTag, ","); idx != -1 {
+				fieldTag = fieldTag[0:idx]
+			}
+			if strings.Contains(fieldTag, "|") {
+				return nil, errors.New("struct tags cannot contain the '|' character")
+			}
+		}
+		for _, name := range names {
+			if name == nil || !name.IsExported() {
+				continue
+			}
+			fd := field{name: name.Name}
+			fd.kind, fd.basic = classify(f.Type, generated)
+			switch fieldTag {
+			case "":
+				fd.key = name.Name
+				byName[name.Name] = fd
+			case "-":
+				ignored[name.Name] = true
+			default:
+				if _, ok := byTag[fieldTag]; !ok {
+					fd.key = fieldTag
+					byTag[fieldTag] = fd
+				}
+			}
+		}
+	}
+	for name, fd := range byName {
+		if _, ok := byTag[name]; !ok && !ignored[name] {
+			byTag[name] = fd
+		}
+	}
+	fields := make([]field, 0, len(byTag))
+	for _, fd := range byTag {
+		fields = append(fields, fd)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+	return fields, nil
+}
+
+// embeddedName returns the name of the embedded field of the type
+func embeddedName(typ ast.Expr) *ast.Ident {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel
+	}
+	return nil
+}
+
+// classify returns how the field of the type is resolved
+func classify(typ ast.Expr, generated map[string]bool) (fieldKind, string) {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		switch {
+		case basicTypes[t.Name]:
+			return fieldBasic, t.Name
+		case generated[t.Name]:
+			return fieldStruct, t.Name
+		}
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok && generated[ident.Name] {
+			return fieldPointer, ident.Name
+		}
+	case *ast.MapType:
+		key, keyOK := t.Key.(*ast.Ident)
+		value, valueOK := t.Value.(*ast.Ident)
+		if keyOK && valueOK && key.Name == "string" && basicTypes[value.Name] {
+			return fieldMap, value.Name
+		}
+	}
+	return fieldReflect, ""
+}
+
+func writeGetPath(w io.Writer, name string, fields []field, tag string) {
+	fmt.Fprintf(w, "\n// GetPath implements bexpr.SelectorSource\n")
+	fmt.Fprintf(w, "func (v *%s) GetPath(path []string) (interface{}, bool, error) {\n", name)
+	fmt.Fprintf(w, "if len(path) == 0 {\nreturn v, true, nil\n}\n")
+	fmt.Fprintf(w, "switch path[0] {\n")
+	for _, f := range fields {
+		switch f.kind {
+		case fieldBasic:
+			fmt.Fprintf(w, "case %q:\nif len(path) == 1 {\nreturn v.%s, true, nil\n}\n", f.key, f.name)
+		case fieldMap:
+			fmt.Fprintf(w, "case %q:\nswitch len(path) {\ncase 1:\nreturn v.%s, true, nil\n", f.key, f.name)
+			fmt.Fprintf(w, "case 2:\nvalue, ok := v.%s[path[1]]\nreturn value, ok, nil\n}\n", f.name)
+		case fieldStruct:
+			fmt.Fprintf(w, "case %q:\nif len(path) == 1 {\nreturn v.%s, true, nil\n}\n", f.key, f.name)
+			fmt.Fprintf(w, "if value, ok, err := v.%s.GetPath(path[1:]); err == nil {\nreturn value, ok, nil\n}\n", f.name)
+		case fieldPointer:
+			fmt.Fprintf(w, "case %q:\nif len(path) == 1 {\nreturn v.%s, true, nil\n}\n", f.key, f.name)
+			fmt.Fprintf(w, "if v.%s != nil {\nif value, ok, err := v.%s.GetPath(path[1:]); err == nil {\nreturn value, ok, nil\n}\n}\n", f.name, f.name)
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "// the other fields are resolved with reflection, which reports the errors\n")
+	fmt.Fprintf(w, "// with the whole path\n")
+	fmt.Fprintf(w, "return bexpr.LookupPath(*v, path, bexpr.WithTagName(%q))\n}\n", tag)
+}
+
+func writeGetTypedPath(w io.Writer, name string, fields []field) {
+	fmt.Fprintf(w, "\n// GetTypedPath implements bexpr.TypedSelectorSource\n")
+	fmt.Fprintf(w, "func (v *%s) GetTypedPath(path []string) (bexpr.TypedValue, bool) {\n", name)
+	fmt.Fprintf(w, "if len(path) == 0 {\nreturn bexpr.TypedValue{}, false\n}\n")
+	fmt.Fprintf(w, "switch path[0] {\n")
+	for _, f := range fields {
+		constructor := typedValues[f.basic]
+		switch {
+		case f.kind == fieldBasic && constructor != "":
+			fmt.Fprintf(w, "case %q:\nif len(path) == 1 {\nreturn bexpr.%s(%s), true\n}\n", f.key, constructor, convert(f.basic, "v."+f.name))
+		case f.kind == fieldMap && constructor != "":
+			fmt.Fprintf(w, "case %q:\nif len(path) == 2 {\nif value, ok := v.%s[path[1]]; ok {\nreturn bexpr.%s(%s), true\n}\n}\n",
+				f.key, f.name, constructor, convert(f.basic, "value"))
+		case f.kind == fieldStruct:
+			fmt.Fprintf(w, "case %q:\nreturn v.%s.GetTypedPath(path[1:])\n", f.key, f.name)
+		case f.kind == fieldPointer:
+			fmt.Fprintf(w, "case %q:\nif v.%s != nil {\nreturn v.%s.GetTypedPath(path[1:])\n}\n", f.key, f.name, f.name)
+		}
+	}
+	fmt.Fprintf(w, "}\nreturn bexpr.TypedValue{}, false\n}\n")
+}
+
+// convert returns the expression converting the value of the predeclared type
+// into the type of the argument of its TypedValue constructor
+func convert(basic string, value string) string {
+	switch typedValues[basic] {
+	case "TypedInt":
+		if basic != "int64" {
+			return "int64(" + value + ")"
+		}
+	case "TypedUint":
+		if basic != "uint64" {
+			return "uint64(" + value + ")"
+		}
+	}
+	return value
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	// the example is generated by go generate
+	expected, err := ioutil.ReadFile("../../examples/generated/service_bexpr.go")
+	require.NoError(t, err)
+	src, err := generate("../../examples/generated", []string{"Service", "Meta"}, "bexpr")
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src))
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "bexpr-gen")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(src string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0o644))
+	}
+
+	write("package types\n\ntype Service struct {\n\tName string `json:\"name\"`\n\tid int\n}\n")
+	var stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"-type", "Service", "-tag", "json", dir}, &stderr), stderr.String())
+	src, err := ioutil.ReadFile(filepath.Join(dir, "service_bexpr.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(src), `case "name":`)
+	require.NotContains(t, string(src), `"id"`)
+	require.Contains(t, string(src), `bexpr.WithTagName("json")`)
+
+	stderr.Reset()
+	require.Equal(t, 1, run([]string{"-type", "Missing", dir}, &stderr))
+	require.Contains(t, stderr.String(), "no struct type Missing in ")
+
+	write("package types\n\ntype Service struct {\n\tName string `bexpr:\"a|b\"`\n}\n")
+	stderr.Reset()
+	require.Equal(t, 1, run([]string{"-type", "Service", dir}, &stderr))
+	require.Equal(t, "bexpr-gen: type Service: struct tags cannot contain the '|' character\n", stderr.String())
+
+	stderr.Reset()
+	require.Equal(t, 2, run(nil, &stderr))
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// positionRe matches the position the syntax errors start with, such as
+// "1:5 (4): "
+var positionRe = regexp.MustCompile(`^(\d+):(\d+) \(\d+\): `)
+
+// response is the object returned to JavaScript. Its values are the ones
+// js.ValueOf accepts.
+type response map[string]interface{}
+
+// errorResponse reports the error, with the line and column of the first
+// syntax error or of the sub-expression which failed to evaluate, if any
+func errorResponse(err error) response {
+	resp := response{"error": err.Error()}
+	if m := positionRe.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		column, _ := strconv.Atoi(m[2])
+		resp["line"] = line
+		resp["column"] = column
+	}
+	return resp
+}
+
+// parse returns the AST of the expression, as written by grammar.Dump
+func parse(expression string) response {
+	ast, err := grammar.Parse("", []byte(expression))
+	if err != nil {
+		return errorResponse(err)
+	}
+	var b strings.Builder
+	if err := grammar.Dump(&b, ast.(grammar.Expression), grammar.DumpConfig{}); err != nil {
+		return errorResponse(err)
+	}
+	return response{"ast": b.String()}
+}
+
+// tokenize returns the tokens of the expression, to highlight it as it is
+// typed, see grammar.Tokenize
+func tokenize(expression string) response {
+	tokens := []interface{}{}
+	for _, token := range grammar.Tokenize([]byte(expression)) {
+		tokens = append(tokens, map[string]interface{}{
+			"kind":   token.Kind.String(),
+			"text":   token.Text,
+			"offset": token.Position.Offset,
+			"line":   token.Position.Line,
+			"column": token.Position.Column,
+		})
+	}
+	return response{"tokens": tokens}
+}
+
+// validate checks the syntax of the expression and, given a JSON Schema, the
+// selectors and operators of the expression against the schema
+func validate(expression, schema string) response {
+	var opts []bexpr.Option
+	if schema != "" {
+		s, err := bexpr.JSONSchema([]byte(schema))
+		if err != nil {
+			return errorResponse(fmt.Errorf("invalid schema: %w", err))
+		}
+		opts = append(opts, bexpr.WithSchema(s))
+	}
+	if _, err := bexpr.CreateEvaluator(expression, opts...); err != nil {
+		resp := errorResponse(err)
+		resp["valid"] = false
+		return resp
+	}
+	return response{"valid": true}
+}
+
+// evaluate evaluates the expression against the JSON document
+func evaluate(expression, document string) response {
+	eval, err := bexpr.CreateEvaluator(expression)
+	if err != nil {
+		return errorResponse(err)
+	}
+	var datum interface{}
+	if err := json.Unmarshal([]byte(document), &datum); err != nil {
+		return errorResponse(fmt.Errorf("invalid document: %w", err))
+	}
+	result, err := eval.Evaluate(datum)
+	if err != nil {
+		return errorResponse(err)
+	}
+	return response{"result": result}
+}
+
+// filter returns the indexes of the elements of the JSON array which match
+// the expression, to preview the documents a filter selects
+func filter(expression, documents string) response {
+	eval, err := bexpr.CreateEvaluator(expression)
+	if err != nil {
+		return errorResponse(err)
+	}
+	var datums []interface{}
+	if err := json.Unmarshal([]byte(documents), &datums); err != nil {
+		return errorResponse(fmt.Errorf("invalid documents: %w", err))
+	}
+	matches := []interface{}{}
+	for i, datum := range datums {
+		result, err := eval.Evaluate(datum)
+		if err != nil {
+			return errorResponse(fmt.Errorf("document %d: %w", i, err))
+		}
+		if result == true {
+			matches = append(matches, i)
+		}
+	}
+	return response{"matches": matches}
+}
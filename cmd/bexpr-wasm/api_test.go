@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, response{"ast": "Equal {\n   Selector: Name\n   Value: \"web\"\n}\n"}, parse(`Name == "web"`))
+	resp := parse(`Name === "web"`)
+	require.Equal(t, 1, resp["line"])
+	require.Equal(t, 8, resp["column"])
+	require.Contains(t, resp["error"], "no match found")
+}
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, response{"tokens": []interface{}{
+		map[string]interface{}{"kind": "identifier", "text": "Name", "offset": 0, "line": 1, "column": 1},
+		map[string]interface{}{"kind": "whitespace", "text": " ", "offset": 4, "line": 1, "column": 5},
+		map[string]interface{}{"kind": "operator", "text": "==", "offset": 5, "line": 1, "column": 6},
+		map[string]interface{}{"kind": "whitespace", "text": " ", "offset": 7, "line": 1, "column": 8},
+		map[string]interface{}{"kind": "invalid", "text": `"web`, "offset": 8, "line": 1, "column": 9},
+	}}, tokenize(`Name == "web`))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := `{"type": "object", "properties": {"Name": {"type": "string"}, "Port": {"type": "integer"}}}`
+
+	type testCase struct {
+		expression string
+		schema     string
+		response   response
+	}
+
+	tests := map[string]testCase{
+		"valid":          {expression: `Name == "web"`, response: response{"valid": true}},
+		"syntax":         {expression: `Name ==`, response: response{"valid": false, "error": `1:8 (7): no match found, expected: "$", "-", "0", "[", "\"", "` + "`" + `", "false", "null", "true", "undefined", [ \t\r\n], [1-9] or [a-zA-Z]`, "line": 1, "column": 8}},
+		"schema":         {expression: `Port > 8000`, schema: schema, response: response{"valid": true}},
+		"unknown field":  {expression: `Host == "a"`, schema: schema, response: response{"valid": false, "error": `error finding value in schema: /Host at part 0: couldn't find key: property "Host"`}},
+		"invalid schema": {expression: `Port > 8000`, schema: `{`, response: response{"error": "invalid schema: failed to decode JSON schema: unexpected end of JSON input"}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tcase.response, validate(tcase.expression, tcase.schema))
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, response{"result": true}, evaluate(`Port > 8000`, `{"Port": 8080}`))
+	require.Equal(t, response{"result": false}, evaluate(`Port > 8000`, `{"Port": 80}`))
+	require.Equal(t, response{"error": "invalid document: unexpected end of JSON input"}, evaluate(`Port > 8000`, `{`))
+	require.Equal(t, response{"error": `1:1 (0): Port > 8000: operator "Higher" cannot be used with values of type string`, "line": 1, "column": 1}, evaluate(`Port > 8000`, `{"Port": "80"}`))
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, response{"matches": []interface{}{1, 2}}, filter(`Port > 8000`, `[{"Port": 80}, {"Port": 8080}, {"Port": 8443}]`))
+	require.Equal(t, response{"matches": []interface{}{}}, filter(`Port > 8000`, `[]`))
+	require.Equal(t, response{"error": "invalid documents: json: cannot unmarshal object into Go value of type []interface {}"}, filter(`Port > 8000`, `{}`))
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !(js && wasm)
+
+// Command bexpr-wasm exposes the parsing, tokenization, validation and evaluation of
+// expressions to JavaScript, to validate filters and preview the documents
+// they match in a browser. It is built with
+//
+//	GOOS=js GOARCH=wasm go build -o bexpr.wasm ./cmd/bexpr-wasm
+//
+// and loaded with the wasm_exec.js of the Go distribution, which defines the
+// global bexpr object:
+//
+//	bexpr.parse(expression)             {ast} or {error, line, column}
+//	bexpr.tokenize(expression)          {tokens}, each one {kind, text, offset, line, column}
+//	bexpr.validate(expression, schema)  {valid}, or {valid: false, error, line, column}
+//	bexpr.evaluate(expression, json)    {result} or {error}
+//	bexpr.filter(expression, jsonArray) {matches}, the indexes of the matching elements, or {error}
+//
+// The schema given to validate is an optional JSON Schema, see
+// bexpr.JSONSchema. The line and column of errors are set for syntax errors.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "bexpr-wasm must be built with GOOS=js GOARCH=wasm")
+	os.Exit(2)
+}
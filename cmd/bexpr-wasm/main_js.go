@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+func main() {
+	js.Global().Set("bexpr", js.ValueOf(map[string]interface{}{
+		"parse": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return js.ValueOf(map[string]interface{}(parse(arg(args, 0))))
+		}),
+		"tokenize": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return js.ValueOf(map[string]interface{}(tokenize(arg(args, 0))))
+		}),
+		"validate": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return js.ValueOf(map[string]interface{}(validate(arg(args, 0), arg(args, 1))))
+		}),
+		"evaluate": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return js.ValueOf(map[string]interface{}(evaluate(arg(args, 0), arg(args, 1))))
+		}),
+		"filter": js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			return js.ValueOf(map[string]interface{}(filter(arg(args, 0), arg(args, 1))))
+		}),
+	}))
+	// the functions are called by JavaScript for the lifetime of the page
+	select {}
+}
+
+// arg returns the string argument, or the empty string when it is missing or
+// not a string
+func arg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
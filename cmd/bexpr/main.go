@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Command bexpr parses a boolean expression and filters the JSON documents
+// read from stdin with it, to try filters out before deploying them.
+//
+//	bexpr [flags] expression < documents.json
+//
+// Each JSON value read from stdin is a document, several of them being
+// separated by whitespace. The documents matching the expression are written
+// to stdout, one per line. The elements of arrays are filtered rather than the
+// arrays themselves, the matching ones being written as an array. Syntax
+// errors are reported with the position of the error in the expression.
+//
+// The exit status is 0 when a document matched, 1 when none did and 2 on
+// errors, as with grep.
+//
+// The fmt subcommand writes the expression, read from stdin when it is not
+// given, with canonical spacing and quoting, breaking the long chains of "and"
+// and "or" over several lines, see bexpr.Format:
+//
+//	bexpr fmt [-width 80] [-dialect bexpr] [expression]
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+const (
+	exitMatch   = 0
+	exitNoMatch = 1
+	exitError   = 2
+)
+
+// positionRe matches the position the syntax errors start with, such as
+// "1:5 (4): "
+var positionRe = regexp.MustCompile(`^(\d+):(\d+) \(\d+\): `)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// params collects the -param flags
+type params map[string]interface{}
+
+func (p params) String() string {
+	return ""
+}
+
+func (p params) Set(s string) error {
+	name, value := s, ""
+	if i := strings.Index(s, "="); i >= 0 {
+		name, value = s[:i], s[i+1:]
+	}
+	if name == "" {
+		return fmt.Errorf("%q is not of the form name=value", s)
+	}
+	// values are JSON values, bare words being strings
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		decoded = value
+	}
+	p[name] = decoded
+	return nil
+}
+
+// parseDialect parses the value of the -dialect flag
+func parseDialect(dialect string) (grammar.SelectorDialect, error) {
+	switch dialect {
+	case "bexpr":
+		return grammar.SelectorDialectBexpr, nil
+	case "jsonpointer":
+		return grammar.SelectorDialectJSONPointer, nil
+	case "jsonpath":
+		return grammar.SelectorDialectJSONPath, nil
+	default:
+		return 0, fmt.Errorf("unknown dialect %q", dialect)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "fmt" {
+		return runFmt(args[1:], stdin, stdout, stderr)
+	}
+	flags := flag.NewFlagSet("bexpr", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: bexpr [flags] expression < documents.json")
+		flags.PrintDefaults()
+	}
+	ast := flags.Bool("ast", false, "write the AST of the expression instead of filtering documents")
+	dialect := flags.String("dialect", "bexpr", "the syntax of the selectors: bexpr, jsonpointer or jsonpath")
+	maxExpressions := flags.Uint64("max-expressions", 0, "the maximum number of expressions, 0 meaning no limit")
+	params := params{}
+	flags.Var(params, "param", "bind the parameter `name=value`, such as -param user=alice, the value being JSON or a string")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitMatch
+		}
+		return exitError
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitError
+	}
+	expression := flags.Arg(0)
+
+	opts := []bexpr.Option{bexpr.WithMaxExpressions(*maxExpressions), bexpr.WithParams(params)}
+	var parserOpts []grammar.Option
+	if *maxExpressions != 0 {
+		parserOpts = append(parserOpts, grammar.MaxExpressions(*maxExpressions))
+	}
+	selectorDialect, err := parseDialect(*dialect)
+	if err != nil {
+		fmt.Fprintf(stderr, "bexpr: %v\n", err)
+		return exitError
+	}
+	if selectorDialect != grammar.SelectorDialectBexpr {
+		opts = append(opts, bexpr.WithSelectorDialect(selectorDialect))
+		parserOpts = append(parserOpts, grammar.Dialect(selectorDialect))
+	}
+
+	if *ast {
+		parsed, err := grammar.Parse("", []byte(expression), parserOpts...)
+		if err != nil {
+			printSyntaxError(stderr, expression, err)
+			return exitError
+		}
+		if err := grammar.Dump(stdout, parsed.(grammar.Expression), grammar.DumpConfig{}); err != nil {
+			fmt.Fprintf(stderr, "bexpr: %v\n", err)
+			return exitError
+		}
+		return exitMatch
+	}
+
+	eval, err := bexpr.CreateEvaluator(expression, opts...)
+	if err != nil {
+		printSyntaxError(stderr, expression, err)
+		return exitError
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	decoder := json.NewDecoder(stdin)
+	decoder.UseNumber()
+	status := exitNoMatch
+	for n := 1; ; n++ {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			fmt.Fprintf(stderr, "bexpr: document %d: %v\n", n, err)
+			return exitError
+		}
+		matched, err := filter(eval, doc)
+		if err != nil {
+			fmt.Fprintf(stderr, "bexpr: document %d: %v\n", n, err)
+			status = exitError
+			continue
+		}
+		if matched == nil {
+			continue
+		}
+		encoded, err := json.Marshal(matched)
+		if err != nil {
+			fmt.Fprintf(stderr, "bexpr: document %d: %v\n", n, err)
+			return exitError
+		}
+		fmt.Fprintf(out, "%s\n", encoded)
+		if status == exitNoMatch {
+			status = exitMatch
+		}
+	}
+	return status
+}
+
+// runFmt runs the fmt subcommand
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bexpr fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: bexpr fmt [flags] [expression]")
+		flags.PrintDefaults()
+	}
+	width := flags.Int("width", 80, "the length of the lines beyond which chains of and and or are broken, 0 meaning no limit")
+	dialect := flags.String("dialect", "bexpr", "the syntax of the selectors: bexpr, jsonpointer or jsonpath")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitMatch
+		}
+		return exitError
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitError
+	}
+	selectorDialect, err := parseDialect(*dialect)
+	if err != nil {
+		fmt.Fprintf(stderr, "bexpr: %v\n", err)
+		return exitError
+	}
+	expression := flags.Arg(0)
+	if flags.NArg() == 0 {
+		read, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "bexpr: %v\n", err)
+			return exitError
+		}
+		expression = strings.TrimSpace(string(read))
+	}
+
+	formatted, err := bexpr.Format(expression, bexpr.FormatConfig{Width: *width, Dialect: selectorDialect})
+	if err != nil {
+		printSyntaxError(stderr, expression, err)
+		return exitError
+	}
+	fmt.Fprintln(stdout, formatted)
+	return exitMatch
+}
+
+// filter returns the document if it matches, or the elements matching of the
+// documents which are arrays. It returns nil when nothing matched.
+func filter(eval *bexpr.Evaluator, doc interface{}) (interface{}, error) {
+	elements, ok := doc.([]interface{})
+	if !ok {
+		matched, err := match(eval, doc)
+		if err != nil || !matched {
+			return nil, err
+		}
+		return doc, nil
+	}
+	var matching []interface{}
+	for i, element := range elements {
+		matched, err := match(eval, element)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if matched {
+			matching = append(matching, element)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	return matching, nil
+}
+
+func match(eval *bexpr.Evaluator, doc interface{}) (bool, error) {
+	result, err := eval.Evaluate(doc)
+	if err != nil {
+		return false, err
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("the expression evaluated to %v rather than a boolean", result)
+	}
+	return matched, nil
+}
+
+// printSyntaxError writes the error, pointing at the position of the syntax
+// errors in the expression
+func printSyntaxError(w io.Writer, expression string, err error) {
+	for _, line := range strings.Split(err.Error(), "\n") {
+		fmt.Fprintf(w, "bexpr: %s\n", line)
+		m := positionRe.FindStringSubmatch(line)
+		if m == nil || m[1] != "1" || strings.Contains(expression, "\n") {
+			continue
+		}
+		col, _ := strconv.Atoi(m[2])
+		fmt.Fprintf(w, "  %s\n  %s^\n", expression, strings.Repeat(" ", col-1))
+	}
+}
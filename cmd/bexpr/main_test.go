@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		args   []string
+		stdin  string
+		status int
+		stdout string
+		stderr string
+	}
+
+	tests := map[string]testCase{
+		"document": {
+			args:   []string{`Name == "web"`},
+			stdin:  `{"Name": "web", "Port": 80}`,
+			stdout: `{"Name":"web","Port":80}` + "\n",
+		},
+		"no match": {
+			args:   []string{`Name == "web"`},
+			stdin:  `{"Name": "db"}`,
+			status: exitNoMatch,
+		},
+		"array": {
+			args:   []string{`Port > 8000`},
+			stdin:  `[{"Port": 80}, {"Port": 8080}, {"Port": 8443}]`,
+			stdout: `[{"Port":8080},{"Port":8443}]` + "\n",
+		},
+		"stream": {
+			args:   []string{`Port > 8000`},
+			stdin:  `{"Port": 80} {"Port": 8080.5}` + "\n" + `[{"Port": 1}]`,
+			stdout: `{"Port":8080.5}` + "\n",
+		},
+		"params": {
+			args:   []string{"-param", "user=alice", "-param", "max=3", `Owner == $user and Count < $max`},
+			stdin:  `{"Owner": "alice", "Count": 2}`,
+			stdout: `{"Count":2,"Owner":"alice"}` + "\n",
+		},
+		"dialect": {
+			args:   []string{"-dialect", "jsonpath", `$.Meta["a b"] == 1`},
+			stdin:  `{"Meta": {"a b": 1}}`,
+			stdout: `{"Meta":{"a b":1}}` + "\n",
+		},
+		"ast": {
+			args:   []string{"-ast", `Name == "web"`},
+			stdout: "Equal {\n   Selector: Name\n   Value: \"web\"\n}\n",
+		},
+		"syntax error": {
+			args:   []string{`Name == == "web"`},
+			status: exitError,
+			stderr: "bexpr: 1:9 (8): no match found",
+		},
+		"syntax error position": {
+			args:   []string{"-ast", `Name === "web"`},
+			status: exitError,
+			stderr: "  Name === \"web\"\n         ^\n",
+		},
+		"evaluation error": {
+			args:   []string{`Name < 3`},
+			stdin:  `{"Name": "web"} {"Name": "db"}`,
+			status: exitError,
+			stderr: "bexpr: document 2: 1:1 (0): Name < 3: operator \"Lower\" cannot be used with values of type string\n",
+		},
+		"invalid json": {
+			args:   []string{`Name == "web"`},
+			stdin:  `{"Name": `,
+			status: exitError,
+			stderr: "bexpr: document 1: unexpected EOF\n",
+		},
+		"unknown dialect": {
+			args:   []string{"-dialect", "xpath", `a == 1`},
+			status: exitError,
+			stderr: "bexpr: unknown dialect \"xpath\"\n",
+		},
+		"no expression": {
+			status: exitError,
+			stderr: "Usage: bexpr [flags] expression",
+		},
+		"fmt": {
+			args:   []string{"fmt", `( Name=="web" )`},
+			stdout: `Name == "web"` + "\n",
+		},
+		"fmt width": {
+			args:   []string{"fmt", "-width", "20", `Name == "web" and (Port > 80 or Port < 10)`},
+			stdout: "Name == \"web\"\nand (\n\tPort > 80\n\tor Port < 10\n)\n",
+		},
+		"fmt stdin": {
+			args:   []string{"fmt", "-dialect", "jsonpath"},
+			stdin:  "$.Meta['a']==1\n",
+			stdout: `$.Meta.a == 1` + "\n",
+		},
+		"fmt syntax error": {
+			args:   []string{"fmt", `Name === "web"`},
+			status: exitError,
+			stderr: "  Name === \"web\"\n         ^\n",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+			status := run(tcase.args, strings.NewReader(tcase.stdin), &stdout, &stderr)
+			require.Equal(t, tcase.status, status, stderr.String())
+			require.Equal(t, tcase.stdout, stdout.String())
+			require.Contains(t, stderr.String(), tcase.stderr)
+		})
+	}
+}
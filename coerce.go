@@ -5,7 +5,10 @@ package bexpr
 
 import (
 	"fmt"
+	"reflect"
 	"strconv"
+
+	"github.com/gterranova/go-bexpr/grammar"
 )
 
 // CoerceInt64 conforms to the FieldValueCoercionFn signature
@@ -25,6 +28,45 @@ func CoerceUint64(value string) (interface{}, error) {
 	return uint64(i), err
 }
 
+// coerceInteger coerces the value into an int64 or, when it is above
+// math.MaxInt64, into a uint64, reporting which one it is
+func coerceInteger(value interface{}) (int64, uint64, bool, error) {
+	i, err := CoerceInt64(value)
+	if err == nil {
+		return i, 0, false, nil
+	}
+	if u, uerr := strconv.ParseUint(fmt.Sprintf("%v", value), 0, 64); uerr == nil {
+		return 0, u, true, nil
+	}
+	return 0, 0, false, err
+}
+
+// compareIntegers compares the values coerced into integers over the full
+// range of int64 and uint64. Values which cannot be coerced compare as 0.
+func compareIntegers(first interface{}, second interface{}) int {
+	i1, u1, unsigned1, _ := coerceInteger(first)
+	i2, u2, unsigned2, _ := coerceInteger(second)
+	switch {
+	case unsigned1 && unsigned2:
+		return compareOrdered(u1 < u2, u1 > u2)
+	case unsigned1:
+		return 1
+	case unsigned2:
+		return -1
+	}
+	return compareOrdered(i1 < i2, i1 > i2)
+}
+
+func compareOrdered(lower bool, higher bool) int {
+	switch {
+	case lower:
+		return -1
+	case higher:
+		return 1
+	}
+	return 0
+}
+
 // CoerceBool conforms to the FieldValueCoercionFn signature
 // and can be used to convert the raw string value of
 // an expression into a `bool`
@@ -64,3 +106,107 @@ func CoerceFloat64(value interface{}) (float64, error) {
 	s := fmt.Sprintf("%v", value)
 	return strconv.ParseFloat(s, 64)
 }
+
+// Coercion converts a value into a value of a given value type, overriding the
+// strconv based coercion of the values compared with values of that type, see
+// WithCoercion and WithSelectorCoercion.
+type Coercion func(value interface{}) (interface{}, error)
+
+// selectorCoercion is a coercion scoped to the values found at or below the
+// selectors matching a pattern.
+type selectorCoercion struct {
+	selectorPattern
+	valueType grammar.ValueType
+	fn        Coercion
+}
+
+// coercionType returns the value type of the values of the type
+func coercionType(typ reflect.Type) (grammar.ValueType, bool) {
+	if typ == nil {
+		return grammar.ValueTypeUndefined, false
+	}
+	switch derefType(typ).Kind() {
+	case reflect.Bool:
+		return grammar.ValueTypeBool, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return grammar.ValueTypeInt, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return grammar.ValueTypeUint, true
+	case reflect.Float32:
+		return grammar.ValueTypeFloat32, true
+	case reflect.Float64:
+		return grammar.ValueTypeFloat64, true
+	case reflect.String:
+		return grammar.ValueTypeString, true
+	}
+	return grammar.ValueTypeUndefined, false
+}
+
+// coerceSelector converts the value of the operand with the first selector
+// coercion matching its selector, unless it already has the value type of the
+// coercion. It reports whether a selector coercion matched.
+func coerceSelector(operand *grammar.ExpressionValue, value interface{}, coercions []selectorCoercion) (interface{}, bool, error) {
+	if len(coercions) == 0 || operand == nil || operand.Operator != grammar.MathOpValue || isNull(value) {
+		return value, false, nil
+	}
+	mv, ok := operand.Left.(*grammar.MatchValue)
+	if !ok || mv.Type != grammar.ValueTypeReflect {
+		return value, false, nil
+	}
+	for _, c := range coercions {
+		if !c.matches(mv.Selector.Path) {
+			continue
+		}
+		if typ, ok := coercionType(reflect.TypeOf(value)); ok && typ == c.valueType {
+			return value, true, nil
+		}
+		coerced, err := c.fn(value)
+		if err != nil {
+			return nil, true, fmt.Errorf("error coercing the value of selector %q: %w", mv.Selector, err)
+		}
+		return indirect(coerced), true, nil
+	}
+	return value, false, nil
+}
+
+// coerceTo converts the value compared with values of the type with the
+// coercion registered for the value type of the type, if any. Values of the
+// same class, such as integers and floats, are left to the comparison.
+func coerceTo(typ reflect.Type, value interface{}, coercions map[grammar.ValueType]Coercion) (interface{}, error) {
+	if len(coercions) == 0 || isNull(value) || valueClass(typ) == valueClass(reflect.TypeOf(value)) {
+		return value, nil
+	}
+	target, ok := coercionType(typ)
+	if !ok {
+		return value, nil
+	}
+	fn, ok := coercions[target]
+	if !ok {
+		return value, nil
+	}
+	coerced, err := fn(value)
+	if err != nil {
+		return nil, fmt.Errorf("error coercing %v for comparison with a %s: %w", value, derefType(typ).Kind(), err)
+	}
+	return indirect(coerced), nil
+}
+
+// coercionTarget returns the type the right operand of the operator is
+// compared with: the type of the elements of the collection for "in", and
+// the type of the left operand otherwise.
+func coercionTarget(op grammar.MatchOperator, leftValue interface{}) reflect.Type {
+	typ := reflect.TypeOf(leftValue)
+	switch op {
+	case grammar.MatchIn, grammar.MatchNotIn:
+		if typ == nil {
+			return nil
+		}
+		switch typ.Kind() {
+		case reflect.Map:
+			return typ.Key()
+		case reflect.Slice, reflect.Array:
+			return typ.Elem()
+		}
+	}
+	return typ
+}
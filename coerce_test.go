@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoercion(t *testing.T) {
+	t.Parallel()
+
+	thousands := func(v interface{}) (interface{}, error) {
+		return strconv.ParseInt(strings.ReplaceAll(fmt.Sprint(v), ",", ""), 10, 64)
+	}
+	yesNo := func(v interface{}) (interface{}, error) {
+		switch fmt.Sprint(v) {
+		case "yes":
+			return true, nil
+		case "no":
+			return false, nil
+		}
+		return nil, errors.New("neither yes nor no")
+	}
+
+	datum := map[string]interface{}{
+		"Count":   1000,
+		"Ratio":   0.5,
+		"Enabled": true,
+		"Amount":  "1,500",
+		"Flag":    "yes",
+		"Ports":   []int{80, 1000},
+		"Limits":  map[int]string{1000: "high"},
+		"Meta": map[string]interface{}{
+			"size": "2,000",
+		},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"default": {
+			expression: `Count == "1,000"`,
+			result:     false,
+		},
+		"int literal": {
+			expression: `Count == "1,000"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"int lower": {
+			expression: `Count < "1,001"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"numbers are not coerced": {
+			expression: `Count < 1000.5`,
+			opts: []Option{WithCoercion(grammar.ValueTypeInt, func(interface{}) (interface{}, error) {
+				return nil, errors.New("coerced")
+			})},
+			result: true,
+		},
+		"other types are not coerced": {
+			expression: `Ratio == "0.5"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"bool literal": {
+			expression: `Enabled == "yes"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeBool, yesNo)},
+			result:     true,
+		},
+		"coercion error": {
+			expression: `Enabled == "maybe"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeBool, yesNo)},
+			err:        `1:1 (0): Enabled == "maybe": error coercing maybe for comparison with a bool: neither yes nor no`,
+		},
+		"slice elements": {
+			expression: `"1,000" in Ports`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"map keys": {
+			expression: `Limits contains "1,000"`,
+			opts:       []Option{WithCoercion(grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"selector": {
+			expression: `Amount > 1000`,
+			opts:       []Option{WithSelectorCoercion("Amount", grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"selector pattern": {
+			expression: `Meta.size == 2000`,
+			opts:       []Option{WithSelectorCoercion("Meta.*", grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"selector on the right": {
+			expression: `1500 == Amount`,
+			opts:       []Option{WithSelectorCoercion("Amount", grammar.ValueTypeInt, thousands)},
+			result:     true,
+		},
+		"selector bool": {
+			expression: `Flag == true`,
+			opts:       []Option{WithSelectorCoercion("Flag", grammar.ValueTypeBool, yesNo)},
+			result:     true,
+		},
+		"selector and type": {
+			expression: `Amount == "1,500"`,
+			opts: []Option{
+				WithSelectorCoercion("Amount", grammar.ValueTypeInt, thousands),
+				WithCoercion(grammar.ValueTypeInt, thousands),
+			},
+			result: true,
+		},
+		"selector not matching": {
+			expression: `Amount == "1,500"`,
+			opts:       []Option{WithSelectorCoercion("Flag", grammar.ValueTypeBool, yesNo)},
+			result:     true,
+		},
+		"selector error": {
+			expression: `Amount == 1`,
+			opts:       []Option{WithSelectorCoercion("Amount", grammar.ValueTypeBool, yesNo)},
+			err:        `1:1 (0): Amount == 1: error coercing the value of selector "Amount": neither yes nor no`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
@@ -6,6 +6,8 @@ package bexpr
 import (
 	"flag"
 	"reflect"
+
+	"github.com/gterranova/go-bexpr/grammar"
 )
 
 var benchFull *bool = flag.Bool("bench-full", false, "Run all benchmarks rather than a subset")
@@ -114,3 +116,31 @@ type testNestedTypes struct {
 	Nested testNestedLevel1
 	TopInt int
 }
+
+type testNullableStruct struct {
+	Ptr      *int
+	NilPtr   *int
+	Slice    []string
+	NilSlice []string
+	Map      map[string]interface{}
+	NilMap   map[string]interface{}
+	Iface    interface{}
+	NilIface interface{}
+}
+
+// withoutPositions returns a copy of the AST without the positions of its
+// nodes, so that it compares equal to the ASTs of the same expression
+// written anywhere else
+func withoutPositions(ast grammar.Expression) grammar.Expression {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: withoutPositions(node.Operand)}
+	case *grammar.BinaryExpression:
+		return &grammar.BinaryExpression{Operator: node.Operator, Left: withoutPositions(node.Left), Right: withoutPositions(node.Right)}
+	case *grammar.LetExpression:
+		return &grammar.LetExpression{Name: node.Name, Value: node.Value, Body: withoutPositions(node.Body)}
+	case *grammar.MatchExpression:
+		return &grammar.MatchExpression{Operator: node.Operator, Left: node.Left, Right: node.Right}
+	}
+	return ast
+}
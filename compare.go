@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Comparer is implemented by the types compared in their own way by ==, !=,
+// <, <=, > and >=, such as money amounts, versions or ULIDs, rather than
+// coerced into a bool, a number or a string. The comparer is consulted
+// whichever side of the operator the value is on, before any coercion, and
+// takes precedence over strict types.
+type Comparer interface {
+	// Compare returns a negative number when the value is lower than other,
+	// zero when they are equal and a positive number when it is higher.
+	// other is the value on the other side of the operator, such as a
+	// string or number literal or another value of the type. An error fails
+	// the evaluation.
+	Compare(other interface{}) (int, error)
+}
+
+// isComparisonOperator reports whether the operator is one of the operators
+// comparing values with a Comparer
+func isComparisonOperator(op grammar.MatchOperator) bool {
+	switch op {
+	case grammar.MatchEqual, grammar.MatchNotEqual, grammar.MatchLower, grammar.MatchLowerOrEqual,
+		grammar.MatchHigher, grammar.MatchHigherOrEqual:
+		return true
+	}
+	return false
+}
+
+var comparerTyp = reflect.TypeOf((*Comparer)(nil)).Elem()
+
+// isComparer reports whether the values of the type, or pointers to them,
+// implement Comparer, or are IP addresses or arbitrary precision numbers
+func isComparer(typ reflect.Type) bool {
+	return isIPType(typ) || isDecimalType(typ) || typ.Implements(comparerTyp) || (typ.Kind() != reflect.Ptr && reflect.PtrTo(typ).Implements(comparerTyp))
+}
+
+// asComparer returns the value as a Comparer, taking the address of a copy of
+// it when only pointers to its type implement Comparer. IP addresses and
+// arbitrary precision numbers are compared by ipComparer and
+// decimalComparer.
+func asComparer(value interface{}) (Comparer, bool) {
+	if c, ok := value.(Comparer); ok {
+		return c, true
+	}
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || v.Kind() == reflect.Ptr || !reflect.PtrTo(v.Type()).Implements(comparerTyp) {
+		if ip, ok := asIP(value); ok {
+			return ipComparer(ip), true
+		}
+		if isDecimal(value) {
+			if r, ok := asRat(value); ok {
+				return decimalComparer{r: r}, true
+			}
+		}
+		return nil, false
+	}
+	ptr := reflect.New(v.Type())
+	ptr.Elem().Set(v)
+	return ptr.Interface().(Comparer), true
+}
+
+// doMatchCompare applies the comparison operator with the Comparer of either
+// operand, reporting whether one of them is a Comparer. The left operand wins
+// when both are.
+func doMatchCompare(op grammar.MatchOperator, leftValue interface{}, rightValue interface{}) (bool, bool, error) {
+	if !isComparisonOperator(op) || isNull(leftValue) || isNull(rightValue) {
+		// null is compared by ==, != as usual
+		return false, false, nil
+	}
+
+	var cmp int
+	var err error
+	if c, ok := asComparer(leftValue); ok {
+		cmp, err = c.Compare(rightValue)
+	} else if c, ok := asComparer(rightValue); ok {
+		cmp, err = c.Compare(leftValue)
+		cmp = -cmp
+	} else {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, true, fmt.Errorf("error comparing %v and %v: %w", comparedValue(leftValue), comparedValue(rightValue), err)
+	}
+
+	switch op {
+	case grammar.MatchEqual:
+		return cmp == 0, true, nil
+	case grammar.MatchNotEqual:
+		return cmp != 0, true, nil
+	case grammar.MatchLower:
+		return cmp < 0, true, nil
+	case grammar.MatchLowerOrEqual:
+		return cmp <= 0, true, nil
+	case grammar.MatchHigher:
+		return cmp > 0, true, nil
+	default:
+		return cmp >= 0, true, nil
+	}
+}
+
+// comparedValue returns the value to report in comparison errors
+func comparedValue(value interface{}) interface{} {
+	if isDecimal(value) {
+		if r, ok := asRat(value); ok {
+			return r.RatString()
+		}
+	}
+	return value
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+// testVersion compares dotted versions part by part, with a pointer receiver
+type testVersion struct {
+	parts []int
+}
+
+func parseTestVersion(s string) (testVersion, error) {
+	var v testVersion
+	for _, part := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return testVersion{}, fmt.Errorf("invalid version %q", s)
+		}
+		v.parts = append(v.parts, n)
+	}
+	return v, nil
+}
+
+func (v *testVersion) Compare(other interface{}) (int, error) {
+	var o testVersion
+	switch other := other.(type) {
+	case testVersion:
+		o = other
+	case string:
+		var err error
+		if o, err = parseTestVersion(other); err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("cannot compare a version with %T", other)
+	}
+	for i := 0; i < len(v.parts) || i < len(o.parts); i++ {
+		var a, b int
+		if i < len(v.parts) {
+			a = v.parts[i]
+		}
+		if i < len(o.parts) {
+			b = o.parts[i]
+		}
+		if a != b {
+			return a - b, nil
+		}
+	}
+	return 0, nil
+}
+
+// testMoney is an amount in cents, compared with amounts written "12.34"
+type testMoney int64
+
+func (m testMoney) Compare(other interface{}) (int, error) {
+	var cents int64
+	switch other := other.(type) {
+	case testMoney:
+		cents = int64(other)
+	case string:
+		f, err := strconv.ParseFloat(other, 64)
+		if err != nil {
+			return 0, err
+		}
+		cents = int64(f*100 + 0.5)
+	default:
+		return 0, fmt.Errorf("cannot compare money with %T", other)
+	}
+	switch {
+	case int64(m) < cents:
+		return -1, nil
+	case int64(m) > cents:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func TestComparer(t *testing.T) {
+	t.Parallel()
+
+	v120, err := parseTestVersion("1.2.0")
+	require.NoError(t, err)
+	v1100, err := parseTestVersion("1.10.0")
+	require.NoError(t, err)
+
+	datum := map[string]interface{}{
+		"Version": v1100,
+		"Minimum": v120,
+		"Pointer": &v120,
+		"Price":   testMoney(1250),
+		"Missing": (*testVersion)(nil),
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equal":                 {expression: `Version == "1.10"`, result: true},
+		"not equal":             {expression: `Version != "1.10.0"`, result: false},
+		"lower":                 {expression: `Version < "1.9"`, result: false},
+		"higher":                {expression: `Version > "1.9"`, result: true},
+		"lower or equal":        {expression: `Version <= "1.10.0"`, result: true},
+		"higher or equal":       {expression: `Version >= "1.11"`, result: false},
+		"both comparers":        {expression: `Minimum < Version`, result: true},
+		"pointer":               {expression: `Pointer == "1.2"`, result: true},
+		"comparer on the right": {expression: `"1.9" < Version`, result: true},
+		"value receiver":        {expression: `Price >= "12.50"`, result: true},
+		"value receiver lower":  {expression: `Price < "12.49"`, result: false},
+		"null":                  {expression: `Missing == null`, result: true},
+		"nil is not present":    {expression: `Missing > "1.0"`, result: false},
+		"strict types":          {expression: `Version > "1.9"`, opts: []Option{WithStrictTypes()}, result: true},
+		"compare error": {
+			expression: `Version == "latest"`,
+			err:        `1:1 (0): Version == "latest": error comparing {[1 10 0]} and latest: invalid version "latest"`,
+		},
+		"unsupported operator": {
+			expression: `Version matches "1"`,
+			err:        `1:1 (0): Version matches "1": operator "Matches" cannot be used with values of type bexpr.testVersion`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestSupportsOperator_Comparer(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, SupportsOperator(grammar.MatchLower, reflect.TypeOf(testVersion{})))
+	require.True(t, SupportsOperator(grammar.MatchEqual, reflect.TypeOf(&testVersion{})))
+	require.True(t, SupportsOperator(grammar.MatchHigher, reflect.TypeOf(testMoney(0))))
+	require.False(t, SupportsOperator(grammar.MatchIn, reflect.TypeOf(testVersion{})))
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// compiledNode evaluates a node of the expression the way evaluateContext
+// does, its operator being dispatched and its operands compiled once when the
+// evaluator is created rather than at each evaluation
+type compiledNode func(call compiledCall) (interface{}, error)
+
+// compiledCall is an evaluation of a compiled expression
+type compiledCall struct {
+	ctx   context.Context
+	datum interface{}
+	opt   []Option
+	// fast is set when the match expressions can be evaluated with their
+	// fastExpression: neither the evaluator nor the evaluation have options
+	// changing how values are resolved or compared
+	fast     bool
+	fastData fastDatum
+}
+
+// compile compiles the expression of the evaluator, into a fastExpression
+// when the whole expression can be evaluated without allocating, and into a
+// tree of compiledNodes evaluating the match expressions with their own
+// fastExpression when they have one
+func (eval *Evaluator) compile() {
+	eval.fast = eval.compileFast()
+	c := &compiler{tagName: eval.fastTagName(), fast: eval.fastOptions()}
+	eval.compiled = c.compile(eval.ast)
+}
+
+// compiler compiles the nodes of an expression
+type compiler struct {
+	tagName string
+	// fast is whether the match expressions are compiled into
+	// fastExpressions
+	fast bool
+	// bound are the names bound by the let expressions in scope, which the
+	// fastExpressions do not resolve
+	bound map[string]bool
+}
+
+func (c *compiler) compile(ast interface{}) compiledNode {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		if node.Operator == grammar.UnaryOpNot {
+			return compiledNot(c.compile(node.Operand))
+		}
+	case *grammar.BinaryExpression:
+		switch node.Operator {
+		case grammar.BinaryOpAnd:
+			return compiledAnd(c.compile(node.Left), c.compile(node.Right))
+		case grammar.BinaryOpOr:
+			return compiledOr(c.compile(node.Left), c.compile(node.Right))
+		}
+	case *grammar.LetExpression:
+		bound := map[string]bool{node.Name: true}
+		for name := range c.bound {
+			bound[name] = true
+		}
+		body := (&compiler{tagName: c.tagName, fast: c.fast, bound: bound}).compile(node.Body)
+		return compiledLet(node, body)
+	case *grammar.MatchExpression:
+		var fast fastExpression
+		if c.fast && !c.isBound(node) {
+			fast = compileFastMatch(node, c.tagName)
+		}
+		return compiledMatch(node, fast)
+	}
+	// the other nodes, such as the values evaluated as booleans, are
+	// evaluated as they are
+	return func(call compiledCall) (interface{}, error) {
+		return evaluateContext(call.ctx, ast, call.datum, call.opt...)
+	}
+}
+
+// isBound reports whether the left operand of the match expression selects
+// a name bound by a let expression
+func (c *compiler) isBound(node *grammar.MatchExpression) bool {
+	left := operandValue(node.Left)
+	return left != nil && left.Type == grammar.ValueTypeReflect && !left.Selector.Anchored &&
+		len(left.Selector.Path) > 0 && c.bound[left.Selector.Path[0]]
+}
+
+func compiledNot(operand compiledNode) compiledNode {
+	return func(call compiledCall) (interface{}, error) {
+		if err := call.ctx.Err(); err != nil {
+			return false, err
+		}
+		result, err := operand(call)
+		if err != nil {
+			return false, err
+		}
+		return !result.(bool), nil
+	}
+}
+
+func compiledAnd(left, right compiledNode) compiledNode {
+	return func(call compiledCall) (interface{}, error) {
+		if err := call.ctx.Err(); err != nil {
+			return false, err
+		}
+		result, err := left(call)
+		if err != nil || !result.(bool) {
+			return result, err
+		}
+		return right(call)
+	}
+}
+
+func compiledOr(left, right compiledNode) compiledNode {
+	return func(call compiledCall) (interface{}, error) {
+		if err := call.ctx.Err(); err != nil {
+			return false, err
+		}
+		result, err := left(call)
+		if err != nil || result.(bool) {
+			return result, err
+		}
+		return right(call)
+	}
+}
+
+func compiledLet(node *grammar.LetExpression, body compiledNode) compiledNode {
+	return func(call compiledCall) (interface{}, error) {
+		if err := call.ctx.Err(); err != nil {
+			return false, err
+		}
+		value, err := getExprValue(node.Value, call.datum, call.opt...)
+		if err != nil {
+			return false, letError(node, err)
+		}
+		call.opt = append(call.opt[:len(call.opt):len(call.opt)], withBinding(node.Name, value))
+		return body(call)
+	}
+}
+
+func compiledMatch(node *grammar.MatchExpression, fast fastExpression) compiledNode {
+	return func(call compiledCall) (interface{}, error) {
+		if err := call.ctx.Err(); err != nil {
+			return false, err
+		}
+		if fast != nil && call.fast {
+			if result, ok := fast.evaluate(call.fastData); ok {
+				return result, nil
+			}
+		}
+		result, err := evaluateMatchExpression(node, call.datum, call.opt...)
+		return result, matchError(node, err)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompiled(t *testing.T) {
+	t.Parallel()
+
+	datum := &fastRecord{
+		Name:   "web",
+		Port:   80,
+		Meta:   map[string]string{"env": "prod"},
+		Labels: map[string]interface{}{"tier": "front"},
+		Tags:   []string{"x", "y"},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		datum      interface{}
+	}
+
+	tests := map[string]testCase{
+		"fast":                {expression: `Name == "web" and Port > 80`},
+		"fast and slow":       {expression: `Name == "web" and "x" in Tags`},
+		"slow or fast":        {expression: `Name matches "^w" or Port == 80`},
+		"not":                 {expression: `not (Meta.env == "prod" and Port + 1 > 80)`},
+		"value":               {expression: `Name == "web" and Enabled`},
+		"let":                 {expression: `let p = Port in p == 80 and Name == "web"`},
+		"let shadowing":       {expression: `let Name = Port in Name == 80`},
+		"nested let":          {expression: `let p = Port in let n = Name in p == 80 and n == "web"`},
+		"let error":           {expression: `let p = Missing in p == 80`},
+		"match error":         {expression: `Name == "web" and Missing == 1`},
+		"fast falls back":     {expression: `Labels.tier == "front" and Port < 1024`, datum: map[string]interface{}{"Labels": map[string]interface{}{"tier": "front"}, "Port": "80"}},
+		"options":             {expression: `Name == "web" and Port > 8`, opts: []Option{WithStrictTypes()}},
+		"map":                 {expression: `Meta.env == "prod" or Port == 80`, datum: map[string]interface{}{"Meta": map[string]string{"env": "prod"}}},
+		"selector source":     {expression: `app == "web" and tier != "db"`, datum: NewKubernetesLabelsSource(map[string]string{"app": "web"})},
+		"partially supported": {expression: `Name != "db" and Port >= 80 and Meta.env in "production"`},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.datum == nil {
+				tc.datum = datum
+			}
+			eval, err := CreateEvaluator(tc.expression, tc.opts...)
+			require.NoError(t, err)
+
+			expected, expectedErr := evaluateContext(context.Background(), eval.ast, tc.datum, eval.evaluateOpts()...)
+			result, err := eval.Evaluate(tc.datum)
+			require.Equal(t, expectedErr, err)
+			require.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestCompiled_Context(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Name == "web" and "x" in Tags`)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = eval.EvaluateContext(ctx, &fastRecord{Name: "web"})
+	require.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SplitKeyValues splits a list of KEY=VALUE strings, such as the environment
+// of a container or the labels given on a command line, into a map. Strings
+// without "=" map to the empty string, and later keys override earlier ones,
+// the way processes read their environment.
+func SplitKeyValues(list []string) map[string]string {
+	values := make(map[string]string, len(list))
+	for _, entry := range list {
+		key, value := entry, ""
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			key, value = entry[:i], entry[i+1:]
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// KeyValueHookFn converts lists of KEY=VALUE strings, []string values or
+// []interface{} values holding strings only, into maps with SplitKeyValues so
+// that selectors address their values by key. Other values are left
+// untouched. As any list of strings is converted, the hook is meant to be
+// scoped to the lists with WithKeyValueLists.
+func KeyValueHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if e.Kind() != reflect.Slice || !e.CanInterface() {
+		return v
+	}
+	switch list := e.Interface().(type) {
+	case []string:
+		return reflect.ValueOf(SplitKeyValues(list))
+	case []interface{}:
+		strs := make([]string, len(list))
+		for i, item := range list {
+			str, ok := item.(string)
+			if !ok {
+				return v
+			}
+			strs[i] = str
+		}
+		return reflect.ValueOf(SplitKeyValues(strs))
+	}
+	return v
+}
+
+// WithKeyValueLists converts the lists of KEY=VALUE strings found at the
+// selectors matching the patterns into maps, see KeyValueHookFn and
+// WithSelectorHook, for the container-like objects of Docker, OCI or
+// Kubernetes tooling, whose labels and annotations are maps but whose
+// environments are lists:
+//
+//	eval, err := bexpr.CreateEvaluator(
+//		`Config.Env.APP_ENV == "prod" and "DEBUG" not in Config.Env and
+//		 Config.Labels["org.opencontainers.image.vendor"] == "acme"`,
+//		bexpr.WithKeyValueLists("Config.Env"),
+//	)
+//
+// Keys are matched with "in", and their values selected like the values of
+// any other map, with brackets for the keys holding dots.
+func WithKeyValueLists(patterns ...string) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			WithSelectorHook(pattern, KeyValueHookFn)(o)
+		}
+	}
+}
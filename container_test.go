@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testContainerConfig struct {
+	Image  string
+	Env    []string
+	Labels map[string]string
+}
+
+type testContainer struct {
+	Name        string
+	Config      testContainerConfig
+	Annotations map[string]string
+	Args        []string
+}
+
+func TestSplitKeyValues(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, map[string]string{
+		"PATH":  "/usr/bin",
+		"OPTS":  "a=b",
+		"EMPTY": "",
+		"UNSET": "",
+		"DUP":   "2",
+	}, SplitKeyValues([]string{"PATH=/usr/bin", "OPTS=a=b", "EMPTY=", "UNSET", "DUP=1", "DUP=2"}))
+}
+
+func TestWithKeyValueLists(t *testing.T) {
+	t.Parallel()
+
+	container := testContainer{
+		Name: "web",
+		Config: testContainerConfig{
+			Image: "acme/web:1.2",
+			Env:   []string{"APP_ENV=prod", "PATH=/usr/local/bin:/usr/bin", "FLAGS=--a=1"},
+			Labels: map[string]string{
+				"org.opencontainers.image.vendor":  "acme",
+				"org.opencontainers.image.version": "1.2",
+			},
+		},
+		Annotations: map[string]string{"kubernetes.io/psp": "restricted"},
+		Args:        []string{"serve", "--port=80"},
+	}
+
+	var decoded interface{}
+	raw, err := json.Marshal(container)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+
+	type testCase struct {
+		expression string
+		result     bool
+	}
+
+	tests := map[string]testCase{
+		"value":               {expression: `Config.Env.APP_ENV == "prod"`, result: true},
+		"value with equals":   {expression: `Config.Env.FLAGS == "--a=1"`, result: true},
+		"key present":         {expression: `"PATH" in Config.Env`, result: true},
+		"key absent":          {expression: `"DEBUG" not in Config.Env and Config.Env.DEBUG is empty`, result: true},
+		"labels":              {expression: `Config.Labels["org.opencontainers.image.vendor"] == "acme"`, result: true},
+		"annotations":         {expression: `Annotations["kubernetes.io/psp"] == "restricted"`, result: true},
+		"lists out of scope":  {expression: `"serve" in Args`, result: true},
+		"strings out of list": {expression: `Config.Image == "acme/web:1.2"`, result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, WithKeyValueLists("Config.Env"))
+			require.NoError(t, err)
+
+			for _, datum := range []interface{}{container, decoded} {
+				result, err := eval.Evaluate(datum)
+				require.NoError(t, err)
+				require.Equal(t, tcase.result, result)
+			}
+		})
+	}
+}
+
+func TestKeyValueHookFn_MixedList(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Items contains 1`, WithKeyValueLists("Items"))
+	require.NoError(t, err)
+
+	result, err := eval.Evaluate(map[string]interface{}{"Items": []interface{}{"a=b", 1}})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+}
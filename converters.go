@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+)
+
+// maxValueConversions bounds the number of converters applied to a single
+// value, for converters returning values of their own type such as nested
+// CBOR tags.
+const maxValueConversions = 16
+
+// ValueConverter converts a value of a decoder-specific wrapper type, such as
+// cbor.Tag or bson's primitive.ObjectID, into a value that can be compared
+// against literals or looked into by selectors.
+type ValueConverter func(value interface{}) interface{}
+
+type valueConverter struct {
+	typ reflect.Type
+	fn  ValueConverter
+}
+
+// WithValueConverter registers the converter of the values of the type of
+// sample. When sample is a nil pointer to an interface type, the converter is
+// used for the values implementing the interface instead:
+//
+//	bexpr.WithValueConverter(cbor.Tag{}, func(v interface{}) interface{} {
+//		return v.(cbor.Tag).Content
+//	})
+//	bexpr.WithValueConverter((*interface{ Hex() string })(nil), func(v interface{}) interface{} {
+//		return v.(interface{ Hex() string }).Hex()
+//	})
+//
+// Converters run on the values found at each part of the selector, before the
+// hooks set with WithHookFn and WithSelectorHook, and again on the values
+// they return until no converter applies. The converter registered first
+// wins when several apply to a value.
+func WithValueConverter(sample interface{}, fn ValueConverter) Option {
+	return func(o *options) {
+		typ := reflect.TypeOf(sample)
+		if typ == nil || fn == nil {
+			return
+		}
+		if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+			typ = typ.Elem()
+		}
+		o.withValueConverters = append(o.withValueConverters, valueConverter{typ: typ, fn: fn})
+	}
+}
+
+// findValueConverter returns the converter of the values of the type
+func findValueConverter(converters []valueConverter, typ reflect.Type) ValueConverter {
+	for _, c := range converters {
+		if c.typ == typ || (c.typ.Kind() == reflect.Interface && typ.Implements(c.typ)) {
+			return c.fn
+		}
+	}
+	return nil
+}
+
+// convertValue applies the converters to the value
+func convertValue(converters []valueConverter, value interface{}) interface{} {
+	for i := 0; i < maxValueConversions && value != nil; i++ {
+		fn := findValueConverter(converters, reflect.TypeOf(value))
+		if fn == nil {
+			break
+		}
+		value = fn(value)
+	}
+	return value
+}
+
+// valueConverterHookFn returns the hook applying the converters to the values
+// found at each part of a selector.
+func valueConverterHookFn(converters []valueConverter) ValueTransformationHookFn {
+	if len(converters) == 0 {
+		return nil
+	}
+	return func(v reflect.Value) reflect.Value {
+		e := hookElem(v)
+		if !e.IsValid() || !e.CanInterface() || findValueConverter(converters, e.Type()) == nil {
+			return v
+		}
+		converted := convertValue(converters, e.Interface())
+		if converted == nil {
+			return reflect.Zero(interfaceTyp)
+		}
+		return reflect.ValueOf(converted)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testTag has the shape of cbor.Tag
+type testTag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// testObjectID has the shape of bson's primitive.ObjectID
+type testObjectID [12]byte
+
+func (id testObjectID) Hex() string {
+	return hex.EncodeToString(id[:])
+}
+
+// testDateTime has the shape of bson's primitive.DateTime
+type testDateTime int64
+
+func (d testDateTime) Time() time.Time {
+	return time.Unix(int64(d)/1000, 0).UTC()
+}
+
+func TestValueConverters(t *testing.T) {
+	t.Parallel()
+
+	tagContent := WithValueConverter(testTag{}, func(v interface{}) interface{} {
+		return v.(testTag).Content
+	})
+	hexString := WithValueConverter((*interface{ Hex() string })(nil), func(v interface{}) interface{} {
+		return v.(interface{ Hex() string }).Hex()
+	})
+	upper := WithHookFn(func(v reflect.Value) reflect.Value {
+		if s, ok := v.Interface().(string); ok {
+			return reflect.ValueOf(strings.ToUpper(s))
+		}
+		return v
+	})
+	unixTime := WithValueConverter(testDateTime(0), func(v interface{}) interface{} {
+		return v.(testDateTime).Time().Unix()
+	})
+
+	datum := map[string]interface{}{
+		"id":      testObjectID{0xde, 0xad, 0xbe, 0xef},
+		"created": testDateTime(1600000000000),
+		"port":    testTag{Number: 2, Content: int64(8080)},
+		"nested":  testTag{Number: 1, Content: testTag{Number: 2, Content: "web"}},
+		"meta": testTag{Number: 259, Content: map[string]interface{}{
+			"env": testTag{Number: 1, Content: "prod"},
+		}},
+		"empty": testTag{Number: 1},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"unconverted": {
+			expression: `port == 8080`,
+			err:        `operator "Equal" cannot be used with values of type bexpr.testTag`,
+		},
+		"tag content": {
+			expression: `port == 8080`,
+			opts:       []Option{tagContent},
+			result:     true,
+		},
+		"nested tags": {
+			expression: `nested == "web"`,
+			opts:       []Option{tagContent},
+			result:     true,
+		},
+		"selector into tag": {
+			expression: `meta.env == "prod"`,
+			opts:       []Option{tagContent},
+			result:     true,
+		},
+		"nil content": {
+			expression: `empty is null`,
+			opts:       []Option{tagContent},
+			result:     true,
+		},
+		"interface converter": {
+			expression: `id == "deadbeef0000000000000000"`,
+			opts:       []Option{hexString},
+			result:     true,
+		},
+		"date time": {
+			expression: `created > 1500000000`,
+			opts:       []Option{unixTime},
+			result:     true,
+		},
+		"before hooks": {
+			expression: `nested == "WEB"`,
+			opts:       []Option{tagContent, upper},
+			result:     true,
+		},
+		"selector source": {
+			expression: `port == 8080`,
+			datum: &testColumns{columns: map[string][]interface{}{
+				"port": {testTag{Number: 2, Content: int64(8080)}},
+			}},
+			opts:   []Option{tagContent},
+			result: true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			var match interface{}
+			if tcase.datum != nil {
+				match, err = eval.Evaluate(tcase.datum)
+			} else {
+				match, err = eval.Evaluate(datum)
+			}
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// ctyMethods are the methods of cty.Value used to read values. cty values
+// are read through reflection, so no dependency on cty is needed.
+var ctyMethods = []string{"IsKnown", "IsWhollyKnown", "IsNull", "Type", "AsString", "AsBigFloat", "True", "ElementIterator"}
+
+// ctySource is the SelectorSource of a cty value
+type ctySource struct {
+	value ctyValue
+}
+
+// NewCtySource returns the SelectorSource of a cty.Value of the go-cty module,
+// such as the values of Terraform plans and states, to be evaluated instead of
+// the value:
+//
+//	src, err := bexpr.NewCtySource(val)
+//	if err != nil {
+//		return err
+//	}
+//	match, err := eval.Evaluate(src)
+//
+// Selectors look up the attributes of objects, the keys of maps and the
+// indexes of lists, tuples and sets. Unknown values, and values holding
+// unknown values, are handled like missing map keys, or replaced by the value
+// set with WithUnknownValue. Null values resolve to null, and the values of
+// the attributes of null objects are missing. Marks, such as the sensitive
+// mark, are removed.
+func NewCtySource(value interface{}) (SelectorSource, error) {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		return nil, fmt.Errorf("value is not a cty value")
+	}
+	for _, name := range ctyMethods {
+		if !v.MethodByName(name).IsValid() {
+			return nil, fmt.Errorf("value of type %s is not a cty value", v.Type())
+		}
+	}
+	if unmark := v.MethodByName("UnmarkDeep"); unmark.IsValid() {
+		v = unmark.Call(nil)[0]
+	}
+	return ctySource{value: ctyValue{v}}, nil
+}
+
+func (s ctySource) GetPath(path []string) (interface{}, bool, error) {
+	value := s.value
+	for _, part := range path {
+		if !value.is("IsKnown") || value.is("IsNull") {
+			return nil, false, nil
+		}
+		next, ok := value.element(part)
+		if !ok {
+			return nil, false, nil
+		}
+		value = next
+	}
+	if !value.is("IsWhollyKnown") {
+		return nil, false, nil
+	}
+	converted, err := value.convert()
+	if err != nil {
+		return nil, false, err
+	}
+	return converted, true, nil
+}
+
+// ctyValue calls the methods of a cty value through reflection
+type ctyValue struct {
+	v reflect.Value
+}
+
+func (c ctyValue) call(name string) []reflect.Value {
+	return c.v.MethodByName(name).Call(nil)
+}
+
+func (c ctyValue) is(name string) bool {
+	return c.call(name)[0].Bool()
+}
+
+// typeIs calls the method of the type of the value
+func (c ctyValue) typeIs(name string) bool {
+	method := c.call("Type")[0].MethodByName(name)
+	return method.IsValid() && method.Call(nil)[0].Bool()
+}
+
+func (c ctyValue) typeName() string {
+	return c.call("Type")[0].MethodByName("FriendlyName").Call(nil)[0].String()
+}
+
+// keyed reports whether the elements of the value are keyed by strings
+func (c ctyValue) keyed() bool {
+	return c.typeIs("IsObjectType") || c.typeIs("IsMapType")
+}
+
+// elements calls the function with the key and value of each element of the
+// value until it returns false.
+func (c ctyValue) elements(fn func(key, value ctyValue) bool) {
+	iter := c.call("ElementIterator")[0]
+	next, element := iter.MethodByName("Next"), iter.MethodByName("Element")
+	for next.Call(nil)[0].Bool() {
+		kv := element.Call(nil)
+		if !fn(ctyValue{kv[0]}, ctyValue{kv[1]}) {
+			return
+		}
+	}
+}
+
+// element returns the element of the value at the selector part
+func (c ctyValue) element(part string) (ctyValue, bool) {
+	if c.typeIs("IsPrimitiveType") {
+		return ctyValue{}, false
+	}
+
+	var found ctyValue
+	ok := false
+	if c.keyed() {
+		c.elements(func(key, value ctyValue) bool {
+			if key.call("AsString")[0].String() == part {
+				found, ok = value, true
+			}
+			return !ok
+		})
+		return found, ok
+	}
+
+	idx, err := strconv.Atoi(part)
+	if err != nil || idx < 0 {
+		return ctyValue{}, false
+	}
+	i := 0
+	c.elements(func(_, value ctyValue) bool {
+		if i == idx {
+			found, ok = value, true
+		}
+		i++
+		return !ok
+	})
+	return found, ok
+}
+
+// convert returns the Go value of a wholly known value
+func (c ctyValue) convert() (interface{}, error) {
+	if c.is("IsNull") {
+		return nil, nil
+	}
+
+	if c.typeIs("IsPrimitiveType") {
+		switch name := c.typeName(); name {
+		case "string":
+			return c.call("AsString")[0].String(), nil
+		case "bool":
+			return c.is("True"), nil
+		case "number":
+			f := c.call("AsBigFloat")[0].Interface().(*big.Float)
+			if i, accuracy := f.Int64(); accuracy == big.Exact {
+				return i, nil
+			}
+			value, _ := f.Float64()
+			return value, nil
+		default:
+			return nil, fmt.Errorf("cannot convert cty value of type %s", name)
+		}
+	}
+
+	var err error
+	if c.keyed() {
+		result := make(map[string]interface{})
+		c.elements(func(key, value ctyValue) bool {
+			result[key.call("AsString")[0].String()], err = value.convert()
+			return err == nil
+		})
+		return result, err
+	}
+	if c.v.MethodByName("CanIterateElements").IsValid() && !c.is("CanIterateElements") {
+		return nil, fmt.Errorf("cannot convert cty value of type %s", c.typeName())
+	}
+	result := []interface{}{}
+	c.elements(func(_, value ctyValue) bool {
+		var converted interface{}
+		converted, err = value.convert()
+		result = append(result, converted)
+		return err == nil
+	})
+	return result, err
+}
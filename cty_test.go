@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testCtyType and testCty have the methods of cty.Type and cty.Value read by
+// the cty source.
+type testCtyType string
+
+func (t testCtyType) FriendlyName() string { return string(t) }
+func (t testCtyType) IsPrimitiveType() bool {
+	return t == "string" || t == "number" || t == "bool"
+}
+func (t testCtyType) IsObjectType() bool { return t == "object" }
+func (t testCtyType) IsMapType() bool    { return t == "map of string" }
+
+type testCty struct {
+	typ     testCtyType
+	unknown bool
+	null    bool
+	str     string
+	num     float64
+	b       bool
+	keys    []string
+	elems   []testCty
+}
+
+func (v testCty) IsKnown() bool { return !v.unknown }
+func (v testCty) IsNull() bool  { return v.null }
+func (v testCty) IsWhollyKnown() bool {
+	if v.unknown {
+		return false
+	}
+	for _, elem := range v.elems {
+		if !elem.IsWhollyKnown() {
+			return false
+		}
+	}
+	return true
+}
+func (v testCty) Type() testCtyType         { return v.typ }
+func (v testCty) AsString() string          { return v.str }
+func (v testCty) AsBigFloat() *big.Float    { return big.NewFloat(v.num) }
+func (v testCty) True() bool                { return v.b }
+func (v testCty) CanIterateElements() bool  { return v.typ != "capsule" }
+func (v testCty) ElementIterator() *ctyIter { return &ctyIter{value: v, idx: -1} }
+
+type ctyIter struct {
+	value testCty
+	idx   int
+}
+
+func (it *ctyIter) Next() bool {
+	it.idx++
+	return it.idx < len(it.value.elems)
+}
+
+func (it *ctyIter) Element() (testCty, testCty) {
+	if it.value.keys != nil {
+		return ctyString(it.value.keys[it.idx]), it.value.elems[it.idx]
+	}
+	return ctyNumber(float64(it.idx)), it.value.elems[it.idx]
+}
+
+func ctyString(s string) testCty       { return testCty{typ: "string", str: s} }
+func ctyNumber(n float64) testCty      { return testCty{typ: "number", num: n} }
+func ctyBool(b bool) testCty           { return testCty{typ: "bool", b: b} }
+func ctyList(elems ...testCty) testCty { return testCty{typ: "list of string", elems: elems} }
+
+func ctyObject(kvs ...interface{}) testCty {
+	v := testCty{typ: "object", keys: []string{}}
+	for i := 0; i < len(kvs); i += 2 {
+		v.keys = append(v.keys, kvs[i].(string))
+		v.elems = append(v.elems, kvs[i+1].(testCty))
+	}
+	return v
+}
+
+func TestCtySource(t *testing.T) {
+	t.Parallel()
+
+	// the shape of a planned resource change
+	tags := ctyObject("env", ctyString("prod"), "team", ctyString("web"))
+	tags.typ = "map of string"
+	value := ctyObject(
+		"type", ctyString("aws_instance"),
+		"count", ctyNumber(3),
+		"ratio", ctyNumber(0.5),
+		"enabled", ctyBool(true),
+		"id", testCty{typ: "string", unknown: true},
+		"tags", tags,
+		"ports", ctyList(ctyNumber(80), ctyNumber(443)),
+		"zones", ctyList(ctyString("a"), testCty{typ: "string", unknown: true}),
+		"description", testCty{typ: "string", null: true},
+		"network", testCty{typ: "object", null: true},
+		"handle", testCty{typ: "capsule"},
+	)
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"string": {
+			expression: `type == "aws_instance"`,
+			result:     true,
+		},
+		"integer": {
+			expression: `count == 3 and count > 2`,
+			result:     true,
+		},
+		"float": {
+			expression: `ratio < 1`,
+			result:     true,
+		},
+		"bool": {
+			expression: `enabled == true`,
+			result:     true,
+		},
+		"map": {
+			expression: `tags.env == "prod" and "team" in tags`,
+			result:     true,
+		},
+		"list": {
+			expression: `443 in ports and ports.0 == 80`,
+			result:     true,
+		},
+		"index out of range": {
+			expression: `ports.2 == 80`,
+			result:     false,
+		},
+		"missing attribute": {
+			expression: `missing != "x"`,
+			result:     true,
+		},
+		"unknown": {
+			expression: `id == "i-123"`,
+			result:     false,
+		},
+		"unknown negated": {
+			expression: `id != "i-123"`,
+			result:     true,
+		},
+		"unknown value option": {
+			expression: `id == "unknown"`,
+			opts:       []Option{WithUnknownValue("unknown")},
+			result:     true,
+		},
+		"partially unknown": {
+			expression: `zones is empty`,
+			result:     true,
+		},
+		"known element of partially unknown": {
+			expression: `zones.0 == "a"`,
+			result:     true,
+		},
+		"null": {
+			expression: `description is null`,
+			result:     true,
+		},
+		"attribute of null": {
+			expression: `network.cidr is null`,
+			result:     true,
+		},
+		"unsupported type": {
+			expression: `handle is null`,
+			err:        "cannot convert cty value of type capsule",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			src, err := NewCtySource(value)
+			require.NoError(t, err)
+
+			match, err := eval.Evaluate(src)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+
+	t.Run("not a cty value", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCtySource(map[string]interface{}{})
+		require.EqualError(t, err, "value of type map[string]interface {} is not a cty value")
+		_, err = NewCtySource(nil)
+		require.EqualError(t, err, "value is not a cty value")
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+var (
+	bigRatTyp   = reflect.TypeOf(big.Rat{})
+	bigIntTyp   = reflect.TypeOf(big.Int{})
+	bigFloatTyp = reflect.TypeOf(big.Float{})
+)
+
+// isDecimalType reports whether the values of the type are arbitrary
+// precision numbers of math/big
+func isDecimalType(typ reflect.Type) bool {
+	switch derefType(typ) {
+	case bigRatTyp, bigIntTyp, bigFloatTyp:
+		return true
+	}
+	return false
+}
+
+// isDecimal reports whether the value is an arbitrary precision number
+func isDecimal(value interface{}) bool {
+	return value != nil && isDecimalType(reflect.TypeOf(value))
+}
+
+// asRat returns the number as a big.Rat. Floats are converted from their
+// shortest decimal representation, so that 0.1 is 1/10 rather than the
+// binary fraction nearest to it.
+func asRat(value interface{}) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case *big.Rat:
+		return v, v != nil
+	case big.Rat:
+		return &v, true
+	case *big.Int:
+		return new(big.Rat).SetInt(v), v != nil
+	case big.Int:
+		return new(big.Rat).SetInt(&v), true
+	case *big.Float:
+		if v == nil || v.IsInf() {
+			return nil, false
+		}
+		r, _ := v.Rat(nil)
+		return r, true
+	case big.Float:
+		return asRat(&v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return new(big.Rat).SetString(strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	}
+	return nil, false
+}
+
+// decimalComparer compares arbitrary precision numbers exactly with other
+// numbers and with the strings they parse from
+type decimalComparer struct {
+	r *big.Rat
+}
+
+func (d decimalComparer) Compare(other interface{}) (int, error) {
+	o, ok := asRat(other)
+	if !ok {
+		if s, isString := other.(string); isString {
+			o, ok = new(big.Rat).SetString(s)
+		}
+		if !ok {
+			return 0, fmt.Errorf("%v is not a number", other)
+		}
+	}
+	return d.r.Cmp(o), nil
+}
+
+// decimalOperands returns the operands of math to perform with big.Rat: when
+// either is an arbitrary precision number, or a float with decimal
+// arithmetic, and both are numbers
+func decimalOperands(lvalue, rvalue interface{}, decimal bool) (*big.Rat, *big.Rat, bool) {
+	lkind, rkind := reflect.ValueOf(lvalue).Kind(), reflect.ValueOf(rvalue).Kind()
+	if !isDecimal(lvalue) && !isDecimal(rvalue) && !(decimal && (isFloatKind(lkind) || isFloatKind(rkind))) {
+		return nil, nil, false
+	}
+	l, ok := asRat(lvalue)
+	if !ok {
+		return nil, nil, false
+	}
+	r, ok := asRat(rvalue)
+	if !ok {
+		return nil, nil, false
+	}
+	return l, r, true
+}
+
+func doDecimalMath(op grammar.MathOperator, l, r *big.Rat) (interface{}, error) {
+	switch op {
+	case grammar.MathOpPlus:
+		return new(big.Rat).Add(l, r), nil
+	case grammar.MathOpMinus:
+		return new(big.Rat).Sub(l, r), nil
+	case grammar.MathOpMul:
+		return new(big.Rat).Mul(l, r), nil
+	case grammar.MathOpDiv:
+		if r.Sign() == 0 {
+			return nil, errors.New("decimal division by zero")
+		}
+		return new(big.Rat).Quo(l, r), nil
+	}
+	return nil, fmt.Errorf("cannot perform math operation %q on decimals", op)
+}
+
+// convertNumber converts JSON numbers, those which are not integers being
+// converted to exact decimals with decimal arithmetic
+func convertNumber(val interface{}, decimal bool) (interface{}, error) {
+	if jn, ok := val.(json.Number); ok && decimal && strings.ContainsAny(string(jn), ".eE") {
+		if r, ok := new(big.Rat).SetString(string(jn)); ok {
+			return r, nil
+		}
+	}
+	return convertJSONNumber(val)
+}
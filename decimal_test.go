@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalArithmetic(t *testing.T) {
+	t.Parallel()
+
+	var jsonDatum map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(`{"Price": 0.1, "Fee": 0.2}`))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&jsonDatum))
+
+	datum := map[string]interface{}{
+		"A":         0.1,
+		"B":         0.2,
+		"Amount":    1000.10,
+		"Threshold": 72.50725,
+		"Count":     3,
+		"Ratios":    []float64{0.25, 0.5},
+		"Name":      "v0.5",
+		"Balance":   big.NewRat(1, 3),
+		"Big":       new(big.Int).Lsh(big.NewInt(1), 100),
+		"Float":     big.NewFloat(2.5),
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		decimal    bool
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"float rounding":           {expression: `A + B == 0.3`, result: false},
+		"decimal sum":              {expression: `A + B == 0.3`, decimal: true, result: true},
+		"decimal product":          {expression: `Amount * 0.0725 == Threshold`, decimal: true, result: true},
+		"decimal product ordering": {expression: `Amount * 0.0725 > Threshold`, decimal: true, result: false},
+		"decimal division":         {expression: `let third = 1 / 3.0 in third * 3 == 1`, decimal: true, result: true},
+		"division by zero":         {expression: `A / 0.0 > 1`, decimal: true, err: `1:1 (0): A / 0.0 > 1: decimal division by zero`},
+		"integers unchanged":       {expression: `Count / 2 == 1`, decimal: true, result: true},
+		"mixed with integers":      {expression: `Count * 0.1 == 0.3`, decimal: true, result: true},
+		"literals folded":          {expression: `0.1 + 0.2 == 0.3`, decimal: true, result: true},
+		"lower":                    {expression: `A < 0.10000000000000001`, decimal: true, result: true},
+		"literal on the left":      {expression: `0.3 == A + B`, decimal: true, result: true},
+		"in":                       {expression: `0.5 in Ratios`, decimal: true, result: true},
+		"string contains":          {expression: `Name contains 0.5`, decimal: true, result: true},
+		"json numbers":             {expression: `Price + Fee == 0.3`, datum: jsonDatum, decimal: true, result: true},
+		"json numbers as floats":   {expression: `Price + Fee == 0.3`, datum: jsonDatum, result: false},
+		"big.Rat":                  {expression: `Balance * 3 == 1`, result: true},
+		"big.Rat comparison":       {expression: `Balance < 0.34`, result: true},
+		"big.Rat and string":       {expression: `Balance == "1/3"`, result: true},
+		"big.Int":                  {expression: `Big > 1000000000000000000`, result: true},
+		"big.Float":                {expression: `Float >= 2.5`, result: true},
+		"not a number": {
+			expression: `Balance == "third"`,
+			err:        `1:1 (0): Balance == "third": error comparing 1/3 and third: third is not a number`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if tcase.decimal {
+				opts = append(opts, WithDecimalArithmetic())
+			}
+			eval, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			d := tcase.datum
+			if d == nil {
+				d = datum
+			}
+			result, err := eval.Evaluate(d)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"sort"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// selectorDefault is the value of the selectors matching a pattern when they
+// are missing from the datum
+type selectorDefault struct {
+	selectorPattern
+	value interface{}
+}
+
+// WithDefaults sets the values of the selectors missing from the datums,
+// keyed by their path in the datum, such as "Meta.weight" or "/Meta/weight",
+// a "*" part matching any key. Unlike WithUnknownValue, which stands for
+// every missing selector, only the selectors given default to their values:
+// the other ones keep failing or being handled with the NotPresentDisposition
+// of their operator. Null values are not missing: they do not default. When
+// several patterns match a selector, the first one in lexical order wins.
+func WithDefaults(defaults map[string]interface{}) Option {
+	patterns := make([]string, 0, len(defaults))
+	for pattern := range defaults {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return func(o *options) {
+		for _, pattern := range patterns {
+			o.withDefaults = append(o.withDefaults, selectorDefault{
+				selectorPattern: newSelectorPattern(pattern),
+				value:           defaults[pattern],
+			})
+		}
+	}
+}
+
+// lookupDefault returns the default value of the selector path
+func lookupDefault(defaults []selectorDefault, path []string) (interface{}, bool) {
+	for _, d := range defaults {
+		if len(d.parts) == len(path) && d.matches(path) {
+			return d.value, true
+		}
+	}
+	return nil, false
+}
+
+// getDefaultedValue resolves the selector in the datum, returning the default
+// value when it is missing
+func getDefaultedValue(value interface{}, expressionValue *grammar.MatchValue, datum interface{}, opt ...Option) (interface{}, error) {
+	opt = append(opt[:len(opt):len(opt)], func(o *options) {
+		o.withDefaults = nil
+		o.withUnknown = nil
+	})
+	val, err := getValue(expressionValue, datum, opt...)
+	if !isUndefined(val) || (err != nil && !isNotFound(err)) {
+		return val, err
+	}
+	return convertNumber(value, getOpts(opt...).withDecimal)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaults(t *testing.T) {
+	t.Parallel()
+
+	defaults := map[string]interface{}{
+		"Meta.weight":  1,
+		"/Meta/region": "eu",
+		"Tags.*":       "none",
+		"Replicas":     3,
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"nested default": {
+			expression: `Meta.weight == 1 and Meta.region == "eu"`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{}},
+			result:     true,
+		},
+		"top level default": {
+			expression: `Replicas > 2`,
+			datum:      map[string]interface{}{},
+			result:     true,
+		},
+		"present value": {
+			expression: `Meta.weight == 1`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{"weight": 5}},
+			result:     false,
+		},
+		"null value": {
+			expression: `Meta.weight == null`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{"weight": nil}},
+			result:     true,
+		},
+		"wildcard": {
+			expression: `Tags.owner == "none"`,
+			datum:      map[string]interface{}{"Tags": map[string]string{}},
+			result:     true,
+		},
+		"struct": {
+			expression: `Meta.weight == 1`,
+			datum:      struct{ Meta map[string]int }{Meta: map[string]int{}},
+			result:     true,
+		},
+		"not present disposition": {
+			expression: `Meta.other == "x"`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{}},
+			result:     false,
+		},
+		"not present negated": {
+			expression: `Meta.other != "x"`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{}},
+			result:     true,
+		},
+		"unknown selector": {
+			expression: `Other == 1`,
+			datum:      map[string]interface{}{},
+			err:        `1:1 (0): Other == 1: error finding value in datum: /Other at part 0: couldn't find key "Other"`,
+		},
+		"over unknown value": {
+			expression: `Replicas == 3 and Other == 0`,
+			datum:      map[string]interface{}{},
+			opts:       []Option{WithUnknownValue(0)},
+			result:     true,
+		},
+		"let": {
+			expression: `let m = Meta in m.weight == 1`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{}},
+			result:     false,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append([]Option{WithDefaults(defaults)}, tcase.opts...)...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(tcase.datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestWithDefaults_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Meta.weight > 2`)
+	require.NoError(t, err)
+
+	datum := map[string]interface{}{"Meta": map[string]interface{}{}}
+	result, err := eval.Evaluate(datum, WithDefaults(map[string]interface{}{"Meta.weight": 3}))
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	result, err = eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, false, result)
+}
+
+func TestWithDefaults_Chained(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Meta.weight == 2`, WithDefaults(map[string]interface{}{"Meta.weight": 1}))
+	require.NoError(t, err)
+
+	result, err := eval.EvaluateChained(
+		map[string]interface{}{"Meta": map[string]interface{}{}},
+		map[string]interface{}{"Meta": map[string]interface{}{"weight": 2}})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+}
+
+func TestWithDefaults_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluator(`Meta.weight == 1`, WithDefaults(map[string]interface{}{"Meta..weight": 1}))
+	require.EqualError(t, err, `invalid options: invalid default: the pattern "Meta..weight" has empty parts`)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithDeterministic guarantees that evaluating the same expression against the
+// same datum yields the same outcome, errors, explanations and traces, byte
+// for byte, across runs and platforms, for snapshot tests of policies. Traces
+// render the values of their operands without the addresses fmt writes for
+// pointers, functions and channels: pointers are followed, maps are written
+// in the order of their keys, and functions, channels and unsafe pointers are
+// written as their type. Features of the expressions depending on the clock,
+// on randomness or on remote lookups fail the creation of evaluators in this
+// mode.
+//
+// The mode covers the evaluator only: the hooks, value converters, coercions,
+// comparers and selector sources given to it are expected to be deterministic
+// too.
+func WithDeterministic() Option {
+	return func(o *options) {
+		o.withDeterministic = true
+	}
+}
+
+// deterministicValue writes the value of a trace the way traceValue does,
+// without addresses
+func deterministicValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	w := &valueWriter{visiting: make(map[visit]bool)}
+	w.write(reflect.ValueOf(value))
+	return w.b.String()
+}
+
+// valueWriter writes values in the Go syntax, following references and
+// sorting the entries of maps
+type valueWriter struct {
+	b        strings.Builder
+	visiting map[visit]bool
+}
+
+func (w *valueWriter) write(v reflect.Value) {
+	if !v.IsValid() {
+		w.b.WriteString("nil")
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		w.b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		w.b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		w.b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32:
+		w.b.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 32))
+	case reflect.Float64:
+		w.b.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprintf(&w.b, "%v", v.Complex())
+	case reflect.String:
+		w.b.WriteString(strconv.Quote(v.String()))
+	case reflect.Array:
+		w.b.WriteString(v.Type().String())
+		w.elements(v)
+	case reflect.Slice:
+		w.b.WriteString(v.Type().String())
+		if v.IsNil() {
+			w.b.WriteString("(nil)")
+			return
+		}
+		if w.enter(v) {
+			w.elements(v)
+			w.leave(v)
+		}
+	case reflect.Map:
+		w.b.WriteString(v.Type().String())
+		if v.IsNil() {
+			w.b.WriteString("(nil)")
+			return
+		}
+		if w.enter(v) {
+			w.entries(v)
+			w.leave(v)
+		}
+	case reflect.Struct:
+		w.b.WriteString(v.Type().String())
+		w.b.WriteString("{")
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				w.b.WriteString(", ")
+			}
+			w.b.WriteString(v.Type().Field(i).Name)
+			w.b.WriteString(":")
+			w.write(v.Field(i))
+		}
+		w.b.WriteString("}")
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprintf(&w.b, "(%s)(nil)", v.Type())
+			return
+		}
+		w.b.WriteString("&")
+		if w.enter(v) {
+			w.write(v.Elem())
+			w.leave(v)
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			w.b.WriteString("nil")
+			return
+		}
+		w.write(v.Elem())
+	default:
+		// functions, channels and unsafe pointers, whose addresses change
+		// from one run to the other
+		w.b.WriteString(v.Type().String())
+	}
+}
+
+func (w *valueWriter) elements(v reflect.Value) {
+	w.b.WriteString("{")
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			w.b.WriteString(", ")
+		}
+		w.write(v.Index(i))
+	}
+	w.b.WriteString("}")
+}
+
+func (w *valueWriter) entries(v reflect.Value) {
+	entries := make([]string, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		entry := &valueWriter{visiting: w.visiting}
+		entry.write(iter.Key())
+		entry.b.WriteString(":")
+		entry.write(iter.Value())
+		entries = append(entries, entry.b.String())
+	}
+	sort.Strings(entries)
+	w.b.WriteString("{")
+	w.b.WriteString(strings.Join(entries, ", "))
+	w.b.WriteString("}")
+}
+
+// enter reports whether the value referenced is to be written, the values
+// referencing themselves being written up to the cycle
+func (w *valueWriter) enter(v reflect.Value) bool {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if w.visiting[key] {
+		w.b.WriteString("...")
+		return false
+	}
+	w.visiting[key] = true
+	return true
+}
+
+func (w *valueWriter) leave(v reflect.Value) {
+	delete(w.visiting, visit{ptr: v.Pointer(), typ: v.Type()})
+}
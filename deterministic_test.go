@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeterministicValue(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name string
+		Next *node
+		Fn   func()
+	}
+	cyclic := &node{Name: "a"}
+	cyclic.Next = cyclic
+	port := 8080
+
+	type testCase struct {
+		value    interface{}
+		expected string
+	}
+
+	tests := map[string]testCase{
+		"nil":       {value: nil, expected: `null`},
+		"string":    {value: "web", expected: `"web"`},
+		"numbers":   {value: []interface{}{1, uint8(2), 1.5, float32(0.1), nil}, expected: `[]interface {}{1, 2, 1.5, 0.1, nil}`},
+		"pointer":   {value: &port, expected: `&8080`},
+		"nil slice": {value: []string(nil), expected: `[]string(nil)`},
+		"map":       {value: map[string]interface{}{"b": &port, "a": []int{1}, "c": (*int)(nil)}, expected: `map[string]interface {}{"a":[]int{1}, "b":&8080, "c":(*int)(nil)}`},
+		"struct":    {value: node{Name: "a", Fn: func() {}}, expected: `bexpr.node{Name:"a", Next:(*bexpr.node)(nil), Fn:func()}`},
+		"cycle":     {value: cyclic, expected: `&bexpr.node{Name:"a", Next:&..., Fn:func()}`},
+		"channel":   {value: map[int]chan int{2: nil, 1: make(chan int)}, expected: `map[int]chan int{1:chan int, 2:chan int}`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tcase.expected, deterministicValue(tcase.value))
+		})
+	}
+}
+
+func TestWithDeterministic(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string
+		Port *int
+	}
+	port := 8080
+	datum := map[string]interface{}{"Service": &service{Name: "web", Port: &port}}
+
+	var trace *Trace
+	eval, err := CreateEvaluator(`Service.Port == 8080 and Service is not null`,
+		WithDeterministic(), WithTrace(func(tr *Trace) { trace = tr }))
+	require.NoError(t, err)
+
+	var outputs []string
+	for i := 0; i < 2; i++ {
+		result, err := eval.Evaluate(datum)
+		require.NoError(t, err)
+		require.Equal(t, true, result)
+		outputs = append(outputs, trace.String())
+	}
+	require.Equal(t, "And => true\n"+
+		"   Service.Port Equal 8080 [&8080, 8080] => true\n"+
+		"   Service Is Not Null [&bexpr.service{Name:\"web\", Port:&8080}] => true\n", outputs[0])
+	require.Equal(t, outputs[0], outputs[1])
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldDoc documents the field found at a selector, for error messages and
+// user interfaces to explain what it means.
+type FieldDoc struct {
+	Description string
+	Examples    []string
+}
+
+// String renders the documentation on a single line
+func (d FieldDoc) String() string {
+	if len(d.Examples) == 0 {
+		return d.Description
+	}
+	examples := "e.g. " + strings.Join(d.Examples, ", ")
+	if d.Description == "" {
+		return examples
+	}
+	return d.Description + ", " + examples
+}
+
+// FieldDocs documents the fields found at the selectors matching its keys,
+// patterns such as "Meta.*.Name" matched the same way as the ones of
+// WithSelectorHook, except that they must match the whole selector. When
+// several patterns match, the one with the fewest "*" wins.
+type FieldDocs map[string]FieldDoc
+
+// lookup returns the documentation of the selector path
+func (docs FieldDocs) lookup(path []string) (FieldDoc, bool) {
+	var matched []string
+	for key := range docs {
+		pattern := newSelectorPattern(key)
+		if len(pattern.parts) == len(path) && pattern.matches(path) {
+			matched = append(matched, key)
+		}
+	}
+	if len(matched) == 0 {
+		return FieldDoc{}, false
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		wi, wj := strings.Count(matched[i], "*"), strings.Count(matched[j], "*")
+		if wi != wj {
+			return wi < wj
+		}
+		return matched[i] < matched[j]
+	})
+	return docs[matched[0]], true
+}
+
+// DocumentedSchema is a Schema documenting the fields it describes. The
+// schemas returned by TypeSchema read the documentation from the "doc" and
+// "example" struct tags, and the ones returned by JSONSchema and
+// OpenAPISchema from the "description" and "examples" keywords.
+type DocumentedSchema interface {
+	Schema
+	// SelectorDoc returns the documentation of the field found at the
+	// selector path. The second return value is false when it has none.
+	SelectorDoc(path []string) (FieldDoc, bool)
+}
+
+// LookupFieldDoc returns the documentation of the field found at the selector
+// path, from the registry set with WithFieldDocs or else from the schema when
+// it is a DocumentedSchema. The schema may be nil.
+func LookupFieldDoc(schema Schema, path []string, opts ...Option) (FieldDoc, bool) {
+	if doc, ok := getOpts(opts...).withFieldDocs.lookup(path); ok {
+		return doc, true
+	}
+	if documented, ok := schema.(DocumentedSchema); ok {
+		return documented.SelectorDoc(path)
+	}
+	return FieldDoc{}, false
+}
+
+// docsSchema adds the documentation of a registry to a schema
+type docsSchema struct {
+	Schema
+	docs FieldDocs
+}
+
+func (s *docsSchema) SelectorDoc(path []string) (FieldDoc, bool) {
+	return LookupFieldDoc(s.Schema, path, WithFieldDocs(s.docs))
+}
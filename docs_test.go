@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testDocumented struct {
+	Name  string `doc:"The name of the service" example:"web"`
+	Port  int    `doc:"The port the service listens on"`
+	Tags  []string
+	Owner struct {
+		Email string `example:"alice@example.com"`
+	}
+	Meta map[string]string
+}
+
+func TestFieldDocs(t *testing.T) {
+	t.Parallel()
+
+	jsonSchema, err := JSONSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "description": "The name of the service", "examples": ["web", "db"]},
+			"port": {"$ref": "#/$defs/port"},
+			"weight": {"type": "number", "example": 1.5}
+		},
+		"$defs": {"port": {"type": "integer", "description": "A TCP port"}}
+	}`))
+	require.NoError(t, err)
+
+	registry := FieldDocs{
+		"Port":        {Description: "Overridden"},
+		"Meta.*":      {Description: "Any metadata"},
+		"Meta.region": {Description: "The region", Examples: []string{"eu-west-1"}},
+	}
+
+	type testCase struct {
+		schema Schema
+		path   []string
+		opts   []Option
+		doc    FieldDoc
+		found  bool
+	}
+
+	tests := map[string]testCase{
+		"struct tags": {
+			schema: TypeSchema(testDocumented{}),
+			path:   []string{"Name"},
+			doc:    FieldDoc{Description: "The name of the service", Examples: []string{"web"}},
+			found:  true,
+		},
+		"nested struct tag": {
+			schema: TypeSchema(&testDocumented{}),
+			path:   []string{"Owner", "Email"},
+			doc:    FieldDoc{Examples: []string{"alice@example.com"}},
+			found:  true,
+		},
+		"undocumented field": {
+			schema: TypeSchema(testDocumented{}),
+			path:   []string{"Tags"},
+		},
+		"unknown field": {
+			schema: TypeSchema(testDocumented{}),
+			path:   []string{"Missing"},
+		},
+		"registry precedence": {
+			schema: TypeSchema(testDocumented{}),
+			path:   []string{"Port"},
+			opts:   []Option{WithFieldDocs(registry)},
+			doc:    FieldDoc{Description: "Overridden"},
+			found:  true,
+		},
+		"registry wildcard": {
+			path:  []string{"Meta", "env"},
+			opts:  []Option{WithFieldDocs(registry)},
+			doc:   FieldDoc{Description: "Any metadata"},
+			found: true,
+		},
+		"registry exact match wins": {
+			path:  []string{"Meta", "region"},
+			opts:  []Option{WithFieldDocs(registry)},
+			doc:   FieldDoc{Description: "The region", Examples: []string{"eu-west-1"}},
+			found: true,
+		},
+		"registry whole selector": {
+			path: []string{"Meta", "region", "zone"},
+			opts: []Option{WithFieldDocs(registry)},
+		},
+		"json schema": {
+			schema: jsonSchema,
+			path:   []string{"name"},
+			doc:    FieldDoc{Description: "The name of the service", Examples: []string{"web", "db"}},
+			found:  true,
+		},
+		"json schema reference": {
+			schema: jsonSchema,
+			path:   []string{"port"},
+			doc:    FieldDoc{Description: "A TCP port"},
+			found:  true,
+		},
+		"openapi example": {
+			schema: jsonSchema,
+			path:   []string{"weight"},
+			doc:    FieldDoc{Examples: []string{"1.5"}},
+			found:  true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc, found := LookupFieldDoc(tcase.schema, tcase.path, tcase.opts...)
+			require.Equal(t, tcase.found, found)
+			require.Equal(t, tcase.doc, doc)
+		})
+	}
+}
+
+func TestFieldDocs_String(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "The port", FieldDoc{Description: "The port"}.String())
+	require.Equal(t, "e.g. 80, 443", FieldDoc{Examples: []string{"80", "443"}}.String())
+	require.Equal(t, "The port, e.g. 80", FieldDoc{Description: "The port", Examples: []string{"80"}}.String())
+}
+
+func TestFieldDocs_Validate(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluator(`Port matches "8.*"`, WithSchema(TypeSchema(testDocumented{})))
+	require.EqualError(t, err, `Port: operator "Matches" cannot be used with values of type int (The port the service listens on)`)
+
+	_, err = CreateEvaluator(`Tags > 1`, WithSchema(TypeSchema(testDocumented{})), WithFieldDocs(FieldDocs{
+		"Tags": {Description: "The tags of the service", Examples: []string{`"prod" in Tags`}},
+	}))
+	require.EqualError(t, err, `Tags: operator "Higher" cannot be used with values of type []string (The tags of the service, e.g. "prod" in Tags)`)
+}
+
+func TestFieldDocs_GenerateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := GenerateJSONSchema(testDocumented{}, WithFieldDocs(FieldDocs{
+		"Port":   {Description: "Overridden"},
+		"Meta.*": {Description: "Any metadata"},
+	}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"Name": {"type": "string", "description": "The name of the service", "examples": ["web"]},
+			"Port": {"type": "integer", "description": "Overridden"},
+			"Tags": {"type": "array", "items": {"type": "string"}},
+			"Owner": {"type": "object", "additionalProperties": false, "properties": {
+				"Email": {"type": "string", "examples": ["alice@example.com"]}
+			}},
+			"Meta": {"type": "object", "additionalProperties": {"type": "string", "description": "Any metadata"}}
+		}
+	}`, string(schema))
+
+	// the documentation survives the round trip
+	parsed, err := JSONSchema(schema)
+	require.NoError(t, err)
+	doc, ok := LookupFieldDoc(parsed, []string{"Name"})
+	require.True(t, ok)
+	require.Equal(t, FieldDoc{Description: "The name of the service", Examples: []string{"web"}}, doc)
+}
@@ -0,0 +1,272 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// esRangeOperators are the parameters of range queries
+var esRangeOperators = map[grammar.MatchOperator]string{
+	grammar.MatchLower:         "lt",
+	grammar.MatchLowerOrEqual:  "lte",
+	grammar.MatchHigher:        "gt",
+	grammar.MatchHigherOrEqual: "gte",
+}
+
+// ElasticsearchQuery translates the expression to the Elasticsearch query DSL,
+// returning the query to be encoded to JSON as the "query" of search
+// requests, so that the filters evaluated in memory can also be run against
+// an index holding the same documents. The options are the ones the values of
+// the expression are resolved with, such as WithParams.
+//
+// Logical operators are translated to bool queries, "and" to must, "or" to
+// should and "not" to must_not. Match expressions must compare a selector,
+// which becomes the field of the query, with values which do not depend on the
+// datum:
+//
+//	==, !=                    term, a bool must_not query holding a term
+//	<, <=, >, >=              range
+//	in, contains              term, which matches the elements of arrays
+//	is empty, is null         a bool must_not query holding an exists query
+//	startswith                prefix
+//	endswith, like            wildcard
+//	matches                   regexp
+//
+// Selectors used as boolean expressions are translated to terms matching true.
+// The translation does not know the mapping of the index: "contains" is not a
+// substring match on text fields, Lucene regular expressions are anchored
+// unlike Go ones, and documents missing a field match the negated queries
+// instead of failing the evaluation. Expressions which cannot be translated,
+// such as the ones comparing two selectors, return an error.
+func (eval *Evaluator) ElasticsearchQuery(opts ...Option) (map[string]interface{}, error) {
+	t := &esTranslator{opts: append(eval.evaluateOpts(), opts...)}
+	return t.expression(grammar.InlineLets(eval.ast))
+}
+
+type esTranslator struct {
+	opts []Option
+}
+
+func (t *esTranslator) expression(ast grammar.Expression) (map[string]interface{}, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := t.expression(node.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return esNot(operand), nil
+
+	case *grammar.BinaryExpression:
+		// chains of the same operator are flattened into a single bool query
+		var clauses []interface{}
+		for _, operand := range []grammar.Expression{node.Left, node.Right} {
+			clause, err := t.expression(operand)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		occur := "must"
+		if node.Operator == grammar.BinaryOpOr {
+			occur = "should"
+		}
+		var flattened []interface{}
+		for _, clause := range clauses {
+			if nested, ok := esBoolClauses(clause.(map[string]interface{}), occur); ok {
+				flattened = append(flattened, nested...)
+			} else {
+				flattened = append(flattened, clause)
+			}
+		}
+		query := map[string]interface{}{occur: flattened}
+		if occur == "should" {
+			query["minimum_should_match"] = 1
+		}
+		return map[string]interface{}{"bool": query}, nil
+
+	case *grammar.ExpressionValue:
+		if field, ok := esField(node); ok {
+			return esTerm(field, true), nil
+		}
+		value, err := t.value(node)
+		if err != nil {
+			return nil, err
+		}
+		if result, ok := value.(bool); ok {
+			return esConstant(result), nil
+		}
+		return nil, fmt.Errorf("cannot translate %s to a query: not a boolean", formatExpression(node))
+
+	case *grammar.MatchExpression:
+		return t.match(node)
+	}
+	return nil, fmt.Errorf("cannot translate %T to a query", ast)
+}
+
+func (t *esTranslator) match(node *grammar.MatchExpression) (map[string]interface{}, error) {
+	operator, left, right := node.Operator, node.Left, node.Right
+	if !refersToDatum(left) && !refersToDatum(right) {
+		// match expressions on literals, such as 1 == 1
+		result, err := evaluateMatchExpression(node, nil, t.opts...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot translate %s to a query: %w", formatExpression(node), err)
+		}
+		return esConstant(result), nil
+	}
+
+	field, ok := esField(left)
+	if mirrored, mirrorable := mirroredOperators[operator]; !ok && mirrorable {
+		// literals compared with a selector, such as 8080 < Port
+		operator, left, right = mirrored, right, left
+		field, ok = esField(left)
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot translate %s to a query: the expression does not compare a selector with a value", formatExpression(node))
+	}
+
+	var value interface{}
+	if right != nil {
+		var err error
+		if value, err = t.value(right); err != nil {
+			return nil, fmt.Errorf("cannot translate %s to a query: %w", formatExpression(node), err)
+		}
+	}
+
+	if positive, ok := positiveOperators[operator]; ok {
+		query, err := t.positiveMatch(node, positive, field, value)
+		if err != nil {
+			return nil, err
+		}
+		return esNot(query), nil
+	}
+	return t.positiveMatch(node, operator, field, value)
+}
+
+func (t *esTranslator) positiveMatch(node *grammar.MatchExpression, operator grammar.MatchOperator, field string, value interface{}) (map[string]interface{}, error) {
+	switch operator {
+	case grammar.MatchEqual, grammar.MatchIn:
+		if value == nil {
+			return esNot(esExists(field)), nil
+		}
+		return esTerm(field, value), nil
+	case grammar.MatchLower, grammar.MatchLowerOrEqual, grammar.MatchHigher, grammar.MatchHigherOrEqual:
+		return map[string]interface{}{"range": map[string]interface{}{
+			field: map[string]interface{}{esRangeOperators[operator]: value},
+		}}, nil
+	case grammar.MatchIsEmpty, grammar.MatchIsNull:
+		return esNot(esExists(field)), nil
+	}
+
+	pattern, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot translate %s to a query: %v is not a string", formatExpression(node), value)
+	}
+	switch operator {
+	case grammar.MatchStartsWith:
+		return map[string]interface{}{"prefix": map[string]interface{}{field: pattern}}, nil
+	case grammar.MatchEndsWith:
+		return esWildcard(field, "*"+esWildcardEscaper.Replace(pattern)), nil
+	case grammar.MatchLike:
+		if strings.Contains(pattern, "[") {
+			return nil, fmt.Errorf("cannot translate %s to a query: character classes are not supported by wildcard queries", formatExpression(node))
+		}
+		return esWildcard(field, pattern), nil
+	case grammar.MatchMatches:
+		return map[string]interface{}{"regexp": map[string]interface{}{field: pattern}}, nil
+	}
+	return nil, fmt.Errorf("cannot translate %s to a query: unsupported operator %s", formatExpression(node), operator)
+}
+
+// value resolves an operand which does not depend on the datum
+func (t *esTranslator) value(expr *grammar.ExpressionValue) (interface{}, error) {
+	return translatedValue(expr, t.opts...)
+}
+
+// translatedValue resolves an operand of the expressions translated to other
+// query languages, which must not depend on the datum
+func translatedValue(expr *grammar.ExpressionValue, opt ...Option) (interface{}, error) {
+	if refersToDatum(expr) {
+		return nil, fmt.Errorf("%s depends on the datum", formatExpression(expr))
+	}
+	return getExprValue(expr, nil, opt...)
+}
+
+// refersToDatum reports whether the value depends on the datum
+func refersToDatum(value interface{}) bool {
+	switch node := value.(type) {
+	case *grammar.MatchValue:
+		for _, arg := range node.Args {
+			if refersToDatum(arg) {
+				return true
+			}
+		}
+		return node.Type == grammar.ValueTypeReflect
+	case *grammar.ExpressionValue:
+		return node != nil && (refersToDatum(node.Left) || refersToDatum(node.Right))
+	}
+	return false
+}
+
+// esField returns the field of the operand when it is a selector
+func esField(expr *grammar.ExpressionValue) (string, bool) {
+	if expr == nil || expr.Operator != grammar.MathOpValue {
+		return "", false
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect || len(value.Selector.Ranges) > 0 {
+		return "", false
+	}
+	return strings.Join(value.Selector.Path, "."), true
+}
+
+// esBoolClauses returns the clauses of bool queries made only of the given
+// occurrence type
+func esBoolClauses(query map[string]interface{}, occur string) ([]interface{}, bool) {
+	boolQuery, ok := query["bool"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for key := range boolQuery {
+		if key != occur && key != "minimum_should_match" {
+			return nil, false
+		}
+	}
+	clauses, ok := boolQuery[occur].([]interface{})
+	return clauses, ok
+}
+
+func esNot(query map[string]interface{}) map[string]interface{} {
+	if clauses, ok := esBoolClauses(query, "must_not"); ok && len(clauses) == 1 {
+		// not not
+		return clauses[0].(map[string]interface{})
+	}
+	return map[string]interface{}{"bool": map[string]interface{}{"must_not": []interface{}{query}}}
+}
+
+// esConstant returns the query matching all the documents or none
+func esConstant(result bool) map[string]interface{} {
+	if result {
+		return map[string]interface{}{"match_all": map[string]interface{}{}}
+	}
+	return map[string]interface{}{"match_none": map[string]interface{}{}}
+}
+
+func esTerm(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"term": map[string]interface{}{field: value}}
+}
+
+func esExists(field string) map[string]interface{} {
+	return map[string]interface{}{"exists": map[string]interface{}{"field": field}}
+}
+
+func esWildcard(field, pattern string) map[string]interface{} {
+	return map[string]interface{}{"wildcard": map[string]interface{}{field: pattern}}
+}
+
+// esWildcardEscaper escapes the special characters of wildcard queries
+var esWildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
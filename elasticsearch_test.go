@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticsearchQuery(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		query      string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equal": {
+			expression: `Name == "web"`,
+			query:      `{"term":{"Name":"web"}}`,
+		},
+		"not equal": {
+			expression: `Name != "web"`,
+			query:      `{"bool":{"must_not":[{"term":{"Name":"web"}}]}}`,
+		},
+		"nested field": {
+			expression: `Meta.env == "prod" and "/Meta/tier" == 2`,
+			query:      `{"bool":{"must":[{"term":{"Meta.env":"prod"}},{"term":{"Meta.tier":2}}]}}`,
+		},
+		"and chain": {
+			expression: `a == 1 and b == 2 and (c == 3 and d == 4)`,
+			query:      `{"bool":{"must":[{"term":{"a":1}},{"term":{"b":2}},{"term":{"c":3}},{"term":{"d":4}}]}}`,
+		},
+		"or": {
+			expression: `a == 1 or b == 2 or c == 3`,
+			query:      `{"bool":{"minimum_should_match":1,"should":[{"term":{"a":1}},{"term":{"b":2}},{"term":{"c":3}}]}}`,
+		},
+		"mixed": {
+			expression: `a == 1 and (b == 2 or not c == 3)`,
+			query:      `{"bool":{"must":[{"term":{"a":1}},{"bool":{"minimum_should_match":1,"should":[{"term":{"b":2}},{"bool":{"must_not":[{"term":{"c":3}}]}}]}}]}}`,
+		},
+		"range": {
+			expression: `Port >= 8000 and Port < 9000 and Ratio <= 0.5 and 10 < Count`,
+			query:      `{"bool":{"must":[{"range":{"Port":{"gte":8000}}},{"range":{"Port":{"lt":9000}}},{"range":{"Ratio":{"lte":0.5}}},{"range":{"Count":{"gt":10}}}]}}`,
+		},
+		"in": {
+			expression: `"prod" in Tags and Tags not contains "test"`,
+			query:      `{"bool":{"must":[{"term":{"Tags":"prod"}},{"bool":{"must_not":[{"term":{"Tags":"test"}}]}}]}}`,
+		},
+		"empty and null": {
+			expression: `Tags is empty or Owner is not null`,
+			query:      `{"bool":{"minimum_should_match":1,"should":[{"bool":{"must_not":[{"exists":{"field":"Tags"}}]}},{"exists":{"field":"Owner"}}]}}`,
+		},
+		"equal null": {
+			expression: `Owner == null`,
+			query:      `{"bool":{"must_not":[{"exists":{"field":"Owner"}}]}}`,
+		},
+		"string operators": {
+			expression: `Name startswith "web" and Name endswith "*.1" and Name like "w?b*" and Name matches "web-[0-9]+"`,
+			query:      `{"bool":{"must":[{"prefix":{"Name":"web"}},{"wildcard":{"Name":"*\\*.1"}},{"wildcard":{"Name":"w?b*"}},{"regexp":{"Name":"web-[0-9]+"}}]}}`,
+		},
+		"boolean selector": {
+			expression: `Enabled and not Deleted`,
+			query:      `{"bool":{"must":[{"term":{"Enabled":true}},{"bool":{"must_not":[{"term":{"Deleted":true}}]}}]}}`,
+		},
+		"let": {
+			expression: `let t = Meta.tier in t == "gold" or t == "platinum"`,
+			query:      `{"bool":{"minimum_should_match":1,"should":[{"term":{"Meta.tier":"gold"}},{"term":{"Meta.tier":"platinum"}}]}}`,
+		},
+		"math on literals": {
+			expression: `Port > 8000 + 80`,
+			query:      `{"range":{"Port":{"gt":8080}}}`,
+		},
+		"params": {
+			expression: `Owner == $user`,
+			opts:       []Option{WithParams(map[string]interface{}{"user": "alice"})},
+			query:      `{"term":{"Owner":"alice"}}`,
+		},
+		"folded": {
+			expression: `1 == 1`,
+			query:      `{"match_all":{}}`,
+		},
+		"two selectors": {
+			expression: `Port == Other`,
+			err:        `cannot translate Port == Other to a query: Other depends on the datum`,
+		},
+		"math on selector": {
+			expression: `Port + 1 == 8080`,
+			err:        `cannot translate Port + 1 == 8080 to a query: the expression does not compare a selector with a value`,
+		},
+		"character class": {
+			expression: `Name like "web-[0-9]"`,
+			err:        `cannot translate Name like "web-[0-9]" to a query: character classes are not supported by wildcard queries`,
+		},
+		"missing param": {
+			expression: `Owner == $user`,
+			err:        `cannot translate Owner == $user to a query: no value bound to parameter $user`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			query, err := eval.ElasticsearchQuery(tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			encoded, err := json.Marshal(query)
+			require.NoError(t, err)
+			require.JSONEq(t, tcase.query, string(encoded))
+		})
+	}
+}
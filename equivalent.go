@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// maxEquivalenceConditions bounds the number of distinct conditions two
+// expressions may hold to be compared, as every combination of their outcomes
+// is checked.
+const maxEquivalenceConditions = 16
+
+// positiveOperators maps the operators whose outcome is always the opposite of
+// another operator's to that operator.
+var positiveOperators = map[grammar.MatchOperator]grammar.MatchOperator{
+	grammar.MatchNotEqual:      grammar.MatchEqual,
+	grammar.MatchNotIn:         grammar.MatchIn,
+	grammar.MatchIsNotEmpty:    grammar.MatchIsEmpty,
+	grammar.MatchNotMatches:    grammar.MatchMatches,
+	grammar.MatchIsNotNull:     grammar.MatchIsNull,
+	grammar.MatchNotStartsWith: grammar.MatchStartsWith,
+	grammar.MatchNotEndsWith:   grammar.MatchEndsWith,
+	grammar.MatchNotLike:       grammar.MatchLike,
+}
+
+// mirroredOperators maps the operators to the ones giving the same outcome
+// with their operands swapped.
+var mirroredOperators = map[grammar.MatchOperator]grammar.MatchOperator{
+	grammar.MatchEqual:         grammar.MatchEqual,
+	grammar.MatchNotEqual:      grammar.MatchNotEqual,
+	grammar.MatchLower:         grammar.MatchHigher,
+	grammar.MatchLowerOrEqual:  grammar.MatchHigherOrEqual,
+	grammar.MatchHigher:        grammar.MatchLower,
+	grammar.MatchHigherOrEqual: grammar.MatchLowerOrEqual,
+}
+
+// Equivalent reports whether the two expressions are logically equivalent,
+// matching exactly the same datums, so that stored filters can be
+// deduplicated and edits which do not change a filter detected.
+//
+// Both expressions are normalized first, see grammar.Simplify. Their match
+// expressions are then treated as opaque conditions, identified by their
+// operator and operands, and the expressions are equivalent when they agree
+// for every combination of the outcomes of their conditions. This proves,
+// for instance, that "a == 1 and b == 2" and "not (b != 2 or a != 1)" are
+// equivalent, but not that "a == 1 and a == 2" is never true, as conditions
+// are not compared with each other. Expressions holding more than 16 distinct
+// conditions cannot be compared.
+func Equivalent(a, b string) (bool, error) {
+	exprs := make([]grammar.Expression, 2)
+	for i, expression := range []string{a, b} {
+		ast, err := grammar.Parse("", []byte(expression))
+		if err != nil {
+			return false, err
+		}
+		exprs[i] = grammar.Simplify(foldConstants(grammar.InlineLets(ast.(grammar.Expression))))
+	}
+
+	conditions := make(map[string]int)
+	for _, expr := range exprs {
+		collectConditions(expr, conditions)
+	}
+	if len(conditions) > maxEquivalenceConditions {
+		return false, fmt.Errorf("expressions hold %d distinct conditions, at most %d can be compared", len(conditions), maxEquivalenceConditions)
+	}
+
+	for outcomes := uint32(0); outcomes < 1<<len(conditions); outcomes++ {
+		if evaluateConditions(exprs[0], conditions, outcomes) != evaluateConditions(exprs[1], conditions, outcomes) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// collectConditions assigns an index to each distinct condition of the
+// expression.
+func collectConditions(ast grammar.Expression, conditions map[string]int) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		collectConditions(node.Operand, conditions)
+	case *grammar.BinaryExpression:
+		collectConditions(node.Left, conditions)
+		collectConditions(node.Right, conditions)
+	default:
+		if key, _, ok := conditionKey(ast); ok {
+			if _, found := conditions[key]; !found {
+				conditions[key] = len(conditions)
+			}
+		}
+	}
+}
+
+// evaluateConditions evaluates the expression given the outcome of each of
+// its conditions, the bit of the index of a condition holding its outcome.
+func evaluateConditions(ast grammar.Expression, conditions map[string]int, outcomes uint32) bool {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return !evaluateConditions(node.Operand, conditions, outcomes)
+	case *grammar.BinaryExpression:
+		left := evaluateConditions(node.Left, conditions, outcomes)
+		right := evaluateConditions(node.Right, conditions, outcomes)
+		if node.Operator == grammar.BinaryOpAnd {
+			return left && right
+		}
+		return left || right
+	case *grammar.ExpressionValue:
+		if literal, ok := node.Left.(*grammar.MatchValue); ok && node.Operator == grammar.MathOpValue && literal.Type == grammar.ValueTypeBool {
+			return literal.Raw == "true"
+		}
+	}
+	key, negated, _ := conditionKey(ast)
+	return (outcomes&(1<<conditions[key]) != 0) != negated
+}
+
+// conditionKey identifies the condition of a match expression or a value used
+// as an expression. Negated operators are identified as the condition of the
+// operator they negate, the second return value being true. The third return
+// value is false for the true and false literals, which are not conditions.
+func conditionKey(ast grammar.Expression) (string, bool, bool) {
+	switch node := ast.(type) {
+	case *grammar.MatchExpression:
+		op, left, right := node.Operator, node.Left, node.Right
+		if mirrored, ok := mirroredOperators[op]; ok && isConstant(left) && !isConstant(right) {
+			op, left, right = mirrored, right, left
+		}
+		positive, negated := positiveOperators[op]
+		if !negated {
+			positive = op
+		}
+		return fmt.Sprintf("%d(%s, %s)", positive, operandKey(left), operandKey(right)), negated, true
+	case *grammar.ExpressionValue:
+		if literal, ok := node.Left.(*grammar.MatchValue); ok && node.Operator == grammar.MathOpValue && literal.Type == grammar.ValueTypeBool {
+			return "", false, false
+		}
+		return operandKey(node), false, true
+	}
+	return fmt.Sprintf("%T", ast), false, true
+}
+
+// operandKey identifies the operand of a match expression. Selectors are
+// identified by their path, whatever their syntax, and literals by their
+// type and value.
+func operandKey(value interface{}) string {
+	switch node := value.(type) {
+	case *grammar.ExpressionValue:
+		if node == nil {
+			return ""
+		}
+		if node.Operator == grammar.MathOpValue {
+			return operandKey(node.Left)
+		}
+		return fmt.Sprintf("(%s %s %s)", operandKey(node.Left), node.Operator, operandKey(node.Right))
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeReflect:
+			return "sel" + strconv.Quote(strings.Join(node.Selector.Path, "\x00")+rangesKey(node.Selector.Ranges))
+		case grammar.ValueTypeCall:
+			args := make([]string, len(node.Args))
+			for i, arg := range node.Args {
+				args[i] = operandKey(arg)
+			}
+			return "call:" + node.Raw + "(" + strings.Join(args, ",") + ")"
+		case grammar.ValueTypeFloat64:
+			if f, err := strconv.ParseFloat(node.Raw, 64); err == nil {
+				return "float:" + strconv.FormatFloat(f, 'g', -1, 64)
+			}
+		}
+		return fmt.Sprintf("%d:%s", node.Type, strconv.Quote(node.Raw))
+	}
+	return ""
+}
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEquivalent(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		a          string
+		b          string
+		equivalent bool
+		err        string
+	}
+
+	var manyConditions []string
+	for i := 0; i < 17; i++ {
+		manyConditions = append(manyConditions, fmt.Sprintf("x == %d", i))
+	}
+
+	tests := map[string]testCase{
+		"identical": {
+			a:          `foo == 3`,
+			b:          `foo == 3`,
+			equivalent: true,
+		},
+		"whitespace and parentheses": {
+			a:          `(foo == 3)`,
+			b:          `foo==3`,
+			equivalent: true,
+		},
+		"different value": {
+			a: `foo == 3`,
+			b: `foo == 4`,
+		},
+		"different value type": {
+			a: `foo == 3`,
+			b: `foo == "3"`,
+		},
+		"float formatting": {
+			a:          `foo == 3.50`,
+			b:          `foo == 3.5`,
+			equivalent: true,
+		},
+		"selector syntax": {
+			a:          `foo.bar == 3`,
+			b:          `"/foo/bar" == 3`,
+			equivalent: true,
+		},
+		"commutativity": {
+			a:          `foo == 3 and bar == 4`,
+			b:          `bar == 4 and foo == 3`,
+			equivalent: true,
+		},
+		"de morgan": {
+			a:          `foo == 3 and bar == 4`,
+			b:          `not (bar != 4 or not foo == 3)`,
+			equivalent: true,
+		},
+		"distributivity": {
+			a:          `foo == 3 and (bar == 4 or baz == 5)`,
+			b:          `(foo == 3 and bar == 4) or (foo == 3 and baz == 5)`,
+			equivalent: true,
+		},
+		"absorption": {
+			a:          `foo == 3 or (foo == 3 and bar == 4)`,
+			b:          `foo == 3`,
+			equivalent: true,
+		},
+		"idempotence": {
+			a:          `foo is empty and foo is empty`,
+			b:          `foo is empty`,
+			equivalent: true,
+		},
+		"mirrored operands": {
+			a:          `3 < foo and 4 == bar`,
+			b:          `foo > 3 and bar == 4`,
+			equivalent: true,
+		},
+		"ordering not negated": {
+			a: `not foo < 3`,
+			b: `foo >= 3`,
+		},
+		"constants": {
+			a:          `foo == 3 or 1 + 1 == 3`,
+			b:          `foo == 3`,
+			equivalent: true,
+		},
+		"tautologies": {
+			a:          `foo == 3 or foo != 3`,
+			b:          `bar is empty or bar is not empty`,
+			equivalent: true,
+		},
+		"different operator": {
+			a: `foo == 3 and bar == 4`,
+			b: `foo == 3 or bar == 4`,
+		},
+		"extra condition": {
+			a: `foo == 3`,
+			b: `foo == 3 and bar == 4`,
+		},
+		"math": {
+			a:          `foo + 1 > 3`,
+			b:          `3 < foo + 1`,
+			equivalent: true,
+		},
+		"different math": {
+			a: `foo + 1 > 3`,
+			b: `foo + 2 > 3`,
+		},
+		"parse error": {
+			a:   `foo == 3`,
+			b:   `foo ==`,
+			err: "1:7 (6): no match found",
+		},
+		"too many conditions": {
+			a:   strings.Join(manyConditions, " or "),
+			b:   `x == 1`,
+			err: "expressions hold 17 distinct conditions, at most 16 can be compared",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			equivalent, err := Equivalent(tcase.a, tcase.b)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.equivalent, equivalent)
+
+			equivalent, err = Equivalent(tcase.b, tcase.a)
+			require.NoError(t, err)
+			require.Equal(t, tcase.equivalent, equivalent)
+		})
+	}
+}
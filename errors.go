@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// The sentinel errors the errors of the package match with errors.Is, so
+// that callers can tell them apart without parsing their messages
+var (
+	// ErrSyntax matches the *SyntaxError errors of the expressions which
+	// cannot be parsed
+	ErrSyntax = errors.New("syntax error")
+	// ErrUnknownSelector matches the *UnknownSelectorError errors of the
+	// selectors which cannot be resolved
+	ErrUnknownSelector = errors.New("unknown selector")
+	// ErrTypeMismatch matches the *TypeMismatchError errors
+	ErrTypeMismatch = api.ErrTypeMismatch
+	// ErrUnsupportedOperator matches the *UnsupportedOperatorError errors
+	ErrUnsupportedOperator = api.ErrUnsupportedOperator
+)
+
+// SyntaxError is the error of the expressions, and of the expressions of
+// the macros, which cannot be parsed. Its message is the one of the parser,
+// listing the errors found.
+type SyntaxError struct {
+	// Position is where the first error was found
+	Position grammar.Position
+	Err      error
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+func (e *SyntaxError) Is(target error) bool {
+	return target == ErrSyntax
+}
+
+// newSyntaxError wraps the error returned by grammar.Parse
+func newSyntaxError(err error) error {
+	pos, _ := grammar.ErrorPosition(err)
+	return &SyntaxError{Position: pos, Err: err}
+}
+
+// UnknownSelectorError is the error of the selectors which cannot be
+// resolved in the datum, or in the Schema the expression is validated
+// against, such as the selectors of missing fields or indexing strings.
+type UnknownSelectorError struct {
+	// Selector is the selector, such as Meta.env
+	Selector string
+	// Schema is set for the selectors which cannot be resolved in a Schema
+	Schema bool
+	// Suggestions are the selectors closest to the selector, written in the
+	// bexpr syntax, for the selectors which cannot be resolved in an
+	// EnumerableSchema
+	Suggestions []string
+	Err         error
+}
+
+func (e *UnknownSelectorError) Error() string {
+	source := "datum"
+	if e.Schema {
+		source = "schema"
+	}
+	if len(e.Suggestions) == 0 {
+		return fmt.Sprintf("error finding value in %s: %v", source, e.Err)
+	}
+	suggestions := strings.Join(e.Suggestions, ", ")
+	if n := len(e.Suggestions); n > 1 {
+		suggestions = strings.Join(e.Suggestions[:n-1], ", ") + " or " + e.Suggestions[n-1]
+	}
+	return fmt.Sprintf("error finding value in %s: %v (did you mean %s?)", source, e.Err, suggestions)
+}
+
+func (e *UnknownSelectorError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnknownSelectorError) Is(target error) bool {
+	return target == ErrUnknownSelector
+}
+
+// EvaluationError is the error evaluating a match expression, or the value
+// bound by a let expression, within the expression of an Evaluator. It
+// reports which sub-expression failed and where it starts, and unwraps to
+// the underlying error, such as an *UnsupportedOperatorError.
+type EvaluationError struct {
+	// Expression is the sub-expression which failed, written in the bexpr
+	// syntax, such as `Port > "80"` or `let p = Port + "a"`
+	Expression string
+	// Position is where the sub-expression starts. It is the zero Position
+	// for the sub-expressions which were not written in the expression, such
+	// as the ones expanded from macros.
+	Position grammar.Position
+	Err      error
+}
+
+func (e *EvaluationError) Error() string {
+	if !e.Position.IsValid() {
+		return fmt.Sprintf("%s: %v", e.Expression, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Position, e.Expression, e.Err)
+}
+
+func (e *EvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// matchError wraps the error evaluating the match expression
+func matchError(node *grammar.MatchExpression, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &EvaluationError{Expression: formatExpression(node), Position: node.Position, Err: err}
+}
+
+// letError wraps the error evaluating the value bound by the let expression
+func letError(node *grammar.LetExpression, err error) error {
+	return &EvaluationError{
+		Expression: "let " + node.Name + " = " + formatExpression(node.Value),
+		Position:   node.Position,
+		Err:        err,
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationError(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		err        string
+		position   grammar.Position
+	}
+
+	datum := map[string]interface{}{"Name": "web", "Port": 8080, "Tags": []string{"a"}}
+
+	tests := map[string]testCase{
+		"match":          {expression: `Name == "web" and Tags < 2`, err: `1:19 (18): Tags < 2: operator "Lower" cannot be used with values of type []string`, position: grammar.Position{Offset: 18, Line: 1, Column: 19}},
+		"lines":          {expression: "Name == \"web\"\n  and Port + Name > 1", err: `2:7 (20): Port + Name > 1: cannot perform math operation "+" on values of type int and string`, position: grammar.Position{Offset: 20, Line: 2, Column: 7}},
+		"let":            {expression: `Name == "web" and let p = Port / 0 in p > 1`, err: `1:19 (18): let p = Port / 0: integer division by zero`, position: grammar.Position{Offset: 18, Line: 1, Column: 19}},
+		"macro":          {expression: `Port > 1 and is_web`, opts: []Option{WithMacros(map[string]string{"is_web": `Name < 1`})}, err: `Name < 1: operator "Lower" cannot be used with values of type string`},
+		"unknown result": {expression: `not (Tags < 2)`, opts: []Option{WithUnknownResult(false)}, err: `1:6 (5): Tags < 2: operator "Lower" cannot be used with values of type []string`, position: grammar.Position{Offset: 5, Line: 1, Column: 6}},
+		"trace":          {expression: `Tags < 2`, opts: []Option{WithTrace(func(*Trace) {})}, err: `1:1 (0): Tags < 2: operator "Lower" cannot be used with values of type []string`, position: grammar.Position{Offset: 0, Line: 1, Column: 1}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			_, err = eval.Evaluate(datum)
+			require.EqualError(t, err, tcase.err)
+			var evalErr *EvaluationError
+			require.True(t, errors.As(err, &evalErr))
+			require.Equal(t, tcase.position, evalErr.Position)
+		})
+	}
+}
+
+func TestEvaluationError_Unwrap(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Tags < 2`)
+	require.NoError(t, err)
+
+	_, err = eval.Evaluate(map[string]interface{}{"Tags": []string{"a"}})
+	var unsupported *UnsupportedOperatorError
+	require.True(t, errors.As(err, &unsupported))
+	require.Equal(t, grammar.MatchLower, unsupported.Operator)
+}
+
+func TestSentinelErrors(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		datum      interface{}
+		sentinel   error
+		check      func(t *testing.T, err error)
+	}
+
+	type service struct {
+		Name string
+		Port int
+	}
+	datum := map[string]interface{}{"Name": "web", "Port": 8080}
+
+	tests := map[string]testCase{
+		"syntax": {
+			expression: "Name == \"web\" and\n  Port >",
+			sentinel:   ErrSyntax,
+			check: func(t *testing.T, err error) {
+				var syntaxErr *SyntaxError
+				require.True(t, errors.As(err, &syntaxErr))
+				require.Equal(t, grammar.Position{Offset: 26, Line: 2, Column: 9}, syntaxErr.Position)
+			},
+		},
+		"macro syntax": {
+			expression: `is_web`,
+			opts:       []Option{WithMacros(map[string]string{"is_web": `Name ==`})},
+			sentinel:   ErrSyntax,
+		},
+		"unknown selector": {
+			expression: `Meta.env == "prod"`,
+			datum:      datum,
+			sentinel:   ErrUnknownSelector,
+			check: func(t *testing.T, err error) {
+				var selectorErr *UnknownSelectorError
+				require.True(t, errors.As(err, &selectorErr))
+				require.Equal(t, "Meta.env", selectorErr.Selector)
+				require.False(t, selectorErr.Schema)
+				var evalErr *EvaluationError
+				require.True(t, errors.As(err, &evalErr))
+				require.Equal(t, grammar.Position{Offset: 0, Line: 1, Column: 1}, evalErr.Position)
+			},
+		},
+		"unknown selector in schema": {
+			expression: `Meta.env == "prod"`,
+			opts:       []Option{WithSchema(TypeSchema(service{}))},
+			sentinel:   ErrUnknownSelector,
+			check: func(t *testing.T, err error) {
+				var selectorErr *UnknownSelectorError
+				require.True(t, errors.As(err, &selectorErr))
+				require.Equal(t, "Meta.env", selectorErr.Selector)
+				require.True(t, selectorErr.Schema)
+			},
+		},
+		"type mismatch": {
+			expression: `Port == "8080"`,
+			opts:       []Option{WithStrictTypes()},
+			datum:      datum,
+			sentinel:   ErrTypeMismatch,
+			check: func(t *testing.T, err error) {
+				var mismatch *TypeMismatchError
+				require.True(t, errors.As(err, &mismatch))
+				require.Equal(t, grammar.MatchEqual, mismatch.Operator)
+			},
+		},
+		"unsupported operator": {
+			expression: `Port startswith "8"`,
+			datum:      datum,
+			sentinel:   ErrUnsupportedOperator,
+			check: func(t *testing.T, err error) {
+				var unsupported *UnsupportedOperatorError
+				require.True(t, errors.As(err, &unsupported))
+				require.Equal(t, grammar.MatchStartsWith, unsupported.Operator)
+			},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			if err == nil {
+				_, err = eval.Evaluate(tcase.datum)
+			}
+			require.ErrorIs(t, err, tcase.sentinel)
+			for _, sentinel := range []error{ErrSyntax, ErrUnknownSelector, ErrTypeMismatch, ErrUnsupportedOperator} {
+				if sentinel != tcase.sentinel {
+					require.NotErrorIs(t, err, sentinel)
+				}
+			}
+			if tcase.check != nil {
+				tcase.check(t, err)
+			}
+		})
+	}
+}
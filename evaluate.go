@@ -5,11 +5,15 @@ package bexpr
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"path"
 	"reflect"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gterranova/go-bexpr/grammar"
@@ -36,6 +40,34 @@ func isUndefined(f interface{}) bool {
 	return t == reflect.TypeOf(undefined)
 }
 
+// isNull reports whether the value is nil: an untyped nil (such as the null
+// literal or a JSON null) or a nil pointer, interface, map, slice, func or
+// channel.
+func isNull(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// indirect follows non-nil pointers to the value they point to
+func indirect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return value
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
 func primitiveEqualityFn(value interface{}) func(first interface{}, second interface{}) bool {
 	t := reflect.Indirect(reflect.ValueOf(value))
 	switch t.Kind() {
@@ -59,9 +91,7 @@ func doEqualBool(first interface{}, second interface{}) bool {
 }
 
 func doEqualInt64(first interface{}, second interface{}) bool {
-	b1, _ := CoerceInt64(first)
-	b2, _ := CoerceInt64(second)
-	return b1 == b2
+	return compareIntegers(first, second) == 0
 }
 
 func doEqualFloat64(first interface{}, second interface{}) bool {
@@ -102,9 +132,7 @@ func primitiveLowerFn(value interface{}) func(first interface{}, second interfac
 }
 
 func doLowerInt64(first interface{}, second interface{}) bool {
-	b1, _ := CoerceInt64(first)
-	b2, _ := CoerceInt64(second)
-	return b1 < b2
+	return compareIntegers(first, second) < 0
 }
 
 func doLowerFloat64(first interface{}, second interface{}) bool {
@@ -121,28 +149,86 @@ func derefType(rtype reflect.Type) reflect.Type {
 	return rtype
 }
 
-func doMatchMatches(leftValue interface{}, rightValue interface{}) (bool, error) {
+func doMatchMatches(leftValue interface{}, rightValue interface{}, limits regexLimits) (bool, error) {
 	value := reflect.Indirect(reflect.ValueOf(leftValue))
 
 	if !value.Type().ConvertibleTo(byteSliceTyp) {
 		return false, fmt.Errorf("value of type %s is not convertible to []byte", value.Type())
 	}
 
-	var re *regexp.Regexp
-	//var ok bool
-	//if expression.Right.Left.Converted != nil {
-	//	re, ok = expression.Right.Left.Converted.(*regexp.Regexp)
-	//}
-	//if !ok || re == nil {
-	var err error
-	re, err = regexp.Compile(rightValue.(string))
+	re, err := limits.compile(fmt.Sprintf("%v", rightValue))
 	if err != nil {
-		return false, fmt.Errorf("failed to compile regular expression %q: %v", rightValue.(string), err)
+		return false, err
 	}
-	//	expression.Right.Left.Converted = re
-	//}
 
-	return re.Match(value.Convert(byteSliceTyp).Interface().([]byte)), nil
+	matched := value.Convert(byteSliceTyp).Interface().([]byte)
+	if err := limits.checkMatch(matched); err != nil {
+		return false, err
+	}
+	return re.Match(matched), nil
+}
+
+func doMatchStartsWith(leftValue interface{}, rightValue interface{}) (bool, error) {
+	value := reflect.Indirect(reflect.ValueOf(leftValue))
+	if value.Kind() != reflect.String {
+		return false, fmt.Errorf("cannot perform startswith/endswith operations on type %s", value.Kind())
+	}
+	return strings.HasPrefix(value.String(), fmt.Sprintf("%v", rightValue)), nil
+}
+
+func doMatchEndsWith(leftValue interface{}, rightValue interface{}) (bool, error) {
+	value := reflect.Indirect(reflect.ValueOf(leftValue))
+	if value.Kind() != reflect.String {
+		return false, fmt.Errorf("cannot perform startswith/endswith operations on type %s", value.Kind())
+	}
+	return strings.HasSuffix(value.String(), fmt.Sprintf("%v", rightValue)), nil
+}
+
+func doMatchLike(leftValue interface{}, rightValue interface{}) (bool, error) {
+	value := reflect.Indirect(reflect.ValueOf(leftValue))
+	if value.Kind() != reflect.String {
+		return false, fmt.Errorf("cannot perform like operations on type %s", value.Kind())
+	}
+	pattern := fmt.Sprintf("%v", rightValue)
+	matched, err := path.Match(pattern, value.String())
+	if err != nil {
+		return false, fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+	}
+	return matched, nil
+}
+
+// checkGlobPatterns checks the glob patterns of the like operators which are
+// literals, so that the invalid ones are rejected when the evaluator is
+// created rather than each time it is evaluated
+func checkGlobPatterns(ast interface{}) error {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return checkGlobPatterns(node.Operand)
+	case *grammar.BinaryExpression:
+		if err := checkGlobPatterns(node.Left); err != nil {
+			return err
+		}
+		return checkGlobPatterns(node.Right)
+	case *grammar.LetExpression:
+		return checkGlobPatterns(node.Body)
+	case *grammar.MatchExpression:
+		if node.Operator != grammar.MatchLike && node.Operator != grammar.MatchNotLike {
+			return nil
+		}
+		if node.Right == nil || !isConstant(node.Right) {
+			return nil
+		}
+		pattern, err := getExprValue(node.Right, nil)
+		if err != nil {
+			return nil
+		}
+		if s, ok := pattern.(string); ok {
+			if _, err := path.Match(s, ""); err != nil {
+				return fmt.Errorf("%s: invalid glob pattern %q: %v", formatExpression(node), s, err)
+			}
+		}
+	}
+	return nil
 }
 
 func doMatchLower(leftValue interface{}, rightValue interface{}) (bool, error) {
@@ -151,23 +237,43 @@ func doMatchLower(leftValue interface{}, rightValue interface{}) (bool, error) {
 	if eqFn == nil {
 		return false, fmt.Errorf("unable to find suitable primitive comparison function for matching %T and %T", leftValue, rightValue)
 	}
+	if isIntegerFloatPair(leftValue, rightValue) {
+		eqFn = doLowerFloat64
+	}
 	return eqFn(leftValue, rightValue), nil
 }
 
 func doMatchEqual(leftValue interface{}, rightValue interface{}) (bool, error) {
+	// null is only ever equal to null
+	if isNull(leftValue) || isNull(rightValue) {
+		return isNull(leftValue) && isNull(rightValue), nil
+	}
 	eqFn := primitiveEqualityFn(leftValue)
 	if eqFn == nil {
 		return false, fmt.Errorf("unable to find suitable primitive comparison function for matching %T and %T", leftValue, rightValue)
 	}
+	if isIntegerFloatPair(leftValue, rightValue) {
+		eqFn = doEqualFloat64
+	}
 	return eqFn(leftValue, rightValue), nil
 }
 
-func doMatchIn(leftValue interface{}, rightValue interface{}) (bool, error) {
+// isIntegerFloatPair reports whether an integer is compared with a float,
+// which must be compared as floats rather than the float being truncated.
+func isIntegerFloatPair(leftValue interface{}, rightValue interface{}) bool {
+	left := reflect.Indirect(reflect.ValueOf(leftValue)).Kind()
+	right := reflect.Indirect(reflect.ValueOf(rightValue)).Kind()
+	return isNumberKind(left) && !isFloatKind(left) && isFloatKind(right)
+}
+
+// doMatchIn reports whether the collection holds the value. With strict
+// types, the elements of interface slices and the keys of interface keyed
+// maps of another type than the value's never match.
+func doMatchIn(leftValue interface{}, rightValue interface{}, strict bool) (bool, error) {
 	value := reflect.ValueOf(leftValue)
 	switch kind := value.Kind(); kind {
 	case reflect.Map:
-		found := value.MapIndex(reflect.ValueOf(rightValue))
-		return found.IsValid(), nil
+		return mapHasKey(value, rightValue, strict), nil
 
 	case reflect.Slice, reflect.Array:
 		itemType := derefType(value.Type().Elem())
@@ -179,8 +285,15 @@ func doMatchIn(leftValue interface{}, rightValue interface{}) (bool, error) {
 			// type/kind and rederiving the match value.
 			for i := 0; i < value.Len(); i++ {
 				item := value.Index(i).Elem()
+				if !item.IsValid() {
+					// nil elements never match
+					continue
+				}
 				itemType := derefType(item.Type())
 				kind := itemType.Kind()
+				if strict && valueClass(itemType) != valueClass(reflect.TypeOf(rightValue)) {
+					continue
+				}
 				// We need to special case errors here. The reason is that in an
 				// interface slice there can be a mix/match of types, but the
 				// coerce functions expect a certain type. So the expression
@@ -221,14 +334,72 @@ func doMatchIn(leftValue interface{}, rightValue interface{}) (bool, error) {
 		}
 
 	case reflect.String:
-		return strings.Contains(value.String(), rightValue.(string)), nil
+		return strings.Contains(value.String(), fmt.Sprintf("%v", rightValue)), nil
 
 	default:
 		return false, fmt.Errorf("cannot perform in/contains operations on type %s", kind)
 	}
 }
 
+// mapHasKey looks the value up in the map after converting it to the key type
+// the same way the equality operator coerces values. Maps keyed by interfaces
+// are searched for a key equal to the value.
+func mapHasKey(m reflect.Value, key interface{}, strict bool) bool {
+	keyType := m.Type().Key()
+	switch keyType.Kind() {
+	case reflect.Interface:
+		iter := m.MapRange()
+		for iter.Next() {
+			k := indirect(iter.Key().Interface())
+			if strict && valueClass(reflect.TypeOf(k)) != valueClass(reflect.TypeOf(key)) {
+				continue
+			}
+			if eqFn := primitiveEqualityFn(k); eqFn != nil && eqFn(k, key) {
+				return true
+			}
+		}
+		return false
+	case reflect.String:
+		key = fmt.Sprintf("%v", key)
+	case reflect.Bool:
+		b, err := CoerceBool(key)
+		if err != nil {
+			return false
+		}
+		key = b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, u, unsigned, err := coerceInteger(key)
+		switch {
+		case err != nil:
+			return false
+		case unsigned:
+			key = u
+		case i < 0 && isUnsignedKind(keyType.Kind()):
+			// negative values are never keys of unsigned maps
+			return false
+		default:
+			key = i
+		}
+	case reflect.Float32, reflect.Float64:
+		f, err := CoerceFloat64(key)
+		if err != nil {
+			return false
+		}
+		key = f
+	}
+
+	k := reflect.ValueOf(key)
+	if !k.IsValid() || !k.Type().ConvertibleTo(keyType) {
+		return false
+	}
+	return m.MapIndex(k.Convert(keyType)).IsValid()
+}
+
 func doMatchIsEmpty(leftValue interface{}) (bool, error) {
+	if isNull(leftValue) {
+		return true, nil
+	}
 	value := reflect.Indirect(reflect.ValueOf(leftValue))
 
 	return value.Len() == 0, nil
@@ -242,8 +413,8 @@ func doMatchIsEmpty(leftValue interface{}) (bool, error) {
 //
 // Returns false if the Selector Path has a length of 1, or if the parent of
 // the Selector's Path is not a map, a pointerstructure.ErrrNotFound error is
-// returned.
-func evaluateNotPresent(ptr pointerstructure.Pointer, datum interface{}) bool {
+// returned. hookFor builds the hook of each lookup of the parent.
+func evaluateNotPresent(ptr taggedPointer, datum interface{}, hookFor func([]string) ValueTransformationHookFn) bool {
 	if len(ptr.Parts) < 2 {
 		return false
 	}
@@ -251,25 +422,117 @@ func evaluateNotPresent(ptr pointerstructure.Pointer, datum interface{}) bool {
 	// Pop the missing leaf part of the path
 	ptr.Parts = ptr.Parts[0 : len(ptr.Parts)-1]
 
-	val, _ := ptr.Get(datum)
+	ptr.Config.ValueTransformationHook = hookFor(ptr.Parts)
+	val, err := ptr.Get(datum)
+	if err != nil {
+		ptr.Config.ValueTransformationHook = hookFor(ptr.Parts)
+		val, _ = getWithStringKeys(ptr, datum)
+	}
 	return reflect.ValueOf(val).Kind() == reflect.Map
 }
 
+// notPresent reports whether the left operand of the operator is handled as
+// a missing key, the outcome of the match being the NotPresentDisposition of
+// the operator, except for the nil values the ordering operators never match,
+// see unordered.
+func notPresent(operator grammar.MatchOperator, leftValue interface{}) bool {
+	if isUndefined(leftValue) {
+		return true
+	}
+	if isNull(leftValue) {
+		switch operator {
+		case grammar.MatchEqual, grammar.MatchNotEqual:
+			// null can be compared with null
+		default:
+			// nil values are handled the same as missing keys
+			return true
+		}
+	}
+	return false
+}
+
+// unordered reports whether the ordering operator is applied to a nil value,
+// which is neither lower nor higher than anything
+func unordered(operator grammar.MatchOperator, leftValue interface{}) bool {
+	switch operator {
+	case grammar.MatchLower, grammar.MatchLowerOrEqual, grammar.MatchHigher, grammar.MatchHigherOrEqual:
+		return isNull(leftValue)
+	default:
+		return false
+	}
+}
+
 func evaluateMatchExpression(expression *grammar.MatchExpression, datum interface{}, opt ...Option) (bool, error) {
 	leftValue, err := getExprValue(expression.Left, datum, opt...)
 	if err != nil {
 		return false, err
 	}
 
-	if isUndefined(leftValue) {
+	opts := getOpts(opt...)
+	if unordered(expression.Operator, leftValue) {
+		return false, nil
+	}
+	if notPresent(expression.Operator, leftValue) {
+		if opts.withLogger != nil {
+			logNotPresent(opts.withLogger, expression, expression.Operator.NotPresentDisposition())
+		}
 		return expression.Operator.NotPresentDisposition(), nil
 	}
 
+	if !isNull(leftValue) {
+		leftValue = indirect(leftValue)
+		if leftValue, _, err = coerceSelector(expression.Left, leftValue, opts.withSelectorCoercions); err != nil {
+			return false, err
+		}
+	}
+	// the right hand side may still be a Comparer
+	var unsupported error
+	if !isNull(leftValue) && !SupportsOperator(expression.Operator, reflect.TypeOf(leftValue)) {
+		unsupported = &UnsupportedOperatorError{Operator: expression.Operator, Type: reflect.TypeOf(leftValue)}
+	}
+
 	rightValue, err := getExprValue(expression.Right, datum, opt...)
+	if unsupported != nil && (err != nil || !isComparisonOperator(expression.Operator)) {
+		return false, unsupported
+	}
+	if err != nil {
+		return false, err
+	}
+	rightValue = indirect(rightValue)
+	rightValue, coerced, err := coerceSelector(expression.Right, rightValue, opts.withSelectorCoercions)
 	if err != nil {
 		return false, err
 	}
 
+	if result, ok, err := doMatchCompare(expression.Operator, leftValue, rightValue); ok {
+		return result, err
+	}
+	if isDecimal(rightValue) {
+		// only the comparison operators compare decimals exactly
+		if r, ok := asRat(rightValue); ok {
+			rightValue, _ = r.Float64()
+		}
+	}
+	if unsupported != nil {
+		return false, unsupported
+	}
+
+	if !coerced {
+		if opts.withLogger != nil && !isNull(leftValue) {
+			logCoercionFallback(opts.withLogger, expression, leftValue, rightValue)
+		}
+		if rightValue, err = coerceTo(coercionTarget(expression.Operator, leftValue), rightValue, opts.withCoercions); err != nil {
+			return false, err
+		}
+	}
+
+	strict := opts.withStrictTypes
+	if strict {
+		if err := checkStrictTypes(expression.Operator, leftValue, rightValue); err != nil {
+			return false, err
+		}
+	}
+
 	//if isUndefined(rightValue) {
 	//	return expression.Operator.NotPresentDisposition(), nil
 	//}
@@ -279,30 +542,26 @@ func evaluateMatchExpression(expression *grammar.MatchExpression, datum interfac
 		return doMatchLower(leftValue, rightValue)
 	case grammar.MatchHigher:
 		result, err := doMatchLower(leftValue, rightValue)
-		if err == nil {
-			if result {
-				return !result, nil
-			}
+		if err != nil || result {
+			return false, err
 		}
 		result, err = doMatchEqual(leftValue, rightValue)
-		if err == nil {
-			return !result, nil
+		if err != nil {
+			return false, err
 		}
-		return true, err
+		return !result, nil
 	case grammar.MatchLowerOrEqual:
-		result, err := doMatchEqual(leftValue, rightValue)
-		if err == nil {
-			if result {
-				return result, nil
-			}
+		result, err := doMatchLower(leftValue, rightValue)
+		if err != nil || result {
+			return result, err
 		}
-		return doMatchLower(leftValue, rightValue)
+		return doMatchEqual(leftValue, rightValue)
 	case grammar.MatchHigherOrEqual:
 		result, err := doMatchLower(leftValue, rightValue)
-		if err == nil {
-			return !result, nil
+		if err != nil {
+			return false, err
 		}
-		return true, nil
+		return !result, nil
 	case grammar.MatchEqual:
 		return doMatchEqual(leftValue, rightValue)
 	case grammar.MatchNotEqual:
@@ -312,9 +571,9 @@ func evaluateMatchExpression(expression *grammar.MatchExpression, datum interfac
 		}
 		return false, err
 	case grammar.MatchIn:
-		return doMatchIn(leftValue, rightValue)
+		return doMatchIn(leftValue, rightValue, strict)
 	case grammar.MatchNotIn:
-		result, err := doMatchIn(leftValue, rightValue)
+		result, err := doMatchIn(leftValue, rightValue, strict)
 		if err == nil {
 			return !result, nil
 		}
@@ -327,10 +586,38 @@ func evaluateMatchExpression(expression *grammar.MatchExpression, datum interfac
 			return !result, nil
 		}
 		return false, err
+	case grammar.MatchIsNull:
+		return isNull(leftValue), nil
+	case grammar.MatchIsNotNull:
+		return !isNull(leftValue), nil
 	case grammar.MatchMatches:
-		return doMatchMatches(leftValue, rightValue)
+		return doMatchMatches(leftValue, rightValue, opts.withRegexLimits)
 	case grammar.MatchNotMatches:
-		result, err := doMatchMatches(leftValue, rightValue)
+		result, err := doMatchMatches(leftValue, rightValue, opts.withRegexLimits)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchStartsWith:
+		return doMatchStartsWith(leftValue, rightValue)
+	case grammar.MatchNotStartsWith:
+		result, err := doMatchStartsWith(leftValue, rightValue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchEndsWith:
+		return doMatchEndsWith(leftValue, rightValue)
+	case grammar.MatchNotEndsWith:
+		result, err := doMatchEndsWith(leftValue, rightValue)
+		if err == nil {
+			return !result, nil
+		}
+		return false, err
+	case grammar.MatchLike:
+		return doMatchLike(leftValue, rightValue)
+	case grammar.MatchNotLike:
+		result, err := doMatchLike(leftValue, rightValue)
 		if err == nil {
 			return !result, nil
 		}
@@ -351,25 +638,100 @@ func getValue(expressionValue *grammar.MatchValue, datum interface{}, opt ...Opt
 	switch expressionValue.Type {
 	case grammar.ValueTypeUndefined:
 		val, err = &undefined, nil
+	case grammar.ValueTypeNull:
+		val, err = nil, nil
 	case grammar.ValueTypeBool:
 		val, err = CoerceBool(expressionValue.Raw)
 
 	case grammar.ValueTypeInt:
 		val, err = CoerceInt64(expressionValue.Raw)
 
+	case grammar.ValueTypeUint:
+		val, err = strconv.ParseUint(expressionValue.Raw, 10, 64)
+
 	case grammar.ValueTypeFloat64:
+		if getOpts(opt...).withDecimal {
+			if r, ok := new(big.Rat).SetString(expressionValue.Raw); ok {
+				return r, nil
+			}
+		}
 		val, err = CoerceFloat64(expressionValue.Raw)
 
+	case grammar.ValueTypeParam:
+		opts := getOpts(opt...)
+		param, ok := opts.withParams[expressionValue.Raw]
+		if !ok {
+			return &undefined, fmt.Errorf("no value bound to parameter $%s", expressionValue.Raw)
+		}
+		return convertNumber(param, opts.withDecimal)
+
+	case grammar.ValueTypeCall:
+		return getCallValue(expressionValue, datum, opt...)
+
 	case grammar.ValueTypeReflect:
+		if len(expressionValue.Selector.Ranges) > 0 {
+			return getRangeValue(expressionValue, datum, opt...)
+		}
+		if path := expressionValue.Selector.Path; len(path) > 0 && !expressionValue.Selector.Anchored {
+			if bound, ok := getOpts(opt...).withBindings[path[0]]; ok {
+				return getBoundValue(bound, path[1:], opt...)
+			}
+		}
+		if value, ok := lookupDefault(getOpts(opt...).withDefaults, expressionValue.Selector.Path); ok {
+			return getDefaultedValue(value, expressionValue, datum, opt...)
+		}
+		if fn := getOpts(opt...).withFieldAccess; fn != nil {
+			return getAccessedValue(fn, expressionValue, datum, opt...)
+		}
+		switch layers := datum.(type) {
+		case chainedDatum:
+			return layers.getValue(expressionValue, opt...)
+		case MergedView:
+			return layers.getValue(expressionValue, opt...)
+		}
 		opts := getOpts(opt...)
-		ptr := pointerstructure.Pointer{
-			Parts: expressionValue.Selector.Path,
-			Config: pointerstructure.Config{
-				TagName:                 opts.withTagName,
-				ValueTransformationHook: opts.withHookFn,
+		if src, ok := datum.(SelectorSource); ok {
+			return getSourceValue(src, expressionValue.Selector, opts)
+		}
+		// the selector hooks are stateful, so each lookup gets its own hook
+		hookFor := func(path []string) ValueTransformationHookFn {
+			return ChainHookFns(
+				valueConverterHookFn(opts.withValueConverters),
+				bsonHookFn,
+				sqlNullHookFn,
+				protoHookFn,
+				selectorHookFn(path, opts.withHookFn, opts.withSelectorHooks),
+			)
+		}
+		// hooks are not called on the datum itself
+		if v := reflect.Indirect(reflect.ValueOf(datum)); v.IsValid() {
+			switch {
+			case isBSONDocument(v.Type()):
+				datum = bsonHookFn(v).Interface()
+			case isProtoMessage(v.Type()):
+				datum = protoHookFn(v).Interface()
+			}
+		}
+		ptr := taggedPointer{
+			Pointer: pointerstructure.Pointer{
+				Parts: expressionValue.Selector.Path,
+				Config: pointerstructure.Config{
+					TagName:                 opts.withTagName,
+					ValueTransformationHook: hookFor(expressionValue.Selector.Path),
+				},
 			},
+			tagNames: opts.withTagNames,
+		}
+		var cached bool
+		if val, cached = opts.withFieldCache.getValue(expressionValue, datum, opts, hookFor(ptr.Parts)); !cached {
+			val, err = ptr.Get(datum)
+		}
+		if errors.Is(err, pointerstructure.ErrNotFound) {
+			ptr.Config.ValueTransformationHook = hookFor(ptr.Parts)
+			if v, retryErr := getWithStringKeys(ptr, datum); retryErr == nil || errors.Is(retryErr, pointerstructure.ErrNotFound) {
+				val, err = v, retryErr
+			}
 		}
-		val, err = ptr.Get(datum)
 		if err != nil {
 			if errors.Is(err, pointerstructure.ErrNotFound) {
 				// Prefer the withUnknown option if set, otherwise defer to NotPresent
@@ -378,125 +740,247 @@ func getValue(expressionValue *grammar.MatchValue, datum interface{}, opt ...Opt
 				case opts.withUnknown != nil:
 					err = nil
 					val = *opts.withUnknown
-				case evaluateNotPresent(ptr, datum):
+				case !opts.withStrictSelectors && evaluateNotPresent(ptr, datum, hookFor):
 					return &undefined, nil
 				}
 			}
 
 			if err != nil {
-				return &undefined, fmt.Errorf("error finding value in datum: %w", err)
-				//return false, fmt.Errorf("error finding value in datum: %w", err)
+				return &undefined, &UnknownSelectorError{Selector: expressionValue.Selector.String(), Err: err}
 			}
 		}
 
-		if jn, ok := val.(json.Number); ok {
-			if jni, err := jn.Int64(); err == nil {
-				val = jni
-			} else if jnf, err := jn.Float64(); err == nil {
-				val = jnf
-			} else {
-				return nil, fmt.Errorf("unable to convert json number %s to int or float", jn)
-			}
+		if isUndefined(val) && opts.withUnknown != nil {
+			// an invalid nullable value of database/sql
+			val = *opts.withUnknown
 		}
+		return convertNumber(val, opts.withDecimal)
 	default:
 		val, err = expressionValue.Raw, nil
 	}
 	return
 }
 
+// getBoundValue looks up the rest of a selector starting with a name bound by
+// a let expression in the value bound
+func getBoundValue(value interface{}, path []string, opt ...Option) (interface{}, error) {
+	if len(path) == 0 || isUndefined(value) {
+		return value, nil
+	}
+	// the names bound and the defaults of the selectors do not apply within
+	// the value, which was resolved and authorized as a whole
+	opt = append(opt[:len(opt):len(opt)], func(o *options) {
+		o.withBindings = nil
+		o.withFieldAccess = nil
+		o.withDefaults = nil
+	})
+	val, err := getValue(&grammar.MatchValue{
+		Type:     grammar.ValueTypeReflect,
+		Selector: grammar.Selector{Type: grammar.SelectorTypeJsonPointer, Path: path},
+	}, value, opt...)
+	if isNotFound(err) && len(path) == 1 && reflect.Indirect(reflect.ValueOf(value)).Kind() == reflect.Map &&
+		!getOpts(opt...).withStrictSelectors {
+		// the value is not the datum: its missing keys are handled as such
+		return &undefined, nil
+	}
+	return val, err
+}
+
+func getSourceValue(src SelectorSource, sel grammar.Selector, opts options) (interface{}, error) {
+	val, found, err := src.GetPath(sel.Path)
+	if err != nil {
+		return &undefined, &UnknownSelectorError{Selector: sel.String(), Err: err}
+	}
+	if !found {
+		if opts.withUnknown != nil {
+			return *opts.withUnknown, nil
+		}
+		if opts.withStrictSelectors {
+			err = fmt.Errorf("%s: %w", (&pointerstructure.Pointer{Parts: sel.Path}).String(), pointerstructure.ErrNotFound)
+			return &undefined, &UnknownSelectorError{Selector: sel.String(), Err: err}
+		}
+		return &undefined, nil
+	}
+	return convertNumber(convertValue(opts.withValueConverters, val), opts.withDecimal)
+}
+
+func convertJSONNumber(val interface{}) (interface{}, error) {
+	jn, ok := val.(json.Number)
+	if !ok {
+		return val, nil
+	}
+	if jni, err := jn.Int64(); err == nil {
+		return jni, nil
+	}
+	if jnu, err := strconv.ParseUint(string(jn), 10, 64); err == nil {
+		return jnu, nil
+	}
+	if jnf, err := jn.Float64(); err == nil {
+		return jnf, nil
+	}
+	return nil, fmt.Errorf("unable to convert json number %s to int or float", jn)
+}
+
 func getExprValue(expression *grammar.ExpressionValue, datum interface{}, opt ...Option) (val interface{}, err error) {
-	var lvalue, rvalue, opvalue interface{}
+	var lvalue, rvalue interface{}
 
 	if expression == nil {
 		return nil, nil
 	}
 
-	lvalue, err = evaluate(expression.Left, datum, opt...)
+	lvalue, err = evaluateValue(expression.Left, datum, opt...)
 	if err != nil {
 		return lvalue, err
 	}
 	if expression.Right != nil {
-		rvalue, err = evaluate(expression.Right, datum, opt...)
+		rvalue, err = evaluateValue(expression.Right, datum, opt...)
 		if err != nil {
 			return rvalue, err
 		}
 	}
 
-	switch expression.Operator {
-	case grammar.MathOpValue:
+	if expression.Operator == grammar.MathOpValue {
 		return lvalue, err
-	case grammar.MathOpPlus:
-		switch rvalue.(type) {
-		case bool:
-			opvalue = lvalue.(bool) && rvalue.(bool)
-		case int, int64:
-			opvalue = lvalue.(int64) + rvalue.(int64)
-		case float64:
-			opvalue = lvalue.(float64) + rvalue.(float64)
-		case string:
-			opvalue = lvalue.(string) + rvalue.(string)
-		default:
-			return nil, fmt.Errorf("unknown types %T for math op", rvalue)
-		}
-	case grammar.MathOpMinus:
-		switch rvalue.(type) {
-		case int, int64:
-			opvalue = lvalue.(int64) - rvalue.(int64)
-		case float64:
-			opvalue = lvalue.(float64) - rvalue.(float64)
-		default:
-			return nil, fmt.Errorf("unknown types %T for math op", rvalue)
-		}
-	case grammar.MathOpMul:
-		switch rvalue.(type) {
-		case int, int64:
-			opvalue = lvalue.(int64) * rvalue.(int64)
-		case float64:
-			opvalue = lvalue.(float64) * rvalue.(float64)
-		default:
-			return nil, fmt.Errorf("unknown types %T for math op", rvalue)
-		}
-	case grammar.MathOpDiv:
-		switch rvalue.(type) {
-		case int, int64:
-			opvalue = lvalue.(int64) / rvalue.(int64)
-		case float64:
-			opvalue = lvalue.(float64) / rvalue.(float64)
-		default:
-			return nil, fmt.Errorf("unknown types %T for math op", rvalue)
+	}
+	return doMath(expression.Operator, lvalue, rvalue, getOpts(opt...).withDecimal)
+}
+
+// doMath applies the math operator to the operands. Integers are computed as
+// int64 unless either operand is a float, in which case float64 is used.
+// Strings can be concatenated and booleans and'ed with the plus operator.
+// Missing and null operands make the result missing or null respectively.
+// Arbitrary precision numbers, and floats with decimal arithmetic, are
+// computed as big.Rat.
+func doMath(op grammar.MathOperator, lvalue, rvalue interface{}, decimal bool) (interface{}, error) {
+	switch {
+	case isUndefined(lvalue) || isUndefined(rvalue):
+		return &undefined, nil
+	case isNull(lvalue) || isNull(rvalue):
+		return nil, nil
+	}
+	lvalue, rvalue = indirect(lvalue), indirect(rvalue)
+	if l, r, ok := decimalOperands(lvalue, rvalue, decimal); ok {
+		return doDecimalMath(op, l, r)
+	}
+	lkind, rkind := reflect.ValueOf(lvalue).Kind(), reflect.ValueOf(rvalue).Kind()
+
+	switch {
+	case lkind == reflect.Bool && rkind == reflect.Bool && op == grammar.MathOpPlus:
+		return reflect.ValueOf(lvalue).Bool() && reflect.ValueOf(rvalue).Bool(), nil
+	case lkind == reflect.String && rkind == reflect.String && op == grammar.MathOpPlus:
+		return reflect.ValueOf(lvalue).String() + reflect.ValueOf(rvalue).String(), nil
+	case isNumberKind(lkind) && isNumberKind(rkind):
+		if isFloatKind(lkind) || isFloatKind(rkind) {
+			l, err := CoerceFloat64(lvalue)
+			if err != nil {
+				return nil, err
+			}
+			r, err := CoerceFloat64(rvalue)
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case grammar.MathOpPlus:
+				return l + r, nil
+			case grammar.MathOpMinus:
+				return l - r, nil
+			case grammar.MathOpMul:
+				return l * r, nil
+			case grammar.MathOpDiv:
+				return l / r, nil
+			}
+		} else {
+			l, lu, lunsigned, err := coerceInteger(lvalue)
+			if err != nil {
+				return nil, err
+			}
+			r, ru, runsigned, err := coerceInteger(rvalue)
+			if err != nil {
+				return nil, err
+			}
+			if lunsigned || runsigned || int64Overflows(op, l, r) {
+				return doWideIntegerMath(op, l, lu, lunsigned, r, ru, runsigned)
+			}
+			switch op {
+			case grammar.MathOpPlus:
+				return l + r, nil
+			case grammar.MathOpMinus:
+				return l - r, nil
+			case grammar.MathOpMul:
+				return l * r, nil
+			case grammar.MathOpDiv:
+				if r == 0 {
+					return nil, errors.New("integer division by zero")
+				}
+				return l / r, nil
+			}
 		}
 	}
-	return opvalue, nil
+	return nil, fmt.Errorf("cannot perform math operation %q on values of type %T and %T", op, lvalue, rvalue)
+}
+
+// evaluateValue resolves an operand of an ExpressionValue. Unlike evaluate it
+// does not collapse undefined values into false, so that match operators can
+// still apply their NotPresentDisposition.
+func evaluateValue(ast interface{}, datum interface{}, opt ...Option) (interface{}, error) {
+	switch node := ast.(type) {
+	case *grammar.MatchValue:
+		return getValue(node, datum, opt...)
+	case *grammar.ExpressionValue:
+		return getExprValue(node, datum, opt...)
+	default:
+		return evaluate(ast, datum, opt...)
+	}
+}
+
+func evaluate(ast interface{}, datum interface{}, opt ...Option) (interface{}, error) {
+	return evaluateContext(context.Background(), ast, datum, opt...)
 }
 
-func evaluate(ast interface{}, datum interface{}, opt ...Option) (result interface{}, err error) {
+// evaluateContext evaluates the AST, checking whether the context is done
+// before evaluating each node.
+func evaluateContext(ctx context.Context, ast interface{}, datum interface{}, opt ...Option) (result interface{}, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
 	switch node := ast.(type) {
 	case *grammar.UnaryExpression:
 		switch node.Operator {
 		case grammar.UnaryOpNot:
-			result, err = evaluate(node.Operand, datum, opt...)
-			return !result.(bool), err
+			result, err = evaluateContext(ctx, node.Operand, datum, opt...)
+			if err != nil {
+				return false, err
+			}
+			return !result.(bool), nil
 		}
 	case *grammar.BinaryExpression:
 		switch node.Operator {
 		case grammar.BinaryOpAnd:
-			result, err = evaluate(node.Left, datum, opt...)
+			result, err = evaluateContext(ctx, node.Left, datum, opt...)
 			if err != nil || !result.(bool) {
 				return result, err
 			}
 
-			return evaluate(node.Right, datum, opt...)
+			return evaluateContext(ctx, node.Right, datum, opt...)
 
 		case grammar.BinaryOpOr:
-			result, err = evaluate(node.Left, datum, opt...)
+			result, err = evaluateContext(ctx, node.Left, datum, opt...)
 			if err != nil || result.(bool) {
 				return result, err
 			}
 
-			return evaluate(node.Right, datum, opt...)
+			return evaluateContext(ctx, node.Right, datum, opt...)
 		}
+	case *grammar.LetExpression:
+		value, err := getExprValue(node.Value, datum, opt...)
+		if err != nil {
+			return false, letError(node, err)
+		}
+		return evaluateContext(ctx, node.Body, datum, append(opt[:len(opt):len(opt)], withBinding(node.Name, value))...)
 	case *grammar.MatchExpression:
 		result, err = evaluateMatchExpression(node, datum, opt...)
+		err = matchError(node, err)
 	case *grammar.ExpressionValue:
 		result, err = getExprValue(node, datum, opt...)
 	case *grammar.MatchValue:
@@ -509,3 +993,57 @@ func evaluate(ast interface{}, datum interface{}, opt ...Option) (result interfa
 	}
 	return result, err
 }
+
+// int64Overflows reports whether the math operator overflows an int64
+func int64Overflows(op grammar.MathOperator, l, r int64) bool {
+	switch op {
+	case grammar.MathOpPlus:
+		return (r > 0 && l > math.MaxInt64-r) || (r < 0 && l < math.MinInt64-r)
+	case grammar.MathOpMinus:
+		return (r < 0 && l > math.MaxInt64+r) || (r > 0 && l < math.MinInt64+r)
+	case grammar.MathOpMul:
+		return l != 0 && r != 0 && ((l*r)/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64))
+	case grammar.MathOpDiv:
+		return l == math.MinInt64 && r == -1
+	}
+	return false
+}
+
+// doWideIntegerMath applies the math operator to integers either of which is
+// above math.MaxInt64, or whose result overflows an int64. The result is an
+// int64 when it fits, a uint64 when it is above math.MaxInt64, and an error
+// when it overflows a uint64 or is below math.MinInt64.
+func doWideIntegerMath(op grammar.MathOperator, l int64, lu uint64, lunsigned bool, r int64, ru uint64, runsigned bool) (interface{}, error) {
+	left, right := big.NewInt(l), big.NewInt(r)
+	if lunsigned {
+		left.SetUint64(lu)
+	}
+	if runsigned {
+		right.SetUint64(ru)
+	}
+
+	result := new(big.Int)
+	switch op {
+	case grammar.MathOpPlus:
+		result.Add(left, right)
+	case grammar.MathOpMinus:
+		result.Sub(left, right)
+	case grammar.MathOpMul:
+		result.Mul(left, right)
+	case grammar.MathOpDiv:
+		if right.Sign() == 0 {
+			return nil, errors.New("integer division by zero")
+		}
+		result.Quo(left, right)
+	default:
+		return nil, fmt.Errorf("cannot perform math operation %q on integers", op)
+	}
+
+	switch {
+	case result.IsInt64():
+		return result.Int64(), nil
+	case result.IsUint64():
+		return result.Uint64(), nil
+	}
+	return nil, fmt.Errorf("integer overflow in %v %s %v", left, op, right)
+}
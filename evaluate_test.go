@@ -4,11 +4,15 @@
 package bexpr
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/gterranova/go-bexpr/grammar"
 	"github.com/mitchellh/pointerstructure"
 	"github.com/stretchr/testify/require"
 )
@@ -138,12 +142,29 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "ColonString == `expo:rted`", result: true},
 			{expression: "ColonString != `expor:ted`", result: true},
 			{expression: "slash/value == `hello`", result: true},
-			{expression: "unexported == `unexported`", result: false, err: `error finding value in datum: /unexported at part 0: couldn't find key: struct field with name "unexported"`},
-			{expression: "Hidden == false", result: false, err: "error finding value in datum: /Hidden at part 0: struct field \"Hidden\" is ignored and cannot be used"},
+			{expression: "unexported == `unexported`", result: false, err: `1:1 (0): unexported == "unexported": error finding value in datum: /unexported at part 0: couldn't find key: struct field with name "unexported"`},
+			{expression: "Hidden == false", result: false, err: `1:1 (0): Hidden == false: error finding value in datum: /Hidden at part 0: struct field "Hidden" is ignored and cannot be used`},
 			{expression: "String matches 	`^ex.*`", result: true, benchQuick: true},
 			{expression: "String not matches `^anchored.*`", result: true, benchQuick: true},
 			{expression: "String matches 	`^anchored.*`", result: false},
 			{expression: "String not matches `^ex.*`", result: false},
+			{expression: "String startswith `exp`", result: true, benchQuick: true},
+			{expression: "String startswith `port`", result: false},
+			{expression: "String not startswith `port`", result: true},
+			{expression: "String not startswith `exp`", result: false},
+			{expression: "String endswith `ted`", result: true, benchQuick: true},
+			{expression: "String endswith `exp`", result: false},
+			{expression: "String not endswith `exp`", result: true},
+			{expression: "String not endswith `ted`", result: false},
+			{expression: "Int startswith `-`", result: false, err: `1:1 (0): Int startswith "-": operator "Starts With" cannot be used with values of type int`},
+			{expression: "String like `ex*ted`", result: true, benchQuick: true},
+			{expression: "String like `ex?orted`", result: true},
+			{expression: "String like `[a-e]x*`", result: true},
+			{expression: "String like `ex*`", result: true},
+			{expression: "String like `port*`", result: false},
+			{expression: "String not like `port*`", result: true},
+			{expression: "String not like `ex*`", result: false},
+			{expression: "Int like `*`", result: false, err: `1:1 (0): Int like "*": operator "Like" cannot be used with values of type int`},
 		},
 	},
 	"Flat Struct Alt Types": {
@@ -221,8 +242,8 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "String == `not-it`", result: false, benchQuick: true},
 			{expression: "String != `exported`", result: false},
 			{expression: "String != `not-it`", result: true},
-			{expression: "unexported == `unexported`", result: false, err: `error finding value in datum: /unexported at part 0: couldn't find key: struct field with name "unexported"`},
-			{expression: "Hidden == false", result: false, err: "error finding value in datum: /Hidden at part 0: struct field \"Hidden\" is ignored and cannot be used"},
+			{expression: "unexported == `unexported`", result: false, err: `1:1 (0): unexported == "unexported": error finding value in datum: /unexported at part 0: couldn't find key: struct field with name "unexported"`},
+			{expression: "Hidden == false", result: false, err: `1:1 (0): Hidden == false: error finding value in datum: /Hidden at part 0: struct field "Hidden" is ignored and cannot be used`},
 		},
 	},
 	"map[string]map[string]bool": {
@@ -265,7 +286,7 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "foo.bar != false", result: true},
 			{expression: "foo.baz != false", result: false},
 			{expression: "foo.baz != true", result: true},
-			{expression: "foo.bar.baz == 3", result: false, err: `error finding value in datum: /foo/bar/baz: at part 2, invalid value kind: bool`},
+			{expression: "foo.bar.baz == 3", result: false, err: `1:1 (0): foo.bar.baz == 3: error finding value in datum: /foo/bar/baz: at part 2, invalid value kind: bool`},
 		},
 	},
 	"Nested Structs and Maps": {
@@ -336,11 +357,11 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "Nested.Map contains \"nope\" or (Nested.Map contains \"bar\" and Nested.Map.bar == `bazel`) or TopInt != 0", result: true, benchQuick: true},
 			{expression: "Nested.MapOfStructs.one.Foo == 42", result: true},
 			{expression: "7 in Nested.SliceOfInts", result: true},
-			{expression: `"/Nested/SliceOfInts" == "7"`, result: false, err: `unable to find suitable primitive comparison function for matching []int and string`},
+			{expression: `"/Nested/SliceOfInts" == "7"`, result: false, err: `1:1 (0): Nested.SliceOfInts == "7": operator "Equal" cannot be used with values of type []int`},
 			{expression: "Nested.MapOfStructs is empty or (Nested.SliceOfInts contains 7 and 9 in Nested.SliceOfInts)", result: true, benchQuick: true},
 			{expression: "Nested.SliceOfStructs.0.X == 1", result: true},
 			{expression: "Nested.SliceOfStructs.0.Y == 4", result: false},
-			{expression: "\"Map\" in Nested", result: false, err: "Cannot perform in/contains operations on type struct"},
+			{expression: "\"Map\" in Nested", result: false, err: `1:1 (0): Nested contains "Map": operator "In" cannot be used with values of type bexpr.testNestedLevel1`},
 			{expression: `"foobar" in "/Nested/SliceOfInfs"`, result: true},
 			{expression: `"1" in "/Nested/SliceOfInfs"`, result: true},
 			{expression: `"2" in "/Nested/SliceOfInfs"`, result: false},
@@ -355,15 +376,72 @@ var evaluateTests map[string]expressionTest = map[string]expressionTest{
 			{expression: "Nested.Map.notfound is not empty", result: false},
 			{expression: `Nested.Map.notfound matches ".*"`, result: false},
 			{expression: `Nested.Map.notfound not matches ".*"`, result: true},
+			{expression: `Nested.Map.notfound startswith "a"`, result: false},
+			{expression: `Nested.Map.notfound not startswith "a"`, result: true},
+			{expression: `Nested.Map.notfound endswith "a"`, result: false},
+			{expression: `Nested.Map.notfound not endswith "a"`, result: true},
+			{expression: `Nested.Map.notfound like "*"`, result: false},
+			{expression: `Nested.Map.notfound not like "*"`, result: true},
 			// Missing field in struct tests
-			{expression: "Nested.Notfound == 4", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: "Nested.Notfound != 4", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: "4 in Nested.Notfound", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: "4 not in Nested.Notfound", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: "Nested.Notfound is empty", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: "Nested.Notfound is not empty", result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: `Nested.Notfound matches ".*"`, result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
-			{expression: `Nested.Notfound not matches ".*"`, result: false, err: `error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "Nested.Notfound == 4", result: false, err: `1:1 (0): Nested.Notfound == 4: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "Nested.Notfound != 4", result: false, err: `1:1 (0): Nested.Notfound != 4: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "4 in Nested.Notfound", result: false, err: `1:1 (0): Nested.Notfound contains 4: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "4 not in Nested.Notfound", result: false, err: `1:1 (0): Nested.Notfound not contains 4: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "Nested.Notfound is empty", result: false, err: `1:1 (0): Nested.Notfound is empty: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: "Nested.Notfound is not empty", result: false, err: `1:1 (0): Nested.Notfound is not empty: error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: `Nested.Notfound matches ".*"`, result: false, err: `1:1 (0): Nested.Notfound matches ".*": error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+			{expression: `Nested.Notfound not matches ".*"`, result: false, err: `1:1 (0): Nested.Notfound not matches ".*": error finding value in datum: /Nested/Notfound at part 1: couldn't find key: struct field with name "Notfound"`},
+		},
+	},
+	"Nullable Fields": {
+		testNullableStruct{
+			Ptr:   func() *int { i := 1; return &i }(),
+			Slice: []string{},
+			Map: map[string]interface{}{
+				"nil":   nil,
+				"value": "foo",
+			},
+			Iface: "set",
+		},
+		[]expressionCheck{
+			{expression: "Ptr is null", result: false},
+			{expression: "Ptr is not null", result: true},
+			{expression: "NilPtr is null", result: true, benchQuick: true},
+			{expression: "NilPtr is not null", result: false},
+			{expression: "NilPtr == null", result: true},
+			{expression: "NilPtr != null", result: false},
+			{expression: "NilPtr == 1", result: false},
+			{expression: "NilPtr is empty", result: true},
+			// nil values are neither lower nor higher than anything
+			{expression: "NilPtr < 3", result: false},
+			{expression: "NilPtr <= -100", result: false},
+			{expression: "NilPtr > 3", result: false},
+			{expression: "NilPtr >= -100", result: false},
+			{expression: "not NilPtr < 3", result: true},
+			{expression: "Slice is null", result: false},
+			{expression: "Slice is empty", result: true},
+			{expression: "NilSlice is null", result: true},
+			{expression: "NilSlice is empty", result: true},
+			{expression: "NilMap is null", result: true},
+			{expression: "Iface is null", result: false},
+			{expression: "Iface == null", result: false},
+			{expression: "NilIface is null", result: true},
+			{expression: "NilIface == null", result: true},
+			{expression: "Map.nil is null", result: true},
+			{expression: "Map.nil == null", result: true},
+			{expression: `Map.nil == "foo"`, result: false},
+			{expression: "Map.nil < 3", result: false},
+			{expression: "Map.nil >= 3", result: false},
+			{expression: "Map.value is null", result: false},
+			{expression: "Map.value is not null", result: true},
+			// missing keys are null but are not equal to null
+			{expression: "Map.missing is null", result: true, benchQuick: true},
+			{expression: "Map.missing is not null", result: false},
+			{expression: "Map.missing == null", result: false},
+			{expression: "Map.missing != null", result: true},
+			{expression: "Map.missing < 3", result: true},
+			{expression: "Map.missing >= 3", result: false},
+			{expression: "Missing is null", result: false, err: `1:1 (0): Missing is null: error finding value in datum: /Missing at part 0: couldn't find key: struct field with name "Missing"`},
 		},
 	},
 }
@@ -383,7 +461,7 @@ func TestEvaluate(t *testing.T) {
 				t.Run(fmt.Sprintf("#%d - %s", i, expTest.expression), func(t *testing.T) {
 					t.Parallel()
 
-					expr, err := CreateEvaluator(expTest.expression, WithHookFn(expTest.hook))
+					expr, err := CreateEvaluator(expTest.expression, WithHookFn(expTest.hook), WithMutationCheck())
 					require.NoError(t, err)
 
 					match, err := expr.Evaluate(tcase.value)
@@ -435,7 +513,7 @@ func TestWithHookFn(t *testing.T) {
 				{expression: `"/I/I"=="bar"`, result: true},
 				{
 					expression: `"/S/I"=="foo"`, result: false,
-					err: "error finding value in datum: /S/I: at part 1, invalid value kind: string",
+					err: `1:1 (0): S.I == "foo": error finding value in datum: /S/I: at part 1, invalid value kind: string`,
 				},
 			},
 		},
@@ -446,7 +524,7 @@ func TestWithHookFn(t *testing.T) {
 			eval: []expressionCheck{
 				{
 					expression: `"/I"=="foo"`, result: false,
-					err: "error finding value in datum: /I at part 0: ValueTransformationHook returned the value of a nil interface",
+					err: `1:1 (0): I == "foo": error finding value in datum: /I at part 0: ValueTransformationHook returned the value of a nil interface`,
 				},
 			},
 		},
@@ -571,6 +649,51 @@ func TestUnknownVal_struct(t *testing.T) {
 	}
 }
 
+func TestStrictSelectors(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Name": "web",
+		"Meta": map[string]interface{}{"env": "prod", "owner": nil},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"found":         {expression: `Name == "web" and Meta.env == "prod"`, result: true},
+		"null":          {expression: `Meta.owner == null`, result: true},
+		"missing key":   {expression: `Meta.region == "eu"`, err: `1:1 (0): Meta.region == "eu": error finding value in datum: /Meta/region at part 1: couldn't find key "region"`},
+		"negated":       {expression: `Meta.region != "eu"`, err: `1:1 (0): Meta.region != "eu": error finding value in datum: /Meta/region at part 1: couldn't find key "region"`},
+		"right operand": {expression: `Name == Meta.name`, err: `1:1 (0): Name == Meta.name: error finding value in datum: /Meta/name at part 1: couldn't find key "name"`},
+		"let":           {expression: `let m = Meta in m.region is empty`, err: `1:17 (16): m.region is empty: error finding value in datum: /region at part 0: couldn't find key "region"`},
+		"default":       {expression: `Meta.region == "eu"`, opts: []Option{WithDefaults(map[string]interface{}{"Meta.region": "eu"})}, result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append(tcase.opts, WithStrictSelectors())...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				require.ErrorIs(t, err, ErrUnknownSelector)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
 func TestCustomTag(t *testing.T) {
 	t.Parallel()
 
@@ -670,3 +793,180 @@ func BenchmarkEvaluate(b *testing.B) {
 		})
 	}
 }
+
+// countingSource counts the lookups of each selector
+type countingSource struct {
+	datum   map[string]interface{}
+	lookups map[string]int
+}
+
+func (s *countingSource) GetPath(path []string) (interface{}, bool, error) {
+	s.lookups[strings.Join(path, ".")]++
+	value, err := pointerstructure.Get(s.datum, "/"+strings.Join(path, "/"))
+	if err != nil {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+func TestLetExpression(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+		// lookups is the number of lookups of Meta.tier
+		lookups int
+	}
+
+	datum := map[string]interface{}{
+		"Meta": map[string]interface{}{
+			"tier":  "platinum",
+			"zones": map[string]interface{}{"eu": 2},
+		},
+		"Port": 8080,
+	}
+
+	tests := map[string]testCase{
+		"bound once": {
+			expression: `let t = Meta.tier in t == "gold" or t == "platinum"`,
+			result:     true,
+			lookups:    1,
+		},
+		"continued selector": {
+			expression: `let z = Meta.zones in z.eu == 2 and z.us is empty`,
+			result:     true,
+		},
+		"math": {
+			expression: `let p = Port + 1 in p > 8080 and p < 8082`,
+			result:     true,
+		},
+		"missing value": {
+			expression: `let o = Meta.owner in o is null and o != "x"`,
+			result:     true,
+		},
+		"shadowing": {
+			expression: `let t = Port in (let t = Meta.tier in t == "platinum") and t == 8080`,
+			result:     true,
+			lookups:    1,
+		},
+		"name not a selector of the datum": {
+			expression: `let Port = 1 in Port == 1`,
+			result:     true,
+		},
+		"scoped to the body": {
+			expression: `(let t = Meta.tier in t == "platinum") and t == "platinum"`,
+			result:     false,
+			lookups:    1,
+		},
+		"value error": {
+			expression: `let t = Meta.tier * 2 in t == "x"`,
+			err:        `cannot perform math operation`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			// the outcome of the inlined expression is the same
+			inlined := *eval
+			inlined.ast = grammar.InlineLets(eval.ast)
+
+			for _, e := range []*Evaluator{eval, &inlined} {
+				src := &countingSource{datum: datum, lookups: make(map[string]int)}
+				result, err := e.Evaluate(src)
+				if tcase.err != "" {
+					require.Error(t, err)
+					require.Contains(t, err.Error(), tcase.err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tcase.result, result)
+				if e == eval {
+					require.Equal(t, tcase.lookups, src.lookups["Meta.tier"])
+				}
+			}
+		})
+	}
+}
+
+func TestUnsignedIntegers(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	datum := map[string]interface{}{
+		"Max":      uint64(math.MaxUint64),
+		"Big":      uint64(math.MaxInt64) + 1,
+		"Small":    uint32(7),
+		"Negative": int64(-1),
+		"Text":     "18446744073709551615",
+		"ByID":     map[uint64]string{math.MaxUint64: "max"},
+		"IDs":      []uint64{1, math.MaxUint64},
+	}
+
+	tests := map[string]testCase{
+		"equal":                   {expression: `Max == 18446744073709551615`, result: true},
+		"not equal":               {expression: `Max != 18446744073709551614`, result: true},
+		"lower":                   {expression: `Big < Max`, result: true},
+		"higher":                  {expression: `Max > 9223372036854775807`, result: true},
+		"higher or equal":         {expression: `Big >= 9223372036854775808`, result: true},
+		"unsigned above negative": {expression: `Negative < Big`, result: true},
+		"small unsigned":          {expression: `Small > -1`, result: true},
+		"string coerced":          {expression: `Max == Text`, result: true},
+		"map key":                 {expression: `18446744073709551615 in ByID`, result: true},
+		"negative map key":        {expression: `-1 in ByID`, result: false},
+		"slice":                   {expression: `18446744073709551615 in IDs`, result: true},
+		"sum":                     {expression: `Big + 9223372036854775807 == Max`, result: true},
+		"difference":              {expression: `Max - Big == 9223372036854775807`, result: true},
+		"negative difference":     {expression: `0 - Big == -9223372036854775808`, result: true},
+		"below int64":             {expression: `Negative - Big < 0`, err: `1:1 (0): Negative - Big < 0: integer overflow in -1 - 9223372036854775808`},
+		"int64 overflow":          {expression: `9223372036854775807 * 2 == 18446744073709551614`, result: true},
+		"division":                {expression: `Max / 3 == 6148914691236517205`, result: true},
+		"folded literals":         {expression: `9223372036854775807 + 1 == Big`, result: true},
+		"overflow":                {expression: `Max + 1 > 0`, err: `1:1 (0): Max + 1 > 0: integer overflow in 18446744073709551615 + 1`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestOrderingNull(t *testing.T) {
+	t.Parallel()
+
+	var datum interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"X": null, "Meta": {"size": null}}`), &datum))
+
+	for _, expression := range []string{`X < 3`, `X <= -100`, `X > 3`, `X >= -100`, `Meta.size < 3`, `Meta.size >= 3`} {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		result, err := eval.Evaluate(datum)
+		require.NoError(t, err)
+		require.Equal(t, false, result, expression)
+	}
+}
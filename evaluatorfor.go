@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.24
+
+package bexpr
+
+// EvaluatorFor is the evaluator of an expression for the values of type T,
+// created with Compile, which validates the selectors and operators of the
+// expression against T, so that its Evaluate takes a T and fails on the
+// values only rather than on the expression.
+type EvaluatorFor[T any] = TypedEvaluator[T]
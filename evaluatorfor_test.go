@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.24
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatorFor(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string
+		Port int
+	}
+
+	var eval *EvaluatorFor[service]
+	eval, err := Compile[service](`Port > 1024 and Name != "ssh"`)
+	require.NoError(t, err)
+
+	match, err := eval.Evaluate(service{Name: "web", Port: 8080})
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = eval.Evaluate(service{Name: "ssh", Port: 2222})
+	require.NoError(t, err)
+	require.False(t, match)
+
+	_, err = Compile[service](`Address == "10.0.0.1"`)
+	require.EqualError(t, err, `error finding value in schema: /Address at part 0: couldn't find key: struct field with name "Address"`)
+}
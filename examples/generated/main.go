@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+//go:generate go run ../../cmd/bexpr-gen -type Service,Meta
+
+import (
+	"fmt"
+
+	bexpr "github.com/gterranova/go-bexpr"
+)
+
+type Service struct {
+	Name    string
+	Port    int
+	Weight  float64
+	Enabled bool
+	Env     string            `bexpr:"env"`
+	Secret  string            `bexpr:"-"`
+	Labels  map[string]string `bexpr:"labels"`
+	Meta    Meta
+	Parent  *Service
+	Tags    []string
+}
+
+type Meta struct {
+	Region  string
+	Zone    uint8
+	Version float32
+}
+
+func main() {
+	services := []*Service{
+		{Name: "web", Port: 80, Env: "prod", Meta: Meta{Region: "eu"}},
+		{Name: "db", Port: 5432, Env: "prod", Meta: Meta{Region: "us"}},
+		{Name: "web", Port: 8080, Env: "dev", Meta: Meta{Region: "eu"}},
+	}
+
+	// the pointers to the services resolve the selectors with the accessors
+	// generated by bexpr-gen, without reflection
+	eval, err := bexpr.CreateEvaluator(`env == "prod" and Meta.Region == "eu"`)
+	if err != nil {
+		fmt.Printf("Failed to create evaluator for expression: %v\n", err)
+		return
+	}
+	for _, service := range services {
+		result, err := eval.Evaluate(service)
+		if err != nil {
+			fmt.Printf("Failed to evaluate the expression: %v\n", err)
+			return
+		}
+		fmt.Printf("%s:%d -> %v\n", service.Name, service.Port, result)
+	}
+}
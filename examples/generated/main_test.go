@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"testing"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated checks that the accessors generated resolve the selectors the
+// way reflection does, the services being evaluated both as pointers, which
+// are selector sources, and as values, which are not
+func TestGenerated(t *testing.T) {
+	t.Parallel()
+
+	service := Service{
+		Name:    "web",
+		Port:    80,
+		Weight:  0.5,
+		Enabled: true,
+		Env:     "prod",
+		Secret:  "s",
+		Labels:  map[string]string{"team": "a"},
+		Meta:    Meta{Region: "eu", Zone: 3, Version: 1.5},
+		Parent:  &Service{Name: "lb", Meta: Meta{Region: "us"}},
+		Tags:    []string{"x"},
+	}
+
+	expressions := []string{
+		`Name == "web"`,
+		`Name != "web" or Port > 79.5`,
+		`Port >= 80 and Weight < 1`,
+		`Enabled == true`,
+		`env == "prod"`,
+		`Env == "prod"`,
+		`Secret == "s"`,
+		`labels.team == "a"`,
+		`labels.missing == "a"`,
+		`labels.missing != "a"`,
+		`labels.team.x == "a"`,
+		`Meta.Region == "eu" and Meta.Zone == 3`,
+		`Meta.Version == 1.5`,
+		`Meta.Missing == 1`,
+		`Parent.Name == "lb"`,
+		`Parent.Meta.Region != "eu"`,
+		`Parent.Parent.Name == "x"`,
+		`Tags.0 == "x"`,
+		`"x" in Tags`,
+		`Name.x == "y"`,
+		`Missing == 1`,
+		`Name matches "^w"`,
+		`Port + 1 == 81`,
+	}
+	for _, expression := range expressions {
+		eval, err := bexpr.CreateEvaluator(expression)
+		require.NoError(t, err)
+		expected, expectedErr := eval.Evaluate(service)
+		result, err := eval.Evaluate(&service)
+		require.Equal(t, expected, result, expression)
+		require.Equal(t, expectedErr, err, expression)
+	}
+}
+
+func TestGenerated_Allocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocations are not measured in short mode")
+	}
+
+	service := &Service{Name: "web", Port: 80, Labels: map[string]string{"team": "a"}, Meta: Meta{Zone: 3}}
+	eval, err := bexpr.CreateEvaluator(`Name == "web" and Port > 8 and labels.team == "a" and Meta.Zone == 3`)
+	require.NoError(t, err)
+	allocs := testing.AllocsPerRun(100, func() {
+		if result, err := eval.Evaluate(service); err != nil || result != true {
+			t.Fatalf("unexpected result %v, %v", result, err)
+		}
+	})
+	require.Zero(t, allocs)
+}
@@ -0,0 +1,145 @@
+// Code generated by bexpr-gen; DO NOT EDIT.
+
+package main
+
+import bexpr "github.com/gterranova/go-bexpr"
+
+// GetPath implements bexpr.SelectorSource
+func (v *Service) GetPath(path []string) (interface{}, bool, error) {
+	if len(path) == 0 {
+		return v, true, nil
+	}
+	switch path[0] {
+	case "Enabled":
+		if len(path) == 1 {
+			return v.Enabled, true, nil
+		}
+	case "Meta":
+		if len(path) == 1 {
+			return v.Meta, true, nil
+		}
+		if value, ok, err := v.Meta.GetPath(path[1:]); err == nil {
+			return value, ok, nil
+		}
+	case "Name":
+		if len(path) == 1 {
+			return v.Name, true, nil
+		}
+	case "Parent":
+		if len(path) == 1 {
+			return v.Parent, true, nil
+		}
+		if v.Parent != nil {
+			if value, ok, err := v.Parent.GetPath(path[1:]); err == nil {
+				return value, ok, nil
+			}
+		}
+	case "Port":
+		if len(path) == 1 {
+			return v.Port, true, nil
+		}
+	case "Weight":
+		if len(path) == 1 {
+			return v.Weight, true, nil
+		}
+	case "env":
+		if len(path) == 1 {
+			return v.Env, true, nil
+		}
+	case "labels":
+		switch len(path) {
+		case 1:
+			return v.Labels, true, nil
+		case 2:
+			value, ok := v.Labels[path[1]]
+			return value, ok, nil
+		}
+	}
+	// the other fields are resolved with reflection, which reports the errors
+	// with the whole path
+	return bexpr.LookupPath(*v, path, bexpr.WithTagName("bexpr"))
+}
+
+// GetTypedPath implements bexpr.TypedSelectorSource
+func (v *Service) GetTypedPath(path []string) (bexpr.TypedValue, bool) {
+	if len(path) == 0 {
+		return bexpr.TypedValue{}, false
+	}
+	switch path[0] {
+	case "Enabled":
+		if len(path) == 1 {
+			return bexpr.TypedBool(v.Enabled), true
+		}
+	case "Meta":
+		return v.Meta.GetTypedPath(path[1:])
+	case "Name":
+		if len(path) == 1 {
+			return bexpr.TypedString(v.Name), true
+		}
+	case "Parent":
+		if v.Parent != nil {
+			return v.Parent.GetTypedPath(path[1:])
+		}
+	case "Port":
+		if len(path) == 1 {
+			return bexpr.TypedInt(int64(v.Port)), true
+		}
+	case "Weight":
+		if len(path) == 1 {
+			return bexpr.TypedFloat(v.Weight), true
+		}
+	case "env":
+		if len(path) == 1 {
+			return bexpr.TypedString(v.Env), true
+		}
+	case "labels":
+		if len(path) == 2 {
+			if value, ok := v.Labels[path[1]]; ok {
+				return bexpr.TypedString(value), true
+			}
+		}
+	}
+	return bexpr.TypedValue{}, false
+}
+
+// GetPath implements bexpr.SelectorSource
+func (v *Meta) GetPath(path []string) (interface{}, bool, error) {
+	if len(path) == 0 {
+		return v, true, nil
+	}
+	switch path[0] {
+	case "Region":
+		if len(path) == 1 {
+			return v.Region, true, nil
+		}
+	case "Version":
+		if len(path) == 1 {
+			return v.Version, true, nil
+		}
+	case "Zone":
+		if len(path) == 1 {
+			return v.Zone, true, nil
+		}
+	}
+	// the other fields are resolved with reflection, which reports the errors
+	// with the whole path
+	return bexpr.LookupPath(*v, path, bexpr.WithTagName("bexpr"))
+}
+
+// GetTypedPath implements bexpr.TypedSelectorSource
+func (v *Meta) GetTypedPath(path []string) (bexpr.TypedValue, bool) {
+	if len(path) == 0 {
+		return bexpr.TypedValue{}, false
+	}
+	switch path[0] {
+	case "Region":
+		if len(path) == 1 {
+			return bexpr.TypedString(v.Region), true
+		}
+	case "Zone":
+		if len(path) == 1 {
+			return bexpr.TypedUint(uint64(v.Zone)), true
+		}
+	}
+	return bexpr.TypedValue{}, false
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Clause is a clause of an expression, a match expression or a value used as
+// a boolean, with its outcome
+type Clause = api.Clause
+
+// Explain evaluates the expression against the datum and returns, along with
+// the outcome, the smallest set of clauses which decide it on their own: the
+// expression has that outcome whatever the outcomes of the other clauses.
+// When the expression does not match, these are the clauses whose failure
+// caused it, such as `Env == "prod"` in
+// `Env == "prod" and (Port > 8000 or Role == "admin")` for a datum in
+// another environment, whatever its port and role. Clauses within a "not"
+// decide the outcome by having matched. The clauses are in the order they
+// are written, with let expressions inlined.
+//
+// The expression is evaluated the way Evaluate does, failing with the same
+// errors. Operands otherwise skipped by short-circuiting are evaluated too,
+// to find smaller explanations, their errors being ignored.
+func (eval *Evaluator) Explain(datum interface{}, opts ...Option) (bool, []Clause, error) {
+	opts = append(eval.evaluateOpts(), opts...)
+	result, clauses, err := explain(context.Background(), grammar.InlineLets(eval.ast), datum, opts...)
+	if err != nil {
+		return false, nil, err
+	}
+	return result, clauses, nil
+}
+
+// explain returns the outcome of the node and the smallest set of clauses
+// deciding it
+func explain(ctx context.Context, ast grammar.Expression, datum interface{}, opt ...Option) (bool, []Clause, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		result, clauses, err := explain(ctx, node.Operand, datum, opt...)
+		return !result, clauses, err
+
+	case *grammar.BinaryExpression:
+		left, leftClauses, err := explain(ctx, node.Left, datum, opt...)
+		if err != nil {
+			return false, nil, err
+		}
+		// the outcome of an operand deciding the outcome on its own
+		absorbing := node.Operator == grammar.BinaryOpOr
+		right, rightClauses, rightErr := explain(ctx, node.Right, datum, opt...)
+		if left == absorbing {
+			if rightErr == nil && right == absorbing && len(rightClauses) < len(leftClauses) {
+				return absorbing, rightClauses, nil
+			}
+			return absorbing, leftClauses, nil
+		}
+		if rightErr != nil {
+			return false, nil, rightErr
+		}
+		if right == absorbing {
+			return absorbing, rightClauses, nil
+		}
+		// both operands are needed
+		return right, mergeClauses(leftClauses, rightClauses), nil
+	}
+
+	value, err := evaluateContext(ctx, ast, datum, opt...)
+	if err != nil {
+		return false, nil, err
+	}
+	result, _ := value.(bool)
+	return result, []Clause{{Clause: formatExpression(ast), Result: result}}, nil
+}
+
+// mergeClauses appends the clauses which are not already listed
+func mergeClauses(clauses []Clause, more []Clause) []Clause {
+	merged := append([]Clause(nil), clauses...)
+	for _, c := range more {
+		found := false
+		for _, existing := range merged {
+			if existing == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
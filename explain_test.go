@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Env":     "dev",
+		"Port":    80,
+		"Role":    "user",
+		"Enabled": true,
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		clauses    []Clause
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"single clause": {
+			expression: `Env == "prod"`,
+			clauses:    []Clause{{Clause: `Env == "prod"`}},
+		},
+		"and": {
+			expression: `Env == "prod" and (Port > 8000 or Role == "admin")`,
+			clauses:    []Clause{{Clause: `Env == "prod"`}},
+		},
+		"smallest operand of and": {
+			expression: `(Port > 8000 or Role == "admin") and Env == "prod"`,
+			clauses:    []Clause{{Clause: `Env == "prod"`}},
+		},
+		"or": {
+			expression: `Env == "prod" or Port > 8000 or Enabled == false`,
+			clauses: []Clause{
+				{Clause: `Env == "prod"`},
+				{Clause: `Port > 8000`},
+				{Clause: `Enabled == false`},
+			},
+		},
+		"not": {
+			expression: `Env == "prod" or not Enabled`,
+			clauses: []Clause{
+				{Clause: `Env == "prod"`},
+				{Clause: `Enabled`, Result: true},
+			},
+		},
+		"repeated clause": {
+			expression: `(Env == "prod" and Port > 0) or (Env == "prod" and Role == "user")`,
+			clauses:    []Clause{{Clause: `Env == "prod"`}},
+		},
+		"let": {
+			expression: `let port = Port in port > 8000 and port < 9000`,
+			clauses:    []Clause{{Clause: `Port > 8000`}},
+		},
+		"match": {
+			expression: `Env == "dev" and (Port == 80 or Port == 8080)`,
+			result:     true,
+			clauses: []Clause{
+				{Clause: `Env == "dev"`, Result: true},
+				{Clause: `Port == 80`, Result: true},
+			},
+		},
+		"error ignored when skipped": {
+			expression: `Role == "admin" and (Env == "prod" and Missing == 1)`,
+			clauses:    []Clause{{Clause: `Role == "admin"`}},
+		},
+		"error": {
+			expression: `Missing == 1 and Env == "prod"`,
+			err:        `1:1 (0): Missing == 1: error finding value in datum: /Missing at part 0: couldn't find key "Missing"`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, clauses, err := eval.Explain(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+			require.Equal(t, tcase.clauses, clauses)
+		})
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// fastExpression is an expression evaluated without allocating: its
+// selectors are resolved with reflect rather than pointerstructure and its
+// values are compared without boxing them into interfaces nor formatting
+// them. It only handles the datums whose values need neither hooks nor
+// conversions, reporting false for the others, which are then evaluated the
+// usual way.
+type fastExpression interface {
+	evaluate(datum fastDatum) (result bool, ok bool)
+}
+
+// fastDatum is the datum a fastExpression is evaluated against: a value
+// resolved with reflect, or a TypedSelectorSource
+type fastDatum struct {
+	value reflect.Value
+	typed TypedSelectorSource
+}
+
+// fastOptions reports whether the evaluator was created without options
+// changing how values are resolved or compared, so that its match expressions
+// can be evaluated with fastExpressions
+func (eval *Evaluator) fastOptions() bool {
+	return eval.valueTransformationHook == nil && len(eval.selectorHooks) == 0 && len(eval.valueConverters) == 0 &&
+		eval.unknownVal == nil && !eval.strictTypes && len(eval.coercions) == 0 && len(eval.selectorCoercions) == 0 &&
+		!eval.decimal && eval.unknownResult == nil && eval.traceFn == nil && eval.stats == nil && eval.fieldAccess == nil &&
+		eval.logger == nil && len(eval.tagNames) == 0
+}
+
+// fastTagName returns the tag naming the fields of structs
+func (eval *Evaluator) fastTagName() string {
+	if eval.tagName == "" {
+		return "pointer"
+	}
+	return eval.tagName
+}
+
+// compileFast returns the fastExpression of the expression of the evaluator,
+// or nil if it has any option changing how values are resolved or compared,
+// or if the expression is not made of and, or, not and of the ==, !=, <,
+// <=, > and >= match expressions comparing a selector with a literal, such
+// as `Name == "web" and Port > 80`.
+func (eval *Evaluator) compileFast() fastExpression {
+	if !eval.fastOptions() {
+		return nil
+	}
+	return compileFastNode(eval.ast, eval.fastTagName())
+}
+
+func compileFastNode(ast grammar.Expression, tagName string) fastExpression {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		if node.Operator != grammar.UnaryOpNot {
+			return nil
+		}
+		if operand := compileFastNode(node.Operand, tagName); operand != nil {
+			return &fastNot{operand: operand}
+		}
+	case *grammar.BinaryExpression:
+		left, right := compileFastNode(node.Left, tagName), compileFastNode(node.Right, tagName)
+		if left == nil || right == nil {
+			return nil
+		}
+		switch node.Operator {
+		case grammar.BinaryOpAnd:
+			return &fastAnd{left: left, right: right}
+		case grammar.BinaryOpOr:
+			return &fastOr{left: left, right: right}
+		}
+	case *grammar.MatchExpression:
+		return compileFastMatch(node, tagName)
+	}
+	return nil
+}
+
+func compileFastMatch(node *grammar.MatchExpression, tagName string) fastExpression {
+	switch node.Operator {
+	case grammar.MatchEqual, grammar.MatchNotEqual, grammar.MatchLower, grammar.MatchLowerOrEqual,
+		grammar.MatchHigher, grammar.MatchHigherOrEqual:
+	default:
+		return nil
+	}
+	left, right := operandValue(node.Left), operandValue(node.Right)
+	if left == nil || right == nil || left.Type != grammar.ValueTypeReflect || left.Selector.Anchored ||
+		len(left.Selector.Path) == 0 || len(left.Selector.Ranges) > 0 {
+		return nil
+	}
+	var value fastValue
+	switch right.Type {
+	case grammar.ValueTypeString:
+		value = fastValue{kind: fastString, s: right.Raw}
+	case grammar.ValueTypeBool:
+		b, err := CoerceBool(right.Raw)
+		if err != nil {
+			return nil
+		}
+		value = fastValue{kind: fastBool, b: b}
+	case grammar.ValueTypeInt:
+		i, err := CoerceInt64(right.Raw)
+		if err != nil {
+			return nil
+		}
+		value = fastValue{kind: fastInt, i: i}
+	case grammar.ValueTypeUint:
+		u, err := strconv.ParseUint(right.Raw, 10, 64)
+		if err != nil {
+			return nil
+		}
+		value = fastValue{kind: fastUint, u: u}
+	case grammar.ValueTypeFloat64:
+		f, err := CoerceFloat64(right.Raw)
+		if err != nil {
+			return nil
+		}
+		value = fastValue{kind: fastFloat, f: f}
+	default:
+		return nil
+	}
+	match := &fastMatch{operator: node.Operator, value: value, tagName: tagName, path: left.Selector.Path}
+	for _, part := range left.Selector.Path {
+		step := &fastStep{part: part, index: -1}
+		if n, err := strconv.Atoi(part); err == nil && n >= 0 && strconv.Itoa(n) == part {
+			step.index = n
+		}
+		match.steps = append(match.steps, step)
+	}
+	return match
+}
+
+// operandValue returns the value of the operand of a match expression, or nil
+// if it is not a value on its own, such as the result of a math operator
+func operandValue(operand *grammar.ExpressionValue) *grammar.MatchValue {
+	if operand == nil || operand.Operator != grammar.MathOpValue {
+		return nil
+	}
+	value, _ := operand.Left.(*grammar.MatchValue)
+	return value
+}
+
+// evaluateFast evaluates the expression with its fastExpression, reporting
+// false when it must be evaluated the usual way
+func (eval *Evaluator) evaluateFast(ctx context.Context, datum interface{}) (bool, bool) {
+	if eval.fast == nil || ctx.Err() != nil {
+		return false, false
+	}
+	fd, ok := fastDatumOf(datum)
+	if !ok {
+		return false, false
+	}
+	return eval.fast.evaluate(fd)
+}
+
+// fastDatumOf returns the fastDatum of the datum, reporting false for the
+// datums resolving selectors in other ways, such as the SelectorSources which
+// are not TypedSelectorSources
+func fastDatumOf(datum interface{}) (fastDatum, bool) {
+	switch src := datum.(type) {
+	case TypedSelectorSource:
+		return fastDatum{typed: src}, true
+	case SelectorSource, chainedDatum, MergedView:
+		return fastDatum{}, false
+	}
+	return fastDatum{value: reflect.ValueOf(datum)}, true
+}
+
+type fastNot struct {
+	operand fastExpression
+}
+
+func (n *fastNot) evaluate(datum fastDatum) (bool, bool) {
+	result, ok := n.operand.evaluate(datum)
+	return !result, ok
+}
+
+type fastAnd struct {
+	left, right fastExpression
+}
+
+func (n *fastAnd) evaluate(datum fastDatum) (bool, bool) {
+	result, ok := n.left.evaluate(datum)
+	if !ok || !result {
+		return false, ok
+	}
+	return n.right.evaluate(datum)
+}
+
+type fastOr struct {
+	left, right fastExpression
+}
+
+func (n *fastOr) evaluate(datum fastDatum) (bool, bool) {
+	result, ok := n.left.evaluate(datum)
+	if !ok || result {
+		return result, ok
+	}
+	return n.right.evaluate(datum)
+}
+
+// fastKind is the kind of a fastValue
+type fastKind uint8
+
+const (
+	fastInvalid fastKind = iota
+	fastString
+	fastBool
+	fastInt
+	fastUint
+	fastFloat
+)
+
+// fastValue is a literal, or a value found in the datum, held without boxing
+// it into an interface
+type fastValue struct {
+	kind fastKind
+	s    string
+	b    bool
+	i    int64
+	u    uint64
+	f    float64
+}
+
+// leafValue returns the value found at the end of a selector. Only the
+// values of the predeclared types, which no hook changes, are handled, and
+// not float32 as the float64 they compare as is not the one they convert to.
+func leafValue(v reflect.Value) (fastValue, bool) {
+	typ := v.Type()
+	if typ.Name() == "" || typ.PkgPath() != "" {
+		return fastValue{}, false
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return fastValue{kind: fastString, s: v.String()}, true
+	case reflect.Bool:
+		return fastValue{kind: fastBool, b: v.Bool()}, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fastValue{kind: fastInt, i: v.Int()}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fastValue{kind: fastUint, u: v.Uint()}, true
+	case reflect.Float64:
+		return fastValue{kind: fastFloat, f: v.Float()}, true
+	}
+	return fastValue{}, false
+}
+
+func (v fastValue) isNumber() bool {
+	return v.kind == fastInt || v.kind == fastUint || v.kind == fastFloat
+}
+
+func (v fastValue) float() float64 {
+	switch v.kind {
+	case fastInt:
+		return float64(v.i)
+	case fastUint:
+		return float64(v.u)
+	}
+	return v.f
+}
+
+// compareIntegers compares integers over the full range of int64 and uint64
+// like compareIntegers
+func (v fastValue) compareIntegers(other fastValue) int {
+	switch {
+	case v.kind == fastUint && other.kind == fastUint:
+		return compareOrdered(v.u < other.u, v.u > other.u)
+	case v.kind == fastUint:
+		if other.i < 0 {
+			return 1
+		}
+		return compareOrdered(v.u < uint64(other.i), v.u > uint64(other.i))
+	case other.kind == fastUint:
+		return -other.compareIntegers(v)
+	}
+	return compareOrdered(v.i < other.i, v.i > other.i)
+}
+
+// fastCompare applies the operator to the value found in the datum and to the
+// literal the way evaluateMatchExpression does, reporting false for the pairs
+// of values it does not compare
+func fastCompare(op grammar.MatchOperator, left, right fastValue) (bool, bool) {
+	switch {
+	case left.kind == fastString && right.kind == fastString:
+		return fastOrder(op, false, left.s == right.s, false)
+	case left.kind == fastBool && right.kind == fastBool:
+		return fastOrder(op, false, left.b == right.b, false)
+	case !left.isNumber() || !right.isNumber():
+		return false, false
+	case left.kind == fastFloat || right.kind == fastFloat:
+		l, r := left.float(), right.float()
+		return fastOrder(op, l < r, l == r, true)
+	}
+	c := left.compareIntegers(right)
+	return fastOrder(op, c < 0, c == 0, true)
+}
+
+// fastOrder returns the outcome of the operator given whether the value found
+// is lower than or equal to the literal. Only numbers are ordered.
+func fastOrder(op grammar.MatchOperator, lower bool, equal bool, ordered bool) (bool, bool) {
+	switch op {
+	case grammar.MatchEqual:
+		return equal, true
+	case grammar.MatchNotEqual:
+		return !equal, true
+	}
+	if !ordered {
+		return false, false
+	}
+	switch op {
+	case grammar.MatchLower:
+		return lower, true
+	case grammar.MatchLowerOrEqual:
+		return lower || equal, true
+	case grammar.MatchHigher:
+		return !lower && !equal, true
+	case grammar.MatchHigherOrEqual:
+		return !lower, true
+	}
+	return false, false
+}
+
+type fastMatch struct {
+	operator grammar.MatchOperator
+	value    fastValue
+	tagName  string
+	path     []string
+	steps    []*fastStep
+}
+
+// fastStep is a part of the selector of a fastMatch
+type fastStep struct {
+	part string
+	// index is the part as the index of a slice, -1 if it is not one
+	index int
+	// types caches the index of the field of the structs, by type, and
+	// whether the slices are indexed without hooks, 0 when they are. It is -1
+	// for the types which are not resolved without hooks.
+	types sync.Map
+}
+
+func (m *fastMatch) evaluate(datum fastDatum) (bool, bool) {
+	if datum.typed != nil {
+		left, ok := datum.typed.GetTypedPath(m.path)
+		if !ok {
+			return false, false
+		}
+		return fastCompare(m.operator, left.value, m.value)
+	}
+	v := datum.value
+	for i, step := range m.steps {
+		next, s, ok := m.lookup(step, v)
+		if !ok {
+			return false, false
+		}
+		if !next.IsValid() {
+			// the string value of a map[string]string
+			if i < len(m.steps)-1 {
+				return false, false
+			}
+			return fastCompare(m.operator, fastValue{kind: fastString, s: s}, m.value)
+		}
+		v = next
+	}
+	v, ok := fastIndirect(v)
+	if !ok {
+		return false, false
+	}
+	left, ok := leafValue(v)
+	if !ok {
+		return false, false
+	}
+	return fastCompare(m.operator, left, m.value)
+}
+
+// lookup returns the value found at the step of the selector. The string
+// values of the map[string]string are returned as is, with an invalid value,
+// as reflect would copy them to return them as values.
+func (m *fastMatch) lookup(step *fastStep, v reflect.Value) (reflect.Value, string, bool) {
+	v, ok := fastIndirect(v)
+	if !ok {
+		return v, "", false
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		field := step.typeIndex(v.Type(), func(typ reflect.Type) int {
+			return fieldIndex(typ, step.part, m.tagName)
+		})
+		if field < 0 {
+			return v, "", false
+		}
+		return v.Field(field), "", true
+	case reflect.Map:
+		if !v.CanInterface() {
+			return v, "", false
+		}
+		switch values := v.Interface().(type) {
+		case map[string]interface{}:
+			value, found := values[step.part]
+			return reflect.ValueOf(value), "", found && value != nil
+		case map[string]string:
+			value, found := values[step.part]
+			return reflect.Value{}, value, found
+		}
+		// the other maps are looked up the usual way
+	case reflect.Slice:
+		plain := step.typeIndex(v.Type(), func(typ reflect.Type) int {
+			if elem := typ.Elem(); isBSONDocument(typ) || (elem.Kind() != reflect.Interface && (elem.Name() == "" || elem.PkgPath() != "")) {
+				return -1
+			}
+			return 0
+		})
+		if plain < 0 || step.index < 0 || step.index >= v.Len() {
+			return v, "", false
+		}
+		return v.Index(step.index), "", true
+	}
+	return v, "", false
+}
+
+// typeIndex returns the index cached for the type, computing it the first
+// time the type is seen
+func (s *fastStep) typeIndex(typ reflect.Type, compute func(reflect.Type) int) int {
+	if index, ok := s.types.Load(typ); ok {
+		return index.(int)
+	}
+	index := compute(typ)
+	s.types.Store(typ, index)
+	return index
+}
+
+// fieldIndex returns the index of the field of the struct named by the part
+// the way pointerstructure finds it, or -1 if it is not found, or if the
+// struct is a type hooks convert
+func fieldIndex(typ reflect.Type, part string, tagName string) int {
+	if isProtoMessage(typ) || isSQLNullType(typ) {
+		return -1
+	}
+	found := -1
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get(tagName)
+		if idx := strings.Index(tag, ","); idx != -1 {
+			tag = tag[0:idx]
+		}
+		switch {
+		case tag == "":
+			if field.Name == part {
+				found = i
+			}
+		case strings.Contains(tag, "|"), tag == "-" && field.Name == part:
+			return -1
+		case tag == part:
+			return i
+		}
+	}
+	return found
+}
+
+// fastIndirect dereferences the interfaces and pointers, reporting false for
+// the nil ones
+func fastIndirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, v.IsValid()
+}
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fastRecord struct {
+	Name    string
+	Port    int
+	Weight  float64
+	Ratio   float32
+	Count   uint64
+	Enabled bool
+	Alias   string `bexpr:"alias"`
+	Hidden  string `bexpr:"-"`
+	Meta    map[string]string
+	Labels  map[string]interface{}
+	Tags    []string
+	Next    *fastRecord
+	Any     interface{}
+	Null    sql.NullString
+}
+
+func TestFast(t *testing.T) {
+	t.Parallel()
+
+	datum := &fastRecord{
+		Name:    "web",
+		Port:    80,
+		Weight:  1.5,
+		Ratio:   0.1,
+		Count:   math.MaxUint64,
+		Enabled: true,
+		Alias:   "a",
+		Meta:    map[string]string{"env": "prod"},
+		Labels:  map[string]interface{}{"tier": "front", "replicas": 3, "n": json.Number("2")},
+		Tags:    []string{"x", "y"},
+		Next:    &fastRecord{Name: "db"},
+		Any:     map[string]interface{}{"id": int8(-1)},
+		Null:    sql.NullString{String: "s", Valid: true},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		// fast is whether the fast path decides the outcome
+		fast bool
+	}
+
+	tests := map[string]testCase{
+		"string":              {expression: `Name == "web"`, datum: datum, fast: true},
+		"string not equal":    {expression: `Name != "web"`, datum: datum, fast: true},
+		"int":                 {expression: `Port >= 80 and Port < 443`, datum: datum, fast: true},
+		"int float":           {expression: `Port > 79.5`, datum: datum, fast: true},
+		"float int":           {expression: `Weight <= 1`, datum: datum, fast: true},
+		"uint":                {expression: `Count > 18446744073709551614`, datum: datum, fast: true},
+		"uint int":            {expression: `Count > -1`, datum: datum, fast: true},
+		"bool":                {expression: `Enabled == true`, datum: datum, fast: true},
+		"tag":                 {expression: `alias == "a"`, datum: datum, fast: true},
+		"map string":          {expression: `Meta.env == "prod"`, datum: datum, fast: true},
+		"map interface":       {expression: `Labels.tier != "back" or Labels.replicas > 2`, datum: datum, fast: true},
+		"slice":               {expression: `Tags.1 == "y"`, datum: datum, fast: true},
+		"pointer":             {expression: `not (Next.Name == "web")`, datum: datum, fast: true},
+		"interface":           {expression: `Any.id < 0`, datum: datum, fast: true},
+		"map datum":           {expression: `env == "prod"`, datum: map[string]string{"env": "prod"}, fast: true},
+		"short circuit":       {expression: `Name == "db" and Missing == 1`, datum: datum, fast: true},
+		"float32":             {expression: `Ratio == 0.1`, datum: datum},
+		"json number":         {expression: `Labels.n == 2`, datum: datum},
+		"sql null":            {expression: `Null == "s"`, datum: datum},
+		"ignored":             {expression: `Hidden == ""`, datum: datum},
+		"missing":             {expression: `Missing == 1`, datum: datum},
+		"missing key":         {expression: `Meta.region != "eu"`, datum: datum},
+		"string int":          {expression: `Name == 1`, datum: datum},
+		"ordered string":      {expression: `Name < "x"`, datum: datum},
+		"index string":        {expression: `Meta.env.0 == "p"`, datum: datum},
+		"nil pointer":         {expression: `Next.Next.Name == "x"`, datum: datum},
+		"out of range":        {expression: `Tags.2 == "z"`, datum: datum},
+		"selector source":     {expression: `Name == "web"`, datum: NewMergedView(datum)},
+		"unsupported operand": {expression: `Name == "web" and "x" in Tags`, datum: datum},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression, WithTagName("bexpr"))
+			require.NoError(t, err)
+			expected, expectedErr := evaluateContext(context.Background(), eval.ast, tc.datum, eval.evaluateOpts()...)
+
+			result, ok := eval.evaluateFast(context.Background(), tc.datum)
+			require.Equal(t, tc.fast, ok)
+			if ok {
+				require.NoError(t, expectedErr)
+				require.Equal(t, expected, result)
+			}
+
+			result2, err := eval.Evaluate(tc.datum)
+			require.Equal(t, expected, result2)
+			require.Equal(t, expectedErr, err)
+		})
+	}
+}
+
+func TestFast_Options(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Name == "web"`, WithHookFn(nil))
+	require.NoError(t, err)
+	require.NotNil(t, eval.fast)
+	eval, err = CreateEvaluator(`Name == "web"`, WithStrictTypes())
+	require.NoError(t, err)
+	require.Nil(t, eval.fast)
+	eval, err = CreateEvaluator(`let n = Name in n == "web"`)
+	require.NoError(t, err)
+	require.Nil(t, eval.fast)
+
+	// the options given to Evaluate are honored
+	eval, err = CreateEvaluator(`Name == "web"`)
+	require.NoError(t, err)
+	result, err := eval.Evaluate(map[string]string{}, WithUnknownValue("web"))
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = eval.EvaluateContext(ctx, map[string]string{"Name": "web"})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFast_Allocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("allocations are not measured in short mode")
+	}
+
+	tests := map[string]struct {
+		expression string
+		datum      interface{}
+	}{
+		"struct": {`Name == "web" and Port > 8`, &fastRecord{Name: "web", Port: 80}},
+		"map":    {`Meta.env == "prod"`, fastRecord{Meta: map[string]string{"env": "prod"}}},
+		"json":   {`Labels.tier == "front"`, map[string]interface{}{"Labels": map[string]interface{}{"tier": "front"}}},
+	}
+	for name, tc := range tests {
+		eval, err := CreateEvaluator(tc.expression)
+		require.NoError(t, err)
+		allocs := testing.AllocsPerRun(100, func() {
+			result, err := eval.Evaluate(tc.datum)
+			if err != nil || result != true {
+				t.Fatalf("%s: unexpected result %v, %v", name, result, err)
+			}
+		})
+		require.Zero(t, allocs, name)
+	}
+}
+
+func BenchmarkFast(b *testing.B) {
+	eval, err := CreateEvaluator(`Name == "web" and Port > 8`)
+	require.NoError(b, err)
+	datum := &fastRecord{Name: "web", Port: 80}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := eval.Evaluate(datum); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
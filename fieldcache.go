@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// fieldCache caches, by type of datum, the indexes of the struct fields the
+// selectors of an evaluator go through, so that evaluating many datums of the
+// same type looks the fields up by name once
+type fieldCache struct {
+	chains sync.Map
+}
+
+type fieldCacheKey struct {
+	selector *grammar.MatchValue
+	typ      reflect.Type
+	tagName  string
+}
+
+// fieldChain returns the indexes of the struct fields the selector goes
+// through from a datum of the type, in the order they are found, stopping at
+// the first part which is not a field of a struct hooks leave as is
+func fieldChain(typ reflect.Type, path []string, tagName string) []int {
+	if tagName == "" {
+		tagName = "pointer"
+	}
+	var chain []int
+	for _, part := range path {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			break
+		}
+		field := fieldIndex(typ, part, tagName)
+		if field < 0 {
+			break
+		}
+		chain = append(chain, field)
+		typ = typ.Field(field).Type
+	}
+	return chain
+}
+
+// getValue resolves the selector in the datum like pointerstructure would,
+// the struct fields at the start of the selector being found with the
+// indexes cached for the type of the datum. It reports false when the
+// selector is to be resolved the usual way: when there are hooks other than
+// the ones of the package, which may change the structs gone through, when
+// the selector does not start with a struct field, or when resolving it
+// fails, so that the errors are the usual ones.
+func (c *fieldCache) getValue(selector *grammar.MatchValue, datum interface{}, opts options, hook ValueTransformationHookFn) (interface{}, bool) {
+	if c == nil || opts.withHookFn != nil || len(opts.withSelectorHooks) > 0 || len(opts.withValueConverters) > 0 ||
+		len(opts.withTagNames) > 0 {
+		return nil, false
+	}
+	v := reflect.ValueOf(datum)
+	if !v.IsValid() {
+		return nil, false
+	}
+	path := selector.Selector.Path
+	key := fieldCacheKey{selector: selector, typ: v.Type(), tagName: opts.withTagName}
+	cached, ok := c.chains.Load(key)
+	if !ok {
+		cached, _ = c.chains.LoadOrStore(key, fieldChain(v.Type(), path, opts.withTagName))
+	}
+	chain := cached.([]int)
+	if len(chain) == 0 {
+		return nil, false
+	}
+	for _, field := range chain {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(field)
+	}
+	// the hooks of the package only change the value the struct fields end
+	// with
+	if v = hook(v); !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	rest := path[len(chain):]
+	if len(rest) == 0 {
+		return v.Interface(), true
+	}
+	ptr := pointerstructure.Pointer{
+		Parts: rest,
+		Config: pointerstructure.Config{
+			TagName:                 opts.withTagName,
+			ValueTransformationHook: hook,
+		},
+	}
+	val, err := ptr.Get(v.Interface())
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fieldCacheRecord struct {
+	Name   string `bexpr:"name"`
+	Port   int
+	Ratio  float32
+	Null   sql.NullString
+	Meta   map[string]string
+	Tags   []string
+	Next   *fieldCacheRecord
+	Any    interface{}
+	Hidden string `bexpr:"-"`
+}
+
+// incrementHook increments the ints
+func incrementHook(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Int {
+		return reflect.ValueOf(int(v.Int()) + 1)
+	}
+	return v
+}
+
+func TestFieldChain(t *testing.T) {
+	t.Parallel()
+
+	typ := reflect.TypeOf(&fieldCacheRecord{})
+	require.Equal(t, []int{6, 6, 0}, fieldChain(typ, []string{"Next", "Next", "name"}, "bexpr"))
+	require.Equal(t, []int{4}, fieldChain(typ, []string{"Meta", "env"}, "bexpr"))
+	require.Equal(t, []int{7}, fieldChain(typ, []string{"Any", "Port"}, "bexpr"))
+	require.Empty(t, fieldChain(typ, []string{"Name"}, "bexpr"))
+	require.Empty(t, fieldChain(typ, []string{"Hidden"}, "bexpr"))
+	require.Empty(t, fieldChain(reflect.TypeOf(map[string]interface{}{}), []string{"Port"}, "bexpr"))
+	require.Equal(t, []int{1}, fieldChain(reflect.TypeOf(fieldCacheRecord{}), []string{"Port"}, ""))
+}
+
+func TestFieldCache(t *testing.T) {
+	t.Parallel()
+
+	datum := &fieldCacheRecord{
+		Name:  "web",
+		Port:  80,
+		Ratio: 0.5,
+		Null:  sql.NullString{String: "s", Valid: true},
+		Meta:  map[string]string{"env": "prod"},
+		Tags:  []string{"x"},
+		Next:  &fieldCacheRecord{Name: "db", Any: map[string]interface{}{"id": 1}},
+		Any:   fieldCacheRecord{Port: 443},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		datum      interface{}
+	}
+
+	tests := map[string]testCase{
+		"field":           {expression: `name == "web" and Port == 80`},
+		"float32":         {expression: `Ratio == 0.5`},
+		"nested":          {expression: `Next.name == "db"`},
+		"nil pointer":     {expression: `Next.Next.name == "db"`},
+		"map":             {expression: `Meta.env == "prod"`},
+		"missing key":     {expression: `Meta.region == "eu"`},
+		"slice":           {expression: `Tags.0 == "x"`},
+		"out of range":    {expression: `Tags.3 == "x"`},
+		"interface":       {expression: `Any.Port == 443 and Next.Any.id == 1`},
+		"sql null":        {expression: `Null == "s"`},
+		"invalid null":    {expression: `Null == "s"`, datum: &fieldCacheRecord{}},
+		"invalid unknown": {expression: `Null == "s"`, datum: &fieldCacheRecord{}, opts: []Option{WithUnknownValue("s")}},
+		"missing field":   {expression: `Missing == 1`},
+		"ignored field":   {expression: `Hidden == ""`},
+		"not a struct":    {expression: `Port == 80`, datum: map[string]interface{}{"Port": 80}},
+		"decimal":         {expression: `Port == 80`, opts: []Option{WithDecimalArithmetic()}},
+		"hook":            {expression: `Port == 81`, opts: []Option{WithHookFn(incrementHook)}},
+		"selector hook":   {expression: `Port == 81`, opts: []Option{WithSelectorHook("Port", incrementHook)}},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.datum == nil {
+				tc.datum = datum
+			}
+			eval, err := CreateEvaluator(tc.expression, tc.opts...)
+			require.NoError(t, err)
+
+			// evaluating without the cache is the reference
+			opts := append(eval.evaluateOpts(), func(o *options) {
+				o.withFieldCache = nil
+			})
+			expected, expectedErr := evaluateContext(context.Background(), eval.ast, tc.datum, opts...)
+			for i := 0; i < 2; i++ {
+				result, err := evaluateContext(context.Background(), eval.ast, tc.datum, eval.evaluateOpts()...)
+				require.Equal(t, expectedErr, err)
+				require.Equal(t, expected, result)
+			}
+		})
+	}
+}
+
+func TestFieldCache_Types(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Port == 80`, WithTagName("json"), WithStrictTypes())
+	require.NoError(t, err)
+
+	type other struct {
+		Port int `json:"port"`
+		Alt  int `json:"Port"`
+	}
+	datums := []interface{}{&fieldCacheRecord{Port: 80}, other{Port: 1, Alt: 80}, &other{Port: 80, Alt: 1}}
+	for i, expected := range []bool{true, true, false} {
+		result, err := eval.Evaluate(datums[i])
+		require.NoError(t, err)
+		require.Equal(t, expected, result, "datum %d", i)
+	}
+
+	// the fast path is not taken with strict types, and a chain is cached by
+	// type of datum
+	chains := 0
+	eval.fieldCache.chains.Range(func(key, _ interface{}) bool {
+		chains++
+		return true
+	})
+	require.Equal(t, 3, chains)
+}
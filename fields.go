@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Fields returns the selectors referenced by the expression, sorted by path
+// and without duplicate paths. Selectors only referenced by parts of the
+// expression which were folded away when creating the evaluator are not
+// included, since they are never evaluated. The names bound by let
+// expressions are not selectors, the selectors of the values bound are
+// included instead.
+func (eval *Evaluator) Fields() []grammar.Selector {
+	selectors := collectSelectors(grammar.InlineLets(eval.ast))
+	keys := make([]string, 0, len(selectors))
+	for key := range selectors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	seen := make(map[string]struct{})
+	var fields []grammar.Selector
+	for _, key := range keys {
+		sel := selectors[key]
+		key := strings.Join(sel.Path, "\x00") + rangesKey(sel.Ranges)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		fields = append(fields, sel)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].Path, fields[j].Path
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// collectSelectors returns every selector referenced by the AST keyed by its
+// string form.
+func collectSelectors(ast interface{}) map[string]grammar.Selector {
+	selectors := make(map[string]grammar.Selector)
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch n := node.(type) {
+		case *grammar.UnaryExpression:
+			walk(n.Operand)
+		case *grammar.BinaryExpression:
+			walk(n.Left)
+			walk(n.Right)
+		case *grammar.MatchExpression:
+			if n.Left != nil {
+				walk(n.Left)
+			}
+			if n.Right != nil {
+				walk(n.Right)
+			}
+		case *grammar.ExpressionValue:
+			walk(n.Left)
+			walk(n.Right)
+		case *grammar.MatchValue:
+			if n.Type == grammar.ValueTypeReflect {
+				selectors[n.Selector.String()] = n.Selector
+			}
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		}
+	}
+	walk(ast)
+	return selectors
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields(t *testing.T) {
+	t.Parallel()
+
+	bexprSel := func(path ...string) grammar.Selector {
+		return grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: path}
+	}
+
+	type testCase struct {
+		expression string
+		fields     []grammar.Selector
+	}
+
+	tests := map[string]testCase{
+		"single": {
+			expression: `Name == "web"`,
+			fields:     []grammar.Selector{bexprSel("Name")},
+		},
+		"sorted without duplicates": {
+			expression: `Port > 1 and (Name == "web" or Name == "db") and not Meta.env is empty and "prod" in Tags`,
+			fields:     []grammar.Selector{bexprSel("Meta", "env"), bexprSel("Name"), bexprSel("Port"), bexprSel("Tags")},
+		},
+		"prefix sorts first": {
+			expression: `Meta.env == "prod" and Meta is not empty`,
+			fields:     []grammar.Selector{bexprSel("Meta"), bexprSel("Meta", "env")},
+		},
+		"json pointer": {
+			expression: `"/Meta/env" == "prod" and Meta.env != "dev" and "/Port" > 1`,
+			fields:     []grammar.Selector{bexprSel("Meta", "env"), {Type: grammar.SelectorTypeJsonPointer, Path: []string{"Port"}}},
+		},
+		"both sides and math": {
+			expression: `Used + Reserved < Capacity`,
+			fields:     []grammar.Selector{bexprSel("Capacity"), bexprSel("Reserved"), bexprSel("Used")},
+		},
+		"folded away": {
+			expression: `1 == 1 or Name == "web"`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			require.Equal(t, tcase.fields, eval.Fields())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strconv"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// foldConstants simplifies the parts of the expression which do not depend on
+// the datum, so that they are computed once instead of on every evaluation.
+// Math on literals is replaced by its result and logical operators with an
+// operand whose outcome is known are reduced to the other operand or to the
+// operand deciding the outcome. Anything failing to evaluate is left as is,
+// for the error to be reported at evaluation time. The options are the ones
+// the literals are evaluated with, such as WithDecimalArithmetic.
+func foldConstants(ast grammar.Expression, opt ...Option) grammar.Expression {
+	node, _, _ := fold(ast, opt...)
+	return node
+}
+
+// fold returns the folded node and, when its outcome does not depend on the
+// datum, the outcome. A node whose outcome is known still evaluates to it.
+func fold(ast grammar.Expression, opt ...Option) (grammar.Expression, bool, bool) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, known, result := fold(node.Operand, opt...)
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: operand}, known, !result
+
+	case *grammar.BinaryExpression:
+		left, leftKnown, leftResult := fold(node.Left, opt...)
+		right, rightKnown, rightResult := fold(node.Right, opt...)
+
+		// the value of the operand deciding the outcome on its own. The left
+		// operand is evaluated first, so a right operand deciding the outcome
+		// cannot replace the left one, which could fail to evaluate.
+		absorbing := node.Operator == grammar.BinaryOpOr
+		switch {
+		case leftKnown && leftResult == absorbing:
+			return left, true, absorbing
+		case leftKnown:
+			return right, rightKnown, rightResult
+		case rightKnown && rightResult != absorbing:
+			return left, false, false
+		}
+		return &grammar.BinaryExpression{Operator: node.Operator, Left: left, Right: right}, false, false
+
+	case *grammar.LetExpression:
+		// the value is evaluated before the body, which is thus never the
+		// outcome on its own
+		body, _, _ := fold(node.Body, opt...)
+		return &grammar.LetExpression{Name: node.Name, Value: foldValue(node.Value, opt...), Body: body, Position: node.Position}, false, false
+
+	case *grammar.MatchExpression:
+		folded := &grammar.MatchExpression{
+			Operator: node.Operator,
+			Left:     foldValue(node.Left, opt...),
+			Right:    foldValue(node.Right, opt...),
+			Position: node.Position,
+		}
+		if !isConstant(folded.Left) || (folded.Right != nil && !isConstant(folded.Right)) {
+			return folded, false, false
+		}
+		// null operands are Unknown with three-valued logic
+		if left, err := getExprValue(folded.Left, nil, opt...); err != nil || notPresent(folded.Operator, left) {
+			return folded, false, false
+		}
+		result, err := evaluateMatchExpression(folded, nil, opt...)
+		if err != nil {
+			return folded, false, false
+		}
+		return folded, true, result
+	}
+	return ast, false, false
+}
+
+// foldValue replaces math on literals by its result
+func foldValue(expr *grammar.ExpressionValue, opt ...Option) *grammar.ExpressionValue {
+	if expr == nil || expr.Operator == grammar.MathOpValue || !isConstant(expr) {
+		return expr
+	}
+	value, err := getExprValue(expr, nil, opt...)
+	if err != nil {
+		return expr
+	}
+	literal, ok := literalValue(value)
+	if !ok {
+		return expr
+	}
+	return &grammar.ExpressionValue{Left: literal}
+}
+
+// literalValue returns the literal evaluating to the value
+func literalValue(value interface{}) (*grammar.MatchValue, bool) {
+	switch v := value.(type) {
+	case bool:
+		return &grammar.MatchValue{Type: grammar.ValueTypeBool, Raw: strconv.FormatBool(v)}, true
+	case int64:
+		return &grammar.MatchValue{Type: grammar.ValueTypeInt, Raw: strconv.FormatInt(v, 10)}, true
+	case uint64:
+		return &grammar.MatchValue{Type: grammar.ValueTypeUint, Raw: strconv.FormatUint(v, 10)}, true
+	case float64:
+		return &grammar.MatchValue{Type: grammar.ValueTypeFloat64, Raw: strconv.FormatFloat(v, 'g', -1, 64)}, true
+	case string:
+		return &grammar.MatchValue{Type: grammar.ValueTypeString, Raw: v}, true
+	case nil:
+		return &grammar.MatchValue{Type: grammar.ValueTypeNull, Raw: "null"}, true
+	}
+	return nil, false
+}
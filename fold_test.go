@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldConstants(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		// folded is an expression parsing to the folded AST
+		folded string
+	}
+
+	tests := map[string]testCase{
+		"math":                  {expression: `2 * 3600 < Uptime`, folded: `7200 < Uptime`},
+		"math on the right":     {expression: `Uptime >= 60 - 1`, folded: `Uptime >= 59`},
+		"float math":            {expression: `Ratio < 1 / 4.0`, folded: `Ratio < 0.25`},
+		"string concatenation":  {expression: `Name == "web" + "-1"`, folded: `Name == "web-1"`},
+		"selector math kept":    {expression: `Uptime * 2 > 10`, folded: `Uptime * 2 > 10`},
+		"known or":              {expression: `"prod" == "prod" or X == 1`, folded: `"prod" == "prod"`},
+		"known and":             {expression: `1 < 2 and X == 1`, folded: `X == 1`},
+		"known false and":       {expression: `1 > 2 and X == 1`, folded: `1 > 2`},
+		"known false or":        {expression: `1 > 2 or X == 1`, folded: `X == 1`},
+		"known right and":       {expression: `X == 1 and 1 < 2`, folded: `X == 1`},
+		"known right or":        {expression: `X == 1 or 1 < 2`, folded: `X == 1 or 1 < 2`},
+		"not":                   {expression: `not (1 == 2) and X == 1`, folded: `X == 1`},
+		"nested":                {expression: `(X == 1 and 2 == 2) or (1 == 2 and Y == 2)`, folded: `X == 1`},
+		"division by zero kept": {expression: `X == 1 / 0`, folded: `X == 1 / 0`},
+		"invalid math kept":     {expression: `X == 1 + "a"`, folded: `X == 1 + "a"`},
+		"failing match kept":    {expression: `"a" < 1 or X == 1`, folded: `"a" < 1 or X == 1`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			expected, err := grammar.Parse("", []byte(tcase.folded))
+			require.NoError(t, err)
+			require.Equal(t, expected, withoutPositions(eval.ast))
+		})
+	}
+}
+
+func TestFoldConstants_Evaluation(t *testing.T) {
+	t.Parallel()
+
+	expressions := []string{
+		`2 * 3600 < Uptime`,
+		`"prod" == "prod" or Missing.x == 1`,
+		`1 > 2 or Uptime > 100`,
+		`not (1 == 1) or Uptime / 2 > 4000`,
+		`Uptime == 1 / 0`,
+		`Missing.x == 1 or 1 == 1`,
+	}
+	datum := map[string]interface{}{"Uptime": 9000}
+
+	for _, expression := range expressions {
+		expression := expression
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(expression)
+			require.NoError(t, err)
+			result, err := eval.Evaluate(datum)
+
+			// the unfolded expression evaluates the same
+			ast, parseErr := grammar.Parse("", []byte(expression), grammar.Positions())
+			require.NoError(t, parseErr)
+			expected, expectedErr := evaluate(ast, datum)
+			require.Equal(t, expectedErr, err)
+			require.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestDoMath(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		op       grammar.MathOperator
+		left     interface{}
+		right    interface{}
+		expected interface{}
+		err      string
+	}
+
+	three := 3
+	tests := map[string]testCase{
+		"int and int64":       {op: grammar.MathOpPlus, left: 1, right: int64(2), expected: int64(3)},
+		"uint and int":        {op: grammar.MathOpMinus, left: uint8(1), right: 2, expected: int64(-1)},
+		"int and float":       {op: grammar.MathOpMul, left: 3, right: 0.5, expected: 1.5},
+		"float32 and float64": {op: grammar.MathOpDiv, left: float32(1), right: 4.0, expected: 0.25},
+		"pointer":             {op: grammar.MathOpPlus, left: &three, right: 1, expected: int64(4)},
+		"strings":             {op: grammar.MathOpPlus, left: "a", right: "b", expected: "ab"},
+		"bools":               {op: grammar.MathOpPlus, left: true, right: false, expected: false},
+		"null":                {op: grammar.MathOpPlus, left: nil, right: 1, expected: nil},
+		"missing":             {op: grammar.MathOpPlus, left: 1, right: &undefined, expected: &undefined},
+		"division by zero":    {op: grammar.MathOpDiv, left: 1, right: 0, err: "integer division by zero"},
+		"string minus":        {op: grammar.MathOpMinus, left: "a", right: "b", err: `cannot perform math operation "-" on values of type string and string`},
+		"mismatched":          {op: grammar.MathOpPlus, left: "a", right: 1, err: `cannot perform math operation "+" on values of type string and int`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := doMath(tcase.op, tcase.left, tcase.right, false)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, result)
+		})
+	}
+}
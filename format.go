@@ -0,0 +1,534 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+var (
+	identifierRe    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_/]*$`)
+	indexRe         = regexp.MustCompile(`^[0-9]+$`)
+	jsonPathNameRe  = regexp.MustCompile(`^[\pL_][\pL\pN_]*$`)
+	jsonPathIndexRe = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+	// the characters ending the segments of the JSON Pointers of the JSON
+	// Pointer dialect
+	jsonPointerStopChars = " \t\r\n()\"=!<>"
+	// the literals which cannot start a selector written in bexpr syntax
+	literalKeywords    = map[string]bool{"true": true, "false": true, "null": true, "undefined": true}
+	jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+	// the double quoted strings parsed as JSON Pointers
+	jsonPointerLiteralRe = regexp.MustCompile(`^(/[\pL\pN\-_.~:|]+)*$`)
+)
+
+// matchOperatorSyntax is the syntax of the match operators. "in" and "not in"
+// are written with "contains", which takes the operands in the order of the
+// AST and accepts any expression value.
+var matchOperatorSyntax = map[grammar.MatchOperator]string{
+	grammar.MatchEqual:         "==",
+	grammar.MatchNotEqual:      "!=",
+	grammar.MatchIn:            "contains",
+	grammar.MatchNotIn:         "not contains",
+	grammar.MatchIsEmpty:       "is empty",
+	grammar.MatchIsNotEmpty:    "is not empty",
+	grammar.MatchMatches:       "matches",
+	grammar.MatchNotMatches:    "not matches",
+	grammar.MatchLower:         "<",
+	grammar.MatchLowerOrEqual:  "<=",
+	grammar.MatchHigher:        ">",
+	grammar.MatchHigherOrEqual: ">=",
+	grammar.MatchIsNull:        "is null",
+	grammar.MatchIsNotNull:     "is not null",
+	grammar.MatchStartsWith:    "startswith",
+	grammar.MatchNotStartsWith: "not startswith",
+	grammar.MatchEndsWith:      "endswith",
+	grammar.MatchNotEndsWith:   "not endswith",
+	grammar.MatchLike:          "like",
+	grammar.MatchNotLike:       "not like",
+}
+
+// WriteTo writes the expression of the evaluator to w in the bexpr syntax, as
+// parsed: with the macros expanded and the constants folded. The selectors
+// are written in the dialect set with WithSelectorDialect, the selectors
+// prefixed with WithSelectorPrefix being written with their prefix. Selectors
+// which cannot be written in the dialect, such as JSON Pointers to keys
+// holding spaces, fail the write. The expression is streamed rather than
+// buffered.
+func (eval *Evaluator) WriteTo(w io.Writer) (int64, error) {
+	f := &formatter{w: w, dialect: eval.dialect}
+	f.expression(eval.ast)
+	return f.n, f.err
+}
+
+// FormatConfig configures the layout of the expressions written by Format
+type FormatConfig struct {
+	// Width is the length of the lines beyond which the chains of "and" and
+	// "or" are broken, one operand per line starting with its operator, along
+	// with the parenthesized operands and the bodies of let expressions. 0
+	// means no limit.
+	Width int
+	// Indent is written once per level of nesting of the broken lines, a tab
+	// when empty
+	Indent string
+	// Dialect is the syntax of the selectors the expression is parsed and
+	// written in
+	Dialect grammar.SelectorDialect
+}
+
+// Format parses the expression and writes it back with canonical spacing and
+// quoting, such as `(a == 1 or b == "x") and c > 2` for
+// `( a==1 or b==` + "`x`" + ` ) and c>2`. Unlike WriteTo, the macros are not
+// expanded and the constants are not folded. Redundant parentheses are
+// dropped, strings are written with double quotes unless they would parse as
+// JSON Pointers, and "contains" is written with "in" when its operands are a
+// selector and a single value.
+func Format(expression string, cfg FormatConfig) (string, error) {
+	ast, err := grammar.Parse("", []byte(expression), grammar.Dialect(cfg.Dialect))
+	if err != nil {
+		return "", newSyntaxError(err)
+	}
+	if cfg.Indent == "" {
+		cfg.Indent = "\t"
+	}
+	var b strings.Builder
+	f := &formatter{w: &b, dialect: cfg.Dialect, width: cfg.Width, indent: cfg.Indent, preferIn: true}
+	f.expression(ast.(grammar.Expression))
+	return b.String(), f.err
+}
+
+// formatExpression writes the expression in the bexpr syntax, parsing back to
+// the same AST up to the nesting of chains of the same logical operator.
+func formatExpression(ast grammar.Expression) string {
+	var b strings.Builder
+	f := &formatter{w: &b}
+	f.expression(ast)
+	return b.String()
+}
+
+// formatSelector writes the selector in the bexpr syntax
+func formatSelector(sel grammar.Selector) string {
+	var b strings.Builder
+	f := &formatter{w: &b}
+	f.selector(sel)
+	return b.String()
+}
+
+// formatter writes expressions in the bexpr syntax, keeping the first error
+type formatter struct {
+	w       io.Writer
+	n       int64
+	err     error
+	dialect grammar.SelectorDialect
+
+	// the layout of Format: the width of the lines, 0 meaning they are never
+	// broken, the indentation, and whether "in" is written when it can be
+	width    int
+	indent   string
+	preferIn bool
+	// compact drops the optional whitespace and shortens the literals, see
+	// Minify
+	compact bool
+	// the level of nesting of the broken lines, and the column of the next
+	// character written
+	level, col int
+}
+
+func (f *formatter) write(s string) {
+	if f.err == nil {
+		var n int
+		n, f.err = io.WriteString(f.w, s)
+		f.n += int64(n)
+	}
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		f.col = utf8.RuneCountInString(s[i+1:])
+	} else {
+		f.col += utf8.RuneCountInString(s)
+	}
+}
+
+// newline starts a new line, indented to the current level
+func (f *formatter) newline() {
+	f.write("\n" + strings.Repeat(f.indent, f.level))
+}
+
+// breaks reports whether the expression, followed by extra characters, goes
+// beyond the width of the lines when written on the current one
+func (f *formatter) breaks(ast grammar.Expression, extra int) bool {
+	if f.width <= 0 {
+		return false
+	}
+	var b strings.Builder
+	flat := &formatter{w: &b, dialect: f.dialect, preferIn: f.preferIn}
+	flat.expression(ast)
+	return f.col+utf8.RuneCountInString(b.String())+extra > f.width
+}
+
+func (f *formatter) expression(ast grammar.Expression) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		f.write("not ")
+		f.operand(node.Operand, nil)
+	case *grammar.BinaryExpression:
+		op := "and"
+		if node.Operator == grammar.BinaryOpOr {
+			op = "or"
+		}
+		if f.breaks(node, 0) {
+			for i, operand := range chainOperands(node, node.Operator) {
+				if i > 0 {
+					f.newline()
+					f.write(op + " ")
+				}
+				f.operand(operand, node)
+			}
+			return
+		}
+		f.operand(node.Left, node)
+		f.write(" " + op + " ")
+		f.operand(node.Right, node)
+	case *grammar.LetExpression:
+		if f.compact {
+			f.write("let " + node.Name + "=")
+		} else {
+			f.write("let " + node.Name + " = ")
+		}
+		f.value(node.Value)
+		if f.breaks(node.Body, 4) {
+			f.write(" in")
+			f.newline()
+		} else {
+			f.write(" in ")
+		}
+		f.expression(node.Body)
+	case *grammar.MatchExpression:
+		if f.preferIn && isInOperands(node) {
+			f.value(node.Right)
+			if node.Operator == grammar.MatchIn {
+				f.write(" in ")
+			} else {
+				f.write(" not in ")
+			}
+			f.value(node.Left)
+			return
+		}
+		f.value(node.Left)
+		op := matchOperatorSyntax[node.Operator]
+		if f.compact && isSymbolicOperator(op) {
+			if f.spaceAfter(lastValue(node.Left), false) {
+				f.write(" ")
+			}
+			f.write(op)
+			f.value(node.Right)
+			return
+		}
+		f.write(" " + op)
+		if node.Right != nil {
+			f.write(" ")
+			f.value(node.Right)
+		}
+	case *grammar.ExpressionValue:
+		f.value(node)
+	}
+}
+
+// operand writes the operand of a logical operator, in parentheses when it
+// would otherwise be parsed differently. parent is nil for "not".
+func (f *formatter) operand(operand grammar.Expression, parent *grammar.BinaryExpression) {
+	parenthesize := false
+	switch node := operand.(type) {
+	case *grammar.LetExpression:
+		// the body of a let expression extends as far as possible
+		parenthesize = true
+	case *grammar.BinaryExpression:
+		// "and" binds tighter than "or", and both looser than "not"
+		parenthesize = parent == nil || (parent.Operator == grammar.BinaryOpAnd && node.Operator == grammar.BinaryOpOr)
+	}
+	if parenthesize {
+		if f.breaks(operand, 2) {
+			f.write("(")
+			f.level++
+			f.newline()
+			f.expression(operand)
+			f.level--
+			f.newline()
+			f.write(")")
+			return
+		}
+		f.write("(")
+		f.expression(operand)
+		f.write(")")
+		return
+	}
+	if _, ok := operand.(*grammar.BinaryExpression); ok {
+		// the chains of "and" within the ones of "or" are broken one level
+		// deeper
+		f.level++
+		defer func() { f.level-- }()
+	}
+	f.expression(operand)
+}
+
+// chainOperands returns the operands of the chain of the logical operator
+// starting at the expression, such as a, b and c for a and (b and c)
+func chainOperands(ast grammar.Expression, op grammar.BinaryOperator) []grammar.Expression {
+	if node, ok := ast.(*grammar.BinaryExpression); ok && node.Operator == op {
+		return append(chainOperands(node.Left, op), chainOperands(node.Right, op)...)
+	}
+	return []grammar.Expression{ast}
+}
+
+// isInOperands reports whether the "in" or "not in" match expression can be
+// written with "in", which takes a single value and a selector
+func isInOperands(node *grammar.MatchExpression) bool {
+	if node.Operator != grammar.MatchIn && node.Operator != grammar.MatchNotIn {
+		return false
+	}
+	isValue := func(expr *grammar.ExpressionValue) (*grammar.MatchValue, bool) {
+		if expr == nil || expr.Operator != grammar.MathOpValue {
+			return nil, false
+		}
+		value, ok := expr.Left.(*grammar.MatchValue)
+		return value, ok
+	}
+	selector, ok := isValue(node.Left)
+	if !ok || selector.Type != grammar.ValueTypeReflect {
+		return false
+	}
+	_, ok = isValue(node.Right)
+	return ok
+}
+
+func (f *formatter) value(expr *grammar.ExpressionValue) {
+	if expr == nil {
+		return
+	}
+	f.mathOperand(expr.Left)
+	if expr.Operator == grammar.MathOpValue {
+		return
+	}
+	if !f.compact {
+		f.write(" " + expr.Operator.String() + " ")
+		f.mathOperand(expr.Right)
+		return
+	}
+	// the slashes following identifiers would be parts of them, and the
+	// ones preceding JSON Pointers their first slash
+	if expr.Operator == grammar.MathOpDiv || f.spaceAfter(operandLastValue(expr.Left), true) {
+		f.write(" ")
+	}
+	f.write(expr.Operator.String())
+	if expr.Operator == grammar.MathOpDiv && f.dialect == grammar.SelectorDialectJSONPointer {
+		f.write(" ")
+	}
+	f.mathOperand(expr.Right)
+}
+
+func (f *formatter) mathOperand(operand interface{}) {
+	switch node := operand.(type) {
+	case *grammar.ExpressionValue:
+		if node.Operator == grammar.MathOpValue {
+			f.value(node)
+			return
+		}
+		f.write("(")
+		f.value(node)
+		f.write(")")
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeString:
+			if f.compact {
+				f.write(minifyString(node.Raw))
+			} else {
+				f.write(formatString(node.Raw))
+			}
+		case grammar.ValueTypeFloat64:
+			if f.compact {
+				f.write(minifyFloat(node.Raw))
+			} else {
+				f.write(node.Raw)
+			}
+		case grammar.ValueTypeReflect:
+			f.selector(node.Selector)
+		case grammar.ValueTypeParam:
+			f.write("$" + node.Raw)
+		case grammar.ValueTypeCall:
+			f.write(node.Raw + "(")
+			for i, arg := range node.Args {
+				if i > 0 && f.dialect == grammar.SelectorDialectJSONPointer && endsWithSelector(node.Args[i-1]) {
+					// JSON Pointers run up to the next whitespace, commas included
+					f.write(" ")
+				}
+				if i > 0 && f.compact {
+					f.write(",")
+				} else if i > 0 {
+					f.write(", ")
+				}
+				f.value(arg)
+			}
+			f.write(")")
+		default:
+			f.write(node.Raw)
+		}
+	}
+}
+
+// endsWithSelector reports whether the last operand of the value is a selector
+func endsWithSelector(expr *grammar.ExpressionValue) bool {
+	last := expr.Left
+	if expr.Operator != grammar.MathOpValue {
+		last = expr.Right
+	}
+	for {
+		switch v := last.(type) {
+		case *grammar.ExpressionValue:
+			if v.Operator == grammar.MathOpValue {
+				last = v.Left
+			} else {
+				last = v.Right
+			}
+		case *grammar.MatchValue:
+			return v.Type == grammar.ValueTypeReflect
+		default:
+			return false
+		}
+	}
+}
+
+func isKeyword(word string) bool {
+	for _, keyword := range grammar.Keywords {
+		if word == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// formatString quotes the string. Double quoted strings cannot hold double
+// quotes, even escaped, and the ones which read as JSON Pointers, such as ""
+// or "/usr", are selectors: those are written as raw strings.
+func formatString(s string) string {
+	if jsonPointerLiteralRe.MatchString(s) {
+		return "`" + s + "`"
+	}
+	return strings.ReplaceAll(strconv.Quote(s), `\"`, `\x22`)
+}
+
+// selector writes the selector in the dialect of the formatter. In the bexpr
+// dialect, selectors are written in the bexpr syntax when they are anchored
+// or their first part is an identifier, escaping the keywords such as
+// ["in"], and as JSON Pointers otherwise. The selectors with ranges, which
+// JSON Pointers cannot hold, are written in the bexpr syntax only.
+func (f *formatter) selector(sel grammar.Selector) {
+	switch f.dialect {
+	case grammar.SelectorDialectJSONPointer, grammar.SelectorDialectJSONPath:
+		if len(sel.Ranges) > 0 {
+			if f.err == nil {
+				f.err = fmt.Errorf("cannot write the ranges of %s in the %s dialect", sel, f.dialect)
+			}
+			return
+		}
+		if f.dialect == grammar.SelectorDialectJSONPointer {
+			f.jsonPointer(sel.Path)
+		} else {
+			f.jsonPath(sel.Path)
+		}
+		return
+	}
+	if sel.Anchored && len(sel.Path) > 0 {
+		f.write("$")
+		f.selectorParts(sel, 0)
+		return
+	}
+	if len(sel.Path) > 0 && identifierRe.MatchString(sel.Path[0]) && !literalKeywords[sel.Path[0]] {
+		if isKeyword(sel.Path[0]) {
+			f.write("[")
+			f.write(formatString(sel.Path[0]))
+			f.write("]")
+		} else {
+			f.write(sel.Path[0])
+		}
+		f.selectorRanges(sel, 1)
+		f.selectorParts(sel, 1)
+		return
+	}
+	if len(sel.Path) > 0 && len(sel.Ranges) > 0 {
+		f.write("[")
+		f.write(formatString(sel.Path[0]))
+		f.write("]")
+		f.selectorRanges(sel, 1)
+		f.selectorParts(sel, 1)
+		return
+	}
+
+	f.write(`"`)
+	for _, part := range sel.Path {
+		f.write("/")
+		f.write(jsonPointerEscaper.Replace(part))
+	}
+	f.write(`"`)
+}
+
+// jsonPointer writes the path as a JSON Pointer of the JSON Pointer dialect
+func (f *formatter) jsonPointer(path []string) {
+	for _, part := range path {
+		if strings.ContainsAny(part, jsonPointerStopChars) {
+			if f.err == nil {
+				f.err = fmt.Errorf("cannot write %q as a part of a JSON Pointer", part)
+			}
+			return
+		}
+		f.write("/")
+		f.write(jsonPointerEscaper.Replace(part))
+	}
+}
+
+// jsonPath writes the path as a JSONPath of the JSONPath dialect
+func (f *formatter) jsonPath(path []string) {
+	f.write("$")
+	for _, part := range path {
+		switch {
+		case jsonPathNameRe.MatchString(part):
+			f.write(".")
+			f.write(part)
+		case jsonPathIndexRe.MatchString(part):
+			f.write("[" + part + "]")
+		default:
+			f.write("[")
+			f.write(formatString(part))
+			f.write("]")
+		}
+	}
+}
+
+// selectorParts writes the parts of a selector from the one at start, each
+// followed by its ranges
+func (f *formatter) selectorParts(sel grammar.Selector, start int) {
+	for i, part := range sel.Path[start:] {
+		if identifierRe.MatchString(part) || indexRe.MatchString(part) {
+			f.write(".")
+			f.write(part)
+		} else {
+			f.write("[")
+			f.write(formatString(part))
+			f.write("]")
+		}
+		f.selectorRanges(sel, start+i+1)
+	}
+}
+
+// selectorRanges writes the ranges of a selector following its first at parts
+func (f *formatter) selectorRanges(sel grammar.Selector, at int) {
+	for _, r := range sel.Ranges {
+		if r.At == at {
+			f.write(r.String())
+		}
+	}
+}
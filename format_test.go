@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatExpression(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"match":             `Name == "web"`,
+		"in":                `"prod" in Tags`,
+		"not in":            `"prod" not in Meta.tags`,
+		"contains":          `Tags contains "prod"`,
+		"unary operators":   `Tags is not empty and Owner is null`,
+		"precedence":        `(a == 1 or b == 2) and c == 3 or not (d == 4 and e == 5)`,
+		"not":               `not a == 1`,
+		"let":               `let t = Meta.tier in t == "gold" or t == "platinum"`,
+		"let operand":       `(let t = Meta.tier in t == "gold") and a == 1`,
+		"math":              `Port + 1 > 8080 and Ratio * 2.5 <= 10`,
+		"literals":          `a == true and b != null and c == -1 and d == 1.5 and e == undefined`,
+		"quotes":            "Name == `say \"hi\"` and Path matches \"a\\\\.b\\n\"",
+		"param":             `Owner == $user`,
+		"json pointer":      `"/Meta/a~1b" == 1 and "/true/x" == 2`,
+		"index":             `Meta["a b"] == 1 and Meta.0 == 2`,
+		"bool value":        `Enabled and not true`,
+		"string operators":  `Name startswith "a" and Name not endswith "b" and Name like "c*" and Name not matches "d"`,
+		"ordering":          `a < 1 and b >= 2 and c > 3`,
+		"nested same chain": `a == 1 and (b == 2 and c == 3)`,
+		"keywords":          `["not"] is empty and ["in"].and == 1`,
+		"anchored":          `$.Meta.env == "prod" and $["a b"].0 == 1`,
+	}
+
+	for name, expression := range tests {
+		expression := expression
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ast, err := grammar.Parse("", []byte(expression))
+			require.NoError(t, err)
+
+			formatted := formatExpression(ast.(grammar.Expression))
+			reparsed, err := grammar.Parse("", []byte(formatted))
+			require.NoError(t, err, formatted)
+			require.Equal(t, formatExpression(ast.(grammar.Expression)), formatExpression(reparsed.(grammar.Expression)))
+			equivalent, err := Equivalent(expression, formatted)
+			require.NoError(t, err)
+			require.True(t, equivalent, formatted)
+		})
+	}
+}
+
+func TestFormatString(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"", "/usr", "/usr/bin", "/a b", "say \"hi\"", "`/x`"} {
+		formatted := formatString(s)
+		parsed, err := grammar.Parse("", []byte("a == "+formatted))
+		require.NoError(t, err, formatted)
+		value := parsed.(*grammar.MatchExpression).Right.Left.(*grammar.MatchValue)
+		require.Equal(t, grammar.ValueType(grammar.ValueTypeString), value.Type, formatted)
+		require.Equal(t, s, value.Raw, formatted)
+	}
+}
+
+func TestEvaluator_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`(Port >   8000 or Role == "admin")  and Env == "prod"`)
+	require.NoError(t, err)
+
+	var b strings.Builder
+	n, err := eval.WriteTo(&b)
+	require.NoError(t, err)
+	require.Equal(t, `(Port > 8000 or Role == "admin") and Env == "prod"`, b.String())
+	require.Equal(t, int64(b.Len()), n)
+}
+
+func TestEvaluator_WriteTo_Generated(t *testing.T) {
+	t.Parallel()
+
+	type meta struct {
+		Env   string
+		Count uint8
+	}
+	type service struct {
+		Name    string
+		Port    int
+		Weight  float64
+		Enabled bool
+		Tags    []string
+		Labels  map[string]string
+		Meta    *meta
+		Any     interface{}
+	}
+	datum := service{
+		Name:    "a-b9",
+		Port:    8,
+		Weight:  -1.5,
+		Enabled: true,
+		Tags:    []string{"x", "a-b"},
+		Labels:  map[string]string{"a": "b"},
+		Meta:    &meta{Env: "xyz", Count: 3},
+		Any:     "c",
+	}
+
+	// the evaluations of the expressions and of their formatting agree, and
+	// the explanations match the outcomes
+	schema := &grammar.GenerateSchema{Fields: grammar.FieldsOf(datum, "bexpr")}
+	for seed := int64(0); seed < 200; seed++ {
+		expression := grammar.Generate(rand.New(rand.NewSource(seed)), schema)
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err, expression)
+		result, err := eval.Evaluate(datum)
+		require.NoError(t, err, expression)
+
+		var b strings.Builder
+		_, err = eval.WriteTo(&b)
+		require.NoError(t, err)
+		formatted, err := CreateEvaluator(b.String())
+		require.NoError(t, err, b.String())
+		formattedResult, err := formatted.Evaluate(datum)
+		require.NoError(t, err, b.String())
+		require.Equal(t, result, formattedResult, "%s\n%s", expression, b.String())
+
+		explained, _, err := eval.Explain(datum)
+		require.NoError(t, err, expression)
+		require.Equal(t, result, explained, expression)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		cfg        FormatConfig
+		expected   string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"spacing":     {expression: "( a==1 or b==`x` ) and\n c>2", expected: `(a == 1 or b == "x") and c > 2`},
+		"parentheses": {expression: `((a == 1)) and (b == 2 and (c == 3))`, expected: `a == 1 and b == 2 and c == 3`},
+		"quoting":     {expression: "a == `say \"hi\"` and b == `/usr` and c == `\\n`", expected: "a == \"say \\x22hi\\x22\" and b == `/usr` and c == \"\\\\n\""},
+		"in":          {expression: `"a"  in Tags and 1 not in Meta.ports and Tags contains Name`, expected: `"a" in Tags and 1 not in Meta.ports and Name in Tags`},
+		"in math":     {expression: `Tags contains Port + 1`, expected: `Tags contains Port + 1`},
+		"not folded":  {expression: `Port > 8000 + 80 and true`, expected: `Port > 8000 + 80 and true`},
+		"fits":        {expression: `a == 1 and b == 2`, cfg: FormatConfig{Width: 17}, expected: `a == 1 and b == 2`},
+		"chain": {
+			expression: `Meta.env == "prod" and Port > 8000 and Name startswith "web"`,
+			cfg:        FormatConfig{Width: 40},
+			expected:   "Meta.env == \"prod\"\nand Port > 8000\nand Name startswith \"web\"",
+		},
+		"nested": {
+			expression: `Meta.env == "prod" and (Port > 8000 or Port < 1000 or Name == "web") and not (a == 1 and b == 2)`,
+			cfg:        FormatConfig{Width: 30, Indent: "  "},
+			expected:   "Meta.env == \"prod\"\nand (\n  Port > 8000\n  or Port < 1000\n  or Name == \"web\"\n)\nand not (a == 1 and b == 2)",
+		},
+		"and within or": {
+			expression: `Meta.env == "prod" and Port > 8000 or Meta.env == "dev"`,
+			cfg:        FormatConfig{Width: 30},
+			expected:   "Meta.env == \"prod\"\n\tand Port > 8000\nor Meta.env == \"dev\"",
+		},
+		"let": {
+			expression: `let t = Meta.tier in t == "gold" or t == "platinum"`,
+			cfg:        FormatConfig{Width: 30},
+			expected:   "let t = Meta.tier in\nt == \"gold\" or t == \"platinum\"",
+		},
+		"dialect": {expression: `/Meta/env=="prod"`, cfg: FormatConfig{Dialect: grammar.SelectorDialectJSONPointer}, expected: `/Meta/env == "prod"`},
+		"syntax":  {expression: `a ==`, err: "syntax"},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted, err := Format(tcase.expression, tcase.cfg)
+			if tcase.err != "" {
+				require.ErrorIs(t, err, ErrSyntax)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, formatted)
+
+			if tcase.cfg.Dialect == grammar.SelectorDialectBexpr {
+				equivalent, err := Equivalent(tcase.expression, formatted)
+				require.NoError(t, err)
+				require.True(t, equivalent)
+			}
+		})
+	}
+}
+
+// The expressions formatted in narrow lines parse to the same AST
+func TestFormat_Generated(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string
+		Port int
+		Tags []string
+		Meta map[string]string
+	}
+	schema := &grammar.GenerateSchema{Fields: grammar.FieldsOf(service{}, "bexpr")}
+	for seed := int64(0); seed < 200; seed++ {
+		expression := grammar.Generate(rand.New(rand.NewSource(seed)), schema)
+		ast, err := grammar.Parse("", []byte(expression))
+		require.NoError(t, err, expression)
+
+		formatted, err := Format(expression, FormatConfig{Width: 20})
+		require.NoError(t, err, expression)
+		reparsed, err := grammar.Parse("", []byte(formatted))
+		require.NoError(t, err, formatted)
+		require.Equal(t, formatExpression(ast.(grammar.Expression)), formatExpression(reparsed.(grammar.Expression)), formatted)
+	}
+}
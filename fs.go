@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.16
+
+package bexpr
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// fileSource is the SelectorSource of the metadata of a file
+type fileSource struct {
+	path string
+	info fs.FileInfo
+}
+
+// NewFileSource returns the SelectorSource of the metadata of the file at the
+// slash-separated path, such as the files of an fs.FS walk, to be evaluated
+// instead of the file. The selectors are:
+//
+//	path       the path of the file, such as "docs/index.md"
+//	name       the base name of the file, such as "index.md"
+//	ext        the extension of the name, such as ".md", or ""
+//	dir        the directory of the path, such as "docs", or "."
+//	size       the length in bytes of regular files
+//	mode       the mode bits, formatted like "-rw-r--r--" or "drwxr-xr-x"
+//	type       "file", "dir", "symlink" or "other"
+//	is_dir     whether the file is a directory
+//	mod_time   the modification time, compared with other times and with RFC
+//	           3339 strings, such as mod_time > "2024-05-01T00:00:00Z"
+//
+// so that a backup may exclude `ext == ".tmp" or size > 1073741824`.
+func NewFileSource(path string, info fs.FileInfo) SelectorSource {
+	return fileSource{path: path, info: info}
+}
+
+func (s fileSource) GetPath(selector []string) (interface{}, bool, error) {
+	if len(selector) != 1 {
+		return nil, false, nil
+	}
+	switch selector[0] {
+	case "path":
+		return s.path, true, nil
+	case "name":
+		return s.info.Name(), true, nil
+	case "ext":
+		return path.Ext(s.info.Name()), true, nil
+	case "dir":
+		return path.Dir(s.path), true, nil
+	case "size":
+		return s.info.Size(), true, nil
+	case "mode":
+		return s.info.Mode().String(), true, nil
+	case "type":
+		return fileType(s.info.Mode()), true, nil
+	case "is_dir":
+		return s.info.IsDir(), true, nil
+	case "mod_time":
+		return fileTime(s.info.ModTime()), true, nil
+	}
+	return nil, false, nil
+}
+
+func fileType(mode fs.FileMode) string {
+	switch {
+	case mode.IsRegular():
+		return "file"
+	case mode.IsDir():
+		return "dir"
+	case mode&fs.ModeSymlink != 0:
+		return "symlink"
+	}
+	return "other"
+}
+
+// fileTime is the modification time of a file, compared with other times and
+// with RFC 3339 strings
+type fileTime time.Time
+
+func (t fileTime) Compare(other interface{}) (int, error) {
+	var o time.Time
+	switch v := other.(type) {
+	case fileTime:
+		o = time.Time(v)
+	case time.Time:
+		o = v
+	case string:
+		var err error
+		if o, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return 0, fmt.Errorf("%q is not an RFC 3339 time", v)
+		}
+	default:
+		return 0, fmt.Errorf("%v is not a time", other)
+	}
+	switch {
+	case time.Time(t).Before(o):
+		return -1, nil
+	case time.Time(t).After(o):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats the time as RFC 3339
+func (t fileTime) String() string {
+	return time.Time(t).Format(time.RFC3339Nano)
+}
+
+// FilterFS walks the file tree of fsys rooted at root like fs.WalkDir,
+// calling fn for the files and directories matching the expression of the
+// evaluator, evaluated against their NewFileSource. Directories which do not
+// match are still walked: rules meant to exclude whole trees select them by
+// path, such as `not (path matches "(^|/)node_modules(/|$)")`, and fn may
+// return fs.SkipDir for the directories it is called with. The errors of the
+// walk are passed to fn the way fs.WalkDir passes them, and the walk stops
+// at the first error of the evaluation.
+func FilterFS(fsys fs.FS, root string, eval *Evaluator, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(p, d, err)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fn(p, d, err)
+		}
+		result, err := eval.Evaluate(NewFileSource(p, info))
+		if err != nil {
+			return fmt.Errorf("failed to evaluate %s: %w", p, err)
+		}
+		if match, _ := CoerceBool(result); !match {
+			return nil
+		}
+		return fn(p, d, nil)
+	})
+}
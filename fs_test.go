@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.16
+
+package bexpr
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFS() fstest.MapFS {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"README.md":                  {Data: []byte("# readme"), ModTime: modTime},
+		"docs/index.md":              {Data: []byte("# index"), ModTime: modTime.Add(-48 * time.Hour)},
+		"docs/draft.tmp":             {Data: []byte("draft"), ModTime: modTime},
+		"node_modules/left/index.js": {Data: []byte("module.exports = 1"), ModTime: modTime},
+		"bin/tool":                   {Data: make([]byte, 2048), Mode: 0755, ModTime: modTime},
+		"link":                       {Data: []byte("README.md"), Mode: fs.ModeSymlink | 0777, ModTime: modTime},
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	t.Parallel()
+
+	fsys := newTestFS()
+	info, err := fs.Stat(fsys, "docs/index.md")
+	require.NoError(t, err)
+	src := NewFileSource("docs/index.md", info)
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"path":            {expression: `path == "docs/index.md"`, result: true},
+		"name":            {expression: `name == "index.md"`, result: true},
+		"ext":             {expression: `ext == ".md"`, result: true},
+		"dir":             {expression: `dir == "docs"`, result: true},
+		"size":            {expression: `size == 7`, result: true},
+		"mode":            {expression: `mode == "----------"`, result: true},
+		"type":            {expression: `type == "file" and not is_dir`, result: true},
+		"mod_time":        {expression: `mod_time < "2024-05-01T00:00:00Z" and mod_time >= "2024-04-29T12:00:00Z"`, result: true},
+		"missing":         {expression: `owner is empty`, result: true},
+		"invalid time":    {expression: `mod_time < "yesterday"`, err: `"yesterday" is not an RFC 3339 time`},
+		"nested selector": {expression: `name.first == "x"`, result: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(src)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestFilterFS(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		skip       string
+		paths      []string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"include": {
+			expression: `ext == ".md"`,
+			paths:      []string{"README.md", "docs/index.md"},
+		},
+		"exclude": {
+			expression: `not is_dir and not (ext == ".tmp" or path matches "(^|/)node_modules(/|$)")`,
+			paths:      []string{"README.md", "bin/tool", "docs/index.md", "link"},
+		},
+		"types": {
+			expression: `type == "symlink" or (type == "file" and mode == "-rwxr-xr-x" and size > 1024)`,
+			paths:      []string{"bin/tool", "link"},
+		},
+		"skip dir": {
+			expression: `is_dir or name == "index.js"`,
+			skip:       "node_modules",
+			paths:      []string{".", "bin", "docs", "node_modules"},
+		},
+		"evaluation error": {
+			expression: `mod_time > 1`,
+			err:        `failed to evaluate .: `,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			var paths []string
+			err = FilterFS(newTestFS(), ".", eval, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				paths = append(paths, path)
+				if path == tcase.skip {
+					return fs.SkipDir
+				}
+				return nil
+			})
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.paths, paths)
+		})
+	}
+}
+
+func TestFilterFS_WalkError(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`is_dir`)
+	require.NoError(t, err)
+
+	var walkErr error
+	err = FilterFS(newTestFS(), "missing", eval, func(path string, d fs.DirEntry, err error) error {
+		walkErr = err
+		return err
+	})
+	require.True(t, errors.Is(err, fs.ErrNotExist))
+	require.Equal(t, err, walkErr)
+}
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// function is a built-in function of the expressions, see grammar.Functions
+type function struct {
+	// call returns the result of the function for the values of its
+	// arguments, none of which is missing or null
+	call func(args []interface{}) (interface{}, error)
+	// result returns the type of the result of the function for the types of
+	// its arguments, a nil type being known at evaluation time only
+	result func(args []reflect.Type) (reflect.Type, error)
+}
+
+var functions = map[string]function{
+	"keys":    {call: callKeys, result: keysType},
+	"values":  {call: callValues, result: valuesType},
+	"len":     {call: callLen, result: lenType},
+	"count":   {call: callLen, result: lenType},
+	"lower":   {call: stringFunction(strings.ToLower), result: stringType},
+	"upper":   {call: stringFunction(strings.ToUpper), result: stringType},
+	"trim":    {call: stringFunction(strings.TrimSpace), result: stringType},
+	"replace": {call: callReplace, result: stringType},
+}
+
+// getCallValue calls the function with the values of its arguments. Like the
+// operands of math operators, missing and null arguments make the result
+// missing or null respectively.
+func getCallValue(call *grammar.MatchValue, datum interface{}, opt ...Option) (interface{}, error) {
+	fn, ok := functions[call.Raw]
+	if !ok {
+		return &undefined, fmt.Errorf("unknown function %s", call.Raw)
+	}
+	args := make([]interface{}, len(call.Args))
+	for i, arg := range call.Args {
+		value, err := getExprValue(arg, datum, opt...)
+		if err != nil || isUndefined(value) {
+			return &undefined, err
+		}
+		if isNull(value) {
+			return nil, nil
+		}
+		args[i] = indirect(value)
+	}
+	val, err := fn.call(args)
+	if err != nil {
+		return &undefined, fmt.Errorf("%s: %w", call, err)
+	}
+	return val, nil
+}
+
+// callType returns the type of the result of the call, checking the types of
+// its arguments
+func callType(call *grammar.MatchValue, schema Schema) (reflect.Type, error) {
+	fn, ok := functions[call.Raw]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", call.Raw)
+	}
+	args := make([]reflect.Type, len(call.Args))
+	for i, arg := range call.Args {
+		typ, err := valueType(arg, schema)
+		if err != nil {
+			return nil, err
+		}
+		if typ != nil && typ != interfaceTyp {
+			args[i] = derefType(typ)
+		}
+	}
+	typ, err := fn.result(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", call, err)
+	}
+	return typ, nil
+}
+
+// callKeys returns the keys of a map, in their order, see sortMapKeys
+func callKeys(args []interface{}) (interface{}, error) {
+	m := reflect.ValueOf(args[0])
+	if m.Kind() != reflect.Map {
+		return nil, fmt.Errorf("not a map but a %s", m.Kind())
+	}
+	keys := sortMapKeys(m)
+	result := reflect.MakeSlice(reflect.SliceOf(m.Type().Key()), len(keys), len(keys))
+	for i, key := range keys {
+		result.Index(i).Set(key)
+	}
+	return result.Interface(), nil
+}
+
+func keysType(args []reflect.Type) (reflect.Type, error) {
+	switch {
+	case args[0] == nil:
+		return nil, nil
+	case args[0].Kind() != reflect.Map:
+		return nil, fmt.Errorf("not a map but a %s", args[0].Kind())
+	}
+	return reflect.SliceOf(args[0].Key()), nil
+}
+
+// callValues returns the values of a map, in the order of their keys, see
+// sortMapKeys, or the elements of a slice or an array
+func callValues(args []interface{}) (interface{}, error) {
+	v := reflect.ValueOf(args[0])
+	var values reflect.Value
+	switch v.Kind() {
+	case reflect.Map:
+		keys := sortMapKeys(v)
+		values = reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), len(keys), len(keys))
+		for i, key := range keys {
+			values.Index(i).Set(v.MapIndex(key))
+		}
+	case reflect.Slice, reflect.Array:
+		values = reflect.MakeSlice(reflect.SliceOf(v.Type().Elem()), v.Len(), v.Len())
+		reflect.Copy(values, v)
+	default:
+		return nil, fmt.Errorf("not a map, a slice or an array but a %s", v.Kind())
+	}
+	return values.Interface(), nil
+}
+
+func valuesType(args []reflect.Type) (reflect.Type, error) {
+	if args[0] == nil {
+		return nil, nil
+	}
+	switch args[0].Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return reflect.SliceOf(args[0].Elem()), nil
+	}
+	return nil, fmt.Errorf("not a map, a slice or an array but a %s", args[0].Kind())
+}
+
+// callLen returns the number of characters of a string or the number of
+// elements of a map, a slice or an array, as an int64 like the integers of
+// the expressions
+func callLen(args []interface{}) (interface{}, error) {
+	v := reflect.ValueOf(args[0])
+	switch v.Kind() {
+	case reflect.String:
+		return int64(utf8.RuneCountInString(v.String())), nil
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return int64(v.Len()), nil
+	}
+	return nil, fmt.Errorf("not a string, a map, a slice or an array but a %s", v.Kind())
+}
+
+func lenType(args []reflect.Type) (reflect.Type, error) {
+	if args[0] == nil {
+		return reflect.TypeOf(int64(0)), nil
+	}
+	switch args[0].Kind() {
+	case reflect.String, reflect.Map, reflect.Slice, reflect.Array:
+		return reflect.TypeOf(int64(0)), nil
+	}
+	return nil, fmt.Errorf("not a string, a map, a slice or an array but a %s", args[0].Kind())
+}
+
+// stringFunction returns the function transforming its string argument with fn
+func stringFunction(fn func(string) string) func([]interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(s), nil
+	}
+}
+
+// callReplace replaces the occurrences of its second argument in the first
+// one with the third one
+func callReplace(args []interface{}) (interface{}, error) {
+	strs := make([]string, len(args))
+	for i, arg := range args {
+		s, err := stringArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+	return strings.ReplaceAll(strs[0], strs[1], strs[2]), nil
+}
+
+func stringArg(arg interface{}) (string, error) {
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.String {
+		return "", fmt.Errorf("not a string but a %s", v.Kind())
+	}
+	return v.String(), nil
+}
+
+func stringType(args []reflect.Type) (reflect.Type, error) {
+	for _, arg := range args {
+		if arg != nil && arg.Kind() != reflect.String {
+			return nil, fmt.Errorf("not a string but a %s", arg.Kind())
+		}
+	}
+	return stringTyp, nil
+}
+
+// sortMapKeys returns the keys of the map in order: the numbers and the
+// strings in their natural order, false before true, and the other keys in
+// the order of the values WithDeterministic writes, so that the results of
+// keys and values do not depend on the iteration order of maps
+func sortMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.Kind() == reflect.Interface {
+			a, b = a.Elem(), b.Elem()
+		}
+		if a.IsValid() && b.IsValid() && a.Kind() == b.Kind() {
+			switch a.Kind() {
+			case reflect.String:
+				return a.String() < b.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			case reflect.Bool:
+				return !a.Bool() && b.Bool()
+			}
+		}
+		return deterministicValue(keys[i].Interface()) < deterministicValue(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,354 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+type functionsService struct {
+	Name     string
+	Meta     map[string]string
+	Ports    map[int]string
+	Replicas []string
+	Weights  map[string]int
+}
+
+func TestKeysValues(t *testing.T) {
+	t.Parallel()
+
+	service := functionsService{
+		Name:     "web",
+		Meta:     map[string]string{"env": "prod", "team": "ops", "region": "eu"},
+		Ports:    map[int]string{443: "https", 80: "http"},
+		Replicas: []string{"a", "b"},
+		Weights:  map[string]int{"a": 2, "b": 1},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"keys":               {expression: `"env" in keys(Meta) and "prod" not in keys(Meta)`, result: true},
+		"values":             {expression: `"prod" in values(Meta) and "env" not in values(Meta)`, result: true},
+		"contains":           {expression: `values(Meta) contains "ops"`, result: true},
+		"int keys":           {expression: `443 in keys(Ports) and 8080 not in keys(Ports)`, result: true},
+		"int values":         {expression: `values(Weights) contains 2`, result: true},
+		"slice values":       {expression: `"b" in values(Replicas)`, result: true},
+		"is empty":           {expression: `keys(Meta) is not empty and values(Meta) is not empty`, result: true},
+		"empty map":          {expression: `keys(Meta) is empty and values(Meta) is empty`, datum: map[string]interface{}{"Meta": map[string]interface{}{}}, result: true},
+		"nested":             {expression: `"prod" in values(Meta.labels)`, datum: map[string]interface{}{"Meta": map[string]interface{}{"labels": map[string]interface{}{"env": "prod"}}}, result: true},
+		"missing":            {expression: `"prod" in values(Meta.labels)`, datum: map[string]interface{}{"Meta": map[string]interface{}{}}, result: false},
+		"missing negated":    {expression: `"prod" not in values(Meta.labels)`, datum: map[string]interface{}{"Meta": map[string]interface{}{}}, result: true},
+		"null":               {expression: `keys(Meta) is empty`, datum: map[string]interface{}{"Meta": nil}, result: true},
+		"let":                {expression: `let m = Meta in "team" in keys(m)`, result: true},
+		"compared selectors": {expression: `Name in values(Replicas)`, datum: map[string]interface{}{"Name": "b", "Replicas": []interface{}{"a", "b"}}, result: true},
+		"not a map":          {expression: `"w" in keys(Name)`, err: `1:1 (0): keys(Name) contains "w": keys(Name): not a map but a string`},
+		"not a collection":   {expression: `"w" in values(Name)`, err: `1:1 (0): values(Name) contains "w": values(Name): not a map, a slice or an array but a string`},
+		"unknown selector":   {expression: `"w" in keys(Labels)`, err: `1:1 (0): keys(Labels) contains "w": error finding value in datum: /Labels at part 0: couldn't find key: struct field with name "Labels"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			datum := tcase.datum
+			if datum == nil {
+				datum = service
+			}
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestKeysValues_Order(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]string{"env": "prod", "team": "ops", "region": "eu", "app": "web"}
+	keys, err := callKeys([]interface{}{meta})
+	require.NoError(t, err)
+	require.Equal(t, []string{"app", "env", "region", "team"}, keys)
+	values, err := callValues([]interface{}{meta})
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "prod", "eu", "ops"}, values)
+
+	keys, err = callKeys([]interface{}{map[int]bool{10: true, -1: false, 2: true}})
+	require.NoError(t, err)
+	require.Equal(t, []int{-1, 2, 10}, keys)
+	keys, err = callKeys([]interface{}{map[interface{}]int{"b": 1, "a": 2, 1.5: 3}})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"a", "b", 1.5}, keys)
+}
+
+func TestKeysValues_Selectors(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`"a" in keys(Meta) and "b" in values(labels)`,
+		WithFieldAliases(map[string]string{"labels": "Meta.labels"}), WithSelectorPrefix("Spec"))
+	require.NoError(t, err)
+	require.Equal(t, `keys($.Spec.Meta) contains "a" and values($.Spec.Meta.labels) contains "b"`, formatExpression(eval.ast))
+	var fields []string
+	for _, sel := range eval.Fields() {
+		fields = append(fields, sel.String())
+	}
+	require.Equal(t, []string{"$.Spec.Meta", "$.Spec.Meta.labels"}, fields)
+
+	_, err = CreateEvaluator(`"a" in keys(Secrets)`, WithDeniedSelectors("Secrets"))
+	var notAllowed *SelectorNotAllowedError
+	require.True(t, errors.As(err, &notAllowed))
+	require.Equal(t, "Secrets", notAllowed.Selector)
+
+	_, err = CreateEvaluator(`"a" in kees(Meta)`)
+	require.EqualError(t, err, `1:12 (11): rule "call": unknown function kees`)
+	_, err = CreateEvaluator(`"a" in keys(Meta, Labels)`)
+	require.EqualError(t, err, `1:26 (25): rule "call": keys takes 1 argument, not 2`)
+}
+
+func TestKeysValues_Schema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"keys":         {expression: `"env" in keys(Meta) and 80 in keys(Ports)`},
+		"values":       {expression: `"prod" in values(Meta) and "a" in values(Replicas)`},
+		"not a map":    {expression: `"w" in keys(Name)`, err: `keys(Name): not a map but a string`},
+		"not a slice":  {expression: `"w" in values(Name)`, err: `values(Name): not a map, a slice or an array but a string`},
+		"unknown":      {expression: `"w" in keys(Labels)`, err: `error finding value in schema: /Labels at part 0: couldn't find key: struct field with name "Labels"`},
+		"unsupported":  {expression: `keys(Meta) matches "a"`, err: `keys(Meta): operator "Matches" cannot be used with values of type []string`},
+		"supported in": {expression: `keys(Meta) contains "a"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(functionsService{})))
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	t.Parallel()
+
+	service := functionsService{
+		Name:     "wéb",
+		Meta:     map[string]string{"env": "prod", "team": "ops"},
+		Replicas: []string{"a", "b", "c"},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"slice":            {expression: `len(Replicas) >= 2 and len(Replicas) == 3`, result: true},
+		"map":              {expression: `len(Meta) == 2`, result: true},
+		"string":           {expression: `len(Name) == 3 and len(Name) < 64`, result: true},
+		"empty":            {expression: `len(Meta) == 0 and len(Name) == 0`, datum: map[string]interface{}{"Meta": map[string]interface{}{}, "Name": ""}, result: true},
+		"count":            {expression: `count(Replicas) > 2`, result: true},
+		"math":             {expression: `len(Replicas) * 2 == 6`, result: true},
+		"compared":         {expression: `len(Replicas) > len(Meta)`, result: true},
+		"composed":         {expression: `len(keys(Meta)) == len(values(Meta))`, result: true},
+		"array":            {expression: `len(Items) == 2`, datum: map[string]interface{}{"Items": [2]int{1, 2}}, result: true},
+		"missing":          {expression: `len(Meta.tags) > 0`, datum: map[string]interface{}{"Meta": map[string]interface{}{}}, result: false},
+		"missing negated":  {expression: `not len(Meta.tags) > 0`, datum: map[string]interface{}{"Meta": map[string]interface{}{}}, result: true},
+		"null":             {expression: `len(Tags) is null`, datum: map[string]interface{}{"Tags": nil}, result: true},
+		"not a collection": {expression: `len(Count) > 1`, datum: map[string]interface{}{"Count": 3}, err: `1:1 (0): len(Count) > 1: len(Count): not a string, a map, a slice or an array but a int`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			datum := tcase.datum
+			if datum == nil {
+				datum = service
+			}
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestLen_Schema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"collections":      {expression: `len(Meta) > 1 and len(Replicas) < 3 and len(Name) <= 64`},
+		"math":             {expression: `len(Replicas) + 1 > 2`},
+		"not a collection": {expression: `len(Weights.a) > 1`, err: `len(Weights.a): not a string, a map, a slice or an array but a int`},
+		"unknown":          {expression: `len(Labels) > 1`, err: `error finding value in schema: /Labels at part 0: couldn't find key: struct field with name "Labels"`},
+		"unsupported":      {expression: `len(Name) matches "1"`, err: `len(Name): operator "Matches" cannot be used with values of type int64`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(functionsService{})))
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStringFunctions(t *testing.T) {
+	t.Parallel()
+
+	service := functionsService{
+		Name:     "  Web ",
+		Meta:     map[string]string{"env": "Prod"},
+		Replicas: []string{"web-1", "web-2"},
+		Weights:  map[string]int{"a": 2},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"lower":            {expression: `lower(trim(Name)) == "web"`, result: true},
+		"upper":            {expression: `upper(Meta.env) == "PROD"`, result: true},
+		"trim":             {expression: `trim(Name) == "Web" and Name != "Web"`, result: true},
+		"replace":          {expression: `replace(Replicas.0, "web-", "db-") == "db-1"`, result: true},
+		"replace nested":   {expression: `lower(replace(trim(Name), "W", "X")) == "xeb"`, result: true},
+		"operators":        {expression: `lower(Meta.env) in "production" and upper(Name) matches "WEB"`, result: true},
+		"right operand":    {expression: `"prod" == lower(Meta.env) and "web-1" in Replicas`, result: true},
+		"math":             {expression: `lower(trim(Name)) + "-1" == Replicas.0`, result: true},
+		"len":              {expression: `len(trim(Name)) == 3`, result: true},
+		"selector args":    {expression: `replace(Name, Name, Meta.env) == "Prod"`, result: true},
+		"missing":          {expression: `lower(Meta.zone) == "eu"`, result: false},
+		"missing negated":  {expression: `lower(Meta.zone) != "eu"`, result: true},
+		"null":             {expression: `upper(Name) is null`, datum: map[string]interface{}{"Name": nil}, result: true},
+		"not a string":     {expression: `lower(Weights.a) == "2"`, err: `1:1 (0): lower(Weights.a) == "2": lower(Weights.a): not a string but a int`},
+		"not a string arg": {expression: `replace(Name, "a", Weights.a) == "b"`, err: `1:1 (0): replace(Name, "a", Weights.a) == "b": replace(Name, "a", Weights.a): not a string but a int`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			datum := tcase.datum
+			if datum == nil {
+				datum = service
+			}
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestStringFunctions_Format(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`lower( trim(Name) )=="web" and replace(Name,"a" , "b") != "c"`)
+	require.NoError(t, err)
+	require.Equal(t, `lower(trim(Name)) == "web" and replace(Name, "a", "b") != "c"`, formatExpression(eval.ast))
+
+	formatted, err := Format(`replace(/Name , "a", /Meta/env) == "c"`, FormatConfig{Dialect: grammar.SelectorDialectJSONPointer})
+	require.NoError(t, err)
+	require.Equal(t, `replace(/Name , "a", /Meta/env) == "c"`, formatted)
+	minified, err := Minify(`replace(/Name , "a", /Meta/env) == "c"`, grammar.SelectorDialectJSONPointer)
+	require.NoError(t, err)
+	require.Equal(t, `replace(/Name ,"a",/Meta/env)=="c"`, minified)
+	_, err = CreateEvaluator(minified, WithSelectorDialect(grammar.SelectorDialectJSONPointer))
+	require.NoError(t, err)
+
+	_, err = CreateEvaluator(`replace(Name, "a") == "b"`)
+	require.EqualError(t, err, `1:19 (18): rule "call": replace takes 3 arguments, not 2`)
+}
+
+func TestStringFunctions_Schema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"strings":      {expression: `lower(trim(Name)) == "web" and replace(Meta.env, "a", "b") matches "^p"`},
+		"not a string": {expression: `upper(Weights.a) == "A"`, err: `upper(Weights.a): not a string but a int`},
+		"not a slice":  {expression: `"a" in lower(Name)`},
+		"unsupported":  {expression: `lower(Replicas) == "a"`, err: `lower(Replicas): not a string but a slice`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(functionsService{})))
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -6,7 +6,10 @@ package grammar
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+
+	"github.com/gterranova/go-bexpr/api"
 )
 
 // TODO - Probably should make most of what is in here un-exported
@@ -75,107 +78,38 @@ func (op MathOperator) String() string {
 	}
 }
 
-type MatchOperator int
+type MatchOperator = api.MatchOperator
 
 const (
-	MatchEqual MatchOperator = iota
-	MatchNotEqual
-	MatchIn
-	MatchNotIn
-	MatchIsEmpty
-	MatchIsNotEmpty
-	MatchMatches
-	MatchNotMatches
-	MatchLower
-	MatchLowerOrEqual
-	MatchHigher
-	MatchHigherOrEqual
+	MatchEqual         = api.MatchEqual
+	MatchNotEqual      = api.MatchNotEqual
+	MatchIn            = api.MatchIn
+	MatchNotIn         = api.MatchNotIn
+	MatchIsEmpty       = api.MatchIsEmpty
+	MatchIsNotEmpty    = api.MatchIsNotEmpty
+	MatchMatches       = api.MatchMatches
+	MatchNotMatches    = api.MatchNotMatches
+	MatchLower         = api.MatchLower
+	MatchLowerOrEqual  = api.MatchLowerOrEqual
+	MatchHigher        = api.MatchHigher
+	MatchHigherOrEqual = api.MatchHigherOrEqual
+	MatchIsNull        = api.MatchIsNull
+	MatchIsNotNull     = api.MatchIsNotNull
+	MatchStartsWith    = api.MatchStartsWith
+	MatchNotStartsWith = api.MatchNotStartsWith
+	MatchEndsWith      = api.MatchEndsWith
+	MatchNotEndsWith   = api.MatchNotEndsWith
+	MatchLike          = api.MatchLike
+	MatchNotLike       = api.MatchNotLike
 )
 
-func (op MatchOperator) String() string {
-	switch op {
-	case MatchEqual:
-		return "Equal"
-	case MatchNotEqual:
-		return "Not Equal"
-	case MatchIn:
-		return "In"
-	case MatchNotIn:
-		return "Not In"
-	case MatchIsEmpty:
-		return "Is Empty"
-	case MatchIsNotEmpty:
-		return "Is Not Empty"
-	case MatchMatches:
-		return "Matches"
-	case MatchNotMatches:
-		return "Not Matches"
-	case MatchLower:
-		return "Lower"
-	case MatchHigher:
-		return "Higher"
-	case MatchLowerOrEqual:
-		return "Lower or Equal"
-	case MatchHigherOrEqual:
-		return "Higher or Equal"
-	default:
-		return "UNKNOWN"
-	}
-}
-
-// NotPresentDisposition is called during evaluation when Selector fails to
-// find a map key to determine the operator's behavior.
-func (op MatchOperator) NotPresentDisposition() bool {
-	// For a selector M["x"] against a map M that lacks an "x" key...
-	switch op {
-	case MatchEqual:
-		// ...M["x"] == <anything> is false. Nothing is equal to a missing key
-		return false
-	case MatchNotEqual:
-		// ...M["x"] != <anything> is true. Nothing is equal to a missing key
-		return true
-	case MatchIn:
-		// "a" in M["x"] is false. Missing keys contain no values
-		return false
-	case MatchNotIn:
-		// "a" not in M["x"] is true. Missing keys contain no values
-		return true
-	case MatchIsEmpty:
-		// M["x"] is empty is true. Missing keys contain no values
-		return true
-	case MatchIsNotEmpty:
-		// M["x"] is not empty is false. Missing keys contain no values
-		return false
-	case MatchMatches:
-		// M["x"] matches <anything> is false. Nothing matches a missing key
-		return false
-	case MatchNotMatches:
-		// M["x"] not matches <anything> is true. Nothing matches a missing key
-		return true
-	case MatchLower:
-		// ...M["x"] < <anything> is false. Nothing is higher than a missing key
-		return true
-	case MatchHigher:
-		// ...M["x"] > <anything> is false. Nothing is higher than a missing key
-		return false
-	case MatchLowerOrEqual:
-		// ...M["x"] <= <anything> is false. Nothing is higher than a missing key
-		return true
-	case MatchHigherOrEqual:
-		// ...M["x"] => <anything> is false. Nothing is higher than a missing key
-		return false
-	default:
-		// Should never be reached as every operator should explicitly define its
-		// behavior.
-		return false
-	}
-}
-
 type MatchValue struct {
 	Selector  Selector
 	Type      ValueType
 	Raw       string
 	Converted interface{}
+	// Args are the arguments of the calls of functions
+	Args []*ExpressionValue
 }
 
 type UnaryExpression struct {
@@ -189,6 +123,17 @@ type BinaryExpression struct {
 	Right    Expression
 }
 
+// LetExpression binds the value of an expression value to a name within its
+// body, where the selectors starting with the name reference the value, such
+// as t in `let t = Meta.tier in (t == "gold" or t == "platinum")`.
+type LetExpression struct {
+	Name  string
+	Value *ExpressionValue
+	Body  Expression
+	// Position is where the expression starts, see Positions
+	Position Position
+}
+
 type ExpressionValue struct {
 	Left     interface{} // *MatchValue or *EExpressionValue
 	Operator MathOperator
@@ -201,6 +146,7 @@ const (
 	SelectorTypeUnknown = iota
 	SelectorTypeBexpr
 	SelectorTypeJsonPointer
+	SelectorTypeJsonPath
 )
 
 type ValueType uint32
@@ -214,11 +160,113 @@ const (
 	ValueTypeFloat64
 	ValueTypeString
 	ValueTypeReflect
+	ValueTypeNull
+	// ValueTypeParam is a parameter such as $user, Raw holding its name
+	ValueTypeParam
+	// ValueTypeCall is a call of a function such as keys(Meta), Raw holding
+	// its name and Args its arguments, see Functions
+	ValueTypeCall
 )
 
+func (val *MatchValue) String() string {
+	if val == nil {
+		return ""
+	}
+	if len(val.Selector.Path) > 0 {
+		return val.Selector.String()
+	}
+	switch val.Type {
+	case ValueTypeParam:
+		return "$" + val.Raw
+	case ValueTypeCall:
+		args := make([]string, len(val.Args))
+		for i, arg := range val.Args {
+			// the string arguments are quoted so that the commas separating the
+			// arguments are not mistaken for theirs
+			if lit, ok := arg.Left.(*MatchValue); ok && arg.Operator == MathOpValue && lit.Type == ValueTypeString {
+				args[i] = strconv.Quote(lit.Raw)
+				continue
+			}
+			args[i] = arg.String()
+		}
+		return val.Raw + "(" + strings.Join(args, ", ") + ")"
+	}
+	return val.Raw
+}
+
+func (expr *ExpressionValue) String() string {
+	if expr == nil {
+		return ""
+	}
+	if expr.Operator == MathOpValue {
+		return fmt.Sprintf("%v", expr.Left)
+	}
+	return fmt.Sprintf("%v %s %v", expr.Left, expr.Operator.String(), expr.Right)
+}
+
 type Selector struct {
 	Type SelectorType
 	Path []string
+	// Anchored is set on the selectors anchored at the root of the datum, such
+	// as $.Meta.env, which are never prefixed, see bexpr.WithSelectorPrefix, nor
+	// reference the names bound by let expressions
+	Anchored bool
+	// Ranges are the ranges of the elements of slices and arrays of the
+	// selector, in the order of the path, such as the [0:10] of
+	// Events[0:10].Type
+	Ranges []SelectorRange
+}
+
+// SelectorRange is a range of the elements of the slice or array found at the
+// first At parts of the path of a selector, from Low up to High excluded. The
+// bounds count from the end when negative, and are the start and the end of
+// the elements when nil. The parts of the path following the range select the
+// values of each element of the range.
+type SelectorRange struct {
+	At   int
+	Low  *int
+	High *int
+}
+
+func newSelectorRange(low, high interface{}) SelectorRange {
+	var r SelectorRange
+	if low != nil {
+		n := low.(int)
+		r.Low = &n
+	}
+	if high != nil {
+		n := high.(int)
+		r.High = &n
+	}
+	return r
+}
+
+// appendParts appends the parts and the ranges parsed to the selector, the
+// ranges following a part of it
+func (sel *Selector) appendParts(parts []interface{}) {
+	for _, part := range parts {
+		switch v := part.(type) {
+		case string:
+			sel.Path = append(sel.Path, v)
+		case SelectorRange:
+			v.At = len(sel.Path)
+			sel.Ranges = append(sel.Ranges, v)
+		}
+	}
+}
+
+func (r SelectorRange) String() string {
+	var b strings.Builder
+	b.WriteString("[")
+	if r.Low != nil {
+		b.WriteString(strconv.Itoa(*r.Low))
+	}
+	b.WriteString(":")
+	if r.High != nil {
+		b.WriteString(strconv.Itoa(*r.High))
+	}
+	b.WriteString("]")
+	return b.String()
 }
 
 func (sel Selector) String() string {
@@ -227,9 +275,31 @@ func (sel Selector) String() string {
 	}
 	switch sel.Type {
 	case SelectorTypeBexpr:
-		return strings.Join(sel.Path, ".")
+		var b strings.Builder
+		if sel.Anchored {
+			b.WriteString("$.")
+		}
+		ranges := sel.Ranges
+		for i, part := range sel.Path {
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(part)
+			for len(ranges) > 0 && ranges[0].At == i+1 {
+				b.WriteString(ranges[0].String())
+				ranges = ranges[1:]
+			}
+		}
+		return b.String()
 	case SelectorTypeJsonPointer:
 		return strings.Join(sel.Path, "/")
+	case SelectorTypeJsonPath:
+		var b strings.Builder
+		b.WriteString("$")
+		for _, part := range sel.Path {
+			b.WriteString("[" + strconv.Quote(part) + "]")
+		}
+		return b.String()
 	default:
 		return ""
 	}
@@ -239,33 +309,31 @@ type MatchExpression struct {
 	Operator MatchOperator
 	Left     *ExpressionValue
 	Right    *ExpressionValue
+	// Position is where the expression starts, see Positions
+	Position Position
 }
 
+// ExpressionDump writes the AST of the expression to w, see Dump
 func (expr *UnaryExpression) ExpressionDump(w io.Writer, indent string, level int) {
-	localIndent := strings.Repeat(indent, level)
-	fmt.Fprintf(w, "%s%s {\n", localIndent, expr.Operator.String())
-	expr.Operand.ExpressionDump(w, indent, level+1)
-	fmt.Fprintf(w, "%s}\n", localIndent)
+	(&dumper{w: w, cfg: DumpConfig{Indent: indent}}).expression(expr, level)
 }
 
+// ExpressionDump writes the AST of the expression to w, see Dump
 func (expr *BinaryExpression) ExpressionDump(w io.Writer, indent string, level int) {
-	localIndent := strings.Repeat(indent, level)
-	fmt.Fprintf(w, "%s%s {\n", localIndent, expr.Operator.String())
-	expr.Left.ExpressionDump(w, indent, level+1)
-	expr.Right.ExpressionDump(w, indent, level+1)
-	fmt.Fprintf(w, "%s}\n", localIndent)
+	(&dumper{w: w, cfg: DumpConfig{Indent: indent}}).expression(expr, level)
+}
+
+// ExpressionDump writes the AST of the expression to w, see Dump
+func (expr *LetExpression) ExpressionDump(w io.Writer, indent string, level int) {
+	(&dumper{w: w, cfg: DumpConfig{Indent: indent}}).expression(expr, level)
 }
 
+// ExpressionDump writes the AST of the expression to w, see Dump
 func (expr *ExpressionValue) ExpressionDump(w io.Writer, indent string, level int) {
-	localIndent := strings.Repeat(indent, level)
-	fmt.Fprintf(w, "%s%s %v %v\n", localIndent, expr.Left, expr.Operator.String(), expr.Right)
+	(&dumper{w: w, cfg: DumpConfig{Indent: indent}}).expression(expr, level)
 }
 
+// ExpressionDump writes the AST of the expression to w, see Dump
 func (expr *MatchExpression) ExpressionDump(w io.Writer, indent string, level int) {
-	switch expr.Operator {
-	case MatchEqual, MatchNotEqual, MatchIn, MatchNotIn, MatchLower, MatchHigher, MatchLowerOrEqual, MatchHigherOrEqual:
-		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[2]sValue: %[5]q\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), expr.Left.Left, expr.Right.Right)
-	default:
-		fmt.Fprintf(w, "%[1]s%[3]s {\n%[2]sSelector: %[4]v\n%[1]s}\n", strings.Repeat(indent, level), strings.Repeat(indent, level+1), expr.Operator.String(), expr.Left.Left)
-	}
+	(&dumper{w: w, cfg: DumpConfig{Indent: indent}}).expression(expr, level)
 }
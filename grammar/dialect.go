@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const selectorDialectKey = "selectorDialect"
+
+// SelectorDialect is the syntax selectors are written in, see Dialect
+type SelectorDialect int
+
+const (
+	// SelectorDialectBexpr is the default syntax: identifiers followed by
+	// parts and indexes, such as Meta.env, Tags.0 or Meta["a b"], JSON
+	// Pointers within double quotes, such as "/Meta/env", and selectors
+	// anchored at the root of the datum, such as $.Meta.env.
+	SelectorDialectBexpr SelectorDialect = iota
+	// SelectorDialectJSONPointer is the syntax of RFC 6901 JSON Pointers
+	// written as is, such as /Meta/env or /Meta/a~1b for the key "a/b".
+	// Quoted strings are always string literals.
+	SelectorDialectJSONPointer
+	// SelectorDialectJSONPath is the subset of JSONPath, see RFC 9535, made of
+	// the root followed by member names and array indexes, such as
+	// $.Meta.env, $.Tags[0] or $['Meta']['a b'].
+	SelectorDialectJSONPath
+)
+
+func (d SelectorDialect) String() string {
+	switch d {
+	case SelectorDialectBexpr:
+		return "bexpr"
+	case SelectorDialectJSONPointer:
+		return "JSON Pointer"
+	case SelectorDialectJSONPath:
+		return "JSONPath"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// SelectorType returns the type of the selectors written in the dialect. The
+// JSON Pointers written within double quotes in the bexpr dialect are of type
+// SelectorTypeJsonPointer too.
+func (d SelectorDialect) SelectorType() SelectorType {
+	switch d {
+	case SelectorDialectJSONPointer:
+		return SelectorTypeJsonPointer
+	case SelectorDialectJSONPath:
+		return SelectorTypeJsonPath
+	default:
+		return SelectorTypeBexpr
+	}
+}
+
+// Dialect creates an Option parsing the selectors in the dialect rather than
+// in the bexpr syntax. Bare identifiers are not selectors in the JSON Pointer
+// and JSONPath dialects, except for the names bound by let expressions, which
+// are referenced like the first part of any other selector, such as /t/a or
+// $.t.a for `let t = ...`.
+func Dialect(dialect SelectorDialect) Option {
+	return GlobalStore(selectorDialectKey, dialect)
+}
+
+func selectorDialect(c *current) SelectorDialect {
+	dialect, _ := c.globalStore[selectorDialectKey].(SelectorDialect)
+	return dialect
+}
+
+// identifierError fails the parse of values which are identifiers, once they
+// could not be parsed as anything else
+func identifierError(c *current, word string) error {
+	switch selectorDialect(c) {
+	case SelectorDialectJSONPointer:
+		return fmt.Errorf("selectors are written as JSON Pointers, such as /%s", word)
+	case SelectorDialectJSONPath:
+		return fmt.Errorf("selectors are written as JSONPaths, such as $.%s", word)
+	}
+	return reservedWordError(c, word)
+}
+
+// parseJSONPointer parses an RFC 6901 JSON Pointer, unescaping its segments
+func parseJSONPointer(pointer string) (Selector, error) {
+	sel := Selector{Type: SelectorTypeJsonPointer}
+	for _, segment := range strings.Split(pointer, "/")[1:] {
+		var b strings.Builder
+		for i := 0; i < len(segment); i++ {
+			if segment[i] != '~' {
+				b.WriteByte(segment[i])
+				continue
+			}
+			if i+1 == len(segment) || (segment[i+1] != '0' && segment[i+1] != '1') {
+				return Selector{}, fmt.Errorf("Invalid escape in JSON Pointer %s, ~ must be followed by 0 or 1", pointer)
+			}
+			i++
+			if segment[i] == '0' {
+				b.WriteByte('~')
+			} else {
+				b.WriteByte('/')
+			}
+		}
+		sel.Path = append(sel.Path, b.String())
+	}
+	return sel, nil
+}
+
+// unquoteJSONPathString unquotes the single quoted member names of JSONPaths,
+// such as 'it\'s', which hold the escape sequences of Go strings
+func unquoteJSONPathString(quoted string) (string, error) {
+	s := quoted[1 : len(quoted)-1]
+	var b strings.Builder
+	for len(s) > 0 {
+		r, multibyte, tail, err := strconv.UnquoteChar(s, '\'')
+		if err != nil {
+			return "", fmt.Errorf("Invalid JSONPath member name %s", quoted)
+		}
+		if r < 0x80 || !multibyte {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+		s = tail
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialect(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		dialect  SelectorDialect
+		expected Expression
+		err      string
+	}
+
+	equal := func(typ SelectorType, path ...string) Expression {
+		return &MatchExpression{
+			Left:     &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: typ, Path: path}, Type: ValueTypeReflect}},
+			Operator: MatchEqual,
+			Right:    &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeInt, Raw: "1"}},
+		}
+	}
+
+	tests := map[string]testCase{
+		"bexpr":                 {input: `Meta.env == 1`, expected: equal(SelectorTypeBexpr, "Meta", "env")},
+		"json pointer":          {input: `/Meta/env == 1`, dialect: SelectorDialectJSONPointer, expected: equal(SelectorTypeJsonPointer, "Meta", "env")},
+		"json pointer escapes":  {input: `/a~1b/c~0d/~01 == 1`, dialect: SelectorDialectJSONPointer, expected: equal(SelectorTypeJsonPointer, "a/b", "c~d", "~1")},
+		"json pointer chars":    {input: `/a b`, dialect: SelectorDialectJSONPointer, err: `1:4 (3): no match found, expected: "!=", "*", "+", "-", "/", "<", "<=", "==", ">", ">=", "and", "contains", "endswith", "in", "is", "like", "matches", "not", "or", "startswith", [ \t\r\n] or EOF`},
+		"json pointer unicode":  {input: `/métadonnées/clé.x|y==1`, dialect: SelectorDialectJSONPointer, expected: equal(SelectorTypeJsonPointer, "métadonnées", "clé.x|y")},
+		"json pointer empty":    {input: `/a// == 1`, dialect: SelectorDialectJSONPointer, expected: equal(SelectorTypeJsonPointer, "a", "", "")},
+		"json pointer escape":   {input: `/a~2 == 1`, dialect: SelectorDialectJSONPointer, err: `1:1 (0): rule "JSON Pointer": Invalid escape in JSON Pointer /a~2, ~ must be followed by 0 or 1`},
+		"json pointer string":   {input: `/a == "/b"`, dialect: SelectorDialectJSONPointer, expected: &MatchExpression{Left: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"a"}}, Type: ValueTypeReflect}}, Operator: MatchEqual, Right: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeString, Raw: "/b"}}}},
+		"json pointer division": {input: `/a / 2 == 1`, dialect: SelectorDialectJSONPointer, expected: &MatchExpression{Left: &ExpressionValue{Operator: MathOpDiv, Left: &MatchValue{Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"a"}}, Type: ValueTypeReflect}, Right: &MatchValue{Type: ValueTypeInt, Raw: "2"}}, Operator: MatchEqual, Right: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeInt, Raw: "1"}}}},
+		"json pointer bare":     {input: `a == 1`, dialect: SelectorDialectJSONPointer, err: `1:2 (1): rule "value": selectors are written as JSON Pointers, such as /a`},
+		"jsonpath":              {input: `$.Meta.env == 1`, dialect: SelectorDialectJSONPath, expected: equal(SelectorTypeJsonPath, "Meta", "env")},
+		"jsonpath brackets":     {input: `$['Meta'][ "a b" ]['it\'s'][0] == 1`, dialect: SelectorDialectJSONPath, expected: equal(SelectorTypeJsonPath, "Meta", "a b", "it's", "0")},
+		"jsonpath unicode":      {input: `$.métadonnées._x1 == 1`, dialect: SelectorDialectJSONPath, expected: equal(SelectorTypeJsonPath, "métadonnées", "_x1")},
+		"jsonpath wildcard":     {input: `$.Meta[*] == 1`, dialect: SelectorDialectJSONPath, err: `1:8 (7): rule "JSONPath segment": Invalid JSONPath segment`},
+		"jsonpath root":         {input: `$ == 1`, dialect: SelectorDialectJSONPath, err: `1:2 (1): no match found, expected: ".", "[" or [a-zA-Z]`},
+		"jsonpath bare":         {input: `a == 1`, dialect: SelectorDialectJSONPath, err: `1:2 (1): rule "value": selectors are written as JSONPaths, such as $.a`},
+		"jsonpath param":        {input: `$.a == $b`, dialect: SelectorDialectJSONPath, expected: &MatchExpression{Left: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: SelectorTypeJsonPath, Path: []string{"a"}}, Type: ValueTypeReflect}}, Operator: MatchEqual, Right: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeParam, Raw: "b"}}}},
+		"let": {
+			input:   `let t = $.Meta in $.t.env == 1`,
+			dialect: SelectorDialectJSONPath,
+			expected: &LetExpression{
+				Name:  "t",
+				Value: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: SelectorTypeJsonPath, Path: []string{"Meta"}}, Type: ValueTypeReflect}},
+				Body:  equal(SelectorTypeJsonPath, "t", "env"),
+			},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse("", []byte(tcase.input), Dialect(tcase.dialect))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, expr)
+		})
+	}
+}
+
+func TestSelector_String_JsonPath(t *testing.T) {
+	t.Parallel()
+
+	sel := Selector{Type: SelectorTypeJsonPath, Path: []string{"Meta", "a b"}}
+	require.Equal(t, `$["Meta"]["a b"]`, sel.String())
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// DumpConfig configures the layout of the ASTs written by Dump
+type DumpConfig struct {
+	// Indent is written once per level of nesting, three spaces when empty
+	Indent string
+	// Width is the maximum number of characters of the selectors and values
+	// written, longer ones being cut and suffixed with "...". 0 means no
+	// limit.
+	Width int
+}
+
+// Dump writes the AST of the expression to w, one node per line, streaming
+// it rather than buffering it so that very large ASTs can be written to
+// network connections or HTTP responses. It returns the first error writing
+// to w, the rest of the AST being skipped.
+func Dump(w io.Writer, expr Expression, cfg DumpConfig) error {
+	if cfg.Indent == "" {
+		cfg.Indent = "   "
+	}
+	d := &dumper{w: w, cfg: cfg}
+	d.expression(expr, 0)
+	return d.err
+}
+
+// dumper writes ASTs, keeping the first error
+type dumper struct {
+	w   io.Writer
+	cfg DumpConfig
+	err error
+}
+
+func (d *dumper) write(s string) {
+	if d.err == nil {
+		_, d.err = io.WriteString(d.w, s)
+	}
+}
+
+func (d *dumper) indent(level int) {
+	for i := 0; i < level; i++ {
+		d.write(d.cfg.Indent)
+	}
+}
+
+// value writes the value, cut to the configured width
+func (d *dumper) value(s string) {
+	if d.cfg.Width <= 0 || utf8.RuneCountInString(s) <= d.cfg.Width {
+		d.write(s)
+		return
+	}
+	n := 0
+	for i := range s {
+		if n == d.cfg.Width {
+			d.write(s[:i])
+			break
+		}
+		n++
+	}
+	d.write("...")
+}
+
+func (d *dumper) expression(expr Expression, level int) {
+	if d.err != nil {
+		return
+	}
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		d.indent(level)
+		d.write(node.Operator.String() + " {\n")
+		d.expression(node.Operand, level+1)
+		d.indent(level)
+		d.write("}\n")
+	case *BinaryExpression:
+		d.indent(level)
+		d.write(node.Operator.String() + " {\n")
+		d.expression(node.Left, level+1)
+		d.expression(node.Right, level+1)
+		d.indent(level)
+		d.write("}\n")
+	case *LetExpression:
+		d.indent(level)
+		d.write("let " + node.Name + " = ")
+		d.value(node.Value.String())
+		d.write(" {\n")
+		d.expression(node.Body, level+1)
+		d.indent(level)
+		d.write("}\n")
+	case *ExpressionValue:
+		d.indent(level)
+		d.value(fmt.Sprint(node.Left))
+		d.write(" " + node.Operator.String() + " ")
+		d.value(fmt.Sprint(node.Right))
+		d.write("\n")
+	case *MatchExpression:
+		d.indent(level)
+		d.write(node.Operator.String() + " {\n")
+		d.indent(level + 1)
+		d.write("Selector: ")
+		d.value(fmt.Sprint(node.Left))
+		d.write("\n")
+		switch node.Operator {
+		case MatchEqual, MatchNotEqual, MatchIn, MatchNotIn, MatchLower, MatchHigher, MatchLowerOrEqual, MatchHigherOrEqual,
+			MatchStartsWith, MatchNotStartsWith, MatchEndsWith, MatchNotEndsWith, MatchLike, MatchNotLike:
+			d.indent(level + 1)
+			d.write("Value: ")
+			d.value(fmt.Sprintf("%q", node.Right.String()))
+			d.write("\n")
+		}
+		d.indent(level)
+		d.write("}\n")
+	default:
+		expr.ExpressionDump(d.w, d.cfg.Indent, level)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails once n bytes were written
+type failingWriter struct {
+	n      int
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if len(p) > w.n {
+		return 0, errors.New("write failed")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := Parse("", []byte(`let t = Meta.tier in (Name == "a-very-long-name" or not Tags is empty) and t == "gold"`))
+	require.NoError(t, err)
+	expr := parsed.(Expression)
+
+	type testCase struct {
+		cfg      DumpConfig
+		expected string
+	}
+
+	tests := map[string]testCase{
+		"default": {
+			expected: "let t = Meta.tier {\n" +
+				"   And {\n" +
+				"      Or {\n" +
+				"         Equal {\n" +
+				"            Selector: Name\n" +
+				"            Value: \"a-very-long-name\"\n" +
+				"         }\n" +
+				"         Not {\n" +
+				"            Is Empty {\n" +
+				"               Selector: Tags\n" +
+				"            }\n" +
+				"         }\n" +
+				"      }\n" +
+				"      Equal {\n" +
+				"         Selector: t\n" +
+				"         Value: \"gold\"\n" +
+				"      }\n" +
+				"   }\n" +
+				"}\n",
+		},
+		"indent and width": {
+			cfg: DumpConfig{Indent: "\t", Width: 6},
+			expected: "let t = Meta.t... {\n" +
+				"\tAnd {\n" +
+				"\t\tOr {\n" +
+				"\t\t\tEqual {\n" +
+				"\t\t\t\tSelector: Name\n" +
+				"\t\t\t\tValue: \"a-ver...\n" +
+				"\t\t\t}\n" +
+				"\t\t\tNot {\n" +
+				"\t\t\t\tIs Empty {\n" +
+				"\t\t\t\t\tSelector: Tags\n" +
+				"\t\t\t\t}\n" +
+				"\t\t\t}\n" +
+				"\t\t}\n" +
+				"\t\tEqual {\n" +
+				"\t\t\tSelector: t\n" +
+				"\t\t\tValue: \"gold\"\n" +
+				"\t\t}\n" +
+				"\t}\n" +
+				"}\n",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := new(bytes.Buffer)
+			require.NoError(t, Dump(buf, expr, tcase.cfg))
+			require.Equal(t, tcase.expected, buf.String())
+		})
+	}
+
+	// ExpressionDump writes the same way
+	buf := new(bytes.Buffer)
+	expr.ExpressionDump(buf, "   ", 0)
+	require.Equal(t, tests["default"].expected, buf.String())
+
+	// the first error stops the dump
+	w := &failingWriter{n: 20}
+	require.EqualError(t, Dump(w, expr, DumpConfig{}), "write failed")
+	require.Less(t, w.writes, 10)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import "fmt"
+
+// Functions are the built-in functions which can be called in values, such
+// as keys(Meta), and the number of arguments they take. A function is called
+// by its name directly followed by the parenthesized arguments, separated by
+// commas: the fields named like functions are still selected by their name
+// alone.
+var Functions = map[string]int{
+	"keys":    1,
+	"values":  1,
+	"len":     1,
+	"count":   1,
+	"lower":   1,
+	"upper":   1,
+	"trim":    1,
+	"replace": 3,
+}
+
+// isFunction reports whether the word is the name of a built-in function
+func isFunction(word string) bool {
+	_, ok := Functions[word]
+	return ok
+}
+
+// checkFunctionCall reports the calls of unknown functions and the calls
+// with a wrong number of arguments
+func checkFunctionCall(name string, args interface{}) error {
+	n, ok := Functions[name]
+	if !ok {
+		return fmt.Errorf("unknown function %s", name)
+	}
+	got := 0
+	if args != nil {
+		got = len(args.([]*ExpressionValue))
+	}
+	switch {
+	case got != n && n == 1:
+		return fmt.Errorf("%s takes 1 argument, not %d", name, got)
+	case got != n:
+		return fmt.Errorf("%s takes %d arguments, not %d", name, n, got)
+	}
+	return nil
+}
+
+// newFunctionCall returns the value of the call of the function
+func newFunctionCall(name string, args interface{}) *MatchValue {
+	call := &MatchValue{Type: ValueTypeCall, Raw: name}
+	if args != nil {
+		call.Args = args.([]*ExpressionValue)
+	}
+	return call
+}
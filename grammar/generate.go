@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// generateIdentifierRe matches the parts of the selectors Generate writes
+var generateIdentifierRe = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// GenerateField is a field of the datums the expressions generated by Generate
+// are evaluated against
+type GenerateField struct {
+	// Path is the selector of the field, such as Meta.env
+	Path []string
+	// Kind is the kind of the values of the field, reflect.Invalid or
+	// reflect.Interface when not known
+	Kind reflect.Kind
+	// Elem is the kind of the elements of slices and arrays, and of the keys
+	// of maps
+	Elem reflect.Kind
+}
+
+// GenerateSchema constrains the expressions generated by Generate
+type GenerateSchema struct {
+	// Fields are the fields the expressions read, each one being compared
+	// with values of its kind with the operators supporting it. When empty,
+	// random selectors are compared with values of random kinds.
+	Fields []GenerateField
+	// MaxDepth is the maximum nesting of the logical operators, 3 when 0
+	MaxDepth int
+}
+
+// FieldsOf returns the fields of the struct, or pointer to struct, the way
+// selectors resolve them with the tag name, such as "bexpr": the fields of
+// nested structs are included, and the fields whose names are not written as
+// identifiers in the bexpr syntax, such as keywords, are left out.
+func FieldsOf(value interface{}, tagName string) []GenerateField {
+	var fields []GenerateField
+	var walk func(typ reflect.Type, path []string, depth int)
+	walk = func(typ reflect.Type, path []string, depth int) {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || depth > 3 {
+			return
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get(tagName); tag != "" {
+				name = strings.Split(tag, ",")[0]
+			}
+			if name == "-" || !isGenerateIdentifier(name) {
+				continue
+			}
+			fieldPath := append(path[:len(path):len(path)], name)
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			switch fieldType.Kind() {
+			case reflect.Struct:
+				walk(fieldType, fieldPath, depth+1)
+			case reflect.Slice, reflect.Array:
+				fields = append(fields, GenerateField{Path: fieldPath, Kind: fieldType.Kind(), Elem: fieldType.Elem().Kind()})
+			case reflect.Map:
+				fields = append(fields, GenerateField{Path: fieldPath, Kind: reflect.Map, Elem: fieldType.Key().Kind()})
+			default:
+				fields = append(fields, GenerateField{Path: fieldPath, Kind: fieldType.Kind()})
+			}
+		}
+	}
+	walk(reflect.TypeOf(value), nil, 0)
+	return fields
+}
+
+func isGenerateIdentifier(name string) bool {
+	if !generateIdentifierRe.MatchString(name) || literalKeywords[name] {
+		return false
+	}
+	for _, keyword := range Keywords {
+		if name == keyword {
+			return false
+		}
+	}
+	return true
+}
+
+var literalKeywords = map[string]bool{"true": true, "false": true, "null": true, "undefined": true}
+
+// Generate returns a random expression, for fuzzing the systems accepting
+// expressions and for differential testing, such as comparing the outcome of
+// an expression with the one of its translation. The expressions always parse.
+// Given fields, they only read these fields, with the operators supporting
+// their kind and values of their kind, so that they evaluate without errors
+// against the datums they were taken from, see FieldsOf. The same source of
+// randomness generates the same expressions.
+func Generate(r *rand.Rand, schema *GenerateSchema) string {
+	g := &generator{r: r}
+	if schema != nil {
+		g.fields = schema.Fields
+		g.maxDepth = schema.MaxDepth
+	}
+	if g.maxDepth == 0 {
+		g.maxDepth = 3
+	}
+	return g.expression(0)
+}
+
+type generator struct {
+	r        *rand.Rand
+	fields   []GenerateField
+	maxDepth int
+}
+
+// generateKinds are the kinds of the fields of random selectors
+var generateKinds = []reflect.Kind{reflect.Bool, reflect.Int, reflect.Float64, reflect.String, reflect.Slice, reflect.Interface}
+
+func (g *generator) expression(depth int) string {
+	if depth >= g.maxDepth {
+		return g.match()
+	}
+	switch g.r.Intn(5) {
+	case 0:
+		return "not " + g.operand(depth)
+	case 1:
+		return g.operand(depth) + " and " + g.operand(depth)
+	case 2:
+		return g.operand(depth) + " or " + g.operand(depth)
+	default:
+		return g.match()
+	}
+}
+
+// operand returns an operand of a logical operator, the logical expressions
+// being in parentheses
+func (g *generator) operand(depth int) string {
+	if g.r.Intn(2) == 0 || depth+1 >= g.maxDepth {
+		return g.match()
+	}
+	switch g.r.Intn(3) {
+	case 0:
+		return "not " + g.operand(depth+1)
+	case 1:
+		return "(" + g.operand(depth+1) + " and " + g.operand(depth+1) + ")"
+	default:
+		return "(" + g.operand(depth+1) + " or " + g.operand(depth+1) + ")"
+	}
+}
+
+// field returns the field of the schema, or a random field
+func (g *generator) field() GenerateField {
+	if len(g.fields) > 0 {
+		return g.fields[g.r.Intn(len(g.fields))]
+	}
+	names := []string{"a", "b", "c", "Name", "Meta", "Tags"}
+	path := []string{names[g.r.Intn(len(names))]}
+	for g.r.Intn(3) == 0 {
+		path = append(path, names[g.r.Intn(len(names))])
+	}
+	return GenerateField{
+		Path: path,
+		Kind: generateKinds[g.r.Intn(len(generateKinds))],
+		Elem: generateKinds[g.r.Intn(4)],
+	}
+}
+
+func (g *generator) match() string {
+	field := g.field()
+	sel := strings.Join(field.Path, ".")
+
+	switch field.Kind {
+	case reflect.Bool:
+		switch g.r.Intn(3) {
+		case 0:
+			return sel
+		case 1:
+			return sel + " == " + g.literal(reflect.Bool)
+		default:
+			return sel + " != " + g.literal(reflect.Bool)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		ops := []string{"==", "!=", "<", "<=", ">", ">="}
+		op := ops[g.r.Intn(len(ops))]
+		if g.r.Intn(4) == 0 {
+			// math on the field, with small values not to overflow
+			return sel + " + " + strconv.Itoa(g.r.Intn(10)) + " " + op + " " + g.literal(field.Kind)
+		}
+		return sel + " " + op + " " + g.literal(field.Kind)
+	case reflect.String:
+		switch g.r.Intn(8) {
+		case 0:
+			return sel + " == " + g.literal(reflect.String)
+		case 1:
+			return sel + " != " + g.literal(reflect.String)
+		case 2:
+			return sel + " " + g.negated("matches") + " " + strconv.Quote("^"+g.word())
+		case 3:
+			return sel + " " + g.negated("startswith") + " " + g.literal(reflect.String)
+		case 4:
+			return sel + " " + g.negated("endswith") + " " + g.literal(reflect.String)
+		case 5:
+			return sel + " " + g.negated("like") + " " + strconv.Quote(g.word()+"*")
+		case 6:
+			return g.literal(reflect.String) + " " + g.negated("in") + " " + sel
+		default:
+			return sel + " " + g.emptiness()
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if !isGenerateLiteralKind(field.Elem) {
+			return sel + " " + g.emptiness()
+		}
+		switch g.r.Intn(3) {
+		case 0:
+			return sel + " " + g.emptiness()
+		case 1:
+			return g.literal(field.Elem) + " " + g.negated("in") + " " + sel
+		default:
+			return sel + " " + g.negated("contains") + " " + g.literal(field.Elem)
+		}
+	default:
+		if g.r.Intn(2) == 0 {
+			return sel + " is " + g.negated("null")
+		}
+		return sel + " == " + g.literal(generateKinds[g.r.Intn(4)])
+	}
+}
+
+// isGenerateLiteralKind reports whether literals of the kind are written
+func isGenerateLiteralKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// negated returns the operator, or its negation, such as "not in"
+func (g *generator) negated(op string) string {
+	if g.r.Intn(2) == 0 {
+		return "not " + op
+	}
+	return op
+}
+
+func (g *generator) emptiness() string {
+	return "is " + g.negated("empty")
+}
+
+// literal returns a literal of the kind
+func (g *generator) literal(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return strconv.FormatBool(g.r.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.Itoa(g.r.Intn(200) - 100)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.Itoa(g.r.Intn(100))
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%d.%d", g.r.Intn(200)-100, g.r.Intn(10))
+	default:
+		return strconv.Quote(g.word())
+	}
+}
+
+// word returns a random word, which can be written in strings and in regular
+// expressions as is, and which does not read as a JSON Pointer
+func (g *generator) word() string {
+	const letters = "abcxyz019 -_"
+	n := 1 + g.r.Intn(6)
+	b := make([]byte, n)
+	b[0] = letters[g.r.Intn(6)]
+	for i := 1; i < n; i++ {
+		b[i] = letters[g.r.Intn(len(letters))]
+	}
+	return string(b)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"math/rand"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type generateTestStruct struct {
+	Name    string
+	Port    int
+	Weight  float64 `bexpr:"weight"`
+	Enabled bool
+	Tags    []string
+	Labels  map[string]string
+	Meta    *struct {
+		Env   string
+		Count uint8
+	}
+	Any        interface{}
+	Keyword    string `bexpr:"in"`
+	Hidden     string `bexpr:"-"`
+	Dashed     string `bexpr:"a-b"`
+	unexported string
+}
+
+func TestFieldsOf(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []GenerateField{
+		{Path: []string{"Name"}, Kind: reflect.String},
+		{Path: []string{"Port"}, Kind: reflect.Int},
+		{Path: []string{"weight"}, Kind: reflect.Float64},
+		{Path: []string{"Enabled"}, Kind: reflect.Bool},
+		{Path: []string{"Tags"}, Kind: reflect.Slice, Elem: reflect.String},
+		{Path: []string{"Labels"}, Kind: reflect.Map, Elem: reflect.String},
+		{Path: []string{"Meta", "Env"}, Kind: reflect.String},
+		{Path: []string{"Meta", "Count"}, Kind: reflect.Uint8},
+		{Path: []string{"Any"}, Kind: reflect.Interface},
+	}, FieldsOf(&generateTestStruct{}, "bexpr"))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	fields := FieldsOf(generateTestStruct{}, "bexpr")
+	for _, schema := range []*GenerateSchema{nil, {Fields: fields}, {Fields: fields, MaxDepth: 5}} {
+		for seed := int64(0); seed < 100; seed++ {
+			expression := Generate(rand.New(rand.NewSource(seed)), schema)
+			_, err := Parse("", []byte(expression))
+			require.NoError(t, err, expression)
+			// the same source generates the same expressions
+			require.Equal(t, expression, Generate(rand.New(rand.NewSource(seed)), schema))
+		}
+	}
+}
+
+func TestGenerate_Like(t *testing.T) {
+	t.Parallel()
+
+	likeRe := regexp.MustCompile(`like ("[^"]*")`)
+	schema := &GenerateSchema{Fields: []GenerateField{{Path: []string{"Name"}, Kind: reflect.String}}, MaxDepth: 1}
+	patterns := 0
+	for seed := int64(0); seed < 100; seed++ {
+		for _, match := range likeRe.FindAllStringSubmatch(Generate(rand.New(rand.NewSource(seed)), schema), -1) {
+			pattern, err := strconv.Unquote(match[1])
+			require.NoError(t, err)
+			// the patterns match the names starting with their word
+			matched, err := path.Match(pattern, strings.TrimSuffix(pattern, "*")+"-suffix")
+			require.NoError(t, err)
+			require.True(t, matched, pattern)
+			patterns++
+		}
+	}
+	require.NotZero(t, patterns)
+}
@@ -150,21 +150,19 @@ var g = &grammar{
 									pos:  position{line: 18, col: 36, offset: 268},
 									name: "_",
 								},
-								&litMatcher{
-									pos:        position{line: 18, col: 38, offset: 270},
-									val:        "or",
-									ignoreCase: false,
-									want:       "\"or\"",
+								&ruleRefExpr{
+									pos:  position{line: 18, col: 38, offset: 270},
+									name: "KeywordOr",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 18, col: 43, offset: 275},
+									pos:  position{line: 18, col: 48, offset: 280},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 18, col: 45, offset: 277},
+									pos:   position{line: 18, col: 50, offset: 282},
 									label: "right",
 									expr: &ruleRefExpr{
-										pos:  position{line: 18, col: 51, offset: 283},
+										pos:  position{line: 18, col: 56, offset: 288},
 										name: "OrExpression",
 									},
 								},
@@ -172,13 +170,13 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 24, col: 5, offset: 433},
+						pos: position{line: 24, col: 5, offset: 438},
 						run: (*parser).callonOrExpression11,
 						expr: &labeledExpr{
-							pos:   position{line: 24, col: 5, offset: 433},
+							pos:   position{line: 24, col: 5, offset: 438},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 24, col: 10, offset: 438},
+								pos:  position{line: 24, col: 10, offset: 443},
 								name: "AndExpression",
 							},
 						},
@@ -188,43 +186,41 @@ var g = &grammar{
 		},
 		{
 			name: "AndExpression",
-			pos:  position{line: 28, col: 1, offset: 477},
+			pos:  position{line: 28, col: 1, offset: 482},
 			expr: &choiceExpr{
-				pos: position{line: 28, col: 18, offset: 494},
+				pos: position{line: 28, col: 18, offset: 499},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 28, col: 18, offset: 494},
+						pos: position{line: 28, col: 18, offset: 499},
 						run: (*parser).callonAndExpression2,
 						expr: &seqExpr{
-							pos: position{line: 28, col: 18, offset: 494},
+							pos: position{line: 28, col: 18, offset: 499},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 28, col: 18, offset: 494},
+									pos:   position{line: 28, col: 18, offset: 499},
 									label: "left",
 									expr: &ruleRefExpr{
-										pos:  position{line: 28, col: 23, offset: 499},
+										pos:  position{line: 28, col: 23, offset: 504},
 										name: "NotExpression",
 									},
 								},
 								&ruleRefExpr{
-									pos:  position{line: 28, col: 37, offset: 513},
+									pos:  position{line: 28, col: 37, offset: 518},
 									name: "_",
 								},
-								&litMatcher{
-									pos:        position{line: 28, col: 39, offset: 515},
-									val:        "and",
-									ignoreCase: false,
-									want:       "\"and\"",
+								&ruleRefExpr{
+									pos:  position{line: 28, col: 39, offset: 520},
+									name: "KeywordAnd",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 28, col: 45, offset: 521},
+									pos:  position{line: 28, col: 50, offset: 531},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 28, col: 47, offset: 523},
+									pos:   position{line: 28, col: 52, offset: 533},
 									label: "right",
 									expr: &ruleRefExpr{
-										pos:  position{line: 28, col: 53, offset: 529},
+										pos:  position{line: 28, col: 58, offset: 539},
 										name: "AndExpression",
 									},
 								},
@@ -232,13 +228,13 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 34, col: 5, offset: 681},
+						pos: position{line: 34, col: 5, offset: 691},
 						run: (*parser).callonAndExpression11,
 						expr: &labeledExpr{
-							pos:   position{line: 34, col: 5, offset: 681},
+							pos:   position{line: 34, col: 5, offset: 691},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 34, col: 10, offset: 686},
+								pos:  position{line: 34, col: 10, offset: 696},
 								name: "NotExpression",
 							},
 						},
@@ -248,31 +244,29 @@ var g = &grammar{
 		},
 		{
 			name: "NotExpression",
-			pos:  position{line: 38, col: 1, offset: 725},
+			pos:  position{line: 38, col: 1, offset: 735},
 			expr: &choiceExpr{
-				pos: position{line: 38, col: 18, offset: 742},
+				pos: position{line: 38, col: 18, offset: 752},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 38, col: 18, offset: 742},
+						pos: position{line: 38, col: 18, offset: 752},
 						run: (*parser).callonNotExpression2,
 						expr: &seqExpr{
-							pos: position{line: 38, col: 18, offset: 742},
+							pos: position{line: 38, col: 18, offset: 752},
 							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 38, col: 18, offset: 742},
-									val:        "not",
-									ignoreCase: false,
-									want:       "\"not\"",
+								&ruleRefExpr{
+									pos:  position{line: 38, col: 18, offset: 752},
+									name: "KeywordNot",
 								},
 								&ruleRefExpr{
-									pos:  position{line: 38, col: 24, offset: 748},
+									pos:  position{line: 38, col: 29, offset: 763},
 									name: "_",
 								},
 								&labeledExpr{
-									pos:   position{line: 38, col: 26, offset: 750},
+									pos:   position{line: 38, col: 31, offset: 765},
 									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 38, col: 31, offset: 755},
+										pos:  position{line: 38, col: 36, offset: 770},
 										name: "NotExpression",
 									},
 								},
@@ -280,13 +274,25 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 49, col: 5, offset: 1142},
+						pos: position{line: 49, col: 5, offset: 1157},
 						run: (*parser).callonNotExpression8,
 						expr: &labeledExpr{
-							pos:   position{line: 49, col: 5, offset: 1142},
+							pos:   position{line: 49, col: 5, offset: 1157},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 49, col: 10, offset: 1162},
+								name: "LetExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 51, col: 5, offset: 1202},
+						run: (*parser).callonNotExpression11,
+						expr: &labeledExpr{
+							pos:   position{line: 51, col: 5, offset: 1202},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 49, col: 10, offset: 1147},
+								pos:  position{line: 51, col: 10, offset: 1207},
 								name: "ParenthesizedExpression",
 							},
 						},
@@ -294,49 +300,127 @@ var g = &grammar{
 				},
 			},
 		},
+		{
+			name:        "LetExpression",
+			displayName: "\"let\"",
+			pos:         position{line: 55, col: 1, offset: 1256},
+			expr: &actionExpr{
+				pos: position{line: 55, col: 24, offset: 1279},
+				run: (*parser).callonLetExpression1,
+				expr: &seqExpr{
+					pos: position{line: 55, col: 24, offset: 1279},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 55, col: 24, offset: 1279},
+							name: "KeywordLet",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 55, col: 35, offset: 1290},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 55, col: 37, offset: 1292},
+							label: "name",
+							expr: &ruleRefExpr{
+								pos:  position{line: 55, col: 42, offset: 1297},
+								name: "Identifier",
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 55, col: 53, offset: 1308},
+							expr: &ruleRefExpr{
+								pos:  position{line: 55, col: 53, offset: 1308},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 55, col: 56, offset: 1311},
+							val:        "=",
+							ignoreCase: false,
+							want:       "\"=\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 55, col: 60, offset: 1315},
+							expr: &ruleRefExpr{
+								pos:  position{line: 55, col: 60, offset: 1315},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 55, col: 63, offset: 1318},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 55, col: 69, offset: 1324},
+								name: "ExpressionValue",
+							},
+						},
+						&ruleRefExpr{
+							pos:  position{line: 55, col: 85, offset: 1340},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 55, col: 87, offset: 1342},
+							name: "KeywordIn",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 55, col: 97, offset: 1352},
+							name: "_",
+						},
+						&labeledExpr{
+							pos:   position{line: 55, col: 99, offset: 1354},
+							label: "body",
+							expr: &ruleRefExpr{
+								pos:  position{line: 55, col: 104, offset: 1359},
+								name: "OrExpression",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:        "ParenthesizedExpression",
 			displayName: "\"grouping\"",
-			pos:         position{line: 53, col: 1, offset: 1196},
+			pos:         position{line: 64, col: 1, offset: 1543},
 			expr: &choiceExpr{
-				pos: position{line: 53, col: 39, offset: 1234},
+				pos: position{line: 64, col: 39, offset: 1581},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 53, col: 39, offset: 1234},
+						pos: position{line: 64, col: 39, offset: 1581},
 						run: (*parser).callonParenthesizedExpression2,
 						expr: &seqExpr{
-							pos: position{line: 53, col: 39, offset: 1234},
+							pos: position{line: 64, col: 39, offset: 1581},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 53, col: 39, offset: 1234},
+									pos:        position{line: 64, col: 39, offset: 1581},
 									val:        "(",
 									ignoreCase: false,
 									want:       "\"(\"",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 53, col: 43, offset: 1238},
+									pos: position{line: 64, col: 43, offset: 1585},
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 43, offset: 1238},
+										pos:  position{line: 64, col: 43, offset: 1585},
 										name: "_",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 53, col: 46, offset: 1241},
+									pos:   position{line: 64, col: 46, offset: 1588},
 									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 51, offset: 1246},
+										pos:  position{line: 64, col: 51, offset: 1593},
 										name: "ExpressionValue",
 									},
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 53, col: 67, offset: 1262},
+									pos: position{line: 64, col: 67, offset: 1609},
 									expr: &ruleRefExpr{
-										pos:  position{line: 53, col: 67, offset: 1262},
+										pos:  position{line: 64, col: 67, offset: 1609},
 										name: "_",
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 53, col: 70, offset: 1265},
+									pos:        position{line: 64, col: 70, offset: 1612},
 									val:        ")",
 									ignoreCase: false,
 									want:       "\")\"",
@@ -345,41 +429,41 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 55, col: 5, offset: 1295},
+						pos: position{line: 66, col: 5, offset: 1642},
 						run: (*parser).callonParenthesizedExpression12,
 						expr: &seqExpr{
-							pos: position{line: 55, col: 5, offset: 1295},
+							pos: position{line: 66, col: 5, offset: 1642},
 							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 55, col: 5, offset: 1295},
+									pos:        position{line: 66, col: 5, offset: 1642},
 									val:        "(",
 									ignoreCase: false,
 									want:       "\"(\"",
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 55, col: 9, offset: 1299},
+									pos: position{line: 66, col: 9, offset: 1646},
 									expr: &ruleRefExpr{
-										pos:  position{line: 55, col: 9, offset: 1299},
+										pos:  position{line: 66, col: 9, offset: 1646},
 										name: "_",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 55, col: 12, offset: 1302},
+									pos:   position{line: 66, col: 12, offset: 1649},
 									label: "expr",
 									expr: &ruleRefExpr{
-										pos:  position{line: 55, col: 17, offset: 1307},
+										pos:  position{line: 66, col: 17, offset: 1654},
 										name: "OrExpression",
 									},
 								},
 								&zeroOrOneExpr{
-									pos: position{line: 55, col: 30, offset: 1320},
+									pos: position{line: 66, col: 30, offset: 1667},
 									expr: &ruleRefExpr{
-										pos:  position{line: 55, col: 30, offset: 1320},
+										pos:  position{line: 66, col: 30, offset: 1667},
 										name: "_",
 									},
 								},
 								&litMatcher{
-									pos:        position{line: 55, col: 33, offset: 1323},
+									pos:        position{line: 66, col: 33, offset: 1670},
 									val:        ")",
 									ignoreCase: false,
 									want:       "\")\"",
@@ -388,67 +472,67 @@ var g = &grammar{
 						},
 					},
 					&actionExpr{
-						pos: position{line: 57, col: 5, offset: 1353},
+						pos: position{line: 68, col: 5, offset: 1700},
 						run: (*parser).callonParenthesizedExpression22,
 						expr: &labeledExpr{
-							pos:   position{line: 57, col: 5, offset: 1353},
+							pos:   position{line: 68, col: 5, offset: 1700},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 57, col: 10, offset: 1358},
+								pos:  position{line: 68, col: 10, offset: 1705},
 								name: "MatchExpression",
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 59, col: 5, offset: 1400},
+						pos: position{line: 70, col: 5, offset: 1747},
 						run: (*parser).callonParenthesizedExpression25,
 						expr: &labeledExpr{
-							pos:   position{line: 59, col: 5, offset: 1400},
+							pos:   position{line: 70, col: 5, offset: 1747},
 							label: "expr",
 							expr: &ruleRefExpr{
-								pos:  position{line: 59, col: 10, offset: 1405},
+								pos:  position{line: 70, col: 10, offset: 1752},
 								name: "ExpressionValue",
 							},
 						},
 					},
 					&seqExpr{
-						pos: position{line: 61, col: 5, offset: 1447},
+						pos: position{line: 72, col: 5, offset: 1794},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 61, col: 5, offset: 1447},
+								pos:        position{line: 72, col: 5, offset: 1794},
 								val:        "(",
 								ignoreCase: false,
 								want:       "\"(\"",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 61, col: 9, offset: 1451},
+								pos: position{line: 72, col: 9, offset: 1798},
 								expr: &ruleRefExpr{
-									pos:  position{line: 61, col: 9, offset: 1451},
+									pos:  position{line: 72, col: 9, offset: 1798},
 									name: "_",
 								},
 							},
 							&ruleRefExpr{
-								pos:  position{line: 61, col: 12, offset: 1454},
+								pos:  position{line: 72, col: 12, offset: 1801},
 								name: "OrExpression",
 							},
 							&zeroOrOneExpr{
-								pos: position{line: 61, col: 25, offset: 1467},
+								pos: position{line: 72, col: 25, offset: 1814},
 								expr: &ruleRefExpr{
-									pos:  position{line: 61, col: 25, offset: 1467},
+									pos:  position{line: 72, col: 25, offset: 1814},
 									name: "_",
 								},
 							},
 							&notExpr{
-								pos: position{line: 61, col: 28, offset: 1470},
+								pos: position{line: 72, col: 28, offset: 1817},
 								expr: &litMatcher{
-									pos:        position{line: 61, col: 29, offset: 1471},
+									pos:        position{line: 72, col: 29, offset: 1818},
 									val:        ")",
 									ignoreCase: false,
 									want:       "\")\"",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 61, col: 33, offset: 1475},
+								pos: position{line: 72, col: 33, offset: 1822},
 								run: (*parser).callonParenthesizedExpression37,
 							},
 						},
@@ -459,20 +543,20 @@ var g = &grammar{
 		{
 			name:        "MatchExpression",
 			displayName: "\"match\"",
-			pos:         position{line: 65, col: 1, offset: 1534},
+			pos:         position{line: 76, col: 1, offset: 1881},
 			expr: &choiceExpr{
-				pos: position{line: 65, col: 28, offset: 1561},
+				pos: position{line: 76, col: 28, offset: 1908},
 				alternatives: []interface{}{
 					&ruleRefExpr{
-						pos:  position{line: 65, col: 28, offset: 1561},
+						pos:  position{line: 76, col: 28, offset: 1908},
 						name: "MatchSelectorOpValue",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 65, col: 51, offset: 1584},
+						pos:  position{line: 76, col: 51, offset: 1931},
 						name: "MatchSelectorOp",
 					},
 					&ruleRefExpr{
-						pos:  position{line: 65, col: 69, offset: 1602},
+						pos:  position{line: 76, col: 69, offset: 1949},
 						name: "MatchValueOpSelector",
 					},
 				},
@@ -481,75 +565,99 @@ var g = &grammar{
 		{
 			name:        "MatchSelectorOpValue",
 			displayName: "\"match\"",
-			pos:         position{line: 67, col: 1, offset: 1624},
+			pos:         position{line: 78, col: 1, offset: 1971},
 			expr: &actionExpr{
-				pos: position{line: 67, col: 33, offset: 1656},
+				pos: position{line: 78, col: 33, offset: 2003},
 				run: (*parser).callonMatchSelectorOpValue1,
 				expr: &seqExpr{
-					pos: position{line: 67, col: 33, offset: 1656},
+					pos: position{line: 78, col: 33, offset: 2003},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 67, col: 33, offset: 1656},
+							pos:   position{line: 78, col: 33, offset: 2003},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 67, col: 38, offset: 1661},
+								pos:  position{line: 78, col: 38, offset: 2008},
 								name: "ExpressionValue",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 67, col: 54, offset: 1677},
+							pos:   position{line: 78, col: 54, offset: 2024},
 							label: "operator",
 							expr: &choiceExpr{
-								pos: position{line: 67, col: 64, offset: 1687},
+								pos: position{line: 78, col: 64, offset: 2034},
 								alternatives: []interface{}{
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 64, offset: 1687},
+										pos:  position{line: 78, col: 64, offset: 2034},
 										name: "MatchLowerOrEqual",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 84, offset: 1707},
+										pos:  position{line: 78, col: 84, offset: 2054},
 										name: "MatchHigherOrEqual",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 105, offset: 1728},
+										pos:  position{line: 78, col: 105, offset: 2075},
 										name: "MatchLower",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 118, offset: 1741},
+										pos:  position{line: 78, col: 118, offset: 2088},
 										name: "MatchHigher",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 132, offset: 1755},
+										pos:  position{line: 78, col: 132, offset: 2102},
 										name: "MatchEqual",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 145, offset: 1768},
+										pos:  position{line: 78, col: 145, offset: 2115},
 										name: "MatchNotEqual",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 161, offset: 1784},
+										pos:  position{line: 78, col: 161, offset: 2131},
 										name: "MatchContains",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 177, offset: 1800},
+										pos:  position{line: 78, col: 177, offset: 2147},
 										name: "MatchNotContains",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 196, offset: 1819},
+										pos:  position{line: 78, col: 196, offset: 2166},
 										name: "MatchMatches",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 67, col: 211, offset: 1834},
+										pos:  position{line: 78, col: 211, offset: 2181},
 										name: "MatchNotMatches",
 									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 229, offset: 2199},
+										name: "MatchStartsWith",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 247, offset: 2217},
+										name: "MatchNotStartsWith",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 268, offset: 2238},
+										name: "MatchEndsWith",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 284, offset: 2254},
+										name: "MatchNotEndsWith",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 303, offset: 2273},
+										name: "MatchLike",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 78, col: 315, offset: 2285},
+										name: "MatchNotLike",
+									},
 								},
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 67, col: 228, offset: 1851},
+							pos:   position{line: 78, col: 329, offset: 2299},
 							label: "right",
 							expr: &ruleRefExpr{
-								pos:  position{line: 67, col: 234, offset: 1857},
+								pos:  position{line: 78, col: 335, offset: 2305},
 								name: "ExpressionValue",
 							},
 						},
@@ -560,35 +668,43 @@ var g = &grammar{
 		{
 			name:        "MatchSelectorOp",
 			displayName: "\"match\"",
-			pos:         position{line: 71, col: 1, offset: 2010},
+			pos:         position{line: 82, col: 1, offset: 2485},
 			expr: &actionExpr{
-				pos: position{line: 71, col: 28, offset: 2037},
+				pos: position{line: 82, col: 28, offset: 2512},
 				run: (*parser).callonMatchSelectorOp1,
 				expr: &seqExpr{
-					pos: position{line: 71, col: 28, offset: 2037},
+					pos: position{line: 82, col: 28, offset: 2512},
 					exprs: []interface{}{
 						&labeledExpr{
-							pos:   position{line: 71, col: 28, offset: 2037},
+							pos:   position{line: 82, col: 28, offset: 2512},
 							label: "left",
 							expr: &ruleRefExpr{
-								pos:  position{line: 71, col: 33, offset: 2042},
+								pos:  position{line: 82, col: 33, offset: 2517},
 								name: "Value",
 							},
 						},
 						&labeledExpr{
-							pos:   position{line: 71, col: 39, offset: 2048},
+							pos:   position{line: 82, col: 39, offset: 2523},
 							label: "operator",
 							expr: &choiceExpr{
-								pos: position{line: 71, col: 49, offset: 2058},
+								pos: position{line: 82, col: 49, offset: 2533},
 								alternatives: []interface{}{
 									&ruleRefExpr{
-										pos:  position{line: 71, col: 49, offset: 2058},
+										pos:  position{line: 82, col: 49, offset: 2533},
 										name: "MatchIsEmpty",
 									},
 									&ruleRefExpr{
-										pos:  position{line: 71, col: 64, offset: 2073},
+										pos:  position{line: 82, col: 64, offset: 2548},
 										name: "MatchIsNotEmpty",
 									},
+									&ruleRefExpr{
+										pos:  position{line: 82, col: 82, offset: 2566},
+										name: "MatchIsNull",
+									},
+									&ruleRefExpr{
+										pos:  position{line: 82, col: 96, offset: 2580},
+										name: "MatchIsNotNull",
+									},
 								},
 							},
 						},
@@ -599,46 +715,46 @@ var g = &grammar{
 		{
 			name:        "MatchValueOpSelector",
 			displayName: "\"match\"",
-			pos:         position{line: 83, col: 1, offset: 2322},
+			pos:         position{line: 95, col: 1, offset: 2861},
 			expr: &choiceExpr{
-				pos: position{line: 83, col: 33, offset: 2354},
+				pos: position{line: 95, col: 33, offset: 2893},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 83, col: 33, offset: 2354},
+						pos: position{line: 95, col: 33, offset: 2893},
 						run: (*parser).callonMatchValueOpSelector2,
 						expr: &seqExpr{
-							pos: position{line: 83, col: 33, offset: 2354},
+							pos: position{line: 95, col: 33, offset: 2893},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 83, col: 33, offset: 2354},
+									pos:   position{line: 95, col: 33, offset: 2893},
 									label: "value",
 									expr: &ruleRefExpr{
-										pos:  position{line: 83, col: 39, offset: 2360},
+										pos:  position{line: 95, col: 39, offset: 2899},
 										name: "Value",
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 83, col: 45, offset: 2366},
+									pos:   position{line: 95, col: 45, offset: 2905},
 									label: "operator",
 									expr: &choiceExpr{
-										pos: position{line: 83, col: 55, offset: 2376},
+										pos: position{line: 95, col: 55, offset: 2915},
 										alternatives: []interface{}{
 											&ruleRefExpr{
-												pos:  position{line: 83, col: 55, offset: 2376},
+												pos:  position{line: 95, col: 55, offset: 2915},
 												name: "MatchIn",
 											},
 											&ruleRefExpr{
-												pos:  position{line: 83, col: 65, offset: 2386},
+												pos:  position{line: 95, col: 65, offset: 2925},
 												name: "MatchNotIn",
 											},
 										},
 									},
 								},
 								&labeledExpr{
-									pos:   position{line: 83, col: 77, offset: 2398},
+									pos:   position{line: 95, col: 77, offset: 2937},
 									label: "selector",
 									expr: &ruleRefExpr{
-										pos:  position{line: 83, col: 86, offset: 2407},
+										pos:  position{line: 95, col: 86, offset: 2946},
 										name: "Value",
 									},
 								},
@@ -646,38 +762,38 @@ var g = &grammar{
 						},
 					},
 					&seqExpr{
-						pos: position{line: 97, col: 5, offset: 2762},
+						pos: position{line: 110, col: 5, offset: 3334},
 						exprs: []interface{}{
 							&ruleRefExpr{
-								pos:  position{line: 97, col: 5, offset: 2762},
+								pos:  position{line: 110, col: 5, offset: 3334},
 								name: "Value",
 							},
 							&labeledExpr{
-								pos:   position{line: 97, col: 11, offset: 2768},
+								pos:   position{line: 110, col: 11, offset: 3340},
 								label: "operator",
 								expr: &choiceExpr{
-									pos: position{line: 97, col: 21, offset: 2778},
+									pos: position{line: 110, col: 21, offset: 3350},
 									alternatives: []interface{}{
 										&ruleRefExpr{
-											pos:  position{line: 97, col: 21, offset: 2778},
+											pos:  position{line: 110, col: 21, offset: 3350},
 											name: "MatchIn",
 										},
 										&ruleRefExpr{
-											pos:  position{line: 97, col: 31, offset: 2788},
+											pos:  position{line: 110, col: 31, offset: 3360},
 											name: "MatchNotIn",
 										},
 									},
 								},
 							},
 							&notExpr{
-								pos: position{line: 97, col: 43, offset: 2800},
+								pos: position{line: 110, col: 43, offset: 3372},
 								expr: &ruleRefExpr{
-									pos:  position{line: 97, col: 44, offset: 2801},
+									pos:  position{line: 110, col: 44, offset: 3373},
 									name: "Selector",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 97, col: 53, offset: 2810},
+								pos: position{line: 110, col: 53, offset: 3382},
 								run: (*parser).callonMatchValueOpSelector20,
 							},
 						},
@@ -687,30 +803,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchLowerOrEqual",
-			pos:  position{line: 101, col: 1, offset: 2864},
+			pos:  position{line: 114, col: 1, offset: 3436},
 			expr: &actionExpr{
-				pos: position{line: 101, col: 22, offset: 2885},
+				pos: position{line: 114, col: 22, offset: 3457},
 				run: (*parser).callonMatchLowerOrEqual1,
 				expr: &seqExpr{
-					pos: position{line: 101, col: 22, offset: 2885},
+					pos: position{line: 114, col: 22, offset: 3457},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 101, col: 22, offset: 2885},
+							pos: position{line: 114, col: 22, offset: 3457},
 							expr: &ruleRefExpr{
-								pos:  position{line: 101, col: 22, offset: 2885},
+								pos:  position{line: 114, col: 22, offset: 3457},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 101, col: 25, offset: 2888},
+							pos:        position{line: 114, col: 25, offset: 3460},
 							val:        "<=",
 							ignoreCase: false,
 							want:       "\"<=\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 101, col: 30, offset: 2893},
+							pos: position{line: 114, col: 30, offset: 3465},
 							expr: &ruleRefExpr{
-								pos:  position{line: 101, col: 30, offset: 2893},
+								pos:  position{line: 114, col: 30, offset: 3465},
 								name: "_",
 							},
 						},
@@ -720,30 +836,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchLower",
-			pos:  position{line: 105, col: 1, offset: 2934},
+			pos:  position{line: 118, col: 1, offset: 3506},
 			expr: &actionExpr{
-				pos: position{line: 105, col: 15, offset: 2948},
+				pos: position{line: 118, col: 15, offset: 3520},
 				run: (*parser).callonMatchLower1,
 				expr: &seqExpr{
-					pos: position{line: 105, col: 15, offset: 2948},
+					pos: position{line: 118, col: 15, offset: 3520},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 105, col: 15, offset: 2948},
+							pos: position{line: 118, col: 15, offset: 3520},
 							expr: &ruleRefExpr{
-								pos:  position{line: 105, col: 15, offset: 2948},
+								pos:  position{line: 118, col: 15, offset: 3520},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 105, col: 18, offset: 2951},
+							pos:        position{line: 118, col: 18, offset: 3523},
 							val:        "<",
 							ignoreCase: false,
 							want:       "\"<\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 105, col: 22, offset: 2955},
+							pos: position{line: 118, col: 22, offset: 3527},
 							expr: &ruleRefExpr{
-								pos:  position{line: 105, col: 22, offset: 2955},
+								pos:  position{line: 118, col: 22, offset: 3527},
 								name: "_",
 							},
 						},
@@ -753,30 +869,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchHigherOrEqual",
-			pos:  position{line: 109, col: 1, offset: 2989},
+			pos:  position{line: 122, col: 1, offset: 3561},
 			expr: &actionExpr{
-				pos: position{line: 109, col: 23, offset: 3011},
+				pos: position{line: 122, col: 23, offset: 3583},
 				run: (*parser).callonMatchHigherOrEqual1,
 				expr: &seqExpr{
-					pos: position{line: 109, col: 23, offset: 3011},
+					pos: position{line: 122, col: 23, offset: 3583},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 109, col: 23, offset: 3011},
+							pos: position{line: 122, col: 23, offset: 3583},
 							expr: &ruleRefExpr{
-								pos:  position{line: 109, col: 23, offset: 3011},
+								pos:  position{line: 122, col: 23, offset: 3583},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 109, col: 26, offset: 3014},
+							pos:        position{line: 122, col: 26, offset: 3586},
 							val:        ">=",
 							ignoreCase: false,
 							want:       "\">=\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 109, col: 31, offset: 3019},
+							pos: position{line: 122, col: 31, offset: 3591},
 							expr: &ruleRefExpr{
-								pos:  position{line: 109, col: 31, offset: 3019},
+								pos:  position{line: 122, col: 31, offset: 3591},
 								name: "_",
 							},
 						},
@@ -786,30 +902,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchHigher",
-			pos:  position{line: 113, col: 1, offset: 3061},
+			pos:  position{line: 126, col: 1, offset: 3633},
 			expr: &actionExpr{
-				pos: position{line: 113, col: 16, offset: 3076},
+				pos: position{line: 126, col: 16, offset: 3648},
 				run: (*parser).callonMatchHigher1,
 				expr: &seqExpr{
-					pos: position{line: 113, col: 16, offset: 3076},
+					pos: position{line: 126, col: 16, offset: 3648},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 113, col: 16, offset: 3076},
+							pos: position{line: 126, col: 16, offset: 3648},
 							expr: &ruleRefExpr{
-								pos:  position{line: 113, col: 16, offset: 3076},
+								pos:  position{line: 126, col: 16, offset: 3648},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 113, col: 19, offset: 3079},
+							pos:        position{line: 126, col: 19, offset: 3651},
 							val:        ">",
 							ignoreCase: false,
 							want:       "\">\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 113, col: 23, offset: 3083},
+							pos: position{line: 126, col: 23, offset: 3655},
 							expr: &ruleRefExpr{
-								pos:  position{line: 113, col: 23, offset: 3083},
+								pos:  position{line: 126, col: 23, offset: 3655},
 								name: "_",
 							},
 						},
@@ -819,30 +935,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchEqual",
-			pos:  position{line: 117, col: 1, offset: 3118},
+			pos:  position{line: 130, col: 1, offset: 3690},
 			expr: &actionExpr{
-				pos: position{line: 117, col: 15, offset: 3132},
+				pos: position{line: 130, col: 15, offset: 3704},
 				run: (*parser).callonMatchEqual1,
 				expr: &seqExpr{
-					pos: position{line: 117, col: 15, offset: 3132},
+					pos: position{line: 130, col: 15, offset: 3704},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 117, col: 15, offset: 3132},
+							pos: position{line: 130, col: 15, offset: 3704},
 							expr: &ruleRefExpr{
-								pos:  position{line: 117, col: 15, offset: 3132},
+								pos:  position{line: 130, col: 15, offset: 3704},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 117, col: 18, offset: 3135},
+							pos:        position{line: 130, col: 18, offset: 3707},
 							val:        "==",
 							ignoreCase: false,
 							want:       "\"==\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 117, col: 23, offset: 3140},
+							pos: position{line: 130, col: 23, offset: 3712},
 							expr: &ruleRefExpr{
-								pos:  position{line: 117, col: 23, offset: 3140},
+								pos:  position{line: 130, col: 23, offset: 3712},
 								name: "_",
 							},
 						},
@@ -852,30 +968,30 @@ var g = &grammar{
 		},
 		{
 			name: "MatchNotEqual",
-			pos:  position{line: 120, col: 1, offset: 3173},
+			pos:  position{line: 133, col: 1, offset: 3745},
 			expr: &actionExpr{
-				pos: position{line: 120, col: 18, offset: 3190},
+				pos: position{line: 133, col: 18, offset: 3762},
 				run: (*parser).callonMatchNotEqual1,
 				expr: &seqExpr{
-					pos: position{line: 120, col: 18, offset: 3190},
+					pos: position{line: 133, col: 18, offset: 3762},
 					exprs: []interface{}{
 						&zeroOrOneExpr{
-							pos: position{line: 120, col: 18, offset: 3190},
+							pos: position{line: 133, col: 18, offset: 3762},
 							expr: &ruleRefExpr{
-								pos:  position{line: 120, col: 18, offset: 3190},
+								pos:  position{line: 133, col: 18, offset: 3762},
 								name: "_",
 							},
 						},
 						&litMatcher{
-							pos:        position{line: 120, col: 21, offset: 3193},
+							pos:        position{line: 133, col: 21, offset: 3765},
 							val:        "!=",
 							ignoreCase: false,
 							want:       "\"!=\"",
 						},
 						&zeroOrOneExpr{
-							pos: position{line: 120, col: 26, offset: 3198},
+							pos: position{line: 133, col: 26, offset: 3770},
 							expr: &ruleRefExpr{
-								pos:  position{line: 120, col: 26, offset: 3198},
+								pos:  position{line: 133, col: 26, offset: 3770},
 								name: "_",
 							},
 						},
@@ -885,32 +1001,28 @@ var g = &grammar{
 		},
 		{
 			name: "MatchIsEmpty",
-			pos:  position{line: 123, col: 1, offset: 3234},
+			pos:  position{line: 136, col: 1, offset: 3806},
 			expr: &actionExpr{
-				pos: position{line: 123, col: 17, offset: 3250},
+				pos: position{line: 136, col: 17, offset: 3822},
 				run: (*parser).callonMatchIsEmpty1,
 				expr: &seqExpr{
-					pos: position{line: 123, col: 17, offset: 3250},
+					pos: position{line: 136, col: 17, offset: 3822},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 123, col: 17, offset: 3250},
+							pos:  position{line: 136, col: 17, offset: 3822},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 123, col: 19, offset: 3252},
-							val:        "is",
-							ignoreCase: false,
-							want:       "\"is\"",
+						&ruleRefExpr{
+							pos:  position{line: 136, col: 19, offset: 3824},
+							name: "KeywordIs",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 123, col: 24, offset: 3257},
+							pos:  position{line: 136, col: 29, offset: 3834},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 123, col: 26, offset: 3259},
-							val:        "empty",
-							ignoreCase: false,
-							want:       "\"empty\"",
+						&ruleRefExpr{
+							pos:  position{line: 136, col: 31, offset: 3836},
+							name: "KeywordEmpty",
 						},
 					},
 				},
@@ -918,42 +1030,102 @@ var g = &grammar{
 		},
 		{
 			name: "MatchIsNotEmpty",
-			pos:  position{line: 126, col: 1, offset: 3299},
+			pos:  position{line: 139, col: 1, offset: 3881},
 			expr: &actionExpr{
-				pos: position{line: 126, col: 20, offset: 3318},
+				pos: position{line: 139, col: 20, offset: 3900},
 				run: (*parser).callonMatchIsNotEmpty1,
 				expr: &seqExpr{
-					pos: position{line: 126, col: 20, offset: 3318},
+					pos: position{line: 139, col: 20, offset: 3900},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 126, col: 20, offset: 3318},
+							pos:  position{line: 139, col: 20, offset: 3900},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 126, col: 21, offset: 3319},
-							val:        "is",
-							ignoreCase: false,
-							want:       "\"is\"",
+						&ruleRefExpr{
+							pos:  position{line: 139, col: 22, offset: 3902},
+							name: "KeywordIs",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 126, col: 26, offset: 3324},
+							pos:  position{line: 139, col: 32, offset: 3912},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 126, col: 28, offset: 3326},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
+						&ruleRefExpr{
+							pos:  position{line: 139, col: 34, offset: 3914},
+							name: "KeywordNot",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 126, col: 34, offset: 3332},
+							pos:  position{line: 139, col: 45, offset: 3925},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 126, col: 36, offset: 3334},
-							val:        "empty",
-							ignoreCase: false,
-							want:       "\"empty\"",
+						&ruleRefExpr{
+							pos:  position{line: 139, col: 47, offset: 3927},
+							name: "KeywordEmpty",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsNull",
+			pos:  position{line: 142, col: 1, offset: 3975},
+			expr: &actionExpr{
+				pos: position{line: 142, col: 16, offset: 3990},
+				run: (*parser).callonMatchIsNull1,
+				expr: &seqExpr{
+					pos: position{line: 142, col: 16, offset: 3990},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 142, col: 16, offset: 3990},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 142, col: 18, offset: 3992},
+							name: "KeywordIs",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 142, col: 28, offset: 4002},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 142, col: 30, offset: 4004},
+							name: "KeywordNull",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchIsNotNull",
+			pos:  position{line: 145, col: 1, offset: 4047},
+			expr: &actionExpr{
+				pos: position{line: 145, col: 19, offset: 4065},
+				run: (*parser).callonMatchIsNotNull1,
+				expr: &seqExpr{
+					pos: position{line: 145, col: 19, offset: 4065},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 19, offset: 4065},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 21, offset: 4067},
+							name: "KeywordIs",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 31, offset: 4077},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 33, offset: 4079},
+							name: "KeywordNot",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 44, offset: 4090},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 145, col: 46, offset: 4092},
+							name: "KeywordNull",
 						},
 					},
 				},
@@ -961,25 +1133,23 @@ var g = &grammar{
 		},
 		{
 			name: "MatchIn",
-			pos:  position{line: 129, col: 1, offset: 3377},
+			pos:  position{line: 148, col: 1, offset: 4138},
 			expr: &actionExpr{
-				pos: position{line: 129, col: 12, offset: 3388},
+				pos: position{line: 148, col: 12, offset: 4149},
 				run: (*parser).callonMatchIn1,
 				expr: &seqExpr{
-					pos: position{line: 129, col: 12, offset: 3388},
+					pos: position{line: 148, col: 12, offset: 4149},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 129, col: 12, offset: 3388},
+							pos:  position{line: 148, col: 12, offset: 4149},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 129, col: 14, offset: 3390},
-							val:        "in",
-							ignoreCase: false,
-							want:       "\"in\"",
+						&ruleRefExpr{
+							pos:  position{line: 148, col: 14, offset: 4151},
+							name: "KeywordIn",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 129, col: 19, offset: 3395},
+							pos:  position{line: 148, col: 24, offset: 4161},
 							name: "_",
 						},
 					},
@@ -988,35 +1158,31 @@ var g = &grammar{
 		},
 		{
 			name: "MatchNotIn",
-			pos:  position{line: 132, col: 1, offset: 3424},
+			pos:  position{line: 151, col: 1, offset: 4190},
 			expr: &actionExpr{
-				pos: position{line: 132, col: 15, offset: 3438},
+				pos: position{line: 151, col: 15, offset: 4204},
 				run: (*parser).callonMatchNotIn1,
 				expr: &seqExpr{
-					pos: position{line: 132, col: 15, offset: 3438},
+					pos: position{line: 151, col: 15, offset: 4204},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 132, col: 15, offset: 3438},
+							pos:  position{line: 151, col: 15, offset: 4204},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 132, col: 17, offset: 3440},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
+						&ruleRefExpr{
+							pos:  position{line: 151, col: 17, offset: 4206},
+							name: "KeywordNot",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 132, col: 23, offset: 3446},
+							pos:  position{line: 151, col: 28, offset: 4217},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 132, col: 25, offset: 3448},
-							val:        "in",
-							ignoreCase: false,
-							want:       "\"in\"",
+						&ruleRefExpr{
+							pos:  position{line: 151, col: 30, offset: 4219},
+							name: "KeywordIn",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 132, col: 30, offset: 3453},
+							pos:  position{line: 151, col: 40, offset: 4229},
 							name: "_",
 						},
 					},
@@ -1025,25 +1191,23 @@ var g = &grammar{
 		},
 		{
 			name: "MatchContains",
-			pos:  position{line: 135, col: 1, offset: 3485},
+			pos:  position{line: 154, col: 1, offset: 4261},
 			expr: &actionExpr{
-				pos: position{line: 135, col: 18, offset: 3502},
+				pos: position{line: 154, col: 18, offset: 4278},
 				run: (*parser).callonMatchContains1,
 				expr: &seqExpr{
-					pos: position{line: 135, col: 18, offset: 3502},
+					pos: position{line: 154, col: 18, offset: 4278},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 135, col: 18, offset: 3502},
+							pos:  position{line: 154, col: 18, offset: 4278},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 135, col: 20, offset: 3504},
-							val:        "contains",
-							ignoreCase: false,
-							want:       "\"contains\"",
+						&ruleRefExpr{
+							pos:  position{line: 154, col: 20, offset: 4280},
+							name: "KeywordContains",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 135, col: 31, offset: 3515},
+							pos:  position{line: 154, col: 36, offset: 4296},
 							name: "_",
 						},
 					},
@@ -1052,35 +1216,31 @@ var g = &grammar{
 		},
 		{
 			name: "MatchNotContains",
-			pos:  position{line: 138, col: 1, offset: 3544},
+			pos:  position{line: 157, col: 1, offset: 4325},
 			expr: &actionExpr{
-				pos: position{line: 138, col: 21, offset: 3564},
+				pos: position{line: 157, col: 21, offset: 4345},
 				run: (*parser).callonMatchNotContains1,
 				expr: &seqExpr{
-					pos: position{line: 138, col: 21, offset: 3564},
+					pos: position{line: 157, col: 21, offset: 4345},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 138, col: 21, offset: 3564},
+							pos:  position{line: 157, col: 21, offset: 4345},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 138, col: 23, offset: 3566},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
+						&ruleRefExpr{
+							pos:  position{line: 157, col: 23, offset: 4347},
+							name: "KeywordNot",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 138, col: 29, offset: 3572},
+							pos:  position{line: 157, col: 34, offset: 4358},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 138, col: 31, offset: 3574},
-							val:        "contains",
-							ignoreCase: false,
-							want:       "\"contains\"",
+						&ruleRefExpr{
+							pos:  position{line: 157, col: 36, offset: 4360},
+							name: "KeywordContains",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 138, col: 42, offset: 3585},
+							pos:  position{line: 157, col: 52, offset: 4376},
 							name: "_",
 						},
 					},
@@ -1089,25 +1249,23 @@ var g = &grammar{
 		},
 		{
 			name: "MatchMatches",
-			pos:  position{line: 141, col: 1, offset: 3617},
+			pos:  position{line: 160, col: 1, offset: 4408},
 			expr: &actionExpr{
-				pos: position{line: 141, col: 17, offset: 3633},
+				pos: position{line: 160, col: 17, offset: 4424},
 				run: (*parser).callonMatchMatches1,
 				expr: &seqExpr{
-					pos: position{line: 141, col: 17, offset: 3633},
+					pos: position{line: 160, col: 17, offset: 4424},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 141, col: 17, offset: 3633},
+							pos:  position{line: 160, col: 17, offset: 4424},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 141, col: 19, offset: 3635},
-							val:        "matches",
-							ignoreCase: false,
-							want:       "\"matches\"",
+						&ruleRefExpr{
+							pos:  position{line: 160, col: 19, offset: 4426},
+							name: "KeywordMatches",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 141, col: 29, offset: 3645},
+							pos:  position{line: 160, col: 34, offset: 4441},
 							name: "_",
 						},
 					},
@@ -1116,35 +1274,31 @@ var g = &grammar{
 		},
 		{
 			name: "MatchNotMatches",
-			pos:  position{line: 144, col: 1, offset: 3679},
+			pos:  position{line: 163, col: 1, offset: 4475},
 			expr: &actionExpr{
-				pos: position{line: 144, col: 20, offset: 3698},
+				pos: position{line: 163, col: 20, offset: 4494},
 				run: (*parser).callonMatchNotMatches1,
 				expr: &seqExpr{
-					pos: position{line: 144, col: 20, offset: 3698},
+					pos: position{line: 163, col: 20, offset: 4494},
 					exprs: []interface{}{
 						&ruleRefExpr{
-							pos:  position{line: 144, col: 20, offset: 3698},
+							pos:  position{line: 163, col: 20, offset: 4494},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 144, col: 22, offset: 3700},
-							val:        "not",
-							ignoreCase: false,
-							want:       "\"not\"",
+						&ruleRefExpr{
+							pos:  position{line: 163, col: 22, offset: 4496},
+							name: "KeywordNot",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 144, col: 28, offset: 3706},
+							pos:  position{line: 163, col: 33, offset: 4507},
 							name: "_",
 						},
-						&litMatcher{
-							pos:        position{line: 144, col: 30, offset: 3708},
-							val:        "matches",
-							ignoreCase: false,
-							want:       "\"matches\"",
+						&ruleRefExpr{
+							pos:  position{line: 163, col: 35, offset: 4509},
+							name: "KeywordMatches",
 						},
 						&ruleRefExpr{
-							pos:  position{line: 144, col: 40, offset: 3718},
+							pos:  position{line: 163, col: 50, offset: 4524},
 							name: "_",
 						},
 					},
@@ -1152,331 +1306,425 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "Selector",
-			displayName: "\"selector\"",
-			pos:         position{line: 148, col: 1, offset: 3756},
-			expr: &choiceExpr{
-				pos: position{line: 148, col: 24, offset: 3779},
-				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 148, col: 24, offset: 3779},
-						run: (*parser).callonSelector2,
-						expr: &seqExpr{
-							pos: position{line: 148, col: 24, offset: 3779},
-							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 148, col: 24, offset: 3779},
-									label: "first",
-									expr: &ruleRefExpr{
-										pos:  position{line: 148, col: 30, offset: 3785},
-										name: "Identifier",
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 148, col: 41, offset: 3796},
-									label: "rest",
-									expr: &zeroOrMoreExpr{
-										pos: position{line: 148, col: 46, offset: 3801},
-										expr: &ruleRefExpr{
-											pos:  position{line: 148, col: 46, offset: 3801},
-											name: "SelectorOrIndex",
-										},
-									},
-								},
-							},
+			name: "MatchStartsWith",
+			pos:  position{line: 166, col: 1, offset: 4561},
+			expr: &actionExpr{
+				pos: position{line: 166, col: 20, offset: 4580},
+				run: (*parser).callonMatchStartsWith1,
+				expr: &seqExpr{
+					pos: position{line: 166, col: 20, offset: 4580},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 166, col: 20, offset: 4580},
+							name: "_",
 						},
-					},
-					&actionExpr{
-						pos: position{line: 159, col: 5, offset: 4065},
-						run: (*parser).callonSelector9,
-						expr: &seqExpr{
-							pos: position{line: 159, col: 5, offset: 4065},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 159, col: 5, offset: 4065},
-									val:        "\"",
-									ignoreCase: false,
-									want:       "\"\\\"\"",
-								},
-								&labeledExpr{
-									pos:   position{line: 159, col: 9, offset: 4069},
-									label: "ptrsegs",
-									expr: &zeroOrMoreExpr{
-										pos: position{line: 159, col: 17, offset: 4077},
-										expr: &ruleRefExpr{
-											pos:  position{line: 159, col: 17, offset: 4077},
-											name: "JsonPointerSegment",
-										},
-									},
-								},
-								&litMatcher{
-									pos:        position{line: 159, col: 37, offset: 4097},
-									val:        "\"",
-									ignoreCase: false,
-									want:       "\"\\\"\"",
-								},
-							},
+						&ruleRefExpr{
+							pos:  position{line: 166, col: 22, offset: 4582},
+							name: "KeywordStartsWith",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 166, col: 40, offset: 4600},
+							name: "_",
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "JsonPointerSegment",
-			pos:  position{line: 180, col: 1, offset: 4575},
+			name: "MatchNotStartsWith",
+			pos:  position{line: 169, col: 1, offset: 4637},
 			expr: &actionExpr{
-				pos: position{line: 180, col: 23, offset: 4597},
-				run: (*parser).callonJsonPointerSegment1,
+				pos: position{line: 169, col: 23, offset: 4659},
+				run: (*parser).callonMatchNotStartsWith1,
 				expr: &seqExpr{
-					pos: position{line: 180, col: 23, offset: 4597},
+					pos: position{line: 169, col: 23, offset: 4659},
 					exprs: []interface{}{
-						&litMatcher{
-							pos:        position{line: 180, col: 23, offset: 4597},
-							val:        "/",
-							ignoreCase: false,
-							want:       "\"/\"",
+						&ruleRefExpr{
+							pos:  position{line: 169, col: 23, offset: 4659},
+							name: "_",
 						},
-						&labeledExpr{
-							pos:   position{line: 180, col: 27, offset: 4601},
-							label: "ident",
-							expr: &oneOrMoreExpr{
-								pos: position{line: 180, col: 33, offset: 4607},
-								expr: &charClassMatcher{
-									pos:        position{line: 180, col: 33, offset: 4607},
-									val:        "[\\pL\\pN-_.~:|]",
-									chars:      []rune{'-', '_', '.', '~', ':', '|'},
-									classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
-									ignoreCase: false,
-									inverted:   false,
-								},
-							},
+						&ruleRefExpr{
+							pos:  position{line: 169, col: 25, offset: 4661},
+							name: "KeywordNot",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 169, col: 36, offset: 4672},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 169, col: 38, offset: 4674},
+							name: "KeywordStartsWith",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 169, col: 56, offset: 4692},
+							name: "_",
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "Identifier",
-			pos:  position{line: 184, col: 1, offset: 4662},
+			name: "MatchEndsWith",
+			pos:  position{line: 172, col: 1, offset: 4732},
 			expr: &actionExpr{
-				pos: position{line: 184, col: 15, offset: 4676},
-				run: (*parser).callonIdentifier1,
+				pos: position{line: 172, col: 18, offset: 4749},
+				run: (*parser).callonMatchEndsWith1,
 				expr: &seqExpr{
-					pos: position{line: 184, col: 15, offset: 4676},
+					pos: position{line: 172, col: 18, offset: 4749},
 					exprs: []interface{}{
-						&charClassMatcher{
-							pos:        position{line: 184, col: 15, offset: 4676},
-							val:        "[a-zA-Z]",
-							ranges:     []rune{'a', 'z', 'A', 'Z'},
-							ignoreCase: false,
-							inverted:   false,
+						&ruleRefExpr{
+							pos:  position{line: 172, col: 18, offset: 4749},
+							name: "_",
 						},
-						&zeroOrMoreExpr{
-							pos: position{line: 184, col: 24, offset: 4685},
-							expr: &charClassMatcher{
-								pos:        position{line: 184, col: 24, offset: 4685},
-								val:        "[a-zA-Z0-9_/]",
-								chars:      []rune{'_', '/'},
-								ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
-								ignoreCase: false,
-								inverted:   false,
-							},
+						&ruleRefExpr{
+							pos:  position{line: 172, col: 20, offset: 4751},
+							name: "KeywordEndsWith",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 172, col: 36, offset: 4767},
+							name: "_",
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "SelectorOrIndex",
-			pos:  position{line: 188, col: 1, offset: 4735},
+			name: "MatchNotEndsWith",
+			pos:  position{line: 175, col: 1, offset: 4802},
+			expr: &actionExpr{
+				pos: position{line: 175, col: 21, offset: 4822},
+				run: (*parser).callonMatchNotEndsWith1,
+				expr: &seqExpr{
+					pos: position{line: 175, col: 21, offset: 4822},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 175, col: 21, offset: 4822},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 175, col: 23, offset: 4824},
+							name: "KeywordNot",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 175, col: 34, offset: 4835},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 175, col: 36, offset: 4837},
+							name: "KeywordEndsWith",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 175, col: 52, offset: 4853},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchLike",
+			pos:  position{line: 178, col: 1, offset: 4891},
+			expr: &actionExpr{
+				pos: position{line: 178, col: 14, offset: 4904},
+				run: (*parser).callonMatchLike1,
+				expr: &seqExpr{
+					pos: position{line: 178, col: 14, offset: 4904},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 178, col: 14, offset: 4904},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 178, col: 16, offset: 4906},
+							name: "KeywordLike",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 178, col: 28, offset: 4918},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MatchNotLike",
+			pos:  position{line: 181, col: 1, offset: 4949},
+			expr: &actionExpr{
+				pos: position{line: 181, col: 17, offset: 4965},
+				run: (*parser).callonMatchNotLike1,
+				expr: &seqExpr{
+					pos: position{line: 181, col: 17, offset: 4965},
+					exprs: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 181, col: 17, offset: 4965},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 181, col: 19, offset: 4967},
+							name: "KeywordNot",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 181, col: 30, offset: 4978},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 181, col: 32, offset: 4980},
+							name: "KeywordLike",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 181, col: 44, offset: 4992},
+							name: "_",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "KeywordAnd",
+			pos:  position{line: 185, col: 1, offset: 5027},
 			expr: &choiceExpr{
-				pos: position{line: 188, col: 20, offset: 4754},
+				pos: position{line: 185, col: 15, offset: 5041},
 				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 188, col: 20, offset: 4754},
-						run: (*parser).callonSelectorOrIndex2,
-						expr: &seqExpr{
-							pos: position{line: 188, col: 20, offset: 4754},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 188, col: 20, offset: 4754},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
-								},
-								&labeledExpr{
-									pos:   position{line: 188, col: 24, offset: 4758},
-									label: "ident",
-									expr: &ruleRefExpr{
-										pos:  position{line: 188, col: 30, offset: 4764},
-										name: "Identifier",
-									},
+					&litMatcher{
+						pos:        position{line: 185, col: 15, offset: 5041},
+						val:        "and",
+						ignoreCase: false,
+						want:       "\"and\"",
+					},
+					&seqExpr{
+						pos: position{line: 185, col: 23, offset: 5049},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 185, col: 23, offset: 5049},
+								run: (*parser).callonKeywordAnd4,
+							},
+							&labeledExpr{
+								pos:   position{line: 185, col: 61, offset: 5087},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 185, col: 63, offset: 5089},
+									name: "Word",
 								},
 							},
-						},
-					},
-					&actionExpr{
-						pos: position{line: 190, col: 5, offset: 4802},
-						run: (*parser).callonSelectorOrIndex7,
-						expr: &labeledExpr{
-							pos:   position{line: 190, col: 5, offset: 4802},
-							label: "expr",
-							expr: &ruleRefExpr{
-								pos:  position{line: 190, col: 10, offset: 4807},
-								name: "IndexExpression",
+							&andCodeExpr{
+								pos: position{line: 185, col: 68, offset: 5094},
+								run: (*parser).callonKeywordAnd7,
 							},
 						},
 					},
-					&actionExpr{
-						pos: position{line: 192, col: 5, offset: 4849},
-						run: (*parser).callonSelectorOrIndex10,
-						expr: &seqExpr{
-							pos: position{line: 192, col: 5, offset: 4849},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 192, col: 5, offset: 4849},
-									val:        ".",
-									ignoreCase: false,
-									want:       "\".\"",
-								},
-								&labeledExpr{
-									pos:   position{line: 192, col: 9, offset: 4853},
-									label: "idx",
-									expr: &oneOrMoreExpr{
-										pos: position{line: 192, col: 13, offset: 4857},
-										expr: &charClassMatcher{
-											pos:        position{line: 192, col: 13, offset: 4857},
-											val:        "[0-9]",
-											ranges:     []rune{'0', '9'},
-											ignoreCase: false,
-											inverted:   false,
-										},
-									},
+				},
+			},
+		},
+		{
+			name: "KeywordOr",
+			pos:  position{line: 187, col: 1, offset: 5149},
+			expr: &choiceExpr{
+				pos: position{line: 187, col: 14, offset: 5162},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 187, col: 14, offset: 5162},
+						val:        "or",
+						ignoreCase: false,
+						want:       "\"or\"",
+					},
+					&seqExpr{
+						pos: position{line: 187, col: 21, offset: 5169},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 187, col: 21, offset: 5169},
+								run: (*parser).callonKeywordOr4,
+							},
+							&labeledExpr{
+								pos:   position{line: 187, col: 59, offset: 5207},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 187, col: 61, offset: 5209},
+									name: "Word",
 								},
 							},
+							&andCodeExpr{
+								pos: position{line: 187, col: 66, offset: 5214},
+								run: (*parser).callonKeywordOr7,
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name:        "IndexExpression",
-			displayName: "\"index\"",
-			pos:         position{line: 196, col: 1, offset: 4903},
+			name: "KeywordNot",
+			pos:  position{line: 189, col: 1, offset: 5268},
 			expr: &choiceExpr{
-				pos: position{line: 196, col: 28, offset: 4930},
+				pos: position{line: 189, col: 15, offset: 5282},
 				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 196, col: 28, offset: 4930},
-						run: (*parser).callonIndexExpression2,
-						expr: &seqExpr{
-							pos: position{line: 196, col: 28, offset: 4930},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 196, col: 28, offset: 4930},
-									val:        "[",
-									ignoreCase: false,
-									want:       "\"[\"",
-								},
-								&zeroOrOneExpr{
-									pos: position{line: 196, col: 32, offset: 4934},
-									expr: &ruleRefExpr{
-										pos:  position{line: 196, col: 32, offset: 4934},
-										name: "_",
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 196, col: 35, offset: 4937},
-									label: "lit",
-									expr: &ruleRefExpr{
-										pos:  position{line: 196, col: 39, offset: 4941},
-										name: "StringLiteral",
-									},
-								},
-								&zeroOrOneExpr{
-									pos: position{line: 196, col: 53, offset: 4955},
-									expr: &ruleRefExpr{
-										pos:  position{line: 196, col: 53, offset: 4955},
-										name: "_",
-									},
-								},
-								&litMatcher{
-									pos:        position{line: 196, col: 56, offset: 4958},
-									val:        "]",
-									ignoreCase: false,
-									want:       "\"]\"",
+					&litMatcher{
+						pos:        position{line: 189, col: 15, offset: 5282},
+						val:        "not",
+						ignoreCase: false,
+						want:       "\"not\"",
+					},
+					&seqExpr{
+						pos: position{line: 189, col: 23, offset: 5290},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 189, col: 23, offset: 5290},
+								run: (*parser).callonKeywordNot4,
+							},
+							&labeledExpr{
+								pos:   position{line: 189, col: 61, offset: 5328},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 189, col: 63, offset: 5330},
+									name: "Word",
 								},
 							},
+							&andCodeExpr{
+								pos: position{line: 189, col: 68, offset: 5335},
+								run: (*parser).callonKeywordNot7,
+							},
 						},
 					},
+				},
+			},
+		},
+		{
+			name: "KeywordLet",
+			pos:  position{line: 191, col: 1, offset: 5390},
+			expr: &choiceExpr{
+				pos: position{line: 191, col: 15, offset: 5404},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 191, col: 15, offset: 5404},
+						val:        "let",
+						ignoreCase: false,
+						want:       "\"let\"",
+					},
 					&seqExpr{
-						pos: position{line: 198, col: 5, offset: 4987},
+						pos: position{line: 191, col: 23, offset: 5412},
 						exprs: []interface{}{
-							&litMatcher{
-								pos:        position{line: 198, col: 5, offset: 4987},
-								val:        "[",
-								ignoreCase: false,
-								want:       "\"[\"",
+							&andCodeExpr{
+								pos: position{line: 191, col: 23, offset: 5412},
+								run: (*parser).callonKeywordLet4,
 							},
-							&zeroOrOneExpr{
-								pos: position{line: 198, col: 9, offset: 4991},
+							&labeledExpr{
+								pos:   position{line: 191, col: 61, offset: 5450},
+								label: "w",
 								expr: &ruleRefExpr{
-									pos:  position{line: 198, col: 9, offset: 4991},
-									name: "_",
+									pos:  position{line: 191, col: 63, offset: 5452},
+									name: "Word",
 								},
 							},
-							&notExpr{
-								pos: position{line: 198, col: 12, offset: 4994},
+							&andCodeExpr{
+								pos: position{line: 191, col: 68, offset: 5457},
+								run: (*parser).callonKeywordLet7,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "KeywordIn",
+			pos:  position{line: 193, col: 1, offset: 5512},
+			expr: &choiceExpr{
+				pos: position{line: 193, col: 14, offset: 5525},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 193, col: 14, offset: 5525},
+						val:        "in",
+						ignoreCase: false,
+						want:       "\"in\"",
+					},
+					&seqExpr{
+						pos: position{line: 193, col: 21, offset: 5532},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 193, col: 21, offset: 5532},
+								run: (*parser).callonKeywordIn4,
+							},
+							&labeledExpr{
+								pos:   position{line: 193, col: 59, offset: 5570},
+								label: "w",
 								expr: &ruleRefExpr{
-									pos:  position{line: 198, col: 13, offset: 4995},
-									name: "StringLiteral",
+									pos:  position{line: 193, col: 61, offset: 5572},
+									name: "Word",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 198, col: 27, offset: 5009},
-								run: (*parser).callonIndexExpression18,
+								pos: position{line: 193, col: 66, offset: 5577},
+								run: (*parser).callonKeywordIn7,
 							},
 						},
 					},
+				},
+			},
+		},
+		{
+			name: "KeywordIs",
+			pos:  position{line: 195, col: 1, offset: 5631},
+			expr: &choiceExpr{
+				pos: position{line: 195, col: 14, offset: 5644},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 195, col: 14, offset: 5644},
+						val:        "is",
+						ignoreCase: false,
+						want:       "\"is\"",
+					},
 					&seqExpr{
-						pos: position{line: 200, col: 5, offset: 5061},
+						pos: position{line: 195, col: 21, offset: 5651},
 						exprs: []interface{}{
-							&litMatcher{
-								pos:        position{line: 200, col: 5, offset: 5061},
-								val:        "[",
-								ignoreCase: false,
-								want:       "\"[\"",
+							&andCodeExpr{
+								pos: position{line: 195, col: 21, offset: 5651},
+								run: (*parser).callonKeywordIs4,
 							},
-							&zeroOrOneExpr{
-								pos: position{line: 200, col: 9, offset: 5065},
+							&labeledExpr{
+								pos:   position{line: 195, col: 59, offset: 5689},
+								label: "w",
 								expr: &ruleRefExpr{
-									pos:  position{line: 200, col: 9, offset: 5065},
-									name: "_",
+									pos:  position{line: 195, col: 61, offset: 5691},
+									name: "Word",
 								},
 							},
-							&ruleRefExpr{
-								pos:  position{line: 200, col: 12, offset: 5068},
-								name: "StringLiteral",
+							&andCodeExpr{
+								pos: position{line: 195, col: 66, offset: 5696},
+								run: (*parser).callonKeywordIs7,
 							},
-							&zeroOrOneExpr{
-								pos: position{line: 200, col: 26, offset: 5082},
-								expr: &ruleRefExpr{
-									pos:  position{line: 200, col: 26, offset: 5082},
-									name: "_",
-								},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "KeywordEmpty",
+			pos:  position{line: 197, col: 1, offset: 5750},
+			expr: &choiceExpr{
+				pos: position{line: 197, col: 17, offset: 5766},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 197, col: 17, offset: 5766},
+						val:        "empty",
+						ignoreCase: false,
+						want:       "\"empty\"",
+					},
+					&seqExpr{
+						pos: position{line: 197, col: 27, offset: 5776},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 197, col: 27, offset: 5776},
+								run: (*parser).callonKeywordEmpty4,
 							},
-							&notExpr{
-								pos: position{line: 200, col: 29, offset: 5085},
-								expr: &litMatcher{
-									pos:        position{line: 200, col: 30, offset: 5086},
-									val:        "]",
-									ignoreCase: false,
-									want:       "\"]\"",
+							&labeledExpr{
+								pos:   position{line: 197, col: 65, offset: 5814},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 197, col: 67, offset: 5816},
+									name: "Word",
 								},
 							},
 							&andCodeExpr{
-								pos: position{line: 200, col: 34, offset: 5090},
-								run: (*parser).callonIndexExpression28,
+								pos: position{line: 197, col: 72, offset: 5821},
+								run: (*parser).callonKeywordEmpty7,
 							},
 						},
 					},
@@ -1484,70 +1732,71 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "ExpressionValue",
-			pos:  position{line: 204, col: 1, offset: 5153},
+			name: "KeywordNull",
+			pos:  position{line: 199, col: 1, offset: 5878},
 			expr: &choiceExpr{
-				pos: position{line: 204, col: 20, offset: 5172},
+				pos: position{line: 199, col: 16, offset: 5893},
 				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 204, col: 20, offset: 5172},
-						run: (*parser).callonExpressionValue2,
-						expr: &seqExpr{
-							pos: position{line: 204, col: 20, offset: 5172},
-							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 204, col: 20, offset: 5172},
-									label: "left",
-									expr: &ruleRefExpr{
-										pos:  position{line: 204, col: 25, offset: 5177},
-										name: "Value",
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 204, col: 31, offset: 5183},
-									label: "operator",
-									expr: &choiceExpr{
-										pos: position{line: 204, col: 41, offset: 5193},
-										alternatives: []interface{}{
-											&ruleRefExpr{
-												pos:  position{line: 204, col: 41, offset: 5193},
-												name: "MathOpPlus",
-											},
-											&ruleRefExpr{
-												pos:  position{line: 204, col: 54, offset: 5206},
-												name: "MathOpMinus",
-											},
-											&ruleRefExpr{
-												pos:  position{line: 204, col: 68, offset: 5220},
-												name: "MathOpMul",
-											},
-											&ruleRefExpr{
-												pos:  position{line: 204, col: 80, offset: 5232},
-												name: "MathOpDiv",
-											},
-										},
-									},
-								},
-								&labeledExpr{
-									pos:   position{line: 204, col: 91, offset: 5243},
-									label: "right",
-									expr: &ruleRefExpr{
-										pos:  position{line: 204, col: 97, offset: 5249},
-										name: "Value",
-									},
+					&litMatcher{
+						pos:        position{line: 199, col: 16, offset: 5893},
+						val:        "null",
+						ignoreCase: false,
+						want:       "\"null\"",
+					},
+					&seqExpr{
+						pos: position{line: 199, col: 25, offset: 5902},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 199, col: 25, offset: 5902},
+								run: (*parser).callonKeywordNull4,
+							},
+							&labeledExpr{
+								pos:   position{line: 199, col: 63, offset: 5940},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 199, col: 65, offset: 5942},
+									name: "Word",
 								},
 							},
+							&andCodeExpr{
+								pos: position{line: 199, col: 70, offset: 5947},
+								run: (*parser).callonKeywordNull7,
+							},
 						},
 					},
-					&actionExpr{
-						pos: position{line: 210, col: 5, offset: 5378},
-						run: (*parser).callonExpressionValue14,
-						expr: &labeledExpr{
-							pos:   position{line: 210, col: 5, offset: 5378},
-							label: "value",
-							expr: &ruleRefExpr{
-								pos:  position{line: 210, col: 11, offset: 5384},
-								name: "Value",
+				},
+			},
+		},
+		{
+			name: "KeywordContains",
+			pos:  position{line: 201, col: 1, offset: 6003},
+			expr: &choiceExpr{
+				pos: position{line: 201, col: 20, offset: 6022},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 201, col: 20, offset: 6022},
+						val:        "contains",
+						ignoreCase: false,
+						want:       "\"contains\"",
+					},
+					&seqExpr{
+						pos: position{line: 201, col: 33, offset: 6035},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 201, col: 33, offset: 6035},
+								run: (*parser).callonKeywordContains4,
+							},
+							&labeledExpr{
+								pos:   position{line: 201, col: 71, offset: 6073},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 201, col: 73, offset: 6075},
+									name: "Word",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 201, col: 78, offset: 6080},
+								run: (*parser).callonKeywordContains7,
 							},
 						},
 					},
@@ -1555,32 +1804,35 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MathOpPlus",
-			pos:  position{line: 218, col: 1, offset: 5499},
-			expr: &actionExpr{
-				pos: position{line: 218, col: 15, offset: 5513},
-				run: (*parser).callonMathOpPlus1,
-				expr: &seqExpr{
-					pos: position{line: 218, col: 15, offset: 5513},
-					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 218, col: 15, offset: 5513},
-							expr: &ruleRefExpr{
-								pos:  position{line: 218, col: 15, offset: 5513},
-								name: "_",
+			name: "KeywordMatches",
+			pos:  position{line: 203, col: 1, offset: 6140},
+			expr: &choiceExpr{
+				pos: position{line: 203, col: 19, offset: 6158},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 203, col: 19, offset: 6158},
+						val:        "matches",
+						ignoreCase: false,
+						want:       "\"matches\"",
+					},
+					&seqExpr{
+						pos: position{line: 203, col: 31, offset: 6170},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 203, col: 31, offset: 6170},
+								run: (*parser).callonKeywordMatches4,
 							},
-						},
-						&litMatcher{
-							pos:        position{line: 218, col: 18, offset: 5516},
-							val:        "+",
-							ignoreCase: false,
-							want:       "\"+\"",
-						},
-						&zeroOrOneExpr{
-							pos: position{line: 218, col: 22, offset: 5520},
-							expr: &ruleRefExpr{
-								pos:  position{line: 218, col: 22, offset: 5520},
-								name: "_",
+							&labeledExpr{
+								pos:   position{line: 203, col: 69, offset: 6208},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 203, col: 71, offset: 6210},
+									name: "Word",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 203, col: 76, offset: 6215},
+								run: (*parser).callonKeywordMatches7,
 							},
 						},
 					},
@@ -1588,32 +1840,35 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MathOpMinus",
-			pos:  position{line: 222, col: 1, offset: 5554},
-			expr: &actionExpr{
-				pos: position{line: 222, col: 16, offset: 5569},
-				run: (*parser).callonMathOpMinus1,
-				expr: &seqExpr{
-					pos: position{line: 222, col: 16, offset: 5569},
-					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 222, col: 16, offset: 5569},
-							expr: &ruleRefExpr{
-								pos:  position{line: 222, col: 16, offset: 5569},
-								name: "_",
+			name: "KeywordStartsWith",
+			pos:  position{line: 205, col: 1, offset: 6274},
+			expr: &choiceExpr{
+				pos: position{line: 205, col: 22, offset: 6295},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 205, col: 22, offset: 6295},
+						val:        "startswith",
+						ignoreCase: false,
+						want:       "\"startswith\"",
+					},
+					&seqExpr{
+						pos: position{line: 205, col: 37, offset: 6310},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 205, col: 37, offset: 6310},
+								run: (*parser).callonKeywordStartsWith4,
 							},
-						},
-						&litMatcher{
-							pos:        position{line: 222, col: 19, offset: 5572},
-							val:        "-",
-							ignoreCase: false,
-							want:       "\"-\"",
-						},
-						&zeroOrOneExpr{
-							pos: position{line: 222, col: 23, offset: 5576},
-							expr: &ruleRefExpr{
-								pos:  position{line: 222, col: 23, offset: 5576},
-								name: "_",
+							&labeledExpr{
+								pos:   position{line: 205, col: 75, offset: 6348},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 205, col: 77, offset: 6350},
+									name: "Word",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 205, col: 82, offset: 6355},
+								run: (*parser).callonKeywordStartsWith7,
 							},
 						},
 					},
@@ -1621,32 +1876,71 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MathOpMul",
-			pos:  position{line: 226, col: 1, offset: 5611},
-			expr: &actionExpr{
-				pos: position{line: 226, col: 14, offset: 5624},
-				run: (*parser).callonMathOpMul1,
-				expr: &seqExpr{
-					pos: position{line: 226, col: 14, offset: 5624},
-					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 226, col: 14, offset: 5624},
-							expr: &ruleRefExpr{
-								pos:  position{line: 226, col: 14, offset: 5624},
-								name: "_",
+			name: "KeywordEndsWith",
+			pos:  position{line: 207, col: 1, offset: 6417},
+			expr: &choiceExpr{
+				pos: position{line: 207, col: 20, offset: 6436},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 207, col: 20, offset: 6436},
+						val:        "endswith",
+						ignoreCase: false,
+						want:       "\"endswith\"",
+					},
+					&seqExpr{
+						pos: position{line: 207, col: 33, offset: 6449},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 207, col: 33, offset: 6449},
+								run: (*parser).callonKeywordEndsWith4,
+							},
+							&labeledExpr{
+								pos:   position{line: 207, col: 71, offset: 6487},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 207, col: 73, offset: 6489},
+									name: "Word",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 207, col: 78, offset: 6494},
+								run: (*parser).callonKeywordEndsWith7,
 							},
 						},
-						&litMatcher{
-							pos:        position{line: 226, col: 17, offset: 5627},
-							val:        "*",
-							ignoreCase: false,
-							want:       "\"*\"",
-						},
-						&zeroOrOneExpr{
-							pos: position{line: 226, col: 21, offset: 5631},
-							expr: &ruleRefExpr{
-								pos:  position{line: 226, col: 21, offset: 5631},
-								name: "_",
+					},
+				},
+			},
+		},
+		{
+			name: "KeywordLike",
+			pos:  position{line: 209, col: 1, offset: 6554},
+			expr: &choiceExpr{
+				pos: position{line: 209, col: 16, offset: 6569},
+				alternatives: []interface{}{
+					&litMatcher{
+						pos:        position{line: 209, col: 16, offset: 6569},
+						val:        "like",
+						ignoreCase: false,
+						want:       "\"like\"",
+					},
+					&seqExpr{
+						pos: position{line: 209, col: 25, offset: 6578},
+						exprs: []interface{}{
+							&andCodeExpr{
+								pos: position{line: 209, col: 25, offset: 6578},
+								run: (*parser).callonKeywordLike4,
+							},
+							&labeledExpr{
+								pos:   position{line: 209, col: 63, offset: 6616},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 209, col: 65, offset: 6618},
+									name: "Word",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 209, col: 70, offset: 6623},
+								run: (*parser).callonKeywordLike7,
 							},
 						},
 					},
@@ -1654,32 +1948,30 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "MathOpDiv",
-			pos:  position{line: 230, col: 1, offset: 5664},
+			name: "Word",
+			pos:  position{line: 212, col: 1, offset: 6752},
 			expr: &actionExpr{
-				pos: position{line: 230, col: 14, offset: 5677},
-				run: (*parser).callonMathOpDiv1,
+				pos: position{line: 212, col: 9, offset: 6760},
+				run: (*parser).callonWord1,
 				expr: &seqExpr{
-					pos: position{line: 230, col: 14, offset: 5677},
+					pos: position{line: 212, col: 9, offset: 6760},
 					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 230, col: 14, offset: 5677},
-							expr: &ruleRefExpr{
-								pos:  position{line: 230, col: 14, offset: 5677},
-								name: "_",
-							},
-						},
-						&litMatcher{
-							pos:        position{line: 230, col: 17, offset: 5680},
-							val:        "/",
+						&charClassMatcher{
+							pos:        position{line: 212, col: 9, offset: 6760},
+							val:        "[\\pL]",
+							classes:    []*unicode.RangeTable{rangeTable("L")},
 							ignoreCase: false,
-							want:       "\"/\"",
+							inverted:   false,
 						},
-						&zeroOrOneExpr{
-							pos: position{line: 230, col: 21, offset: 5684},
-							expr: &ruleRefExpr{
-								pos:  position{line: 230, col: 21, offset: 5684},
-								name: "_",
+						&zeroOrMoreExpr{
+							pos: position{line: 212, col: 15, offset: 6766},
+							expr: &charClassMatcher{
+								pos:        position{line: 212, col: 15, offset: 6766},
+								val:        "[\\pL\\pN_]",
+								chars:      []rune{'_'},
+								classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
+								ignoreCase: false,
+								inverted:   false,
 							},
 						},
 					},
@@ -1687,177 +1979,228 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "Value",
-			displayName: "\"value\"",
-			pos:         position{line: 234, col: 1, offset: 5717},
+			name:        "Selector",
+			displayName: "\"selector\"",
+			pos:         position{line: 216, col: 1, offset: 6812},
 			expr: &choiceExpr{
-				pos: position{line: 234, col: 18, offset: 5734},
+				pos: position{line: 216, col: 24, offset: 6835},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 234, col: 18, offset: 5734},
-						run: (*parser).callonValue2,
-						expr: &labeledExpr{
-							pos:   position{line: 234, col: 18, offset: 5734},
-							label: "b",
-							expr: &ruleRefExpr{
-								pos:  position{line: 234, col: 20, offset: 5736},
-								name: "TrueOrFalse",
-							},
-						},
-					},
-					&actionExpr{
-						pos: position{line: 236, col: 5, offset: 5819},
-						run: (*parser).callonValue5,
-						expr: &labeledExpr{
-							pos:   position{line: 236, col: 5, offset: 5819},
-							label: "u",
-							expr: &ruleRefExpr{
-								pos:  position{line: 236, col: 7, offset: 5821},
-								name: "Undefined",
-							},
-						},
-					},
-					&actionExpr{
-						pos: position{line: 238, col: 5, offset: 5907},
-						run: (*parser).callonValue8,
-						expr: &labeledExpr{
-							pos:   position{line: 238, col: 5, offset: 5907},
-							label: "selector",
-							expr: &ruleRefExpr{
-								pos:  position{line: 238, col: 14, offset: 5916},
-								name: "Selector",
+						pos: position{line: 216, col: 24, offset: 6835},
+						run: (*parser).callonSelector2,
+						expr: &seqExpr{
+							pos: position{line: 216, col: 24, offset: 6835},
+							exprs: []interface{}{
+								&andCodeExpr{
+									pos: position{line: 216, col: 24, offset: 6835},
+									run: (*parser).callonSelector4,
+								},
+								&labeledExpr{
+									pos:   position{line: 216, col: 90, offset: 6901},
+									label: "sel",
+									expr: &ruleRefExpr{
+										pos:  position{line: 216, col: 94, offset: 6905},
+										name: "JSONPointerSelector",
+									},
+								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 240, col: 5, offset: 6051},
-						run: (*parser).callonValue11,
+						pos: position{line: 218, col: 5, offset: 6950},
+						run: (*parser).callonSelector7,
 						expr: &seqExpr{
-							pos: position{line: 240, col: 5, offset: 6051},
+							pos: position{line: 218, col: 5, offset: 6950},
 							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 240, col: 5, offset: 6051},
-									label: "n",
-									expr: &ruleRefExpr{
-										pos:  position{line: 240, col: 7, offset: 6053},
-										name: "Float",
-									},
+								&andCodeExpr{
+									pos: position{line: 218, col: 5, offset: 6950},
+									run: (*parser).callonSelector9,
 								},
-								&andExpr{
-									pos: position{line: 240, col: 13, offset: 6059},
+								&labeledExpr{
+									pos:   position{line: 218, col: 68, offset: 7013},
+									label: "sel",
 									expr: &ruleRefExpr{
-										pos:  position{line: 240, col: 14, offset: 6060},
-										name: "AfterNumbers",
+										pos:  position{line: 218, col: 72, offset: 7017},
+										name: "JSONPathSelector",
 									},
 								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 242, col: 5, offset: 6147},
-						run: (*parser).callonValue17,
+						pos: position{line: 220, col: 5, offset: 7059},
+						run: (*parser).callonSelector12,
 						expr: &seqExpr{
-							pos: position{line: 242, col: 5, offset: 6147},
+							pos: position{line: 220, col: 5, offset: 7059},
 							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 242, col: 5, offset: 6147},
-									label: "n",
-									expr: &ruleRefExpr{
-										pos:  position{line: 242, col: 7, offset: 6149},
-										name: "Integer",
-									},
+								&andCodeExpr{
+									pos: position{line: 220, col: 5, offset: 7059},
+									run: (*parser).callonSelector14,
 								},
-								&andExpr{
-									pos: position{line: 242, col: 15, offset: 6157},
+								&labeledExpr{
+									pos:   position{line: 220, col: 65, offset: 7119},
+									label: "sel",
 									expr: &ruleRefExpr{
-										pos:  position{line: 242, col: 16, offset: 6158},
-										name: "AfterNumbers",
+										pos:  position{line: 220, col: 69, offset: 7123},
+										name: "BexprSelector",
 									},
 								},
 							},
 						},
 					},
+				},
+			},
+		},
+		{
+			name: "BexprSelector",
+			pos:  position{line: 224, col: 1, offset: 7161},
+			expr: &choiceExpr{
+				pos: position{line: 224, col: 18, offset: 7178},
+				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 244, col: 5, offset: 6241},
-						run: (*parser).callonValue23,
+						pos: position{line: 224, col: 18, offset: 7178},
+						run: (*parser).callonBexprSelector2,
 						expr: &seqExpr{
-							pos: position{line: 244, col: 5, offset: 6241},
+							pos: position{line: 224, col: 18, offset: 7178},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 244, col: 5, offset: 6241},
-									label: "n",
+									pos:   position{line: 224, col: 18, offset: 7178},
+									label: "first",
 									expr: &ruleRefExpr{
-										pos:  position{line: 244, col: 7, offset: 6243},
-										name: "Float",
+										pos:  position{line: 224, col: 24, offset: 7184},
+										name: "Identifier",
 									},
 								},
-								&notExpr{
-									pos: position{line: 244, col: 13, offset: 6249},
-									expr: &ruleRefExpr{
-										pos:  position{line: 244, col: 14, offset: 6250},
-										name: "AfterNumbers",
+								&andCodeExpr{
+									pos: position{line: 224, col: 35, offset: 7195},
+									run: (*parser).callonBexprSelector6,
+								},
+								&labeledExpr{
+									pos:   position{line: 224, col: 87, offset: 7247},
+									label: "rest",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 224, col: 92, offset: 7252},
+										expr: &ruleRefExpr{
+											pos:  position{line: 224, col: 92, offset: 7252},
+											name: "SelectorOrIndex",
+										},
 									},
 								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 246, col: 5, offset: 6323},
-						run: (*parser).callonValue29,
+						pos: position{line: 233, col: 5, offset: 7457},
+						run: (*parser).callonBexprSelector10,
 						expr: &seqExpr{
-							pos: position{line: 246, col: 5, offset: 6323},
+							pos: position{line: 233, col: 5, offset: 7457},
 							exprs: []interface{}{
-								&labeledExpr{
-									pos:   position{line: 246, col: 5, offset: 6323},
-									label: "n",
-									expr: &ruleRefExpr{
-										pos:  position{line: 246, col: 7, offset: 6325},
-										name: "Integer",
-									},
+								&litMatcher{
+									pos:        position{line: 233, col: 5, offset: 7457},
+									val:        "$",
+									ignoreCase: false,
+									want:       "\"$\"",
 								},
 								&notExpr{
-									pos: position{line: 246, col: 15, offset: 6333},
+									pos: position{line: 233, col: 9, offset: 7461},
 									expr: &ruleRefExpr{
-										pos:  position{line: 246, col: 16, offset: 6334},
-										name: "AfterNumbers",
+										pos:  position{line: 233, col: 10, offset: 7462},
+										name: "RangeExpression",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 233, col: 26, offset: 7478},
+									label: "rest",
+									expr: &oneOrMoreExpr{
+										pos: position{line: 233, col: 31, offset: 7483},
+										expr: &ruleRefExpr{
+											pos:  position{line: 233, col: 31, offset: 7483},
+											name: "SelectorOrIndex",
+										},
 									},
 								},
 							},
 						},
 					},
+					&seqExpr{
+						pos: position{line: 241, col: 5, offset: 7704},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 241, col: 5, offset: 7704},
+								val:        "$",
+								ignoreCase: false,
+								want:       "\"$\"",
+							},
+							&andExpr{
+								pos: position{line: 241, col: 9, offset: 7708},
+								expr: &ruleRefExpr{
+									pos:  position{line: 241, col: 10, offset: 7709},
+									name: "RangeExpression",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 241, col: 26, offset: 7725},
+								run: (*parser).callonBexprSelector22,
+							},
+						},
+					},
 					&actionExpr{
-						pos: position{line: 248, col: 5, offset: 6407},
-						run: (*parser).callonValue35,
+						pos: position{line: 243, col: 5, offset: 7806},
+						run: (*parser).callonBexprSelector23,
 						expr: &seqExpr{
-							pos: position{line: 248, col: 5, offset: 6407},
+							pos: position{line: 243, col: 5, offset: 7806},
 							exprs: []interface{}{
 								&labeledExpr{
-									pos:   position{line: 248, col: 5, offset: 6407},
-									label: "n",
+									pos:   position{line: 243, col: 5, offset: 7806},
+									label: "first",
 									expr: &ruleRefExpr{
-										pos:  position{line: 248, col: 7, offset: 6409},
-										name: "TrueOrFalse",
+										pos:  position{line: 243, col: 11, offset: 7812},
+										name: "IndexExpression",
 									},
 								},
-								&notExpr{
-									pos: position{line: 248, col: 19, offset: 6421},
-									expr: &ruleRefExpr{
-										pos:  position{line: 248, col: 20, offset: 6422},
-										name: "AfterNumbers",
+								&labeledExpr{
+									pos:   position{line: 243, col: 27, offset: 7828},
+									label: "rest",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 243, col: 32, offset: 7833},
+										expr: &ruleRefExpr{
+											pos:  position{line: 243, col: 32, offset: 7833},
+											name: "SelectorOrIndex",
+										},
 									},
 								},
 							},
 						},
 					},
 					&actionExpr{
-						pos: position{line: 250, col: 5, offset: 6493},
-						run: (*parser).callonValue41,
-						expr: &labeledExpr{
-							pos:   position{line: 250, col: 5, offset: 6493},
-							label: "s",
-							expr: &ruleRefExpr{
-								pos:  position{line: 250, col: 7, offset: 6495},
-								name: "StringLiteral",
+						pos: position{line: 253, col: 5, offset: 8080},
+						run: (*parser).callonBexprSelector30,
+						expr: &seqExpr{
+							pos: position{line: 253, col: 5, offset: 8080},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 253, col: 5, offset: 8080},
+									val:        "\"",
+									ignoreCase: false,
+									want:       "\"\\\"\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 253, col: 9, offset: 8084},
+									label: "ptrsegs",
+									expr: &zeroOrMoreExpr{
+										pos: position{line: 253, col: 17, offset: 8092},
+										expr: &ruleRefExpr{
+											pos:  position{line: 253, col: 17, offset: 8092},
+											name: "JsonPointerSegment",
+										},
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 253, col: 37, offset: 8112},
+									val:        "\"",
+									ignoreCase: false,
+									want:       "\"\\\"\"",
+								},
 							},
 						},
 					},
@@ -1865,53 +2208,96 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "Undefined",
-			displayName: "\"undefined\"",
-			pos:         position{line: 254, col: 1, offset: 6581},
-			expr: &choiceExpr{
-				pos: position{line: 254, col: 26, offset: 6606},
-				alternatives: []interface{}{
-					&actionExpr{
-						pos: position{line: 254, col: 26, offset: 6606},
-						run: (*parser).callonUndefined2,
-						expr: &seqExpr{
-							pos: position{line: 254, col: 26, offset: 6606},
-							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 254, col: 26, offset: 6606},
-									val:        "undefined",
+			name: "JsonPointerSegment",
+			pos:  position{line: 274, col: 1, offset: 8590},
+			expr: &actionExpr{
+				pos: position{line: 274, col: 23, offset: 8612},
+				run: (*parser).callonJsonPointerSegment1,
+				expr: &seqExpr{
+					pos: position{line: 274, col: 23, offset: 8612},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 274, col: 23, offset: 8612},
+							val:        "/",
+							ignoreCase: false,
+							want:       "\"/\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 274, col: 27, offset: 8616},
+							label: "ident",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 274, col: 33, offset: 8622},
+								expr: &charClassMatcher{
+									pos:        position{line: 274, col: 33, offset: 8622},
+									val:        "[\\pL\\pN-_.~:|]",
+									chars:      []rune{'-', '_', '.', '~', ':', '|'},
+									classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
 									ignoreCase: false,
-									want:       "\"undefined\"",
-								},
-								&andExpr{
-									pos: position{line: 254, col: 38, offset: 6618},
-									expr: &ruleRefExpr{
-										pos:  position{line: 254, col: 39, offset: 6619},
-										name: "AfterNumbers",
-									},
+									inverted:   false,
 								},
 							},
 						},
 					},
-					&seqExpr{
-						pos: position{line: 256, col: 5, offset: 6668},
+				},
+			},
+		},
+		{
+			name:        "JSONPointerSelector",
+			displayName: "\"JSON Pointer\"",
+			pos:         position{line: 281, col: 1, offset: 8894},
+			expr: &actionExpr{
+				pos: position{line: 281, col: 39, offset: 8932},
+				run: (*parser).callonJSONPointerSelector1,
+				expr: &oneOrMoreExpr{
+					pos: position{line: 281, col: 39, offset: 8932},
+					expr: &seqExpr{
+						pos: position{line: 281, col: 40, offset: 8933},
 						exprs: []interface{}{
 							&litMatcher{
-								pos:        position{line: 256, col: 5, offset: 6668},
-								val:        "undefined",
+								pos:        position{line: 281, col: 40, offset: 8933},
+								val:        "/",
 								ignoreCase: false,
-								want:       "\"undefined\"",
+								want:       "\"/\"",
 							},
-							&notExpr{
-								pos: position{line: 256, col: 17, offset: 6680},
-								expr: &ruleRefExpr{
-									pos:  position{line: 256, col: 18, offset: 6681},
-									name: "AfterNumbers",
+							&zeroOrMoreExpr{
+								pos: position{line: 281, col: 44, offset: 8937},
+								expr: &charClassMatcher{
+									pos:        position{line: 281, col: 44, offset: 8937},
+									val:        "[^ \\t\\r\\n/()\"=!<>]",
+									chars:      []rune{' ', '\t', '\r', '\n', '/', '(', ')', '"', '=', '!', '<', '>'},
+									ignoreCase: false,
+									inverted:   true,
 								},
 							},
-							&andCodeExpr{
-								pos: position{line: 256, col: 31, offset: 6694},
-								run: (*parser).callonUndefined11,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "JSONPathSelector",
+			pos:  position{line: 287, col: 1, offset: 9139},
+			expr: &actionExpr{
+				pos: position{line: 287, col: 21, offset: 9159},
+				run: (*parser).callonJSONPathSelector1,
+				expr: &seqExpr{
+					pos: position{line: 287, col: 21, offset: 9159},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 287, col: 21, offset: 9159},
+							val:        "$",
+							ignoreCase: false,
+							want:       "\"$\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 287, col: 25, offset: 9163},
+							label: "segs",
+							expr: &oneOrMoreExpr{
+								pos: position{line: 287, col: 30, offset: 9168},
+								expr: &ruleRefExpr{
+									pos:  position{line: 287, col: 30, offset: 9168},
+									name: "JSONPathSegment",
+								},
 							},
 						},
 					},
@@ -1919,75 +2305,133 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "TrueOrFalse",
-			displayName: "\"bool\"",
-			pos:         position{line: 260, col: 1, offset: 6757},
+			name:        "JSONPathSegment",
+			displayName: "\"JSONPath segment\"",
+			pos:         position{line: 297, col: 1, offset: 9363},
 			expr: &choiceExpr{
-				pos: position{line: 260, col: 23, offset: 6779},
+				pos: position{line: 297, col: 39, offset: 9401},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 260, col: 23, offset: 6779},
-						run: (*parser).callonTrueOrFalse2,
+						pos: position{line: 297, col: 39, offset: 9401},
+						run: (*parser).callonJSONPathSegment2,
 						expr: &seqExpr{
-							pos: position{line: 260, col: 23, offset: 6779},
+							pos: position{line: 297, col: 39, offset: 9401},
 							exprs: []interface{}{
-								&choiceExpr{
-									pos: position{line: 260, col: 24, offset: 6780},
-									alternatives: []interface{}{
-										&litMatcher{
-											pos:        position{line: 260, col: 24, offset: 6780},
-											val:        "true",
-											ignoreCase: false,
-											want:       "\"true\"",
-										},
-										&litMatcher{
-											pos:        position{line: 260, col: 33, offset: 6789},
-											val:        "false",
-											ignoreCase: false,
-											want:       "\"false\"",
-										},
-									},
+								&litMatcher{
+									pos:        position{line: 297, col: 39, offset: 9401},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
 								},
-								&andExpr{
-									pos: position{line: 260, col: 42, offset: 6798},
+								&labeledExpr{
+									pos:   position{line: 297, col: 43, offset: 9405},
+									label: "name",
 									expr: &ruleRefExpr{
-										pos:  position{line: 260, col: 43, offset: 6799},
-										name: "AfterNumbers",
+										pos:  position{line: 297, col: 48, offset: 9410},
+										name: "JSONPathName",
 									},
 								},
 							},
 						},
 					},
-					&seqExpr{
-						pos: position{line: 262, col: 5, offset: 6848},
-						exprs: []interface{}{
-							&choiceExpr{
-								pos: position{line: 262, col: 6, offset: 6849},
-								alternatives: []interface{}{
-									&litMatcher{
-										pos:        position{line: 262, col: 6, offset: 6849},
-										val:        "true",
-										ignoreCase: false,
-										want:       "\"true\"",
+					&actionExpr{
+						pos: position{line: 299, col: 5, offset: 9449},
+						run: (*parser).callonJSONPathSegment7,
+						expr: &seqExpr{
+							pos: position{line: 299, col: 5, offset: 9449},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 299, col: 5, offset: 9449},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 299, col: 9, offset: 9453},
+									expr: &ruleRefExpr{
+										pos:  position{line: 299, col: 9, offset: 9453},
+										name: "_",
 									},
-									&litMatcher{
-										pos:        position{line: 262, col: 15, offset: 6858},
-										val:        "false",
-										ignoreCase: false,
-										want:       "\"false\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 299, col: 12, offset: 9456},
+									label: "idx",
+									expr: &ruleRefExpr{
+										pos:  position{line: 299, col: 16, offset: 9460},
+										name: "JSONPathIndex",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 299, col: 30, offset: 9474},
+									expr: &ruleRefExpr{
+										pos:  position{line: 299, col: 30, offset: 9474},
+										name: "_",
 									},
 								},
+								&litMatcher{
+									pos:        position{line: 299, col: 33, offset: 9477},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
 							},
-							&notExpr{
-								pos: position{line: 262, col: 24, offset: 6867},
-								expr: &ruleRefExpr{
-									pos:  position{line: 262, col: 25, offset: 6868},
-									name: "AfterNumbers",
+						},
+					},
+					&actionExpr{
+						pos: position{line: 301, col: 5, offset: 9506},
+						run: (*parser).callonJSONPathSegment17,
+						expr: &seqExpr{
+							pos: position{line: 301, col: 5, offset: 9506},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 301, col: 5, offset: 9506},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 301, col: 9, offset: 9510},
+									expr: &ruleRefExpr{
+										pos:  position{line: 301, col: 9, offset: 9510},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 301, col: 12, offset: 9513},
+									label: "name",
+									expr: &ruleRefExpr{
+										pos:  position{line: 301, col: 17, offset: 9518},
+										name: "JSONPathString",
+									},
 								},
+								&zeroOrOneExpr{
+									pos: position{line: 301, col: 32, offset: 9533},
+									expr: &ruleRefExpr{
+										pos:  position{line: 301, col: 32, offset: 9533},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 301, col: 35, offset: 9536},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 303, col: 5, offset: 9566},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 303, col: 5, offset: 9566},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
 							},
 							&andCodeExpr{
-								pos: position{line: 262, col: 38, offset: 6881},
-								run: (*parser).callonTrueOrFalse15,
+								pos: position{line: 303, col: 9, offset: 9570},
+								run: (*parser).callonJSONPathSegment29,
 							},
 						},
 					},
@@ -1995,95 +2439,66 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "AfterNumbers",
-			pos:  position{line: 266, col: 1, offset: 6939},
-			expr: &andExpr{
-				pos: position{line: 266, col: 17, offset: 6955},
-				expr: &choiceExpr{
-					pos: position{line: 266, col: 19, offset: 6957},
-					alternatives: []interface{}{
-						&ruleRefExpr{
-							pos:  position{line: 266, col: 19, offset: 6957},
-							name: "_",
-						},
-						&ruleRefExpr{
-							pos:  position{line: 266, col: 23, offset: 6961},
-							name: "EOF",
-						},
-						&litMatcher{
-							pos:        position{line: 266, col: 29, offset: 6967},
-							val:        ")",
+			name: "JSONPathName",
+			pos:  position{line: 307, col: 1, offset: 9632},
+			expr: &actionExpr{
+				pos: position{line: 307, col: 17, offset: 9648},
+				run: (*parser).callonJSONPathName1,
+				expr: &seqExpr{
+					pos: position{line: 307, col: 17, offset: 9648},
+					exprs: []interface{}{
+						&charClassMatcher{
+							pos:        position{line: 307, col: 17, offset: 9648},
+							val:        "[\\pL_]",
+							chars:      []rune{'_'},
+							classes:    []*unicode.RangeTable{rangeTable("L")},
 							ignoreCase: false,
-							want:       "\")\"",
+							inverted:   false,
+						},
+						&zeroOrMoreExpr{
+							pos: position{line: 307, col: 24, offset: 9655},
+							expr: &charClassMatcher{
+								pos:        position{line: 307, col: 24, offset: 9655},
+								val:        "[\\pL\\pN_]",
+								chars:      []rune{'_'},
+								classes:    []*unicode.RangeTable{rangeTable("L"), rangeTable("N")},
+								ignoreCase: false,
+								inverted:   false,
+							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "Float",
-			pos:  position{line: 268, col: 1, offset: 6973},
+			name: "JSONPathIndex",
+			pos:  position{line: 311, col: 1, offset: 9701},
 			expr: &actionExpr{
-				pos: position{line: 268, col: 10, offset: 6982},
-				run: (*parser).callonFloat1,
-				expr: &seqExpr{
-					pos: position{line: 268, col: 10, offset: 6982},
-					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 268, col: 10, offset: 6982},
-							expr: &litMatcher{
-								pos:        position{line: 268, col: 10, offset: 6982},
-								val:        "-",
-								ignoreCase: false,
-								want:       "\"-\"",
-							},
-						},
-						&choiceExpr{
-							pos: position{line: 268, col: 16, offset: 6988},
-							alternatives: []interface{}{
-								&litMatcher{
-									pos:        position{line: 268, col: 16, offset: 6988},
-									val:        "0",
-									ignoreCase: false,
-									want:       "\"0\"",
-								},
-								&seqExpr{
-									pos: position{line: 268, col: 22, offset: 6994},
-									exprs: []interface{}{
-										&charClassMatcher{
-											pos:        position{line: 268, col: 22, offset: 6994},
-											val:        "[1-9]",
-											ranges:     []rune{'1', '9'},
-											ignoreCase: false,
-											inverted:   false,
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 268, col: 27, offset: 6999},
-											expr: &charClassMatcher{
-												pos:        position{line: 268, col: 27, offset: 6999},
-												val:        "[0-9]",
-												ranges:     []rune{'0', '9'},
-												ignoreCase: false,
-												inverted:   false,
-											},
-										},
-									},
-								},
-							},
+				pos: position{line: 311, col: 18, offset: 9718},
+				run: (*parser).callonJSONPathIndex1,
+				expr: &choiceExpr{
+					pos: position{line: 311, col: 19, offset: 9719},
+					alternatives: []interface{}{
+						&litMatcher{
+							pos:        position{line: 311, col: 19, offset: 9719},
+							val:        "0",
+							ignoreCase: false,
+							want:       "\"0\"",
 						},
 						&seqExpr{
-							pos: position{line: 268, col: 36, offset: 7008},
+							pos: position{line: 311, col: 25, offset: 9725},
 							exprs: []interface{}{
-								&litMatcher{
-									pos:        position{line: 268, col: 36, offset: 7008},
-									val:        ".",
+								&charClassMatcher{
+									pos:        position{line: 311, col: 25, offset: 9725},
+									val:        "[1-9]",
+									ranges:     []rune{'1', '9'},
 									ignoreCase: false,
-									want:       "\".\"",
+									inverted:   false,
 								},
-								&oneOrMoreExpr{
-									pos: position{line: 268, col: 40, offset: 7012},
+								&zeroOrMoreExpr{
+									pos: position{line: 311, col: 30, offset: 9730},
 									expr: &charClassMatcher{
-										pos:        position{line: 268, col: 40, offset: 7012},
+										pos:        position{line: 311, col: 30, offset: 9730},
 										val:        "[0-9]",
 										ranges:     []rune{'0', '9'},
 										ignoreCase: false,
@@ -2097,54 +2512,70 @@ var g = &grammar{
 			},
 		},
 		{
-			name: "Integer",
-			pos:  position{line: 272, col: 1, offset: 7055},
-			expr: &actionExpr{
-				pos: position{line: 272, col: 12, offset: 7066},
-				run: (*parser).callonInteger1,
-				expr: &seqExpr{
-					pos: position{line: 272, col: 12, offset: 7066},
-					exprs: []interface{}{
-						&zeroOrOneExpr{
-							pos: position{line: 272, col: 12, offset: 7066},
-							expr: &litMatcher{
-								pos:        position{line: 272, col: 12, offset: 7066},
-								val:        "-",
-								ignoreCase: false,
-								want:       "\"-\"",
-							},
-						},
-						&choiceExpr{
-							pos: position{line: 272, col: 18, offset: 7072},
-							alternatives: []interface{}{
+			name: "JSONPathString",
+			pos:  position{line: 315, col: 1, offset: 9773},
+			expr: &choiceExpr{
+				pos: position{line: 315, col: 19, offset: 9791},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 315, col: 19, offset: 9791},
+						run: (*parser).callonJSONPathString2,
+						expr: &seqExpr{
+							pos: position{line: 315, col: 19, offset: 9791},
+							exprs: []interface{}{
 								&litMatcher{
-									pos:        position{line: 272, col: 18, offset: 7072},
-									val:        "0",
+									pos:        position{line: 315, col: 19, offset: 9791},
+									val:        "'",
 									ignoreCase: false,
-									want:       "\"0\"",
+									want:       "\"'\"",
 								},
-								&seqExpr{
-									pos: position{line: 272, col: 24, offset: 7078},
-									exprs: []interface{}{
-										&charClassMatcher{
-											pos:        position{line: 272, col: 24, offset: 7078},
-											val:        "[1-9]",
-											ranges:     []rune{'1', '9'},
-											ignoreCase: false,
-											inverted:   false,
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 272, col: 29, offset: 7083},
-											expr: &charClassMatcher{
-												pos:        position{line: 272, col: 29, offset: 7083},
-												val:        "[0-9]",
-												ranges:     []rune{'0', '9'},
+								&zeroOrMoreExpr{
+									pos: position{line: 315, col: 23, offset: 9795},
+									expr: &choiceExpr{
+										pos: position{line: 315, col: 24, offset: 9796},
+										alternatives: []interface{}{
+											&seqExpr{
+												pos: position{line: 315, col: 24, offset: 9796},
+												exprs: []interface{}{
+													&litMatcher{
+														pos:        position{line: 315, col: 24, offset: 9796},
+														val:        "\\",
+														ignoreCase: false,
+														want:       "\"\\\\\"",
+													},
+													&anyMatcher{
+														line: 315, col: 29, offset: 9801,
+													},
+												},
+											},
+											&charClassMatcher{
+												pos:        position{line: 315, col: 33, offset: 9805},
+												val:        "[^'\\\\]",
+												chars:      []rune{'\'', '\\'},
 												ignoreCase: false,
-												inverted:   false,
+												inverted:   true,
 											},
 										},
 									},
 								},
+								&litMatcher{
+									pos:        position{line: 315, col: 42, offset: 9814},
+									val:        "'",
+									ignoreCase: false,
+									want:       "\"'\"",
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 321, col: 5, offset: 9959},
+						run: (*parser).callonJSONPathString12,
+						expr: &labeledExpr{
+							pos:   position{line: 321, col: 5, offset: 9959},
+							label: "lit",
+							expr: &ruleRefExpr{
+								pos:  position{line: 321, col: 9, offset: 9963},
+								name: "StringLiteral",
 							},
 						},
 					},
@@ -2152,563 +2583,2325 @@ var g = &grammar{
 			},
 		},
 		{
-			name:        "StringLiteral",
-			displayName: "\"string\"",
-			pos:         position{line: 276, col: 1, offset: 7126},
+			name: "Identifier",
+			pos:  position{line: 325, col: 1, offset: 10001},
+			expr: &actionExpr{
+				pos: position{line: 325, col: 15, offset: 10015},
+				run: (*parser).callonIdentifier1,
+				expr: &seqExpr{
+					pos: position{line: 325, col: 15, offset: 10015},
+					exprs: []interface{}{
+						&charClassMatcher{
+							pos:        position{line: 325, col: 15, offset: 10015},
+							val:        "[a-zA-Z]",
+							ranges:     []rune{'a', 'z', 'A', 'Z'},
+							ignoreCase: false,
+							inverted:   false,
+						},
+						&zeroOrMoreExpr{
+							pos: position{line: 325, col: 24, offset: 10024},
+							expr: &charClassMatcher{
+								pos:        position{line: 325, col: 24, offset: 10024},
+								val:        "[a-zA-Z0-9_/]",
+								chars:      []rune{'_', '/'},
+								ranges:     []rune{'a', 'z', 'A', 'Z', '0', '9'},
+								ignoreCase: false,
+								inverted:   false,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Param",
+			displayName: "\"parameter\"",
+			pos:         position{line: 329, col: 1, offset: 10074},
+			expr: &actionExpr{
+				pos: position{line: 329, col: 22, offset: 10095},
+				run: (*parser).callonParam1,
+				expr: &seqExpr{
+					pos: position{line: 329, col: 22, offset: 10095},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 329, col: 22, offset: 10095},
+							val:        "$",
+							ignoreCase: false,
+							want:       "\"$\"",
+						},
+						&labeledExpr{
+							pos:   position{line: 329, col: 26, offset: 10099},
+							label: "ident",
+							expr: &ruleRefExpr{
+								pos:  position{line: 329, col: 32, offset: 10105},
+								name: "Identifier",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "SelectorOrIndex",
+			pos:  position{line: 333, col: 1, offset: 10142},
 			expr: &choiceExpr{
-				pos: position{line: 276, col: 27, offset: 7152},
+				pos: position{line: 333, col: 20, offset: 10161},
 				alternatives: []interface{}{
 					&actionExpr{
-						pos: position{line: 276, col: 27, offset: 7152},
-						run: (*parser).callonStringLiteral2,
-						expr: &choiceExpr{
-							pos: position{line: 276, col: 28, offset: 7153},
-							alternatives: []interface{}{
-								&seqExpr{
-									pos: position{line: 276, col: 28, offset: 7153},
-									exprs: []interface{}{
-										&litMatcher{
-											pos:        position{line: 276, col: 28, offset: 7153},
-											val:        "`",
-											ignoreCase: false,
-											want:       "\"`\"",
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 276, col: 32, offset: 7157},
-											expr: &ruleRefExpr{
-												pos:  position{line: 276, col: 32, offset: 7157},
-												name: "RawStringChar",
-											},
-										},
-										&litMatcher{
-											pos:        position{line: 276, col: 47, offset: 7172},
-											val:        "`",
-											ignoreCase: false,
-											want:       "\"`\"",
-										},
-									},
+						pos: position{line: 333, col: 20, offset: 10161},
+						run: (*parser).callonSelectorOrIndex2,
+						expr: &seqExpr{
+							pos: position{line: 333, col: 20, offset: 10161},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 333, col: 20, offset: 10161},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
 								},
-								&seqExpr{
-									pos: position{line: 276, col: 53, offset: 7178},
-									exprs: []interface{}{
-										&litMatcher{
-											pos:        position{line: 276, col: 53, offset: 7178},
-											val:        "\"",
-											ignoreCase: false,
-											want:       "\"\\\"\"",
-										},
-										&zeroOrMoreExpr{
-											pos: position{line: 276, col: 57, offset: 7182},
-											expr: &ruleRefExpr{
-												pos:  position{line: 276, col: 57, offset: 7182},
-												name: "DoubleStringChar",
-											},
-										},
-										&litMatcher{
-											pos:        position{line: 276, col: 75, offset: 7200},
-											val:        "\"",
-											ignoreCase: false,
-											want:       "\"\\\"\"",
-										},
+								&labeledExpr{
+									pos:   position{line: 333, col: 24, offset: 10165},
+									label: "ident",
+									expr: &ruleRefExpr{
+										pos:  position{line: 333, col: 30, offset: 10171},
+										name: "Identifier",
 									},
 								},
 							},
 						},
 					},
-					&seqExpr{
-						pos: position{line: 278, col: 5, offset: 7252},
-						exprs: []interface{}{
-							&choiceExpr{
-								pos: position{line: 278, col: 6, offset: 7253},
-								alternatives: []interface{}{
-									&seqExpr{
-										pos: position{line: 278, col: 6, offset: 7253},
-										exprs: []interface{}{
-											&litMatcher{
-												pos:        position{line: 278, col: 6, offset: 7253},
-												val:        "`",
-												ignoreCase: false,
-												want:       "\"`\"",
-											},
-											&zeroOrMoreExpr{
-												pos: position{line: 278, col: 10, offset: 7257},
-												expr: &ruleRefExpr{
-													pos:  position{line: 278, col: 10, offset: 7257},
-													name: "RawStringChar",
-												},
-											},
-										},
-									},
-									&seqExpr{
-										pos: position{line: 278, col: 27, offset: 7274},
-										exprs: []interface{}{
-											&litMatcher{
-												pos:        position{line: 278, col: 27, offset: 7274},
-												val:        "\"",
-												ignoreCase: false,
-												want:       "\"\\\"\"",
-											},
-											&zeroOrMoreExpr{
-												pos: position{line: 278, col: 31, offset: 7278},
-												expr: &ruleRefExpr{
-													pos:  position{line: 278, col: 31, offset: 7278},
-													name: "DoubleStringChar",
-												},
-											},
+					&actionExpr{
+						pos: position{line: 335, col: 5, offset: 10209},
+						run: (*parser).callonSelectorOrIndex7,
+						expr: &labeledExpr{
+							pos:   position{line: 335, col: 5, offset: 10209},
+							label: "r",
+							expr: &ruleRefExpr{
+								pos:  position{line: 335, col: 7, offset: 10211},
+								name: "RangeExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 337, col: 5, offset: 10250},
+						run: (*parser).callonSelectorOrIndex10,
+						expr: &labeledExpr{
+							pos:   position{line: 337, col: 5, offset: 10250},
+							label: "expr",
+							expr: &ruleRefExpr{
+								pos:  position{line: 337, col: 10, offset: 10255},
+								name: "IndexExpression",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 339, col: 5, offset: 10297},
+						run: (*parser).callonSelectorOrIndex13,
+						expr: &seqExpr{
+							pos: position{line: 339, col: 5, offset: 10297},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 339, col: 5, offset: 10297},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&labeledExpr{
+									pos:   position{line: 339, col: 9, offset: 10301},
+									label: "idx",
+									expr: &oneOrMoreExpr{
+										pos: position{line: 339, col: 13, offset: 10305},
+										expr: &charClassMatcher{
+											pos:        position{line: 339, col: 13, offset: 10305},
+											val:        "[0-9]",
+											ranges:     []rune{'0', '9'},
+											ignoreCase: false,
+											inverted:   false,
 										},
 									},
 								},
 							},
-							&ruleRefExpr{
-								pos:  position{line: 278, col: 50, offset: 7297},
-								name: "EOF",
-							},
-							&andCodeExpr{
-								pos: position{line: 278, col: 54, offset: 7301},
-								run: (*parser).callonStringLiteral25,
-							},
 						},
 					},
 				},
 			},
 		},
 		{
-			name: "RawStringChar",
-			pos:  position{line: 282, col: 1, offset: 7365},
-			expr: &seqExpr{
-				pos: position{line: 282, col: 18, offset: 7382},
-				exprs: []interface{}{
-					&notExpr{
-						pos: position{line: 282, col: 18, offset: 7382},
-						expr: &litMatcher{
-							pos:        position{line: 282, col: 19, offset: 7383},
-							val:        "`",
+			name:        "RangeExpression",
+			displayName: "\"range\"",
+			pos:         position{line: 345, col: 1, offset: 10453},
+			expr: &actionExpr{
+				pos: position{line: 345, col: 28, offset: 10480},
+				run: (*parser).callonRangeExpression1,
+				expr: &seqExpr{
+					pos: position{line: 345, col: 28, offset: 10480},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 345, col: 28, offset: 10480},
+							val:        "[",
 							ignoreCase: false,
-							want:       "\"`\"",
+							want:       "\"[\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 345, col: 32, offset: 10484},
+							expr: &ruleRefExpr{
+								pos:  position{line: 345, col: 32, offset: 10484},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 345, col: 35, offset: 10487},
+							label: "low",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 345, col: 39, offset: 10491},
+								expr: &ruleRefExpr{
+									pos:  position{line: 345, col: 39, offset: 10491},
+									name: "RangeBound",
+								},
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 345, col: 51, offset: 10503},
+							expr: &ruleRefExpr{
+								pos:  position{line: 345, col: 51, offset: 10503},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 345, col: 54, offset: 10506},
+							val:        ":",
+							ignoreCase: false,
+							want:       "\":\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 345, col: 58, offset: 10510},
+							expr: &ruleRefExpr{
+								pos:  position{line: 345, col: 58, offset: 10510},
+								name: "_",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 345, col: 61, offset: 10513},
+							label: "high",
+							expr: &zeroOrOneExpr{
+								pos: position{line: 345, col: 66, offset: 10518},
+								expr: &ruleRefExpr{
+									pos:  position{line: 345, col: 66, offset: 10518},
+									name: "RangeBound",
+								},
+							},
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 345, col: 78, offset: 10530},
+							expr: &ruleRefExpr{
+								pos:  position{line: 345, col: 78, offset: 10530},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 345, col: 81, offset: 10533},
+							val:        "]",
+							ignoreCase: false,
+							want:       "\"]\"",
 						},
 					},
-					&anyMatcher{
-						line: 282, col: 23, offset: 7387,
+				},
+			},
+		},
+		{
+			name: "RangeBound",
+			pos:  position{line: 349, col: 1, offset: 10585},
+			expr: &actionExpr{
+				pos: position{line: 349, col: 15, offset: 10599},
+				run: (*parser).callonRangeBound1,
+				expr: &labeledExpr{
+					pos:   position{line: 349, col: 15, offset: 10599},
+					label: "n",
+					expr: &ruleRefExpr{
+						pos:  position{line: 349, col: 17, offset: 10601},
+						name: "Integer",
 					},
 				},
 			},
 		},
 		{
-			name: "DoubleStringChar",
-			pos:  position{line: 283, col: 1, offset: 7389},
-			expr: &seqExpr{
-				pos: position{line: 283, col: 21, offset: 7409},
-				exprs: []interface{}{
-					&notExpr{
-						pos: position{line: 283, col: 21, offset: 7409},
-						expr: &litMatcher{
-							pos:        position{line: 283, col: 22, offset: 7410},
-							val:        "\"",
-							ignoreCase: false,
-							want:       "\"\\\"\"",
+			name:        "IndexExpression",
+			displayName: "\"index\"",
+			pos:         position{line: 353, col: 1, offset: 10649},
+			expr: &choiceExpr{
+				pos: position{line: 353, col: 28, offset: 10676},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 353, col: 28, offset: 10676},
+						run: (*parser).callonIndexExpression2,
+						expr: &seqExpr{
+							pos: position{line: 353, col: 28, offset: 10676},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 353, col: 28, offset: 10676},
+									val:        "[",
+									ignoreCase: false,
+									want:       "\"[\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 353, col: 32, offset: 10680},
+									expr: &ruleRefExpr{
+										pos:  position{line: 353, col: 32, offset: 10680},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 353, col: 35, offset: 10683},
+									label: "lit",
+									expr: &ruleRefExpr{
+										pos:  position{line: 353, col: 39, offset: 10687},
+										name: "StringLiteral",
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 353, col: 53, offset: 10701},
+									expr: &ruleRefExpr{
+										pos:  position{line: 353, col: 53, offset: 10701},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 353, col: 56, offset: 10704},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
 						},
 					},
-					&anyMatcher{
-						line: 283, col: 26, offset: 7414,
+					&seqExpr{
+						pos: position{line: 355, col: 5, offset: 10733},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 355, col: 5, offset: 10733},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 355, col: 9, offset: 10737},
+								expr: &ruleRefExpr{
+									pos:  position{line: 355, col: 9, offset: 10737},
+									name: "_",
+								},
+							},
+							&notExpr{
+								pos: position{line: 355, col: 12, offset: 10740},
+								expr: &ruleRefExpr{
+									pos:  position{line: 355, col: 13, offset: 10741},
+									name: "StringLiteral",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 355, col: 27, offset: 10755},
+								run: (*parser).callonIndexExpression18,
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 357, col: 5, offset: 10807},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 357, col: 5, offset: 10807},
+								val:        "[",
+								ignoreCase: false,
+								want:       "\"[\"",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 357, col: 9, offset: 10811},
+								expr: &ruleRefExpr{
+									pos:  position{line: 357, col: 9, offset: 10811},
+									name: "_",
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 357, col: 12, offset: 10814},
+								name: "StringLiteral",
+							},
+							&zeroOrOneExpr{
+								pos: position{line: 357, col: 26, offset: 10828},
+								expr: &ruleRefExpr{
+									pos:  position{line: 357, col: 26, offset: 10828},
+									name: "_",
+								},
+							},
+							&notExpr{
+								pos: position{line: 357, col: 29, offset: 10831},
+								expr: &litMatcher{
+									pos:        position{line: 357, col: 30, offset: 10832},
+									val:        "]",
+									ignoreCase: false,
+									want:       "\"]\"",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 357, col: 34, offset: 10836},
+								run: (*parser).callonIndexExpression28,
+							},
+						},
 					},
 				},
 			},
 		},
 		{
-			name:        "_",
-			displayName: "\"whitespace\"",
-			pos:         position{line: 285, col: 1, offset: 7417},
-			expr: &oneOrMoreExpr{
-				pos: position{line: 285, col: 19, offset: 7435},
-				expr: &charClassMatcher{
-					pos:        position{line: 285, col: 19, offset: 7435},
-					val:        "[ \\t\\r\\n]",
-					chars:      []rune{' ', '\t', '\r', '\n'},
-					ignoreCase: false,
-					inverted:   false,
+			name: "ExpressionValue",
+			pos:  position{line: 361, col: 1, offset: 10899},
+			expr: &choiceExpr{
+				pos: position{line: 361, col: 20, offset: 10918},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 361, col: 20, offset: 10918},
+						run: (*parser).callonExpressionValue2,
+						expr: &seqExpr{
+							pos: position{line: 361, col: 20, offset: 10918},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 361, col: 20, offset: 10918},
+									label: "left",
+									expr: &ruleRefExpr{
+										pos:  position{line: 361, col: 25, offset: 10923},
+										name: "Value",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 361, col: 31, offset: 10929},
+									label: "operator",
+									expr: &choiceExpr{
+										pos: position{line: 361, col: 41, offset: 10939},
+										alternatives: []interface{}{
+											&ruleRefExpr{
+												pos:  position{line: 361, col: 41, offset: 10939},
+												name: "MathOpPlus",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 361, col: 54, offset: 10952},
+												name: "MathOpMinus",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 361, col: 68, offset: 10966},
+												name: "MathOpMul",
+											},
+											&ruleRefExpr{
+												pos:  position{line: 361, col: 80, offset: 10978},
+												name: "MathOpDiv",
+											},
+										},
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 361, col: 91, offset: 10989},
+									label: "right",
+									expr: &ruleRefExpr{
+										pos:  position{line: 361, col: 97, offset: 10995},
+										name: "Value",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 367, col: 5, offset: 11124},
+						run: (*parser).callonExpressionValue14,
+						expr: &labeledExpr{
+							pos:   position{line: 367, col: 5, offset: 11124},
+							label: "value",
+							expr: &ruleRefExpr{
+								pos:  position{line: 367, col: 11, offset: 11130},
+								name: "Value",
+							},
+						},
+					},
 				},
 			},
 		},
 		{
-			name: "EOF",
-			pos:  position{line: 287, col: 1, offset: 7447},
-			expr: &notExpr{
-				pos: position{line: 287, col: 8, offset: 7454},
-				expr: &anyMatcher{
-					line: 287, col: 9, offset: 7455,
+			name: "MathOpPlus",
+			pos:  position{line: 375, col: 1, offset: 11245},
+			expr: &actionExpr{
+				pos: position{line: 375, col: 15, offset: 11259},
+				run: (*parser).callonMathOpPlus1,
+				expr: &seqExpr{
+					pos: position{line: 375, col: 15, offset: 11259},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 375, col: 15, offset: 11259},
+							expr: &ruleRefExpr{
+								pos:  position{line: 375, col: 15, offset: 11259},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 375, col: 18, offset: 11262},
+							val:        "+",
+							ignoreCase: false,
+							want:       "\"+\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 375, col: 22, offset: 11266},
+							expr: &ruleRefExpr{
+								pos:  position{line: 375, col: 22, offset: 11266},
+								name: "_",
+							},
+						},
+					},
 				},
 			},
 		},
-	},
+		{
+			name: "MathOpMinus",
+			pos:  position{line: 379, col: 1, offset: 11300},
+			expr: &actionExpr{
+				pos: position{line: 379, col: 16, offset: 11315},
+				run: (*parser).callonMathOpMinus1,
+				expr: &seqExpr{
+					pos: position{line: 379, col: 16, offset: 11315},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 379, col: 16, offset: 11315},
+							expr: &ruleRefExpr{
+								pos:  position{line: 379, col: 16, offset: 11315},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 379, col: 19, offset: 11318},
+							val:        "-",
+							ignoreCase: false,
+							want:       "\"-\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 379, col: 23, offset: 11322},
+							expr: &ruleRefExpr{
+								pos:  position{line: 379, col: 23, offset: 11322},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MathOpMul",
+			pos:  position{line: 383, col: 1, offset: 11357},
+			expr: &actionExpr{
+				pos: position{line: 383, col: 14, offset: 11370},
+				run: (*parser).callonMathOpMul1,
+				expr: &seqExpr{
+					pos: position{line: 383, col: 14, offset: 11370},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 383, col: 14, offset: 11370},
+							expr: &ruleRefExpr{
+								pos:  position{line: 383, col: 14, offset: 11370},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 383, col: 17, offset: 11373},
+							val:        "*",
+							ignoreCase: false,
+							want:       "\"*\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 383, col: 21, offset: 11377},
+							expr: &ruleRefExpr{
+								pos:  position{line: 383, col: 21, offset: 11377},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "MathOpDiv",
+			pos:  position{line: 387, col: 1, offset: 11410},
+			expr: &actionExpr{
+				pos: position{line: 387, col: 14, offset: 11423},
+				run: (*parser).callonMathOpDiv1,
+				expr: &seqExpr{
+					pos: position{line: 387, col: 14, offset: 11423},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 387, col: 14, offset: 11423},
+							expr: &ruleRefExpr{
+								pos:  position{line: 387, col: 14, offset: 11423},
+								name: "_",
+							},
+						},
+						&litMatcher{
+							pos:        position{line: 387, col: 17, offset: 11426},
+							val:        "/",
+							ignoreCase: false,
+							want:       "\"/\"",
+						},
+						&zeroOrOneExpr{
+							pos: position{line: 387, col: 21, offset: 11430},
+							expr: &ruleRefExpr{
+								pos:  position{line: 387, col: 21, offset: 11430},
+								name: "_",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Value",
+			displayName: "\"value\"",
+			pos:         position{line: 391, col: 1, offset: 11463},
+			expr: &choiceExpr{
+				pos: position{line: 391, col: 18, offset: 11480},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 391, col: 18, offset: 11480},
+						run: (*parser).callonValue2,
+						expr: &labeledExpr{
+							pos:   position{line: 391, col: 18, offset: 11480},
+							label: "b",
+							expr: &ruleRefExpr{
+								pos:  position{line: 391, col: 20, offset: 11482},
+								name: "TrueOrFalse",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 393, col: 5, offset: 11565},
+						run: (*parser).callonValue5,
+						expr: &labeledExpr{
+							pos:   position{line: 393, col: 5, offset: 11565},
+							label: "u",
+							expr: &ruleRefExpr{
+								pos:  position{line: 393, col: 7, offset: 11567},
+								name: "Undefined",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 395, col: 5, offset: 11653},
+						run: (*parser).callonValue8,
+						expr: &labeledExpr{
+							pos:   position{line: 395, col: 5, offset: 11653},
+							label: "n",
+							expr: &ruleRefExpr{
+								pos:  position{line: 395, col: 7, offset: 11655},
+								name: "Null",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 397, col: 5, offset: 11731},
+						run: (*parser).callonValue11,
+						expr: &labeledExpr{
+							pos:   position{line: 397, col: 5, offset: 11731},
+							label: "p",
+							expr: &ruleRefExpr{
+								pos:  position{line: 397, col: 7, offset: 11733},
+								name: "Param",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 399, col: 5, offset: 11811},
+						run: (*parser).callonValue14,
+						expr: &labeledExpr{
+							pos:   position{line: 399, col: 5, offset: 11811},
+							label: "call",
+							expr: &ruleRefExpr{
+								pos:  position{line: 399, col: 10, offset: 11816},
+								name: "FunctionCall",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 401, col: 5, offset: 11855},
+						run: (*parser).callonValue17,
+						expr: &labeledExpr{
+							pos:   position{line: 401, col: 5, offset: 11855},
+							label: "selector",
+							expr: &ruleRefExpr{
+								pos:  position{line: 401, col: 14, offset: 11864},
+								name: "Selector",
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 403, col: 5, offset: 11999},
+						run: (*parser).callonValue20,
+						expr: &seqExpr{
+							pos: position{line: 403, col: 5, offset: 11999},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 403, col: 5, offset: 11999},
+									label: "n",
+									expr: &ruleRefExpr{
+										pos:  position{line: 403, col: 7, offset: 12001},
+										name: "Float",
+									},
+								},
+								&andExpr{
+									pos: position{line: 403, col: 13, offset: 12007},
+									expr: &ruleRefExpr{
+										pos:  position{line: 403, col: 14, offset: 12008},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 405, col: 5, offset: 12095},
+						run: (*parser).callonValue26,
+						expr: &seqExpr{
+							pos: position{line: 405, col: 5, offset: 12095},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 405, col: 5, offset: 12095},
+									label: "n",
+									expr: &ruleRefExpr{
+										pos:  position{line: 405, col: 7, offset: 12097},
+										name: "Integer",
+									},
+								},
+								&andExpr{
+									pos: position{line: 405, col: 15, offset: 12105},
+									expr: &ruleRefExpr{
+										pos:  position{line: 405, col: 16, offset: 12106},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 413, col: 5, offset: 12462},
+						run: (*parser).callonValue32,
+						expr: &seqExpr{
+							pos: position{line: 413, col: 5, offset: 12462},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 413, col: 5, offset: 12462},
+									label: "n",
+									expr: &ruleRefExpr{
+										pos:  position{line: 413, col: 7, offset: 12464},
+										name: "Float",
+									},
+								},
+								&notExpr{
+									pos: position{line: 413, col: 13, offset: 12470},
+									expr: &ruleRefExpr{
+										pos:  position{line: 413, col: 14, offset: 12471},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 415, col: 5, offset: 12544},
+						run: (*parser).callonValue38,
+						expr: &seqExpr{
+							pos: position{line: 415, col: 5, offset: 12544},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 415, col: 5, offset: 12544},
+									label: "n",
+									expr: &ruleRefExpr{
+										pos:  position{line: 415, col: 7, offset: 12546},
+										name: "Integer",
+									},
+								},
+								&notExpr{
+									pos: position{line: 415, col: 15, offset: 12554},
+									expr: &ruleRefExpr{
+										pos:  position{line: 415, col: 16, offset: 12555},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 417, col: 5, offset: 12628},
+						run: (*parser).callonValue44,
+						expr: &seqExpr{
+							pos: position{line: 417, col: 5, offset: 12628},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 417, col: 5, offset: 12628},
+									label: "n",
+									expr: &ruleRefExpr{
+										pos:  position{line: 417, col: 7, offset: 12630},
+										name: "TrueOrFalse",
+									},
+								},
+								&notExpr{
+									pos: position{line: 417, col: 19, offset: 12642},
+									expr: &ruleRefExpr{
+										pos:  position{line: 417, col: 20, offset: 12643},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&actionExpr{
+						pos: position{line: 419, col: 5, offset: 12714},
+						run: (*parser).callonValue50,
+						expr: &labeledExpr{
+							pos:   position{line: 419, col: 5, offset: 12714},
+							label: "s",
+							expr: &ruleRefExpr{
+								pos:  position{line: 419, col: 7, offset: 12716},
+								name: "StringLiteral",
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 421, col: 5, offset: 12803},
+						exprs: []interface{}{
+							&labeledExpr{
+								pos:   position{line: 421, col: 5, offset: 12803},
+								label: "w",
+								expr: &ruleRefExpr{
+									pos:  position{line: 421, col: 7, offset: 12805},
+									name: "Identifier",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 421, col: 18, offset: 12816},
+								run: (*parser).callonValue56,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "FunctionCall",
+			displayName: "\"call\"",
+			pos:         position{line: 427, col: 1, offset: 13007},
+			expr: &choiceExpr{
+				pos: position{line: 427, col: 24, offset: 13030},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 427, col: 24, offset: 13030},
+						run: (*parser).callonFunctionCall2,
+						expr: &seqExpr{
+							pos: position{line: 427, col: 24, offset: 13030},
+							exprs: []interface{}{
+								&labeledExpr{
+									pos:   position{line: 427, col: 24, offset: 13030},
+									label: "name",
+									expr: &ruleRefExpr{
+										pos:  position{line: 427, col: 29, offset: 13035},
+										name: "Identifier",
+									},
+								},
+								&andCodeExpr{
+									pos: position{line: 427, col: 40, offset: 13046},
+									run: (*parser).callonFunctionCall6,
+								},
+								&litMatcher{
+									pos:        position{line: 427, col: 83, offset: 13089},
+									val:        "(",
+									ignoreCase: false,
+									want:       "\"(\"",
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 427, col: 87, offset: 13093},
+									expr: &ruleRefExpr{
+										pos:  position{line: 427, col: 87, offset: 13093},
+										name: "_",
+									},
+								},
+								&labeledExpr{
+									pos:   position{line: 427, col: 90, offset: 13096},
+									label: "args",
+									expr: &zeroOrOneExpr{
+										pos: position{line: 427, col: 95, offset: 13101},
+										expr: &ruleRefExpr{
+											pos:  position{line: 427, col: 95, offset: 13101},
+											name: "FunctionArgs",
+										},
+									},
+								},
+								&zeroOrOneExpr{
+									pos: position{line: 427, col: 109, offset: 13115},
+									expr: &ruleRefExpr{
+										pos:  position{line: 427, col: 109, offset: 13115},
+										name: "_",
+									},
+								},
+								&litMatcher{
+									pos:        position{line: 427, col: 112, offset: 13118},
+									val:        ")",
+									ignoreCase: false,
+									want:       "\")\"",
+								},
+								&andCodeExpr{
+									pos: position{line: 427, col: 116, offset: 13122},
+									run: (*parser).callonFunctionCall16,
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 432, col: 5, offset: 13260},
+						exprs: []interface{}{
+							&labeledExpr{
+								pos:   position{line: 432, col: 5, offset: 13260},
+								label: "name",
+								expr: &ruleRefExpr{
+									pos:  position{line: 432, col: 10, offset: 13265},
+									name: "Identifier",
+								},
+							},
+							&andExpr{
+								pos: position{line: 432, col: 21, offset: 13276},
+								expr: &litMatcher{
+									pos:        position{line: 432, col: 22, offset: 13277},
+									val:        "(",
+									ignoreCase: false,
+									want:       "\"(\"",
+								},
+							},
+							&notCodeExpr{
+								pos: position{line: 432, col: 26, offset: 13281},
+								run: (*parser).callonFunctionCall22,
+							},
+							&andCodeExpr{
+								pos: position{line: 432, col: 69, offset: 13324},
+								run: (*parser).callonFunctionCall23,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "FunctionArgs",
+			pos:  position{line: 436, col: 1, offset: 13385},
+			expr: &actionExpr{
+				pos: position{line: 436, col: 17, offset: 13401},
+				run: (*parser).callonFunctionArgs1,
+				expr: &seqExpr{
+					pos: position{line: 436, col: 17, offset: 13401},
+					exprs: []interface{}{
+						&labeledExpr{
+							pos:   position{line: 436, col: 17, offset: 13401},
+							label: "first",
+							expr: &ruleRefExpr{
+								pos:  position{line: 436, col: 23, offset: 13407},
+								name: "ExpressionValue",
+							},
+						},
+						&labeledExpr{
+							pos:   position{line: 436, col: 39, offset: 13423},
+							label: "rest",
+							expr: &zeroOrMoreExpr{
+								pos: position{line: 436, col: 44, offset: 13428},
+								expr: &seqExpr{
+									pos: position{line: 436, col: 45, offset: 13429},
+									exprs: []interface{}{
+										&zeroOrOneExpr{
+											pos: position{line: 436, col: 45, offset: 13429},
+											expr: &ruleRefExpr{
+												pos:  position{line: 436, col: 45, offset: 13429},
+												name: "_",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 436, col: 48, offset: 13432},
+											val:        ",",
+											ignoreCase: false,
+											want:       "\",\"",
+										},
+										&zeroOrOneExpr{
+											pos: position{line: 436, col: 52, offset: 13436},
+											expr: &ruleRefExpr{
+												pos:  position{line: 436, col: 52, offset: 13436},
+												name: "_",
+											},
+										},
+										&ruleRefExpr{
+											pos:  position{line: 436, col: 55, offset: 13439},
+											name: "ExpressionValue",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Undefined",
+			displayName: "\"undefined\"",
+			pos:         position{line: 444, col: 1, offset: 13654},
+			expr: &choiceExpr{
+				pos: position{line: 444, col: 26, offset: 13679},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 444, col: 26, offset: 13679},
+						run: (*parser).callonUndefined2,
+						expr: &seqExpr{
+							pos: position{line: 444, col: 26, offset: 13679},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 444, col: 26, offset: 13679},
+									val:        "undefined",
+									ignoreCase: false,
+									want:       "\"undefined\"",
+								},
+								&andExpr{
+									pos: position{line: 444, col: 38, offset: 13691},
+									expr: &ruleRefExpr{
+										pos:  position{line: 444, col: 39, offset: 13692},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 446, col: 5, offset: 13741},
+						exprs: []interface{}{
+							&litMatcher{
+								pos:        position{line: 446, col: 5, offset: 13741},
+								val:        "undefined",
+								ignoreCase: false,
+								want:       "\"undefined\"",
+							},
+							&notExpr{
+								pos: position{line: 446, col: 17, offset: 13753},
+								expr: &ruleRefExpr{
+									pos:  position{line: 446, col: 18, offset: 13754},
+									name: "AfterNumbers",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 446, col: 31, offset: 13767},
+								run: (*parser).callonUndefined11,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Null",
+			displayName: "\"null\"",
+			pos:         position{line: 450, col: 1, offset: 13830},
+			expr: &actionExpr{
+				pos: position{line: 450, col: 16, offset: 13845},
+				run: (*parser).callonNull1,
+				expr: &seqExpr{
+					pos: position{line: 450, col: 16, offset: 13845},
+					exprs: []interface{}{
+						&litMatcher{
+							pos:        position{line: 450, col: 16, offset: 13845},
+							val:        "null",
+							ignoreCase: false,
+							want:       "\"null\"",
+						},
+						&andExpr{
+							pos: position{line: 450, col: 23, offset: 13852},
+							expr: &ruleRefExpr{
+								pos:  position{line: 450, col: 24, offset: 13853},
+								name: "AfterNumbers",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "TrueOrFalse",
+			displayName: "\"bool\"",
+			pos:         position{line: 454, col: 1, offset: 13901},
+			expr: &choiceExpr{
+				pos: position{line: 454, col: 23, offset: 13923},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 454, col: 23, offset: 13923},
+						run: (*parser).callonTrueOrFalse2,
+						expr: &seqExpr{
+							pos: position{line: 454, col: 23, offset: 13923},
+							exprs: []interface{}{
+								&choiceExpr{
+									pos: position{line: 454, col: 24, offset: 13924},
+									alternatives: []interface{}{
+										&litMatcher{
+											pos:        position{line: 454, col: 24, offset: 13924},
+											val:        "true",
+											ignoreCase: false,
+											want:       "\"true\"",
+										},
+										&litMatcher{
+											pos:        position{line: 454, col: 33, offset: 13933},
+											val:        "false",
+											ignoreCase: false,
+											want:       "\"false\"",
+										},
+									},
+								},
+								&andExpr{
+									pos: position{line: 454, col: 42, offset: 13942},
+									expr: &ruleRefExpr{
+										pos:  position{line: 454, col: 43, offset: 13943},
+										name: "AfterNumbers",
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 456, col: 5, offset: 13992},
+						exprs: []interface{}{
+							&choiceExpr{
+								pos: position{line: 456, col: 6, offset: 13993},
+								alternatives: []interface{}{
+									&litMatcher{
+										pos:        position{line: 456, col: 6, offset: 13993},
+										val:        "true",
+										ignoreCase: false,
+										want:       "\"true\"",
+									},
+									&litMatcher{
+										pos:        position{line: 456, col: 15, offset: 14002},
+										val:        "false",
+										ignoreCase: false,
+										want:       "\"false\"",
+									},
+								},
+							},
+							&notExpr{
+								pos: position{line: 456, col: 24, offset: 14011},
+								expr: &ruleRefExpr{
+									pos:  position{line: 456, col: 25, offset: 14012},
+									name: "AfterNumbers",
+								},
+							},
+							&andCodeExpr{
+								pos: position{line: 456, col: 38, offset: 14025},
+								run: (*parser).callonTrueOrFalse15,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "AfterNumbers",
+			pos:  position{line: 460, col: 1, offset: 14083},
+			expr: &andExpr{
+				pos: position{line: 460, col: 17, offset: 14099},
+				expr: &choiceExpr{
+					pos: position{line: 460, col: 19, offset: 14101},
+					alternatives: []interface{}{
+						&ruleRefExpr{
+							pos:  position{line: 460, col: 19, offset: 14101},
+							name: "_",
+						},
+						&ruleRefExpr{
+							pos:  position{line: 460, col: 23, offset: 14105},
+							name: "EOF",
+						},
+						&litMatcher{
+							pos:        position{line: 460, col: 29, offset: 14111},
+							val:        ")",
+							ignoreCase: false,
+							want:       "\")\"",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Float",
+			pos:  position{line: 462, col: 1, offset: 14117},
+			expr: &actionExpr{
+				pos: position{line: 462, col: 10, offset: 14126},
+				run: (*parser).callonFloat1,
+				expr: &seqExpr{
+					pos: position{line: 462, col: 10, offset: 14126},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 462, col: 10, offset: 14126},
+							expr: &litMatcher{
+								pos:        position{line: 462, col: 10, offset: 14126},
+								val:        "-",
+								ignoreCase: false,
+								want:       "\"-\"",
+							},
+						},
+						&choiceExpr{
+							pos: position{line: 462, col: 16, offset: 14132},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 462, col: 16, offset: 14132},
+									val:        "0",
+									ignoreCase: false,
+									want:       "\"0\"",
+								},
+								&seqExpr{
+									pos: position{line: 462, col: 22, offset: 14138},
+									exprs: []interface{}{
+										&charClassMatcher{
+											pos:        position{line: 462, col: 22, offset: 14138},
+											val:        "[1-9]",
+											ranges:     []rune{'1', '9'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 462, col: 27, offset: 14143},
+											expr: &charClassMatcher{
+												pos:        position{line: 462, col: 27, offset: 14143},
+												val:        "[0-9]",
+												ranges:     []rune{'0', '9'},
+												ignoreCase: false,
+												inverted:   false,
+											},
+										},
+									},
+								},
+							},
+						},
+						&seqExpr{
+							pos: position{line: 462, col: 36, offset: 14152},
+							exprs: []interface{}{
+								&litMatcher{
+									pos:        position{line: 462, col: 36, offset: 14152},
+									val:        ".",
+									ignoreCase: false,
+									want:       "\".\"",
+								},
+								&oneOrMoreExpr{
+									pos: position{line: 462, col: 40, offset: 14156},
+									expr: &charClassMatcher{
+										pos:        position{line: 462, col: 40, offset: 14156},
+										val:        "[0-9]",
+										ranges:     []rune{'0', '9'},
+										ignoreCase: false,
+										inverted:   false,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Integer",
+			pos:  position{line: 466, col: 1, offset: 14199},
+			expr: &actionExpr{
+				pos: position{line: 466, col: 12, offset: 14210},
+				run: (*parser).callonInteger1,
+				expr: &seqExpr{
+					pos: position{line: 466, col: 12, offset: 14210},
+					exprs: []interface{}{
+						&zeroOrOneExpr{
+							pos: position{line: 466, col: 12, offset: 14210},
+							expr: &litMatcher{
+								pos:        position{line: 466, col: 12, offset: 14210},
+								val:        "-",
+								ignoreCase: false,
+								want:       "\"-\"",
+							},
+						},
+						&choiceExpr{
+							pos: position{line: 466, col: 18, offset: 14216},
+							alternatives: []interface{}{
+								&litMatcher{
+									pos:        position{line: 466, col: 18, offset: 14216},
+									val:        "0",
+									ignoreCase: false,
+									want:       "\"0\"",
+								},
+								&seqExpr{
+									pos: position{line: 466, col: 24, offset: 14222},
+									exprs: []interface{}{
+										&charClassMatcher{
+											pos:        position{line: 466, col: 24, offset: 14222},
+											val:        "[1-9]",
+											ranges:     []rune{'1', '9'},
+											ignoreCase: false,
+											inverted:   false,
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 466, col: 29, offset: 14227},
+											expr: &charClassMatcher{
+												pos:        position{line: 466, col: 29, offset: 14227},
+												val:        "[0-9]",
+												ranges:     []rune{'0', '9'},
+												ignoreCase: false,
+												inverted:   false,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "StringLiteral",
+			displayName: "\"string\"",
+			pos:         position{line: 470, col: 1, offset: 14270},
+			expr: &choiceExpr{
+				pos: position{line: 470, col: 27, offset: 14296},
+				alternatives: []interface{}{
+					&actionExpr{
+						pos: position{line: 470, col: 27, offset: 14296},
+						run: (*parser).callonStringLiteral2,
+						expr: &choiceExpr{
+							pos: position{line: 470, col: 28, offset: 14297},
+							alternatives: []interface{}{
+								&seqExpr{
+									pos: position{line: 470, col: 28, offset: 14297},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 470, col: 28, offset: 14297},
+											val:        "`",
+											ignoreCase: false,
+											want:       "\"`\"",
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 470, col: 32, offset: 14301},
+											expr: &ruleRefExpr{
+												pos:  position{line: 470, col: 32, offset: 14301},
+												name: "RawStringChar",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 470, col: 47, offset: 14316},
+											val:        "`",
+											ignoreCase: false,
+											want:       "\"`\"",
+										},
+									},
+								},
+								&seqExpr{
+									pos: position{line: 470, col: 53, offset: 14322},
+									exprs: []interface{}{
+										&litMatcher{
+											pos:        position{line: 470, col: 53, offset: 14322},
+											val:        "\"",
+											ignoreCase: false,
+											want:       "\"\\\"\"",
+										},
+										&zeroOrMoreExpr{
+											pos: position{line: 470, col: 57, offset: 14326},
+											expr: &ruleRefExpr{
+												pos:  position{line: 470, col: 57, offset: 14326},
+												name: "DoubleStringChar",
+											},
+										},
+										&litMatcher{
+											pos:        position{line: 470, col: 75, offset: 14344},
+											val:        "\"",
+											ignoreCase: false,
+											want:       "\"\\\"\"",
+										},
+									},
+								},
+							},
+						},
+					},
+					&seqExpr{
+						pos: position{line: 476, col: 5, offset: 14478},
+						exprs: []interface{}{
+							&choiceExpr{
+								pos: position{line: 476, col: 6, offset: 14479},
+								alternatives: []interface{}{
+									&seqExpr{
+										pos: position{line: 476, col: 6, offset: 14479},
+										exprs: []interface{}{
+											&litMatcher{
+												pos:        position{line: 476, col: 6, offset: 14479},
+												val:        "`",
+												ignoreCase: false,
+												want:       "\"`\"",
+											},
+											&zeroOrMoreExpr{
+												pos: position{line: 476, col: 10, offset: 14483},
+												expr: &ruleRefExpr{
+													pos:  position{line: 476, col: 10, offset: 14483},
+													name: "RawStringChar",
+												},
+											},
+										},
+									},
+									&seqExpr{
+										pos: position{line: 476, col: 27, offset: 14500},
+										exprs: []interface{}{
+											&litMatcher{
+												pos:        position{line: 476, col: 27, offset: 14500},
+												val:        "\"",
+												ignoreCase: false,
+												want:       "\"\\\"\"",
+											},
+											&zeroOrMoreExpr{
+												pos: position{line: 476, col: 31, offset: 14504},
+												expr: &ruleRefExpr{
+													pos:  position{line: 476, col: 31, offset: 14504},
+													name: "DoubleStringChar",
+												},
+											},
+										},
+									},
+								},
+							},
+							&ruleRefExpr{
+								pos:  position{line: 476, col: 50, offset: 14523},
+								name: "EOF",
+							},
+							&andCodeExpr{
+								pos: position{line: 476, col: 54, offset: 14527},
+								run: (*parser).callonStringLiteral25,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "RawStringChar",
+			pos:  position{line: 480, col: 1, offset: 14591},
+			expr: &seqExpr{
+				pos: position{line: 480, col: 18, offset: 14608},
+				exprs: []interface{}{
+					&notExpr{
+						pos: position{line: 480, col: 18, offset: 14608},
+						expr: &litMatcher{
+							pos:        position{line: 480, col: 19, offset: 14609},
+							val:        "`",
+							ignoreCase: false,
+							want:       "\"`\"",
+						},
+					},
+					&anyMatcher{
+						line: 480, col: 23, offset: 14613,
+					},
+				},
+			},
+		},
+		{
+			name: "DoubleStringChar",
+			pos:  position{line: 481, col: 1, offset: 14615},
+			expr: &seqExpr{
+				pos: position{line: 481, col: 21, offset: 14635},
+				exprs: []interface{}{
+					&notExpr{
+						pos: position{line: 481, col: 21, offset: 14635},
+						expr: &litMatcher{
+							pos:        position{line: 481, col: 22, offset: 14636},
+							val:        "\"",
+							ignoreCase: false,
+							want:       "\"\\\"\"",
+						},
+					},
+					&anyMatcher{
+						line: 481, col: 26, offset: 14640,
+					},
+				},
+			},
+		},
+		{
+			name:        "_",
+			displayName: "\"whitespace\"",
+			pos:         position{line: 483, col: 1, offset: 14643},
+			expr: &oneOrMoreExpr{
+				pos: position{line: 483, col: 19, offset: 14661},
+				expr: &charClassMatcher{
+					pos:        position{line: 483, col: 19, offset: 14661},
+					val:        "[ \\t\\r\\n]",
+					chars:      []rune{' ', '\t', '\r', '\n'},
+					ignoreCase: false,
+					inverted:   false,
+				},
+			},
+		},
+		{
+			name: "EOF",
+			pos:  position{line: 485, col: 1, offset: 14673},
+			expr: &notExpr{
+				pos: position{line: 485, col: 8, offset: 14680},
+				expr: &anyMatcher{
+					line: 485, col: 9, offset: 14681,
+				},
+			},
+		},
+	},
+}
+
+func (c *current) onInput2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonInput2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput2(stack["expr"])
+}
+
+func (c *current) onInput17(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonInput17() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onInput17(stack["expr"])
+}
+
+func (c *current) onOrExpression2(left, right interface{}) (interface{}, error) {
+	return &BinaryExpression{
+		Operator: BinaryOpOr,
+		Left:     left.(Expression),
+		Right:    right.(Expression),
+	}, nil
+}
+
+func (p *parser) callonOrExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onOrExpression2(stack["left"], stack["right"])
+}
+
+func (c *current) onOrExpression11(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonOrExpression11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onOrExpression11(stack["expr"])
+}
+
+func (c *current) onAndExpression2(left, right interface{}) (interface{}, error) {
+	return &BinaryExpression{
+		Operator: BinaryOpAnd,
+		Left:     left.(Expression),
+		Right:    right.(Expression),
+	}, nil
+}
+
+func (p *parser) callonAndExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAndExpression2(stack["left"], stack["right"])
+}
+
+func (c *current) onAndExpression11(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonAndExpression11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onAndExpression11(stack["expr"])
+}
+
+func (c *current) onNotExpression2(expr interface{}) (interface{}, error) {
+	if unary, ok := expr.(*UnaryExpression); ok && unary.Operator == UnaryOpNot {
+		// small optimization to get rid unnecessary levels of AST nodes
+		// for things like:  not not foo == 3  which is equivalent to foo == 3
+		return unary.Operand, nil
+	}
+
+	return &UnaryExpression{
+		Operator: UnaryOpNot,
+		Operand:  expr.(Expression),
+	}, nil
+}
+
+func (p *parser) callonNotExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNotExpression2(stack["expr"])
+}
+
+func (c *current) onNotExpression8(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonNotExpression8() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNotExpression8(stack["expr"])
+}
+
+func (c *current) onNotExpression11(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonNotExpression11() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNotExpression11(stack["expr"])
+}
+
+func (c *current) onLetExpression1(name, value, body interface{}) (interface{}, error) {
+	return &LetExpression{
+		Name:     name.(string),
+		Value:    value.(*ExpressionValue),
+		Body:     body.(Expression),
+		Position: nodePosition(c),
+	}, nil
+}
+
+func (p *parser) callonLetExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onLetExpression1(stack["name"], stack["value"], stack["body"])
+}
+
+func (c *current) onParenthesizedExpression2(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression2(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression12(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression12(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression22(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression22() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression22(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression25(expr interface{}) (interface{}, error) {
+	return expr, nil
+}
+
+func (p *parser) callonParenthesizedExpression25() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression25(stack["expr"])
+}
+
+func (c *current) onParenthesizedExpression37() (bool, error) {
+	return false, errors.New("Unmatched parentheses")
+}
+
+func (p *parser) callonParenthesizedExpression37() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParenthesizedExpression37()
+}
+
+func (c *current) onMatchSelectorOpValue1(left, operator, right interface{}) (interface{}, error) {
+	return &MatchExpression{Left: left.(*ExpressionValue), Operator: operator.(MatchOperator), Right: right.(*ExpressionValue), Position: nodePosition(c)}, nil
+}
+
+func (p *parser) callonMatchSelectorOpValue1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorOpValue1(stack["left"], stack["operator"], stack["right"])
+}
+
+func (c *current) onMatchSelectorOp1(left, operator interface{}) (interface{}, error) {
+	return &MatchExpression{
+		Left: &ExpressionValue{
+			Operator: MathOpValue,
+			Left:     left.(*MatchValue),
+			Right:    nil,
+		},
+		Operator: operator.(MatchOperator),
+		Right:    nil,
+		Position: nodePosition(c),
+	}, nil
+}
+
+func (p *parser) callonMatchSelectorOp1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchSelectorOp1(stack["left"], stack["operator"])
+}
+
+func (c *current) onMatchValueOpSelector2(value, operator, selector interface{}) (interface{}, error) {
+	return &MatchExpression{
+		Left: &ExpressionValue{
+			Operator: MathOpValue,
+			Left:     selector.(*MatchValue),
+			Right:    nil,
+		},
+		Operator: operator.(MatchOperator),
+		Right: &ExpressionValue{
+			Operator: MathOpValue,
+			Left:     value.(*MatchValue),
+			Right:    nil,
+		},
+		Position: nodePosition(c),
+	}, nil
+}
+
+func (p *parser) callonMatchValueOpSelector2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchValueOpSelector2(stack["value"], stack["operator"], stack["selector"])
+}
+
+func (c *current) onMatchValueOpSelector20(operator interface{}) (bool, error) {
+	return false, errors.New("Invalid selector")
+}
+
+func (p *parser) callonMatchValueOpSelector20() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchValueOpSelector20(stack["operator"])
+}
+
+func (c *current) onMatchLowerOrEqual1() (interface{}, error) {
+	return MatchLowerOrEqual, nil
+}
+
+func (p *parser) callonMatchLowerOrEqual1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchLowerOrEqual1()
+}
+
+func (c *current) onMatchLower1() (interface{}, error) {
+	return MatchLower, nil
+}
+
+func (p *parser) callonMatchLower1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchLower1()
+}
+
+func (c *current) onMatchHigherOrEqual1() (interface{}, error) {
+	return MatchHigherOrEqual, nil
+}
+
+func (p *parser) callonMatchHigherOrEqual1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchHigherOrEqual1()
+}
+
+func (c *current) onMatchHigher1() (interface{}, error) {
+	return MatchHigher, nil
+}
+
+func (p *parser) callonMatchHigher1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchHigher1()
+}
+
+func (c *current) onMatchEqual1() (interface{}, error) {
+	return MatchEqual, nil
+}
+
+func (p *parser) callonMatchEqual1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchEqual1()
+}
+
+func (c *current) onMatchNotEqual1() (interface{}, error) {
+	return MatchNotEqual, nil
+}
+
+func (p *parser) callonMatchNotEqual1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotEqual1()
+}
+
+func (c *current) onMatchIsEmpty1() (interface{}, error) {
+	return MatchIsEmpty, nil
+}
+
+func (p *parser) callonMatchIsEmpty1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchIsEmpty1()
 }
 
-func (c *current) onInput2(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchIsNotEmpty1() (interface{}, error) {
+	return MatchIsNotEmpty, nil
 }
 
-func (p *parser) callonInput2() (interface{}, error) {
+func (p *parser) callonMatchIsNotEmpty1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onInput2(stack["expr"])
+	return p.cur.onMatchIsNotEmpty1()
 }
 
-func (c *current) onInput17(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchIsNull1() (interface{}, error) {
+	return MatchIsNull, nil
 }
 
-func (p *parser) callonInput17() (interface{}, error) {
+func (p *parser) callonMatchIsNull1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onInput17(stack["expr"])
+	return p.cur.onMatchIsNull1()
 }
 
-func (c *current) onOrExpression2(left, right interface{}) (interface{}, error) {
-	return &BinaryExpression{
-		Operator: BinaryOpOr,
-		Left:     left.(Expression),
-		Right:    right.(Expression),
-	}, nil
+func (c *current) onMatchIsNotNull1() (interface{}, error) {
+	return MatchIsNotNull, nil
 }
 
-func (p *parser) callonOrExpression2() (interface{}, error) {
+func (p *parser) callonMatchIsNotNull1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onOrExpression2(stack["left"], stack["right"])
+	return p.cur.onMatchIsNotNull1()
 }
 
-func (c *current) onOrExpression11(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchIn1() (interface{}, error) {
+	return MatchIn, nil
 }
 
-func (p *parser) callonOrExpression11() (interface{}, error) {
+func (p *parser) callonMatchIn1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onOrExpression11(stack["expr"])
+	return p.cur.onMatchIn1()
 }
 
-func (c *current) onAndExpression2(left, right interface{}) (interface{}, error) {
-	return &BinaryExpression{
-		Operator: BinaryOpAnd,
-		Left:     left.(Expression),
-		Right:    right.(Expression),
-	}, nil
+func (c *current) onMatchNotIn1() (interface{}, error) {
+	return MatchNotIn, nil
 }
 
-func (p *parser) callonAndExpression2() (interface{}, error) {
+func (p *parser) callonMatchNotIn1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onAndExpression2(stack["left"], stack["right"])
+	return p.cur.onMatchNotIn1()
 }
 
-func (c *current) onAndExpression11(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchContains1() (interface{}, error) {
+	return MatchIn, nil
 }
 
-func (p *parser) callonAndExpression11() (interface{}, error) {
+func (p *parser) callonMatchContains1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onAndExpression11(stack["expr"])
+	return p.cur.onMatchContains1()
 }
 
-func (c *current) onNotExpression2(expr interface{}) (interface{}, error) {
-	if unary, ok := expr.(*UnaryExpression); ok && unary.Operator == UnaryOpNot {
-		// small optimization to get rid unnecessary levels of AST nodes
-		// for things like:  not not foo == 3  which is equivalent to foo == 3
-		return unary.Operand, nil
-	}
+func (c *current) onMatchNotContains1() (interface{}, error) {
+	return MatchNotIn, nil
+}
 
-	return &UnaryExpression{
-		Operator: UnaryOpNot,
-		Operand:  expr.(Expression),
-	}, nil
+func (p *parser) callonMatchNotContains1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onMatchNotContains1()
 }
 
-func (p *parser) callonNotExpression2() (interface{}, error) {
+func (c *current) onMatchMatches1() (interface{}, error) {
+	return MatchMatches, nil
+}
+
+func (p *parser) callonMatchMatches1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onNotExpression2(stack["expr"])
+	return p.cur.onMatchMatches1()
 }
 
-func (c *current) onNotExpression8(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchNotMatches1() (interface{}, error) {
+	return MatchNotMatches, nil
 }
 
-func (p *parser) callonNotExpression8() (interface{}, error) {
+func (p *parser) callonMatchNotMatches1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onNotExpression8(stack["expr"])
+	return p.cur.onMatchNotMatches1()
 }
 
-func (c *current) onParenthesizedExpression2(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchStartsWith1() (interface{}, error) {
+	return MatchStartsWith, nil
 }
 
-func (p *parser) callonParenthesizedExpression2() (interface{}, error) {
+func (p *parser) callonMatchStartsWith1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression2(stack["expr"])
+	return p.cur.onMatchStartsWith1()
 }
 
-func (c *current) onParenthesizedExpression12(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchNotStartsWith1() (interface{}, error) {
+	return MatchNotStartsWith, nil
 }
 
-func (p *parser) callonParenthesizedExpression12() (interface{}, error) {
+func (p *parser) callonMatchNotStartsWith1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression12(stack["expr"])
+	return p.cur.onMatchNotStartsWith1()
 }
 
-func (c *current) onParenthesizedExpression22(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchEndsWith1() (interface{}, error) {
+	return MatchEndsWith, nil
 }
 
-func (p *parser) callonParenthesizedExpression22() (interface{}, error) {
+func (p *parser) callonMatchEndsWith1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression22(stack["expr"])
+	return p.cur.onMatchEndsWith1()
 }
 
-func (c *current) onParenthesizedExpression25(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onMatchNotEndsWith1() (interface{}, error) {
+	return MatchNotEndsWith, nil
 }
 
-func (p *parser) callonParenthesizedExpression25() (interface{}, error) {
+func (p *parser) callonMatchNotEndsWith1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression25(stack["expr"])
+	return p.cur.onMatchNotEndsWith1()
 }
 
-func (c *current) onParenthesizedExpression37() (bool, error) {
-	return false, errors.New("Unmatched parentheses")
+func (c *current) onMatchLike1() (interface{}, error) {
+	return MatchLike, nil
 }
 
-func (p *parser) callonParenthesizedExpression37() (bool, error) {
+func (p *parser) callonMatchLike1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onParenthesizedExpression37()
+	return p.cur.onMatchLike1()
 }
 
-func (c *current) onMatchSelectorOpValue1(left, operator, right interface{}) (interface{}, error) {
-	return &MatchExpression{Left: left.(*ExpressionValue), Operator: operator.(MatchOperator), Right: right.(*ExpressionValue)}, nil
+func (c *current) onMatchNotLike1() (interface{}, error) {
+	return MatchNotLike, nil
 }
 
-func (p *parser) callonMatchSelectorOpValue1() (interface{}, error) {
+func (p *parser) callonMatchNotLike1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchSelectorOpValue1(stack["left"], stack["operator"], stack["right"])
+	return p.cur.onMatchNotLike1()
 }
 
-func (c *current) onMatchSelectorOp1(left, operator interface{}) (interface{}, error) {
-	return &MatchExpression{
-		Left: &ExpressionValue{
-			Operator: MathOpValue,
-			Left:     left.(*MatchValue),
-			Right:    nil,
-		},
-		Operator: operator.(MatchOperator),
-		Right:    nil,
-	}, nil
+func (c *current) onKeywordAnd4() (bool, error) {
+	return hasKeywordAliases(c), nil
 }
 
-func (p *parser) callonMatchSelectorOp1() (interface{}, error) {
+func (p *parser) callonKeywordAnd4() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchSelectorOp1(stack["left"], stack["operator"])
+	return p.cur.onKeywordAnd4()
 }
 
-func (c *current) onMatchValueOpSelector2(value, operator, selector interface{}) (interface{}, error) {
-	return &MatchExpression{
-		Left: &ExpressionValue{
-			Operator: MathOpValue,
-			Left:     selector.(*MatchValue),
-			Right:    nil,
-		},
-		Operator: operator.(MatchOperator),
-		Right: &ExpressionValue{
-			Operator: MathOpValue,
-			Left:     value.(*MatchValue),
-			Right:    nil,
-		},
-	}, nil
+func (c *current) onKeywordAnd7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "and"), nil
 }
 
-func (p *parser) callonMatchValueOpSelector2() (interface{}, error) {
+func (p *parser) callonKeywordAnd7() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchValueOpSelector2(stack["value"], stack["operator"], stack["selector"])
+	return p.cur.onKeywordAnd7(stack["w"])
+}
+
+func (c *current) onKeywordOr4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordOr4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordOr4()
+}
+
+func (c *current) onKeywordOr7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "or"), nil
+}
+
+func (p *parser) callonKeywordOr7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordOr7(stack["w"])
+}
+
+func (c *current) onKeywordNot4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordNot4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordNot4()
+}
+
+func (c *current) onKeywordNot7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "not"), nil
+}
+
+func (p *parser) callonKeywordNot7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordNot7(stack["w"])
+}
+
+func (c *current) onKeywordLet4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordLet4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordLet4()
+}
+
+func (c *current) onKeywordLet7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "let"), nil
+}
+
+func (p *parser) callonKeywordLet7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordLet7(stack["w"])
+}
+
+func (c *current) onKeywordIn4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordIn4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordIn4()
+}
+
+func (c *current) onKeywordIn7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "in"), nil
+}
+
+func (p *parser) callonKeywordIn7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordIn7(stack["w"])
+}
+
+func (c *current) onKeywordIs4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordIs4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordIs4()
+}
+
+func (c *current) onKeywordIs7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "is"), nil
+}
+
+func (p *parser) callonKeywordIs7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordIs7(stack["w"])
+}
+
+func (c *current) onKeywordEmpty4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordEmpty4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordEmpty4()
+}
+
+func (c *current) onKeywordEmpty7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "empty"), nil
+}
+
+func (p *parser) callonKeywordEmpty7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordEmpty7(stack["w"])
+}
+
+func (c *current) onKeywordNull4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordNull4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordNull4()
+}
+
+func (c *current) onKeywordNull7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "null"), nil
+}
+
+func (p *parser) callonKeywordNull7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordNull7(stack["w"])
+}
+
+func (c *current) onKeywordContains4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordContains4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordContains4()
+}
+
+func (c *current) onKeywordContains7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "contains"), nil
+}
+
+func (p *parser) callonKeywordContains7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordContains7(stack["w"])
+}
+
+func (c *current) onKeywordMatches4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordMatches4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordMatches4()
+}
+
+func (c *current) onKeywordMatches7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "matches"), nil
+}
+
+func (p *parser) callonKeywordMatches7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordMatches7(stack["w"])
+}
+
+func (c *current) onKeywordStartsWith4() (bool, error) {
+	return hasKeywordAliases(c), nil
+}
+
+func (p *parser) callonKeywordStartsWith4() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordStartsWith4()
+}
+
+func (c *current) onKeywordStartsWith7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "startswith"), nil
+}
+
+func (p *parser) callonKeywordStartsWith7() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onKeywordStartsWith7(stack["w"])
 }
 
-func (c *current) onMatchValueOpSelector20(operator interface{}) (bool, error) {
-	return false, errors.New("Invalid selector")
+func (c *current) onKeywordEndsWith4() (bool, error) {
+	return hasKeywordAliases(c), nil
 }
 
-func (p *parser) callonMatchValueOpSelector20() (bool, error) {
+func (p *parser) callonKeywordEndsWith4() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchValueOpSelector20(stack["operator"])
+	return p.cur.onKeywordEndsWith4()
 }
 
-func (c *current) onMatchLowerOrEqual1() (interface{}, error) {
-	return MatchLowerOrEqual, nil
+func (c *current) onKeywordEndsWith7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "endswith"), nil
 }
 
-func (p *parser) callonMatchLowerOrEqual1() (interface{}, error) {
+func (p *parser) callonKeywordEndsWith7() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchLowerOrEqual1()
+	return p.cur.onKeywordEndsWith7(stack["w"])
 }
 
-func (c *current) onMatchLower1() (interface{}, error) {
-	return MatchLower, nil
+func (c *current) onKeywordLike4() (bool, error) {
+	return hasKeywordAliases(c), nil
 }
 
-func (p *parser) callonMatchLower1() (interface{}, error) {
+func (p *parser) callonKeywordLike4() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchLower1()
+	return p.cur.onKeywordLike4()
 }
 
-func (c *current) onMatchHigherOrEqual1() (interface{}, error) {
-	return MatchHigherOrEqual, nil
+func (c *current) onKeywordLike7(w interface{}) (bool, error) {
+	return isKeywordAlias(c, w.(string), "like"), nil
 }
 
-func (p *parser) callonMatchHigherOrEqual1() (interface{}, error) {
+func (p *parser) callonKeywordLike7() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchHigherOrEqual1()
+	return p.cur.onKeywordLike7(stack["w"])
 }
 
-func (c *current) onMatchHigher1() (interface{}, error) {
-	return MatchHigher, nil
+func (c *current) onWord1() (interface{}, error) {
+	return string(c.text), nil
 }
 
-func (p *parser) callonMatchHigher1() (interface{}, error) {
+func (p *parser) callonWord1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchHigher1()
+	return p.cur.onWord1()
 }
 
-func (c *current) onMatchEqual1() (interface{}, error) {
-	return MatchEqual, nil
+func (c *current) onSelector4() (bool, error) {
+	return selectorDialect(c) == SelectorDialectJSONPointer, nil
 }
 
-func (p *parser) callonMatchEqual1() (interface{}, error) {
+func (p *parser) callonSelector4() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchEqual1()
+	return p.cur.onSelector4()
 }
 
-func (c *current) onMatchNotEqual1() (interface{}, error) {
-	return MatchNotEqual, nil
+func (c *current) onSelector2(sel interface{}) (interface{}, error) {
+	return sel, nil
 }
 
-func (p *parser) callonMatchNotEqual1() (interface{}, error) {
+func (p *parser) callonSelector2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotEqual1()
+	return p.cur.onSelector2(stack["sel"])
 }
 
-func (c *current) onMatchIsEmpty1() (interface{}, error) {
-	return MatchIsEmpty, nil
+func (c *current) onSelector9() (bool, error) {
+	return selectorDialect(c) == SelectorDialectJSONPath, nil
 }
 
-func (p *parser) callonMatchIsEmpty1() (interface{}, error) {
+func (p *parser) callonSelector9() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchIsEmpty1()
+	return p.cur.onSelector9()
 }
 
-func (c *current) onMatchIsNotEmpty1() (interface{}, error) {
-	return MatchIsNotEmpty, nil
+func (c *current) onSelector7(sel interface{}) (interface{}, error) {
+	return sel, nil
 }
 
-func (p *parser) callonMatchIsNotEmpty1() (interface{}, error) {
+func (p *parser) callonSelector7() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchIsNotEmpty1()
+	return p.cur.onSelector7(stack["sel"])
 }
 
-func (c *current) onMatchIn1() (interface{}, error) {
-	return MatchIn, nil
+func (c *current) onSelector14() (bool, error) {
+	return selectorDialect(c) == SelectorDialectBexpr, nil
 }
 
-func (p *parser) callonMatchIn1() (interface{}, error) {
+func (p *parser) callonSelector14() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchIn1()
+	return p.cur.onSelector14()
 }
 
-func (c *current) onMatchNotIn1() (interface{}, error) {
-	return MatchNotIn, nil
+func (c *current) onSelector12(sel interface{}) (interface{}, error) {
+	return sel, nil
 }
 
-func (p *parser) callonMatchNotIn1() (interface{}, error) {
+func (p *parser) callonSelector12() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotIn1()
+	return p.cur.onSelector12(stack["sel"])
 }
 
-func (c *current) onMatchContains1() (interface{}, error) {
-	return MatchIn, nil
+func (c *current) onBexprSelector6(first interface{}) (bool, error) {
+	return !isReservedWord(c, first.(string)), nil
 }
 
-func (p *parser) callonMatchContains1() (interface{}, error) {
+func (p *parser) callonBexprSelector6() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchContains1()
+	return p.cur.onBexprSelector6(stack["first"])
 }
 
-func (c *current) onMatchNotContains1() (interface{}, error) {
-	return MatchNotIn, nil
+func (c *current) onBexprSelector2(first, rest interface{}) (interface{}, error) {
+	sel := Selector{
+		Type: SelectorTypeBexpr,
+		Path: []string{first.(string)},
+	}
+	if rest != nil {
+		sel.appendParts(rest.([]interface{}))
+	}
+	return sel, nil
 }
 
-func (p *parser) callonMatchNotContains1() (interface{}, error) {
+func (p *parser) callonBexprSelector2() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotContains1()
+	return p.cur.onBexprSelector2(stack["first"], stack["rest"])
 }
 
-func (c *current) onMatchMatches1() (interface{}, error) {
-	return MatchMatches, nil
+func (c *current) onBexprSelector10(rest interface{}) (interface{}, error) {
+	// anchored at the root of the datum, such as $.Meta.env
+	sel := Selector{
+		Type:     SelectorTypeBexpr,
+		Anchored: true,
+	}
+	sel.appendParts(rest.([]interface{}))
+	return sel, nil
 }
 
-func (p *parser) callonMatchMatches1() (interface{}, error) {
+func (p *parser) callonBexprSelector10() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchMatches1()
+	return p.cur.onBexprSelector10(stack["rest"])
 }
 
-func (c *current) onMatchNotMatches1() (interface{}, error) {
-	return MatchNotMatches, nil
+func (c *current) onBexprSelector22() (bool, error) {
+	return false, errors.New("a range must follow a part of the selector")
 }
 
-func (p *parser) callonMatchNotMatches1() (interface{}, error) {
+func (p *parser) callonBexprSelector22() (bool, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onMatchNotMatches1()
+	return p.cur.onBexprSelector22()
 }
 
-func (c *current) onSelector2(first, rest interface{}) (interface{}, error) {
+func (c *current) onBexprSelector23(first, rest interface{}) (interface{}, error) {
+	// escaped first part, such as ["and"]
 	sel := Selector{
 		Type: SelectorTypeBexpr,
 		Path: []string{first.(string)},
 	}
 	if rest != nil {
-		for _, v := range rest.([]interface{}) {
-			sel.Path = append(sel.Path, v.(string))
-		}
+		sel.appendParts(rest.([]interface{}))
 	}
 	return sel, nil
 }
 
-func (p *parser) callonSelector2() (interface{}, error) {
+func (p *parser) callonBexprSelector23() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelector2(stack["first"], stack["rest"])
+	return p.cur.onBexprSelector23(stack["first"], stack["rest"])
 }
 
-func (c *current) onSelector9(ptrsegs interface{}) (interface{}, error) {
+func (c *current) onBexprSelector30(ptrsegs interface{}) (interface{}, error) {
 	sel := Selector{
 		Type: SelectorTypeJsonPointer,
 	}
@@ -2729,10 +4922,10 @@ func (c *current) onSelector9(ptrsegs interface{}) (interface{}, error) {
 	return sel, nil
 }
 
-func (p *parser) callonSelector9() (interface{}, error) {
+func (p *parser) callonBexprSelector30() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelector9(stack["ptrsegs"])
+	return p.cur.onBexprSelector30(stack["ptrsegs"])
 }
 
 func (c *current) onJsonPointerSegment1(ident interface{}) (interface{}, error) {
@@ -2745,6 +4938,116 @@ func (p *parser) callonJsonPointerSegment1() (interface{}, error) {
 	return p.cur.onJsonPointerSegment1(stack["ident"])
 }
 
+func (c *current) onJSONPointerSelector1() (interface{}, error) {
+	return parseJSONPointer(string(c.text))
+}
+
+func (p *parser) callonJSONPointerSelector1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPointerSelector1()
+}
+
+func (c *current) onJSONPathSelector1(segs interface{}) (interface{}, error) {
+	sel := Selector{
+		Type: SelectorTypeJsonPath,
+	}
+	for _, v := range segs.([]interface{}) {
+		sel.Path = append(sel.Path, v.(string))
+	}
+	return sel, nil
+}
+
+func (p *parser) callonJSONPathSelector1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathSelector1(stack["segs"])
+}
+
+func (c *current) onJSONPathSegment2(name interface{}) (interface{}, error) {
+	return name, nil
+}
+
+func (p *parser) callonJSONPathSegment2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathSegment2(stack["name"])
+}
+
+func (c *current) onJSONPathSegment7(idx interface{}) (interface{}, error) {
+	return idx, nil
+}
+
+func (p *parser) callonJSONPathSegment7() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathSegment7(stack["idx"])
+}
+
+func (c *current) onJSONPathSegment17(name interface{}) (interface{}, error) {
+	return name, nil
+}
+
+func (p *parser) callonJSONPathSegment17() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathSegment17(stack["name"])
+}
+
+func (c *current) onJSONPathSegment29() (bool, error) {
+	return false, errors.New("Invalid JSONPath segment")
+}
+
+func (p *parser) callonJSONPathSegment29() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathSegment29()
+}
+
+func (c *current) onJSONPathName1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonJSONPathName1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathName1()
+}
+
+func (c *current) onJSONPathIndex1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonJSONPathIndex1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathIndex1()
+}
+
+func (c *current) onJSONPathString2() (interface{}, error) {
+	s, err := unquoteJSONPathString(string(c.text))
+	if err != nil {
+		return nil, err
+	}
+	return s, checkLiteralLimits(c, s)
+}
+
+func (p *parser) callonJSONPathString2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathString2()
+}
+
+func (c *current) onJSONPathString12(lit interface{}) (interface{}, error) {
+	return lit, nil
+}
+
+func (p *parser) callonJSONPathString12() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onJSONPathString12(stack["lit"])
+}
+
 func (c *current) onIdentifier1() (interface{}, error) {
 	return string(c.text), nil
 }
@@ -2755,6 +5058,16 @@ func (p *parser) callonIdentifier1() (interface{}, error) {
 	return p.cur.onIdentifier1()
 }
 
+func (c *current) onParam1(ident interface{}) (interface{}, error) {
+	return ident, nil
+}
+
+func (p *parser) callonParam1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onParam1(stack["ident"])
+}
+
 func (c *current) onSelectorOrIndex2(ident interface{}) (interface{}, error) {
 	return ident, nil
 }
@@ -2765,24 +5078,54 @@ func (p *parser) callonSelectorOrIndex2() (interface{}, error) {
 	return p.cur.onSelectorOrIndex2(stack["ident"])
 }
 
-func (c *current) onSelectorOrIndex7(expr interface{}) (interface{}, error) {
-	return expr, nil
+func (c *current) onSelectorOrIndex7(r interface{}) (interface{}, error) {
+	return r, nil
 }
 
 func (p *parser) callonSelectorOrIndex7() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelectorOrIndex7(stack["expr"])
+	return p.cur.onSelectorOrIndex7(stack["r"])
 }
 
-func (c *current) onSelectorOrIndex10(idx interface{}) (interface{}, error) {
-	return string(c.text)[1:], nil
+func (c *current) onSelectorOrIndex10(expr interface{}) (interface{}, error) {
+	return expr, nil
 }
 
 func (p *parser) callonSelectorOrIndex10() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onSelectorOrIndex10(stack["idx"])
+	return p.cur.onSelectorOrIndex10(stack["expr"])
+}
+
+func (c *current) onSelectorOrIndex13(idx interface{}) (interface{}, error) {
+	return string(c.text)[1:], nil
+}
+
+func (p *parser) callonSelectorOrIndex13() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onSelectorOrIndex13(stack["idx"])
+}
+
+func (c *current) onRangeExpression1(low, high interface{}) (interface{}, error) {
+	return newSelectorRange(low, high), nil
+}
+
+func (p *parser) callonRangeExpression1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRangeExpression1(stack["low"], stack["high"])
+}
+
+func (c *current) onRangeBound1(n interface{}) (interface{}, error) {
+	return strconv.Atoi(n.(string))
+}
+
+func (p *parser) callonRangeBound1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onRangeBound1(stack["n"])
 }
 
 func (c *current) onIndexExpression2(lit interface{}) (interface{}, error) {
@@ -2903,74 +5246,185 @@ func (p *parser) callonValue5() (interface{}, error) {
 	return p.cur.onValue5(stack["u"])
 }
 
-func (c *current) onValue8(selector interface{}) (interface{}, error) {
-	return &MatchValue{Selector: selector.(Selector), Type: ValueTypeReflect /*, Raw:selector.(Selector).String()*/}, nil
+func (c *current) onValue8(n interface{}) (interface{}, error) {
+	return &MatchValue{Type: ValueTypeNull, Raw: n.(string)}, nil
 }
 
 func (p *parser) callonValue8() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue8(stack["selector"])
+	return p.cur.onValue8(stack["n"])
 }
 
-func (c *current) onValue11(n interface{}) (interface{}, error) {
-	return &MatchValue{Type: ValueTypeFloat64, Raw: n.(string)}, nil
+func (c *current) onValue11(p interface{}) (interface{}, error) {
+	return &MatchValue{Type: ValueTypeParam, Raw: p.(string)}, nil
 }
 
 func (p *parser) callonValue11() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue11(stack["n"])
+	return p.cur.onValue11(stack["p"])
 }
 
-func (c *current) onValue17(n interface{}) (interface{}, error) {
-	return &MatchValue{Type: ValueTypeInt, Raw: n.(string)}, nil
+func (c *current) onValue14(call interface{}) (interface{}, error) {
+	return call, nil
+}
+
+func (p *parser) callonValue14() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue14(stack["call"])
+}
+
+func (c *current) onValue17(selector interface{}) (interface{}, error) {
+	return &MatchValue{Selector: selector.(Selector), Type: ValueTypeReflect /*, Raw:selector.(Selector).String()*/}, nil
 }
 
 func (p *parser) callonValue17() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue17(stack["n"])
+	return p.cur.onValue17(stack["selector"])
+}
+
+func (c *current) onValue20(n interface{}) (interface{}, error) {
+	return &MatchValue{Type: ValueTypeFloat64, Raw: n.(string)}, nil
+}
+
+func (p *parser) callonValue20() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue20(stack["n"])
+}
+
+func (c *current) onValue26(n interface{}) (interface{}, error) {
+	if _, err := strconv.ParseInt(n.(string), 10, 64); err != nil {
+		// integers above math.MaxInt64 are unsigned
+		if _, err := strconv.ParseUint(n.(string), 10, 64); err == nil {
+			return &MatchValue{Type: ValueTypeUint, Raw: n.(string)}, nil
+		}
+	}
+	return &MatchValue{Type: ValueTypeInt, Raw: n.(string)}, nil
+}
+
+func (p *parser) callonValue26() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue26(stack["n"])
 }
 
-func (c *current) onValue23(n interface{}) (interface{}, error) {
+func (c *current) onValue32(n interface{}) (interface{}, error) {
 	return false, errors.New("Invalid number literal")
 }
 
-func (p *parser) callonValue23() (interface{}, error) {
+func (p *parser) callonValue32() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue23(stack["n"])
+	return p.cur.onValue32(stack["n"])
 }
 
-func (c *current) onValue29(n interface{}) (interface{}, error) {
+func (c *current) onValue38(n interface{}) (interface{}, error) {
 	return false, errors.New("Invalid number literal")
 }
 
-func (p *parser) callonValue29() (interface{}, error) {
+func (p *parser) callonValue38() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue29(stack["n"])
+	return p.cur.onValue38(stack["n"])
 }
 
-func (c *current) onValue35(n interface{}) (interface{}, error) {
+func (c *current) onValue44(n interface{}) (interface{}, error) {
 	return false, errors.New("Invalid bool literal")
 }
 
-func (p *parser) callonValue35() (interface{}, error) {
+func (p *parser) callonValue44() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue35(stack["n"])
+	return p.cur.onValue44(stack["n"])
 }
 
-func (c *current) onValue41(s interface{}) (interface{}, error) {
+func (c *current) onValue50(s interface{}) (interface{}, error) {
 	return &MatchValue{Type: ValueTypeString, Raw: s.(string)}, nil
 }
 
-func (p *parser) callonValue41() (interface{}, error) {
+func (p *parser) callonValue50() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue50(stack["s"])
+}
+
+func (c *current) onValue56(w interface{}) (bool, error) {
+	return false, identifierError(c, w.(string))
+}
+
+func (p *parser) callonValue56() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onValue56(stack["w"])
+}
+
+func (c *current) onFunctionCall6(name interface{}) (bool, error) {
+	return isFunction(name.(string)), nil
+}
+
+func (p *parser) callonFunctionCall6() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall6(stack["name"])
+}
+
+func (c *current) onFunctionCall16(name, args interface{}) (bool, error) {
+	err := checkFunctionCall(name.(string), args)
+	return err == nil, err
+}
+
+func (p *parser) callonFunctionCall16() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall16(stack["name"], stack["args"])
+}
+
+func (c *current) onFunctionCall2(name, args interface{}) (interface{}, error) {
+	return newFunctionCall(name.(string), args), nil
+}
+
+func (p *parser) callonFunctionCall2() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall2(stack["name"], stack["args"])
+}
+
+func (c *current) onFunctionCall22(name interface{}) (bool, error) {
+	return isFunction(name.(string)), nil
+}
+
+func (p *parser) callonFunctionCall22() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall22(stack["name"])
+}
+
+func (c *current) onFunctionCall23(name interface{}) (bool, error) {
+	return false, checkFunctionCall(name.(string), nil)
+}
+
+func (p *parser) callonFunctionCall23() (bool, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onFunctionCall23(stack["name"])
+}
+
+func (c *current) onFunctionArgs1(first, rest interface{}) (interface{}, error) {
+	args := []*ExpressionValue{first.(*ExpressionValue)}
+	for _, r := range rest.([]interface{}) {
+		args = append(args, r.([]interface{})[3].(*ExpressionValue))
+	}
+	return args, nil
+}
+
+func (p *parser) callonFunctionArgs1() (interface{}, error) {
 	stack := p.vstack[len(p.vstack)-1]
 	_ = stack
-	return p.cur.onValue41(stack["s"])
+	return p.cur.onFunctionArgs1(stack["first"], stack["rest"])
 }
 
 func (c *current) onUndefined2() (interface{}, error) {
@@ -2993,6 +5447,16 @@ func (p *parser) callonUndefined11() (bool, error) {
 	return p.cur.onUndefined11()
 }
 
+func (c *current) onNull1() (interface{}, error) {
+	return string(c.text), nil
+}
+
+func (p *parser) callonNull1() (interface{}, error) {
+	stack := p.vstack[len(p.vstack)-1]
+	_ = stack
+	return p.cur.onNull1()
+}
+
 func (c *current) onTrueOrFalse2() (interface{}, error) {
 	return string(c.text), nil
 }
@@ -3034,7 +5498,11 @@ func (p *parser) callonInteger1() (interface{}, error) {
 }
 
 func (c *current) onStringLiteral2() (interface{}, error) {
-	return strconv.Unquote(string(c.text))
+	s, err := strconv.Unquote(string(c.text))
+	if err != nil {
+		return nil, err
+	}
+	return s, checkLiteralLimits(c, s)
 }
 
 func (p *parser) callonStringLiteral2() (interface{}, error) {
@@ -20,79 +20,182 @@ func TestExpressionParsing(t *testing.T) {
 	tests := map[string]testCase{
 		"Match Equality": {
 			input:    "foo == 3",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 			err:      "",
 		},
 		"Match Equality, JSON Pointer": {
 			input:    `"/foo" == 3`,
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 			err:      "",
 		},
 		"Match Equality, JSON Pointer, with punctuation": {
 			input:    `"/hy-phen/under_score/pi|pe/do.t/ti~lde/co:lon" == 3`,
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"hy-phen", "under_score", "pi|pe", "do.t", "ti~lde", "co:lon"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"hy-phen", "under_score", "pi|pe", "do.t", "ti~lde", "co:lon"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 			err:      "",
 		},
 		"Match Equality, JSON Pointer, with punctuation, trailing slash": {
 			input:    `"/hy-phen/under_score/pi|pe/do.t/ti~lde/" == 3`,
-			expected: nil,
-			err:      "1:43 (42): no match found, expected: \"in\", \"not\" or [ \\t\\r\\n]",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "/hy-phen/under_score/pi|pe/do.t/ti~lde/"}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
+			err:      "",
 		},
 		"Match Equality with forward slash in identifier": {
 			input:    "foo/bar == 3",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo/bar"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo/bar"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
+			err:      "",
+		},
+		"Match Equality, anchored": {
+			input:    `$.foo["b c"].0 == 3`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "b c", "0"}, Anchored: true}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
+			err:      "",
+		},
+		"Match Equality, ranges": {
+			input:    `$.Events[1:].Tags[ : -1 ][:2] is not empty`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Events", "Tags"}, Anchored: true, Ranges: []SelectorRange{{At: 1, Low: intPtr(1)}, {At: 2, High: intPtr(-1)}, {At: 2, High: intPtr(2)}}}}}, Operator: MatchIsNotEmpty},
+			err:      "",
+		},
+		"Match Equality, range of all the elements": {
+			input:    `Events[:] is empty`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"Events"}, Ranges: []SelectorRange{{At: 1}}}}}, Operator: MatchIsEmpty},
+			err:      "",
+		},
+		"Match Function Call": {
+			input:    `"a" in values( keys(foo) )`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeCall, Raw: "values", Args: []*ExpressionValue{{Left: &MatchValue{Type: ValueTypeCall, Raw: "keys", Args: []*ExpressionValue{{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}}}}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "a"}}},
+			err:      "",
+		},
+		"Match Selector Named Like A Function": {
+			input:    `keys is empty`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"keys"}}}}, Operator: MatchIsEmpty},
 			err:      "",
 		},
 		"Match Inequality": {
 			input:    "foo != \"xyz\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "xyz"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "xyz"}}},
 			err:      "",
 		},
 		"Match Is Empty": {
 			input:    "list is empty",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}, Operator: MatchIsEmpty, Right: nil},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}}, Operator: MatchIsEmpty, Right: nil},
 			err:      "",
 		},
 		"Match Is Not Empty": {
 			input:    "list is not empty",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}, Operator: MatchIsNotEmpty, Right: nil},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}}, Operator: MatchIsNotEmpty, Right: nil},
+			err:      "",
+		},
+		"Match Is Null": {
+			input:    "ptr is null",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"ptr"}}}}, Operator: MatchIsNull, Right: nil},
+			err:      "",
+		},
+		"Match Is Not Null": {
+			input:    "ptr is not null",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"ptr"}}}}, Operator: MatchIsNotNull, Right: nil},
+			err:      "",
+		},
+		"Match Equality, Param": {
+			input:    "owner == $user",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"owner"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeParam, Raw: "user"}}},
+			err:      "",
+		},
+		"Match In, Param": {
+			input:    "team in $teams",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeParam, Raw: "teams"}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"team"}}}}},
+			err:      "",
+		},
+		"Let": {
+			input: `let t = meta.tier in t == "gold" or t == "platinum"`,
+			expected: &LetExpression{
+				Name:  "t",
+				Value: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"meta", "tier"}}}},
+				Body: &BinaryExpression{
+					Operator: BinaryOpOr,
+					Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"t"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "gold"}}},
+					Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"t"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "platinum"}}},
+				},
+			},
+			err: "",
+		},
+		"Let selector named let": {
+			input:    `let == 1`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"let"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "1"}}},
+			err:      "",
+		},
+		"Match Equality, Null": {
+			input:    "ptr == null",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"ptr"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeNull, Raw: "null"}}},
+			err:      "",
+		},
+		"Selector Starting With Null": {
+			input:    "nullable == 3",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"nullable"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 			err:      "",
 		},
 		"Match In": {
 			input:    "\"foo\" in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Match Not In": {
 			input:    "\"foo\" not in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchNotIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchNotIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Match Contains": {
 			input:    "bar contains \"foo\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Match Not Contains": {
 			input:    "bar not contains \"foo\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchNotIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchNotIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Match Matches": {
 			input:    "foo matches \"bar\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchMatches, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchMatches, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 			err:      "",
 		},
 		"Match Not Matches": {
 			input:    "foo not matches \"bar\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchNotMatches, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchNotMatches, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
+			err:      "",
+		},
+		"Match Starts With": {
+			input:    "name startswith \"web-\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchStartsWith, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "web-"}}},
+			err:      "",
+		},
+		"Match Not Starts With": {
+			input:    "name not startswith \"web-\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchNotStartsWith, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "web-"}}},
+			err:      "",
+		},
+		"Match Ends With": {
+			input:    "name endswith \"web-\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchEndsWith, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "web-"}}},
+			err:      "",
+		},
+		"Match Not Ends With": {
+			input:    "name not endswith \"web-\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchNotEndsWith, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "web-"}}},
+			err:      "",
+		},
+		"Match Like": {
+			input:    "name like \"api-*-prod\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchLike, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "api-*-prod"}}},
+			err:      "",
+		},
+		"Match Not Like": {
+			input:    "name not like \"api-*-prod\"",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"name"}}}}, Operator: MatchNotLike, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "api-*-prod"}}},
 			err:      "",
 		},
 		"Logical Not": {
 			input: "not \"prod\" in tags",
 			expected: &UnaryExpression{
 				Operator: UnaryOpNot,
-				Operand:  &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "prod"}},
+				Operand:  &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"tags"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "prod"}}},
 			},
 			err: "",
 		},
@@ -100,8 +203,8 @@ func TestExpressionParsing(t *testing.T) {
 			input: "port != 80 and port != 8080",
 			expected: &BinaryExpression{
 				Operator: BinaryOpAnd,
-				Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "80"}},
-				Right:    &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "8080"}},
+				Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "80"}}},
+				Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "8080"}}},
 			},
 			err: "",
 		},
@@ -109,49 +212,49 @@ func TestExpressionParsing(t *testing.T) {
 			input: "port == 80 or port == 443",
 			expected: &BinaryExpression{
 				Operator: BinaryOpOr,
-				Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "80"}},
-				Right:    &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "443"}},
+				Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "80"}}},
+				Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"port"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "443"}}},
 			},
 			err: "",
 		},
 		"Double Quoted Value (Equal)": {
 			input:    "foo == \"bar\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 			err:      "",
 		},
 		"Double Quoted Value (Not Equal)": {
 			input:    "foo != \"bar\"",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 			err:      "",
 		},
 		"Double Quoted Value (In)": {
 			input:    "\"foo\" in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Double Quoted Value (Not In)": {
 			input:    "\"foo\" not in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchNotIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchNotIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Equal)": {
 			input:    "foo == `bar`",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Not Equal)": {
 			input:    "foo != `bar`",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 			err:      "",
 		},
 		"Backtick Quoted Value (In)": {
 			input:    "`foo` in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Backtick Quoted Value (Not In)": {
 			input:    "`foo` not in bar",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}, Operator: MatchNotIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"bar"}}}}, Operator: MatchNotIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		// This is standard boolean expression precedence
@@ -165,13 +268,13 @@ func TestExpressionParsing(t *testing.T) {
 				Operator: BinaryOpOr,
 				Left: &BinaryExpression{
 					Operator: BinaryOpAnd,
-					Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
+					Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
 					Right: &UnaryExpression{
 						Operator: UnaryOpNot,
-						Operand:  &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"str"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "something"}},
+						Operand:  &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"str"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "something"}}},
 					},
 				},
-				Right: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}, Operator: MatchIsEmpty, Right: nil},
+				Right: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}}, Operator: MatchIsEmpty, Right: nil},
 			},
 			err: "",
 		},
@@ -184,13 +287,13 @@ func TestExpressionParsing(t *testing.T) {
 			input: "\"x\" in foo and not (str == \"something\" or list is empty)",
 			expected: &BinaryExpression{
 				Operator: BinaryOpAnd,
-				Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
+				Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
 				Right: &UnaryExpression{
 					Operator: UnaryOpNot,
 					Operand: &BinaryExpression{
 						Operator: BinaryOpOr,
-						Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"str"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "something"}},
-						Right:    &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}, Operator: MatchIsEmpty, Right: nil},
+						Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"str"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "something"}}},
+						Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"list"}}}}, Operator: MatchIsEmpty, Right: nil},
 					},
 				},
 			},
@@ -198,39 +301,39 @@ func TestExpressionParsing(t *testing.T) {
 		},
 		"Extra Whitespace (Equal)": {
 			input:    "\t\r\n  foo \t\r\n == \t\r\n \"x\" \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
 			err:      "",
 		},
 		"Extra Whitespace (Not Equal)": {
 			input:    "\t\r\n  foo \t\r\n != \t\r\n \"x\" \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
 			err:      "",
 		},
 		"Extra Whitespace (In)": {
 			input:    "\t\r\n  \"foo\" \t\r\n in \t\r\n x \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Extra Whitespace (Not In)": {
 			input:    "\t\r\n  \"foo\" \t\r\n not \t\r\n in \t\r\n x \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}, Operator: MatchNotIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}}, Operator: MatchNotIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			err:      "",
 		},
 		"Extra Whitespace (Is Empty)": {
 			input:    "\t\r\n  foo \t\r\n is \t\r\n empty \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchIsEmpty, Right: nil},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchIsEmpty, Right: nil},
 			err:      "",
 		},
 		"Extra Whitespace (Is Not Empty)": {
 			input:    "\t\r\n  foo \t\r\n is \t\r\n not \t\r\n empty \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchIsNotEmpty, Right: nil},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchIsNotEmpty, Right: nil},
 			err:      "",
 		},
 		"Extra Whitespace (Not)": {
 			input: "\t\r\n not \t\r\n  \"foo\" \t\r\n in \t\r\n x \t\r\n",
 			expected: &UnaryExpression{
 				Operator: UnaryOpNot,
-				Operand:  &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "foo"}},
+				Operand:  &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "foo"}}},
 			},
 			err: "",
 		},
@@ -238,8 +341,8 @@ func TestExpressionParsing(t *testing.T) {
 			input: "\t\r\n foo \t\r\n == \t\r\n \"x\" \t\r\n and \t\r\n y \t\r\n is \t\r\n empty \t\r\n",
 			expected: &BinaryExpression{
 				Operator: BinaryOpAnd,
-				Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
-				Right:    &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"y"}}}, Operator: MatchIsEmpty, Right: nil},
+				Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
+				Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"y"}}}}, Operator: MatchIsEmpty, Right: nil},
 			},
 			err: "",
 		},
@@ -247,41 +350,41 @@ func TestExpressionParsing(t *testing.T) {
 			input: "\t\r\n foo \t\r\n == \t\r\n \"x\" \t\r\n or \t\r\n y \t\r\n is \t\r\n empty \t\r\n",
 			expected: &BinaryExpression{
 				Operator: BinaryOpOr,
-				Left:     &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
-				Right:    &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"y"}}}, Operator: MatchIsEmpty, Right: nil},
+				Left:     &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
+				Right:    &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"y"}}}}, Operator: MatchIsEmpty, Right: nil},
 			},
 			err: "",
 		},
 		"Extra Whitespace (Parentheses)": {
 			input:    "\t\r\n ( \t\r\n foo \t\r\n == \t\r\n \"x\" \t\r\n ) \t\r\n",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "x"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "x"}}},
 			err:      "",
 		},
 		"Selector Path": {
 			input:    "`environment` in foo.bar[\"meta\"].tags[\t`ENV` ]",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "environment"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "environment"}}},
 			err:      "",
 		},
 		"Selector Path, JSON Pointer": {
-			input:    `\"environment\" in "/hy-phen/under_score/pi|pe/do.t/ti~lde"`,
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"hy-phen", "under_score", "pi|pe", "do.t", "ti~lde"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "environment"}},
+			input:    `"environment" in "/hy-phen/under_score/pi|pe/do.t/ti~lde"`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"hy-phen", "under_score", "pi|pe", "do.t", "ti~lde"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "environment"}}},
 			err:      "",
 		},
 		"Selector All Indexes": {
-			input:    `\"environment\" in foo["bar"]["meta"]["tags"]["ENV"]`,
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "environment"}},
+			input:    `"environment" in foo["bar"]["meta"]["tags"]["ENV"]`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "environment"}}},
 			err:      "",
 		},
 		"Selector All Dotted": {
 			input:    "\"environment\" in foo.bar.meta.tags.ENV",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "environment"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "bar", "meta", "tags", "ENV"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "environment"}}},
 			err:      "",
 		},
 		// selectors can contain almost any character set when index expressions are used
 		// This includes whitespace, hyphens, unicode, etc.
 		"Selector Index Chars": {
 			input:    "\"environment\" in foo[\"abc-def ghi åß∂ƒ\"]",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "abc-def ghi åß∂ƒ"}}}, Operator: MatchIn, Right: &MatchValue{Type: ValueTypeString, Raw: "environment"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo", "abc-def ghi åß∂ƒ"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "environment"}}},
 			err:      "",
 		},
 		"Unterminated String Literal 1": {
@@ -297,13 +400,33 @@ func TestExpressionParsing(t *testing.T) {
 		"Invalid Number": {
 			input:    "foo == 3x",
 			expected: nil,
-			err:      "1:9 (8): rule \"number\": Invalid number literal",
+			err:      "1:8 (7): rule \"value\": Invalid number literal",
 		},
 		"Invalid Index Key": {
 			input:    "foo[3] == \"abc\"",
 			expected: nil,
 			err:      "1:5 (4): rule \"index\": Invalid index",
 		},
+		"Range Without Part": {
+			input:    "$[0:1] is empty",
+			expected: nil,
+			err:      "1:2 (1): rule BexprSelector: a range must follow a part of the selector",
+		},
+		"Invalid Range Bound": {
+			input:    "foo[1:x] is empty",
+			expected: nil,
+			err:      "1:5 (4): rule \"index\": Invalid index",
+		},
+		"Unknown Function": {
+			input:    "foo(bar) is empty",
+			expected: nil,
+			err:      "1:4 (3): rule \"call\": unknown function foo",
+		},
+		"Function Arguments": {
+			input:    "keys(foo, bar) is empty",
+			expected: nil,
+			err:      "1:15 (14): rule \"call\": keys takes 1 argument, not 2",
+		},
 		"Unclosed Index Expression 1": {
 			input:    "x in foo[\"abc\"",
 			expected: nil,
@@ -314,20 +437,20 @@ func TestExpressionParsing(t *testing.T) {
 			expected: nil,
 			err:      "1:11 (10): rule \"index\": Unclosed index expression",
 		},
-		"Invalid Selector 1": {
+		"Value In Value": {
 			input:    "x in 32",
-			expected: nil,
-			err:      "1:6 (5): rule \"match\": Invalid selector",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "32"}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}}},
+			err:      "",
 		},
-		"Invalid Selector 2": {
+		"Value Equal Value": {
 			input:    "32 == 32",
-			expected: nil,
-			err:      `1:4 (3): no match found, expected: "in", "not" or [ \t\r\n]`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "32"}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "32"}}},
+			err:      "",
 		},
-		"Invalid Selector 3": {
+		"Value Is Empty": {
 			input:    "32 is empty",
-			expected: nil,
-			err:      `1:4 (3): no match found, expected: "in", "not" or [ \t\r\n]`,
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "32"}}, Operator: MatchIsEmpty, Right: nil},
+			err:      "",
 		},
 		"Junk at the end 1": {
 			input:    "x in foo abc",
@@ -337,31 +460,41 @@ func TestExpressionParsing(t *testing.T) {
 		"Junk at the end 2": {
 			input:    "x in foo and ",
 			expected: nil,
-			err:      "1:14 (13): no match found, expected: \"(\", \"-\", \"0\", \"\\\"\", \"`\", \"false\", \"not\", \"true\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			err:      "1:14 (13): no match found, expected: \"$\", \"(\", \"-\", \"0\", \"[\", \"\\\"\", \"`\", \"false\", \"let\", \"not\", \"null\", \"true\", \"undefined\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
 		},
 		"Junk at the end 3": {
 			input:    "x in foo or ",
 			expected: nil,
-			err:      "1:13 (12): no match found, expected: \"(\", \"-\", \"0\", \"\\\"\", \"`\", \"false\", \"not\", \"true\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			err:      "1:13 (12): no match found, expected: \"$\", \"(\", \"-\", \"0\", \"[\", \"\\\"\", \"`\", \"false\", \"let\", \"not\", \"null\", \"true\", \"undefined\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
 		},
 		"Junk at the end 4": {
 			input:    "x in foo or not ",
-			expected: nil,
-			err:      "1:17 (16): no match found, expected: \"!=\", \"(\", \"-\", \"0\", \"<\", \"<=\", \"==\", \">\", \">=\", \"\\\"\", \"`\", \"contains\", \"false\", \"in\", \"is\", \"matches\", \"not\", \"true\", [ \\t\\r\\n], [1-9] or [a-zA-Z]",
+			expected: &BinaryExpression{Operator: BinaryOpOr, Left: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchIn, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"x"}}}}}, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"not"}}}}},
+			err:      "",
 		},
 		"Float Literal 1": {
 			input:    "foo == 0.2",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeFloat64, Raw: "0.2"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeFloat64, Raw: "0.2"}}},
 			err:      "",
 		},
 		"Float Literal 2": {
 			input:    "foo == 11.11",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeFloat64, Raw: "11.11"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeFloat64, Raw: "11.11"}}},
 			err:      "",
 		},
 		"Negative Float": {
 			input:    "foo == -0.2",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeFloat64, Raw: "-0.2"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeFloat64, Raw: "-0.2"}}},
+			err:      "",
+		},
+		"Unsigned Literal": {
+			input:    "foo == 18446744073709551615",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeUint, Raw: "18446744073709551615"}}},
+			err:      "",
+		},
+		"Max Signed Literal": {
+			input:    "foo == 9223372036854775807",
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "9223372036854775807"}}},
 			err:      "",
 		},
 		"Unmatched Parentheses": {
@@ -371,7 +504,7 @@ func TestExpressionParsing(t *testing.T) {
 		},
 		"Double Not": {
 			input:    "not not foo == 3",
-			expected: &MatchExpression{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}, Operator: MatchEqual, Right: &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+			expected: &MatchExpression{Left: &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}}, Operator: MatchEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 			err:      "",
 		},
 		"Complex": {
@@ -381,22 +514,22 @@ func TestExpressionParsing(t *testing.T) {
 				Left: &BinaryExpression{
 					Operator: BinaryOpAnd,
 					Left: &MatchExpression{
-						Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}},
+						Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}},
 						Operator: MatchEqual,
-						Right:    &MatchValue{Type: ValueTypeInt, Raw: "3"}},
+						Right:    &ExpressionValue{Left: &MatchValue{Type: ValueTypeInt, Raw: "3"}}},
 					Right: &UnaryExpression{
 						Operator: UnaryOpNot,
 						Operand: &BinaryExpression{
 							Operator: BinaryOpAnd,
 							Left: &MatchExpression{
-								Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"baz"}}},
+								Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"baz"}}}},
 								Operator: MatchIn,
-								Right:    &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+								Right:    &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 							Right: &UnaryExpression{
 								Operator: UnaryOpNot,
 								Operand: &MatchExpression{
-									Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"one"}}},
-									Operator: MatchNotEqual, Right: &MatchValue{Type: ValueTypeString, Raw: "two"}},
+									Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"one"}}}},
+									Operator: MatchNotEqual, Right: &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "two"}}},
 							},
 						},
 					},
@@ -406,21 +539,21 @@ func TestExpressionParsing(t *testing.T) {
 					Left: &BinaryExpression{
 						Operator: BinaryOpAnd,
 						Left: &MatchExpression{
-							Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"next"}}},
+							Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"next"}}}},
 							Operator: MatchIsEmpty,
 							Right:    nil},
 						Right: &UnaryExpression{
 							Operator: UnaryOpNot,
 							Operand: &MatchExpression{
-								Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}},
+								Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}},
 								Operator: MatchIsNotEmpty,
 								Right:    nil},
 						},
 					},
 					Right: &MatchExpression{
-						Left:     &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}},
+						Left:     &ExpressionValue{Left: &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: SelectorTypeBexpr, Path: []string{"foo"}}}},
 						Operator: MatchNotIn,
-						Right:    &MatchValue{Type: ValueTypeString, Raw: "bar"}},
+						Right:    &ExpressionValue{Left: &MatchValue{Type: ValueTypeString, Raw: "bar"}}},
 				},
 			},
 			err: "",
@@ -479,3 +612,7 @@ func BenchmarkExpressionParsing(b *testing.B) {
 		})
 	}
 }
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"fmt"
+	"unicode"
+)
+
+const keywordAliasesKey = "keywordAliases"
+
+// Keywords are the keywords of the grammar which can be aliased with
+// KeywordAliases
+var Keywords = []string{
+	"and", "or", "not", "let", "in", "is", "empty", "null",
+	"contains", "matches", "startswith", "endswith", "like",
+}
+
+// KeywordAliases creates an Option accepting aliases of the keywords, such as
+// "et" and "ou" for "and" and "or", or "contient" for "contains", so that
+// expressions can be written in other languages than English. The aliases are
+// mapped to the keywords they stand for, and parse to the same AST as the
+// keywords do. The keywords themselves are always accepted. Aliases are
+// expected to be valid, see ValidateKeywordAliases.
+func KeywordAliases(aliases map[string]string) Option {
+	return GlobalStore(keywordAliasesKey, aliases)
+}
+
+// ValidateKeywordAliases checks the aliases given to KeywordAliases: each one
+// must be a word, letters followed by letters, digits and underscores, aliasing
+// one of the Keywords without being a keyword itself.
+func ValidateKeywordAliases(aliases map[string]string) error {
+	for alias, keyword := range aliases {
+		if !isKeyword(keyword) {
+			return fmt.Errorf("alias %q of unknown keyword %q", alias, keyword)
+		}
+		if isKeyword(alias) {
+			return fmt.Errorf("alias %q of %q is a keyword", alias, keyword)
+		}
+		for i, r := range alias {
+			if !unicode.IsLetter(r) && (i == 0 || (!unicode.IsDigit(r) && r != '_')) {
+				return fmt.Errorf("alias %q of %q is not a word", alias, keyword)
+			}
+		}
+		if alias == "" {
+			return fmt.Errorf("empty alias of %q", keyword)
+		}
+	}
+	return nil
+}
+
+func isKeyword(word string) bool {
+	for _, keyword := range Keywords {
+		if word == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKeywordAliases reports whether the parser was given keyword aliases,
+// keeping the parse of the expressions without aliases unchanged
+func hasKeywordAliases(c *current) bool {
+	aliases, _ := c.globalStore[keywordAliasesKey].(map[string]string)
+	return len(aliases) > 0
+}
+
+// isKeywordAlias reports whether the word is an alias of the keyword
+func isKeywordAlias(c *current, word, keyword string) bool {
+	aliases, _ := c.globalStore[keywordAliasesKey].(map[string]string)
+	return aliases[word] == keyword
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordAliases(t *testing.T) {
+	t.Parallel()
+
+	french := KeywordAliases(map[string]string{
+		"et":          "and",
+		"ou":          "or",
+		"non":         "not",
+		"soit":        "let",
+		"dans":        "in",
+		"est":         "is",
+		"vide":        "empty",
+		"nul":         "null",
+		"contient":    "contains",
+		"correspond":  "matches",
+		"commencePar": "startswith",
+		"finitPar":    "endswith",
+		"comme":       "like",
+	})
+
+	type testCase struct {
+		input   string
+		english string
+		err     string
+	}
+
+	tests := map[string]testCase{
+		"and or": {
+			input:   `foo == 1 et bar == 2 ou baz == 3`,
+			english: `foo == 1 and bar == 2 or baz == 3`,
+		},
+		"not": {
+			input:   `non (foo == 1)`,
+			english: `not (foo == 1)`,
+		},
+		"in": {
+			input:   `"a" dans foo et "b" non dans bar`,
+			english: `"a" in foo and "b" not in bar`,
+		},
+		"empty and null": {
+			input:   `foo est vide ou bar est non nul`,
+			english: `foo is empty or bar is not null`,
+		},
+		"string operators": {
+			input:   `foo contient "a" et foo correspond "^a" et foo commencePar "a" et foo finitPar "a" et foo comme "a%"`,
+			english: `foo contains "a" and foo matches "^a" and foo startswith "a" and foo endswith "a" and foo like "a%"`,
+		},
+		"let": {
+			input:   `soit x = foo + 1 dans x > 2`,
+			english: `let x = foo + 1 in x > 2`,
+		},
+		"keywords still accepted": {
+			input:   `foo == 1 and bar == 2 ou baz == 3`,
+			english: `foo == 1 and bar == 2 or baz == 3`,
+		},
+		"alias as selector": {
+			input:   `et == 1 et ou == 2`,
+			english: `et == 1 and ou == 2`,
+		},
+		"alias of another keyword": {
+			input: `foo == 1 ou et bar == 2`,
+			err:   `1:19 (18): no match found, expected: [\pL\pN_]`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse("", []byte(tcase.input), french)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+
+			english, err := Parse("", []byte(tcase.english))
+			require.NoError(t, err)
+			require.Equal(t, english, expr)
+		})
+	}
+}
+
+func TestValidateKeywordAliases(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		aliases map[string]string
+		err     string
+	}
+
+	tests := map[string]testCase{
+		"valid":           {aliases: map[string]string{"und": "and", "oder": "or", "enthält": "contains", "ist_leer": "empty"}},
+		"unknown keyword": {aliases: map[string]string{"und": "und"}, err: `alias "und" of unknown keyword "und"`},
+		"keyword":         {aliases: map[string]string{"or": "and"}, err: `alias "or" of "and" is a keyword`},
+		"not a word":      {aliases: map[string]string{"&&": "and"}, err: `alias "&&" of "and" is not a word`},
+		"leading digit":   {aliases: map[string]string{"1und": "and"}, err: `alias "1und" of "and" is not a word`},
+		"empty":           {aliases: map[string]string{"": "and"}, err: `empty alias of "and"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateKeywordAliases(tcase.aliases)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
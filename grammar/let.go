@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+// InlineLets returns an equivalent expression without let expressions, the
+// references to the names they bind being replaced by the values bound. A
+// reference continuing a name bound to a selector, such as t.a with t bound to
+// Meta, is replaced by the selector continued the same way, Meta.a. Such
+// references to names bound to other values are left as is. The expression
+// given is not modified.
+func InlineLets(expr Expression) Expression {
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		return &UnaryExpression{Operator: node.Operator, Operand: InlineLets(node.Operand)}
+	case *BinaryExpression:
+		return &BinaryExpression{Operator: node.Operator, Left: InlineLets(node.Left), Right: InlineLets(node.Right)}
+	case *LetExpression:
+		// the inner let expressions are inlined first, as they shadow the
+		// names bound by the outer ones
+		return inlineLet(InlineLets(node.Body), node.Name, node.Value)
+	}
+	return expr
+}
+
+// inlineLet replaces the references to the name in an expression without let
+// expressions.
+func inlineLet(expr Expression, name string, value *ExpressionValue) Expression {
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		return &UnaryExpression{Operator: node.Operator, Operand: inlineLet(node.Operand, name, value)}
+	case *BinaryExpression:
+		return &BinaryExpression{Operator: node.Operator, Left: inlineLet(node.Left, name, value), Right: inlineLet(node.Right, name, value)}
+	case *MatchExpression:
+		return &MatchExpression{Operator: node.Operator, Left: inlineValue(node.Left, name, value), Right: inlineValue(node.Right, name, value), Position: node.Position}
+	case *ExpressionValue:
+		return inlineValue(node, name, value)
+	}
+	return expr
+}
+
+func inlineValue(expr *ExpressionValue, name string, value *ExpressionValue) *ExpressionValue {
+	if expr == nil {
+		return nil
+	}
+	if ref, ok := expr.Left.(*MatchValue); ok && expr.Operator == MathOpValue && isReference(ref, name) && len(ref.Selector.Path) == 1 {
+		return value
+	}
+	return &ExpressionValue{
+		Left:     inlineOperand(expr.Left, name, value),
+		Operator: expr.Operator,
+		Right:    inlineOperand(expr.Right, name, value),
+	}
+}
+
+func inlineOperand(operand interface{}, name string, value *ExpressionValue) interface{} {
+	switch node := operand.(type) {
+	case *ExpressionValue:
+		return inlineValue(node, name, value)
+	case *MatchValue:
+		if node.Type == ValueTypeCall {
+			args := make([]*ExpressionValue, len(node.Args))
+			for i, arg := range node.Args {
+				args[i] = inlineValue(arg, name, value)
+			}
+			return &MatchValue{Type: ValueTypeCall, Raw: node.Raw, Args: args}
+		}
+		if !isReference(node, name) {
+			return node
+		}
+		bound, isValue := value.Left.(*MatchValue)
+		isValue = isValue && value.Operator == MathOpValue
+		whole := len(node.Selector.Path) == 1 && len(node.Selector.Ranges) == 0
+		switch {
+		case whole && isValue:
+			return bound
+		case whole:
+			return value
+		case isValue && bound.Type == ValueTypeReflect && len(bound.Selector.Ranges) == 0:
+			// the references into a range bound, such as e.Type with e bound
+			// to Events[0:10], do not select the parts of each element of
+			// the range the way Events[0:10].Type does: they are not inlined
+			path := append(append([]string(nil), bound.Selector.Path...), node.Selector.Path[1:]...)
+			var ranges []SelectorRange
+			for _, r := range node.Selector.Ranges {
+				r.At += len(bound.Selector.Path) - 1
+				ranges = append(ranges, r)
+			}
+			return &MatchValue{Type: ValueTypeReflect, Selector: Selector{Type: bound.Selector.Type, Path: path, Anchored: bound.Selector.Anchored, Ranges: ranges}}
+		}
+	}
+	return operand
+}
+
+// isReference reports whether the value is a selector starting with the name
+func isReference(value *MatchValue, name string) bool {
+	return value.Type == ValueTypeReflect && !value.Selector.Anchored && len(value.Selector.Path) > 0 && value.Selector.Path[0] == name
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineLets(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		expected string
+	}
+
+	tests := map[string]testCase{
+		"No Let": {
+			input:    `foo == 3 and not bar in baz`,
+			expected: `foo == 3 and not bar in baz`,
+		},
+		"Selector": {
+			input:    `let t = meta.tier in t == "gold" or "gold" in t`,
+			expected: `meta.tier == "gold" or "gold" in meta.tier`,
+		},
+		"Continued Selector": {
+			input:    `let m = meta in m.tier == "gold" and m.zone is empty`,
+			expected: `meta.tier == "gold" and meta.zone is empty`,
+		},
+		"Math": {
+			input:    `let p = port + 1 in p > 80 and p < 90`,
+			expected: `port + 1 > 80 and port + 1 < 90`,
+		},
+		"Literal": {
+			input:    `let n = 3 in foo == n`,
+			expected: `foo == 3`,
+		},
+		"Shadowing": {
+			input:    `let t = foo in (let t = bar in t == 1) and t == 2`,
+			expected: `bar == 1 and foo == 2`,
+		},
+		"Outer Name In Inner Value": {
+			input:    `let m = meta in let t = m.tier in t == "gold"`,
+			expected: `meta.tier == "gold"`,
+		},
+		"Range": {
+			input:    `let e = meta.events in e[1:].type is not empty and e is not empty`,
+			expected: `meta.events[1:].type is not empty and meta.events is not empty`,
+		},
+		"Bound Range": {
+			input:    `let e = events[:2] in e is not empty and e.0 == "a"`,
+			expected: `events[:2] is not empty and e.0 == "a"`,
+		},
+		"Function": {
+			input:    `let m = meta in keys(m) contains "a" and "b" in values(m.tags)`,
+			expected: `keys(meta) contains "a" and "b" in values(meta.tags)`,
+		},
+		"Other Selectors": {
+			input:    `let t = foo in tier == 1 and t == 2`,
+			expected: `tier == 1 and foo == 2`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := Parse("", []byte(tcase.input))
+			require.NoError(t, err)
+			expected, err := Parse("", []byte(tcase.expected))
+			require.NoError(t, err)
+
+			require.Equal(t, expected, InlineLets(input.(Expression)))
+		})
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind is the lexical class of a Token
+type TokenKind int
+
+const (
+	// TokenInvalid is the kind of the characters which cannot start a token,
+	// such as # or a lone !, and of the unterminated strings
+	TokenInvalid TokenKind = iota
+	// TokenWhitespace is a run of spaces, tabs and newlines
+	TokenWhitespace
+	// TokenIdentifier is a part of a selector, such as Meta or the 0 of
+	// Tags.0, or a name bound by a let expression
+	TokenIdentifier
+	// TokenKeyword is a keyword, such as and or contains, or an alias of one,
+	// see KeywordAliases
+	TokenKeyword
+	// TokenString is a double quoted or raw string. The double quoted strings
+	// starting with a slash are JSON Pointer selectors in the bexpr dialect,
+	// such as "/Meta/env" in `"/Meta/env" == "prod"`.
+	TokenString
+	// TokenNumber is an integer or floating point number, such as -1 or 2.5
+	TokenNumber
+	// TokenBool is true or false
+	TokenBool
+	// TokenNull is null or undefined
+	TokenNull
+	// TokenParam is a parameter, such as $port
+	TokenParam
+	// TokenOperator is a comparison or math operator, or the = of let
+	// expressions
+	TokenOperator
+	// TokenPunctuation is a parenthesis, a bracket, the dot between the parts
+	// of selectors, the colon of their ranges, the $ anchoring selectors at
+	// the root of the datum, or the comma between the arguments of functions
+	TokenPunctuation
+	// TokenJSONPointer is a JSON Pointer selector written as is in the JSON
+	// Pointer dialect, such as /Meta/env
+	TokenJSONPointer
+	// TokenFunction is the name of a function called, such as the keys of
+	// keys(Meta), see Functions
+	TokenFunction
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenInvalid:
+		return "invalid"
+	case TokenWhitespace:
+		return "whitespace"
+	case TokenIdentifier:
+		return "identifier"
+	case TokenKeyword:
+		return "keyword"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenBool:
+		return "bool"
+	case TokenNull:
+		return "null"
+	case TokenParam:
+		return "parameter"
+	case TokenOperator:
+		return "operator"
+	case TokenPunctuation:
+		return "punctuation"
+	case TokenJSONPointer:
+		return "JSON Pointer"
+	case TokenFunction:
+		return "function"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Token is a token of an expression, see Tokenize
+type Token struct {
+	Kind TokenKind
+	// Text is the text of the token as written in the expression
+	Text string
+	// Value is the literal the token stands for: the unquoted string of
+	// strings, the name of parameters without the $ and the keyword aliased
+	// by keyword aliases. It is the text of the other tokens.
+	Value string
+	// Position is where the token starts
+	Position Position
+}
+
+// Tokenize splits the expression into the tokens it is made of, so that
+// editors can highlight expressions without parsing them. The tokens cover
+// the whole expression, including whitespace, and are returned for invalid
+// expressions too, the text which is not a token making TokenInvalid tokens.
+// Tokenize honors the Dialect and KeywordAliases options, and ignores the
+// other ones. Tokens being lexical, the expressions split into tokens
+// without error can still fail to parse.
+func Tokenize(expression []byte, opts ...Option) []Token {
+	p := newParser("", expression, opts...)
+	aliases, _ := p.cur.globalStore[keywordAliasesKey].(map[string]string)
+	dialect, _ := p.cur.globalStore[selectorDialectKey].(SelectorDialect)
+	l := &lexer{src: string(expression), dialect: dialect, aliases: aliases, line: 1, col: 1}
+	for l.offset < len(l.src) {
+		l.next()
+	}
+	return l.tokens
+}
+
+type lexer struct {
+	src     string
+	dialect SelectorDialect
+	aliases map[string]string
+	tokens  []Token
+
+	// the position of the next token
+	offset, line, col int
+}
+
+// next appends the token starting at the current offset
+func (l *lexer) next() {
+	rest := l.src[l.offset:]
+	r, _ := utf8.DecodeRuneInString(rest)
+	switch {
+	case isSpace(r):
+		l.emit(TokenWhitespace, len(rest)-len(strings.TrimLeft(rest, " \t\r\n")), "")
+	case r == '"' || r == '`':
+		l.quoted(rest, r)
+	case r == '\'' && l.dialect == SelectorDialectJSONPath:
+		l.quoted(rest, r)
+	case r >= '0' && r <= '9' && l.afterDot():
+		l.emit(TokenIdentifier, scan(rest, 0, isDigit), "")
+	case r >= '0' && r <= '9', r == '-' && len(rest) > 1 && isDigit(rune(rest[1])) && !l.afterValue():
+		l.number(rest)
+	case r == '$' && len(rest) > 1 && isASCIILetter(rune(rest[1])):
+		n := scan(rest, 1, isIdentifierChar)
+		l.emit(TokenParam, n, rest[1:n])
+	case r == '/' && l.dialect == SelectorDialectJSONPointer && !l.afterValue():
+		n := 0
+		for n < len(rest) && rest[n] == '/' {
+			n = scan(rest, n+1, func(r rune) bool { return !isSpace(r) && !strings.ContainsRune(`/()"=!<>`, r) })
+		}
+		l.emit(TokenJSONPointer, n, "")
+	case unicode.IsLetter(r) || (r == '_' && l.dialect == SelectorDialectJSONPath && l.afterDot()):
+		l.word(rest)
+	case strings.HasPrefix(rest, "=="), strings.HasPrefix(rest, "!="), strings.HasPrefix(rest, "<="), strings.HasPrefix(rest, ">="):
+		l.emit(TokenOperator, 2, "")
+	case strings.ContainsRune("<>+-*/=", r):
+		l.emit(TokenOperator, 1, "")
+	case strings.ContainsRune("()[].$:,", r):
+		l.emit(TokenPunctuation, 1, "")
+	default:
+		_, size := utf8.DecodeRuneInString(rest)
+		l.emit(TokenInvalid, size, "")
+	}
+}
+
+// quoted appends the string starting with the quote, or an invalid token
+// running to the end of the expression if it is unterminated
+func (l *lexer) quoted(rest string, quote rune) {
+	end := -1
+	for i := 1; i < len(rest); i++ {
+		if quote == '\'' && rest[i] == '\\' {
+			i++
+			continue
+		}
+		if rune(rest[i]) == quote {
+			end = i + 1
+			break
+		}
+	}
+	if end == -1 {
+		l.emit(TokenInvalid, len(rest), "")
+		return
+	}
+	var value string
+	var err error
+	if quote == '\'' {
+		value, err = unquoteJSONPathString(rest[:end])
+	} else {
+		value, err = strconv.Unquote(rest[:end])
+	}
+	if err != nil {
+		l.emit(TokenInvalid, end, "")
+		return
+	}
+	l.emit(TokenString, end, value)
+}
+
+// number appends the integer or floating point number
+func (l *lexer) number(rest string) {
+	n := 0
+	if rest[0] == '-' {
+		n++
+	}
+	n = scan(rest, n, isDigit)
+	if n+1 < len(rest) && rest[n] == '.' && isDigit(rune(rest[n+1])) {
+		n = scan(rest, n+1, isDigit)
+	}
+	l.emit(TokenNumber, n, "")
+}
+
+// word appends the keyword, literal or identifier starting with a letter
+func (l *lexer) word(rest string) {
+	if l.afterDot() {
+		// member names, which can be keywords, such as Meta.in
+		if l.dialect == SelectorDialectJSONPath {
+			l.emit(TokenIdentifier, scan(rest, 0, isWordChar), "")
+			return
+		}
+		n := scan(rest, 0, isIdentifierChar)
+		l.emit(kindOf(rest, n), n, "")
+		return
+	}
+	n := scan(rest, 0, isWordChar)
+	word := rest[:n]
+	switch {
+	case word == "true" || word == "false":
+		l.emit(TokenBool, n, "")
+	case word == "null" || word == "undefined":
+		l.emit(TokenNull, n, "")
+	case isKeyword(word):
+		l.emit(TokenKeyword, n, "")
+	case l.aliases[word] == "null":
+		l.emit(TokenNull, n, "null")
+	case l.aliases[word] != "":
+		l.emit(TokenKeyword, n, l.aliases[word])
+	case isFunction(word) && strings.HasPrefix(rest[n:], "("):
+		l.emit(TokenFunction, n, "")
+	default:
+		if m := scan(rest, 0, isIdentifierChar); m >= n {
+			l.emit(kindOf(rest, m), m, "")
+			return
+		}
+		// words with letters outside of ASCII, which are not identifiers
+		l.emit(TokenInvalid, n, "")
+	}
+}
+
+// kindOf returns TokenIdentifier if the first n bytes of the text are an
+// identifier, and TokenInvalid if they are not, such as for the words
+// starting with letters outside of ASCII
+func kindOf(text string, n int) TokenKind {
+	if n == 0 || !isASCIILetter(rune(text[0])) {
+		return TokenInvalid
+	}
+	return TokenIdentifier
+}
+
+// emit appends the token made of the next n bytes, and moves past it
+func (l *lexer) emit(kind TokenKind, n int, value string) {
+	if n == 0 {
+		_, n = utf8.DecodeRuneInString(l.src[l.offset:])
+		kind = TokenInvalid
+	}
+	text := l.src[l.offset : l.offset+n]
+	if value == "" && kind != TokenString {
+		value = text
+	}
+	l.tokens = append(l.tokens, Token{
+		Kind:     kind,
+		Text:     text,
+		Value:    value,
+		Position: Position{Offset: l.offset, Line: l.line, Column: l.col},
+	})
+	for _, r := range text {
+		if r == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+	}
+	l.offset += n
+}
+
+// last returns the last token which is not whitespace
+func (l *lexer) last() (Token, bool) {
+	for i := len(l.tokens) - 1; i >= 0; i-- {
+		if l.tokens[i].Kind != TokenWhitespace {
+			return l.tokens[i], true
+		}
+	}
+	return Token{}, false
+}
+
+// afterDot reports whether the next token directly follows the dot between
+// the parts of a selector
+func (l *lexer) afterDot() bool {
+	if len(l.tokens) == 0 {
+		return false
+	}
+	last := l.tokens[len(l.tokens)-1]
+	return last.Kind == TokenPunctuation && last.Text == "."
+}
+
+// afterValue reports whether the last token ends a value, so that the next
+// minus or slash is a math operator rather than the start of a negative
+// number or of a JSON Pointer
+func (l *lexer) afterValue() bool {
+	last, ok := l.last()
+	if !ok {
+		return false
+	}
+	switch last.Kind {
+	case TokenIdentifier, TokenString, TokenNumber, TokenBool, TokenNull, TokenParam, TokenJSONPointer:
+		return true
+	case TokenPunctuation:
+		return last.Text == ")" || last.Text == "]"
+	}
+	return false
+}
+
+// scan returns the offset of the first rune from the offset on which is not
+// accepted
+func scan(s string, offset int, accept func(rune) bool) int {
+	for offset < len(s) {
+		r, size := utf8.DecodeRuneInString(s[offset:])
+		if !accept(r) {
+			break
+		}
+		offset += size
+	}
+	return offset
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// isIdentifierChar accepts the characters of the identifiers after their
+// first letter
+func isIdentifierChar(r rune) bool {
+	return isASCIILetter(r) || isDigit(r) || r == '_' || r == '/'
+}
+
+// isWordChar accepts the characters of the words which can alias keywords,
+// and of the member names of JSONPaths
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
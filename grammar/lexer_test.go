@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		opts     []Option
+		expected []string
+	}
+
+	tests := map[string]testCase{
+		"match":        {input: `Meta.env == "prod"`, expected: []string{`identifier Meta`, `punctuation .`, `identifier env`, `operator ==`, `string "prod"`}},
+		"logical":      {input: `not (a and b) or c`, expected: []string{`keyword not`, `punctuation (`, `identifier a`, `keyword and`, `identifier b`, `punctuation )`, `keyword or`, `identifier c`}},
+		"literals":     {input: "a == true or b is null or c == undefined or d == `raw`", expected: []string{`identifier a`, `operator ==`, `bool true`, `keyword or`, `identifier b`, `keyword is`, `null null`, `keyword or`, `identifier c`, `operator ==`, `null undefined`, `keyword or`, `identifier d`, `operator ==`, "string `raw`"}},
+		"numbers":      {input: `a > -1 and b <= 2.5`, expected: []string{`identifier a`, `operator >`, `number -1`, `keyword and`, `identifier b`, `operator <=`, `number 2.5`}},
+		"math":         {input: `a-1 >= (b - -2) * c`, expected: []string{`identifier a`, `operator -`, `number 1`, `operator >=`, `punctuation (`, `identifier b`, `operator -`, `number -2`, `punctuation )`, `operator *`, `identifier c`}},
+		"indexes":      {input: `Tags.0 != Meta["a b"].in`, expected: []string{`identifier Tags`, `punctuation .`, `identifier 0`, `operator !=`, `identifier Meta`, `punctuation [`, `string "a b"`, `punctuation ]`, `punctuation .`, `identifier in`}},
+		"ranges":       {input: `Events[-2:].Type`, expected: []string{`identifier Events`, `punctuation [`, `number -2`, `punctuation :`, `punctuation ]`, `punctuation .`, `identifier Type`}},
+		"functions":    {input: `"a" in values(Meta) and keys == 1`, expected: []string{`string "a"`, `keyword in`, `function values`, `punctuation (`, `identifier Meta`, `punctuation )`, `keyword and`, `identifier keys`, `operator ==`, `number 1`}},
+		"anchored":     {input: `$.Meta.env == $env`, expected: []string{`punctuation $`, `punctuation .`, `identifier Meta`, `punctuation .`, `identifier env`, `operator ==`, `parameter $env`}},
+		"let":          {input: `let x = a/b + 1 in x > 2`, expected: []string{`keyword let`, `identifier x`, `operator =`, `identifier a/b`, `operator +`, `number 1`, `keyword in`, `identifier x`, `operator >`, `number 2`}},
+		"pointer":      {input: `"/Meta/env" == "prod"`, expected: []string{`string "/Meta/env"`, `operator ==`, `string "prod"`}},
+		"invalid":      {input: `a # b ! "c`, expected: []string{`identifier a`, `invalid #`, `identifier b`, `invalid !`, `invalid "c`}},
+		"non ascii":    {input: `clé == 1`, expected: []string{`invalid clé`, `operator ==`, `number 1`}},
+		"aliases":      {input: `a est non nul et b contient "x"`, opts: []Option{KeywordAliases(map[string]string{"et": "and", "non": "not", "est": "is", "nul": "null", "contient": "contains"})}, expected: []string{`identifier a`, `keyword est`, `keyword non`, `null nul`, `keyword et`, `identifier b`, `keyword contient`, `string "x"`}},
+		"json pointer": {input: `/Meta/a~1b / 2 == /x`, opts: []Option{Dialect(SelectorDialectJSONPointer)}, expected: []string{`JSON Pointer /Meta/a~1b`, `operator /`, `number 2`, `operator ==`, `JSON Pointer /x`}},
+		"jsonpath":     {input: `$.métadonnées['it\'s'][0] == 'a'`, opts: []Option{Dialect(SelectorDialectJSONPath)}, expected: []string{`punctuation $`, `punctuation .`, `identifier métadonnées`, `punctuation [`, `string 'it\'s'`, `punctuation ]`, `punctuation [`, `number 0`, `punctuation ]`, `operator ==`, `string 'a'`}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tokens := Tokenize([]byte(tcase.input), tcase.opts...)
+
+			var text strings.Builder
+			var actual []string
+			for _, token := range tokens {
+				text.WriteString(token.Text)
+				if token.Kind != TokenWhitespace {
+					actual = append(actual, token.Kind.String()+" "+token.Text)
+				}
+			}
+			require.Equal(t, tcase.input, text.String())
+			require.Equal(t, tcase.expected, actual)
+		})
+	}
+}
+
+func TestTokenize_Values(t *testing.T) {
+	t.Parallel()
+
+	tokens := Tokenize([]byte("soit x = $p in\n  x == \"a\\tb\""), KeywordAliases(map[string]string{"soit": "let"}))
+	require.Equal(t, []Token{
+		{Kind: TokenKeyword, Text: "soit", Value: "let", Position: Position{Offset: 0, Line: 1, Column: 1}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 4, Line: 1, Column: 5}},
+		{Kind: TokenIdentifier, Text: "x", Value: "x", Position: Position{Offset: 5, Line: 1, Column: 6}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 6, Line: 1, Column: 7}},
+		{Kind: TokenOperator, Text: "=", Value: "=", Position: Position{Offset: 7, Line: 1, Column: 8}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 8, Line: 1, Column: 9}},
+		{Kind: TokenParam, Text: "$p", Value: "p", Position: Position{Offset: 9, Line: 1, Column: 10}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 11, Line: 1, Column: 12}},
+		{Kind: TokenKeyword, Text: "in", Value: "in", Position: Position{Offset: 12, Line: 1, Column: 13}},
+		{Kind: TokenWhitespace, Text: "\n  ", Value: "\n  ", Position: Position{Offset: 14, Line: 1, Column: 15}},
+		{Kind: TokenIdentifier, Text: "x", Value: "x", Position: Position{Offset: 17, Line: 2, Column: 3}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 18, Line: 2, Column: 4}},
+		{Kind: TokenOperator, Text: "==", Value: "==", Position: Position{Offset: 19, Line: 2, Column: 5}},
+		{Kind: TokenWhitespace, Text: " ", Value: " ", Position: Position{Offset: 21, Line: 2, Column: 7}},
+		{Kind: TokenString, Text: "\"a\\tb\"", Value: "a\tb", Position: Position{Offset: 22, Line: 2, Column: 8}},
+	}, tokens)
+}
+
+// The positions of the tokens match the ones of the parse errors
+func TestTokenize_ErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	input := "a == 1 and\n  é == 2"
+	_, err := Parse("", []byte(input))
+	require.Error(t, err)
+	pos, ok := ErrorPosition(err)
+	require.True(t, ok)
+
+	for _, token := range Tokenize([]byte(input)) {
+		if token.Kind == TokenInvalid {
+			require.Equal(t, pos, token.Position)
+			return
+		}
+	}
+	t.Fatal("no invalid token")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import "fmt"
+
+const (
+	maxLiteralLengthKey = "maxLiteralLength"
+	maxLiteralBytesKey  = "maxLiteralBytes"
+	literalBytesKey     = "literalBytes"
+)
+
+// MaxLiteralLength creates an Option failing the parse on string literals,
+// including the ones indexing selectors, longer than maxLen bytes once
+// unquoted. The default for maxLen is 0, no limit.
+func MaxLiteralLength(maxLen int) Option {
+	return GlobalStore(maxLiteralLengthKey, maxLen)
+}
+
+// MaxLiteralBytes creates an Option failing the parse when the string
+// literals of the expression, including the ones indexing selectors, amount
+// to more than maxBytes bytes once unquoted. The default for maxBytes is 0,
+// no limit.
+func MaxLiteralBytes(maxBytes int) Option {
+	return GlobalStore(maxLiteralBytesKey, maxBytes)
+}
+
+// checkLiteralLimits checks the string literal just parsed against the limits
+// set with MaxLiteralLength and MaxLiteralBytes.
+func checkLiteralLimits(c *current, literal string) error {
+	if maxLen, _ := c.globalStore[maxLiteralLengthKey].(int); maxLen > 0 && len(literal) > maxLen {
+		return fmt.Errorf("String literal longer than %d bytes", maxLen)
+	}
+	maxBytes, _ := c.globalStore[maxLiteralBytesKey].(int)
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	// the parser backtracks, parsing some literals more than once: they are
+	// counted by offset
+	literals, _ := c.globalStore[literalBytesKey].(map[int]int)
+	if literals == nil {
+		literals = make(map[int]int)
+		c.globalStore[literalBytesKey] = literals
+	}
+	literals[c.pos.offset] = len(literal)
+	total := 0
+	for _, n := range literals {
+		total += n
+	}
+	if total > maxBytes {
+		return fmt.Errorf("String literals longer than %d bytes in total", maxBytes)
+	}
+	return nil
+}
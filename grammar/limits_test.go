@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiteralLimits(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input string
+		opts  []Option
+		err   string
+	}
+
+	tests := map[string]testCase{
+		"no limits": {
+			input: `foo == "abcdefghijklmnopqrstuvwxyz"`,
+		},
+		"length": {
+			input: `foo == "abcd" or bar == "abcdef"`,
+			opts:  []Option{MaxLiteralLength(5)},
+			err:   "1:25 (24): rule \"string\": String literal longer than 5 bytes",
+		},
+		"length within limit": {
+			input: `foo == "abcd" or bar == "abcde"`,
+			opts:  []Option{MaxLiteralLength(5)},
+		},
+		"length unquoted": {
+			input: `foo == "\x41\x42\x43"`,
+			opts:  []Option{MaxLiteralLength(3)},
+		},
+		"length of index": {
+			input: `foo["abcdef"] == 1`,
+			opts:  []Option{MaxLiteralLength(5)},
+			err:   "1:5 (4): rule \"string\": String literal longer than 5 bytes",
+		},
+		"total": {
+			input: `foo == "abc" or bar == "def" or baz == "ghi"`,
+			opts:  []Option{MaxLiteralBytes(8)},
+			err:   "1:40 (39): rule \"string\": String literals longer than 8 bytes in total",
+		},
+		"total within limit": {
+			input: `foo["abc"] == "def" or (bar == "gh")`,
+			opts:  []Option{MaxLiteralBytes(8)},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse("", []byte(tcase.input), tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, expr)
+		})
+	}
+}
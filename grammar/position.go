@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"errors"
+	"fmt"
+)
+
+const positionsKey = "positions"
+
+// Position is where a node of the AST starts within the expression it was
+// parsed from. The zero Position is the one of the nodes which were not
+// parsed with the Positions option, or were built rather than parsed.
+type Position struct {
+	// Offset is the offset in bytes, starting at 0
+	Offset int
+	// Line starts at 1
+	Line int
+	// Column is the offset in runes within the line, starting at 1
+	Column int
+}
+
+// IsValid reports whether the position was recorded while parsing
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the format of the parse errors, such as
+// 1:5 (4)
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d (%d)", p.Line, p.Column, p.Offset)
+}
+
+// Positions creates an Option recording the Position of the match and let
+// expressions in the AST. They are not recorded by default, so that the ASTs
+// of identical expressions are equal wherever they were parsed from.
+func Positions() Option {
+	return GlobalStore(positionsKey, true)
+}
+
+// nodePosition returns the position the current rule matched at, if
+// positions are recorded
+func nodePosition(c *current) Position {
+	if recorded, _ := c.globalStore[positionsKey].(bool); !recorded {
+		return Position{}
+	}
+	return Position{Offset: c.pos.offset, Line: c.pos.line, Column: c.pos.col}
+}
+
+// ErrorPosition returns the position of the first error reported by Parse
+func ErrorPosition(err error) (Position, bool) {
+	var list errList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return Position{}, false
+	}
+	var perr *parserError
+	if !errors.As(list[0], &perr) {
+		return Position{}, false
+	}
+	return Position{Offset: perr.pos.offset, Line: perr.pos.line, Column: perr.pos.col}, true
+}
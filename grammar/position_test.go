@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositions(t *testing.T) {
+	t.Parallel()
+
+	expr, err := Parse("", []byte("Name == \"a\" and\n  not (x in Tags) or\n\tlet p = \"é\" in p == Port and Meta is empty"), Positions())
+	require.NoError(t, err)
+
+	var positions []Position
+	var walk func(Expression)
+	walk = func(e Expression) {
+		switch node := e.(type) {
+		case *UnaryExpression:
+			walk(node.Operand)
+		case *BinaryExpression:
+			walk(node.Left)
+			walk(node.Right)
+		case *LetExpression:
+			positions = append(positions, node.Position)
+			walk(node.Body)
+		case *MatchExpression:
+			positions = append(positions, node.Position)
+		}
+	}
+	walk(expr.(Expression))
+	require.Equal(t, []Position{
+		{Offset: 0, Line: 1, Column: 1},
+		{Offset: 23, Line: 2, Column: 8},
+		{Offset: 38, Line: 3, Column: 2},
+		{Offset: 54, Line: 3, Column: 17},
+		{Offset: 68, Line: 3, Column: 31},
+	}, positions)
+	require.Equal(t, "3:17 (54)", positions[3].String())
+
+	// positions are not recorded by default
+	expr, err = Parse("", []byte(`Name == "a"`))
+	require.NoError(t, err)
+	require.False(t, expr.(*MatchExpression).Position.IsValid())
+}
+
+func TestErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse("", []byte("Name == \"a\" and\n  Port >"))
+	require.Error(t, err)
+	pos, ok := ErrorPosition(err)
+	require.True(t, ok)
+	require.Equal(t, Position{Offset: 24, Line: 2, Column: 9}, pos)
+
+	_, ok = ErrorPosition(errors.New("not a parse error"))
+	require.False(t, ok)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import "fmt"
+
+const reservedKeywordsKey = "reservedKeywords"
+
+// ReservedKeywords creates an Option reserving the keywords, and their aliases
+// given with KeywordAliases, so that they are never read as the first part of
+// selectors. By default, a field named like a keyword, such as "in" or
+// "empty", is read as a selector wherever the keyword does not fit, which
+// makes expressions such as `not is empty` fail to parse while `empty is
+// empty` does not. With this option a keyword is always a keyword, and fields
+// named like keywords are escaped with brackets, such as ["in"] == 1 or
+// ["empty"] is empty, or written as JSON Pointers, such as "/in" == 1. The
+// parts following the first one, such as in foo.in, are not ambiguous and are
+// never reserved.
+func ReservedKeywords() Option {
+	return GlobalStore(reservedKeywordsKey, true)
+}
+
+// isReservedWord reports whether the word cannot be read as a selector
+func isReservedWord(c *current, word string) bool {
+	if reserved, _ := c.globalStore[reservedKeywordsKey].(bool); !reserved {
+		return false
+	}
+	if isKeyword(word) {
+		return true
+	}
+	aliases, _ := c.globalStore[keywordAliasesKey].(map[string]string)
+	_, ok := aliases[word]
+	return ok
+}
+
+// reservedWordError fails the parse of values which are reserved words, once
+// they could not be parsed as anything else
+func reservedWordError(c *current, word string) error {
+	if !isReservedWord(c, word) {
+		return nil
+	}
+	return fmt.Errorf("%q is a reserved keyword, fields named %q are written [%q]", word, word, word)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservedKeywords(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		reserved bool
+		expected Expression
+		err      string
+	}
+
+	equal := func(path ...string) Expression {
+		return &MatchExpression{
+			Left:     &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: SelectorTypeBexpr, Path: path}, Type: ValueTypeReflect}},
+			Operator: MatchEqual,
+			Right:    &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeInt, Raw: "1"}},
+		}
+	}
+
+	tests := map[string]testCase{
+		"keyword selector":         {input: `and == 1`, expected: equal("and")},
+		"escaped":                  {input: `["and"] == 1`, expected: equal("and")},
+		"escaped reserved":         {input: `["and"] == 1`, reserved: true, expected: equal("and")},
+		"escaped with parts":       {input: `["in"].not["x y"] == 1`, reserved: true, expected: equal("in", "not", "x y")},
+		"json pointer reserved":    {input: `"/and" == 1`, reserved: true, expected: &MatchExpression{Left: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Selector: Selector{Type: SelectorTypeJsonPointer, Path: []string{"and"}}, Type: ValueTypeReflect}}, Operator: MatchEqual, Right: &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeInt, Raw: "1"}}}},
+		"later parts not reserved": {input: `foo.and == 1`, reserved: true, expected: equal("foo", "and")},
+		"ambiguous":                {input: `not is empty`, err: `1:8 (7): no match found, expected: "!=", "*", "+", "-", "/", "<", "<=", "==", ">", ">=", "and", "contains", "endswith", "in", "is", "like", "matches", "not", "or", "startswith", [ \t\r\n] or EOF`},
+		"reserved":                 {input: `empty == 1`, reserved: true, err: `1:6 (5): rule "value": "empty" is a reserved keyword, fields named "empty" are written ["empty"]`},
+		"reserved after operator":  {input: `foo == 1 or or == 1`, reserved: true, err: `1:15 (14): rule "value": "or" is a reserved keyword, fields named "or" are written ["or"]`},
+		"reserved prefix":          {input: `index == 1`, reserved: true, expected: equal("index")},
+		"invalid escape":           {input: `[1] == 1`, err: `1:2 (1): rule "index": Invalid index`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if tcase.reserved {
+				opts = append(opts, ReservedKeywords())
+			}
+			expr, err := Parse("", []byte(tcase.input), opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, expr)
+		})
+	}
+}
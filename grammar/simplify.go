@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+// Simplify returns an equivalent expression in a canonical form, so that
+// expressions differing only in the way their logic is written produce the
+// same AST:
+//
+//   - double negations are eliminated
+//   - negations are pushed down to the match expressions with De Morgan's
+//     laws, and negated match expressions are replaced by the match
+//     expression of the opposite operator, such as "!=" for "=="
+//   - nested chains of "and" and "or" are flattened and rebuilt nested to the
+//     right, the way the parser builds them
+//   - the true and false literals are removed from the chains they do not
+//     decide, and chains they decide are replaced by the literal
+//
+// The order of the operands is kept, as operands are evaluated in order.
+// Operands dropped from chains decided by a literal are not evaluated by the
+// simplified expression, so errors they would report are not reported
+// either. The expression given is not modified.
+func Simplify(expr Expression) Expression {
+	return simplify(expr, false)
+}
+
+// simplify returns the simplified expression, negated when negate is true
+func simplify(expr Expression, negate bool) Expression {
+	switch node := expr.(type) {
+	case *UnaryExpression:
+		if node.Operator == UnaryOpNot {
+			return simplify(node.Operand, !negate)
+		}
+
+	case *BinaryExpression:
+		op := node.Operator
+		if negate {
+			// De Morgan's laws
+			if op == BinaryOpAnd {
+				op = BinaryOpOr
+			} else {
+				op = BinaryOpAnd
+			}
+		}
+
+		var operands []Expression
+		for _, operand := range []Expression{node.Left, node.Right} {
+			operand = simplify(operand, negate)
+			if chain, ok := operand.(*BinaryExpression); ok && chain.Operator == op {
+				operands = append(operands, chainOperands(chain)...)
+			} else {
+				operands = append(operands, operand)
+			}
+		}
+
+		// the literal deciding the outcome of the chain on its own
+		absorbing := op == BinaryOpOr
+		kept := operands[:0]
+		for _, operand := range operands {
+			if value, ok := boolLiteral(operand); ok {
+				if value == absorbing {
+					return boolExpression(absorbing)
+				}
+				continue
+			}
+			kept = append(kept, operand)
+		}
+		if len(kept) == 0 {
+			return boolExpression(!absorbing)
+		}
+		return buildChain(op, kept)
+
+	case *LetExpression:
+		return &LetExpression{Name: node.Name, Value: node.Value, Body: simplify(node.Body, negate), Position: node.Position}
+
+	case *MatchExpression:
+		if !negate {
+			return node
+		}
+		if op, ok := negated(node.Operator); ok {
+			return &MatchExpression{Operator: op, Left: node.Left, Right: node.Right, Position: node.Position}
+		}
+
+	case *ExpressionValue:
+		if value, ok := boolLiteral(node); ok {
+			return boolExpression(value != negate)
+		}
+	}
+
+	if negate {
+		return &UnaryExpression{Operator: UnaryOpNot, Operand: expr}
+	}
+	return expr
+}
+
+// negated returns the operator whose outcome is always the opposite of the
+// operator's, including for missing values.
+func negated(op MatchOperator) (MatchOperator, bool) {
+	switch op {
+	case MatchEqual:
+		return MatchNotEqual, true
+	case MatchNotEqual:
+		return MatchEqual, true
+	case MatchIn:
+		return MatchNotIn, true
+	case MatchNotIn:
+		return MatchIn, true
+	case MatchIsEmpty:
+		return MatchIsNotEmpty, true
+	case MatchIsNotEmpty:
+		return MatchIsEmpty, true
+	case MatchMatches:
+		return MatchNotMatches, true
+	case MatchNotMatches:
+		return MatchMatches, true
+	case MatchIsNull:
+		return MatchIsNotNull, true
+	case MatchIsNotNull:
+		return MatchIsNull, true
+	case MatchStartsWith:
+		return MatchNotStartsWith, true
+	case MatchNotStartsWith:
+		return MatchStartsWith, true
+	case MatchEndsWith:
+		return MatchNotEndsWith, true
+	case MatchNotEndsWith:
+		return MatchEndsWith, true
+	case MatchLike:
+		return MatchNotLike, true
+	case MatchNotLike:
+		return MatchLike, true
+	default:
+		// the ordering operators are not negated: values which cannot be
+		// ordered, such as NaN, are neither lower nor higher or equal
+		return op, false
+	}
+}
+
+// chainOperands returns the operands of a chain nested to the right
+func chainOperands(chain *BinaryExpression) []Expression {
+	var operands []Expression
+	for {
+		operands = append(operands, chain.Left)
+		next, ok := chain.Right.(*BinaryExpression)
+		if !ok || next.Operator != chain.Operator {
+			return append(operands, chain.Right)
+		}
+		chain = next
+	}
+}
+
+// buildChain joins the operands with the operator, nested to the right
+func buildChain(op BinaryOperator, operands []Expression) Expression {
+	expr := operands[len(operands)-1]
+	for i := len(operands) - 2; i >= 0; i-- {
+		expr = &BinaryExpression{Operator: op, Left: operands[i], Right: expr}
+	}
+	return expr
+}
+
+// boolLiteral returns the value of the true and false literals
+func boolLiteral(expr Expression) (bool, bool) {
+	value, ok := expr.(*ExpressionValue)
+	if !ok || value.Operator != MathOpValue {
+		return false, false
+	}
+	literal, ok := value.Left.(*MatchValue)
+	if !ok || literal.Type != ValueTypeBool {
+		return false, false
+	}
+	return literal.Raw == "true", true
+}
+
+func boolExpression(value bool) Expression {
+	raw := "false"
+	if value {
+		raw = "true"
+	}
+	return &ExpressionValue{Operator: MathOpValue, Left: &MatchValue{Type: ValueTypeBool, Raw: raw}}
+}
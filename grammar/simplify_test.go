@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package grammar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplify(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		input    string
+		expected string
+	}
+
+	tests := map[string]testCase{
+		"Match": {
+			input:    `foo == 3`,
+			expected: `foo == 3`,
+		},
+		"Double Negation": {
+			input:    `not (not foo == 3)`,
+			expected: `foo == 3`,
+		},
+		"Negated Match": {
+			input:    `not foo == 3`,
+			expected: `foo != 3`,
+		},
+		"Negated Ordering": {
+			input:    `not foo < 3`,
+			expected: `not foo < 3`,
+		},
+		"Negated Operators": {
+			input:    `not (foo in bar or foo is empty or foo matches "x" or foo is null or foo startswith "x" or foo endswith "x" or foo like "x*")`,
+			expected: `foo not in bar and foo is not empty and foo not matches "x" and foo is not null and foo not startswith "x" and foo not endswith "x" and foo not like "x*"`,
+		},
+		"De Morgan And": {
+			input:    `not (foo == 3 and bar == 4)`,
+			expected: `foo != 3 or bar != 4`,
+		},
+		"De Morgan Or": {
+			input:    `not (foo == 3 or not bar < 4)`,
+			expected: `foo != 3 and bar < 4`,
+		},
+		"Flatten Left Nesting": {
+			input:    `((a == 1 and b == 2) and c == 3) and d == 4`,
+			expected: `a == 1 and b == 2 and c == 3 and d == 4`,
+		},
+		"Flatten After De Morgan": {
+			input:    `a == 1 and not (b == 2 or c == 3)`,
+			expected: `a == 1 and b != 2 and c != 3`,
+		},
+		"Keep Mixed Operators": {
+			input:    `(a == 1 or b == 2) and (c == 3 or d == 4)`,
+			expected: `(a == 1 or b == 2) and (c == 3 or d == 4)`,
+		},
+		"Remove True From And": {
+			input:    `a == 1 and true and b == 2`,
+			expected: `a == 1 and b == 2`,
+		},
+		"Remove False From Or": {
+			input:    `false or a == 1`,
+			expected: `a == 1`,
+		},
+		"False Decides And": {
+			input:    `a == 1 and (b == 2 and false)`,
+			expected: `false`,
+		},
+		"True Decides Or": {
+			input:    `a == 1 or true`,
+			expected: `true`,
+		},
+		"Negated Literal": {
+			input:    `a == 1 or not true`,
+			expected: `a == 1`,
+		},
+		"Only Literals": {
+			input:    `true and not false`,
+			expected: `true`,
+		},
+		"Negated Let": {
+			input:    `not (let t = foo in t == 1 or t == 2)`,
+			expected: `let t = foo in t != 1 and t != 2`,
+		},
+		"Decided Nested Chain": {
+			input:    `a == 1 and (b == 2 or true)`,
+			expected: `a == 1`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := Parse("", []byte(tcase.input))
+			require.NoError(t, err)
+			expected, err := Parse("", []byte(tcase.expected))
+			require.NoError(t, err)
+
+			simplified := Simplify(input.(Expression))
+			require.Equal(t, expected, simplified)
+
+			// simplifying is idempotent and leaves the input untouched
+			require.Equal(t, simplified, Simplify(simplified))
+			reparsed, err := Parse("", []byte(tcase.input))
+			require.NoError(t, err)
+			require.Equal(t, reparsed, input)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Hash returns a stable digest of the expression of the evaluator once
+// normalized, to be used as a cache key or to deduplicate expressions,
+// including across restarts. Expressions share their hash when they parse to
+// the same AST once their let expressions are inlined, see grammar.InlineLets,
+// and they are simplified, see grammar.Simplify, up to:
+//
+//   - the syntax of their literals and selectors, such as 1.50 and 1.5, or
+//     Meta["env"] and "/Meta/env"
+//   - the order of the operands of "and" and "or", and of the operands of ==
+//     and !=
+//   - the side of the selectors compared with literals, such as 1 < a and
+//     a > 1
+//
+// Unlike the Fingerprint, which tells how an expression is evaluated, the
+// hash tells which datums it matches: expressions evaluating their operands
+// in other orders share their hash, although they can report different
+// errors, such as for a == 1 and a > "x" and its reverse.
+func (eval *Evaluator) Hash() string {
+	sum := sha256.Sum256([]byte(hashKey(grammar.Simplify(grammar.InlineLets(eval.ast)))))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashKey serializes the normalized expression, sorting the operands of
+// commutative operators
+func hashKey(ast grammar.Expression) string {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return "not(" + hashKey(node.Operand) + ")"
+	case *grammar.BinaryExpression:
+		var keys []string
+		for _, operand := range chainOperands(node, node.Operator) {
+			keys = append(keys, hashKey(operand))
+		}
+		sort.Strings(keys)
+		return node.Operator.String() + "(" + strings.Join(keys, ",") + ")"
+	case *grammar.LetExpression:
+		return "let(" + node.Name + "," + operandKey(node.Value) + "," + hashKey(node.Body) + ")"
+	case *grammar.MatchExpression:
+		op, left, right := node.Operator, operandKey(node.Left), operandKey(node.Right)
+		if mirrored, ok := mirroredOperators[op]; ok {
+			leftConstant, rightConstant := isConstant(node.Left), isConstant(node.Right)
+			if (leftConstant && !rightConstant) || (leftConstant == rightConstant && left > right) {
+				op, left, right = mirrored, right, left
+			}
+		}
+		return matchOperatorSyntax[op] + "(" + left + "," + right + ")"
+	case *grammar.ExpressionValue:
+		return "value(" + operandKey(node) + ")"
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluator_Hash(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		a, b string
+		same bool
+	}
+
+	tests := map[string]testCase{
+		"whitespace":         {a: `a == 1 and b == 2`, b: "(a==1)\n\tand b == 2", same: true},
+		"and order":          {a: `a == 1 and b == 2 and c == 3`, b: `c == 3 and (b == 2 and a == 1)`, same: true},
+		"or order":           {a: `a == 1 or b == 2`, b: `b == 2 or a == 1`, same: true},
+		"equality order":     {a: `a == b and c != 1`, b: `b == a and 1 != c`, same: true},
+		"mirrored":           {a: `1 < a and b >= 2`, b: `a > 1 and 2 <= b`, same: true},
+		"literals":           {a: "a == 1.50 and b == `x`", b: `a == 1.5 and b == "x"`, same: true},
+		"selectors":          {a: `Meta["env"] == "prod"`, b: `"/Meta/env" == "prod"`, same: true},
+		"negations":          {a: `not (a == 1 or b != 2)`, b: `a != 1 and b == 2`, same: true},
+		"let":                {a: `let t = Meta.tier in t == "gold"`, b: `Meta.tier == "gold"`, same: true},
+		"constants":          {a: `a == 1 + 1`, b: `a == 2`, same: true},
+		"operator":           {a: `a == 1`, b: `a != 1`},
+		"value":              {a: `a == 1`, b: `a == 2`},
+		"type":               {a: `a == 1`, b: `a == "1"`},
+		"float":              {a: `a == 1`, b: `a == 1.0`},
+		"logical operators":  {a: `a == 1 and b == 2`, b: `a == 1 or b == 2`},
+		"nesting":            {a: `(a == 1 or b == 2) and c == 3`, b: `a == 1 or b == 2 and c == 3`},
+		"ordering operands":  {a: `a < b`, b: `b < a`},
+		"math operands":      {a: `a + 1 == 2`, b: `1 + a == 2`},
+		"selector and value": {a: `a == "b"`, b: `a == b`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a, err := CreateEvaluator(tcase.a)
+			require.NoError(t, err)
+			b, err := CreateEvaluator(tcase.b)
+			require.NoError(t, err)
+			if tcase.same {
+				require.Equal(t, a.Hash(), b.Hash())
+			} else {
+				require.NotEqual(t, a.Hash(), b.Hash())
+			}
+		})
+	}
+
+	// the hash is stable across releases
+	eval, err := CreateEvaluator(`Port > 80 and Name == "web"`)
+	require.NoError(t, err)
+	require.Equal(t, "fd3e458d945dc608d81e0b9971ecd4169a3e540af93c3f632feeb2831e5a71f2", eval.Hash())
+}
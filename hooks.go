@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeTyp           = reflect.TypeOf(time.Time{})
+	jsonRawMessageTyp = reflect.TypeOf(json.RawMessage{})
+	valuerTyp         = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// ChainHookFns returns a hook calling each of the hooks in order, the value
+// returned by one hook being passed to the next. Nil hooks are skipped.
+func ChainHookFns(fns ...ValueTransformationHookFn) ValueTransformationHookFn {
+	var chain []ValueTransformationHookFn
+	for _, fn := range fns {
+		if fn != nil {
+			chain = append(chain, fn)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	}
+	return func(v reflect.Value) reflect.Value {
+		for _, fn := range chain {
+			v = fn(v)
+			if !v.IsValid() {
+				// let pointerstructure report the invalid value
+				return v
+			}
+		}
+		return v
+	}
+}
+
+// selectorPattern matches selectors by a pattern such as "Meta" or "Meta.*",
+// where a "*" matches any single part of the selector. Patterns starting with
+// "/" are JSON Pointers.
+type selectorPattern struct {
+	pattern string
+	parts   []string
+}
+
+func newSelectorPattern(pattern string) selectorPattern {
+	var parts []string
+	if strings.HasPrefix(pattern, "/") {
+		parts = strings.Split(pattern[1:], "/")
+	} else {
+		parts = strings.Split(pattern, ".")
+	}
+	return selectorPattern{pattern: pattern, parts: parts}
+}
+
+// validate reports the patterns which match no selector: the empty pattern,
+// and the patterns with empty parts which are not JSON Pointers, such as
+// "Meta..env"
+func (p selectorPattern) validate() error {
+	if p.pattern == "" {
+		return errors.New("the pattern cannot be empty")
+	}
+	if strings.HasPrefix(p.pattern, "/") {
+		return nil
+	}
+	for _, part := range p.parts {
+		if part == "" {
+			return fmt.Errorf("the pattern %q has empty parts", p.pattern)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the pattern is a prefix of the path
+func (p selectorPattern) matches(path []string) bool {
+	if len(p.parts) > len(path) {
+		return false
+	}
+	for i, part := range p.parts {
+		if part != "*" && part != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// holds reports whether the path is a proper prefix of the pattern, the
+// value found at the path holding the values matching the pattern
+func (p selectorPattern) holds(path []string) bool {
+	if len(path) >= len(p.parts) {
+		return false
+	}
+	for i, part := range path {
+		if p.parts[i] != "*" && p.parts[i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+// selectorHook is a hook scoped to the values found at or below the selectors
+// matching a pattern.
+type selectorHook struct {
+	selectorPattern
+	fn ValueTransformationHookFn
+}
+
+func newSelectorHook(pattern string, fn ValueTransformationHookFn) selectorHook {
+	return selectorHook{selectorPattern: newSelectorPattern(pattern), fn: fn}
+}
+
+// selectorHookFn returns the hook to resolve the selector path with. The
+// selector hooks matching a prefix of the path only run on the values found
+// at or below that prefix. pointerstructure calls the hook once for each
+// part of the path, in order, so the returned hook must not be shared across
+// lookups.
+func selectorHookFn(path []string, hookFn ValueTransformationHookFn, selectorHooks []selectorHook) ValueTransformationHookFn {
+	if len(selectorHooks) == 0 {
+		return hookFn
+	}
+
+	// the hooks to run on the value found at each part of the path
+	byPart := make([][]ValueTransformationHookFn, len(path))
+	found := false
+	for _, hook := range selectorHooks {
+		if !hook.matches(path) {
+			continue
+		}
+		found = true
+		for i := len(hook.parts) - 1; i < len(path); i++ {
+			byPart[i] = append(byPart[i], hook.fn)
+		}
+	}
+	if !found {
+		return hookFn
+	}
+	chains := make([]ValueTransformationHookFn, len(path))
+	for i, fns := range byPart {
+		chains[i] = ChainHookFns(append([]ValueTransformationHookFn{hookFn}, fns...)...)
+	}
+
+	part := 0
+	return func(v reflect.Value) reflect.Value {
+		if part < len(chains) && chains[part] != nil {
+			v = chains[part](v)
+		}
+		part++
+		return v
+	}
+}
+
+// TimeHookFn returns a hook formatting time.Time values with the layout, so
+// that they can be compared against string literals.
+func TimeHookFn(layout string) ValueTransformationHookFn {
+	return func(v reflect.Value) reflect.Value {
+		e := hookElem(v)
+		if e.Kind() == reflect.Ptr && !e.IsNil() {
+			e = e.Elem()
+		}
+		if e.Type() != timeTyp || !e.CanInterface() {
+			return v
+		}
+		return reflect.ValueOf(e.Interface().(time.Time).Format(layout))
+	}
+}
+
+// JSONRawMessageHookFn decodes json.RawMessage values so that selectors can
+// descend into them. Numbers are decoded as json.Number and converted the
+// same way as in any other datum. Invalid JSON is left untouched.
+func JSONRawMessageHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if e.Type() != jsonRawMessageTyp || !e.CanInterface() {
+		return v
+	}
+	dec := json.NewDecoder(bytes.NewReader(e.Interface().(json.RawMessage)))
+	dec.UseNumber()
+	var decoded interface{}
+	if err := dec.Decode(&decoded); err != nil {
+		return v
+	}
+	if decoded == nil {
+		return reflect.Zero(interfaceTyp)
+	}
+	return reflect.ValueOf(decoded)
+}
+
+// NullHookFn unwraps values implementing driver.Valuer to the value they
+// hold. Values which are not valid become null. The nullable types of
+// database/sql are unwrapped before the hooks run, the ones which are not
+// valid being handled as missing values.
+func NullHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if !e.Type().Implements(valuerTyp) || !e.CanInterface() {
+		return v
+	}
+	if e.Kind() == reflect.Ptr && e.IsNil() {
+		return v
+	}
+	value, err := e.Interface().(driver.Valuer).Value()
+	if err != nil {
+		return v
+	}
+	if value == nil {
+		return reflect.Zero(interfaceTyp)
+	}
+	return reflect.ValueOf(value)
+}
+
+// BytesToStringHookFn converts byte slices into strings, for decoders which
+// represent strings as []byte, such as msgpack decoders configured to keep
+// raw strings as bytes.
+func BytesToStringHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if e.Kind() != reflect.Slice || e.Type().Elem().Kind() != reflect.Uint8 {
+		return v
+	}
+	return reflect.ValueOf(string(e.Bytes()))
+}
+
+// hookElem returns the value held by non-nil interfaces, such as the fields
+// of a struct declared as interface{}.
+func hookElem(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testNullTime is a driver.Valuer outside of database/sql, whose nullable
+// types are unwrapped before the hooks run
+type testNullTime struct {
+	sql.NullTime
+}
+
+func TestHookFns(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		Created  time.Time
+		Deleted  *time.Time
+		Raw      json.RawMessage
+		Any      interface{}
+		Name     sql.NullString
+		Age      sql.NullInt64
+		Verified testNullTime
+	}
+
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	datum := record{
+		Created:  created,
+		Deleted:  &created,
+		Raw:      json.RawMessage(`{"labels": {"env": "prod"}, "replicas": 3, "owner": null}`),
+		Any:      json.RawMessage(`["a", "b"]`),
+		Name:     sql.NullString{String: "web", Valid: true},
+		Verified: testNullTime{sql.NullTime{Time: created, Valid: true}},
+	}
+
+	upper := func(v reflect.Value) reflect.Value {
+		if v.Kind() == reflect.String {
+			return reflect.ValueOf(strings.ToUpper(v.String()))
+		}
+		return v
+	}
+
+	type testCase struct {
+		expression string
+		hooks      []ValueTransformationHookFn
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"time": {
+			expression: `Created == "2024-05-01T12:00:00Z"`,
+			hooks:      []ValueTransformationHookFn{TimeHookFn(time.RFC3339)},
+			result:     true,
+		},
+		"time pointer": {
+			expression: `Deleted startswith "2024-05-01"`,
+			hooks:      []ValueTransformationHookFn{TimeHookFn(time.RFC3339)},
+			result:     true,
+		},
+		"raw message": {
+			expression: `Raw.labels.env == "prod" and Raw.replicas > 2 and Raw.owner is null`,
+			hooks:      []ValueTransformationHookFn{JSONRawMessageHookFn},
+			result:     true,
+		},
+		"raw message in interface": {
+			expression: `"b" in Any`,
+			hooks:      []ValueTransformationHookFn{JSONRawMessageHookFn},
+			result:     true,
+		},
+		"raw message without hook": {
+			expression: `Raw.labels.env == "prod"`,
+			err:        `1:1 (0): Raw.labels.env == "prod": error finding value in datum: /Raw/labels/env at part 1: couldn't convert value "labels" to type int`,
+		},
+		"null valid": {
+			expression: `Name == "web"`,
+			hooks:      []ValueTransformationHookFn{NullHookFn},
+			result:     true,
+		},
+		"null invalid": {
+			expression: `Age is null and Age != 0`,
+			hooks:      []ValueTransformationHookFn{NullHookFn},
+			result:     true,
+		},
+		"null then time": {
+			expression: `Verified == "2024-05-01"`,
+			hooks:      []ValueTransformationHookFn{NullHookFn, TimeHookFn("2006-01-02")},
+			result:     true,
+		},
+		"time then null": {
+			expression: `Verified == "2024-05-01"`,
+			hooks:      []ValueTransformationHookFn{TimeHookFn("2006-01-02"), NullHookFn},
+			err:        `1:1 (0): Verified == "2024-05-01": operator "Equal" cannot be used with values of type time.Time`,
+		},
+		"chain order": {
+			expression: `Name == "WEB"`,
+			hooks:      []ValueTransformationHookFn{NullHookFn, nil, upper},
+			result:     true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// evaluations never mutate the datum, whatever the hooks
+			opts := []Option{WithMutationCheck()}
+			for _, hook := range tcase.hooks {
+				opts = append(opts, WithHookFn(hook))
+			}
+			eval, err := CreateEvaluator(tcase.expression, opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+
+			eval, err = CreateEvaluator(tcase.expression, WithHookFn(ChainHookFns(tcase.hooks...)))
+			require.NoError(t, err)
+			result, err = eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestChainHookFns(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, ChainHookFns())
+	require.Nil(t, ChainHookFns(nil, nil))
+
+	invalid := func(v reflect.Value) reflect.Value { return reflect.Value{} }
+	called := false
+	after := func(v reflect.Value) reflect.Value {
+		called = true
+		return v
+	}
+	require.False(t, ChainHookFns(invalid, after)(reflect.ValueOf(1)).IsValid())
+	require.False(t, called)
+}
+
+func TestWithSelectorHook(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Meta": map[string]interface{}{
+			"labels": json.RawMessage(`{"env": "prod"}`),
+			"name":   "web",
+		},
+		"Spec": map[string]interface{}{
+			"labels":   json.RawMessage(`{"env": "dev"}`),
+			"replicas": 3,
+		},
+	}
+
+	type testCase struct {
+		expression string
+		pattern    string
+		result     bool
+		calls      int
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"prefix": {
+			expression: `Meta.labels.env == "prod"`,
+			pattern:    "Meta",
+			result:     true,
+			calls:      3,
+		},
+		"wildcard": {
+			expression: `Meta.labels.env == "prod" and Spec.replicas == 3`,
+			pattern:    "Meta.*",
+			result:     true,
+			calls:      2,
+		},
+		"leading wildcard": {
+			expression: `Meta.labels.env == "prod" and Spec.labels.env == "dev" and Meta.name == "web"`,
+			pattern:    "*.labels",
+			result:     true,
+			calls:      4,
+		},
+		"json pointer": {
+			expression: `"/Spec/labels/env" == "dev"`,
+			pattern:    "/Spec/labels",
+			result:     true,
+			calls:      2,
+		},
+		"not matching": {
+			expression: `Spec.labels.env == "dev"`,
+			pattern:    "Meta.*",
+			err:        `1:1 (0): Spec.labels.env == "dev": error finding value in datum: /Spec/labels/env at part 2: couldn't convert value "env" to type int`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls := 0
+			hook := func(v reflect.Value) reflect.Value {
+				calls++
+				return JSONRawMessageHookFn(v)
+			}
+			eval, err := CreateEvaluator(tcase.expression, WithSelectorHook(tcase.pattern, hook))
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				require.Zero(t, calls)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+			require.Equal(t, tcase.calls, calls)
+		})
+	}
+}
+
+func TestWithSelectorHook_Order(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	record := func(name string) ValueTransformationHookFn {
+		return func(v reflect.Value) reflect.Value {
+			calls = append(calls, name)
+			return v
+		}
+	}
+
+	eval, err := CreateEvaluator(`A.B == 1`,
+		WithSelectorHook("A.B", record("selector 1")),
+		WithHookFn(record("global")),
+		WithSelectorHook("A", record("selector 2")),
+	)
+	require.NoError(t, err)
+
+	result, err := eval.Evaluate(map[string]interface{}{"A": map[string]interface{}{"B": 1}})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+	require.Equal(t, []string{"global", "selector 2", "global", "selector 1", "selector 2"}, calls)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"reflect"
+)
+
+var ipTyp = reflect.TypeOf(net.IP{})
+
+// ipAddr is implemented by netip.Addr, which is not imported to keep
+// supporting the Go versions predating it
+type ipAddr interface {
+	As16() [16]byte
+	IsValid() bool
+}
+
+var ipAddrTyp = reflect.TypeOf((*ipAddr)(nil)).Elem()
+
+// isIPType reports whether the values of the type are IP addresses compared
+// numerically
+func isIPType(typ reflect.Type) bool {
+	return typ == ipTyp || typ.Implements(ipAddrTyp)
+}
+
+// asIP returns the 16-byte form of the IP address, IPv4 addresses being
+// mapped to IPv6 the way net.IP.To16 does
+func asIP(value interface{}) (net.IP, bool) {
+	switch v := value.(type) {
+	case net.IP:
+		ip := v.To16()
+		return ip, ip != nil
+	case ipAddr:
+		if !v.IsValid() {
+			return nil, false
+		}
+		ip := v.As16()
+		return net.IP(ip[:]), true
+	}
+	return nil, false
+}
+
+// ipComparer compares IP addresses numerically, rather than as strings where
+// "10.0.0.9" is higher than "10.0.0.10", with other addresses and with the
+// strings they parse from.
+type ipComparer net.IP
+
+func (ip ipComparer) Compare(other interface{}) (int, error) {
+	o, ok := asIP(other)
+	if !ok {
+		if s, isString := other.(string); isString {
+			o = net.ParseIP(s)
+		}
+		if o == nil {
+			return 0, fmt.Errorf("%v is not an IP address", other)
+		}
+	}
+	return bytes.Compare(ip, o.To16()), nil
+}
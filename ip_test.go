@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+// testAddr has the shape of netip.Addr
+type testAddr struct {
+	ip net.IP
+}
+
+func (a testAddr) As16() [16]byte {
+	var b [16]byte
+	copy(b[:], a.ip.To16())
+	return b
+}
+
+func (a testAddr) IsValid() bool {
+	return a.ip != nil
+}
+
+func TestIPComparison(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Client":  net.ParseIP("10.0.0.9"),
+		"Gateway": net.ParseIP("10.0.0.10"),
+		"V4":      net.IPv4(192, 168, 1, 1).To4(),
+		"V6":      net.ParseIP("2001:db8::1"),
+		"Addr":    testAddr{ip: net.ParseIP("10.0.0.9")},
+		"Invalid": testAddr{},
+		"Missing": net.IP(nil),
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equal":              {expression: `Client == "10.0.0.9"`, result: true},
+		"not equal":          {expression: `Client != "10.0.0.9"`, result: false},
+		"numeric ordering":   {expression: `Client < "10.0.0.10"`, result: true},
+		"higher":             {expression: `Client > "10.0.0.10"`, result: false},
+		"lower or equal":     {expression: `Gateway <= "10.0.0.10"`, result: true},
+		"higher or equal":    {expression: `Gateway >= "10.0.1.0"`, result: false},
+		"fields":             {expression: `Client < Gateway`, result: true},
+		"literal on left":    {expression: `"10.0.0.10" > Client`, result: true},
+		"4-byte form":        {expression: `V4 == "192.168.1.1"`, result: true},
+		"ipv6":               {expression: `V6 > "2001:db8::"`, result: true},
+		"ipv4 below ipv6":    {expression: `V4 < V6`, result: true},
+		"netip":              {expression: `Addr == "10.0.0.9"`, result: true},
+		"netip and net.IP":   {expression: `Addr < Gateway`, result: true},
+		"nil is not present": {expression: `Missing > "10.0.0.1"`, result: false},
+		"nil equals null":    {expression: `Missing == null`, result: true},
+		"not an address": {
+			expression: `Client == "localhost"`,
+			err:        `1:1 (0): Client == "localhost": error comparing 10.0.0.9 and localhost: localhost is not an IP address`,
+		},
+		"invalid netip": {
+			expression: `Invalid == "10.0.0.9"`,
+			err:        `1:1 (0): Invalid == "10.0.0.9": unable to find suitable primitive comparison function for matching bexpr.testAddr and string`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+
+	require.True(t, SupportsOperator(grammar.MatchLower, reflect.TypeOf(net.IP{})))
+	require.True(t, SupportsOperator(grammar.MatchEqual, reflect.TypeOf(testAddr{})))
+}
@@ -0,0 +1,480 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/pointerstructure"
+)
+
+// maxSchemaRefs bounds the number of references followed to resolve a single
+// schema, to detect reference cycles.
+const maxSchemaRefs = 32
+
+var (
+	float64Typ = reflect.TypeOf(float64(0))
+	int64Typ   = reflect.TypeOf(int64(0))
+	boolTyp    = reflect.TypeOf(false)
+	objectTyp  = reflect.TypeOf(map[string]interface{}{})
+)
+
+// jsonSchema is a Schema derived from a JSON Schema
+type jsonSchema struct {
+	// document holds the schema, for references to be resolved against
+	document interface{}
+	root     map[string]interface{}
+}
+
+// JSONSchema returns the Schema described by a JSON Schema document, so that
+// expressions written against a public data model can be validated without
+// access to the Go types it is decoded into.
+//
+// Selectors look up the properties of object schemas, along with the
+// properties of their allOf, anyOf and oneOf subschemas, and the items of
+// array schemas. Objects declaring properties only allow the selection of
+// other properties when additionalProperties allows them, while objects
+// declaring none allow any. Selected values are typed after the type of their
+// schema: string, integer (int64), number (float64), boolean, object
+// (map[string]interface{}) and array (a slice of the type of its items).
+// Values whose type is not restricted to one, other than null, are only typed
+// at evaluation time. References are only resolved within the document.
+func JSONSchema(document []byte) (Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON schema: %w", err)
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("JSON schema is not an object")
+	}
+	return &jsonSchema{document: doc, root: root}, nil
+}
+
+// OpenAPISchema returns the Schema described by the component schema with the
+// given name of an OpenAPI 3 document, or by the definition with that name of
+// a Swagger 2 document. The document must be JSON encoded. See JSONSchema.
+func OpenAPISchema(document []byte, name string) (Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAPI document: %w", err)
+	}
+	for _, parts := range [][]string{{"components", "schemas", name}, {"definitions", name}} {
+		ptr := pointerstructure.Pointer{Parts: parts}
+		if schema, err := ptr.Get(doc); err == nil {
+			root, ok := schema.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schema %q is not an object", name)
+			}
+			return &jsonSchema{document: doc, root: root}, nil
+		}
+	}
+	return nil, fmt.Errorf("OpenAPI document has no schema named %q", name)
+}
+
+func (s *jsonSchema) SelectorType(path []string) (reflect.Type, error) {
+	node, err := s.lookup(path)
+	switch {
+	case err != nil:
+		return nil, err
+	case node == nil:
+		return interfaceTyp, nil
+	}
+	return s.goType(node, 0)
+}
+
+// SelectorDoc reads the documentation of the field found at the path from the
+// "description" and "examples" keywords of its schema.
+func (s *jsonSchema) SelectorDoc(path []string) (FieldDoc, bool) {
+	node, err := s.lookup(path)
+	if err != nil || node == nil {
+		return FieldDoc{}, false
+	}
+	if node, err = s.resolve(node); err != nil {
+		return FieldDoc{}, false
+	}
+	doc := FieldDoc{}
+	doc.Description, _ = node["description"].(string)
+	examples, _ := node["examples"].([]interface{})
+	if example, ok := node["example"]; ok {
+		// the OpenAPI 3.0 keyword
+		examples = append(examples, example)
+	}
+	for _, example := range examples {
+		if str, ok := example.(string); ok {
+			doc.Examples = append(doc.Examples, str)
+		} else if encoded, err := json.Marshal(example); err == nil {
+			doc.Examples = append(doc.Examples, string(encoded))
+		}
+	}
+	return doc, doc.Description != "" || doc.Examples != nil
+}
+
+// lookup returns the schema of the values found at the path. A nil schema is
+// returned when the path can only be resolved against a datum.
+func (s *jsonSchema) lookup(path []string) (map[string]interface{}, error) {
+	node := s.root
+	for i, part := range path {
+		var err error
+		node, err = s.resolve(node)
+		if err != nil {
+			return nil, fmt.Errorf("%s at part %d: %w", s.pointer(path), i, err)
+		}
+
+		switch typ := s.schemaType(node); typ {
+		case "":
+			return nil, nil
+		case "object":
+			property, ok, err := s.property(node, part)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("%s at part %d: %w", s.pointer(path), i, err)
+			case !ok:
+				return nil, fmt.Errorf("%s at part %d: %w: property %q", s.pointer(path), i, pointerstructure.ErrNotFound, part)
+			case property == nil:
+				return nil, nil
+			}
+			node = property
+		case "array":
+			if _, err := strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("%s at part %d: invalid slice index %q", s.pointer(path), i, part)
+			}
+			items, ok := node["items"].(map[string]interface{})
+			if !ok {
+				return nil, nil
+			}
+			node = items
+		default:
+			return nil, fmt.Errorf("%s: at part %d, %w: %s", s.pointer(path), i, pointerstructure.ErrInvalidKind, typ)
+		}
+	}
+	return node, nil
+}
+
+func (s *jsonSchema) pointer(path []string) string {
+	ptr := pointerstructure.Pointer{Parts: path}
+	return ptr.String()
+}
+
+// resolve follows the references of the schema
+func (s *jsonSchema) resolve(node map[string]interface{}) (map[string]interface{}, error) {
+	for i := 0; i < maxSchemaRefs; i++ {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, nil
+		}
+		if !strings.HasPrefix(ref, "#") {
+			return nil, fmt.Errorf("unsupported schema reference %q", ref)
+		}
+		ptr, err := pointerstructure.Parse(ref[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schema reference %q: %w", ref, err)
+		}
+		target, err := ptr.Get(s.document)
+		if err != nil {
+			return nil, fmt.Errorf("unresolvable schema reference %q: %w", ref, err)
+		}
+		if node, ok = target.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("schema reference %q is not an object", ref)
+		}
+	}
+	return nil, fmt.Errorf("too many nested schema references")
+}
+
+// subschemas returns the resolved subschemas of the composition keyword
+func (s *jsonSchema) subschemas(node map[string]interface{}, keyword string) []map[string]interface{} {
+	list, _ := node[keyword].([]interface{})
+	var result []map[string]interface{}
+	for _, item := range list {
+		if sub, ok := item.(map[string]interface{}); ok {
+			if sub, err := s.resolve(sub); err == nil {
+				result = append(result, sub)
+			}
+		}
+	}
+	return result
+}
+
+// schemaType returns the type of the values valid against the schema, other
+// than null, or an empty string when they are not all of the same type.
+func (s *jsonSchema) schemaType(node map[string]interface{}) string {
+	switch typ := node["type"].(type) {
+	case string:
+		return typ
+	case []interface{}:
+		var result string
+		for _, t := range typ {
+			if t == "null" {
+				continue
+			}
+			name, ok := t.(string)
+			if !ok || result != "" {
+				return ""
+			}
+			result = name
+		}
+		return result
+	}
+
+	if _, ok := node["properties"]; ok {
+		return "object"
+	}
+	if _, ok := node["items"]; ok {
+		return "array"
+	}
+	for _, sub := range s.subschemas(node, "allOf") {
+		if typ := s.schemaType(sub); typ != "" {
+			return typ
+		}
+	}
+	var result string
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		for _, sub := range s.subschemas(node, keyword) {
+			typ := s.schemaType(sub)
+			if typ == "" || (result != "" && typ != result) {
+				return ""
+			}
+			result = typ
+		}
+	}
+	return result
+}
+
+// property returns the schema of the property of an object schema. The schema
+// is nil when the object allows any property.
+func (s *jsonSchema) property(node map[string]interface{}, name string) (map[string]interface{}, bool, error) {
+	if properties, ok := node["properties"].(map[string]interface{}); ok {
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			return property, true, nil
+		}
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		for _, sub := range s.subschemas(node, keyword) {
+			if property, ok, _ := s.property(sub, name); ok && property != nil {
+				return property, true, nil
+			}
+		}
+	}
+
+	switch additional := node["additionalProperties"].(type) {
+	case map[string]interface{}:
+		return additional, true, nil
+	case bool:
+		return nil, additional, nil
+	}
+	return nil, !s.declaresProperties(node), nil
+}
+
+// declaresProperties reports whether the object schema, or any of its
+// subschemas, declares properties.
+func (s *jsonSchema) declaresProperties(node map[string]interface{}) bool {
+	if _, ok := node["properties"]; ok {
+		return true
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		for _, sub := range s.subschemas(node, keyword) {
+			if s.declaresProperties(sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goType returns the type of the values of the schema
+func (s *jsonSchema) goType(node map[string]interface{}, depth int) (reflect.Type, error) {
+	node, err := s.resolve(node)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.schemaType(node) {
+	case "string":
+		return stringTyp, nil
+	case "integer":
+		return int64Typ, nil
+	case "number":
+		return float64Typ, nil
+	case "boolean":
+		return boolTyp, nil
+	case "object":
+		return objectTyp, nil
+	case "array":
+		items, ok := node["items"].(map[string]interface{})
+		if !ok || depth >= maxSchemaRefs {
+			return reflect.TypeOf([]interface{}{}), nil
+		}
+		elem, err := s.goType(items, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.SliceOf(elem), nil
+	}
+	return interfaceTyp, nil
+}
+
+// GenerateJSONSchema returns the JSON Schema describing the selectors which
+// can be used against values of the type of the given value, along with the
+// types of the values they select, for user interfaces to build filters
+// with. Struct fields are named after the tag set with WithTagName and
+// documented by their "doc" and "example" tags or by the documentation set
+// with WithFieldDocs, which takes precedence. The
+// fields denied with WithDeniedFields are left out, as are the fields which
+// cannot be selected, such as functions and channels. Recursive types are
+// described down to their first recurrence, below which any selector is
+// allowed.
+func GenerateJSONSchema(value interface{}, opts ...Option) ([]byte, error) {
+	o := getOpts(opts...)
+	g := &schemaGenerator{
+		tagName:  o.withTagName,
+		denied:   o.withDeniedFields,
+		docs:     o.withFieldDocs,
+		visiting: make(map[reflect.Type]bool),
+	}
+	schema := g.generate(reflect.TypeOf(value), nil)
+	if schema == nil {
+		return nil, fmt.Errorf("values of type %s cannot be selected", reflect.TypeOf(value))
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return json.Marshal(schema)
+}
+
+// schemaGenerator generates the JSON Schema of Go types
+type schemaGenerator struct {
+	tagName  string
+	denied   []selectorPattern
+	docs     FieldDocs
+	visiting map[reflect.Type]bool
+}
+
+// generate returns the documented schema of the type found at the path, or
+// nil when its values cannot be selected.
+func (g *schemaGenerator) generate(typ reflect.Type, path []string) map[string]interface{} {
+	schema := g.generateType(typ, path)
+	if schema != nil {
+		if doc, ok := g.docs.lookup(path); ok && len(path) > 0 {
+			addFieldDoc(schema, doc)
+		}
+	}
+	return schema
+}
+
+// addFieldDoc adds the documentation to the schema
+func addFieldDoc(schema map[string]interface{}, doc FieldDoc) {
+	if doc.Description != "" {
+		schema["description"] = doc.Description
+	}
+	if len(doc.Examples) > 0 {
+		schema["examples"] = doc.Examples
+	}
+}
+
+// generateType returns the schema of the type found at the path, or nil when
+// its values cannot be selected.
+func (g *schemaGenerator) generateType(typ reflect.Type, path []string) map[string]interface{} {
+	if typ == nil {
+		return map[string]interface{}{}
+	}
+
+	typ = derefType(typ)
+	switch kind := typ.Kind(); {
+	case kind == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case isFloatKind(kind):
+		return map[string]interface{}{"type": "number"}
+	case isNumberKind(kind):
+		return map[string]interface{}{"type": "integer"}
+	case kind == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case kind == reflect.Interface:
+		return map[string]interface{}{}
+	case kind == reflect.Slice, kind == reflect.Array:
+		schema := map[string]interface{}{"type": "array"}
+		if items := g.generate(typ.Elem(), g.child(path, "*")); items != nil {
+			schema["items"] = items
+		}
+		return schema
+	case kind == reflect.Map:
+		if !reflect.TypeOf("").ConvertibleTo(typ.Key()) && !isNumberKind(typ.Key().Kind()) {
+			return nil
+		}
+		schema := map[string]interface{}{"type": "object", "additionalProperties": false}
+		if values := g.generate(typ.Elem(), g.child(path, "*")); values != nil {
+			schema["additionalProperties"] = values
+		}
+		return schema
+	case kind == reflect.Struct:
+		if g.visiting[typ] {
+			return map[string]interface{}{"type": "object"}
+		}
+		g.visiting[typ] = true
+		defer delete(g.visiting, typ)
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           g.properties(typ, path),
+			"additionalProperties": false,
+		}
+	}
+	return nil
+}
+
+// properties returns the schemas of the fields of the struct, named the same
+// way pointerstructure looks them up.
+func (g *schemaGenerator) properties(typ reflect.Type, path []string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	tagged := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		tag := field.Tag.Get(g.tagName)
+		if idx := strings.Index(tag, ","); idx != -1 {
+			tag = tag[0:idx]
+		}
+		name := field.Name
+		switch {
+		case tag == "-":
+			continue
+		case tag != "":
+			name = tag
+		case tagged[name]:
+			// fields tagged with the name of another field take precedence
+			continue
+		}
+
+		fieldPath := g.child(path, name)
+		if g.isDenied(fieldPath) {
+			continue
+		}
+		if schema := g.generate(field.Type, fieldPath); schema != nil {
+			if _, ok := g.docs.lookup(fieldPath); !ok {
+				if doc, ok := structFieldDoc(field); ok {
+					addFieldDoc(schema, doc)
+				}
+			}
+			properties[name] = schema
+			tagged[name] = tag != ""
+		}
+	}
+	return properties
+}
+
+func (g *schemaGenerator) child(path []string, part string) []string {
+	return append(path[:len(path):len(path)], part)
+}
+
+func (g *schemaGenerator) isDenied(path []string) bool {
+	for _, pattern := range g.denied {
+		if pattern.matches(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPIDocument = `{
+	"openapi": "3.0.3",
+	"components": {
+		"schemas": {
+			"Service": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"port": {"type": "integer", "format": "int32"},
+					"weight": {"type": "number", "nullable": true},
+					"enabled": {"type": "boolean"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"meta": {"type": "object", "additionalProperties": {"type": "string"}},
+					"extra": {"type": "object"},
+					"owner": {"$ref": "#/components/schemas/Owner"},
+					"checks": {"type": "array", "items": {"$ref": "#/components/schemas/Check"}},
+					"node": {
+						"allOf": [
+							{"$ref": "#/components/schemas/Owner"},
+							{"properties": {"datacenter": {"type": "string"}}}
+						]
+					},
+					"address": {"type": ["string", "null"]},
+					"value": {"oneOf": [{"type": "string"}, {"type": "integer"}]},
+					"loop": {"$ref": "#/components/schemas/Loop"},
+					"remote": {"$ref": "other.json#/Remote"}
+				}
+			},
+			"Owner": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"}
+				}
+			},
+			"Check": {
+				"properties": {
+					"status": {"type": "string"},
+					"interval": {"type": "integer"}
+				}
+			},
+			"Loop": {"$ref": "#/components/schemas/Loop"}
+		}
+	}
+}`
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := OpenAPISchema([]byte(testOpenAPIDocument), "Service")
+	require.NoError(t, err)
+
+	type testCase struct {
+		path []string
+		typ  reflect.Type
+		err  string
+	}
+
+	tests := map[string]testCase{
+		"string":                  {path: []string{"name"}, typ: stringTyp},
+		"integer":                 {path: []string{"port"}, typ: int64Typ},
+		"number":                  {path: []string{"weight"}, typ: float64Typ},
+		"boolean":                 {path: []string{"enabled"}, typ: boolTyp},
+		"array":                   {path: []string{"tags"}, typ: reflect.TypeOf([]string{})},
+		"array item":              {path: []string{"tags", "0"}, typ: stringTyp},
+		"array index":             {path: []string{"tags", "x"}, err: `/tags/x at part 1: invalid slice index "x"`},
+		"additional properties":   {path: []string{"meta", "anything"}, typ: stringTyp},
+		"object":                  {path: []string{"meta"}, typ: objectTyp},
+		"free-form object":        {path: []string{"extra", "anything", "else"}, typ: interfaceTyp},
+		"reference":               {path: []string{"owner", "name"}, typ: stringTyp},
+		"array of references":     {path: []string{"checks", "0", "interval"}, typ: int64Typ},
+		"array of objects":        {path: []string{"checks"}, typ: reflect.TypeOf([]map[string]interface{}{})},
+		"all of":                  {path: []string{"node", "datacenter"}, typ: stringTyp},
+		"all of reference":        {path: []string{"node", "name"}, typ: stringTyp},
+		"nullable type":           {path: []string{"address"}, typ: stringTyp},
+		"mixed types":             {path: []string{"value"}, typ: interfaceTyp},
+		"unknown property":        {path: []string{"nmae"}, err: `/nmae at part 0: couldn't find key: property "nmae"`},
+		"unknown nested property": {path: []string{"owner", "email"}, err: `/owner/email at part 1: couldn't find key: property "email"`},
+		"selector into primitive": {path: []string{"name", "first"}, err: `/name/first: at part 1, invalid value kind: string`},
+		"reference cycle":         {path: []string{"loop", "x"}, err: `/loop/x at part 1: too many nested schema references`},
+		"remote reference":        {path: []string{"remote", "x"}, err: `/remote/x at part 1: unsupported schema reference "other.json#/Remote"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			typ, err := schema.SelectorType(tcase.path)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.typ, typ)
+		})
+	}
+}
+
+func TestJSONSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	schema, err := JSONSchema([]byte(`{
+		"$defs": {"port": {"type": "integer", "minimum": 0}},
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"ports": {"type": "array", "items": {"$ref": "#/$defs/port"}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	}`))
+	require.NoError(t, err)
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"valid": {
+			expression: `name == "web" and 80 in ports and labels.env == "prod" and "env" in labels`,
+		},
+		"unknown selector": {
+			expression: `nmae == "web"`,
+			err:        `error finding value in schema: /nmae at part 0: couldn't find key: property "nmae" (did you mean name?)`,
+		},
+		"unsupported operator": {
+			expression: `name > 3`,
+			err:        `name: operator "Higher" cannot be used with values of type string`,
+		},
+		"operator on array": {
+			expression: `ports == 3`,
+			err:        `ports: operator "Equal" cannot be used with values of type []int64`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(schema))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestJSONSchema_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := JSONSchema([]byte(`[]`))
+	require.EqualError(t, err, "JSON schema is not an object")
+	_, err = JSONSchema([]byte(`{`))
+	require.EqualError(t, err, "failed to decode JSON schema: unexpected end of JSON input")
+	_, err = OpenAPISchema([]byte(testOpenAPIDocument), "Missing")
+	require.EqualError(t, err, `OpenAPI document has no schema named "Missing"`)
+	_, err = OpenAPISchema([]byte(`{"definitions": {"Service": {"type": "object"}}}`), "Service")
+	require.NoError(t, err)
+}
+
+type testSchemaOwner struct {
+	Name    string
+	Manager *testSchemaOwner
+}
+
+type testSchemaService struct {
+	Name       string
+	Port       int `bexpr:"port"`
+	Weight     *float64
+	Enabled    bool
+	Tags       []string
+	Meta       map[string]string
+	Checks     []struct{ Status string }
+	Secret     string `bexpr:"-"`
+	Internal   struct{ Token, Region string }
+	Owner      *testSchemaOwner
+	Any        interface{}
+	Callback   func()
+	Updates    chan int
+	unexported int
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		value    interface{}
+		opts     []Option
+		expected string
+		err      string
+	}
+
+	tests := map[string]testCase{
+		"struct": {
+			value: testSchemaService{},
+			opts:  []Option{WithDeniedFields("Internal.Token", "Checks.*.Status")},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {
+					"Name": {"type": "string"},
+					"port": {"type": "integer"},
+					"Weight": {"type": "number"},
+					"Enabled": {"type": "boolean"},
+					"Tags": {"type": "array", "items": {"type": "string"}},
+					"Meta": {"type": "object", "additionalProperties": {"type": "string"}},
+					"Checks": {"type": "array", "items": {"type": "object", "additionalProperties": false, "properties": {}}},
+					"Internal": {"type": "object", "additionalProperties": false, "properties": {
+						"Region": {"type": "string"}
+					}},
+					"Owner": {"type": "object", "additionalProperties": false, "properties": {
+						"Name": {"type": "string"},
+						"Manager": {"type": "object"}
+					}},
+					"Any": {}
+				}
+			}`,
+		},
+		"tag name": {
+			value: struct {
+				Name  string `json:"name"`
+				Other string `json:"-"`
+			}{},
+			opts: []Option{WithTagName("json")},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"additionalProperties": false,
+				"properties": {"name": {"type": "string"}}
+			}`,
+		},
+		"denied prefix": {
+			value: map[string]testSchemaOwner{},
+			opts:  []Option{WithDeniedFields("*.Manager")},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"additionalProperties": {"type": "object", "additionalProperties": false, "properties": {
+					"Name": {"type": "string"}
+				}}
+			}`,
+		},
+		"slice": {
+			value: []int{},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "array",
+				"items": {"type": "integer"}
+			}`,
+		},
+		"nil": {
+			value:    nil,
+			expected: `{"$schema": "https://json-schema.org/draft/2020-12/schema"}`,
+		},
+		"not selectable": {
+			value: func() {},
+			err:   "values of type func() cannot be selected",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schema, err := GenerateJSONSchema(tcase.value, tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tcase.expected, string(schema))
+		})
+	}
+}
+
+func TestGenerateJSONSchema_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	generated, err := GenerateJSONSchema(testSchemaService{})
+	require.NoError(t, err)
+	schema, err := JSONSchema(generated)
+	require.NoError(t, err)
+
+	for _, expression := range []string{
+		`Name == "web" and port > 80 and Weight < 1.5 and Enabled == true`,
+		`"prod" in Tags and Meta.env == "prod" and Checks.0.Status == "passing"`,
+		`Owner.Manager.Name == "alice" and Any.anything == 1`,
+	} {
+		_, err := CreateEvaluator(expression, WithSchema(schema))
+		require.NoError(t, err, expression)
+	}
+
+	_, err = CreateEvaluator(`Secret == "x"`, WithSchema(schema))
+	require.EqualError(t, err, `error finding value in schema: /Secret at part 0: couldn't find key: property "Secret"`)
+}
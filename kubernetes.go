@@ -0,0 +1,542 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+var (
+	// k8sLabelKeyRe matches the keys of labels, names optionally prefixed
+	// with a DNS subdomain, such as app.kubernetes.io/name
+	k8sLabelKeyRe = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+	// k8sLabelValueRe matches the values of labels, which may be empty
+	k8sLabelValueRe = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?)?$`)
+	// k8sFieldPartRe matches the parts of the paths of field selectors, such
+	// as metadata.name
+	k8sFieldPartRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+	k8sExistsRe   = regexp.MustCompile(`^(!?)\s*([^\s!=<>(),]+)$`)
+	k8sSetRe      = regexp.MustCompile(`^([^\s!=<>(),]+)\s+(in|notin)\s*\(([^()]*)\)$`)
+	k8sOperatorRe = regexp.MustCompile(`^([^\s!=<>(),]+)\s*(==|=|!=|>|<)\s*([^\s!=<>(),]*)$`)
+	k8sFieldRe    = regexp.MustCompile(`^([^\s!=]+)\s*(==|=|!=)(.*)$`)
+
+	k8sFieldEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `=`, `\=`)
+)
+
+// KubernetesLabelSelector translates the expression to a Kubernetes label
+// selector, in the syntax parsed by labels.Parse of k8s.io/apimachinery, such
+// as "app=web,tier in (gold,silver),!canary", so that the filters of a
+// controller can be given to the API in ListOptions.LabelSelector and
+// evaluated against the labels of the objects, see NewKubernetesLabelsSource.
+// The options are the ones the values of the expression are resolved with,
+// such as WithParams.
+//
+// The expression must be a conjunction of requirements on single-part
+// selectors named like label keys, such as app or ["app.kubernetes.io/name"]:
+//
+//	app == "web", app != "web"                   app=web, app!=web
+//	tier == "a" or tier == "b"                   tier in (a,b)
+//	not (tier == "a" or tier == "b")             tier notin (a,b)
+//	canary is not null, canary is null           canary, !canary
+//	replicas > 2, replicas < 5                   replicas>2, replicas<5
+//	canary                                       canary=true
+//
+// Label values being strings, the orderings are only evaluated by bexpr when
+// the values are converted to numbers, such as with WithSelectorCoercion.
+// Other expressions return an error, as do expressions which are never true.
+func (eval *Evaluator) KubernetesLabelSelector(opts ...Option) (string, error) {
+	t := &k8sTranslator{opts: append(eval.evaluateOpts(), opts...)}
+	return t.selector(grammar.InlineLets(eval.ast))
+}
+
+// KubernetesFieldSelector translates the expression to a Kubernetes field
+// selector, in the syntax parsed by fields.ParseSelector of
+// k8s.io/apimachinery, such as "status.phase=Running,spec.nodeName!=", so
+// that the filters of a controller can be given to the API in
+// ListOptions.FieldSelector and evaluated against the objects. The options
+// are the ones the values of the expression are resolved with, such as
+// WithParams.
+//
+// The expression must be a conjunction of == and != comparisons of selectors,
+// the dotted paths of the fields, with values: the API only supports a few
+// fields for each kind of object, and compares them as strings.
+func (eval *Evaluator) KubernetesFieldSelector(opts ...Option) (string, error) {
+	t := &k8sTranslator{opts: append(eval.evaluateOpts(), opts...), fields: true}
+	return t.selector(grammar.InlineLets(eval.ast))
+}
+
+// k8sLabels is the SelectorSource of the labels of an object
+type k8sLabels map[string]string
+
+// NewKubernetesLabelsSource returns the SelectorSource of the labels of a
+// Kubernetes object, such as the map returned by its GetLabels method, to
+// evaluate the expressions translated to and from label selectors instead of
+// the map. The labels an object lacks are missing rather than failing the
+// evaluation, the way label selectors handle them: app != "web" matches the
+// objects without an app label.
+func NewKubernetesLabelsSource(labels map[string]string) SelectorSource {
+	return k8sLabels(labels)
+}
+
+func (l k8sLabels) GetPath(path []string) (interface{}, bool, error) {
+	if len(path) != 1 {
+		return nil, false, nil
+	}
+	value, ok := l[path[0]]
+	return value, ok, nil
+}
+
+type k8sTranslator struct {
+	opts []Option
+	// fields is set for field selectors, rather than label selectors
+	fields bool
+}
+
+func (t *k8sTranslator) kind() string {
+	if t.fields {
+		return "field"
+	}
+	return "label"
+}
+
+func (t *k8sTranslator) errorf(ast grammar.Expression, format string, args ...interface{}) error {
+	return fmt.Errorf("cannot translate %s to a %s selector: %s", formatExpression(ast), t.kind(), fmt.Sprintf(format, args...))
+}
+
+func (t *k8sTranslator) selector(ast grammar.Expression) (string, error) {
+	operands := []grammar.Expression{ast}
+	if node, ok := ast.(*grammar.BinaryExpression); ok && node.Operator == grammar.BinaryOpAnd {
+		operands = binaryChain(node)
+	}
+	var requirements []string
+	for _, operand := range operands {
+		requirement, err := t.requirement(operand)
+		if err != nil {
+			return "", err
+		}
+		// the requirements which are always met are left out
+		if requirement != "" {
+			requirements = append(requirements, requirement)
+		}
+	}
+	return strings.Join(requirements, ","), nil
+}
+
+func (t *k8sTranslator) requirement(ast grammar.Expression) (string, error) {
+	negated := false
+	operand := ast
+	if node, ok := ast.(*grammar.UnaryExpression); ok {
+		negated, operand = true, node.Operand
+	}
+	switch node := operand.(type) {
+	case *grammar.BinaryExpression:
+		if node.Operator == grammar.BinaryOpOr && !t.fields {
+			return t.set(ast, node, negated)
+		}
+	case *grammar.MatchExpression:
+		return t.match(ast, node, negated)
+	case *grammar.ExpressionValue:
+		if key, ok, err := t.key(node); err != nil {
+			return "", err
+		} else if ok {
+			// selectors used as boolean expressions
+			if negated {
+				return key + "!=true", nil
+			}
+			return key + "=true", nil
+		}
+		value, err := translatedValue(node, t.opts...)
+		if err != nil {
+			return "", t.errorf(ast, "%v", err)
+		}
+		result, ok := value.(bool)
+		if !ok {
+			return "", t.errorf(ast, "not a boolean")
+		}
+		return t.constant(ast, result != negated)
+	}
+	return "", t.errorf(ast, "selectors are conjunctions of requirements")
+}
+
+// constant translates the outcome of the requirements which do not depend on
+// the datum
+func (t *k8sTranslator) constant(ast grammar.Expression, result bool) (string, error) {
+	if !result {
+		return "", t.errorf(ast, "the requirement is never met")
+	}
+	return "", nil
+}
+
+// operands returns the operator and the key and value compared by the match
+// expression, swapping the operands of the expressions comparing a value
+// with a selector, such as 2 < replicas.
+func (t *k8sTranslator) operands(ast grammar.Expression, node *grammar.MatchExpression) (grammar.MatchOperator, string, interface{}, error) {
+	operator, left, right := node.Operator, node.Left, node.Right
+	key, ok, err := t.key(left)
+	if mirrored, mirrorable := mirroredOperators[operator]; err == nil && !ok && mirrorable {
+		operator, left, right = mirrored, right, left
+		key, ok, err = t.key(left)
+	}
+	switch {
+	case err != nil:
+		return operator, "", nil, err
+	case !ok:
+		return operator, "", nil, t.errorf(ast, "the expression does not compare a selector with a value")
+	}
+	var value interface{}
+	if right != nil {
+		if value, err = translatedValue(right, t.opts...); err != nil {
+			return operator, "", nil, t.errorf(ast, "%v", err)
+		}
+	}
+	return operator, key, value, nil
+}
+
+func (t *k8sTranslator) match(ast grammar.Expression, node *grammar.MatchExpression, negated bool) (string, error) {
+	if !refersToDatum(node.Left) && !refersToDatum(node.Right) {
+		// match expressions on literals, such as 1 == 1
+		result, err := evaluateMatchExpression(node, nil, t.opts...)
+		if err != nil {
+			return "", t.errorf(ast, "%v", err)
+		}
+		return t.constant(ast, result != negated)
+	}
+
+	operator, key, value, err := t.operands(ast, node)
+	if err != nil {
+		return "", err
+	}
+	if positive, ok := positiveOperators[operator]; ok {
+		operator, negated = positive, !negated
+	}
+
+	switch {
+	case operator == grammar.MatchEqual && value != nil:
+		formatted, err := t.value(ast, value)
+		if err != nil {
+			return "", err
+		}
+		if negated {
+			return key + "!=" + formatted, nil
+		}
+		return key + "=" + formatted, nil
+	case t.fields:
+	case operator == grammar.MatchEqual || operator == grammar.MatchIsNull:
+		// == null is the same as is null
+		if negated {
+			return key, nil
+		}
+		return "!" + key, nil
+	case (operator == grammar.MatchHigher || operator == grammar.MatchLower) && !negated:
+		n, ok := value.(int64)
+		if !ok {
+			return "", t.errorf(ast, "%v is not an integer", value)
+		}
+		op := ">"
+		if operator == grammar.MatchLower {
+			op = "<"
+		}
+		return key + op + strconv.FormatInt(n, 10), nil
+	}
+	return "", t.errorf(ast, "unsupported operator %s", node.Operator)
+}
+
+// set translates the disjunctions of equalities of a label with values to
+// set-based requirements
+func (t *k8sTranslator) set(ast grammar.Expression, node *grammar.BinaryExpression, negated bool) (string, error) {
+	var setKey string
+	var values []string
+	for _, operand := range binaryChain(node) {
+		match, ok := operand.(*grammar.MatchExpression)
+		if !ok {
+			return "", t.errorf(ast, "disjunctions must compare a label with values")
+		}
+		operator, key, value, err := t.operands(ast, match)
+		if err != nil {
+			return "", err
+		}
+		if operator != grammar.MatchEqual || value == nil || (setKey != "" && key != setKey) {
+			return "", t.errorf(ast, "disjunctions must compare a label with values")
+		}
+		formatted, err := t.value(ast, value)
+		if err != nil {
+			return "", err
+		}
+		setKey, values = key, append(values, formatted)
+	}
+	if negated {
+		return setKey + " notin (" + strings.Join(values, ",") + ")", nil
+	}
+	return setKey + " in (" + strings.Join(values, ",") + ")", nil
+}
+
+// key returns the key of the label or the path of the field of the operand
+// when it is a selector, failing on selectors which cannot be translated
+func (t *k8sTranslator) key(expr *grammar.ExpressionValue) (string, bool, error) {
+	if expr == nil || expr.Operator != grammar.MathOpValue {
+		return "", false, nil
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect {
+		return "", false, nil
+	}
+	path := value.Selector.Path
+	if !t.fields {
+		if len(path) != 1 || len(value.Selector.Ranges) > 0 || !k8sLabelKeyRe.MatchString(path[0]) {
+			return "", false, fmt.Errorf("cannot translate %s to a label selector: not a label key", formatExpression(expr))
+		}
+		return path[0], true, nil
+	}
+	for _, part := range path {
+		if !k8sFieldPartRe.MatchString(part) || len(value.Selector.Ranges) > 0 {
+			return "", false, fmt.Errorf("cannot translate %s to a field selector: not a field path", formatExpression(expr))
+		}
+	}
+	return strings.Join(path, "."), true, nil
+}
+
+// value formats the value of a requirement
+func (t *k8sTranslator) value(ast grammar.Expression, value interface{}) (string, error) {
+	var formatted string
+	switch v := value.(type) {
+	case string:
+		formatted = v
+	case bool, int64, uint64:
+		formatted = fmt.Sprint(v)
+	case float64:
+		formatted = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return "", t.errorf(ast, "cannot translate the value %v", value)
+	}
+	if t.fields {
+		return k8sFieldEscaper.Replace(formatted), nil
+	}
+	if !k8sLabelValueRe.MatchString(formatted) {
+		return "", t.errorf(ast, "%q is not a label value", formatted)
+	}
+	return formatted, nil
+}
+
+// FromKubernetesLabelSelector translates a Kubernetes label selector, in the
+// syntax parsed by labels.Parse of k8s.io/apimachinery, to an expression
+// evaluated against maps of labels, such as
+// `app == "web" and (tier == "gold" or tier == "silver")` for
+// "app=web,tier in (gold,silver)". The empty selector, which matches any
+// object, is translated to true. See KubernetesLabelSelector for the
+// translation of each requirement.
+func FromKubernetesLabelSelector(selector string) (string, error) {
+	var exprs []grammar.Expression
+	for _, requirement := range splitLabelSelector(selector) {
+		requirement = strings.TrimSpace(requirement)
+		expr, err := labelRequirement(requirement)
+		if err != nil {
+			return "", fmt.Errorf("invalid requirement %q: %w", requirement, err)
+		}
+		exprs = append(exprs, expr)
+	}
+	return formatExpression(k8sConjunction(exprs)), nil
+}
+
+// splitLabelSelector splits the selector on the commas outside of the sets of
+// values
+func splitLabelSelector(selector string) []string {
+	if strings.TrimSpace(selector) == "" {
+		return nil
+	}
+	var requirements []string
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selector[start:])
+}
+
+func labelRequirement(requirement string) (grammar.Expression, error) {
+	if m := k8sSetRe.FindStringSubmatch(requirement); m != nil {
+		if !k8sLabelKeyRe.MatchString(m[1]) {
+			return nil, fmt.Errorf("%q is not a label key", m[1])
+		}
+		var values []string
+		if strings.TrimSpace(m[3]) != "" {
+			values = strings.Split(m[3], ",")
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("the set of values is empty")
+		}
+		var exprs []grammar.Expression
+		for _, value := range values {
+			value = strings.TrimSpace(value)
+			if !k8sLabelValueRe.MatchString(value) {
+				return nil, fmt.Errorf("%q is not a label value", value)
+			}
+			exprs = append(exprs, k8sMatch(m[1], grammar.MatchEqual, stringValue(value)))
+		}
+		if m[2] == "notin" {
+			return &grammar.UnaryExpression{Operator: grammar.UnaryOpNot, Operand: k8sDisjunction(exprs)}, nil
+		}
+		return k8sDisjunction(exprs), nil
+	}
+
+	if m := k8sOperatorRe.FindStringSubmatch(requirement); m != nil {
+		if !k8sLabelKeyRe.MatchString(m[1]) {
+			return nil, fmt.Errorf("%q is not a label key", m[1])
+		}
+		switch m[2] {
+		case ">", "<":
+			if _, err := strconv.ParseInt(m[3], 10, 64); err != nil {
+				return nil, fmt.Errorf("%q is not an integer", m[3])
+			}
+			operator := grammar.MatchHigher
+			if m[2] == "<" {
+				operator = grammar.MatchLower
+			}
+			return k8sMatch(m[1], operator, &grammar.MatchValue{Type: grammar.ValueTypeInt, Raw: m[3]}), nil
+		}
+		if !k8sLabelValueRe.MatchString(m[3]) {
+			return nil, fmt.Errorf("%q is not a label value", m[3])
+		}
+		operator := grammar.MatchEqual
+		if m[2] == "!=" {
+			operator = grammar.MatchNotEqual
+		}
+		return k8sMatch(m[1], operator, stringValue(m[3])), nil
+	}
+
+	if m := k8sExistsRe.FindStringSubmatch(requirement); m != nil {
+		if !k8sLabelKeyRe.MatchString(m[2]) {
+			return nil, fmt.Errorf("%q is not a label key", m[2])
+		}
+		operator := grammar.MatchIsNotNull
+		if m[1] == "!" {
+			operator = grammar.MatchIsNull
+		}
+		return k8sMatch(m[2], operator, nil), nil
+	}
+	return nil, fmt.Errorf("unrecognized syntax")
+}
+
+// FromKubernetesFieldSelector translates a Kubernetes field selector, in the
+// syntax parsed by fields.ParseSelector of k8s.io/apimachinery, to an
+// expression evaluated against the objects, such as
+// `status.phase == "Running" and spec.nodeName != ``` for
+// "status.phase=Running,spec.nodeName!=". Values are always strings. The
+// empty selector, which matches any object, is translated to true.
+func FromKubernetesFieldSelector(selector string) (string, error) {
+	var exprs []grammar.Expression
+	for _, term := range splitFieldSelector(selector) {
+		term = strings.TrimSpace(term)
+		m := k8sFieldRe.FindStringSubmatch(term)
+		if m == nil {
+			return "", fmt.Errorf("invalid requirement %q: unrecognized syntax", term)
+		}
+		path := strings.Split(m[1], ".")
+		for _, part := range path {
+			if !k8sFieldPartRe.MatchString(part) {
+				return "", fmt.Errorf("invalid requirement %q: %q is not a field path", term, m[1])
+			}
+		}
+		value, err := unescapeFieldValue(m[3])
+		if err != nil {
+			return "", fmt.Errorf("invalid requirement %q: %w", term, err)
+		}
+		operator := grammar.MatchEqual
+		if m[2] == "!=" {
+			operator = grammar.MatchNotEqual
+		}
+		exprs = append(exprs, &grammar.MatchExpression{
+			Operator: operator,
+			Left:     &grammar.ExpressionValue{Left: &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: path}}},
+			Right:    &grammar.ExpressionValue{Left: stringValue(value)},
+		})
+	}
+	return formatExpression(k8sConjunction(exprs)), nil
+}
+
+// splitFieldSelector splits the selector on the commas which are not escaped
+func splitFieldSelector(selector string) []string {
+	if strings.TrimSpace(selector) == "" {
+		return nil
+	}
+	var terms []string
+	start := 0
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '\\':
+			i++
+		case ',':
+			terms = append(terms, selector[start:i])
+			start = i + 1
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+// unescapeFieldValue unescapes the \\, \, and \= sequences of field values
+func unescapeFieldValue(value string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+1 == len(value) || !strings.ContainsRune(`\,=`, rune(value[i+1])) {
+			return "", fmt.Errorf("invalid escape sequence in %q", value)
+		}
+		i++
+		b.WriteByte(value[i])
+	}
+	return b.String(), nil
+}
+
+func stringValue(s string) *grammar.MatchValue {
+	return &grammar.MatchValue{Type: grammar.ValueTypeString, Raw: s}
+}
+
+// k8sMatch returns the match expression comparing the label with the value
+func k8sMatch(key string, operator grammar.MatchOperator, value *grammar.MatchValue) *grammar.MatchExpression {
+	match := &grammar.MatchExpression{
+		Operator: operator,
+		Left:     &grammar.ExpressionValue{Left: &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: []string{key}}}},
+	}
+	if value != nil {
+		match.Right = &grammar.ExpressionValue{Left: value}
+	}
+	return match
+}
+
+// k8sConjunction returns the conjunction of the expressions, true when there
+// are none
+func k8sConjunction(exprs []grammar.Expression) grammar.Expression {
+	return k8sChain(exprs, grammar.BinaryOpAnd)
+}
+
+func k8sDisjunction(exprs []grammar.Expression) grammar.Expression {
+	return k8sChain(exprs, grammar.BinaryOpOr)
+}
+
+func k8sChain(exprs []grammar.Expression, operator grammar.BinaryOperator) grammar.Expression {
+	switch len(exprs) {
+	case 0:
+		return &grammar.ExpressionValue{Left: &grammar.MatchValue{Type: grammar.ValueTypeBool, Raw: "true"}}
+	case 1:
+		return exprs[0]
+	}
+	return &grammar.BinaryExpression{Operator: operator, Left: exprs[0], Right: k8sChain(exprs[1:], operator)}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		selector   string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equality":     {expression: `app == "web" and env != "prod"`, selector: `app=web,env!=prod`},
+		"label keys":   {expression: `["app.kubernetes.io/name"] == "web" and "web" == ["example.com/role"]`, selector: `app.kubernetes.io/name=web,example.com/role=web`},
+		"in":           {expression: `tier == "gold" or tier == "silver"`, selector: `tier in (gold,silver)`},
+		"notin":        {expression: `app == "web" and not (tier == "gold" or "silver" == tier)`, selector: `app=web,tier notin (gold,silver)`},
+		"exists":       {expression: `canary is not null and legacy is null and not (beta is null) and gamma != null`, selector: `canary,!legacy,beta,gamma`},
+		"orderings":    {expression: `replicas > 2 and 5 > replicas`, selector: `replicas>2,replicas<5`},
+		"negations":    {expression: `not (app == "web") and not (env != "prod")`, selector: `app!=web,env=prod`},
+		"boolean":      {expression: `canary and not stable`, selector: `canary=true,stable!=true`},
+		"values":       {expression: "version == 2 and enabled == true and empty == ``", selector: `version=2,enabled=true,empty=`},
+		"params":       {expression: `app == $app`, opts: []Option{WithParams(map[string]interface{}{"app": "web"})}, selector: `app=web`},
+		"let":          {expression: `let t = tier in t == "gold" or t == "silver"`, selector: `tier in (gold,silver)`},
+		"always true":  {expression: `app == "web" and 1 == 1`, selector: `app=web`},
+		"everything":   {expression: `true`, selector: ``},
+		"never true":   {expression: `app == "web" and 1 == 2`, err: `cannot translate 1 == 2 to a label selector: the requirement is never met`},
+		"disjunction":  {expression: `app == "web" or env == "prod"`, err: `cannot translate app == "web" or env == "prod" to a label selector: disjunctions must compare a label with values`},
+		"nested":       {expression: `metadata.labels.app == "web"`, err: `cannot translate metadata.labels.app to a label selector: not a label key`},
+		"label value":  {expression: `app == "web server"`, err: `cannot translate app == "web server" to a label selector: "web server" is not a label value`},
+		"operator":     {expression: `app startswith "web"`, err: `cannot translate app startswith "web" to a label selector: unsupported operator Starts With`},
+		"not ordering": {expression: `not (replicas > 2)`, err: `cannot translate not replicas > 2 to a label selector: unsupported operator Higher`},
+		"two labels":   {expression: `app == env`, err: `cannot translate app == env to a label selector: env depends on the datum`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			selector, err := eval.KubernetesLabelSelector(tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.selector, selector)
+		})
+	}
+}
+
+func TestKubernetesFieldSelector(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		selector   string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equality":   {expression: "status.phase == \"Running\" and spec.nodeName != ``", selector: `status.phase=Running,spec.nodeName!=`},
+		"escapes":    {expression: `metadata.name == "a,b=c\\d"`, selector: `metadata.name=a\,b\=c\\d`},
+		"negation":   {expression: `not (metadata.namespace == "kube-system")`, selector: `metadata.namespace!=kube-system`},
+		"boolean":    {expression: `spec.unschedulable`, selector: `spec.unschedulable=true`},
+		"in":         {expression: `status.phase == "Running" or status.phase == "Pending"`, err: `cannot translate status.phase == "Running" or status.phase == "Pending" to a field selector: selectors are conjunctions of requirements`},
+		"null":       {expression: `spec.nodeName is null`, err: `cannot translate spec.nodeName is null to a field selector: unsupported operator Is Null`},
+		"field path": {expression: `metadata.labels["app.kubernetes.io/name"] == "web"`, err: `cannot translate metadata.labels["app.kubernetes.io/name"] to a field selector: not a field path`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			selector, err := eval.KubernetesFieldSelector()
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.selector, selector)
+		})
+	}
+}
+
+func TestFromKubernetesLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		selector   string
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equality":    {selector: `app=web,env==prod,tier!=gold`, expression: `app == "web" and env == "prod" and tier != "gold"`},
+		"spaces":      {selector: ` app = web , tier in ( gold , silver ) `, expression: `app == "web" and (tier == "gold" or tier == "silver")`},
+		"notin":       {selector: `app=web,tier notin (gold,silver)`, expression: `app == "web" and not (tier == "gold" or tier == "silver")`},
+		"single":      {selector: `tier in (gold)`, expression: `tier == "gold"`},
+		"exists":      {selector: `canary,!legacy`, expression: `canary is not null and legacy is null`},
+		"orderings":   {selector: `replicas>2,replicas<5`, expression: `replicas > 2 and replicas < 5`},
+		"label keys":  {selector: `app.kubernetes.io/name=web`, expression: `"/app.kubernetes.io~1name" == "web"`},
+		"empty value": {selector: `app=`, expression: "app == ``"},
+		"everything":  {selector: ``, expression: `true`},
+		"empty set":   {selector: `tier in ()`, err: `invalid requirement "tier in ()": the set of values is empty`},
+		"bad key":     {selector: `-app=web`, err: `invalid requirement "-app=web": "-app" is not a label key`},
+		"bad value":   {selector: `app=-web`, err: `invalid requirement "app=-web": "-web" is not a label value`},
+		"bad integer": {selector: `replicas>two`, err: `invalid requirement "replicas>two": "two" is not an integer`},
+		"syntax":      {selector: `app web`, err: `invalid requirement "app web": unrecognized syntax`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := FromKubernetesLabelSelector(tcase.selector)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expression, expression)
+		})
+	}
+}
+
+func TestFromKubernetesFieldSelector(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		selector   string
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equality":   {selector: `status.phase=Running,spec.nodeName!=`, expression: "status.phase == \"Running\" and spec.nodeName != ``"},
+		"double":     {selector: `metadata.name==web`, expression: `metadata.name == "web"`},
+		"escapes":    {selector: `metadata.name=a\,b\=c\\d,x=y`, expression: `metadata.name == "a,b=c\\d" and x == "y"`},
+		"everything": {selector: ` `, expression: `true`},
+		"escape":     {selector: `metadata.name=a\b`, err: `invalid requirement "metadata.name=a\\b": invalid escape sequence in "a\\b"`},
+		"field path": {selector: `metadata..name=web`, err: `invalid requirement "metadata..name=web": "metadata..name" is not a field path`},
+		"syntax":     {selector: `metadata.name`, err: `invalid requirement "metadata.name": unrecognized syntax`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := FromKubernetesFieldSelector(tcase.selector)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.expression, expression)
+		})
+	}
+}
+
+func TestKubernetesLabelSelector_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	labelSets := []map[string]string{
+		{"app": "web", "tier": "gold"},
+		{"app": "web", "tier": "bronze", "canary": ""},
+		{"app": "db"},
+		{},
+	}
+	for _, selector := range []string{
+		`app=web,tier in (gold,silver)`,
+		`app!=db,tier notin (gold,silver)`,
+		`canary,!legacy`,
+		`!app`,
+	} {
+		expression, err := FromKubernetesLabelSelector(selector)
+		require.NoError(t, err)
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		translated, err := eval.KubernetesLabelSelector()
+		require.NoError(t, err)
+		require.Equal(t, selector, translated)
+
+		// k8s semantics: missing labels differ from every value
+		for _, labels := range labelSets {
+			_, err := eval.Evaluate(NewKubernetesLabelsSource(labels))
+			require.NoError(t, err, "%s against %v", expression, labels)
+		}
+	}
+
+	eval, err := CreateEvaluator(`app != "db" and tier != "gold" and tier != "silver"`)
+	require.NoError(t, err)
+	for labels, expected := range map[int]bool{0: false, 1: true, 2: false, 3: true} {
+		result, err := eval.Evaluate(NewKubernetesLabelsSource(labelSets[labels]))
+		require.NoError(t, err)
+		require.Equal(t, expected, result, "%v", labelSets[labels])
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// ldapAttributeRe matches the attribute descriptions of RFC 4512, names or
+// OIDs followed by options
+var ldapAttributeRe = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*|[0-9]+(\.[0-9]+)+)(;[a-zA-Z0-9-]+)*$`)
+
+// ldapEscaper escapes the values of LDAP filters, see RFC 4515
+var ldapEscaper = strings.NewReplacer(`\`, `\5c`, `*`, `\2a`, `(`, `\28`, `)`, `\29`, "\x00", `\00`)
+
+// LDAPFilter translates the expression to an LDAP search filter, see RFC
+// 4515, such as (&(objectClass=person)(|(ou=eng)(ou=ops))), so that the
+// filters of an API can also be run against a directory. The options are the
+// ones the values of the expression are resolved with, such as WithParams.
+//
+// Logical operators are translated to the & | and ! filters. Match
+// expressions must compare a selector made of a single attribute description,
+// such as cn or mail;binary, with values which do not depend on the datum:
+//
+//	==, !=                    equality, negated for !=
+//	<=, >=                    less or equal, greater or equal
+//	<, >                      greater or equal, less or equal, negated
+//	in, contains              equality, which matches any value of the attribute
+//	is empty, is null         presence, negated
+//	startswith, endswith      substrings
+//	like                      substrings, for patterns made of * wildcards only
+//
+// Selectors used as boolean expressions are translated to equality with TRUE,
+// and the outcome of expressions which do not depend on the datum to the
+// absolute true and false filters (&) and (|) of RFC 4526. Orderings follow
+// the matching rules of the attributes rather than the types of the values,
+// and entries missing an attribute match the negated filters instead of
+// failing the evaluation. The "matches" operator cannot be translated, nor
+// can expressions comparing two selectors: they return an error.
+func (eval *Evaluator) LDAPFilter(opts ...Option) (string, error) {
+	t := &ldapTranslator{opts: append(eval.evaluateOpts(), opts...)}
+	var b strings.Builder
+	if err := t.expression(&b, grammar.InlineLets(eval.ast)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type ldapTranslator struct {
+	opts []Option
+}
+
+func (t *ldapTranslator) expression(b *strings.Builder, ast grammar.Expression) error {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		b.WriteString("(!")
+		if err := t.expression(b, node.Operand); err != nil {
+			return err
+		}
+		b.WriteString(")")
+		return nil
+
+	case *grammar.BinaryExpression:
+		// chains of the same operator are written as a single filter
+		if node.Operator == grammar.BinaryOpOr {
+			b.WriteString("(|")
+		} else {
+			b.WriteString("(&")
+		}
+		for _, operand := range binaryChain(node) {
+			if err := t.expression(b, operand); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+		return nil
+
+	case *grammar.ExpressionValue:
+		if attr, ok, err := ldapAttribute(node); err != nil {
+			return err
+		} else if ok {
+			b.WriteString("(" + attr + "=TRUE)")
+			return nil
+		}
+		value, err := translatedValue(node, t.opts...)
+		if err != nil {
+			return err
+		}
+		if result, ok := value.(bool); ok {
+			b.WriteString(ldapConstant(result))
+			return nil
+		}
+		return fmt.Errorf("cannot translate %s to a filter: not a boolean", formatExpression(node))
+
+	case *grammar.MatchExpression:
+		return t.match(b, node)
+	}
+	return fmt.Errorf("cannot translate %T to a filter", ast)
+}
+
+func (t *ldapTranslator) match(b *strings.Builder, node *grammar.MatchExpression) error {
+	operator, left, right := node.Operator, node.Left, node.Right
+	if !refersToDatum(left) && !refersToDatum(right) {
+		// match expressions on literals, such as 1 == 1
+		result, err := evaluateMatchExpression(node, nil, t.opts...)
+		if err != nil {
+			return fmt.Errorf("cannot translate %s to a filter: %w", formatExpression(node), err)
+		}
+		b.WriteString(ldapConstant(result))
+		return nil
+	}
+
+	attr, ok, err := ldapAttribute(left)
+	if mirrored, mirrorable := mirroredOperators[operator]; err == nil && !ok && mirrorable {
+		// literals compared with a selector, such as 8080 < port
+		operator, left, right = mirrored, right, left
+		attr, ok, err = ldapAttribute(left)
+	}
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return fmt.Errorf("cannot translate %s to a filter: the expression does not compare a selector with a value", formatExpression(node))
+	}
+
+	var value interface{}
+	if right != nil {
+		if value, err = translatedValue(right, t.opts...); err != nil {
+			return fmt.Errorf("cannot translate %s to a filter: %w", formatExpression(node), err)
+		}
+	}
+
+	// the filters of the operators which are negations of others, along with
+	// < and >, are the negation of the filter of another operator
+	negated := false
+	if positive, ok := positiveOperators[operator]; ok {
+		operator, negated = positive, true
+	}
+	switch operator {
+	case grammar.MatchLower:
+		operator, negated = grammar.MatchHigherOrEqual, !negated
+	case grammar.MatchHigher:
+		operator, negated = grammar.MatchLowerOrEqual, !negated
+	}
+
+	var filter string
+	switch operator {
+	case grammar.MatchEqual, grammar.MatchIn:
+		if value == nil {
+			filter, negated = attr+"=*", !negated
+		} else {
+			filter = attr + "=" + ldapValue(value)
+		}
+	case grammar.MatchLowerOrEqual:
+		filter = attr + "<=" + ldapValue(value)
+	case grammar.MatchHigherOrEqual:
+		filter = attr + ">=" + ldapValue(value)
+	case grammar.MatchIsEmpty, grammar.MatchIsNull:
+		filter, negated = attr+"=*", !negated
+	case grammar.MatchStartsWith, grammar.MatchEndsWith, grammar.MatchLike:
+		pattern, ok := value.(string)
+		switch {
+		case !ok:
+			return fmt.Errorf("cannot translate %s to a filter: %v is not a string", formatExpression(node), value)
+		case operator == grammar.MatchStartsWith:
+			filter = attr + "=" + ldapValue(pattern) + "*"
+		case operator == grammar.MatchEndsWith:
+			filter = attr + "=*" + ldapValue(pattern)
+		default:
+			if filter, err = ldapSubstrings(attr, pattern); err != nil {
+				return fmt.Errorf("cannot translate %s to a filter: %w", formatExpression(node), err)
+			}
+		}
+	default:
+		return fmt.Errorf("cannot translate %s to a filter: unsupported operator %s", formatExpression(node), node.Operator)
+	}
+
+	if negated {
+		b.WriteString("(!(" + filter + "))")
+	} else {
+		b.WriteString("(" + filter + ")")
+	}
+	return nil
+}
+
+// ldapSubstrings translates a like pattern made of * wildcards to a substrings
+// filter
+func ldapSubstrings(attr, pattern string) (string, error) {
+	var b strings.Builder
+	b.WriteString(attr + "=")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(ldapEscaper.Replace(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			// consecutive wildcards are not valid in filters
+			if !strings.HasSuffix(b.String(), "*") {
+				b.WriteRune('*')
+			}
+		case r == '?' || r == '[':
+			return "", fmt.Errorf("substrings filters only support * wildcards")
+		default:
+			b.WriteString(ldapEscaper.Replace(string(r)))
+		}
+	}
+	return b.String(), nil
+}
+
+// binaryChain returns the operands of the chain of binary expressions of the
+// same operator the expression starts, such as a, b and c for a and (b and c)
+func binaryChain(node *grammar.BinaryExpression) []grammar.Expression {
+	var operands []grammar.Expression
+	for _, operand := range []grammar.Expression{node.Left, node.Right} {
+		if nested, ok := operand.(*grammar.BinaryExpression); ok && nested.Operator == node.Operator {
+			operands = append(operands, binaryChain(nested)...)
+		} else {
+			operands = append(operands, operand)
+		}
+	}
+	return operands
+}
+
+func ldapConstant(result bool) string {
+	if result {
+		return "(&)"
+	}
+	return "(|)"
+}
+
+// ldapAttribute returns the attribute description of the operand when it is a
+// selector, failing on selectors which are not attribute descriptions
+func ldapAttribute(expr *grammar.ExpressionValue) (string, bool, error) {
+	if expr == nil || expr.Operator != grammar.MathOpValue {
+		return "", false, nil
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect {
+		return "", false, nil
+	}
+	if len(value.Selector.Path) != 1 || len(value.Selector.Ranges) > 0 || !ldapAttributeRe.MatchString(value.Selector.Path[0]) {
+		return "", false, fmt.Errorf("cannot translate %s to a filter: not an attribute description", formatExpression(expr))
+	}
+	return value.Selector.Path[0], true, nil
+}
+
+// ldapValue formats the value of a filter
+func ldapValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ldapEscaper.Replace(fmt.Sprint(value))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLDAPFilter(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		filter     string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equal":            {expression: `cn == "alice"`, filter: `(cn=alice)`},
+		"not equal":        {expression: `cn != "alice"`, filter: `(!(cn=alice))`},
+		"and chain":        {expression: `objectClass == "person" and (ou == "eng" or ou == "ops" or ou == "qa") and uid != "root"`, filter: `(&(objectClass=person)(|(ou=eng)(ou=ops)(ou=qa))(!(uid=root)))`},
+		"not":              {expression: `not (ou == "eng" and l == "Rome")`, filter: `(!(&(ou=eng)(l=Rome)))`},
+		"ordering":         {expression: `uidNumber >= 1000 and uidNumber <= 2000 and age > 17 and 65 > age`, filter: `(&(uidNumber>=1000)(uidNumber<=2000)(!(age<=17))(!(age>=65)))`},
+		"in":               {expression: `"admins" in memberOf and memberOf not contains "guests"`, filter: `(&(memberOf=admins)(!(memberOf=guests)))`},
+		"presence":         {expression: `mail is not empty and manager is null`, filter: `(&(mail=*)(!(manager=*)))`},
+		"equal null":       {expression: `manager == null`, filter: `(!(manager=*))`},
+		"substrings":       {expression: `cn startswith "Al" and mail endswith "@example.com" and sn like "Ro*s**i"`, filter: `(&(cn=Al*)(mail=*@example.com)(sn=Ro*s*i))`},
+		"escaping":         {expression: `cn == "a*(b)\\c" and sn like "x\\*y*"`, filter: `(&(cn=a\2a\28b\29\5cc)(sn=x\2ay*))`},
+		"boolean selector": {expression: `pwdReset and not locked`, filter: `(&(pwdReset=TRUE)(!(locked=TRUE)))`},
+		"boolean value":    {expression: `enabled == true`, filter: `(enabled=TRUE)`},
+		"options":          {expression: `$["userCertificate;binary"] is not empty`, filter: `(userCertificate;binary=*)`},
+		"oid":              {expression: `"/2.5.4.3" == "alice"`, filter: `(2.5.4.3=alice)`},
+		"params":           {expression: `uid == $user`, opts: []Option{WithParams(map[string]interface{}{"user": "bob"})}, filter: `(uid=bob)`},
+		"let":              {expression: `let g = memberOf in "a" in g or "b" in g`, filter: `(|(memberOf=a)(memberOf=b))`},
+		"constant":         {expression: `1 == 2 or cn == "x"`, filter: `(cn=x)`},
+		"constant true":    {expression: `1 == 1`, filter: `(&)`},
+		"matches":          {expression: `cn matches "^a"`, err: `cannot translate cn matches "^a" to a filter: unsupported operator Matches`},
+		"nested selector":  {expression: `Meta.env == "prod"`, err: `cannot translate Meta.env to a filter: not an attribute description`},
+		"two selectors":    {expression: `cn == sn`, err: `cannot translate cn == sn to a filter: sn depends on the datum`},
+		"single character": {expression: `cn like "a?c"`, err: `cannot translate cn like "a?c" to a filter: substrings filters only support * wildcards`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			filter, err := eval.LDAPFilter(tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.filter, filter)
+		})
+	}
+}
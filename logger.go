@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Logger logs the diagnostics of WithLogger, with alternating keys and
+// values as the arguments. *slog.Logger implements it.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+}
+
+// WithLogger logs at debug level why an expression does not match what it
+// was expected to: the expressions which fail to create evaluators, the
+// match expressions decided by the NotPresentDisposition of their operator
+// because their selector is missing or null, and the comparisons of values
+// with literals which cannot be coerced to their type, such as a number with
+// "eighty", which do not fail but hardly compare as expected. Logging
+// evaluations, the evaluator does not evaluate them on its fast path.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.withLogger = logger
+	}
+}
+
+func logCreateFailure(logger Logger, expression string, err error) {
+	logger.Debug("bexpr: evaluator not created", "expression", expression, "error", err)
+}
+
+func logNotPresent(logger Logger, expression *grammar.MatchExpression, result bool) {
+	logger.Debug("bexpr: selector not present", "expression", formatExpression(expression), "selector", expression.Left.String(), "result", result)
+}
+
+// logCoercionFallback logs the comparisons of the match expression whose
+// right operand cannot be coerced to the type of the left one
+func logCoercionFallback(logger Logger, expression *grammar.MatchExpression, leftValue interface{}, rightValue interface{}) {
+	switch expression.Operator {
+	case grammar.MatchEqual, grammar.MatchNotEqual, grammar.MatchLower, grammar.MatchLowerOrEqual,
+		grammar.MatchHigher, grammar.MatchHigherOrEqual:
+	default:
+		return
+	}
+	var err error
+	switch kind := reflect.Indirect(reflect.ValueOf(leftValue)).Kind(); {
+	case kind == reflect.Bool:
+		_, err = CoerceBool(rightValue)
+	case isFloatKind(kind) || isIntegerFloatPair(leftValue, rightValue):
+		_, err = CoerceFloat64(rightValue)
+	case isNumberKind(kind):
+		_, _, _, err = coerceInteger(rightValue)
+	}
+	if err != nil {
+		logger.Debug("bexpr: value not coerced", "expression", formatExpression(expression),
+			"type", reflect.TypeOf(leftValue).String(), "value", rightValue, "error", err)
+	}
+}
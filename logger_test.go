@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.21
+
+package bexpr
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLogger returns a logger writing the debug records to the buffer,
+// without their times
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		logs       []string
+	}
+
+	tests := map[string]testCase{
+		"Not Present": {
+			expression: `Meta.region == "eu"`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{}},
+			result:     false,
+			logs: []string{
+				`level=DEBUG msg="bexpr: selector not present" expression="Meta.region == \"eu\"" selector=Meta.region result=false`,
+			},
+		},
+		"Null Negated": {
+			expression: `"eu" not in Regions`,
+			datum:      map[string]interface{}{"Regions": nil},
+			result:     true,
+			logs: []string{
+				`level=DEBUG msg="bexpr: selector not present" expression="Regions not contains \"eu\"" selector=Regions result=true`,
+			},
+		},
+		"Integer Not Coerced": {
+			expression: `Port == "eighty"`,
+			datum:      map[string]interface{}{"Port": 80},
+			result:     false,
+			logs: []string{
+				`level=DEBUG msg="bexpr: value not coerced" expression="Port == \"eighty\"" type=int value=eighty error=`,
+			},
+		},
+		"Float Not Coerced": {
+			expression: `Ratio < "half"`,
+			datum:      map[string]interface{}{"Ratio": 0.5},
+			result:     false,
+			logs: []string{
+				`level=DEBUG msg="bexpr: value not coerced" expression="Ratio < \"half\"" type=float64 value=half error=`,
+			},
+		},
+		"Coerced": {
+			expression: `Port == "80" and Ratio < "1" and Enabled == "true"`,
+			datum:      map[string]interface{}{"Port": 80, "Ratio": 0.5, "Enabled": true},
+			result:     true,
+		},
+		"Matched": {
+			expression: `Name == "web"`,
+			datum:      map[string]interface{}{"Name": "web"},
+			result:     true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			eval, err := CreateEvaluator(tcase.expression, WithLogger(newTestLogger(&buf)))
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(tcase.datum)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+
+			logs := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if len(tcase.logs) == 0 {
+				require.Empty(t, buf.String())
+				return
+			}
+			require.Len(t, logs, len(tcase.logs))
+			for i, log := range tcase.logs {
+				require.True(t, strings.HasPrefix(logs[i], log), "log %d: %s", i, logs[i])
+			}
+		})
+	}
+}
+
+func TestWithLogger_ParseError(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	_, err := CreateEvaluator(`Name ==`, WithLogger(newTestLogger(&buf)))
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(buf.String(), `level=DEBUG msg="bexpr: evaluator not created" expression="Name ==" error=`), buf.String())
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
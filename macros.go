@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// macroName returns the name of the macro referenced by the expression, which
+// is a bare selector of the type made of a single identifier.
+func macroName(ast grammar.Expression, selectorType grammar.SelectorType) (string, bool) {
+	expr, ok := ast.(*grammar.ExpressionValue)
+	if !ok || expr.Operator != grammar.MathOpValue {
+		return "", false
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect || value.Selector.Type != selectorType || value.Selector.Anchored || len(value.Selector.Path) != 1 || len(value.Selector.Ranges) > 0 {
+		return "", false
+	}
+	return value.Selector.Path[0], true
+}
+
+// macroExpander replaces the references to macros by their expressions
+type macroExpander struct {
+	macros     map[string]string
+	parserOpts []grammar.Option
+	// the type of the selectors referencing macros
+	selectorType grammar.SelectorType
+	// the macros being expanded, to detect cycles
+	expanding map[string]bool
+}
+
+func (m *macroExpander) expand(ast grammar.Expression) (grammar.Expression, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := m.expand(node.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: operand}, nil
+	case *grammar.BinaryExpression:
+		left, err := m.expand(node.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := m.expand(node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.BinaryExpression{Operator: node.Operator, Left: left, Right: right}, nil
+	case *grammar.LetExpression:
+		body, err := m.expand(node.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &grammar.LetExpression{Name: node.Name, Value: node.Value, Body: body, Position: node.Position}, nil
+	}
+
+	name, ok := macroName(ast, m.selectorType)
+	if !ok {
+		return ast, nil
+	}
+	expression, ok := m.macros[name]
+	if !ok {
+		return ast, nil
+	}
+	if m.expanding[name] {
+		return nil, fmt.Errorf("macro %q references itself", name)
+	}
+
+	parsed, err := grammar.Parse(name, []byte(expression), m.parserOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing macro %q: %w", name, newSyntaxError(err))
+	}
+	m.expanding[name] = true
+	defer delete(m.expanding, name)
+	return m.expand(parsed.(grammar.Expression))
+}
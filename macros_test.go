@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMacros(t *testing.T) {
+	t.Parallel()
+
+	macros := map[string]string{
+		"is_prod":  `Env == "prod"`,
+		"is_acme":  `Tenant == "acme"`,
+		"guards":   `is_acme and is_prod`,
+		"loop":     `Env == "x" or loop`,
+		"invalid":  `Env ==`,
+		"indirect": `guards or loop`,
+	}
+	datum := map[string]interface{}{
+		"Env":     "prod",
+		"Tenant":  "acme",
+		"Role":    "admin",
+		"is_prod": false,
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"reference":          {expression: `is_prod and Role == "admin"`, result: true},
+		"nested macros":      {expression: `guards and not Role == "viewer"`, result: true},
+		"negated":            {expression: `not is_prod`, result: false},
+		"let body":           {expression: `let r = Role in is_acme and r == "admin"`, result: true},
+		"selector in match":  {expression: `is_prod == false`, result: true},
+		"not a macro":        {expression: `Role == "admin"`, result: true},
+		"cycle":              {expression: `loop`, err: `macro "loop" references itself`},
+		"indirect cycle":     {expression: `Role == "x" or indirect`, err: `macro "loop" references itself`},
+		"invalid macro":      {expression: `invalid`, err: `error parsing macro "invalid"`},
+		"unused cycle is ok": {expression: `is_acme`, result: true},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression, WithMacros(macros))
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// getWithStringKeys resolves the pointer after rekeying the maps keyed by
+// interfaces, such as the map[interface{}]interface{} values decoded by
+// gopkg.in/yaml.v2 and by msgpack and CBOR libraries, by the string form of
+// their keys. This lets
+// selectors find integer and other non-string keys, which pointerstructure
+// only compares with the selector's string.
+func getWithStringKeys(ptr taggedPointer, datum interface{}) (interface{}, error) {
+	ptr.Config.ValueTransformationHook = ChainHookFns(ptr.Config.ValueTransformationHook, stringKeysHookFn)
+	return ptr.Get(stringKeysHookFn(reflect.ValueOf(datum)).Interface())
+}
+
+// stringKeysHookFn converts maps keyed by interfaces holding non-string keys
+// into maps keyed by strings. String keys take precedence over other keys
+// with the same string form.
+func stringKeysHookFn(v reflect.Value) reflect.Value {
+	m := hookElem(v)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.Interface || !m.CanInterface() {
+		return v
+	}
+
+	rekeyed := false
+	iter := m.MapRange()
+	for iter.Next() {
+		if k := iter.Key(); !k.IsNil() && k.Elem().Kind() != reflect.String {
+			rekeyed = true
+			break
+		}
+	}
+	if !rekeyed {
+		return v
+	}
+
+	result := make(map[string]interface{}, m.Len())
+	strKeys := make(map[string]bool, m.Len())
+	iter = m.MapRange()
+	for iter.Next() {
+		k := iter.Key()
+		isString := !k.IsNil() && k.Elem().Kind() == reflect.String
+		key := fmt.Sprintf("%v", k.Interface())
+		if strKeys[key] && !isString {
+			continue
+		}
+		strKeys[key] = strKeys[key] || isString
+		result[key] = iter.Value().Interface()
+	}
+	return reflect.ValueOf(result)
+}
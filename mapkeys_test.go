@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterfaceKeyedMaps(t *testing.T) {
+	t.Parallel()
+
+	// the shape of datums decoded by msgpack and CBOR libraries
+	datum := map[interface{}]interface{}{
+		"name":    []byte("web"),
+		"port":    uint64(8080),
+		uint64(1): "one",
+		int8(2): map[interface{}]interface{}{
+			uint16(3): "three",
+			"labels": map[interface{}]interface{}{
+				"env": "prod",
+			},
+		},
+		"tags": []interface{}{"a", "b"},
+		// the string key takes precedence over the integer one
+		"4":      "string",
+		int64(4): "integer",
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"string key": {
+			expression: `port == 8080`,
+			result:     true,
+		},
+		"integer key": {
+			expression: `"/1" == "one"`,
+			result:     true,
+		},
+		"nested integer keys": {
+			expression: `"/2/3" == "three"`,
+			result:     true,
+		},
+		"string key below integer key": {
+			expression: `"/2/labels/env" == "prod"`,
+			result:     true,
+		},
+		"missing key": {
+			expression: `"/2/5" == "five"`,
+			result:     false,
+		},
+		"missing key negated": {
+			expression: `"/2/5" != "five"`,
+			result:     true,
+		},
+		"colliding keys": {
+			expression: `"/4" == "string"`,
+			result:     true,
+		},
+		"collection": {
+			expression: `"b" in tags`,
+			result:     true,
+		},
+		"bytes": {
+			expression: `name == "web"`,
+			err:        `operator "Equal" cannot be used with values of type []uint8`,
+		},
+		"bytes as string": {
+			expression: `name == "web"`,
+			opts:       []Option{WithHookFn(BytesToStringHookFn)},
+			result:     true,
+		},
+		"bytes as string below integer key": {
+			expression: `"/2/3" == "three" and name matches "^w"`,
+			opts:       []Option{WithHookFn(BytesToStringHookFn)},
+			result:     true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			match, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
+
+func TestYAMLMaps(t *testing.T) {
+	t.Parallel()
+
+	// the shape of the documents decoded by gopkg.in/yaml.v2, such as
+	//
+	//	spec:
+	//	  replicas: 3
+	//	  labels: {env: prod, 80: http, true: enabled}
+	//	  ports:
+	//	  - port: 80
+	//	  annotations: {}
+	datum := map[interface{}]interface{}{
+		"spec": map[interface{}]interface{}{
+			"replicas":    3,
+			"labels":      map[interface{}]interface{}{"env": "prod", 80: "http", true: "enabled"},
+			"ports":       []interface{}{map[interface{}]interface{}{"port": 80}},
+			"annotations": map[interface{}]interface{}{},
+		},
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+	}
+
+	tests := map[string]testCase{
+		"nested key":            {expression: `spec.replicas == 3 and spec.labels.env == "prod"`, result: true},
+		"integer key":           {expression: `spec.labels.80 == "http"`, result: true},
+		"bool key":              {expression: `spec.labels.true == "enabled"`, result: true},
+		"in":                    {expression: `"env" in spec.labels and 80 in spec.labels and "true" in spec.labels`, result: true},
+		"map in slice":          {expression: `spec.ports.0.port == 80`, result: true},
+		"empty map":             {expression: `spec.annotations is empty and spec.labels is not empty`, result: true},
+		"missing key":           {expression: `spec.labels.tier == "gold"`, result: false},
+		"missing key negated":   {expression: `spec.labels.tier != "gold"`, result: true},
+		"missing key below map": {expression: `spec.selector == "app"`, result: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			match, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, match)
+		})
+	}
+}
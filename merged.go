@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// MergedView is a read-only view over several maps and structs, the layers,
+// deep merging them without copying them. Evaluated against the view, a
+// selector is resolved in the first layer it can be found in, as with
+// EvaluateChained, and the maps it resolves to are merged: their keys are the
+// keys of the string keyed maps found at the selector in every layer, each
+// key taking its value from the first of the layers holding it. Only the keys
+// of these maps are copied, the values being shared with the layers.
+type MergedView []interface{}
+
+// NewMergedView returns the view over the layers, the ones given first taking
+// precedence.
+func NewMergedView(layers ...interface{}) MergedView {
+	return MergedView(layers)
+}
+
+func (v MergedView) getValue(expressionValue *grammar.MatchValue, opt ...Option) (interface{}, error) {
+	val, err := chainedDatum(v).getValue(expressionValue, opt...)
+	if err != nil || !isStringKeyedMap(val) {
+		return val, err
+	}
+
+	merged := make(map[string]interface{})
+	for _, layer := range v {
+		layerVal, err := getValue(expressionValue, layer, opt...)
+		if err != nil || !isStringKeyedMap(layerVal) {
+			continue
+		}
+		iter := reflect.Indirect(reflect.ValueOf(layerVal)).MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if _, ok := merged[key]; !ok {
+				merged[key] = iter.Value().Interface()
+			}
+		}
+	}
+	return merged, nil
+}
+
+func isStringKeyedMap(value interface{}) bool {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	return v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergedView(t *testing.T) {
+	t.Parallel()
+
+	type defaults struct {
+		Region string
+		Labels map[string]string
+		Ports  []int
+	}
+
+	view := NewMergedView(
+		map[string]interface{}{
+			"Name":   "web-1",
+			"Labels": map[string]interface{}{"team": "web"},
+			"Ports":  []int{443},
+		},
+		&defaults{
+			Region: "eu",
+			Labels: map[string]string{"team": "infra", "env": "prod"},
+			Ports:  []int{80, 8080},
+		},
+	)
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"first layer":          {expression: `Name == "web-1"`, result: true},
+		"second layer":         {expression: `Region == "eu"`, result: true},
+		"nested precedence":    {expression: `Labels.team == "web"`, result: true},
+		"nested fallback":      {expression: `Labels.env == "prod"`, result: true},
+		"merged keys":          {expression: `"env" in Labels and "team" in Labels`, result: true},
+		"merged map not empty": {expression: `Labels is not empty`, result: true},
+		"slices not merged":    {expression: `80 in Ports`, result: false},
+		"missing":              {expression: `Labels.zone == "a"`, result: false},
+		"missing everywhere":   {expression: `Zone == "a"`, err: `couldn't find key "Zone"`},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(view)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+
+	t.Run("read only", func(t *testing.T) {
+		t.Parallel()
+
+		labels := map[string]interface{}{"team": "web"}
+		view := NewMergedView(map[string]interface{}{"Labels": labels}, map[string]interface{}{"Labels": map[string]interface{}{"env": "prod"}})
+
+		eval, err := CreateEvaluator(`"env" in Labels`)
+		require.NoError(t, err)
+		result, err := eval.Evaluate(view)
+		require.NoError(t, err)
+		require.Equal(t, true, result)
+		require.Equal(t, map[string]interface{}{"team": "web"}, labels)
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Minify parses the expression, with its selectors written in the dialect,
+// and writes it back in its shortest form, to embed filters in URLs and
+// headers with length limits: without redundant parentheses and whitespace,
+// with the shortest quoting of strings, such as `a"b` rather than "a\x22b",
+// without the trailing zeros of floating point numbers, and with "in" rather
+// than "contains" where it can be written. The expression parses to the same
+// AST as the original one, up to the nesting of chains of the same logical
+// operator and the text of numbers: it is not rewritten, such as
+// not (a == 1) to a != 1, the two differing on missing selectors.
+func Minify(expression string, dialect grammar.SelectorDialect) (string, error) {
+	ast, err := grammar.Parse("", []byte(expression), grammar.Dialect(dialect))
+	if err != nil {
+		return "", newSyntaxError(err)
+	}
+	var b strings.Builder
+	f := &formatter{w: &b, dialect: dialect, preferIn: true, compact: true}
+	f.expression(ast.(grammar.Expression))
+	return b.String(), f.err
+}
+
+// isSymbolicOperator reports whether the match operator is written with
+// symbols, which do not need whitespace around them
+func isSymbolicOperator(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// spaceAfter reports whether an operator written after the value must be
+// preceded by whitespace: literals must be followed by whitespace, a closing
+// parenthesis or the end of the expression, and the JSON Pointers of the
+// JSON Pointer dialect run up to the next whitespace, slash, parenthesis or
+// comparison operator, taking in math operators.
+func (f *formatter) spaceAfter(value *grammar.MatchValue, math bool) bool {
+	if value == nil {
+		return false
+	}
+	switch value.Type {
+	case grammar.ValueTypeBool, grammar.ValueTypeInt, grammar.ValueTypeUint,
+		grammar.ValueTypeFloat64, grammar.ValueTypeNull, grammar.ValueTypeUndefined:
+		return true
+	case grammar.ValueTypeReflect:
+		return math && f.dialect == grammar.SelectorDialectJSONPointer
+	}
+	return false
+}
+
+// lastValue returns the value the expression value ends with, or nil when it
+// ends with a closing parenthesis
+func lastValue(expr *grammar.ExpressionValue) *grammar.MatchValue {
+	if expr == nil {
+		return nil
+	}
+	if expr.Operator == grammar.MathOpValue {
+		return operandLastValue(expr.Left)
+	}
+	return operandLastValue(expr.Right)
+}
+
+// operandLastValue returns the value the operand of a math operator ends
+// with, or nil when it is parenthesized
+func operandLastValue(operand interface{}) *grammar.MatchValue {
+	switch node := operand.(type) {
+	case *grammar.MatchValue:
+		return node
+	case *grammar.ExpressionValue:
+		if node.Operator == grammar.MathOpValue {
+			return lastValue(node)
+		}
+	}
+	return nil
+}
+
+// minifyString quotes the string in the shortest way: as a raw string when
+// it is shorter, or the double quoted string would parse as a JSON Pointer,
+// and it can be written on a single line without backquotes nor control
+// characters, see strconv.CanBackquote, and with double quotes otherwise
+func minifyString(s string) string {
+	quoted := strings.ReplaceAll(strconv.Quote(s), `\"`, `\x22`)
+	if (jsonPointerLiteralRe.MatchString(s) || len(s)+2 < len(quoted)) && strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return quoted
+}
+
+// minifyFloat drops the trailing zeros of the fractional part of the number,
+// keeping one digit after the dot so that it remains a floating point number
+func minifyFloat(raw string) string {
+	i := strings.IndexByte(raw, '.')
+	if i == -1 {
+		return raw
+	}
+	trimmed := strings.TrimRight(raw, "0")
+	if len(trimmed) == i+1 {
+		trimmed += "0"
+	}
+	return trimmed
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinify(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		dialect    grammar.SelectorDialect
+		expected   string
+	}
+
+	tests := map[string]testCase{
+		"match":        {expression: `Meta["env"] == "prod"`, expected: `Meta.env=="prod"`},
+		"parentheses":  {expression: `( (a == 1) and (b != 2) ) or not (c < 3)`, expected: `a==1 and b!=2 or not c<3`},
+		"precedence":   {expression: `(a == 1 or b == 2) and c == 3`, expected: `(a==1 or b==2) and c==3`},
+		"literals":     {expression: `a == true and b == 1.500 and c == 2.0 and d == null`, expected: `a==true and b==1.5 and c==2.0 and d==null`},
+		"after number": {expression: `1 == a and true != b and 1.0 < c`, expected: `1 ==a and true !=b and 1.0 <c`},
+		"strings":      {expression: "a == \"say \\x22hi\\x22\" and b == `/usr` and c == \"a\\nb\"", expected: "a==`say \"hi\"` and b==`/usr` and c==\"a\\nb\""},
+		"in":           {expression: `Tags contains "a" and Tags not contains "b"`, expected: `"a" in Tags and "b" not in Tags`},
+		"keywords":     {expression: `Tags is not empty and Name startswith "a"`, expected: `Tags is not empty and Name startswith "a"`},
+		"math":         {expression: `Port + 1 > 2 and Port - -1 > Weight * 2 and Port / 2 == 1 and 1 + Port < 3`, expected: `Port+1 >2 and Port--1 >Weight*2 and Port /2 ==1 and 1 +Port<3`},
+		"let":          {expression: `let x = Port + 1 in x > 2`, expected: `let x=Port+1 in x>2`},
+		"params":       {expression: `Owner == $user`, expected: `Owner==$user`},
+		"json pointer": {expression: `/a + 1 > 2 and /b / 2 == /c`, dialect: grammar.SelectorDialectJSONPointer, expected: `/a +1 >2 and /b / 2 ==/c`},
+		"jsonpath":     {expression: `$['a'].b + 1 == 2`, dialect: grammar.SelectorDialectJSONPath, expected: `$.a.b+1 ==2`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			minified, err := Minify(tcase.expression, tcase.dialect)
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, minified)
+
+			ast, err := grammar.Parse("", []byte(minified), grammar.Dialect(tcase.dialect))
+			require.NoError(t, err)
+			again, err := Minify(minified, tcase.dialect)
+			require.NoError(t, err)
+			require.Equal(t, minified, again)
+			if tcase.dialect == grammar.SelectorDialectBexpr {
+				equivalent, err := Equivalent(tcase.expression, formatExpression(ast.(grammar.Expression)))
+				require.NoError(t, err)
+				require.True(t, equivalent)
+			}
+		})
+	}
+
+	_, err := Minify(`a ==`, grammar.SelectorDialectBexpr)
+	require.ErrorIs(t, err, ErrSyntax)
+}
+
+// The minified expressions parse to the same AST
+func TestMinify_Generated(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name   string
+		Port   int
+		Weight float64
+		Tags   []string
+		Meta   map[string]string
+	}
+	schema := &grammar.GenerateSchema{Fields: grammar.FieldsOf(service{}, "bexpr")}
+	for seed := int64(0); seed < 200; seed++ {
+		expression := grammar.Generate(rand.New(rand.NewSource(seed)), schema)
+		ast, err := grammar.Parse("", []byte(expression))
+		require.NoError(t, err, expression)
+
+		minified, err := Minify(expression, grammar.SelectorDialectBexpr)
+		require.NoError(t, err, expression)
+		require.LessOrEqual(t, len(minified), len(formatExpression(ast.(grammar.Expression))))
+		reparsed, err := grammar.Parse("", []byte(minified))
+		require.NoError(t, err, minified)
+		require.Equal(t, formatExpression(ast.(grammar.Expression)), formatExpression(reparsed.(grammar.Expression)), minified)
+	}
+}
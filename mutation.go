@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// errDatumMutated is returned by the evaluations which mutated their datum
+// when the evaluator is created with WithMutationCheck
+var errDatumMutated = errors.New("the datum was mutated during the evaluation")
+
+// WithMutationCheck hashes the datum before and after each evaluation, and
+// fails the evaluations which mutated it. Evaluations never mutate the datum
+// themselves: this debug mode catches the hooks, value converters, comparers
+// and selector sources which do. Hashing walks the whole datum, unexported
+// fields included, so that it is not meant for production.
+func WithMutationCheck() Option {
+	return func(o *options) {
+		o.withMutationCheck = true
+	}
+}
+
+// hashDatum hashes the value and everything it references. The entries of
+// maps are hashed independently of their order, and values referencing
+// themselves are hashed up to the cycle.
+func hashDatum(datum interface{}) uint64 {
+	h := &datumHasher{h: fnv.New64a(), visiting: make(map[visit]bool)}
+	h.hash(reflect.ValueOf(datum))
+	return h.h.Sum64()
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type datumHasher struct {
+	h        hash.Hash64
+	visiting map[visit]bool
+	buf      [8]byte
+}
+
+func (h *datumHasher) writeUint(n uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], n)
+	_, _ = h.h.Write(h.buf[:])
+}
+
+func (h *datumHasher) hash(v reflect.Value) {
+	if !v.IsValid() {
+		h.writeUint(0)
+		return
+	}
+	h.writeUint(uint64(v.Kind()))
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.writeUint(1)
+		} else {
+			h.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		h.writeUint(math.Float64bits(real(v.Complex())))
+		h.writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		h.writeUint(uint64(v.Len()))
+		_, _ = h.h.Write([]byte(v.String()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			h.hash(v.Index(i))
+		}
+	case reflect.Slice:
+		if h.nilOrVisiting(v) {
+			return
+		}
+		h.writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h.hash(v.Index(i))
+		}
+		h.leave(v)
+	case reflect.Map:
+		if h.nilOrVisiting(v) {
+			return
+		}
+		h.writeUint(uint64(v.Len()))
+		// the sum of the hashes of the entries does not depend on their order
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := &datumHasher{h: fnv.New64a(), visiting: h.visiting}
+			entry.hash(iter.Key())
+			entry.hash(iter.Value())
+			sum += entry.h.Sum64()
+		}
+		h.writeUint(sum)
+		h.leave(v)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			h.hash(v.Field(i))
+		}
+	case reflect.Ptr:
+		if h.nilOrVisiting(v) {
+			return
+		}
+		h.hash(v.Elem())
+		h.leave(v)
+	case reflect.Interface:
+		if v.IsNil() {
+			h.writeUint(0)
+			return
+		}
+		h.writeUint(1)
+		h.hash(v.Elem())
+	default:
+		// functions, channels and unsafe pointers
+		h.writeUint(uint64(v.Pointer()))
+	}
+}
+
+// nilOrVisiting hashes nil references and the references to the values being
+// hashed, reporting whether the value referenced is to be hashed
+func (h *datumHasher) nilOrVisiting(v reflect.Value) bool {
+	if v.IsNil() {
+		h.writeUint(0)
+		return true
+	}
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if h.visiting[key] {
+		h.writeUint(2)
+		return true
+	}
+	h.visiting[key] = true
+	h.writeUint(1)
+	return false
+}
+
+func (h *datumHasher) leave(v reflect.Value) {
+	delete(h.visiting, visit{ptr: v.Pointer(), typ: v.Type()})
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMutationCheck(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Name   string
+		Parent *node
+		Tags   []string
+	}
+	root := &node{Name: "root"}
+	root.Parent = root
+
+	labels := make(map[string]interface{})
+	for i := 0; i < 100; i++ {
+		labels[strconv.Itoa(i)] = i
+	}
+
+	// a hook normalizing strings in place
+	mutating := func(v reflect.Value) reflect.Value {
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && v.Len() > 0 {
+			v.Index(0).SetString("mutated")
+		}
+		return v
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		hook       ValueTransformationHookFn
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"map": {
+			expression: `labels["42"] == 42`,
+			datum:      map[string]interface{}{"labels": labels},
+			result:     true,
+		},
+		"cycle": {
+			expression: `Parent.Parent.Name == "root"`,
+			datum:      root,
+			result:     true,
+		},
+		"mutating hook": {
+			expression: `"b" in Tags`,
+			datum:      &node{Tags: []string{"a", "b"}},
+			hook:       mutating,
+			err:        "the datum was mutated during the evaluation",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, WithHookFn(tcase.hook), WithMutationCheck())
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(tcase.datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestHashDatum(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{"a": []int{1, 2}, "b": map[int]string{1: "x", 2: "y"}}
+	hash := hashDatum(datum)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, hash, hashDatum(datum))
+	}
+
+	datum["b"].(map[int]string)[2] = "z"
+	require.NotEqual(t, hash, hashDatum(datum))
+	require.NotEqual(t, hashDatum([]int(nil)), hashDatum([]int{}))
+}
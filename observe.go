@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import "time"
+
+// ParseEvent is the creation of an evaluator, see WithOnParse
+type ParseEvent struct {
+	Expression string
+	// Duration is the time taken to parse, validate and compile the
+	// expression
+	Duration time.Duration
+	// Err is the error creating the evaluator, if any
+	Err error
+}
+
+// EvaluateEvent is an evaluation, see WithOnEvaluate
+type EvaluateEvent struct {
+	// Expression is the expression the evaluator was created from
+	Expression string
+	Duration   time.Duration
+	// Result is the outcome of the evaluation, false when Err is set
+	Result bool
+	Err    error
+}
+
+// ErrorEvent is a failure creating or evaluating an evaluator, see
+// WithOnError
+type ErrorEvent struct {
+	Expression string
+	// Parse is set for the errors creating evaluators
+	Parse bool
+	Err   error
+}
+
+// WithOnParse calls the function each time an evaluator is created, or
+// fails to be, with the time it took, so that the usage of the expressions
+// can be measured. When given more than once, all the functions are called,
+// in order.
+func WithOnParse(fn func(ParseEvent)) Option {
+	return func(o *options) {
+		if prev := o.withObserver.onParse; prev != nil {
+			o.withObserver.onParse = func(e ParseEvent) {
+				prev(e)
+				fn(e)
+			}
+			return
+		}
+		o.withObserver.onParse = fn
+	}
+}
+
+// WithOnEvaluate calls the function after each evaluation made with Evaluate
+// or EvaluateContext, and with the methods built on them such as
+// Filter.Execute and EvaluateParallel, with its outcome and the time it took,
+// so that the latency and the failure rate of the expressions can be
+// measured. The time taken by the shadow expression, see WithShadow, is not
+// included. When given more than once, all the functions are called, in
+// order.
+func WithOnEvaluate(fn func(EvaluateEvent)) Option {
+	return func(o *options) {
+		if prev := o.withObserver.onEvaluate; prev != nil {
+			o.withObserver.onEvaluate = func(e EvaluateEvent) {
+				prev(e)
+				fn(e)
+			}
+			return
+		}
+		o.withObserver.onEvaluate = fn
+	}
+}
+
+// WithOnError calls the function each time an evaluator fails to be created
+// or an evaluation fails, the evaluations being the ones of WithOnEvaluate.
+// When given more than once, all the functions are called, in order.
+func WithOnError(fn func(ErrorEvent)) Option {
+	return func(o *options) {
+		if prev := o.withObserver.onError; prev != nil {
+			o.withObserver.onError = func(e ErrorEvent) {
+				prev(e)
+				fn(e)
+			}
+			return
+		}
+		o.withObserver.onError = fn
+	}
+}
+
+// observer holds the callbacks of WithOnParse, WithOnEvaluate and
+// WithOnError
+type observer struct {
+	onParse    func(ParseEvent)
+	onEvaluate func(EvaluateEvent)
+	onError    func(ErrorEvent)
+}
+
+func (o observer) observesParses() bool {
+	return o.onParse != nil || o.onError != nil
+}
+
+func (o observer) observesEvaluations() bool {
+	return o.onEvaluate != nil || o.onError != nil
+}
+
+func (o observer) parsed(expression string, d time.Duration, err error) {
+	if o.onParse != nil {
+		o.onParse(ParseEvent{Expression: expression, Duration: d, Err: err})
+	}
+	if err != nil && o.onError != nil {
+		o.onError(ErrorEvent{Expression: expression, Parse: true, Err: err})
+	}
+}
+
+func (o observer) evaluated(expression string, d time.Duration, result interface{}, err error) {
+	if o.onEvaluate != nil {
+		matched, _ := result.(bool)
+		o.onEvaluate(EvaluateEvent{Expression: expression, Duration: d, Result: matched && err == nil, Err: err})
+	}
+	if err != nil && o.onError != nil {
+		o.onError(ErrorEvent{Expression: expression, Err: err})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver_Parse(t *testing.T) {
+	t.Parallel()
+
+	var parses []ParseEvent
+	var errs []ErrorEvent
+	opts := []Option{
+		WithOnParse(func(e ParseEvent) { parses = append(parses, e) }),
+		WithOnError(func(e ErrorEvent) { errs = append(errs, e) }),
+	}
+
+	_, err := CreateEvaluator(`Name == "web"`, opts...)
+	require.NoError(t, err)
+	_, err = CreateEvaluator(`Name ==`, opts...)
+	require.Error(t, err)
+
+	require.Len(t, parses, 2)
+	require.Equal(t, `Name == "web"`, parses[0].Expression)
+	require.NoError(t, parses[0].Err)
+	require.Positive(t, parses[0].Duration)
+	require.Equal(t, `Name ==`, parses[1].Expression)
+	require.Equal(t, err, parses[1].Err)
+	require.Equal(t, []ErrorEvent{{Expression: `Name ==`, Parse: true, Err: err}}, errs)
+}
+
+func TestObserver_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	var evaluations []EvaluateEvent
+	var errs []ErrorEvent
+	var shadowed int
+	eval, err := CreateEvaluator(`Port > 80`,
+		WithOnEvaluate(func(e EvaluateEvent) { evaluations = append(evaluations, e) }),
+		WithOnError(func(e ErrorEvent) { errs = append(errs, e) }),
+		WithShadow(`Port > 90`, func(*Divergence) { shadowed++ }))
+	require.NoError(t, err)
+
+	for _, datum := range []interface{}{
+		map[string]interface{}{"Port": 443},
+		map[string]interface{}{"Port": 85},
+		map[string]interface{}{"Port": "80"},
+	} {
+		_, _ = eval.Evaluate(datum)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = eval.EvaluateContext(ctx, map[string]interface{}{"Port": 443})
+
+	require.Len(t, evaluations, 4)
+	require.Equal(t, 1, shadowed)
+	for _, e := range evaluations {
+		require.Equal(t, `Port > 80`, e.Expression)
+		require.Positive(t, e.Duration)
+	}
+	require.True(t, evaluations[0].Result)
+	require.NoError(t, evaluations[0].Err)
+	require.True(t, evaluations[1].Result)
+	require.False(t, evaluations[2].Result)
+	require.ErrorIs(t, evaluations[2].Err, ErrUnsupportedOperator)
+	require.False(t, evaluations[3].Result)
+	require.Equal(t, context.Canceled, evaluations[3].Err)
+
+	require.Len(t, errs, 2)
+	require.Equal(t, ErrorEvent{Expression: `Port > 80`, Err: evaluations[2].Err}, errs[0])
+	require.Equal(t, ErrorEvent{Expression: `Port > 80`, Err: context.Canceled}, errs[1])
+}
+
+func TestObserver_Chained(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	eval, err := CreateEvaluator(`Port > 80`,
+		WithOnEvaluate(func(EvaluateEvent) { calls = append(calls, "first") }),
+		WithOnEvaluate(func(EvaluateEvent) { calls = append(calls, "second") }))
+	require.NoError(t, err)
+
+	flt := &Filter{evaluator: eval}
+	_, err = flt.Execute([]map[string]interface{}{{"Port": 443}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, calls)
+}
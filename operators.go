@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// UnsupportedOperatorError is returned when a match operator is applied to a
+// value of a type it does not support.
+type UnsupportedOperatorError = api.UnsupportedOperatorError
+
+// TypeMismatchError is returned, with strict types, when a match operator is
+// applied to values of types it does not compare without coercion.
+type TypeMismatchError = api.TypeMismatchError
+
+// SupportsOperator reports whether the match operator can be applied to a
+// value of the given type, which is the left hand side of the operator or the
+// collection for "in" and "contains". Pointers are dereferenced first.
+//
+//	==, !=                     bool, integers, floats and strings
+//	<, <=, >, >=               bool, integers and floats
+//	in, not in                 maps, slices, arrays and strings
+//	is empty, is not empty     maps, slices, arrays, strings and channels
+//	matches, not matches       strings and []byte
+//	startswith, endswith       strings
+//	like, not like             strings
+//	is null, is not null       any type
+//
+// The comparison operators ==, !=, <, <=, > and >= can also be applied to the
+// types implementing Comparer, and to net.IP and netip.Addr, which are
+// compared numerically.
+//
+// Interface types are reported as supported since the concrete type is only
+// known at evaluation time. Nil values are handled like missing keys, by the
+// operator's NotPresentDisposition, except by == and != which compare them
+// with null and by <, <=, > and >= which never match them.
+func SupportsOperator(op grammar.MatchOperator, typ reflect.Type) bool {
+	if typ == nil {
+		return true
+	}
+	typ = derefType(typ)
+	kind := typ.Kind()
+	if kind == reflect.Interface {
+		return true
+	}
+
+	if isComparisonOperator(op) && isComparer(typ) {
+		return true
+	}
+
+	switch op {
+	case grammar.MatchEqual, grammar.MatchNotEqual:
+		switch kind {
+		case reflect.Bool, reflect.String:
+			return true
+		default:
+			return isNumberKind(kind)
+		}
+	case grammar.MatchLower, grammar.MatchLowerOrEqual, grammar.MatchHigher, grammar.MatchHigherOrEqual:
+		return kind == reflect.Bool || isNumberKind(kind)
+	case grammar.MatchIn, grammar.MatchNotIn:
+		switch kind {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+			return true
+		}
+	case grammar.MatchIsEmpty, grammar.MatchIsNotEmpty:
+		switch kind {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.String, reflect.Chan:
+			return true
+		}
+	case grammar.MatchMatches, grammar.MatchNotMatches:
+		return typ.ConvertibleTo(byteSliceTyp)
+	case grammar.MatchStartsWith, grammar.MatchNotStartsWith, grammar.MatchEndsWith, grammar.MatchNotEndsWith,
+		grammar.MatchLike, grammar.MatchNotLike:
+		return kind == reflect.String
+	case grammar.MatchIsNull, grammar.MatchIsNotNull:
+		return true
+	}
+	return false
+}
+
+// valueClass groups the types compared without coercion with strict types
+func valueClass(typ reflect.Type) string {
+	if typ == nil {
+		return "null"
+	}
+	typ = derefType(typ)
+	switch kind := typ.Kind(); {
+	case kind == reflect.Bool:
+		return "bool"
+	case kind == reflect.String:
+		return "string"
+	case isNumberKind(kind):
+		return "number"
+	default:
+		return typ.String()
+	}
+}
+
+// checkStrictTypes reports the operands of the operator whose types differ,
+// once the left hand side is known to support the operator. Integers and
+// floats are both numbers and compared with each other, and null is compared
+// with any value by == and !=.
+func checkStrictTypes(op grammar.MatchOperator, left, right interface{}) error {
+	leftTyp, rightTyp := reflect.TypeOf(left), reflect.TypeOf(right)
+	switch op {
+	case grammar.MatchEqual, grammar.MatchNotEqual:
+		if left == nil || right == nil {
+			return nil
+		}
+	case grammar.MatchLower, grammar.MatchLowerOrEqual, grammar.MatchHigher, grammar.MatchHigherOrEqual:
+	case grammar.MatchIn, grammar.MatchNotIn:
+		switch leftTyp.Kind() {
+		case reflect.Map:
+			leftTyp = leftTyp.Key()
+		case reflect.Slice, reflect.Array:
+			leftTyp = leftTyp.Elem()
+		}
+		if derefType(leftTyp).Kind() == reflect.Interface {
+			// elements are compared one by one
+			return nil
+		}
+	case grammar.MatchMatches, grammar.MatchNotMatches, grammar.MatchStartsWith, grammar.MatchNotStartsWith,
+		grammar.MatchEndsWith, grammar.MatchNotEndsWith, grammar.MatchLike, grammar.MatchNotLike:
+		// the pattern must be a string, whatever the value matched
+		leftTyp = stringTyp
+	default:
+		return nil
+	}
+
+	if valueClass(leftTyp) != valueClass(rightTyp) {
+		return &TypeMismatchError{Operator: op, Left: leftTyp, Right: rightTyp}
+	}
+	return nil
+}
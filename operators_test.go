@@ -0,0 +1,357 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+// negatedOperators pairs each operator with the one returning the opposite
+// result whenever neither errors
+var negatedOperators = map[grammar.MatchOperator]grammar.MatchOperator{
+	grammar.MatchEqual:        grammar.MatchNotEqual,
+	grammar.MatchIn:           grammar.MatchNotIn,
+	grammar.MatchIsEmpty:      grammar.MatchIsNotEmpty,
+	grammar.MatchMatches:      grammar.MatchNotMatches,
+	grammar.MatchLower:        grammar.MatchHigherOrEqual,
+	grammar.MatchLowerOrEqual: grammar.MatchHigher,
+	grammar.MatchIsNull:       grammar.MatchIsNotNull,
+	grammar.MatchStartsWith:   grammar.MatchNotStartsWith,
+	grammar.MatchEndsWith:     grammar.MatchNotEndsWith,
+	grammar.MatchLike:         grammar.MatchNotLike,
+}
+
+func matrixOperators() []grammar.MatchOperator {
+	var ops []grammar.MatchOperator
+	for op := grammar.MatchEqual; op.String() != "UNKNOWN"; op++ {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func matrixSamples() map[string]interface{} {
+	ten := 10
+	tenPtr := &ten
+	str := "10"
+	var inf interface{} = 10
+
+	return map[string]interface{}{
+		"bool":                   true,
+		"int":                    10,
+		"int8":                   int8(10),
+		"int16":                  int16(10),
+		"int32":                  int32(10),
+		"int64":                  int64(10),
+		"uint":                   uint(10),
+		"uint8":                  uint8(10),
+		"uint16":                 uint16(10),
+		"uint32":                 uint32(10),
+		"uint64":                 uint64(10),
+		"float32":                float32(10),
+		"float64":                float64(10),
+		"string":                 "10",
+		"[]byte":                 []byte("10"),
+		"[]int":                  []int{1, 10},
+		"[]string":               []string{"1", "10"},
+		"[]interface{}":          []interface{}{nil, true, "x", 10},
+		"[2]int":                 [2]int{1, 10},
+		"map[string]int":         map[string]int{"10": 1},
+		"map[int]string":         map[int]string{10: "a"},
+		"map[float64]bool":       map[float64]bool{10: true},
+		"map[bool]int":           map[bool]int{true: 1},
+		"map[interface{}]string": map[interface{}]string{int8(10): "a", "x": "b"},
+		"struct":                 testFlatStruct{},
+		"*int":                   tenPtr,
+		"**int":                  &tenPtr,
+		"*string":                &str,
+		"*[]int":                 &[]int{10},
+		"*interface{}":           &inf,
+		"json.Number int":        json.Number("10"),
+		"json.Number float":      json.Number("10.5"),
+		"chan":                   make(chan int),
+		"func":                   func() {},
+		"nil":                    nil,
+		"nil *int":               (*int)(nil),
+		"nil []int":              ([]int)(nil),
+		"nil map[string]int":     (map[string]int)(nil),
+		"nil *map[string]int":    (*map[string]int)(nil),
+		"nil in []interface{}":   []interface{}{nil},
+	}
+}
+
+func matrixExpression(op grammar.MatchOperator) *grammar.MatchExpression {
+	expr := &grammar.MatchExpression{
+		Operator: op,
+		Left: &grammar.ExpressionValue{Left: &grammar.MatchValue{
+			Selector: grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: []string{"value"}},
+			Type:     grammar.ValueTypeReflect,
+		}},
+	}
+	switch op {
+	case grammar.MatchIsEmpty, grammar.MatchIsNotEmpty, grammar.MatchIsNull, grammar.MatchIsNotNull:
+	default:
+		expr.Right = &grammar.ExpressionValue{Left: &grammar.MatchValue{Raw: "10", Type: grammar.ValueTypeString}}
+	}
+	return expr
+}
+
+func TestOperatorMatrix(t *testing.T) {
+	t.Parallel()
+
+	for name, sample := range matrixSamples() {
+		sample := sample
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			datum := map[string]interface{}{"value": sample}
+			results := make(map[grammar.MatchOperator]bool)
+			for _, op := range matrixOperators() {
+				var result bool
+				var err error
+				require.NotPanics(t, func() {
+					result, err = evaluateMatchExpression(matrixExpression(op), datum)
+				}, op.String())
+
+				// json.Number values are converted before the operator applies
+				value, valueErr := getExprValue(matrixExpression(op).Left, datum)
+				require.NoError(t, valueErr)
+				typ := reflect.TypeOf(indirect(value))
+				if isNull(sample) || SupportsOperator(op, typ) {
+					require.NoError(t, err, op.String())
+					results[op] = result
+					continue
+				}
+
+				var unsupported *UnsupportedOperatorError
+				require.True(t, errors.As(err, &unsupported), "%s: %v", op, err)
+				require.Equal(t, op, unsupported.Operator)
+				require.Equal(t, typ, unsupported.Type)
+			}
+
+			for op, negated := range negatedOperators {
+				result, ok := results[op]
+				if !ok || unordered(op, sample) {
+					// nil values are neither lower nor higher than anything
+					continue
+				}
+				require.Contains(t, results, negated, op.String())
+				require.Equal(t, !result, results[negated], fmt.Sprintf("%s and %s", op, negated))
+			}
+		})
+	}
+}
+
+func TestOperatorMatrix_Results(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		op     grammar.MatchOperator
+		sample interface{}
+		result bool
+	}
+
+	ten := 10
+	str := "10"
+	tests := map[string]testCase{
+		"in map[int]string":             {op: grammar.MatchIn, sample: map[int]string{10: "a"}, result: true},
+		"in map[float64]bool":           {op: grammar.MatchIn, sample: map[float64]bool{10: true}, result: true},
+		"in map[interface{}]string":     {op: grammar.MatchIn, sample: map[interface{}]string{int8(10): "a"}, result: true},
+		"in map[int]string missing key": {op: grammar.MatchIn, sample: map[int]string{1: "a"}, result: false},
+		"in interface slice with nil":   {op: grammar.MatchIn, sample: []interface{}{nil, "10"}, result: true},
+		"in byte slice":                 {op: grammar.MatchIn, sample: []byte{1, 10}, result: true},
+		"equal pointer":                 {op: grammar.MatchEqual, sample: &ten, result: true},
+		"matches pointer":               {op: grammar.MatchMatches, sample: &str, result: true},
+		"higher":                        {op: grammar.MatchHigher, sample: 11, result: true},
+		"lower nil pointer":             {op: grammar.MatchLower, sample: (*int)(nil), result: false},
+		"higher nil pointer":            {op: grammar.MatchHigher, sample: (*int)(nil), result: false},
+		"is empty nil slice":            {op: grammar.MatchIsEmpty, sample: ([]int)(nil), result: true},
+		"equal json.Number":             {op: grammar.MatchEqual, sample: json.Number("10"), result: true},
+		"higher or equal json.Number":   {op: grammar.MatchHigherOrEqual, sample: json.Number("10.5"), result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := evaluateMatchExpression(matrixExpression(tcase.op), map[string]interface{}{"value": tcase.sample})
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestSupportsOperator(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		op        grammar.MatchOperator
+		value     interface{}
+		supported bool
+	}
+
+	tests := map[string]testCase{
+		"in map[int]string":        {op: grammar.MatchIn, value: map[int]string{}, supported: true},
+		"in string":                {op: grammar.MatchIn, value: "", supported: true},
+		"in int":                   {op: grammar.MatchIn, value: 1, supported: false},
+		"equal pointer":            {op: grammar.MatchEqual, value: new(float32), supported: true},
+		"equal struct":             {op: grammar.MatchEqual, value: testFlatStruct{}, supported: false},
+		"lower bool":               {op: grammar.MatchLower, value: true, supported: true},
+		"lower string":             {op: grammar.MatchLower, value: "", supported: false},
+		"is empty chan":            {op: grammar.MatchIsEmpty, value: make(chan int), supported: true},
+		"is empty bool":            {op: grammar.MatchIsEmpty, value: false, supported: false},
+		"matches bytes":            {op: grammar.MatchMatches, value: []byte{}, supported: true},
+		"matches int":              {op: grammar.MatchMatches, value: 1, supported: false},
+		"ends with string pointer": {op: grammar.MatchEndsWith, value: new(string), supported: true},
+		"is null struct":           {op: grammar.MatchIsNull, value: testFlatStruct{}, supported: true},
+		"interface":                {op: grammar.MatchLower, value: new(interface{}), supported: true},
+		"nil":                      {op: grammar.MatchStartsWith, value: nil, supported: true},
+		"unknown operator":         {op: grammar.MatchOperator(1000), value: 1, supported: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tcase.supported, SupportsOperator(tcase.op, reflect.TypeOf(tcase.value)))
+		})
+	}
+}
+
+func TestStrictTypes(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"name":    "web",
+		"port":    8080,
+		"weight":  1.5,
+		"enabled": true,
+		"flag":    "true",
+		"count":   "3",
+		"tags":    []string{"a", "b"},
+		"ports":   []int{80, 443},
+		"mixed":   []interface{}{"1", 2, true},
+		"labels":  map[string]string{"1": "one"},
+		"ids":     map[interface{}]bool{"1": true, 2: true},
+		"nothing": nil,
+	}
+
+	type testCase struct {
+		expression string
+		// lenient is the result without strict types
+		lenient bool
+		result  bool
+		err     string
+	}
+
+	tests := map[string]testCase{
+		"same types": {
+			expression: `name == "web" and port == 8080 and enabled == true`,
+			lenient:    true,
+			result:     true,
+		},
+		"integer and float": {
+			expression: `weight > 1 and port < 9000.5`,
+			lenient:    true,
+			result:     true,
+		},
+		"null": {
+			expression: `nothing == null and name != null`,
+			lenient:    true,
+			result:     true,
+		},
+		"string and bool": {
+			expression: `flag == true`,
+			lenient:    true,
+			err:        `1:1 (0): flag == true: operator "Equal" cannot compare values of type string and bool with strict types`,
+		},
+		"string and number": {
+			expression: `count != 3`,
+			lenient:    false,
+			err:        `1:1 (0): count != 3: operator "Not Equal" cannot compare values of type string and int64 with strict types`,
+		},
+		"number and string": {
+			expression: `port == "8080"`,
+			lenient:    true,
+			err:        `1:1 (0): port == "8080": operator "Equal" cannot compare values of type int and string with strict types`,
+		},
+		"ordering": {
+			expression: `port >= "80"`,
+			lenient:    true,
+			err:        `1:1 (0): port >= "80": operator "Higher or Equal" cannot compare values of type int and string with strict types`,
+		},
+		"in typed slice": {
+			expression: `"80" in ports`,
+			lenient:    true,
+			err:        `1:1 (0): ports contains "80": operator "In" cannot compare values of type int and string with strict types`,
+		},
+		"in typed map": {
+			expression: `1 in labels`,
+			lenient:    true,
+			err:        `1:1 (0): labels contains 1: operator "In" cannot compare values of type string and int64 with strict types`,
+		},
+		"in string": {
+			expression: `1 not in name`,
+			lenient:    true,
+			err:        `1:1 (0): name not contains 1: operator "Not In" cannot compare values of type string and int64 with strict types`,
+		},
+		"in same types": {
+			expression: `"a" in tags and 443 in ports and "1" in labels`,
+			lenient:    true,
+			result:     true,
+		},
+		"in interface slice": {
+			expression: `1 in mixed`,
+			lenient:    true,
+			result:     false,
+		},
+		"in interface slice same type": {
+			expression: `2 in mixed and "1" in mixed`,
+			lenient:    true,
+			result:     true,
+		},
+		"in interface keyed map": {
+			expression: `"2" in ids`,
+			lenient:    true,
+			result:     false,
+		},
+		"pattern": {
+			expression: `name matches 1`,
+			lenient:    false,
+			err:        `1:1 (0): name matches 1: operator "Matches" cannot compare values of type string and int64 with strict types`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			result, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, tcase.lenient, result)
+
+			eval, err = CreateEvaluator(tcase.expression, WithStrictTypes())
+			require.NoError(t, err)
+			result, err = eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				var mismatch *TypeMismatchError
+				require.ErrorAs(t, err, &mismatch)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
@@ -3,6 +3,13 @@
 
 package bexpr
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
 // getOpts - iterate the inbound Options and return a struct
 func getOpts(opt ...Option) options {
 	opts := getDefaultOptions()
@@ -14,15 +21,153 @@ func getOpts(opt ...Option) options {
 	return opts
 }
 
+// validate reports the options which are invalid, or which cannot be used
+// together, so that they fail the creation of evaluators instead of being
+// silently ignored when evaluating.
+func (o options) validate() error {
+	if o.withTagName == "" {
+		return errors.New("the tag name cannot be empty")
+	}
+	for _, tagName := range o.withTagNames {
+		if tagName == "" {
+			return errors.New("the tag names cannot be empty")
+		}
+	}
+	if o.withMaxLiteralLength < 0 {
+		return fmt.Errorf("the maximum literal length cannot be negative, got %d", o.withMaxLiteralLength)
+	}
+	if o.withMaxLiteralBytes < 0 {
+		return fmt.Errorf("the maximum literal bytes cannot be negative, got %d", o.withMaxLiteralBytes)
+	}
+	if err := o.withRegexLimits.validate(); err != nil {
+		return err
+	}
+	switch o.withSelectorDialect {
+	case grammar.SelectorDialectBexpr, grammar.SelectorDialectJSONPointer, grammar.SelectorDialectJSONPath:
+	default:
+		return fmt.Errorf("unknown selector dialect %d", o.withSelectorDialect)
+	}
+	if o.withUnknownResult != nil {
+		// three-valued logic resolves missing values to Unknown, and does not
+		// record the evaluations
+		switch {
+		case o.withUnknown != nil:
+			return errors.New("WithUnknownValue cannot be used with WithUnknownResult")
+		case o.withTrace != nil:
+			return errors.New("WithTrace cannot be used with WithUnknownResult")
+		case o.withStats != nil:
+			return errors.New("WithStats cannot be used with WithUnknownResult")
+		case o.withStrictSelectors:
+			return errors.New("WithStrictSelectors cannot be used with WithUnknownResult")
+		}
+	}
+	if o.withStrictSelectors && o.withUnknown != nil {
+		return errors.New("WithUnknownValue cannot be used with WithStrictSelectors")
+	}
+	for _, a := range o.withFieldAliases {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("invalid field alias: %w", err)
+		}
+	}
+	for _, d := range o.withDefaults {
+		if err := d.selectorPattern.validate(); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	for _, hook := range o.withSelectorHooks {
+		if err := hook.selectorPattern.validate(); err != nil {
+			return fmt.Errorf("invalid selector hook: %w", err)
+		}
+	}
+	for typ := range o.withCoercions {
+		if !coercible(typ) {
+			return fmt.Errorf("invalid coercion: values cannot be coerced to the value type %d", typ)
+		}
+	}
+	for _, c := range o.withSelectorCoercions {
+		if err := c.selectorPattern.validate(); err != nil {
+			return fmt.Errorf("invalid selector coercion: %w", err)
+		}
+		if !coercible(c.valueType) {
+			return fmt.Errorf("invalid selector coercion of %q: values cannot be coerced to the value type %d", c.pattern, c.valueType)
+		}
+	}
+	for _, p := range o.withDeniedFields {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid denied fields: %w", err)
+		}
+	}
+	for _, p := range o.withAllowedSelectors {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid allowed selectors: %w", err)
+		}
+	}
+	for _, p := range o.withDeniedSelectors {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid denied selectors: %w", err)
+		}
+	}
+	return nil
+}
+
+// coercible reports whether the values of the value type are the target of
+// coercions, see coercionType
+func coercible(typ grammar.ValueType) bool {
+	switch typ {
+	case grammar.ValueTypeBool, grammar.ValueTypeInt, grammar.ValueTypeUint,
+		grammar.ValueTypeFloat32, grammar.ValueTypeFloat64, grammar.ValueTypeString:
+		return true
+	}
+	return false
+}
+
 // Option - how Options are passed as arguments
 type Option func(*options)
 
 // options = how options are represented
 type options struct {
-	withMaxExpressions uint64
-	withTagName        string
-	withHookFn         ValueTransformationHookFn
-	withUnknown        *interface{}
+	withMaxExpressions    uint64
+	withMaxLiteralLength  int
+	withMaxLiteralBytes   int
+	withKeywordAliases    map[string]string
+	withReservedKeywords  bool
+	withSelectorDialect   grammar.SelectorDialect
+	withSelectorPrefix    []string
+	withFieldAliases      []fieldAlias
+	withTagName           string
+	withTagNames          []string
+	withHookFn            ValueTransformationHookFn
+	withSelectorHooks     []selectorHook
+	withValueConverters   []valueConverter
+	withFieldCache        *fieldCache
+	withFieldAccess       FieldAccessFn
+	withUnknown           *interface{}
+	withDefaults          []selectorDefault
+	withSchema            Schema
+	withTrace             func(*Trace)
+	withDeniedFields      []selectorPattern
+	withAllowedSelectors  []selectorPattern
+	withDeniedSelectors   []selectorPattern
+	withStrictTypes       bool
+	withStrictSelectors   bool
+	withFieldDocs         FieldDocs
+	withUnknownResult     *bool
+	withParams            map[string]interface{}
+	withBindings          map[string]interface{}
+	withMacros            map[string]string
+	withCoercions         map[grammar.ValueType]Coercion
+	withSelectorCoercions []selectorCoercion
+	withStats             *Stats
+	withStatsRule         string
+	withShadow            *shadowOptions
+	withDecimal           bool
+	withRecorder          *Recorder
+	withMutationCheck     bool
+	withDeterministic     bool
+	withRegexLimits       regexLimits
+	withObserver          observer
+	withTracer            Tracer
+	withLogger            Logger
 }
 
 func WithMaxExpressions(maxExprCnt uint64) Option {
@@ -31,10 +176,104 @@ func WithMaxExpressions(maxExprCnt uint64) Option {
 	}
 }
 
+// WithMaxLiteralLength fails the creation of evaluators on expressions with
+// string literals longer than maxLen bytes, to keep untrusted expressions from
+// being used to store data. The literals of the macros, see WithMacros, are
+// not limited. 0, the default, means no limit.
+func WithMaxLiteralLength(maxLen int) Option {
+	return func(o *options) {
+		o.withMaxLiteralLength = maxLen
+	}
+}
+
+// WithMaxLiteralBytes fails the creation of evaluators on expressions whose
+// string literals amount to more than maxBytes bytes. 0, the default, means no
+// limit.
+func WithMaxLiteralBytes(maxBytes int) Option {
+	return func(o *options) {
+		o.withMaxLiteralBytes = maxBytes
+	}
+}
+
+// WithMaxPatternLength bounds the length in bytes of the regular expressions
+// of the matches and not matches operators, to accept them from untrusted
+// expressions. The expressions with longer literal patterns fail the creation
+// of evaluators, and the longer patterns found in the datum or in parameters
+// fail the evaluations, with errors matching ErrRegexLimit. 0, the default,
+// means no limit.
+func WithMaxPatternLength(maxLen int) Option {
+	return func(o *options) {
+		o.withRegexLimits.maxPatternLength = maxLen
+	}
+}
+
+// WithMaxPatternSize bounds the size of the regular expressions like
+// WithMaxPatternLength, their size being the number of instructions of the
+// program they compile to. It rejects the patterns which are short but
+// expensive to compile and match, such as nested repetitions. 0, the
+// default, means no limit.
+func WithMaxPatternSize(maxInstructions int) Option {
+	return func(o *options) {
+		o.withRegexLimits.maxPatternSize = maxInstructions
+	}
+}
+
+// WithMaxMatchLength bounds the length in bytes of the values matched
+// against regular expressions: matching longer values fails the evaluations
+// with errors matching ErrRegexLimit. 0, the default, means no limit.
+func WithMaxMatchLength(maxLen int) Option {
+	return func(o *options) {
+		o.withRegexLimits.maxMatchLength = maxLen
+	}
+}
+
+// WithKeywordAliases accepts aliases of the keywords of the expressions and
+// of the macros, such as "et" and "ou" for "and" and "or", or "contient" for
+// "contains", mapped to the keywords they stand for, see
+// grammar.KeywordAliases. The keywords themselves are always accepted. Invalid
+// aliases fail the creation of evaluators. When given more than once, the
+// aliases are merged.
+func WithKeywordAliases(aliases map[string]string) Option {
+	return func(o *options) {
+		merged := make(map[string]string, len(o.withKeywordAliases)+len(aliases))
+		for alias, keyword := range o.withKeywordAliases {
+			merged[alias] = keyword
+		}
+		for alias, keyword := range aliases {
+			merged[alias] = keyword
+		}
+		o.withKeywordAliases = merged
+	}
+}
+
+// WithReservedKeywords reserves the keywords and their aliases, see
+// grammar.ReservedKeywords: fields named like keywords are then written
+// ["in"] rather than in, and expressions reading keywords as selectors fail
+// to parse instead of being read one way or the other depending on their
+// position.
+func WithReservedKeywords() Option {
+	return func(o *options) {
+		o.withReservedKeywords = true
+	}
+}
+
+// WithSelectorDialect parses the selectors of the expression, and of its
+// macros, in the dialect, see grammar.Dialect: RFC 6901 JSON Pointers such as
+// /Meta/env, or JSONPaths made of member names and array indexes such as
+// $.Meta.env or $.Tags[0]. The expression is written back in the dialect by
+// Evaluator.WriteTo. Macros are referenced the way single-part selectors are
+// written in the dialect, such as /is_admin or $.is_admin.
+func WithSelectorDialect(dialect grammar.SelectorDialect) Option {
+	return func(o *options) {
+		o.withSelectorDialect = dialect
+	}
+}
+
 // WithTagName indictes what tag to use instead of the default "bexpr"
 func WithTagName(tagName string) Option {
 	return func(o *options) {
 		o.withTagName = tagName
+		o.withTagNames = nil
 	}
 }
 
@@ -42,9 +281,25 @@ func WithTagName(tagName string) Option {
 // and all subfields, indexes, and values recursively.  That makes it
 // easier for the JSON Pointer to not match exactly the Go value being
 // evaluated (for example, when using protocol buffers' well-known types).
+//
+// When given more than once, the hooks are chained in the order they were
+// given, see ChainHookFns.
 func WithHookFn(fn ValueTransformationHookFn) Option {
 	return func(o *options) {
-		o.withHookFn = fn
+		o.withHookFn = ChainHookFns(o.withHookFn, fn)
+	}
+}
+
+// WithSelectorHook sets a HookFn to be called only on the values found at or
+// below the selectors matching the pattern, such as "Meta" or "Meta.*", where
+// a "*" matches any single part of the selector. Patterns starting with "/"
+// are JSON Pointers. Selector hooks run after the hooks set with WithHookFn,
+// in the order they were given.
+func WithSelectorHook(pattern string, fn ValueTransformationHookFn) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.withSelectorHooks = append(o.withSelectorHooks, newSelectorHook(pattern, fn))
+		}
 	}
 }
 
@@ -58,6 +313,196 @@ func WithUnknownValue(val interface{}) Option {
 	}
 }
 
+// WithSchema validates the expression against the given schema when the
+// evaluator is created. Unknown selectors and operators which cannot be used
+// with the selected type are reported as errors instead of failing on the
+// first evaluation.
+func WithSchema(schema Schema) Option {
+	return func(o *options) {
+		o.withSchema = schema
+	}
+}
+
+// WithTrace records, for every node of the expression evaluated, the values
+// its operands resolved to and its outcome. The trace is passed to the given
+// function after each evaluation, before Evaluate returns.
+func WithTrace(fn func(*Trace)) Option {
+	return func(o *options) {
+		o.withTrace = fn
+	}
+}
+
+// WithStrictTypes makes match operators report a TypeMismatchError when the
+// types of their operands differ, such as a string compared with a number,
+// instead of coercing one into the other. Integers and floats are compared
+// with each other, and == and != compare any value with null. The elements of
+// interface slices and the keys of interface keyed maps of another type than
+// the value looked up with "in" never match it.
+func WithStrictTypes() Option {
+	return func(o *options) {
+		o.withStrictTypes = true
+	}
+}
+
+// WithStrictSelectors makes the selectors missing from the datum fail the
+// evaluation with an UnknownSelectorError reporting their path, rather than
+// being handled with the NotPresentDisposition of the operator when the value
+// they are missing from is a map, such as Meta.region when Meta has no region
+// key. The values of WithDefaults still stand for the missing selectors they
+// are given for. Null values are not missing.
+func WithStrictSelectors() Option {
+	return func(o *options) {
+		o.withStrictSelectors = true
+	}
+}
+
+// WithParams binds values to the parameters of the expression, such as $user
+// in `Owner == $user`. Given to Evaluate, the values are bound for that
+// evaluation only, so that an expression can be created once and evaluated
+// with the values of each request without interpolating them into the
+// expression. Evaluating a parameter without a value is an error. When given
+// more than once, the values are merged.
+func WithParams(params map[string]interface{}) Option {
+	return func(o *options) {
+		merged := make(map[string]interface{}, len(o.withParams)+len(params))
+		for name, value := range o.withParams {
+			merged[name] = value
+		}
+		for name, value := range params {
+			merged[name] = value
+		}
+		o.withParams = merged
+	}
+}
+
+// WithMacros names boolean expressions, to be referenced by name from the
+// expression and from the other macros, such as is_prod in
+// `is_prod and Role == "admin"` given the macro is_prod: `Env == "prod"`. A
+// reference is a name used as an expression on its own, outside of match
+// expressions, and is replaced by the expression of the macro when the
+// evaluator is created. When given more than once, the macros are merged.
+func WithMacros(macros map[string]string) Option {
+	return func(o *options) {
+		merged := make(map[string]string, len(o.withMacros)+len(macros))
+		for name, expression := range o.withMacros {
+			merged[name] = expression
+		}
+		for name, expression := range macros {
+			merged[name] = expression
+		}
+		o.withMacros = merged
+	}
+}
+
+// WithCoercion overrides the coercion of the values compared with values of
+// the value type, such as the string literal compared with an integer field in
+// `Count == "1,000"`, or the keys looked up with "in" in maps keyed by the
+// value type. The values compared are converted with fn instead of strconv:
+//
+//	bexpr.WithCoercion(grammar.ValueTypeInt, func(v interface{}) (interface{}, error) {
+//		return strconv.ParseInt(strings.ReplaceAll(fmt.Sprint(v), ",", ""), 10, 64)
+//	})
+//
+// Only the values of another kind are converted: integers and floats are
+// compared with each other as numbers. The error of fn fails the evaluation.
+// When given more than once for the same value type, the last one wins.
+func WithCoercion(valueType grammar.ValueType, fn Coercion) Option {
+	return func(o *options) {
+		if fn == nil {
+			return
+		}
+		coercions := make(map[grammar.ValueType]Coercion, len(o.withCoercions)+1)
+		for typ, c := range o.withCoercions {
+			coercions[typ] = c
+		}
+		coercions[valueType] = fn
+		o.withCoercions = coercions
+	}
+}
+
+// WithSelectorCoercion converts the values found at or below the selectors
+// matching the pattern into the value type with fn before they are compared,
+// whatever the value they are compared with, such as a string field holding
+// "yes" or "no" to be compared as a boolean. Values which already have the
+// value type are not converted. Patterns are matched the same way as the
+// ones of WithSelectorHook, and the first matching coercion applies. Values
+// compared with the converted ones are coerced as usual, see WithCoercion.
+func WithSelectorCoercion(pattern string, valueType grammar.ValueType, fn Coercion) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.withSelectorCoercions = append(o.withSelectorCoercions, selectorCoercion{
+				selectorPattern: newSelectorPattern(pattern),
+				valueType:       valueType,
+				fn:              fn,
+			})
+		}
+	}
+}
+
+// WithDecimalArithmetic performs math and comparisons on floats with exact
+// decimals, as big.Rat, rather than float64, so that `Amount * 0.0725 > 10`
+// does not suffer from rounding. Float literals and JSON numbers are parsed as
+// exact decimals, and floats found in the datum are converted from their
+// shortest decimal representation, 0.1 being 1/10. Math on integers only is
+// unchanged. Values of math/big, big.Int, big.Float and big.Rat, are compared
+// and computed exactly regardless of this option.
+func WithDecimalArithmetic() Option {
+	return func(o *options) {
+		o.withDecimal = true
+	}
+}
+
+// withBinding binds the value to the name while evaluating the body of a let
+// expression, shadowing the value bound to the name by outer let expressions
+func withBinding(name string, value interface{}) Option {
+	return func(o *options) {
+		bindings := make(map[string]interface{}, len(o.withBindings)+1)
+		for n, v := range o.withBindings {
+			bindings[n] = v
+		}
+		bindings[name] = value
+		o.withBindings = bindings
+	}
+}
+
+// WithUnknownResult makes Evaluate use three-valued logic, see
+// EvaluateTristate, the Unknown outcome being reported as the given result.
+// Missing values being Unknown and traces not being recorded in this mode, it
+// cannot be used with WithUnknownValue, WithTrace or WithStats.
+func WithUnknownResult(result bool) Option {
+	return func(o *options) {
+		o.withUnknownResult = &result
+	}
+}
+
+// WithFieldDocs documents the fields found at selectors, see FieldDocs. The
+// documentation is added to the validation errors about the fields and to the
+// JSON Schema generated by GenerateJSONSchema, taking precedence over the
+// documentation of the schema. When given more than once, the registries are
+// merged.
+func WithFieldDocs(docs FieldDocs) Option {
+	return func(o *options) {
+		if o.withFieldDocs == nil {
+			o.withFieldDocs = make(FieldDocs, len(docs))
+		}
+		for key, doc := range docs {
+			o.withFieldDocs[key] = doc
+		}
+	}
+}
+
+// WithDeniedFields leaves the fields at or below the selectors matching the
+// patterns out of the JSON Schema generated by GenerateJSONSchema. Patterns
+// are matched the same way as the ones of WithSelectorHook, the items of
+// slices and the values of maps being matched by "*".
+func WithDeniedFields(patterns ...string) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			o.withDeniedFields = append(o.withDeniedFields, newSelectorPattern(pattern))
+		}
+	}
+}
+
 func getDefaultOptions() options {
 	return options{
 		withMaxExpressions: 0,
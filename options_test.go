@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEvaluator_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	hook := func(v reflect.Value) reflect.Value { return v }
+	coercion := func(v interface{}) (interface{}, error) { return v, nil }
+
+	type testCase struct {
+		opts []Option
+		err  string
+	}
+
+	tests := map[string]testCase{
+		"valid": {opts: []Option{
+			WithTagName("json"),
+			WithMaxLiteralLength(10),
+			WithSelectorHook("/a//b", hook),
+			WithCoercion(grammar.ValueTypeInt, coercion),
+			WithUnknownResult(false),
+		}},
+		"tag name":               {opts: []Option{WithTagName("")}, err: `invalid options: the tag name cannot be empty`},
+		"literal length":         {opts: []Option{WithMaxLiteralLength(-1)}, err: `invalid options: the maximum literal length cannot be negative, got -1`},
+		"literal bytes":          {opts: []Option{WithMaxLiteralBytes(-1)}, err: `invalid options: the maximum literal bytes cannot be negative, got -1`},
+		"pattern length":         {opts: []Option{WithMaxPatternLength(-1)}, err: `invalid options: the maximum pattern length cannot be negative, got -1`},
+		"pattern size":           {opts: []Option{WithMaxPatternSize(-1)}, err: `invalid options: the maximum pattern size cannot be negative, got -1`},
+		"match length":           {opts: []Option{WithMaxMatchLength(-1)}, err: `invalid options: the maximum match length cannot be negative, got -1`},
+		"dialect":                {opts: []Option{WithSelectorDialect(grammar.SelectorDialect(9))}, err: `invalid options: unknown selector dialect 9`},
+		"unknown value":          {opts: []Option{WithUnknownValue(""), WithUnknownResult(false)}, err: `invalid options: WithUnknownValue cannot be used with WithUnknownResult`},
+		"trace":                  {opts: []Option{WithUnknownResult(true), WithTrace(func(*Trace) {})}, err: `invalid options: WithTrace cannot be used with WithUnknownResult`},
+		"stats":                  {opts: []Option{WithUnknownResult(true), WithStats(NewStats(), "rule")}, err: `invalid options: WithStats cannot be used with WithUnknownResult`},
+		"empty hook pattern":     {opts: []Option{WithSelectorHook("", hook)}, err: `invalid options: invalid selector hook: the pattern cannot be empty`},
+		"hook pattern parts":     {opts: []Option{WithSelectorHook("Meta..env", hook)}, err: `invalid options: invalid selector hook: the pattern "Meta..env" has empty parts`},
+		"coercion type":          {opts: []Option{WithCoercion(grammar.ValueTypeNull, coercion)}, err: `invalid options: invalid coercion: values cannot be coerced to the value type 8`},
+		"selector coercion type": {opts: []Option{WithSelectorCoercion("a", grammar.ValueTypeReflect, coercion)}, err: `invalid options: invalid selector coercion of "a": values cannot be coerced to the value type 7`},
+		"selector coercion":      {opts: []Option{WithSelectorCoercion("a.", grammar.ValueTypeBool, coercion)}, err: `invalid options: invalid selector coercion: the pattern "a." has empty parts`},
+		"strict selectors":       {opts: []Option{WithUnknownResult(false), WithStrictSelectors()}, err: `invalid options: WithStrictSelectors cannot be used with WithUnknownResult`},
+		"strict unknown value":   {opts: []Option{WithUnknownValue(""), WithStrictSelectors()}, err: `invalid options: WithUnknownValue cannot be used with WithStrictSelectors`},
+		"denied fields":          {opts: []Option{WithDeniedFields("")}, err: `invalid options: invalid denied fields: the pattern cannot be empty`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(`a == 1`, tcase.opts...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
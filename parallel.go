@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelChunk is the number of elements a worker evaluates at once. It is a
+// multiple of the 64 bits of the words of a Bitmask, so that the workers never
+// set bits of the same word.
+const parallelChunk = 1024
+
+// EvaluateParallel evaluates the expression against every element of the
+// slice or array with a pool of workers, and returns the bitmask of the
+// elements which matched. The elements are sharded in chunks the workers take
+// in turn. A number of workers below 1 uses one worker per CPU, see
+// runtime.GOMAXPROCS. The evaluation stops at the first error, which is
+// returned with the index of the element which failed, or once the context is
+// done, with its error.
+func (eval *Evaluator) EvaluateParallel(ctx context.Context, data interface{}, workers int) (Bitmask, error) {
+	rvalue := reflect.ValueOf(data)
+	if kind := rvalue.Kind(); kind != reflect.Slice && kind != reflect.Array {
+		return nil, fmt.Errorf("only slices and arrays can be evaluated in parallel, not %s", kind)
+	}
+	return evaluateParallel(ctx, rvalue.Len(), workers, func(ctx context.Context, i int) (bool, error) {
+		return eval.evaluateElement(ctx, rvalue.Index(i))
+	})
+}
+
+// evaluateElement evaluates the expression against an element of a container
+func (eval *Evaluator) evaluateElement(ctx context.Context, item reflect.Value) (bool, error) {
+	if !item.CanInterface() {
+		return false, fmt.Errorf("value of type %s cannot be used", item.Type())
+	}
+	return eval.evaluateItem(ctx, item.Interface())
+}
+
+// evaluateParallel calls fn for the n elements with a pool of workers, and
+// returns the bitmask of the elements for which it returned true
+func evaluateParallel(ctx context.Context, n int, workers int, fn func(context.Context, int) (bool, error)) (Bitmask, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if chunks := (n + parallelChunk - 1) / parallelChunk; workers > chunks {
+		workers = chunks
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	matched := newBitmask(n)
+	var next int64
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				start := int(atomic.AddInt64(&next, parallelChunk)) - parallelChunk
+				if start >= n {
+					return
+				}
+				end := start + parallelChunk
+				if end > n {
+					end = n
+				}
+				for i := start; i < end; i++ {
+					ok, err := fn(ctx, i)
+					if err != nil {
+						once.Do(func() {
+							// the errors of the evaluations given up on are not
+							// the ones of the elements
+							if ctx.Err() == nil {
+								firstErr = fmt.Errorf("error evaluating element %d: %w", i, err)
+							}
+							cancel()
+						})
+						return
+					}
+					if ok {
+						matched.set(i)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// the workers cancel their context on errors only, so it was done by the
+	// parent one
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+// ExecuteParallel executes the filter like Execute, evaluating the elements
+// of the slice, array or map with a pool of workers, see
+// Evaluator.EvaluateParallel. The elements of slices and arrays keep their
+// order. If called on a nil filter this is a no-op and will return the
+// original data.
+func (f *Filter) ExecuteParallel(ctx context.Context, data interface{}, workers int) (interface{}, error) {
+	if f == nil {
+		return data, nil
+	}
+
+	rvalue := reflect.ValueOf(data)
+	rtype := rvalue.Type()
+
+	switch rvalue.Kind() {
+	case reflect.Array:
+		// For arrays we return slices instead of fixed sized arrays
+		rtype = reflect.SliceOf(rtype.Elem())
+		fallthrough
+	case reflect.Slice:
+		matched, err := f.evaluator.EvaluateParallel(ctx, data, workers)
+		if err != nil {
+			return nil, err
+		}
+		newSlice := reflect.MakeSlice(rtype, 0, matched.Count())
+		for _, i := range matched.Rows() {
+			newSlice = reflect.Append(newSlice, rvalue.Index(i))
+		}
+		return newSlice.Interface(), nil
+	case reflect.Map:
+		keys := rvalue.MapKeys()
+		matched, err := evaluateParallel(ctx, len(keys), workers, func(ctx context.Context, i int) (bool, error) {
+			return f.evaluator.evaluateElement(ctx, rvalue.MapIndex(keys[i]))
+		})
+		if err != nil {
+			return nil, err
+		}
+		newMap := reflect.MakeMap(rtype)
+		for _, i := range matched.Rows() {
+			newMap.SetMapIndex(keys[i], rvalue.MapIndex(keys[i]))
+		}
+		return newMap.Interface(), nil
+	default:
+		return nil, fmt.Errorf("Only slices, arrays and maps are filterable")
+	}
+}
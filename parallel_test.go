@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// parallelRecords returns n records, a tenth of them in the prod environment
+func parallelRecords(n int) []map[string]interface{} {
+	records := make([]map[string]interface{}, n)
+	for i := range records {
+		env := "dev"
+		if i%10 == 0 {
+			env = "prod"
+		}
+		records[i] = map[string]interface{}{"Port": i, "Meta": map[string]string{"env": env}}
+	}
+	return records
+}
+
+func TestEvaluateParallel(t *testing.T) {
+	t.Parallel()
+
+	records := parallelRecords(5000)
+	eval, err := CreateEvaluator(`Meta.env == "prod" and Port > 100`)
+	require.NoError(t, err)
+
+	expected := newBitmask(len(records))
+	for i, record := range records {
+		result, err := eval.Evaluate(record)
+		require.NoError(t, err)
+		if result == true {
+			expected.set(i)
+		}
+	}
+	require.Equal(t, 489, expected.Count())
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		matched, err := eval.EvaluateParallel(context.Background(), records, workers)
+		require.NoError(t, err)
+		require.Equal(t, expected, matched, "%d workers", workers)
+	}
+
+	matched, err := eval.EvaluateParallel(context.Background(), [0]int{}, 4)
+	require.NoError(t, err)
+	require.Zero(t, matched.Count())
+
+	_, err = eval.EvaluateParallel(context.Background(), records[0], 4)
+	require.EqualError(t, err, "only slices and arrays can be evaluated in parallel, not map")
+}
+
+func TestEvaluateParallel_Errors(t *testing.T) {
+	t.Parallel()
+
+	records := parallelRecords(5000)
+	records[3000]["Port"] = "80"
+	eval, err := CreateEvaluator(`Port > 100`)
+	require.NoError(t, err)
+
+	_, err = eval.EvaluateParallel(context.Background(), records, 4)
+	require.ErrorIs(t, err, ErrUnsupportedOperator)
+	require.True(t, strings.HasPrefix(err.Error(), "error evaluating element 3000: "), err.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = eval.EvaluateParallel(ctx, records, 4)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestFilter_ExecuteParallel(t *testing.T) {
+	t.Parallel()
+
+	flt, err := CreateFilter(`X == 1`)
+	require.NoError(t, err)
+	for name, input := range map[string]interface{}{"slice": testSlice, "array": testArray, "map": testMap} {
+		expected, err := flt.Execute(input)
+		require.NoError(t, err)
+		result, err := flt.ExecuteParallel(context.Background(), input, 2)
+		require.NoError(t, err)
+		require.Equal(t, expected, result, name)
+	}
+
+	_, err = flt.ExecuteParallel(context.Background(), 1, 2)
+	require.EqualError(t, err, "Only slices, arrays and maps are filterable")
+
+	var nilFilter *Filter
+	result, err := nilFilter.ExecuteParallel(context.Background(), testSlice, 2)
+	require.NoError(t, err)
+	require.Equal(t, testSlice, result)
+}
+
+// TestEvaluator_Concurrent evaluates an evaluator with options keeping state
+// from many goroutines at once, the race detector checking its safety for
+// concurrent use
+func TestEvaluator_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var divergences int64
+	var mu sync.Mutex
+	stats := NewStats()
+	hook := func(v reflect.Value) reflect.Value {
+		if v.Kind() == reflect.String {
+			return reflect.ValueOf(strings.ToLower(v.String()))
+		}
+		return v
+	}
+	evals := map[string]*Evaluator{}
+	for name, opts := range map[string][]Option{
+		"fast":          nil,
+		"selector hook": {WithSelectorHook("Meta", hook)},
+		"stats":         {WithStats(stats, "rule"), WithTrace(func(*Trace) {})},
+		"shadow": {WithShadow(`Meta.env == "prod"`, func(*Divergence) {
+			mu.Lock()
+			divergences++
+			mu.Unlock()
+		})},
+	} {
+		eval, err := CreateEvaluator(`Meta.env == "prod" and Port > 100`, opts...)
+		require.NoError(t, err)
+		evals[name] = eval
+	}
+
+	records := parallelRecords(2000)
+	var wg sync.WaitGroup
+	for name, eval := range evals {
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(name string, eval *Evaluator) {
+				defer wg.Done()
+				for i, record := range records {
+					result, err := eval.Evaluate(record)
+					require.NoError(t, err)
+					require.Equal(t, i%10 == 0 && i > 100, result, fmt.Sprintf("%s: record %d", name, i))
+				}
+			}(name, eval)
+		}
+	}
+	wg.Wait()
+
+	// the shadow expression diverges on the prod records up to the port 100
+	require.Equal(t, int64(4*11), divergences)
+	require.Equal(t, uint64(4*len(records)), stats.Rules()["rule"].Evaluations)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// PartialResult is the outcome of evaluating an expression against a datum
+// holding only some of the fields.
+type PartialResult struct {
+	// Decided is true when the outcome does not depend on the missing
+	// fields. Result then holds the outcome.
+	Decided bool
+	Result  bool
+	// Residual is the part of the expression which could not be decided,
+	// referencing the missing fields. It is nil when Decided is true.
+	Residual *Evaluator
+}
+
+// PartialEvaluate evaluates the expression against a datum holding only some
+// of the fields. Selectors which cannot be found in the datum are unknown
+// rather than missing: the match expressions referencing them are kept, and
+// everything decidable without them is folded away. Match expressions which
+// fail to evaluate are kept as well, so that evaluating the residual against
+// the complete datum reports the same errors Evaluate would. Let expressions
+// are inlined in the residual.
+func (eval *Evaluator) PartialEvaluate(datum interface{}) *PartialResult {
+	node, decided, result := partialEvaluate(grammar.InlineLets(eval.ast), datum, eval.evaluateOpts()...)
+	if decided {
+		return &PartialResult{Decided: true, Result: result}
+	}
+	residual := *eval
+	residual.ast = node
+	residual.compile()
+	if residual.tracer != nil {
+		residual.spanAttributes = newSpanAttributes(&residual)
+	}
+	return &PartialResult{Residual: &residual}
+}
+
+func partialEvaluate(ast grammar.Expression, datum interface{}, opt ...Option) (grammar.Expression, bool, bool) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, decided, result := partialEvaluate(node.Operand, datum, opt...)
+		if decided {
+			return nil, true, !result
+		}
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: operand}, false, false
+
+	case *grammar.BinaryExpression:
+		left, leftDecided, leftResult := partialEvaluate(node.Left, datum, opt...)
+		right, rightDecided, rightResult := partialEvaluate(node.Right, datum, opt...)
+
+		// the value of the operand deciding the outcome on its own
+		absorbing := node.Operator == grammar.BinaryOpOr
+		switch {
+		case leftDecided && leftResult == absorbing, rightDecided && rightResult == absorbing:
+			return nil, true, absorbing
+		case leftDecided && rightDecided:
+			return nil, true, !absorbing
+		case leftDecided:
+			return right, false, false
+		case rightDecided:
+			return left, false, false
+		}
+		return &grammar.BinaryExpression{Operator: node.Operator, Left: left, Right: right}, false, false
+
+	case *grammar.MatchExpression:
+		for _, value := range []*grammar.ExpressionValue{node.Left, node.Right} {
+			if !partialKnown(value, datum, opt...) {
+				return node, false, false
+			}
+		}
+		result, err := evaluateMatchExpression(node, datum, opt...)
+		if err != nil {
+			return node, false, false
+		}
+		return nil, true, result
+
+	case *grammar.ExpressionValue:
+		// boolean operands such as Enabled
+		if !partialKnown(node, datum, opt...) {
+			return node, false, false
+		}
+		value, err := getExprValue(node, datum, opt...)
+		if err != nil {
+			return node, false, false
+		}
+		if isUndefined(value) || isNull(value) {
+			return nil, true, false
+		}
+		result, err := CoerceBool(indirect(value))
+		if err != nil {
+			return node, false, false
+		}
+		return nil, true, result
+	}
+	return ast, false, false
+}
+
+// partialKnown reports whether every selector of the expression value can be
+// found in the datum.
+func partialKnown(expr *grammar.ExpressionValue, datum interface{}, opt ...Option) bool {
+	if expr == nil {
+		return true
+	}
+	for _, sel := range collectSelectors(expr) {
+		value, err := getValue(&grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: sel}, datum, opt...)
+		if isUndefined(value) && (err == nil || errors.Is(err, pointerstructure.ErrNotFound)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialEvaluate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		partial    interface{}
+		decided    bool
+		result     bool
+		// residual is an equivalent expression for the residual
+		residual string
+	}
+
+	partial := map[string]interface{}{
+		"Name": "web",
+		"Port": 8080,
+		"Meta": map[string]interface{}{"env": "prod"},
+		"On":   true,
+	}
+
+	tests := map[string]testCase{
+		"decided true": {
+			expression: `Name == "web" and Meta.env == "prod"`,
+			partial:    partial,
+			decided:    true,
+			result:     true,
+		},
+		"decided false": {
+			expression: `Name == "db" and Tags is not empty`,
+			partial:    partial,
+			decided:    true,
+			result:     false,
+		},
+		"or decided by unknown-free side": {
+			expression: `Tags is empty or Port > 8000`,
+			partial:    partial,
+			decided:    true,
+			result:     true,
+		},
+		"and folds known side": {
+			expression: `Name == "web" and "prod" in Tags`,
+			partial:    partial,
+			residual:   `"prod" in Tags`,
+		},
+		"or folds known side": {
+			expression: `Port < 8000 or Meta.zone == "a"`,
+			partial:    partial,
+			residual:   `Meta.zone == "a"`,
+		},
+		"not": {
+			expression: `not (Port == 8080 and Tags is empty)`,
+			partial:    partial,
+			residual:   `not Tags is empty`,
+		},
+		"nested": {
+			expression: `(Name == "web" or Owner == "x") and (Port == 1 or Owner != "y") and Meta.env == "prod"`,
+			partial:    partial,
+			residual:   `Owner != "y"`,
+		},
+		"unknown in math": {
+			expression: `Port + Offset > 9000`,
+			partial:    map[string]interface{}{"Port": int64(8080)},
+			residual:   `Port + Offset > 9000`,
+		},
+		"errors are kept": {
+			expression: `Name.first == "x" or Tags is empty`,
+			partial:    partial,
+			residual:   `Name.first == "x" or Tags is empty`,
+		},
+		"boolean operand": {
+			expression: `On`,
+			partial:    partial,
+			decided:    true,
+			result:     true,
+		},
+		"negated boolean operand": {
+			expression: `not On`,
+			partial:    partial,
+			decided:    true,
+			result:     false,
+		},
+		"boolean operand and match": {
+			expression: `On and Port > 1`,
+			partial:    partial,
+			decided:    true,
+			result:     true,
+		},
+		"unknown boolean operand": {
+			expression: `Enabled and Port > 1`,
+			partial:    partial,
+			residual:   `Enabled`,
+		},
+		"empty datum": {
+			expression: `Name == "web" and Port == 1`,
+			partial:    map[string]interface{}{},
+			residual:   `Name == "web" and Port == 1`,
+		},
+	}
+
+	full := map[string]interface{}{
+		"Name":    "web",
+		"Port":    int64(8080),
+		"Meta":    map[string]interface{}{"env": "prod", "zone": "a"},
+		"Tags":    []string{"prod"},
+		"Owner":   "z",
+		"Offset":  int64(1000),
+		"On":      true,
+		"Enabled": true,
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result := eval.PartialEvaluate(tcase.partial)
+			require.Equal(t, tcase.decided, result.Decided)
+			if tcase.decided {
+				require.Equal(t, tcase.result, result.Result)
+				require.Nil(t, result.Residual)
+				return
+			}
+
+			expected, err := CreateEvaluator(tcase.residual)
+			require.NoError(t, err)
+			require.Equal(t, withoutPositions(expected.ast), withoutPositions(result.Residual.ast))
+
+			// the residual decides the outcome over the complete datum
+			fullResult, fullErr := eval.Evaluate(full)
+			residualResult, residualErr := result.Residual.Evaluate(full)
+			require.Equal(t, fullErr, residualErr)
+			require.Equal(t, fullResult, residualResult)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// PolicySet evaluates several expressions against the same datum. The
+// subexpressions shared by the expressions, such as guards on the tenant or
+// the environment repeated across rules, are evaluated once per datum and
+// their outcome is shared. Subexpressions are identified by a fingerprint of
+// their AST once let expressions are inlined and the expression simplified,
+// see grammar.Simplify, so that subexpressions written differently but
+// normalized the same way are shared as well.
+type PolicySet struct {
+	asts []grammar.Expression
+	// fingerprints of the subexpressions occurring more than once
+	shared map[grammar.Expression]string
+	opts   []Option
+}
+
+// policyOutcome is the memoized outcome of a shared subexpression
+type policyOutcome struct {
+	result bool
+	err    error
+}
+
+// NewPolicySet builds a policy set over the given evaluators. The options
+// control how selectors are resolved against the datum and should match the
+// ones the evaluators were created with.
+func NewPolicySet(evaluators []*Evaluator, opts ...Option) *PolicySet {
+	p := &PolicySet{
+		asts:   make([]grammar.Expression, len(evaluators)),
+		shared: make(map[grammar.Expression]string),
+		opts:   opts,
+	}
+
+	nodes := make(map[string][]grammar.Expression)
+	for i, eval := range evaluators {
+		p.asts[i] = grammar.Simplify(grammar.InlineLets(eval.ast))
+		collectFingerprints(p.asts[i], nodes)
+	}
+	for fingerprint, occurrences := range nodes {
+		if len(occurrences) < 2 {
+			continue
+		}
+		for _, node := range occurrences {
+			p.shared[node] = fingerprint
+		}
+	}
+	return p
+}
+
+// Evaluate evaluates every expression of the set against the datum and
+// returns their outcomes, in the order the evaluators were given. The first
+// error encountered is returned, prefixed with the index of the evaluator.
+func (p *PolicySet) Evaluate(datum interface{}) ([]bool, error) {
+	ctx := context.Background()
+	memo := make(map[string]policyOutcome)
+	results := make([]bool, len(p.asts))
+	for i, ast := range p.asts {
+		result, err := p.evaluate(ctx, ast, datum, memo)
+		if err != nil {
+			return nil, fmt.Errorf("expression %d: %w", i, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (p *PolicySet) evaluate(ctx context.Context, ast grammar.Expression, datum interface{}, memo map[string]policyOutcome) (bool, error) {
+	fingerprint, shared := p.shared[ast]
+	if shared {
+		if outcome, ok := memo[fingerprint]; ok {
+			return outcome.result, outcome.err
+		}
+	}
+
+	var result bool
+	var err error
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		result, err = p.evaluate(ctx, node.Operand, datum, memo)
+		result = err == nil && !result
+	case *grammar.BinaryExpression:
+		result, err = p.evaluate(ctx, node.Left, datum, memo)
+		shortCircuit := err != nil ||
+			(node.Operator == grammar.BinaryOpAnd && !result) ||
+			(node.Operator == grammar.BinaryOpOr && result)
+		if !shortCircuit {
+			result, err = p.evaluate(ctx, node.Right, datum, memo)
+		}
+	default:
+		var value interface{}
+		value, err = evaluateContext(ctx, ast, datum, p.opts...)
+		result, _ = value.(bool)
+	}
+	if err != nil {
+		result = false
+	}
+
+	if shared {
+		memo[fingerprint] = policyOutcome{result: result, err: err}
+	}
+	return result, err
+}
+
+// collectFingerprints records the nodes of the expression by fingerprint
+func collectFingerprints(ast grammar.Expression, nodes map[string][]grammar.Expression) string {
+	var fingerprint string
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		fingerprint = fmt.Sprintf("%s(%s)", node.Operator, collectFingerprints(node.Operand, nodes))
+	case *grammar.BinaryExpression:
+		fingerprint = fmt.Sprintf("%s(%s, %s)", node.Operator, collectFingerprints(node.Left, nodes), collectFingerprints(node.Right, nodes))
+	case *grammar.MatchExpression:
+		fingerprint = fmt.Sprintf("%s(%s, %s)", node.Operator, operandKey(node.Left), operandKey(node.Right))
+	case *grammar.ExpressionValue:
+		fingerprint = operandKey(node)
+	default:
+		// never shared
+		return fmt.Sprintf("%p", ast)
+	}
+	nodes[fingerprint] = append(nodes[fingerprint], ast)
+	return fingerprint
+}
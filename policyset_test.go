@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicySet(t *testing.T) {
+	t.Parallel()
+
+	expressions := []string{
+		`Tenant == "acme" and Env == "prod" and Role == "admin"`,
+		`Tenant == "acme" and Env == "prod" and Role == "viewer"`,
+		`not (Tenant != "acme" or Env != "prod") and Port > 8000`,
+		`let t = Tenant in t == "acme" and Region == "eu"`,
+		`Role == "admin" or Port < 100`,
+	}
+	var evaluators []*Evaluator
+	for _, expression := range expressions {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		evaluators = append(evaluators, eval)
+	}
+	set := NewPolicySet(evaluators)
+
+	for name, datum := range map[string]map[string]interface{}{
+		"all guards match": {"Tenant": "acme", "Env": "prod", "Role": "admin", "Port": 8080, "Region": "eu"},
+		"guard fails":      {"Tenant": "other", "Env": "prod", "Role": "viewer", "Port": 80, "Region": "eu"},
+		"second guard":     {"Tenant": "acme", "Env": "dev", "Role": "viewer", "Port": 8080, "Region": "us"},
+	} {
+		datum := datum
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src := &countingSource{datum: datum, lookups: make(map[string]int)}
+			results, err := set.Evaluate(src)
+			require.NoError(t, err)
+
+			// the outcomes are the ones of the evaluators
+			for i, eval := range evaluators {
+				expected, err := eval.Evaluate(datum)
+				require.NoError(t, err)
+				require.Equal(t, expected, results[i], expressions[i])
+			}
+
+			// the shared subexpressions are evaluated once
+			require.Equal(t, 1, src.lookups["Tenant"])
+			require.LessOrEqual(t, src.lookups["Env"], 1)
+			require.LessOrEqual(t, src.lookups["Role"], 2)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		eval, err := CreateEvaluator(`Tenant == "acme" and Port.number > 1`)
+		require.NoError(t, err)
+		set := NewPolicySet(append([]*Evaluator{evaluators[0]}, eval))
+
+		_, err = set.Evaluate(map[string]interface{}{"Tenant": "acme", "Env": "prod", "Role": "admin", "Port": 8080})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expression 1: 1:22 (21): Port.number > 1: error finding value in datum")
+	})
+}
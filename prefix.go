@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import "github.com/gterranova/go-bexpr/grammar"
+
+// WithSelectorPrefix prefixes the selectors of the expression with the given
+// selector, such as "Spec" or "/Spec", so that expressions written against a
+// part of the datum can be evaluated against the object wrapping it without
+// being rewritten: `Meta.env == "prod"` selects Spec.Meta.env. The selectors
+// anchored at the root of the datum with "$", such as $.Kind, are not
+// prefixed, nor are the references to the names bound by let expressions.
+// Macros are expanded before the prefix is applied. The selectors of the
+// evaluator, as formatted or validated against a schema, are the prefixed
+// ones, anchored at the root.
+func WithSelectorPrefix(prefix string) Option {
+	return func(o *options) {
+		o.withSelectorPrefix = nil
+		if prefix != "" {
+			o.withSelectorPrefix = newSelectorPattern(prefix).parts
+		}
+	}
+}
+
+// prefixSelectors prefixes the selectors of the expression which are neither
+// anchored nor bound by the let expressions of the scope.
+func prefixSelectors(ast grammar.Expression, prefix []string) grammar.Expression {
+	return rewriteSelectors(ast, func(sel grammar.Selector) grammar.Selector {
+		path := append(append(make([]string, 0, len(prefix)+len(sel.Path)), prefix...), sel.Path...)
+		return grammar.Selector{Type: sel.Type, Path: path, Anchored: true, Ranges: shiftRanges(sel.Ranges, len(prefix))}
+	}, nil)
+}
+
+// rewriteSelectors rewrites the selectors of the expression which are neither
+// anchored nor bound by the let expressions of the scope with the function.
+func rewriteSelectors(ast grammar.Expression, fn func(grammar.Selector) grammar.Selector, bound map[string]bool) grammar.Expression {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: rewriteSelectors(node.Operand, fn, bound)}
+	case *grammar.BinaryExpression:
+		return &grammar.BinaryExpression{
+			Operator: node.Operator,
+			Left:     rewriteSelectors(node.Left, fn, bound),
+			Right:    rewriteSelectors(node.Right, fn, bound),
+		}
+	case *grammar.LetExpression:
+		// the value is outside of the scope of the name
+		value := rewriteValue(node.Value, fn, bound)
+		scope := make(map[string]bool, len(bound)+1)
+		for name := range bound {
+			scope[name] = true
+		}
+		scope[node.Name] = true
+		return &grammar.LetExpression{Name: node.Name, Value: value, Body: rewriteSelectors(node.Body, fn, scope), Position: node.Position}
+	case *grammar.MatchExpression:
+		return &grammar.MatchExpression{
+			Operator: node.Operator,
+			Left:     rewriteValue(node.Left, fn, bound),
+			Right:    rewriteValue(node.Right, fn, bound),
+			Position: node.Position,
+		}
+	case *grammar.ExpressionValue:
+		return rewriteValue(node, fn, bound)
+	}
+	return ast
+}
+
+// shiftRanges returns the ranges of a selector whose path is shifted by n
+// parts
+func shiftRanges(ranges []grammar.SelectorRange, n int) []grammar.SelectorRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	shifted := make([]grammar.SelectorRange, len(ranges))
+	for i, r := range ranges {
+		r.At += n
+		shifted[i] = r
+	}
+	return shifted
+}
+
+func rewriteValue(expr *grammar.ExpressionValue, fn func(grammar.Selector) grammar.Selector, bound map[string]bool) *grammar.ExpressionValue {
+	if expr == nil {
+		return nil
+	}
+	return &grammar.ExpressionValue{
+		Left:     rewriteOperand(expr.Left, fn, bound),
+		Operator: expr.Operator,
+		Right:    rewriteOperand(expr.Right, fn, bound),
+	}
+}
+
+func rewriteOperand(operand interface{}, fn func(grammar.Selector) grammar.Selector, bound map[string]bool) interface{} {
+	switch node := operand.(type) {
+	case *grammar.ExpressionValue:
+		return rewriteValue(node, fn, bound)
+	case *grammar.MatchValue:
+		if node.Type == grammar.ValueTypeCall {
+			args := make([]*grammar.ExpressionValue, len(node.Args))
+			for i, arg := range node.Args {
+				args[i] = rewriteValue(arg, fn, bound)
+			}
+			return &grammar.MatchValue{Type: node.Type, Raw: node.Raw, Args: args}
+		}
+		sel := node.Selector
+		if node.Type != grammar.ValueTypeReflect || sel.Anchored || len(sel.Path) == 0 || bound[sel.Path[0]] {
+			return node
+		}
+		return &grammar.MatchValue{Type: node.Type, Selector: fn(sel)}
+	}
+	return operand
+}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSelectorPrefix(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{
+		"Kind": "Deployment",
+		"env":  "root",
+		"Spec": map[string]interface{}{
+			"env":      "prod",
+			"replicas": 3,
+			"Meta":     map[string]interface{}{"tier": "gold"},
+		},
+	}
+
+	type testCase struct {
+		expression string
+		prefix     string
+		opts       []Option
+		result     bool
+		formatted  string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"prefixed": {
+			expression: `env == "prod" and replicas > 2`,
+			prefix:     "Spec",
+			result:     true,
+			formatted:  `$.Spec.env == "prod" and $.Spec.replicas > 2`,
+		},
+		"json pointer prefix": {
+			expression: `"/Meta/tier" == "gold"`,
+			prefix:     "/Spec",
+			result:     true,
+			formatted:  `$.Spec.Meta.tier == "gold"`,
+		},
+		"anchored": {
+			expression: `$.Kind == "Deployment" and $.env == "root" and env == "prod"`,
+			prefix:     "Spec",
+			result:     true,
+			formatted:  `$.Kind == "Deployment" and $.env == "root" and $.Spec.env == "prod"`,
+		},
+		"anchored without prefix": {
+			expression: `$.Spec.env == "prod" and env == "root"`,
+			result:     true,
+			formatted:  `$.Spec.env == "prod" and env == "root"`,
+		},
+		"let": {
+			expression: `let m = Meta in m.tier == "gold" and $.Kind != "m"`,
+			prefix:     "Spec",
+			result:     true,
+			formatted:  `let m = $.Spec.Meta in m.tier == "gold" and $.Kind != "m"`,
+		},
+		"anchored let name": {
+			expression: `let env = "x" in $.env == "root" and env == "x"`,
+			result:     true,
+		},
+		"macros": {
+			expression: `is_prod and $.Kind == "Deployment"`,
+			prefix:     "Spec",
+			opts:       []Option{WithMacros(map[string]string{"is_prod": `env == "prod"`})},
+			result:     true,
+		},
+		"nested prefix": {
+			expression: `tier == "gold"`,
+			prefix:     "Spec.Meta",
+			result:     true,
+		},
+		"not in prefixed object": {
+			expression: `Kind == "Deployment"`,
+			prefix:     "Spec",
+			result:     false,
+		},
+		"missing prefix": {
+			expression: `env == "prod"`,
+			prefix:     "Status",
+			err:        `1:1 (0): $.Status.env == "prod": error finding value in datum: /Status/env at part 0: couldn't find key "Status"`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append(tcase.opts, WithSelectorPrefix(tcase.prefix))...)
+			require.NoError(t, err)
+			if tcase.formatted != "" {
+				require.Equal(t, tcase.formatted, formatExpression(eval.ast))
+			}
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Prescreen quickly rules out evaluators which cannot match a datum. Every
+// evaluator whose expression requires a selector to equal one of a set of
+// literal values is dispatched on that selector, so a datum only needs to be
+// evaluated against the evaluators whose required values it actually holds.
+type Prescreen struct {
+	// indexes of the evaluators which could not be dispatched on a selector
+	always    []int
+	selectors []*prescreenSelector
+	opts      []Option
+}
+
+// prescreenSelector dispatches on the value of one selector. Literal values
+// are keyed the same way the equality operator coerces them, depending on the
+// kind of the value found in the datum.
+type prescreenSelector struct {
+	value    *grammar.MatchValue
+	all      []int
+	byBool   map[bool][]int
+	byInt    map[int64][]int
+	byUint   map[uint64][]int
+	byFloat  map[float64][]int
+	byString map[string][]int
+}
+
+// NewPrescreen builds a prescreen over the given evaluators. The options
+// control how selectors are resolved against the datum and should match the
+// ones the evaluators were created with. The evaluators configured with
+// WithCoercion or WithSelectorCoercion, or all of them when the options
+// configure coercions, are never ruled out, since the values their coercions
+// compare equal cannot be keyed.
+func NewPrescreen(evaluators []*Evaluator, opts ...Option) *Prescreen {
+	p := &Prescreen{opts: opts}
+	byKey := make(map[string]*prescreenSelector)
+	o := getOpts(opts...)
+	coerced := len(o.withCoercions) > 0 || len(o.withSelectorCoercions) > 0
+
+	for idx, eval := range evaluators {
+		key, values := prescreenValues(eval)
+		if key == "" || coerced || len(eval.coercions) > 0 || len(eval.selectorCoercions) > 0 {
+			p.always = append(p.always, idx)
+			continue
+		}
+
+		sel, ok := byKey[key]
+		if !ok {
+			sel = &prescreenSelector{
+				value:    &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: collectSelectors(grammar.InlineLets(eval.ast))[key]},
+				byBool:   make(map[bool][]int),
+				byInt:    make(map[int64][]int),
+				byUint:   make(map[uint64][]int),
+				byFloat:  make(map[float64][]int),
+				byString: make(map[string][]int),
+			}
+			byKey[key] = sel
+			p.selectors = append(p.selectors, sel)
+		}
+		sel.add(idx, values)
+	}
+
+	return p
+}
+
+// prescreenValues picks the selector with the smallest set of required values
+// for the evaluator. An empty key is returned when no selector is restricted
+// to a set of values.
+func prescreenValues(eval *Evaluator) (string, []interface{}) {
+	var key string
+	var values []interface{}
+	for sel, rng := range eval.Ranges() {
+		if rng.Values == nil {
+			continue
+		}
+		if key == "" || len(rng.Values) < len(values) || (len(rng.Values) == len(values) && sel < key) {
+			key, values = sel, rng.Values
+		}
+	}
+	return key, values
+}
+
+func (sel *prescreenSelector) add(idx int, values []interface{}) {
+	sel.all = append(sel.all, idx)
+	for _, value := range values {
+		value = indirect(value)
+		b, _ := CoerceBool(value)
+		sel.byBool[b] = appendIndex(sel.byBool[b], idx)
+		if i, u, unsigned, _ := coerceInteger(value); unsigned {
+			sel.byUint[u] = appendIndex(sel.byUint[u], idx)
+		} else {
+			sel.byInt[i] = appendIndex(sel.byInt[i], idx)
+		}
+		f, _ := CoerceFloat64(value)
+		sel.byFloat[f] = appendIndex(sel.byFloat[f], idx)
+		s := fmt.Sprintf("%v", value)
+		sel.byString[s] = appendIndex(sel.byString[s], idx)
+	}
+}
+
+func appendIndex(indexes []int, idx int) []int {
+	if len(indexes) > 0 && indexes[len(indexes)-1] == idx {
+		return indexes
+	}
+	return append(indexes, idx)
+}
+
+// candidates returns the evaluators dispatched on this selector which may
+// match the datum.
+func (sel *prescreenSelector) candidates(datum interface{}, opts ...Option) []int {
+	value, err := getValue(sel.value, datum, opts...)
+	if err != nil {
+		// let the evaluators report the error
+		return sel.all
+	}
+	if isUndefined(value) || isNull(value) {
+		// nothing is equal to a missing key or null value
+		return nil
+	}
+
+	value = indirect(value)
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Bool:
+		b, _ := CoerceBool(value)
+		return sel.byBool[b]
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, u, unsigned, _ := coerceInteger(value)
+		if unsigned {
+			return sel.byUint[u]
+		}
+		return sel.byInt[i]
+	case reflect.Float32, reflect.Float64:
+		f, _ := CoerceFloat64(value)
+		return sel.byFloat[f]
+	case reflect.String:
+		return sel.byString[fmt.Sprintf("%v", value)]
+	default:
+		return sel.all
+	}
+}
+
+// Candidates returns, in ascending order, the indexes of the evaluators which
+// may match the datum. Evaluators left out are guaranteed not to match.
+func (p *Prescreen) Candidates(datum interface{}) []int {
+	seen := make(map[int]struct{})
+	result := append([]int{}, p.always...)
+	for _, idx := range p.always {
+		seen[idx] = struct{}{}
+	}
+	for _, sel := range p.selectors {
+		for _, idx := range sel.candidates(datum, p.opts...) {
+			if _, ok := seen[idx]; !ok {
+				seen[idx] = struct{}{}
+				result = append(result, idx)
+			}
+		}
+	}
+	sort.Ints(result)
+	return result
+}
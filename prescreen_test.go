@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrescreen(t *testing.T) {
+	t.Parallel()
+
+	expressions := []string{
+		`Env == "prod" and Team == "core"`,
+		`Env == "dev" or Env == "test"`,
+		`Port == 80 or Port == 443`,
+		`Port > 1024`,
+		`Enabled == true and Env == "prod"`,
+		`Env == "prod" and Env == "dev"`,
+		`Meta.tier == "gold"`,
+	}
+
+	var evaluators []*Evaluator
+	for _, expression := range expressions {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		evaluators = append(evaluators, eval)
+	}
+	p := NewPrescreen(evaluators)
+
+	type datum struct {
+		Env     string
+		Team    string
+		Port    uint16
+		Enabled bool
+		Meta    map[string]string
+	}
+
+	type testCase struct {
+		datum    interface{}
+		expected []int
+	}
+
+	tests := map[string]testCase{
+		"prod": {
+			datum:    datum{Env: "prod", Team: "core", Port: 8080, Enabled: true, Meta: map[string]string{}},
+			expected: []int{0, 3, 4},
+		},
+		"dev on 443": {
+			datum:    datum{Env: "dev", Port: 443, Meta: map[string]string{"tier": "gold"}},
+			expected: []int{1, 2, 3, 6},
+		},
+		"map datum": {
+			datum:    map[string]interface{}{"Env": "test", "Enabled": false, "Port": 80.0, "Meta": map[string]interface{}{"tier": "silver"}},
+			expected: []int{1, 2, 3},
+		},
+		"missing nested selector": {
+			datum:    map[string]interface{}{"Env": "prod", "Team": "web", "Enabled": true, "Port": 22, "Meta": map[string]interface{}{}},
+			expected: []int{0, 3, 4},
+		},
+		// evaluators error on unknown top level keys, so they are all kept
+		"missing selectors": {
+			datum:    map[string]interface{}{},
+			expected: []int{0, 1, 2, 3, 4, 5, 6},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			candidates := p.Candidates(tcase.datum)
+			require.Equal(t, tcase.expected, candidates)
+
+			// every evaluator which was left out must not match
+			for idx, eval := range evaluators {
+				if containsInt(candidates, idx) {
+					continue
+				}
+				result, err := eval.Evaluate(tcase.datum)
+				if err == nil {
+					require.Equal(t, false, result, expressions[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestPrescreen_Pointers(t *testing.T) {
+	t.Parallel()
+
+	type datum struct {
+		P *int
+		F *float64
+		S *string
+		B *bool
+	}
+
+	expressions := []string{`P == 5`, `F == 1.5`, `S == "x"`, `B == true`, `P == 6 or P == 7`}
+	var evaluators []*Evaluator
+	for _, expression := range expressions {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		evaluators = append(evaluators, eval)
+	}
+	p := NewPrescreen(evaluators)
+
+	i, f, s, b := 5, 1.5, "x", true
+	require.Equal(t, []int{0, 1, 2, 3}, p.Candidates(datum{P: &i, F: &f, S: &s, B: &b}))
+	require.Empty(t, p.Candidates(datum{}))
+}
+
+func TestPrescreen_Matches(t *testing.T) {
+	t.Parallel()
+
+	type datum struct {
+		Env   string
+		Port  *uint16
+		Ratio float32
+		Name  *string
+		Big   uint64
+		Meta  map[string]interface{}
+	}
+
+	expressions := []string{
+		`Env == "prod"`,
+		`Port == 80 or Port == 443`,
+		`Ratio == 0.5`,
+		`Name == "web" and Env == "dev"`,
+		`Big == 18446744073709551615`,
+		`Big == 9007199254740993 or Big == 9007199254740992`,
+		`Meta.tier == "gold" or Meta.tier == 1`,
+		`Port == 8080.0`,
+	}
+	var evaluators []*Evaluator
+	for _, expression := range expressions {
+		eval, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		evaluators = append(evaluators, eval)
+	}
+	p := NewPrescreen(evaluators)
+
+	port80, port8080, web := uint16(80), uint16(8080), "web"
+	data := []datum{
+		{Env: "prod", Port: &port80, Ratio: 0.5, Meta: map[string]interface{}{"tier": "gold"}},
+		{Env: "dev", Port: &port8080, Name: &web, Big: 9007199254740992, Meta: map[string]interface{}{"tier": 1}},
+		{Big: 18446744073709551615, Meta: map[string]interface{}{}},
+		{Big: 9007199254740993, Name: &web},
+	}
+
+	for _, d := range data {
+		candidates := p.Candidates(d)
+		for idx, eval := range evaluators {
+			result, err := eval.Evaluate(d)
+			require.NoError(t, err)
+			if result == true {
+				require.Contains(t, candidates, idx, "%s matches %+v", expressions[idx], d)
+			}
+		}
+	}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPrescreen_Coercions(t *testing.T) {
+	t.Parallel()
+
+	type datum struct {
+		Count int
+		Env   string
+	}
+
+	thousands := func(value interface{}) (interface{}, error) {
+		return strconv.Atoi(strings.ReplaceAll(fmt.Sprint(value), ",", ""))
+	}
+	prefixed := func(value interface{}) (interface{}, error) {
+		return fmt.Sprintf("n%v", value), nil
+	}
+
+	coerced, err := CreateEvaluator(`Count == "1,000"`, WithCoercion(grammar.ValueTypeInt, thousands))
+	require.NoError(t, err)
+	selected, err := CreateEvaluator(`Count == "n1000"`, WithSelectorCoercion("Count", grammar.ValueTypeString, prefixed))
+	require.NoError(t, err)
+	plain, err := CreateEvaluator(`Env == "prod"`)
+	require.NoError(t, err)
+	evaluators := []*Evaluator{coerced, selected, plain}
+
+	d := datum{Count: 1000, Env: "dev"}
+	for _, eval := range evaluators[:2] {
+		result, err := eval.Evaluate(d)
+		require.NoError(t, err)
+		require.Equal(t, true, result)
+	}
+	require.Equal(t, []int{0, 1}, NewPrescreen(evaluators).Candidates(d))
+
+	// the coercions of the options apply to every evaluator
+	require.Equal(t, []int{0, 1, 2}, NewPrescreen(evaluators, WithCoercion(grammar.ValueTypeInt, thousands)).Candidates(d))
+}
@@ -0,0 +1,356 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Protocol buffer messages, the structs generated by protoc-gen-go, are
+// recognized by their shape rather than their type, so that they can be
+// evaluated without depending on the protobuf module. They evaluate close to
+// the way protojson renders them:
+//
+//   - selectors look up the fields of messages by their name in the .proto
+//     file or by their JSON name, such as user_id or userId. Unset message
+//     fields and optional fields are null.
+//   - the set field of a oneof is looked up like any other field, and the
+//     name of the oneof resolves to the name of the field set, such as
+//     kind == "email". Unset fields are missing.
+//   - enum values resolve to their name.
+//   - google.protobuf.Timestamp values are compared with RFC 3339 strings,
+//     such as created > "2024-05-01T12:00:00Z", and google.protobuf.Duration
+//     values with the strings parsed by time.ParseDuration, such as
+//     timeout <= "1.5s".
+//   - wrappers, such as google.protobuf.StringValue, resolve to the value
+//     they hold.
+//   - google.protobuf.Struct values, such as *structpb.Struct, resolve to
+//     maps of the values of their fields, and google.protobuf.Value values to
+//     the value they hold: nil, a float64, a string, a bool, a struct or a
+//     slice of values.
+
+// isProtoMessage reports whether the type is a message generated by
+// protoc-gen-go
+func isProtoMessage(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := reflect.PtrTo(typ).MethodByName("ProtoReflect"); !ok {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := typ.Field(i).Tag.Lookup("protobuf"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isProtoEnum reports whether the type is an enum generated by protoc-gen-go
+func isProtoEnum(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Int32 {
+		return false
+	}
+	for _, name := range []string{"Enum", "Number", "String"} {
+		if _, ok := typ.MethodByName(name); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// protoWrappers are the names of the wrapper messages of
+// google/protobuf/wrappers.proto
+var protoWrappers = map[string]bool{
+	"DoubleValue": true, "FloatValue": true, "Int64Value": true, "UInt64Value": true,
+	"Int32Value": true, "UInt32Value": true, "BoolValue": true, "StringValue": true,
+	"BytesValue": true,
+}
+
+// protoField is a field of a message as declared in the .proto file
+type protoField struct {
+	name     string
+	jsonName string
+}
+
+// parseProtoTag parses the protobuf tag of a field, such as
+// `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3"`
+func parseProtoTag(tag string) protoField {
+	var field protoField
+	for _, part := range strings.Split(tag, ",") {
+		switch {
+		case strings.HasPrefix(part, "name="):
+			field.name = strings.TrimPrefix(part, "name=")
+		case strings.HasPrefix(part, "json="):
+			field.jsonName = strings.TrimPrefix(part, "json=")
+		}
+	}
+	return field
+}
+
+// protoHookFn converts messages to maps of their fields, enums to their
+// names and well-known types to the values they are compared as.
+func protoHookFn(v reflect.Value) reflect.Value {
+	e := hookElem(v)
+	if !e.IsValid() || !e.CanInterface() {
+		return v
+	}
+	if e.Kind() == reflect.Ptr {
+		if e.IsNil() || !isProtoMessage(e.Type().Elem()) {
+			return v
+		}
+		e = e.Elem()
+	}
+	if val, ok := protoValue(e); ok {
+		return reflect.ValueOf(val)
+	}
+	return v
+}
+
+// protoValue converts a message, well-known type or enum, or a slice of
+// them, reporting whether the value is one of them
+func protoValue(e reflect.Value) (interface{}, bool) {
+	typ := e.Type()
+	switch {
+	case isProtoEnum(typ):
+		return e.Interface().(fmt.Stringer).String(), true
+	case typ.Kind() == reflect.Slice && isProtoEnum(typ.Elem()):
+		names := make([]string, e.Len())
+		for i := range names {
+			names[i] = e.Index(i).Interface().(fmt.Stringer).String()
+		}
+		return names, true
+	case !isProtoMessage(typ):
+		return nil, false
+	}
+
+	fields := protoFields(e)
+	switch {
+	case typ.Name() == "Struct" && len(fields) == 1 && fields["fields"].Kind() == reflect.Map:
+		return structpbStruct(fields["fields"]), true
+	case typ.Name() == "ListValue" && len(fields) == 1 && fields["values"].Kind() == reflect.Slice:
+		return structpbList(fields["values"]), true
+	case isStructpbValue(typ):
+		return structpbValue(e), true
+	case typ.Name() == "Timestamp" && isProtoTime(fields):
+		return protoTimestamp(time.Unix(fields["seconds"].Int(), fields["nanos"].Int()).UTC()), true
+	case typ.Name() == "Duration" && isProtoTime(fields):
+		return protoDuration(time.Duration(fields["seconds"].Int())*time.Second + time.Duration(fields["nanos"].Int())), true
+	case protoWrappers[typ.Name()] && len(fields) == 1 && fields["value"].IsValid():
+		return fields["value"].Interface(), true
+	}
+
+	msg := make(map[string]interface{}, len(fields))
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		f := e.Field(i)
+		if oneof, ok := sf.Tag.Lookup("protobuf_oneof"); ok {
+			// the field holds a pointer to a struct with the field set
+			if f.IsNil() || f.Elem().IsNil() {
+				continue
+			}
+			member := f.Elem().Elem()
+			tag, ok := member.Type().Field(0).Tag.Lookup("protobuf")
+			if !ok {
+				continue
+			}
+			field := parseProtoTag(tag)
+			setProtoField(msg, field, member.Field(0))
+			msg[oneof] = field.name
+			continue
+		}
+		tag, ok := sf.Tag.Lookup("protobuf")
+		if !ok {
+			continue
+		}
+		if f.Kind() == reflect.Ptr && f.IsNil() {
+			// unset messages and optional fields are null
+			setProtoField(msg, parseProtoTag(tag), reflect.Zero(interfaceTyp))
+			continue
+		}
+		setProtoField(msg, parseProtoTag(tag), f)
+	}
+	return msg, true
+}
+
+// isStructpbValue reports whether the type is google.protobuf.Value, a
+// message made of the oneof holding its kind
+func isStructpbValue(typ reflect.Type) bool {
+	if typ.Name() != "Value" {
+		return false
+	}
+	kind, ok := typ.FieldByName("Kind")
+	return ok && kind.Tag.Get("protobuf_oneof") == "kind" && kind.Type.Kind() == reflect.Interface
+}
+
+// structpbStruct converts the fields of a google.protobuf.Struct, a map of
+// pointers to google.protobuf.Value
+func structpbStruct(fields reflect.Value) map[string]interface{} {
+	m := make(map[string]interface{}, fields.Len())
+	iter := fields.MapRange()
+	for iter.Next() {
+		var val interface{}
+		if v := iter.Value(); !v.IsNil() {
+			val = structpbValue(v.Elem())
+		}
+		m[iter.Key().String()] = val
+	}
+	return m
+}
+
+// structpbList converts the values of a google.protobuf.ListValue, a slice of
+// pointers to google.protobuf.Value
+func structpbList(values reflect.Value) []interface{} {
+	list := make([]interface{}, values.Len())
+	for i := range list {
+		if v := values.Index(i); !v.IsNil() {
+			list[i] = structpbValue(v.Elem())
+		}
+	}
+	return list
+}
+
+// structpbValue converts a google.protobuf.Value to the value it holds.
+// Structs are converted by protoHookFn when selectors descend into them.
+func structpbValue(e reflect.Value) interface{} {
+	kind := e.FieldByName("Kind")
+	if kind.IsNil() || kind.Elem().IsNil() {
+		return nil
+	}
+	f := kind.Elem().Elem().Field(0)
+	switch {
+	case isProtoEnum(f.Type()):
+		// google.protobuf.NullValue
+		return nil
+	case f.Kind() == reflect.Ptr:
+		if f.IsNil() {
+			return nil
+		}
+		if f.Elem().Type().Name() == "ListValue" {
+			if values := f.Elem().FieldByName("Values"); values.Kind() == reflect.Slice {
+				return structpbList(values)
+			}
+		}
+	}
+	return f.Interface()
+}
+
+// protoFields returns the fields of the message by name
+func protoFields(e reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < e.NumField(); i++ {
+		if tag, ok := e.Type().Field(i).Tag.Lookup("protobuf"); ok {
+			fields[parseProtoTag(tag).name] = e.Field(i)
+		}
+	}
+	return fields
+}
+
+// isProtoTime reports whether the fields are the ones of timestamps and
+// durations
+func isProtoTime(fields map[string]reflect.Value) bool {
+	seconds, nanos := fields["seconds"], fields["nanos"]
+	return len(fields) == 2 && seconds.IsValid() && seconds.Kind() == reflect.Int64 &&
+		nanos.IsValid() && nanos.Kind() == reflect.Int32
+}
+
+func setProtoField(msg map[string]interface{}, field protoField, f reflect.Value) {
+	var val interface{}
+	if !f.IsValid() || (f.Kind() == reflect.Interface && f.IsNil()) {
+		val = nil
+	} else if converted, ok := protoValue(f); ok {
+		val = converted
+	} else {
+		val = f.Interface()
+	}
+	msg[field.name] = val
+	if field.jsonName != "" {
+		msg[field.jsonName] = val
+	}
+}
+
+// protoTimestamp is a google.protobuf.Timestamp, compared with other
+// timestamps and with RFC 3339 strings
+type protoTimestamp time.Time
+
+func (t protoTimestamp) Compare(other interface{}) (int, error) {
+	var o time.Time
+	switch v := other.(type) {
+	case protoTimestamp:
+		o = time.Time(v)
+	case string:
+		var err error
+		if o, err = time.Parse(time.RFC3339Nano, v); err != nil {
+			return 0, fmt.Errorf("%q is not an RFC 3339 timestamp", v)
+		}
+	default:
+		return 0, fmt.Errorf("%v is not a timestamp", other)
+	}
+	switch {
+	case time.Time(t).Before(o):
+		return -1, nil
+	case time.Time(t).After(o):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats the timestamp the way protojson does
+func (t protoTimestamp) String() string {
+	return time.Time(t).Format("2006-01-02T15:04:05") + formatNanos(int32(time.Time(t).Nanosecond())) + "Z"
+}
+
+// protoDuration is a google.protobuf.Duration, compared with other durations
+// and with the strings parsed by time.ParseDuration, such as "1.5s" or "2m"
+type protoDuration time.Duration
+
+func (d protoDuration) Compare(other interface{}) (int, error) {
+	var o time.Duration
+	switch v := other.(type) {
+	case protoDuration:
+		o = time.Duration(v)
+	case string:
+		var err error
+		if o, err = time.ParseDuration(v); err != nil {
+			return 0, fmt.Errorf("%q is not a duration", v)
+		}
+	default:
+		return 0, fmt.Errorf("%v is not a duration", other)
+	}
+	switch {
+	case time.Duration(d) < o:
+		return -1, nil
+	case time.Duration(d) > o:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String formats the duration the way protojson does
+func (d protoDuration) String() string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	seconds, nanos := int64(d)/int64(time.Second), int32(int64(d)%int64(time.Second))
+	return sign + strconv.FormatInt(seconds, 10) + formatNanos(nanos) + "s"
+}
+
+// formatNanos formats the fractional seconds with 0, 3, 6 or 9 digits, the
+// way protojson does
+func formatNanos(nanos int32) string {
+	switch {
+	case nanos == 0:
+		return ""
+	case nanos%1000000 == 0:
+		return fmt.Sprintf(".%03d", nanos/1000000)
+	case nanos%1000 == 0:
+		return fmt.Sprintf(".%06d", nanos/1000)
+	}
+	return fmt.Sprintf(".%09d", nanos)
+}
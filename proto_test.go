@@ -0,0 +1,319 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The types below have the shape of the code generated by protoc-gen-go for:
+//
+//	message User {
+//	  string name = 1;
+//	  string user_id = 2;
+//	  Status status = 3;
+//	  repeated Status history = 4;
+//	  oneof contact {
+//	    string email = 5;
+//	    Address address = 6;
+//	  }
+//	  google.protobuf.Timestamp created = 7;
+//	  google.protobuf.Duration session = 8;
+//	  google.protobuf.StringValue nickname = 9;
+//	  optional int32 age = 10;
+//	  Address home = 11;
+//	  map<string, string> labels = 12;
+//	}
+
+type testProtoState struct{}
+
+type testStatus int32
+
+const (
+	testStatusUnknown testStatus = 0
+	testStatusActive  testStatus = 1
+)
+
+func (x testStatus) Enum() *testStatus { return &x }
+func (x testStatus) Number() int32     { return int32(x) }
+func (x testStatus) String() string {
+	switch x {
+	case testStatusUnknown:
+		return "STATUS_UNKNOWN"
+	case testStatusActive:
+		return "STATUS_ACTIVE"
+	}
+	return strconv.Itoa(int(x))
+}
+
+type testUser struct {
+	state testProtoState
+
+	Name     string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	UserId   string            `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status   testStatus        `protobuf:"varint,3,opt,name=status,proto3,enum=test.Status" json:"status,omitempty"`
+	History  []testStatus      `protobuf:"varint,4,rep,packed,name=history,proto3,enum=test.Status" json:"history,omitempty"`
+	Contact  isUser_Contact    `protobuf_oneof:"contact"`
+	Created  *Timestamp        `protobuf:"bytes,7,opt,name=created,proto3" json:"created,omitempty"`
+	Session  *Duration         `protobuf:"bytes,8,opt,name=session,proto3" json:"session,omitempty"`
+	Nickname *StringValue      `protobuf:"bytes,9,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Age      *int32            `protobuf:"varint,10,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	Home     *testAddress      `protobuf:"bytes,11,opt,name=home,proto3" json:"home,omitempty"`
+	Labels   map[string]string `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (*testUser) ProtoReflect() {}
+
+type isUser_Contact interface {
+	isUser_Contact()
+}
+
+type testUser_Email struct {
+	Email string `protobuf:"bytes,5,opt,name=email,proto3,oneof"`
+}
+
+type testUser_Address struct {
+	Address *testAddress `protobuf:"bytes,6,opt,name=address,proto3,oneof"`
+}
+
+func (*testUser_Email) isUser_Contact()   {}
+func (*testUser_Address) isUser_Contact() {}
+
+type testAddress struct {
+	state testProtoState
+
+	City       string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	PostalCode string `protobuf:"bytes,2,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+}
+
+func (*testAddress) ProtoReflect() {}
+
+// Timestamp, Duration and StringValue have the shape of the well-known types
+type Timestamp struct {
+	state testProtoState
+
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos   int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func (*Timestamp) ProtoReflect() {}
+
+type Duration struct {
+	state testProtoState
+
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	Nanos   int32 `protobuf:"varint,2,opt,name=nanos,proto3" json:"nanos,omitempty"`
+}
+
+func (*Duration) ProtoReflect() {}
+
+type StringValue struct {
+	state testProtoState
+
+	Value string `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (*StringValue) ProtoReflect() {}
+
+func TestProto(t *testing.T) {
+	t.Parallel()
+
+	user := &testUser{
+		Name:     "alice",
+		UserId:   "u-1",
+		Status:   testStatusActive,
+		History:  []testStatus{testStatusUnknown, testStatusActive},
+		Contact:  &testUser_Email{Email: "alice@example.com"},
+		Created:  &Timestamp{Seconds: 1714564800, Nanos: 500000000},
+		Session:  &Duration{Seconds: -1, Nanos: -500},
+		Nickname: &StringValue{Value: "al"},
+		Home:     &testAddress{City: "Rome", PostalCode: "00100"},
+		Labels:   map[string]string{"team": "core"},
+	}
+	moved := &testUser{
+		Name:    "bob",
+		Contact: &testUser_Address{Address: &testAddress{City: "Milan"}},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"field":                {expression: `name == "alice"`, result: true},
+		"proto name":           {expression: `user_id == "u-1"`, result: true},
+		"json name":            {expression: `userId == "u-1"`, result: true},
+		"go name":              {expression: `UserId == "u-1"`, err: `1:1 (0): UserId == "u-1": error finding value in datum: /UserId at part 0: couldn't find key "UserId"`},
+		"enum":                 {expression: `status == "STATUS_ACTIVE"`, result: true},
+		"repeated enum":        {expression: `"STATUS_UNKNOWN" in history`, result: true},
+		"oneof":                {expression: `email endswith "@example.com" and contact == "email"`, result: true},
+		"timestamp string":     {expression: `created contains "2024"`, err: `1:1 (0): created contains "2024": operator "In" cannot be used with values of type bexpr.protoTimestamp`},
+		"oneof unset member":   {expression: `contact != "address"`, result: true},
+		"oneof message":        {expression: `address.city == "Milan" and contact == "address"`, datum: moved, result: true},
+		"timestamp":            {expression: `created == "2024-05-01T12:00:00.500Z"`, result: true},
+		"timestamp ordering":   {expression: `created > "2024-01-01T00:00:00Z"`, result: true},
+		"duration":             {expression: `session == "-1.0000005s" and session < "-1s"`, result: true},
+		"invalid timestamp":    {expression: `created > "yesterday"`, err: `1:1 (0): created > "yesterday": error comparing 2024-05-01T12:00:00.500Z and yesterday: "yesterday" is not an RFC 3339 timestamp`},
+		"wrapper":              {expression: `nickname == "al"`, result: true},
+		"unset optional":       {expression: `age is null`, result: true},
+		"unset message":        {expression: `created is empty`, datum: moved, result: true},
+		"nested message":       {expression: `home.postalCode == "00100" and home.postal_code == "00100"`, result: true},
+		"map":                  {expression: `labels.team == "core"`, result: true},
+		"message value":        {expression: `name == "alice"`, datum: *user, result: true},
+		"message in interface": {expression: `user.name == "alice"`, datum: map[string]interface{}{"user": user}, result: true},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			d := tcase.datum
+			if d == nil {
+				d = user
+			}
+			result, err := eval.Evaluate(d)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+// Struct, Value and ListValue have the shape of the types of structpb
+type Struct struct {
+	state testProtoState
+
+	Fields map[string]*Value `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (*Struct) ProtoReflect() {}
+
+type Value struct {
+	state testProtoState
+
+	Kind isValue_Kind `protobuf_oneof:"kind"`
+}
+
+func (*Value) ProtoReflect() {}
+
+type isValue_Kind interface {
+	isValue_Kind()
+}
+
+type NullValue int32
+
+func (x NullValue) Enum() *NullValue { return &x }
+func (x NullValue) Number() int32    { return int32(x) }
+func (x NullValue) String() string   { return "NULL_VALUE" }
+
+type Value_NullValue struct {
+	NullValue NullValue `protobuf:"varint,1,opt,name=null_value,json=nullValue,proto3,enum=google.protobuf.NullValue,oneof"`
+}
+
+type Value_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,2,opt,name=number_value,json=numberValue,proto3,oneof"`
+}
+
+type Value_StringValue struct {
+	StringValue string `protobuf:"bytes,3,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
+type Value_BoolValue struct {
+	BoolValue bool `protobuf:"varint,4,opt,name=bool_value,json=boolValue,proto3,oneof"`
+}
+
+type Value_StructValue struct {
+	StructValue *Struct `protobuf:"bytes,5,opt,name=struct_value,json=structValue,proto3,oneof"`
+}
+
+type Value_ListValue struct {
+	ListValue *ListValue `protobuf:"bytes,6,opt,name=list_value,json=listValue,proto3,oneof"`
+}
+
+func (*Value_NullValue) isValue_Kind()   {}
+func (*Value_NumberValue) isValue_Kind() {}
+func (*Value_StringValue) isValue_Kind() {}
+func (*Value_BoolValue) isValue_Kind()   {}
+func (*Value_StructValue) isValue_Kind() {}
+func (*Value_ListValue) isValue_Kind()   {}
+
+type ListValue struct {
+	state testProtoState
+
+	Values []*Value `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (*ListValue) ProtoReflect() {}
+
+func TestStructpb(t *testing.T) {
+	t.Parallel()
+
+	str := func(s string) *Value { return &Value{Kind: &Value_StringValue{StringValue: s}} }
+	num := func(n float64) *Value { return &Value{Kind: &Value_NumberValue{NumberValue: n}} }
+	datum := &Struct{Fields: map[string]*Value{
+		"name":     str("web"),
+		"replicas": num(3),
+		"enabled":  {Kind: &Value_BoolValue{BoolValue: true}},
+		"owner":    {Kind: &Value_NullValue{}},
+		"tags": {Kind: &Value_ListValue{ListValue: &ListValue{Values: []*Value{
+			str("prod"),
+			str("eu"),
+			{Kind: &Value_StructValue{StructValue: &Struct{Fields: map[string]*Value{"zone": str("a")}}}},
+		}}}},
+		"labels": {Kind: &Value_StructValue{StructValue: &Struct{Fields: map[string]*Value{
+			"team": str("core"),
+			"cost": num(1.5),
+		}}}},
+	}}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"string":         {expression: `name == "web"`, result: true},
+		"number":         {expression: `replicas > 2 and replicas == 3`, result: true},
+		"bool":           {expression: `enabled`, result: true},
+		"null":           {expression: `owner is null`, result: true},
+		"list":           {expression: `"eu" in tags and tags is not empty`, result: true},
+		"list index":     {expression: `tags.0 == "prod"`, result: true},
+		"struct in list": {expression: `tags.2.zone == "a"`, result: true},
+		"nested struct":  {expression: `labels.team == "core" and labels.cost < 2`, result: true},
+		"struct keys":    {expression: `"team" in labels`, result: true},
+		"missing":        {expression: `labels.region == "eu"`, result: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Bound is one end of a Range
+type Bound struct {
+	Value     interface{}
+	Inclusive bool
+}
+
+// Range describes the values a selector can hold for an expression to match.
+//
+// When Values is non-nil the selector must equal one of the listed values (an
+// empty, non-nil Values means nothing can match). Otherwise the selector must
+// lie between Lower and Upper, where a nil Bound is unbounded.
+//
+// Ranges are conservative: every datum matched by the expression satisfies
+// the range, but not every value inside the range will match. They are meant
+// to seed index scans which are then filtered by evaluating the expression.
+type Range struct {
+	Lower  *Bound
+	Upper  *Bound
+	Values []interface{}
+}
+
+// Ranges extracts, per selector, the range of values implied by the
+// expression. Selectors which are not constrained are not included.
+func (eval *Evaluator) Ranges() map[string]*Range {
+	return extractRanges(grammar.InlineLets(eval.ast))
+}
+
+func extractRanges(ast interface{}) map[string]*Range {
+	switch node := ast.(type) {
+	case *grammar.BinaryExpression:
+		left := extractRanges(node.Left)
+		right := extractRanges(node.Right)
+		switch node.Operator {
+		case grammar.BinaryOpAnd:
+			for sel, rng := range right {
+				if existing, ok := left[sel]; ok {
+					left[sel] = existing.intersect(rng)
+				} else {
+					left[sel] = rng
+				}
+			}
+			return left
+		case grammar.BinaryOpOr:
+			// only selectors constrained on both sides remain constrained
+			result := make(map[string]*Range)
+			for sel, rng := range left {
+				if other, ok := right[sel]; ok {
+					if union := rng.union(other); union != nil {
+						result[sel] = union
+					}
+				}
+			}
+			return result
+		}
+	case *grammar.MatchExpression:
+		return matchRanges(node)
+	}
+	// Negations and anything else do not constrain selectors
+	return map[string]*Range{}
+}
+
+func matchRanges(expr *grammar.MatchExpression) map[string]*Range {
+	result := make(map[string]*Range)
+	if expr.Right == nil {
+		return result
+	}
+
+	op := expr.Operator
+	sel, ok := rangeSelector(expr.Left)
+	value, isConst := constantValue(expr.Right)
+	if !ok || !isConst {
+		// try with the selector on the right hand side: 5 < X is X > 5
+		sel, ok = rangeSelector(expr.Right)
+		value, isConst = constantValue(expr.Left)
+		if !ok || !isConst {
+			return result
+		}
+		switch op {
+		case grammar.MatchLower:
+			op = grammar.MatchHigher
+		case grammar.MatchLowerOrEqual:
+			op = grammar.MatchHigherOrEqual
+		case grammar.MatchHigher:
+			op = grammar.MatchLower
+		case grammar.MatchHigherOrEqual:
+			op = grammar.MatchLowerOrEqual
+		}
+	}
+
+	key := sel.String()
+	switch op {
+	case grammar.MatchEqual:
+		result[key] = &Range{Values: []interface{}{value}}
+	case grammar.MatchLower:
+		result[key] = &Range{Upper: &Bound{Value: value}}
+	case grammar.MatchLowerOrEqual:
+		result[key] = &Range{Upper: &Bound{Value: value, Inclusive: true}}
+	case grammar.MatchHigher:
+		result[key] = &Range{Lower: &Bound{Value: value}}
+	case grammar.MatchHigherOrEqual:
+		result[key] = &Range{Lower: &Bound{Value: value, Inclusive: true}}
+	}
+	return result
+}
+
+// rangeSelector returns the selector when the expression value is nothing
+// more than a selector.
+func rangeSelector(expr *grammar.ExpressionValue) (grammar.Selector, bool) {
+	if expr == nil || expr.Operator != grammar.MathOpValue {
+		return grammar.Selector{}, false
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect {
+		return grammar.Selector{}, false
+	}
+	return value.Selector, true
+}
+
+// constantValue computes the value of an expression value which does not
+// reference any selector.
+func constantValue(expr *grammar.ExpressionValue) (interface{}, bool) {
+	if expr == nil || !isConstant(expr) {
+		return nil, false
+	}
+	value, err := getExprValue(expr, nil)
+	if err != nil || isNull(value) || isUndefined(value) {
+		return nil, false
+	}
+	return value, true
+}
+
+func isConstant(ast interface{}) bool {
+	switch node := ast.(type) {
+	case nil:
+		return true
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeReflect, grammar.ValueTypeUndefined, grammar.ValueTypeParam:
+			return false
+		}
+		for _, arg := range node.Args {
+			if !isConstant(arg) {
+				return false
+			}
+		}
+		return true
+	case *grammar.ExpressionValue:
+		return isConstant(node.Left) && isConstant(node.Right)
+	default:
+		return false
+	}
+}
+
+// compareValues orders two literal values. The second return value is false
+// when the values cannot be ordered relative to each other. Integers are
+// compared exactly, floating point only when one of the values is a float.
+func compareValues(a, b interface{}) (int, bool) {
+	switch av := a.(type) {
+	case int64:
+		switch bv := b.(type) {
+		case int64:
+			return compareInts(av, bv), true
+		case uint64:
+			if av < 0 {
+				return -1, true
+			}
+			return compareUints(uint64(av), bv), true
+		case float64:
+			return compareFloats(float64(av), bv), true
+		}
+	case uint64:
+		switch bv := b.(type) {
+		case int64:
+			if bv < 0 {
+				return 1, true
+			}
+			return compareUints(av, uint64(bv)), true
+		case uint64:
+			return compareUints(av, bv), true
+		case float64:
+			return compareFloats(float64(av), bv), true
+		}
+	case float64:
+		switch bv := b.(type) {
+		case int64:
+			return compareFloats(av, float64(bv)), true
+		case uint64:
+			return compareFloats(av, float64(bv)), true
+		case float64:
+			return compareFloats(av, bv), true
+		}
+	case string:
+		if bv, ok := b.(string); ok {
+			return strings.Compare(av, bv), true
+		}
+	case bool:
+		if bv, ok := b.(bool); ok && av == bv {
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareUints(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// contains reports whether the value falls inside the range. Values which
+// cannot be compared with the range are assumed to be contained.
+func (r *Range) contains(value interface{}) bool {
+	if r.Values != nil {
+		for _, v := range r.Values {
+			if c, ok := compareValues(v, value); !ok || c == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if r.Lower != nil {
+		if c, ok := compareValues(value, r.Lower.Value); ok && (c < 0 || (c == 0 && !r.Lower.Inclusive)) {
+			return false
+		}
+	}
+	if r.Upper != nil {
+		if c, ok := compareValues(value, r.Upper.Value); ok && (c > 0 || (c == 0 && !r.Upper.Inclusive)) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasValue reports whether the value is one of the values of the set
+func (r *Range) hasValue(value interface{}) bool {
+	for _, v := range r.Values {
+		if c, ok := compareValues(v, value); ok && c == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Range) intersect(other *Range) *Range {
+	switch {
+	case r.Values != nil:
+		return r.filter(other)
+	case other.Values != nil:
+		return other.filter(r)
+	}
+	return &Range{
+		Lower: tighterBound(r.Lower, other.Lower, 1),
+		Upper: tighterBound(r.Upper, other.Upper, -1),
+	}
+}
+
+// filter keeps the values of the set which fall inside the other range.
+func (r *Range) filter(other *Range) *Range {
+	values := []interface{}{}
+	for _, v := range r.Values {
+		if other.contains(v) {
+			values = append(values, v)
+		}
+	}
+	return &Range{Values: values}
+}
+
+func (r *Range) union(other *Range) *Range {
+	// an empty set adds nothing to the union
+	if r.Values != nil && len(r.Values) == 0 {
+		return other
+	}
+	if other.Values != nil && len(other.Values) == 0 {
+		return r
+	}
+	if r.Values != nil && other.Values != nil {
+		values := append([]interface{}{}, r.Values...)
+		for _, v := range other.Values {
+			if !r.hasValue(v) {
+				values = append(values, v)
+			}
+		}
+		return &Range{Values: values}
+	}
+	lower, lok := looserBound(r.lowerBound(), other.lowerBound(), -1)
+	upper, uok := looserBound(r.upperBound(), other.upperBound(), 1)
+	if !lok || !uok || (lower == nil && upper == nil) {
+		// the union is unbounded or cannot be expressed
+		return nil
+	}
+	return &Range{Lower: lower, Upper: upper}
+}
+
+// lowerBound returns the lower bound of the range, computing it from the set
+// of values when necessary.
+func (r *Range) lowerBound() *Bound {
+	if r.Values == nil {
+		return r.Lower
+	}
+	return r.setBound(-1)
+}
+
+func (r *Range) upperBound() *Bound {
+	if r.Values == nil {
+		return r.Upper
+	}
+	return r.setBound(1)
+}
+
+func (r *Range) setBound(direction int) *Bound {
+	var bound *Bound
+	for _, v := range r.Values {
+		if bound == nil {
+			bound = &Bound{Value: v, Inclusive: true}
+			continue
+		}
+		if c, ok := compareValues(v, bound.Value); !ok {
+			return nil
+		} else if c*direction > 0 {
+			bound.Value = v
+		}
+	}
+	return bound
+}
+
+// tighterBound picks the more restrictive of two bounds. The direction is 1
+// for lower bounds and -1 for upper bounds.
+func tighterBound(a, b *Bound, direction int) *Bound {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	c, ok := compareValues(a.Value, b.Value)
+	switch {
+	case !ok:
+		return a
+	case c*direction > 0:
+		return a
+	case c*direction < 0:
+		return b
+	case !a.Inclusive:
+		return a
+	default:
+		return b
+	}
+}
+
+// looserBound picks the less restrictive of two bounds. The direction is -1
+// for lower bounds and 1 for upper bounds. The second return value is false if
+// the bounds cannot be compared.
+func looserBound(a, b *Bound, direction int) (*Bound, bool) {
+	if a == nil || b == nil {
+		return nil, true
+	}
+	c, ok := compareValues(a.Value, b.Value)
+	switch {
+	case !ok:
+		return nil, false
+	case c*direction > 0:
+		return a, true
+	case c*direction < 0:
+		return b, true
+	case a.Inclusive:
+		return a, true
+	default:
+		return b, true
+	}
+}
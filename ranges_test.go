@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluator_Ranges(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		expected   map[string]*Range
+	}
+
+	tests := map[string]testCase{
+		"equality": {
+			expression: `Status == "a"`,
+			expected: map[string]*Range{
+				"Status": {Values: []interface{}{"a"}},
+			},
+		},
+		"half open interval": {
+			expression: "Port > 8000 and Port <= 9000",
+			expected: map[string]*Range{
+				"Port": {Lower: &Bound{Value: int64(8000)}, Upper: &Bound{Value: int64(9000), Inclusive: true}},
+			},
+		},
+		"literal on the left": {
+			expression: "8000 < Port",
+			expected: map[string]*Range{
+				"Port": {Lower: &Bound{Value: int64(8000)}},
+			},
+		},
+		"constant math": {
+			expression: "Uptime >= 2 * 3600",
+			expected: map[string]*Range{
+				"Uptime": {Lower: &Bound{Value: int64(7200), Inclusive: true}},
+			},
+		},
+		"tighter bound wins": {
+			expression: "Port > 10 and Port >= 20 and Port < 100 and Port < 50",
+			expected: map[string]*Range{
+				"Port": {Lower: &Bound{Value: int64(20), Inclusive: true}, Upper: &Bound{Value: int64(50)}},
+			},
+		},
+		"set": {
+			expression: `Status == "a" or Status == "b"`,
+			expected: map[string]*Range{
+				"Status": {Values: []interface{}{"a", "b"}},
+			},
+		},
+		"set filtered by interval": {
+			expression: "(Port == 80 or Port == 443 or Port == 8080) and Port > 100",
+			expected: map[string]*Range{
+				"Port": {Values: []interface{}{int64(443), int64(8080)}},
+			},
+		},
+		"contradiction": {
+			expression: `Status == "a" and Status == "b"`,
+			expected: map[string]*Range{
+				"Status": {Values: []interface{}{}},
+			},
+		},
+		"large integers": {
+			expression: "X == 9007199254740993 or X == 9007199254740992",
+			expected: map[string]*Range{
+				"X": {Values: []interface{}{int64(9007199254740993), int64(9007199254740992)}},
+			},
+		},
+		"unsigned integers": {
+			expression: "X == 18446744073709551615 or X == 18446744073709551614 or X == -1",
+			expected: map[string]*Range{
+				"X": {Values: []interface{}{uint64(18446744073709551615), uint64(18446744073709551614), int64(-1)}},
+			},
+		},
+		"unsigned bounds": {
+			expression: "X > 18446744073709551614 and X >= 9007199254740993 and X < 18446744073709551615",
+			expected: map[string]*Range{
+				"X": {Lower: &Bound{Value: uint64(18446744073709551614)}, Upper: &Bound{Value: uint64(18446744073709551615)}},
+			},
+		},
+		"mixed type set": {
+			expression: `Port == 80 and Port == "80"`,
+			expected: map[string]*Range{
+				"Port": {Values: []interface{}{int64(80)}},
+			},
+		},
+		"union of intervals": {
+			expression: "(Port > 10 and Port < 20) or (Port >= 30 and Port < 40)",
+			expected: map[string]*Range{
+				"Port": {Lower: &Bound{Value: int64(10)}, Upper: &Bound{Value: int64(40)}},
+			},
+		},
+		"or across selectors": {
+			expression: `Status == "a" or Port == 80`,
+			expected:   map[string]*Range{},
+		},
+		"unbounded union": {
+			expression: "Port < 10 or Port > 20",
+			expected:   map[string]*Range{},
+		},
+		"multiple selectors": {
+			expression: `Meta.env == "prod" and Port != 22 and not Port == 80 and Weight < 1.5`,
+			expected: map[string]*Range{
+				"Meta.env": {Values: []interface{}{"prod"}},
+				"Weight":   {Upper: &Bound{Value: 1.5}},
+			},
+		},
+		"json pointer": {
+			expression: `"/Meta/env" == "prod"`,
+			expected: map[string]*Range{
+				"Meta/env": {Values: []interface{}{"prod"}},
+			},
+		},
+		"non constant comparison": {
+			expression: "Port > Other",
+			expected:   map[string]*Range{},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, eval.Ranges())
+		})
+	}
+}
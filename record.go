@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Sample is an evaluation recorded by a Recorder
+type Sample struct {
+	// Fingerprint is the fingerprint of the expression evaluated, see
+	// Evaluator.Fingerprint
+	Fingerprint string `json:"fingerprint"`
+	// Datum is the datum evaluated, serialized with encoding/json
+	Datum json.RawMessage `json:"datum"`
+	// Result is the outcome of the evaluation, false when Error is set
+	Result bool   `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Fingerprint identifies the expression of the evaluator once parsed, so that
+// expressions written differently, with other spacing or parentheses, but
+// parsing the same way share their fingerprint.
+func (eval *Evaluator) Fingerprint() string {
+	sum := sha256.Sum256([]byte(formatExpression(eval.ast)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Recorder records the evaluations of the evaluators created with
+// WithRecorder as samples, written as JSON lines, to be replayed against new
+// versions of the expressions by a Replayer. The datums must be serializable
+// with encoding/json into values the selectors of the expressions resolve the
+// same way. Recorder is safe for concurrent use.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewRecorder returns a recorder writing the samples to w
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// WithRecorder records the evaluations of the expression with the recorder.
+// Evaluations given up on because the context is done are not recorded.
+func WithRecorder(rec *Recorder) Option {
+	return func(o *options) {
+		o.withRecorder = rec
+	}
+}
+
+// Err returns the first error encountered serializing or writing a sample.
+// No sample is recorded after an error.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(ctx context.Context, fingerprint string, datum interface{}, result interface{}, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return
+	}
+
+	sample := Sample{Fingerprint: fingerprint}
+	sample.Datum, r.err = json.Marshal(datum)
+	if r.err != nil {
+		r.err = fmt.Errorf("error serializing datum: %w", r.err)
+		return
+	}
+	if err != nil {
+		sample.Error = err.Error()
+	} else {
+		sample.Result, _ = result.(bool)
+	}
+	if r.err = r.enc.Encode(sample); r.err != nil {
+		r.err = fmt.Errorf("error writing sample: %w", r.err)
+	}
+}
+
+// Replayer re-evaluates recorded samples against new versions of the
+// expressions, to regression-test changes against historical traffic.
+type Replayer struct {
+	samples []Sample
+	decode  func(json.RawMessage) (interface{}, error)
+}
+
+// NewReplayer reads the samples written by a Recorder. decode deserializes
+// the datums of the samples; when nil, they are decoded with encoding/json
+// into maps, numbers being decoded as json.Number.
+func NewReplayer(r io.Reader, decode func(json.RawMessage) (interface{}, error)) (*Replayer, error) {
+	if decode == nil {
+		decode = decodeDatum
+	}
+	replayer := &Replayer{decode: decode}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("error reading sample at line %d: %w", line, err)
+		}
+		replayer.samples = append(replayer.samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading samples: %w", err)
+	}
+	return replayer, nil
+}
+
+// Samples returns the samples read
+func (r *Replayer) Samples() []Sample {
+	return r.samples
+}
+
+// Replay re-evaluates the samples recorded for the expression of the given
+// fingerprint, or every sample when empty, against the candidate and returns
+// the divergences between the outcomes recorded, reported as Active, and the
+// outcomes of the candidate, reported as Shadow. Recorded errors are only
+// compared by their presence.
+func (r *Replayer) Replay(candidate *Evaluator, fingerprint string) ([]*Divergence, error) {
+	expression := formatExpression(candidate.ast)
+	var divergences []*Divergence
+	for i, sample := range r.samples {
+		if fingerprint != "" && sample.Fingerprint != fingerprint {
+			continue
+		}
+		datum, err := r.decode(sample.Datum)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding the datum of sample %d: %w", i, err)
+		}
+
+		var recordedErr error
+		if sample.Error != "" {
+			recordedErr = errors.New(sample.Error)
+		}
+		result, err := candidate.Evaluate(datum)
+		if d := newDivergence(expression, datum, sample.Result, recordedErr, result, err); d != nil {
+			divergences = append(divergences, d)
+		}
+	}
+	return divergences, nil
+}
+
+func decodeDatum(data json.RawMessage) (interface{}, error) {
+	var datum interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&datum); err != nil {
+		return nil, err
+	}
+	return datum, nil
+}
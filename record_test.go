@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	active, err := CreateEvaluator(`Port > 1024 and Env == "prod"`, WithRecorder(rec))
+	require.NoError(t, err)
+	other, err := CreateEvaluator(`Env == "dev"`, WithRecorder(rec))
+	require.NoError(t, err)
+
+	type service struct {
+		Port int
+		Env  string
+	}
+	data := []interface{}{
+		map[string]interface{}{"Port": 8080, "Env": "prod"},
+		map[string]interface{}{"Port": 80, "Env": "prod"},
+		service{Port: 9090, Env: "dev"},
+		map[string]interface{}{"Port": 2048, "Env": "staging"},
+		map[string]interface{}{"Port": 2048},
+	}
+	for _, datum := range data {
+		_, _ = active.Evaluate(datum)
+	}
+	_, err = other.Evaluate(data[2])
+	require.NoError(t, err)
+	require.NoError(t, rec.Err())
+
+	replayer, err := NewReplayer(&buf, nil)
+	require.NoError(t, err)
+	samples := replayer.Samples()
+	require.Len(t, samples, 6)
+	require.Equal(t, active.Fingerprint(), samples[0].Fingerprint)
+	require.JSONEq(t, `{"Port": 8080, "Env": "prod"}`, string(samples[0].Datum))
+	require.True(t, samples[0].Result)
+	require.False(t, samples[1].Result)
+	require.JSONEq(t, `{"Port": 9090, "Env": "dev"}`, string(samples[2].Datum))
+	require.Contains(t, samples[4].Error, `couldn't find key "Env"`)
+	require.Equal(t, other.Fingerprint(), samples[5].Fingerprint)
+
+	// the same expression written differently
+	same, err := CreateEvaluator(`(Port > 1024)   and Env == "prod"`)
+	require.NoError(t, err)
+	require.Equal(t, active.Fingerprint(), same.Fingerprint())
+	divergences, err := replayer.Replay(same, active.Fingerprint())
+	require.NoError(t, err)
+	require.Empty(t, divergences)
+
+	candidate, err := CreateEvaluator(`Port > 1024 and Env != "dev"`)
+	require.NoError(t, err)
+	divergences, err = replayer.Replay(candidate, active.Fingerprint())
+	require.NoError(t, err)
+	require.Len(t, divergences, 1)
+	require.Equal(t, `Port > 1024 and Env != "dev"`, divergences[0].Candidate)
+	require.Equal(t, map[string]interface{}{"Port": json.Number("2048"), "Env": "staging"}, divergences[0].Datum)
+	require.False(t, divergences[0].Active)
+	require.NoError(t, divergences[0].ActiveErr)
+	require.True(t, divergences[0].Shadow)
+	require.NoError(t, divergences[0].ShadowErr)
+
+	// every sample
+	divergences, err = replayer.Replay(candidate, "")
+	require.NoError(t, err)
+	require.Len(t, divergences, 2)
+}
+
+func TestRecordReplay_Errors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	eval, err := CreateEvaluator(`Port > 1024`, WithRecorder(rec))
+	require.NoError(t, err)
+	_, _ = eval.Evaluate(map[string]interface{}{"Port": 8080, "Fn": func() {}})
+	require.Error(t, rec.Err())
+	require.Contains(t, rec.Err().Error(), "error serializing datum")
+	require.Zero(t, buf.Len())
+
+	_, err = NewReplayer(strings.NewReader("{\"fingerprint\": \"a\", \"datum\": {}}\nnot json\n"), nil)
+	require.EqualError(t, err, "error reading sample at line 2: invalid character 'o' in literal null (expecting 'u')")
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// ErrRegexLimit matches the errors of the regular expressions, and of the
+// values matched against them, exceeding the limits set with
+// WithMaxPatternLength, WithMaxPatternSize and WithMaxMatchLength
+var ErrRegexLimit = errors.New("regular expression limit exceeded")
+
+// regexLimits bound the regular expressions of the matches and not matches
+// operators and the values matched against them. 0 means no limit.
+type regexLimits struct {
+	maxPatternLength int
+	maxPatternSize   int
+	maxMatchLength   int
+}
+
+func (l regexLimits) validate() error {
+	switch {
+	case l.maxPatternLength < 0:
+		return fmt.Errorf("the maximum pattern length cannot be negative, got %d", l.maxPatternLength)
+	case l.maxPatternSize < 0:
+		return fmt.Errorf("the maximum pattern size cannot be negative, got %d", l.maxPatternSize)
+	case l.maxMatchLength < 0:
+		return fmt.Errorf("the maximum match length cannot be negative, got %d", l.maxMatchLength)
+	}
+	return nil
+}
+
+// compile compiles the regular expression once it is known to be within the
+// limits. The size of a pattern is the number of instructions of the program
+// it compiles to, which grows with the nested repetitions such as
+// `(a{30}){30}` much faster than the pattern does.
+func (l regexLimits) compile(pattern string) (*regexp.Regexp, error) {
+	if l.maxPatternLength > 0 && len(pattern) > l.maxPatternLength {
+		return nil, fmt.Errorf("%w: regular expression of %d bytes, more than the %d allowed", ErrRegexLimit, len(pattern), l.maxPatternLength)
+	}
+	if l.maxPatternSize > 0 {
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regular expression %q: %v", pattern, err)
+		}
+		prog, err := syntax.Compile(re.Simplify())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile regular expression %q: %v", pattern, err)
+		}
+		if size := len(prog.Inst); size > l.maxPatternSize {
+			return nil, fmt.Errorf("%w: regular expression %q compiles to %d instructions, more than the %d allowed", ErrRegexLimit, pattern, size, l.maxPatternSize)
+		}
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile regular expression %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// checkMatch checks the length of a value matched against a regular
+// expression
+func (l regexLimits) checkMatch(value []byte) error {
+	if l.maxMatchLength > 0 && len(value) > l.maxMatchLength {
+		return fmt.Errorf("%w: value of %d bytes matched, more than the %d allowed", ErrRegexLimit, len(value), l.maxMatchLength)
+	}
+	return nil
+}
+
+// checkPatterns checks the regular expressions of the expression which are
+// literals, so that the expressions with patterns exceeding the limits are
+// rejected when the evaluator is created rather than when it is evaluated
+func (l regexLimits) checkPatterns(ast interface{}) error {
+	if l.maxPatternLength == 0 && l.maxPatternSize == 0 {
+		return nil
+	}
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return l.checkPatterns(node.Operand)
+	case *grammar.BinaryExpression:
+		if err := l.checkPatterns(node.Left); err != nil {
+			return err
+		}
+		return l.checkPatterns(node.Right)
+	case *grammar.LetExpression:
+		return l.checkPatterns(node.Body)
+	case *grammar.MatchExpression:
+		if node.Operator != grammar.MatchMatches && node.Operator != grammar.MatchNotMatches {
+			return nil
+		}
+		if node.Right == nil || !isConstant(node.Right) {
+			return nil
+		}
+		pattern, err := getExprValue(node.Right, nil)
+		if err != nil {
+			return nil
+		}
+		if s, ok := pattern.(string); ok {
+			if _, err := l.compile(s); errors.Is(err, ErrRegexLimit) {
+				return fmt.Errorf("%s: %w", formatExpression(node), err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexLimits_Create(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"no limits": {
+			expression: `Name matches "(a{30}){30}"`,
+		},
+		"pattern length": {
+			expression: `Name matches "^web-[0-9]+$"`,
+			opts:       []Option{WithMaxPatternLength(8)},
+			err:        `Name matches "^web-[0-9]+$": regular expression limit exceeded: regular expression of 12 bytes, more than the 8 allowed`,
+		},
+		"pattern length within": {
+			expression: `Name matches "^web-[0-9]+$"`,
+			opts:       []Option{WithMaxPatternLength(12)},
+		},
+		"pattern size": {
+			expression: `Name == "web" or Name not matches "(a{30}){30}"`,
+			opts:       []Option{WithMaxPatternSize(900)},
+			err:        `Name not matches "(a{30}){30}": regular expression limit exceeded: regular expression "(a{30}){30}" compiles to 962 instructions, more than the 900 allowed`,
+		},
+		"pattern size within": {
+			expression: `Name matches "^web-[0-9]+$"`,
+			opts:       []Option{WithMaxPatternSize(1000)},
+		},
+		"let": {
+			expression: `let n = Name in n matches "a{1000}"`,
+			opts:       []Option{WithMaxPatternSize(100)},
+			err:        `n matches "a{1000}": regular expression limit exceeded: regular expression "a{1000}" compiles to 1002 instructions, more than the 100 allowed`,
+		},
+		"parameter": {
+			expression: `Name matches $pattern`,
+			opts:       []Option{WithMaxPatternLength(1)},
+		},
+		"other operators": {
+			expression: `Name == "a long string literal"`,
+			opts:       []Option{WithMaxPatternLength(1)},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tc.expression, tc.opts...)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrRegexLimit)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestRegexLimits_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		datum      map[string]interface{}
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"match length": {
+			expression: `Name matches "^a+$"`,
+			opts:       []Option{WithMaxMatchLength(16)},
+			datum:      map[string]interface{}{"Name": strings.Repeat("a", 17)},
+			err:        `1:1 (0): Name matches "^a+$": regular expression limit exceeded: value of 17 bytes matched, more than the 16 allowed`,
+		},
+		"match length within": {
+			expression: `Name matches "^a+$"`,
+			opts:       []Option{WithMaxMatchLength(16)},
+			datum:      map[string]interface{}{"Name": strings.Repeat("a", 16)},
+			result:     true,
+		},
+		"not matches": {
+			expression: `Name not matches "^a+$"`,
+			opts:       []Option{WithMaxMatchLength(16)},
+			datum:      map[string]interface{}{"Name": strings.Repeat("a", 17)},
+			err:        `1:1 (0): Name not matches "^a+$": regular expression limit exceeded: value of 17 bytes matched, more than the 16 allowed`,
+		},
+		"selected pattern": {
+			expression: `Name matches Pattern`,
+			opts:       []Option{WithMaxPatternSize(100)},
+			datum:      map[string]interface{}{"Name": "a", "Pattern": "a{1000}"},
+			err:        `1:1 (0): Name matches Pattern: regular expression limit exceeded: regular expression "a{1000}" compiles to 1002 instructions, more than the 100 allowed`,
+		},
+		"parameter": {
+			expression: `Name matches $pattern`,
+			opts:       []Option{WithMaxPatternLength(4), WithParams(map[string]interface{}{"pattern": "^abcdef$"})},
+			datum:      map[string]interface{}{"Name": "a"},
+			err:        `1:1 (0): Name matches $pattern: regular expression limit exceeded: regular expression of 8 bytes, more than the 4 allowed`,
+		},
+		"invalid pattern": {
+			expression: `Name matches Pattern`,
+			opts:       []Option{WithMaxPatternSize(100)},
+			datum:      map[string]interface{}{"Name": "a", "Pattern": "("},
+			err:        "1:1 (0): Name matches Pattern: failed to compile regular expression \"(\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression, tc.opts...)
+			require.NoError(t, err)
+			result, err := eval.Evaluate(tc.datum)
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, tc.result, result)
+				return
+			}
+			if !strings.Contains(tc.err, "failed to compile") {
+				require.ErrorIs(t, err, ErrRegexLimit)
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
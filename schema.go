@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/mitchellh/pointerstructure"
+)
+
+// Schema describes the shape of the data expressions will be evaluated
+// against, so that expressions can be validated without a datum.
+type Schema interface {
+	// SelectorType returns the type of the value found at the selector path.
+	// An interface type means the type is only known at evaluation time.
+	SelectorType(path []string) (reflect.Type, error)
+}
+
+// typeSchema is a Schema derived from a Go type. Selectors are resolved the
+// same way pointerstructure resolves them at evaluation time.
+type typeSchema struct {
+	typ      reflect.Type
+	tagName  string
+	tagNames []string
+}
+
+// TypeSchema returns a Schema describing the type of the given value. Struct
+// fields are looked up using the tag names the evaluator is configured with.
+func TypeSchema(value interface{}) Schema {
+	return &typeSchema{typ: reflect.TypeOf(value)}
+}
+
+var interfaceTyp = reflect.TypeOf((*interface{})(nil)).Elem()
+
+func (s *typeSchema) SelectorType(path []string) (reflect.Type, error) {
+	typ := s.typ
+	if typ == nil {
+		return interfaceTyp, nil
+	}
+
+	for i, part := range path {
+		typ = derefType(resolvedType(typ))
+		switch typ.Kind() {
+		case reflect.Interface:
+			// the rest of the path can only be resolved against a datum
+			return interfaceTyp, nil
+		case reflect.Map:
+			if !reflect.TypeOf(part).ConvertibleTo(typ.Key()) && !isNumberKind(typ.Key().Kind()) {
+				return nil, fmt.Errorf("%s at part %d: map key type %s is not supported", s.pointer(path), i, typ.Key())
+			}
+			typ = typ.Elem()
+		case reflect.Slice, reflect.Array:
+			if _, err := strconv.Atoi(part); err != nil {
+				return nil, fmt.Errorf("%s at part %d: invalid slice index %q", s.pointer(path), i, part)
+			}
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, err := s.structField(typ, part)
+			if err != nil {
+				return nil, fmt.Errorf("%s at part %d: %w", s.pointer(path), i, err)
+			}
+			typ = field.Type
+		default:
+			return nil, fmt.Errorf("%s: at part %d, %w: %s", s.pointer(path), i, pointerstructure.ErrInvalidKind, typ.Kind())
+		}
+	}
+	return resolvedType(typ), nil
+}
+
+// resolvedType returns the type of the values the values of the type resolve
+// to when selected, such as the type of the value the nullable types of
+// database/sql hold, string for BSON object IDs, or the maps ordered BSON
+// documents are converted to
+func resolvedType(typ reflect.Type) reflect.Type {
+	switch elem := derefType(typ); {
+	case isSQLNullType(elem):
+		return resolvedType(elem.Field(0).Type)
+	case isBSONObjectID(elem):
+		return stringTyp
+	case isBSONDocument(elem):
+		return bsonDocumentTyp
+	}
+	return typ
+}
+
+// SelectorDoc reads the documentation of the struct field found at the path
+// from its "doc" and "example" tags.
+func (s *typeSchema) SelectorDoc(path []string) (FieldDoc, bool) {
+	if len(path) == 0 {
+		return FieldDoc{}, false
+	}
+	parent, err := s.SelectorType(path[:len(path)-1])
+	if err != nil || parent == nil || derefType(parent).Kind() != reflect.Struct {
+		return FieldDoc{}, false
+	}
+	field, err := s.structField(derefType(parent), path[len(path)-1])
+	if err != nil {
+		return FieldDoc{}, false
+	}
+	return structFieldDoc(field)
+}
+
+// structFieldDoc reads the documentation of the struct field from its tags
+func structFieldDoc(field reflect.StructField) (FieldDoc, bool) {
+	doc := FieldDoc{Description: field.Tag.Get("doc")}
+	if example, ok := field.Tag.Lookup("example"); ok {
+		doc.Examples = []string{example}
+	}
+	return doc, doc.Description != "" || doc.Examples != nil
+}
+
+func (s *typeSchema) pointer(path []string) string {
+	ptr := pointerstructure.Pointer{Parts: path}
+	return ptr.String()
+}
+
+// structField mirrors the struct field lookup of pointerstructure, or the
+// one of taggedPointer when the fields are named after several tags
+func (s *typeSchema) structField(typ reflect.Type, part string) (reflect.StructField, error) {
+	if len(s.tagNames) > 1 {
+		field, err := taggedField(typ, part, s.tagNames)
+		if err != nil {
+			return reflect.StructField{}, err
+		}
+		return typ.Field(field), nil
+	}
+
+	var found *reflect.StructField
+	ignored := false
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		switch tag := taggedName(field, []string{s.tagName}); {
+		case tag == "-":
+			if field.Name == part {
+				ignored = true
+			}
+		case tag == part:
+			return field, nil
+		case tag == "" && field.Name == part:
+			found = &field
+		}
+	}
+
+	switch {
+	case ignored:
+		return reflect.StructField{}, fmt.Errorf("struct field %q is ignored and cannot be used", part)
+	case found == nil:
+		return reflect.StructField{}, fmt.Errorf("%w: struct field with name %q", pointerstructure.ErrNotFound, part)
+	}
+	return *found, nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isUnsignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldTagNames returns the tags the struct fields are named after
+func (s *typeSchema) fieldTagNames() []string {
+	if len(s.tagNames) > 1 {
+		return s.tagNames
+	}
+	return []string{s.tagName}
+}
@@ -0,0 +1,271 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// ErrSelectorNotAllowed matches the *SelectorNotAllowedError errors
+var ErrSelectorNotAllowed = errors.New("selector not allowed")
+
+// SelectorNotAllowedError is the error of the expressions with selectors
+// the options WithAllowedSelectors and WithDeniedSelectors do not allow
+type SelectorNotAllowedError struct {
+	// Selector is the selector, such as Meta.secret. The selectors starting
+	// with a name bound by a let expression are reported as written.
+	Selector string
+	// Position is where the match expression or the let expression with the
+	// selector starts, see EvaluationError
+	Position grammar.Position
+}
+
+func (e *SelectorNotAllowedError) Error() string {
+	if !e.Position.IsValid() {
+		return fmt.Sprintf("selector %s is not allowed", e.Selector)
+	}
+	return fmt.Sprintf("%s: selector %s is not allowed", e.Position, e.Selector)
+}
+
+func (e *SelectorNotAllowedError) Is(target error) bool {
+	return target == ErrSelectorNotAllowed
+}
+
+// WithAllowedSelectors fails the creation of evaluators on expressions with
+// selectors no pattern matches, so that the expressions written by untrusted
+// users only reference the fields meant for them. Patterns are matched the
+// same way as the ones of WithSelectorHook: "Meta" allows Meta and all the
+// selectors below it, and "Services.*.Name" allows the names of the services
+// only. The selectors are checked once prefixed, see WithSelectorPrefix, and
+// the selectors of the macros, see WithMacros, are checked too. When given
+// more than once, the patterns are merged.
+func WithAllowedSelectors(patterns ...string) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			o.withAllowedSelectors = append(o.withAllowedSelectors, newSelectorPattern(pattern))
+		}
+	}
+}
+
+// WithDeniedSelectors fails the creation of evaluators on expressions with
+// selectors matching any of the patterns, like WithAllowedSelectors, denied
+// selectors taking precedence over allowed ones. The selectors of the values
+// holding denied fields are denied too: denying "Meta.secret" denies Meta,
+// which would let `"secret" in Meta` tell whether it is set.
+func WithDeniedSelectors(patterns ...string) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			o.withDeniedSelectors = append(o.withDeniedSelectors, newSelectorPattern(pattern))
+		}
+	}
+}
+
+// selectorPolicy checks the selectors of expressions against the patterns of
+// WithAllowedSelectors and WithDeniedSelectors
+type selectorPolicy struct {
+	allowed []selectorPattern
+	denied  []selectorPattern
+}
+
+// anyIndex is the part of the paths standing for the indexes of the ranges
+// which cannot be enumerated, such as the one of Events[-10:]. A denied
+// pattern matches it whatever its part, an allowed pattern only with "*".
+const anyIndex = "\x00*"
+
+// maxRangeIndexes is the number of indexes beyond which the indexes of a
+// range are not enumerated
+const maxRangeIndexes = 64
+
+func (p selectorPolicy) allows(path []string) bool {
+	for _, pattern := range p.denied {
+		if denies(pattern, path) {
+			return false
+		}
+	}
+	if len(p.allowed) == 0 {
+		return true
+	}
+	for _, pattern := range p.allowed {
+		if pattern.matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// denies reports whether the pattern matches the path or a value the path
+// holds, see selectorPattern.matches and selectorPattern.holds
+func denies(pattern selectorPattern, path []string) bool {
+	for i := 0; i < len(pattern.parts) && i < len(path); i++ {
+		if pattern.parts[i] != "*" && path[i] != anyIndex && pattern.parts[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// check returns a *SelectorNotAllowedError for the first selector of the
+// expression which is not allowed. The selectors starting with a name bound
+// to a selector by a let expression are checked as the selectors they stand
+// for, the selector the name is bound to followed by the rest of their path,
+// so that `let m = Meta in m.env == "prod"` only selects Meta.env. The
+// selectors with ranges are checked as the selectors of the elements of the
+// ranges, Services[0:2].Name selecting Services.0.Name and Services.1.Name.
+func (p selectorPolicy) check(ast interface{}) error {
+	if len(p.allowed) == 0 && len(p.denied) == 0 {
+		return nil
+	}
+	return p.checkNode(ast, nil, grammar.Position{})
+}
+
+func (p selectorPolicy) checkNode(ast interface{}, bound map[string][][]string, pos grammar.Position) error {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return p.checkNode(node.Operand, bound, pos)
+	case *grammar.BinaryExpression:
+		if err := p.checkNode(node.Left, bound, pos); err != nil {
+			return err
+		}
+		return p.checkNode(node.Right, bound, pos)
+	case *grammar.LetExpression:
+		// the values selected are checked where the body references them,
+		// and the sub-paths of computed values are not selectors of the datum
+		var paths [][]string
+		if value := operandValue(node.Value); value != nil && value.Type == grammar.ValueTypeReflect {
+			paths = p.resolve(value.Selector, bound)
+		} else if err := p.checkNode(node.Value, bound, node.Position); err != nil {
+			return err
+		}
+		scope := make(map[string][][]string, len(bound)+1)
+		for name, paths := range bound {
+			scope[name] = paths
+		}
+		scope[node.Name] = paths
+		return p.checkNode(node.Body, scope, pos)
+	case *grammar.MatchExpression:
+		if node.Left != nil {
+			if err := p.checkNode(node.Left, bound, node.Position); err != nil {
+				return err
+			}
+		}
+		if node.Right != nil {
+			return p.checkNode(node.Right, bound, node.Position)
+		}
+	case *grammar.ExpressionValue:
+		if node == nil {
+			return nil
+		}
+		if err := p.checkNode(node.Left, bound, pos); err != nil {
+			return err
+		}
+		return p.checkNode(node.Right, bound, pos)
+	case *grammar.MatchValue:
+		if node == nil {
+			return nil
+		}
+		for _, arg := range node.Args {
+			if err := p.checkNode(arg, bound, pos); err != nil {
+				return err
+			}
+		}
+		if node.Type != grammar.ValueTypeReflect {
+			return nil
+		}
+		for _, path := range p.resolve(node.Selector, bound) {
+			if !p.allows(path) {
+				return &SelectorNotAllowedError{Selector: node.Selector.String(), Position: pos}
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the paths of the selector in the datum, see rangePaths, or
+// nil for the selectors within values computed by let expressions
+func (p selectorPolicy) resolve(sel grammar.Selector, bound map[string][][]string) [][]string {
+	if sel.Anchored || len(sel.Path) == 0 {
+		return rangePaths(sel.Path, sel.Ranges)
+	}
+	prefixes, ok := bound[sel.Path[0]]
+	if !ok {
+		return rangePaths(sel.Path, sel.Ranges)
+	}
+	if prefixes == nil {
+		return nil
+	}
+	var paths [][]string
+	for _, rest := range rangePaths(sel.Path[1:], shiftRanges(sel.Ranges, -1)) {
+		for _, prefix := range prefixes {
+			paths = append(paths, append(prefix[:len(prefix):len(prefix)], rest...))
+		}
+	}
+	return paths
+}
+
+// rangePaths returns the paths of the elements of the ranges of a selector,
+// the ranges being replaced by the indexes they select, or by anyIndex when
+// they cannot be enumerated
+func rangePaths(path []string, ranges []grammar.SelectorRange) [][]string {
+	paths := [][]string{nil}
+	last := 0
+	for len(ranges) > 0 {
+		at := ranges[0].At
+		n := 1
+		for n < len(ranges) && ranges[n].At == at {
+			n++
+		}
+		indexes := rangeIndexes(ranges[:n])
+		next := make([][]string, 0, len(paths)*len(indexes))
+		for _, prefix := range paths {
+			prefix = append(prefix[:len(prefix):len(prefix)], path[last:at]...)
+			for _, index := range indexes {
+				next = append(next, append(prefix[:len(prefix):len(prefix)], index))
+			}
+		}
+		paths, last, ranges = next, at, ranges[n:]
+	}
+	for i := range paths {
+		paths[i] = append(paths[i], path[last:]...)
+	}
+	return paths
+}
+
+// rangeIndexes returns the indexes a range selects, followed by the ranges
+// slicing its elements in turn, such as the 1 and 2 of [0:3][1:], or anyIndex
+// when they depend on the length of the slice, are too many or are none
+func rangeIndexes(ranges []grammar.SelectorRange) []string {
+	first := ranges[0]
+	if (first.Low != nil && *first.Low < 0) || first.High == nil || *first.High < 0 {
+		return []string{anyIndex}
+	}
+	low := 0
+	if first.Low != nil {
+		low = *first.Low
+	}
+	high := *first.High
+	if high-low > maxRangeIndexes {
+		return []string{anyIndex}
+	}
+	var indexes []int
+	for i := low; i < high; i++ {
+		indexes = append(indexes, i)
+	}
+	for _, r := range ranges[1:] {
+		elems := sliceRange(reflect.ValueOf(indexes), r)
+		indexes = elems.Interface().([]int)
+	}
+	if len(indexes) == 0 {
+		return []string{anyIndex}
+	}
+	parts := make([]string, len(indexes))
+	for i, index := range indexes {
+		parts[i] = strconv.Itoa(index)
+	}
+	return parts
+}
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorPolicy(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		err        string
+	}
+
+	allowed := WithAllowedSelectors("Name", "Meta.env", "Services.*.Name")
+	denied := WithDeniedSelectors("Meta.secret", "Internal")
+
+	tests := map[string]testCase{
+		"no policy":            {expression: `Internal.ID == 1`},
+		"allowed":              {expression: `Name == "web" and Meta.env == "prod"`, opts: []Option{allowed}},
+		"allowed below":        {expression: `Name.First == "a"`, opts: []Option{allowed}},
+		"allowed wildcard":     {expression: `Services.0.Name == "db"`, opts: []Option{allowed}},
+		"not allowed":          {expression: `Name == "web" and Meta.region == "eu"`, opts: []Option{allowed}, err: `1:19 (18): selector Meta.region is not allowed`},
+		"not allowed above":    {expression: `"env" in Meta`, opts: []Option{allowed}, err: `1:1 (0): selector Meta is not allowed`},
+		"not allowed wildcard": {expression: `Services.0.Port == 80`, opts: []Option{allowed}, err: `1:1 (0): selector Services.0.Port is not allowed`},
+		"right operand":        {expression: `Name == Meta.owner`, opts: []Option{allowed}, err: `1:1 (0): selector Meta.owner is not allowed`},
+		"math":                 {expression: `Meta.env + Port == "a"`, opts: []Option{allowed}, err: `1:1 (0): selector Port is not allowed`},
+		"denied":               {expression: `Meta.secret == "s"`, opts: []Option{denied}, err: `1:1 (0): selector Meta.secret is not allowed`},
+		"denied below":         {expression: `not Internal.ID == 1`, opts: []Option{denied}, err: `1:5 (4): selector Internal.ID is not allowed`},
+		"denied above":         {expression: `Meta is empty`, opts: []Option{denied}, err: `1:1 (0): selector Meta is not allowed`},
+		"denied sibling":       {expression: `Meta.env == "prod"`, opts: []Option{denied}},
+		"denied over allowed":  {expression: `Meta.env == "prod"`, opts: []Option{allowed, WithDeniedSelectors("Meta.*")}, err: `1:1 (0): selector Meta.env is not allowed`},
+		"let":                  {expression: `let m = Meta in m.env == "prod"`, opts: []Option{allowed}},
+		"let not allowed":      {expression: `let m = Meta in m.secret == "s"`, opts: []Option{denied}, err: `1:17 (16): selector m.secret is not allowed`},
+		"let value":            {expression: `let m = Meta in m is empty`, opts: []Option{denied}, err: `1:17 (16): selector m is not allowed`},
+		"let computed value":   {expression: `let p = Port + 1 in p == 81`, opts: []Option{allowed}, err: `1:1 (0): selector Port is not allowed`},
+		"nested let":           {expression: `let m = Meta in let s = m.secret in s == "s"`, opts: []Option{denied}, err: `1:37 (36): selector s is not allowed`},
+		"let computed":         {expression: `let n = Name + "s" in n == "webs"`, opts: []Option{allowed}},
+		"let shadowing":        {expression: `let Meta = Name in Meta.secret == "s"`, opts: []Option{denied}},
+		"anchored":             {expression: `let Meta = Name in $.Meta.secret == "s"`, opts: []Option{denied}, err: `1:20 (19): selector $.Meta.secret is not allowed`},
+		"prefix":               {expression: `env == "prod"`, opts: []Option{allowed, WithSelectorPrefix("Meta")}},
+		"prefix not allowed":   {expression: `secret == "s"`, opts: []Option{denied, WithSelectorPrefix("Meta")}, err: `1:1 (0): selector $.Meta.secret is not allowed`},
+		"macro":                {expression: `secret`, opts: []Option{denied, WithMacros(map[string]string{"secret": `Meta.secret == "s"`})}, err: `selector Meta.secret is not allowed`},
+		"range allowed":        {expression: `Services[0:2].Name contains "db" and Services[:].Name is not empty`, opts: []Option{allowed}},
+		"range not allowed":    {expression: `Services[0:1].Port contains 80`, opts: []Option{allowed}, err: `1:1 (0): selector Services[0:1].Port is not allowed`},
+		"range elements":       {expression: `Services[0:1] is empty`, opts: []Option{allowed}, err: `1:1 (0): selector Services[0:1] is not allowed`},
+		"range denied":         {expression: `Services[0:1].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}, err: `1:1 (0): selector Services[0:1].Token is not allowed`},
+		"range denied sibling": {expression: `Services[1:3].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}},
+		"range denied open":    {expression: `Services[-1:].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}, err: `1:1 (0): selector Services[-1:].Token is not allowed`},
+		"range denied sliced":  {expression: `Services[0:3][1:].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.2.Token")}, err: `1:1 (0): selector Services[0:3][1:].Token is not allowed`},
+		"range denied slices":  {expression: `Services[0:3][1:].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}},
+		"range denied above":   {expression: `Services[0:1] is empty`, opts: []Option{WithDeniedSelectors("Services.0.Token")}, err: `1:1 (0): selector Services[0:1] is not allowed`},
+		"range denied nested":  {expression: `"t" in Services[0:2].Tokens[1:2]`, opts: []Option{WithDeniedSelectors("Services.1.Tokens.1")}, err: `1:1 (0): selector Services[0:2].Tokens[1:2] is not allowed`},
+		"range let":            {expression: `let s = Services[0:1] in s.Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}, err: `1:26 (25): selector s.Token is not allowed`},
+		"range let reference":  {expression: `let s = Services in s[0:1].Token contains "t0p"`, opts: []Option{WithDeniedSelectors("Services.0.Token")}, err: `1:21 (20): selector s[0:1].Token is not allowed`},
+		"range let allowed":    {expression: `let s = Services in s[0:1].Name contains "db"`, opts: []Option{allowed}},
+		"invalid allowed":      {expression: `Name == "web"`, opts: []Option{WithAllowedSelectors("")}, err: `invalid options: invalid allowed selectors: the pattern cannot be empty`},
+		"invalid denied":       {expression: `Name == "web"`, opts: []Option{WithDeniedSelectors("Meta..env")}, err: `invalid options: invalid denied selectors: the pattern "Meta..env" has empty parts`},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tc.expression, tc.opts...)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+			if _, ok := err.(*SelectorNotAllowedError); ok {
+				require.ErrorIs(t, err, ErrSelectorNotAllowed)
+			}
+		})
+	}
+}
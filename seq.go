@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package bexpr
+
+import (
+	"context"
+	"iter"
+)
+
+// FilterSeq returns the sequence of the items of the sequence which match the
+// expression of the evaluator, evaluating them as they are pulled, so that
+// range over func pipelines are filtered without collecting the items in
+// slices. The items the expression fails to evaluate against do not match,
+// see FilterSeqErr to stop at their errors instead.
+func FilterSeq[T any](seq iter.Seq[T], ev *Evaluator) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range seq {
+			if matched, err := ev.evaluateItem(context.Background(), item); err == nil && matched && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// FilterSeqErr returns the sequence of the items of the sequence which match
+// the expression of the evaluator, paired with a nil error, like FilterSeq.
+// The first item the expression fails to evaluate against ends the sequence,
+// paired with its error.
+func FilterSeqErr[T any](seq iter.Seq[T], ev *Evaluator) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item := range seq {
+			matched, err := ev.evaluateItem(context.Background(), item)
+			if err != nil {
+				yield(item, err)
+				return
+			}
+			if matched && !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package bexpr
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSeq(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`X == 1`)
+	require.NoError(t, err)
+	require.Equal(t, []testStruct{{X: 1, Y: "a"}, {X: 1, Y: "b"}}, slices.Collect(FilterSeq(slices.Values(testSlice), eval)))
+
+	// pulling stops evaluating
+	evaluated := 0
+	seq := func(yield func(testStruct) bool) {
+		for _, item := range testSlice {
+			evaluated++
+			if !yield(item) {
+				return
+			}
+		}
+	}
+	for range FilterSeq(seq, eval) {
+		break
+	}
+	require.Equal(t, 1, evaluated)
+
+	items := []interface{}{map[string]int{"X": 1}, map[string]string{"X": "a"}, map[string]int{"X": 1}}
+	require.Len(t, slices.Collect(FilterSeq(slices.Values(items), eval)), 2)
+}
+
+func TestFilterSeqErr(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`X > 1`)
+	require.NoError(t, err)
+
+	items := []interface{}{map[string]int{"X": 2}, map[string]int{"X": 1}, map[string]string{"X": "a"}, map[string]int{"X": 3}}
+	var matched []interface{}
+	var errs []error
+	for item, err := range FilterSeqErr(slices.Values(items), eval) {
+		matched = append(matched, item)
+		errs = append(errs, err)
+	}
+	require.Equal(t, items[0:1], matched[0:1])
+	require.Equal(t, items[2], matched[1])
+	require.NoError(t, errs[0])
+	require.ErrorIs(t, errs[1], ErrUnsupportedOperator)
+	require.Len(t, errs, 2)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+var (
+	selectorTypes = map[grammar.SelectorType]string{
+		grammar.SelectorTypeBexpr:       "SELECTOR_TYPE_BEXPR",
+		grammar.SelectorTypeJsonPointer: "SELECTOR_TYPE_JSON_POINTER",
+		grammar.SelectorTypeJsonPath:    "SELECTOR_TYPE_JSON_PATH",
+	}
+	literalTypes = map[grammar.ValueType]string{
+		grammar.ValueTypeBool:      "LITERAL_TYPE_BOOL",
+		grammar.ValueTypeInt:       "LITERAL_TYPE_INT",
+		grammar.ValueTypeUint:      "LITERAL_TYPE_UINT",
+		grammar.ValueTypeFloat32:   "LITERAL_TYPE_FLOAT32",
+		grammar.ValueTypeFloat64:   "LITERAL_TYPE_FLOAT64",
+		grammar.ValueTypeString:    "LITERAL_TYPE_STRING",
+		grammar.ValueTypeNull:      "LITERAL_TYPE_NULL",
+		grammar.ValueTypeUndefined: "LITERAL_TYPE_UNDEFINED",
+	}
+	mathOperators = map[grammar.MathOperator]string{
+		grammar.MathOpPlus:  "MATH_OPERATOR_PLUS",
+		grammar.MathOpMinus: "MATH_OPERATOR_MINUS",
+		grammar.MathOpMul:   "MATH_OPERATOR_MULTIPLY",
+		grammar.MathOpDiv:   "MATH_OPERATOR_DIVIDE",
+	}
+)
+
+// enumName returns the name of the value of the enum from the name of the
+// operator, such as MATCH_OPERATOR_NOT_STARTS_WITH for "Not Starts With"
+func enumName(enum string, name string) string {
+	return enum + "_" + strings.ToUpper(strings.ReplaceAll(name, " ", "_"))
+}
+
+// newExpression converts the AST into its message
+func newExpression(ast grammar.Expression) (*Expression, error) {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand, err := newExpression(node.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return &Expression{Unary: &UnaryExpression{
+			Operator: enumName("UNARY_OPERATOR", node.Operator.String()),
+			Operand:  operand,
+		}}, nil
+	case *grammar.BinaryExpression:
+		left, err := newExpression(node.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := newExpression(node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &Expression{Binary: &BinaryExpression{
+			Operator: enumName("BINARY_OPERATOR", node.Operator.String()),
+			Left:     left,
+			Right:    right,
+		}}, nil
+	case *grammar.MatchExpression:
+		left, err := newValue(node.Left)
+		if err != nil {
+			return nil, err
+		}
+		match := &MatchExpression{Operator: enumName("MATCH_OPERATOR", node.Operator.String()), Left: left}
+		if node.Right != nil {
+			if match.Right, err = newValue(node.Right); err != nil {
+				return nil, err
+			}
+		}
+		return &Expression{Match: match}, nil
+	case *grammar.LetExpression:
+		value, err := newValue(node.Value)
+		if err != nil {
+			return nil, err
+		}
+		body, err := newExpression(node.Body)
+		if err != nil {
+			return nil, err
+		}
+		return &Expression{Let: &LetExpression{Name: node.Name, Value: value, Body: body}}, nil
+	case *grammar.ExpressionValue:
+		value, err := newValue(node)
+		if err != nil {
+			return nil, err
+		}
+		return &Expression{Value: value}, nil
+	}
+	return nil, fmt.Errorf("unsupported AST node %T", ast)
+}
+
+// newValue converts an expression value, or the operand of a math operator,
+// into its message
+func newValue(operand interface{}) (*Value, error) {
+	switch node := operand.(type) {
+	case *grammar.ExpressionValue:
+		if node.Operator == grammar.MathOpValue {
+			return newValue(node.Left)
+		}
+		left, err := newValue(node.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := newValue(node.Right)
+		if err != nil {
+			return nil, err
+		}
+		return &Value{Math: &MathExpression{Operator: mathOperators[node.Operator], Left: left, Right: right}}, nil
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeReflect:
+			if len(node.Selector.Ranges) > 0 {
+				return nil, fmt.Errorf("unsupported ranges of the selector %s", node.Selector)
+			}
+			return &Value{Selector: &Selector{
+				Type:     selectorTypes[node.Selector.Type],
+				Path:     node.Selector.Path,
+				Anchored: node.Selector.Anchored,
+			}}, nil
+		case grammar.ValueTypeParam:
+			return &Value{Param: node.Raw}, nil
+		case grammar.ValueTypeCall:
+			return nil, fmt.Errorf("unsupported call of the function %s", node.Raw)
+		}
+		if typ, ok := literalTypes[node.Type]; ok {
+			return &Value{Literal: &Literal{Type: typ, Raw: node.Raw}}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported AST value %T", operand)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+import (
+	"testing"
+
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExpression(t *testing.T) {
+	t.Parallel()
+
+	selector := func(path ...string) *Value {
+		return &Value{Selector: &Selector{Type: "SELECTOR_TYPE_BEXPR", Path: path}}
+	}
+
+	type testCase struct {
+		expression string
+		expected   *Expression
+	}
+
+	tests := map[string]testCase{
+		"match": {
+			expression: `Meta.env == "prod"`,
+			expected: &Expression{Match: &MatchExpression{
+				Operator: "MATCH_OPERATOR_EQUAL",
+				Left:     selector("Meta", "env"),
+				Right:    &Value{Literal: &Literal{Type: "LITERAL_TYPE_STRING", Raw: "prod"}},
+			}},
+		},
+		"logical": {
+			expression: `not Tags is empty or a`,
+			expected: &Expression{Binary: &BinaryExpression{
+				Operator: "BINARY_OPERATOR_OR",
+				Left: &Expression{Unary: &UnaryExpression{
+					Operator: "UNARY_OPERATOR_NOT",
+					Operand:  &Expression{Match: &MatchExpression{Operator: "MATCH_OPERATOR_IS_EMPTY", Left: selector("Tags")}},
+				}},
+				Right: &Expression{Value: selector("a")},
+			}},
+		},
+		"let and math": {
+			expression: `let t = Port + 1 in t not startswith $prefix`,
+			expected: &Expression{Let: &LetExpression{
+				Name: "t",
+				Value: &Value{Math: &MathExpression{
+					Operator: "MATH_OPERATOR_PLUS",
+					Left:     selector("Port"),
+					Right:    &Value{Literal: &Literal{Type: "LITERAL_TYPE_INT", Raw: "1"}},
+				}},
+				Body: &Expression{Match: &MatchExpression{Operator: "MATCH_OPERATOR_NOT_STARTS_WITH", Left: selector("t"), Right: &Value{Param: "prefix"}}},
+			}},
+		},
+		"anchored": {
+			expression: `$.Meta is null`,
+			expected: &Expression{Match: &MatchExpression{
+				Operator: "MATCH_OPERATOR_IS_NULL",
+				Left:     &Value{Selector: &Selector{Type: "SELECTOR_TYPE_BEXPR", Path: []string{"Meta"}, Anchored: true}},
+			}},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ast, err := grammar.Parse("", []byte(tcase.expression))
+			require.NoError(t, err)
+			expr, err := newExpression(ast.(grammar.Expression))
+			require.NoError(t, err)
+			require.Equal(t, tcase.expected, expr)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+import "net/http"
+
+// The codes of the errors, named as in Connect and gRPC
+const (
+	CodeCanceled        = "canceled"
+	CodeInvalidArgument = "invalid_argument"
+	CodeNotFound        = "not_found"
+	CodeInternal        = "internal"
+	CodeUnimplemented   = "unimplemented"
+)
+
+// Error is the error of a method of the service, encoded in JSON as the
+// errors of Connect
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// httpStatus returns the HTTP status of the errors of the code, see the
+// Connect protocol
+func httpStatus(code string) int {
+	switch code {
+	case CodeCanceled:
+		return 499
+	case CodeInvalidArgument:
+		return http.StatusBadRequest
+	case CodeNotFound, CodeUnimplemented:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
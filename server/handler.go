@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ServicePath is the path prefix of the methods of the service
+const ServicePath = "/bexpr.v1.FilterService/"
+
+// maxRequestBytes is the default size limit of the bodies of the requests
+const maxRequestBytes = 4 << 20
+
+// NewHandler serves the methods of the service with the unary JSON protocol of
+// Connect: each method is called with a POST request to ServicePath followed
+// by the name of the method, the body holding the request message encoded in
+// JSON. Errors are answered with the HTTP status of their code and the Error
+// encoded in JSON. The handler is meant to be mounted on ServicePath:
+//
+//	mux.Handle(server.ServicePath, server.NewHandler(server.NewService()))
+//
+// Request bodies larger than 4 MiB are refused.
+func NewHandler(svc *Service) http.Handler {
+	return &handler{svc: svc}
+}
+
+type handler struct {
+	svc *Service
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	method := strings.TrimPrefix(r.URL.Path, ServicePath)
+	if method == r.URL.Path {
+		writeError(w, &Error{Code: CodeNotFound, Message: fmt.Sprintf("unknown path %s", r.URL.Path)})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "the methods are called with POST", http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "the messages are encoded in JSON", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req interface{}
+	var call func(ctx context.Context) (interface{}, error)
+	switch method {
+	case "Parse":
+		msg := &ParseRequest{}
+		req, call = msg, func(ctx context.Context) (interface{}, error) { return h.svc.Parse(ctx, msg) }
+	case "Validate":
+		msg := &ValidateRequest{}
+		req, call = msg, func(ctx context.Context) (interface{}, error) { return h.svc.Validate(ctx, msg) }
+	case "Evaluate":
+		msg := &EvaluateRequest{}
+		req, call = msg, func(ctx context.Context) (interface{}, error) { return h.svc.Evaluate(ctx, msg) }
+	case "Translate":
+		msg := &TranslateRequest{}
+		req, call = msg, func(ctx context.Context) (interface{}, error) { return h.svc.Translate(ctx, msg) }
+	default:
+		writeError(w, &Error{Code: CodeUnimplemented, Message: fmt.Sprintf("unknown method %s", method)})
+		return
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	// the numbers of the datums keep their precision
+	decoder.UseNumber()
+	if err := decoder.Decode(req); err != nil && err != io.EOF {
+		writeError(w, &Error{Code: CodeInvalidArgument, Message: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	resp, err := call(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Code: CodeInternal, Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(e.Code))
+	_ = json.NewEncoder(w).Encode(e)
+}
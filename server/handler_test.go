@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(NewService(bexpr.WithMaxLiteralLength(16)))
+
+	type testCase struct {
+		method      string
+		path        string
+		contentType string
+		body        string
+		status      int
+		response    string
+	}
+
+	tests := map[string]testCase{
+		"parse": {
+			path:     "Parse",
+			body:     `{"expression": "Port > 8000"}`,
+			response: `{"ast":{"match":{"operator":"MATCH_OPERATOR_HIGHER","left":{"selector":{"type":"SELECTOR_TYPE_BEXPR","path":["Port"]}},"right":{"literal":{"type":"LITERAL_TYPE_INT","raw":"8000"}}}}}`,
+		},
+		"parse error": {
+			path:     "Parse",
+			body:     `{"expression": "Port >"}`,
+			status:   http.StatusBadRequest,
+			response: `{"code":"invalid_argument","message":"1:7 (6): no match found, expected: \"$\", \"-\", \"0\", \"[\", \"\\\"\", \"` + "`" + `\", \"false\", \"null\", \"true\", \"undefined\", [ \\t\\r\\n], [1-9] or [a-zA-Z]"}`,
+		},
+		"options": {
+			path:     "Evaluate",
+			body:     `{"expression": "Name == \"a very long literal\""}`,
+			status:   http.StatusBadRequest,
+			response: `{"code":"invalid_argument","message":"1:9 (8): rule \"string\": String literal longer than 16 bytes"}`,
+		},
+		"validate": {
+			path:     "Validate",
+			body:     `{"expression": "Port > 8000", "jsonSchema": "{\"type\": \"object\", \"properties\": {\"Port\": {\"type\": \"string\"}}}"}`,
+			response: `{"error":"Port: operator \"Higher\" cannot be used with values of type string"}`,
+		},
+		"valid": {
+			path:     "Validate",
+			body:     `{"expression": "Port > 8000"}`,
+			response: `{"valid":true}`,
+		},
+		"evaluate": {
+			path:     "Evaluate",
+			body:     `{"expression": "Port > 8000 and Owner == $user", "datum": {"Port": 8080, "Owner": "alice"}, "params": {"user": "alice"}}`,
+			response: `{"result":true}`,
+		},
+		"evaluate false": {
+			path:     "Evaluate",
+			body:     `{"expression": "Port > 8000", "datum": {"Port": 80}}`,
+			response: `{}`,
+		},
+		"large numbers": {
+			path:     "Evaluate",
+			body:     `{"expression": "Id == 9007199254740993", "datum": {"Id": 9007199254740993}}`,
+			response: `{"result":true}`,
+		},
+		"evaluate error": {
+			path:     "Evaluate",
+			body:     `{"expression": "Port > 8000", "datum": {"Port": "80"}}`,
+			status:   http.StatusBadRequest,
+			response: `{"code":"invalid_argument","message":"1:1 (0): Port \u003e 8000: operator \"Higher\" cannot be used with values of type string"}`,
+		},
+		"translate": {
+			path:     "Translate",
+			body:     `{"expression": "app == \"web\" and tier != \"gold\"", "target": "TARGET_KUBERNETES_LABEL_SELECTOR"}`,
+			response: `{"translation":"app=web,tier!=gold"}`,
+		},
+		"translate elasticsearch": {
+			path:     "Translate",
+			body:     `{"expression": "app == \"web\"", "target": "TARGET_ELASTICSEARCH"}`,
+			response: `{"translation":"{\"term\":{\"app\":\"web\"}}"}`,
+		},
+		"translate bexpr": {
+			path:     "Translate",
+			body:     `{"expression": "app==\"web\"", "target": "TARGET_BEXPR"}`,
+			response: `{"translation":"app == \"web\""}`,
+		},
+		"unsupported target": {
+			path:     "Translate",
+			body:     `{"expression": "app == \"web\""}`,
+			status:   http.StatusBadRequest,
+			response: `{"code":"invalid_argument","message":"unsupported target \"\""}`,
+		},
+		"invalid request": {
+			path:     "Evaluate",
+			body:     `{"expression": 1}`,
+			status:   http.StatusBadRequest,
+			response: `{"code":"invalid_argument","message":"invalid request: json: cannot unmarshal number into Go struct field EvaluateRequest.expression of type string"}`,
+		},
+		"unknown method": {
+			path:     "Explain",
+			body:     `{}`,
+			status:   http.StatusNotFound,
+			response: `{"code":"unimplemented","message":"unknown method Explain"}`,
+		},
+		"content type": {
+			path:        "Evaluate",
+			contentType: "application/proto",
+			status:      http.StatusUnsupportedMediaType,
+			response:    "the messages are encoded in JSON",
+		},
+		"get": {
+			method:   http.MethodGet,
+			path:     "Evaluate",
+			status:   http.StatusMethodNotAllowed,
+			response: "the methods are called with POST",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			method, contentType, status := tcase.method, tcase.contentType, tcase.status
+			if method == "" {
+				method = http.MethodPost
+			}
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			if status == 0 {
+				status = http.StatusOK
+			}
+			req := httptest.NewRequest(method, ServicePath+tcase.path, strings.NewReader(tcase.body))
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, status, rec.Code)
+			require.Equal(t, tcase.response, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
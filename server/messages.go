@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package server
+
+// The messages of bexpr.proto, encoded in JSON the way protojson encodes
+// them: fields in lowerCamelCase, enums by name and google.protobuf.Value as
+// any JSON value.
+
+type ParseRequest struct {
+	Expression string `json:"expression"`
+}
+
+type ParseResponse struct {
+	AST *Expression `json:"ast,omitempty"`
+}
+
+type ValidateRequest struct {
+	Expression string `json:"expression"`
+	// JSONSchema is the JSON Schema of the datums, validating the selectors
+	// and operators of the expression when set
+	JSONSchema string `json:"jsonSchema,omitempty"`
+}
+
+type ValidateResponse struct {
+	Valid bool `json:"valid,omitempty"`
+	// Error is the reason the expression is not valid
+	Error string `json:"error,omitempty"`
+}
+
+type EvaluateRequest struct {
+	Expression string      `json:"expression"`
+	Datum      interface{} `json:"datum"`
+	// Params are the values of the parameters of the expression, such as
+	// $user
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type EvaluateResponse struct {
+	Result bool `json:"result,omitempty"`
+}
+
+type TranslateRequest struct {
+	Expression string `json:"expression"`
+	// Target is the name of a value of the Target enum, such as TARGET_CEL
+	Target string `json:"target,omitempty"`
+}
+
+type TranslateResponse struct {
+	// Translation is the translated expression, the Elasticsearch queries
+	// being encoded in JSON
+	Translation string `json:"translation,omitempty"`
+}
+
+// The targets of TranslateRequest
+const (
+	TargetUnspecified             = "TARGET_UNSPECIFIED"
+	TargetBexpr                   = "TARGET_BEXPR"
+	TargetCEL                     = "TARGET_CEL"
+	TargetElasticsearch           = "TARGET_ELASTICSEARCH"
+	TargetLDAP                    = "TARGET_LDAP"
+	TargetKubernetesLabelSelector = "TARGET_KUBERNETES_LABEL_SELECTOR"
+	TargetKubernetesFieldSelector = "TARGET_KUBERNETES_FIELD_SELECTOR"
+)
+
+// Expression is a node of the AST of an expression, only one of its fields
+// being set
+type Expression struct {
+	Unary  *UnaryExpression  `json:"unary,omitempty"`
+	Binary *BinaryExpression `json:"binary,omitempty"`
+	Match  *MatchExpression  `json:"match,omitempty"`
+	Let    *LetExpression    `json:"let,omitempty"`
+	// Value is a value used as a boolean, such as a selector
+	Value *Value `json:"value,omitempty"`
+}
+
+type UnaryExpression struct {
+	Operator string      `json:"operator"`
+	Operand  *Expression `json:"operand"`
+}
+
+type BinaryExpression struct {
+	Operator string      `json:"operator"`
+	Left     *Expression `json:"left"`
+	Right    *Expression `json:"right"`
+}
+
+type MatchExpression struct {
+	Operator string `json:"operator"`
+	// Left is the collection of "in" and "contains", Right being the element
+	Left *Value `json:"left"`
+	// Right is nil for the unary operators, such as "is empty"
+	Right *Value `json:"right,omitempty"`
+}
+
+type LetExpression struct {
+	Name  string      `json:"name"`
+	Value *Value      `json:"value"`
+	Body  *Expression `json:"body"`
+}
+
+// Value is an operand of a match expression, only one of its fields being set
+type Value struct {
+	Selector *Selector `json:"selector,omitempty"`
+	Literal  *Literal  `json:"literal,omitempty"`
+	// Param is the name of a parameter, such as user for $user
+	Param string          `json:"param,omitempty"`
+	Math  *MathExpression `json:"math,omitempty"`
+}
+
+type Selector struct {
+	Type string   `json:"type"`
+	Path []string `json:"path"`
+	// Anchored is set on the selectors anchored at the root of the datum,
+	// such as $.Meta.env
+	Anchored bool `json:"anchored,omitempty"`
+}
+
+type Literal struct {
+	Type string `json:"type"`
+	// Raw is the literal as written in the expression, unquoted
+	Raw string `json:"raw,omitempty"`
+}
+
+type MathExpression struct {
+	Operator string `json:"operator"`
+	Left     *Value `json:"left"`
+	Right    *Value `json:"right"`
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package server exposes the parsing, validation, evaluation and translation
+// of expressions as the FilterService of bexpr.proto, so that services
+// written in other languages can delegate them to a sidecar.
+//
+// Service implements the methods of the service on the Go messages of this
+// package, which encode to JSON the way protojson encodes the messages of
+// bexpr.proto. NewHandler serves them over HTTP with the unary JSON protocol
+// of Connect, as POST /bexpr.v1.FilterService/Evaluate and so on, which the
+// Connect clients generated from bexpr.proto call, and any HTTP client can:
+//
+//	curl -H 'Content-Type: application/json' \
+//	    -d '{"expression": "Port > 8000", "datum": {"Port": 8080}}' \
+//	    http://localhost:8080/bexpr.v1.FilterService/Evaluate
+//
+// The module does not depend on gRPC: serving the gRPC protocol takes the
+// code generated from bexpr.proto by protoc-gen-go-grpc, whose methods
+// convert the messages with protojson and forward them to the Service.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	bexpr "github.com/gterranova/go-bexpr"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Service implements the FilterService of bexpr.proto
+type Service struct {
+	opts []bexpr.Option
+}
+
+// NewService creates a service creating the evaluators of the requests with
+// the options, such as bexpr.WithMaxExpressions to limit the expressions of
+// untrusted clients
+func NewService(opts ...bexpr.Option) *Service {
+	return &Service{opts: opts}
+}
+
+// Parse returns the AST of the expression as written, in the bexpr dialect:
+// the macros and the let expressions are kept.
+func (s *Service) Parse(ctx context.Context, req *ParseRequest) (*ParseResponse, error) {
+	if _, err := s.evaluator(req.Expression); err != nil {
+		return nil, err
+	}
+	parsed, err := grammar.Parse("", []byte(req.Expression))
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidArgument, Message: err.Error()}
+	}
+	ast, err := newExpression(parsed.(grammar.Expression))
+	if err != nil {
+		return nil, &Error{Code: CodeInternal, Message: err.Error()}
+	}
+	return &ParseResponse{AST: ast}, nil
+}
+
+// Validate checks the syntax of the expression and, given a JSON Schema, its
+// selectors and operators. Invalid expressions are reported in the response
+// rather than as errors.
+func (s *Service) Validate(ctx context.Context, req *ValidateRequest) (*ValidateResponse, error) {
+	opts := s.opts
+	if req.JSONSchema != "" {
+		schema, err := bexpr.JSONSchema([]byte(req.JSONSchema))
+		if err != nil {
+			return nil, &Error{Code: CodeInvalidArgument, Message: fmt.Sprintf("invalid JSON Schema: %v", err)}
+		}
+		opts = append(opts[:len(opts):len(opts)], bexpr.WithSchema(schema))
+	}
+	if _, err := bexpr.CreateEvaluator(req.Expression, opts...); err != nil {
+		return &ValidateResponse{Error: err.Error()}, nil
+	}
+	return &ValidateResponse{Valid: true}, nil
+}
+
+// Evaluate evaluates the expression against the datum
+func (s *Service) Evaluate(ctx context.Context, req *EvaluateRequest) (*EvaluateResponse, error) {
+	eval, err := s.evaluator(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	result, err := eval.EvaluateContext(ctx, req.Datum, bexpr.WithParams(req.Params))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, &Error{Code: CodeCanceled, Message: err.Error()}
+		}
+		return nil, &Error{Code: CodeInvalidArgument, Message: err.Error()}
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return nil, &Error{Code: CodeInvalidArgument, Message: fmt.Sprintf("the expression evaluated to %v rather than a boolean", result)}
+	}
+	return &EvaluateResponse{Result: matched}, nil
+}
+
+// Translate translates the expression into the query language of the target
+func (s *Service) Translate(ctx context.Context, req *TranslateRequest) (*TranslateResponse, error) {
+	eval, err := s.evaluator(req.Expression)
+	if err != nil {
+		return nil, err
+	}
+	var translation string
+	switch req.Target {
+	case TargetBexpr:
+		var b strings.Builder
+		_, err = eval.WriteTo(&b)
+		translation = b.String()
+	case TargetCEL:
+		translation, err = eval.CELExpression()
+	case TargetElasticsearch:
+		var query map[string]interface{}
+		if query, err = eval.ElasticsearchQuery(); err == nil {
+			var encoded []byte
+			encoded, err = json.Marshal(query)
+			translation = string(encoded)
+		}
+	case TargetLDAP:
+		translation, err = eval.LDAPFilter()
+	case TargetKubernetesLabelSelector:
+		translation, err = eval.KubernetesLabelSelector()
+	case TargetKubernetesFieldSelector:
+		translation, err = eval.KubernetesFieldSelector()
+	default:
+		return nil, &Error{Code: CodeInvalidArgument, Message: fmt.Sprintf("unsupported target %q", req.Target)}
+	}
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidArgument, Message: err.Error()}
+	}
+	return &TranslateResponse{Translation: translation}, nil
+}
+
+func (s *Service) evaluator(expression string) (*bexpr.Evaluator, error) {
+	eval, err := bexpr.CreateEvaluator(expression, s.opts...)
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidArgument, Message: err.Error()}
+	}
+	return eval, nil
+}
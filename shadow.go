@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Divergence reports a datum on which the candidate expression evaluated in
+// shadow of the active one, see WithShadow, disagreed with it.
+type Divergence struct {
+	// Candidate is the candidate expression
+	Candidate string
+	// Datum is the datum evaluated
+	Datum interface{}
+	// Active and ActiveErr are the outcome of the active expression, the
+	// one Evaluate returned.
+	Active    bool
+	ActiveErr error
+	// Shadow and ShadowErr are the outcome of the candidate expression
+	Shadow    bool
+	ShadowErr error
+}
+
+type shadowOptions struct {
+	candidate string
+	fn        func(*Divergence)
+}
+
+// shadow evaluates a candidate expression in shadow of the active one
+type shadow struct {
+	candidate string
+	eval      *Evaluator
+	fn        func(*Divergence)
+}
+
+// WithShadow evaluates the candidate expression alongside the expression of
+// the evaluator, with the same options, to roll a new version of a filter out
+// safely. The outcome of the candidate never affects the one returned by
+// Evaluate: when they diverge, fn is called with both before Evaluate returns.
+// The outcomes diverge when their results differ or only one of them failed.
+// Evaluations given up on because the context is done are not compared. The
+// candidate is parsed when the evaluator is created, a syntax error failing
+// the creation.
+func WithShadow(candidate string, fn func(*Divergence)) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.withShadow = &shadowOptions{candidate: candidate, fn: fn}
+		}
+	}
+}
+
+func newShadow(opts *shadowOptions, evalOpts []Option) (*shadow, error) {
+	// the candidate is neither traced, counted under the name of the rule,
+	// recorded nor observed, its evaluations are not spans of their own and
+	// they are not logged
+	evalOpts = append(evalOpts[:len(evalOpts):len(evalOpts)], func(o *options) {
+		o.withShadow = nil
+		o.withTrace = nil
+		o.withStats = nil
+		o.withRecorder = nil
+		o.withObserver = observer{}
+		o.withTracer = nil
+		o.withLogger = nil
+	})
+	eval, err := CreateEvaluator(opts.candidate, evalOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the shadow evaluator: %w", err)
+	}
+	return &shadow{candidate: opts.candidate, eval: eval, fn: opts.fn}, nil
+}
+
+// compare evaluates the candidate and reports its divergence from the outcome
+// of the active expression
+func (s *shadow) compare(ctx context.Context, datum interface{}, result interface{}, err error, opts ...Option) {
+	if ctx.Err() != nil {
+		return
+	}
+	shadowResult, shadowErr := s.eval.evaluate(ctx, datum, opts...)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if d := newDivergence(s.candidate, datum, result, err, shadowResult, shadowErr); d != nil {
+		s.fn(d)
+	}
+}
+
+// newDivergence returns the divergence of the outcomes, or nil when they
+// agree: their results are the same or both failed
+func newDivergence(candidate string, datum interface{}, result interface{}, err error, shadowResult interface{}, shadowErr error) *Divergence {
+	active, _ := result.(bool)
+	shadow, _ := shadowResult.(bool)
+	if (err == nil) == (shadowErr == nil) && (err != nil || active == shadow) {
+		return nil
+	}
+	return &Divergence{
+		Candidate: candidate,
+		Datum:     datum,
+		Active:    active && err == nil,
+		ActiveErr: err,
+		Shadow:    shadow && shadowErr == nil,
+		ShadowErr: shadowErr,
+	}
+}
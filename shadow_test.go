@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithShadow(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		active     string
+		candidate  string
+		datum      interface{}
+		opts       []Option
+		result     bool
+		err        string
+		divergence *Divergence
+	}
+
+	datum := map[string]interface{}{"Port": 8080, "Env": "prod"}
+
+	tests := map[string]testCase{
+		"agree": {
+			active:    `Port > 1024`,
+			candidate: `Port >= 1025`,
+			datum:     datum,
+			result:    true,
+		},
+		"diverge": {
+			active:     `Port > 1024`,
+			candidate:  `Port > 8080`,
+			datum:      datum,
+			result:     true,
+			divergence: &Divergence{Candidate: `Port > 8080`, Datum: datum, Active: true},
+		},
+		"candidate fails": {
+			active:    `Env == "prod"`,
+			candidate: `Region == "eu"`,
+			datum:     datum,
+			result:    true,
+			divergence: &Divergence{
+				Candidate: `Region == "eu"`,
+				Datum:     datum,
+				Active:    true,
+				ShadowErr: errMissingRegion,
+			},
+		},
+		"active fails": {
+			active:    `Region == "eu"`,
+			candidate: `Env == "prod"`,
+			datum:     datum,
+			err:       errMissingRegion.Error(),
+			divergence: &Divergence{
+				Candidate: `Env == "prod"`,
+				Datum:     datum,
+				ActiveErr: errMissingRegion,
+				Shadow:    true,
+			},
+		},
+		"both fail": {
+			active:    `Region == "eu"`,
+			candidate: `Zone == "a"`,
+			datum:     datum,
+			err:       errMissingRegion.Error(),
+		},
+		"evaluator options": {
+			active:    `Region == "eu"`,
+			candidate: `Region != "us"`,
+			datum:     datum,
+			opts:      []Option{WithUnknownValue("eu")},
+			result:    true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var divergences []*Divergence
+			opts := append(tcase.opts, WithShadow(tcase.candidate, func(d *Divergence) {
+				divergences = append(divergences, d)
+			}))
+			eval, err := CreateEvaluator(tcase.active, opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(tcase.datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tcase.result, result)
+			}
+
+			if tcase.divergence == nil {
+				require.Empty(t, divergences)
+				return
+			}
+			require.Len(t, divergences, 1)
+			d := divergences[0]
+			require.Equal(t, tcase.divergence.Candidate, d.Candidate)
+			require.Equal(t, tcase.divergence.Datum, d.Datum)
+			require.Equal(t, tcase.divergence.Active, d.Active)
+			require.Equal(t, tcase.divergence.Shadow, d.Shadow)
+			requireSameError(t, tcase.divergence.ActiveErr, d.ActiveErr)
+			requireSameError(t, tcase.divergence.ShadowErr, d.ShadowErr)
+		})
+	}
+}
+
+var errMissingRegion = errors.New(`1:1 (0): Region == "eu": error finding value in datum: /Region at part 0: couldn't find key "Region"`)
+
+func requireSameError(t *testing.T, expected, actual error) {
+	t.Helper()
+	if expected == nil {
+		require.NoError(t, actual)
+		return
+	}
+	require.EqualError(t, actual, expected.Error())
+}
+
+func TestWithShadow_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := CreateEvaluator(`Port > 1024`, WithShadow(`Port >`, func(*Divergence) {}))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error creating the shadow evaluator")
+
+	// evaluations given up on are not compared
+	called := false
+	eval, err := CreateEvaluator(`Port > 1024`, WithShadow(`Port > 8080`, func(*Divergence) {
+		called = true
+	}))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = eval.EvaluateContext(ctx, map[string]interface{}{"Port": 2048})
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, called)
+}
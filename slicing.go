@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// getRangeValue resolves a selector with ranges, such as Events[0:10].Type:
+// the value found at the parts preceding the first range is sliced, and the
+// rest of the selector is resolved in each element of the range, the elements
+// whose maps it is missing from being skipped, unless WithStrictSelectors is
+// set, and the values of the ranges following the first one being flattened.
+// Without any part following it, a range selects a slice of the elements.
+func getRangeValue(expressionValue *grammar.MatchValue, datum interface{}, opt ...Option) (interface{}, error) {
+	sel := expressionValue.Selector
+	first := sel.Ranges[0]
+	head := &grammar.MatchValue{
+		Type:     grammar.ValueTypeReflect,
+		Selector: grammar.Selector{Type: sel.Type, Path: sel.Path[:first.At], Anchored: sel.Anchored},
+	}
+	// the selectors resolved are built for this lookup, they are not cached
+	opt = append(opt[:len(opt):len(opt)], func(o *options) {
+		o.withFieldCache = nil
+	})
+	val, err := getValue(head, datum, opt...)
+	if err != nil || isUndefined(val) || isNull(val) {
+		return val, err
+	}
+
+	elems := reflect.ValueOf(val)
+	for elems.Kind() == reflect.Interface || elems.Kind() == reflect.Ptr {
+		elems = elems.Elem()
+	}
+	ranges := sel.Ranges
+	for len(ranges) > 0 && ranges[0].At == first.At {
+		if elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array {
+			err := fmt.Errorf("cannot select the range %s of %s: not a slice or an array but a %s", ranges[0], head.Selector, elems.Kind())
+			return &undefined, &UnknownSelectorError{Selector: sel.String(), Err: err}
+		}
+		elems = sliceRange(elems, ranges[0])
+		ranges = ranges[1:]
+	}
+	rest := grammar.Selector{Type: sel.Type, Path: sel.Path[first.At:]}
+	if len(rest.Path) == 0 {
+		return elems.Interface(), nil
+	}
+	for _, r := range ranges {
+		r.At -= first.At
+		rest.Ranges = append(rest.Ranges, r)
+	}
+	sliced := head.Selector
+	sliced.Ranges = sel.Ranges[:len(sel.Ranges)-len(ranges)]
+
+	// the rest of the selector is relative to the elements, which were
+	// resolved and authorized as a whole
+	opts := getOpts(opt...)
+	opt = append(opt, func(o *options) {
+		o.withBindings = nil
+		o.withFieldAccess = nil
+		o.withDefaults = nil
+		o.withUnknown = nil
+	})
+	restValue := &grammar.MatchValue{Type: grammar.ValueTypeReflect, Selector: rest}
+	values := make([]interface{}, 0, elems.Len())
+	for i := 0; i < elems.Len(); i++ {
+		elem := elems.Index(i).Interface()
+		if isNull(elem) {
+			continue
+		}
+		val, err := getValue(restValue, elem, opt...)
+		switch {
+		case err == nil && isUndefined(val):
+			// a key missing from a map of the element
+			continue
+		case isNotFound(err) && !opts.withStrictSelectors && len(rest.Path) == 1 && isMapValue(elem):
+			// a key missing from the element
+			continue
+		case err != nil:
+			var unknown *UnknownSelectorError
+			if errors.As(err, &unknown) {
+				err = &UnknownSelectorError{Selector: sel.String(), Err: fmt.Errorf("element %d of %s: %w", i, sliced, unknown.Err)}
+			}
+			return &undefined, err
+		}
+		if len(rest.Ranges) == 0 {
+			values = append(values, val)
+			continue
+		}
+		// the elements of the ranges of the elements are flattened
+		nested := reflect.ValueOf(val)
+		for j := 0; j < nested.Len(); j++ {
+			values = append(values, nested.Index(j).Interface())
+		}
+	}
+	return values, nil
+}
+
+// isMapValue reports whether the value is a map, or a pointer to a map
+func isMapValue(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Interface || rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	return rv.Kind() == reflect.Map
+}
+
+// sliceRange returns the elements of the slice or array within the range,
+// the bounds being clamped to the elements
+func sliceRange(elems reflect.Value, r grammar.SelectorRange) reflect.Value {
+	n := elems.Len()
+	bound := func(b *int, def int) int {
+		if b == nil {
+			return def
+		}
+		i := *b
+		if i < 0 {
+			i += n
+		}
+		switch {
+		case i < 0:
+			return 0
+		case i > n:
+			return n
+		}
+		return i
+	}
+	low, high := bound(r.Low, 0), bound(r.High, n)
+	if high < low {
+		high = low
+	}
+	if elems.Kind() == reflect.Slice {
+		return elems.Slice(low, high)
+	}
+	// the arrays found are not addressable
+	sliced := reflect.MakeSlice(reflect.SliceOf(elems.Type().Elem()), high-low, high-low)
+	for i := low; i < high; i++ {
+		sliced.Index(i - low).Set(elems.Index(i))
+	}
+	return sliced
+}
+
+// rangeSelectorType returns the type of the values a selector with ranges
+// selects in the schema, checking that the ranges select elements of slices
+// or arrays
+func rangeSelectorType(schema Schema, sel grammar.Selector) (reflect.Type, error) {
+	// the parts following a range are resolved in its first element
+	path := make([]string, 0, len(sel.Path)+len(sel.Ranges))
+	var last int
+	var typ reflect.Type
+	for i, r := range sel.Ranges {
+		if i > 0 && r.At == sel.Ranges[i-1].At {
+			continue
+		}
+		path = append(append(path, sel.Path[last:r.At]...), "0")
+		last = r.At
+		head, err := schema.SelectorType(path[:len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		switch kind := derefType(head).Kind(); kind {
+		case reflect.Interface:
+			return interfaceTyp, nil
+		case reflect.Slice, reflect.Array:
+		default:
+			return nil, fmt.Errorf("cannot select the range %s of %s: not a slice or an array but a %s", r, grammar.Selector{Type: sel.Type, Path: sel.Path[:r.At]}, kind)
+		}
+		if typ == nil {
+			typ = reflect.SliceOf(derefType(head).Elem())
+		}
+	}
+	if _, err := schema.SelectorType(append(path, sel.Path[last:]...)); err != nil {
+		return nil, err
+	}
+	if sel.Ranges[0].At < len(sel.Path) {
+		// the values selected in each element
+		return reflect.TypeOf([]interface{}(nil)), nil
+	}
+	return typ, nil
+}
+
+// rangesKey returns a key of the ranges of a selector, the selectors with the
+// same path and ranges having the same key
+func rangesKey(ranges []grammar.SelectorRange) string {
+	var b strings.Builder
+	for _, r := range ranges {
+		fmt.Fprintf(&b, "\x00%d%s", r.At, r)
+	}
+	return b.String()
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type slicingEvent struct {
+	Type string
+	Tags []string
+	Meta map[string]string
+}
+
+type slicingHistory struct {
+	Name   string
+	Events []slicingEvent
+	Last   [3]int
+	Ptrs   []*slicingEvent
+}
+
+func TestSliceRanges(t *testing.T) {
+	t.Parallel()
+
+	history := slicingHistory{
+		Name: "web",
+		Events: []slicingEvent{
+			{Type: "created", Tags: []string{"a"}},
+			{Type: "updated", Tags: []string{"b", "c"}, Meta: map[string]string{"by": "ops"}},
+			{Type: "deleted"},
+		},
+		Last: [3]int{1, 2, 3},
+		Ptrs: []*slicingEvent{{Type: "created"}, nil},
+	}
+	datum := map[string]interface{}{
+		"Events": []interface{}{
+			map[string]interface{}{"Type": "created", "By": "ops"},
+			map[string]interface{}{"Type": "updated"},
+			nil,
+		},
+		"Name": "web",
+		"Meta": map[string]interface{}{},
+	}
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"in":                {expression: `"updated" in Events[0:2].Type`, result: true},
+		"not in":            {expression: `"deleted" not in Events[0:2].Type`, result: true},
+		"negative bound":    {expression: `"deleted" in Events[-1:].Type and "created" not in Events[-2:].Type`, result: true},
+		"omitted bounds":    {expression: `Events[:].Type contains "deleted" and Events[:1].Type contains "created"`, result: true},
+		"clamped bounds":    {expression: `Events[1:10].Type contains "deleted" and Events[-10:1] is not empty`, result: true},
+		"empty range":       {expression: `Events[2:1] is empty and Events[5:] is empty`, result: true},
+		"elements":          {expression: `Events[1:] is not empty and Last[1:] contains 3 and Last[:1] not contains 3`, result: true},
+		"nested ranges":     {expression: `"c" in Events[1:].Tags[1:] and "a" not in Events[:].Tags[1:]`, result: true},
+		"consecutive":       {expression: `"updated" in Events[1:][:1].Type and "deleted" not in Events[1:][:1].Type`, result: true},
+		"missing skipped":   {expression: `Events[:].Meta.by contains "ops"`, result: true},
+		"anchored":          {expression: `$.Events[0:1].Type contains "created"`, result: true},
+		"let":               {expression: `let recent = Events[-2:] in recent is not empty and "deleted" in recent.1.Type`, result: true},
+		"let reference":     {expression: `let e = Events in e[-1:].Type contains "deleted"`, result: true},
+		"null elements":     {expression: `"created" in Ptrs[:].Type`, result: true},
+		"not a slice":       {expression: `"web" in Name[0:1]`, err: `1:1 (0): Name[0:1] contains "web": error finding value in datum: cannot select the range [0:1] of Name: not a slice or an array but a string`},
+		"missing field":     {expression: `"a" in Events[:].Zone`, err: `1:1 (0): Events[:].Zone contains "a": error finding value in datum: element 0 of Events[:]: /Zone at part 0: couldn't find key: struct field with name "Zone"`},
+		"map elements":      {expression: `"ops" in Events[:].By and Events[1:].By is empty`, datum: datum, result: true},
+		"map strict":        {expression: `"ops" in Events[:].By`, datum: datum, opts: []Option{WithStrictSelectors()}, err: `1:1 (0): Events[:].By contains "ops": error finding value in datum: element 1 of Events[:]: /By at part 0: couldn't find key "By"`},
+		"missing head":      {expression: `Meta.history[0:1] is empty`, datum: datum, result: true},
+		"missing head uses": {expression: `"a" in Meta.history[0:1].Type`, datum: datum, result: false},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			datum := tcase.datum
+			if datum == nil {
+				datum = history
+			}
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestSliceRanges_Format(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`Events[0:10].Type == "a"`:             `Events[0:10].Type == "a"`,
+		`$.Events[ -2 : ]["a b"][:1] is empty`: `$.Events[-2:]["a b"][:1] is empty`,
+		`["in"][1:].x is empty`:                `["in"][1:].x is empty`,
+		`["a b"][1:] is empty`:                 `["a b"][1:] is empty`,
+	}
+
+	for expression, formatted := range tests {
+		expression, formatted := expression, formatted
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(expression)
+			require.NoError(t, err)
+			require.Equal(t, formatted, formatExpression(eval.ast))
+
+			_, err = CreateEvaluator(formatted)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSliceRanges_Selectors(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Events[0:2].Type contains "a" and Events.0.Type == "a" and Events[0:2].Type is not empty`,
+		WithSelectorPrefix("Spec"))
+	require.NoError(t, err)
+	var fields []string
+	for _, sel := range eval.Fields() {
+		fields = append(fields, sel.String())
+	}
+	require.Equal(t, []string{"$.Spec.Events.0.Type", "$.Spec.Events[0:2].Type"}, fields)
+
+	eval, err = CreateEvaluator(`events[-1:].type contains "a"`, WithFieldAliases(map[string]string{"events": "History.events"}))
+	require.NoError(t, err)
+	require.Equal(t, `History.events[-1:].type contains "a"`, formatExpression(eval.ast))
+}
+
+func TestSliceRanges_Schema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"elements":    {expression: `Events[0:2] is not empty and Last[1:] contains 2`},
+		"values":      {expression: `"a" in Events[0:2].Type and "b" in Events[:].Tags[1:]`},
+		"not a slice": {expression: `"a" in Name[0:1]`, err: `error finding value in schema: cannot select the range [0:1] of Name: not a slice or an array but a string`},
+		"missing":     {expression: `"a" in Events[0:1].Zone`, err: `error finding value in schema: /Events/0/Zone at part 2: couldn't find key: struct field with name "Zone"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(slicingHistory{})))
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
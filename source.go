@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import "errors"
+
+// SelectorSource is implemented by datums resolving selectors themselves,
+// such as columnar stores or generated accessors. When the datum implements
+// it, selectors are resolved by calling GetPath instead of using reflection,
+// and hooks set with WithHookFn or WithSelectorHook are not called.
+type SelectorSource interface {
+	// GetPath returns the value found at the selector path. The second
+	// return value is false when there is no value at the path, which is
+	// handled the same way as a missing map key: the operator's
+	// NotPresentDisposition is used unless WithUnknownValue was set.
+	GetPath(path []string) (interface{}, bool, error)
+}
+
+// TypedSelectorSource is a SelectorSource also returning the strings,
+// booleans, integers and floats it holds without boxing them into interfaces,
+// such as the accessors generated by bexpr-gen. Evaluators compare them with
+// the literals of the expression without reflection nor allocations, when
+// they were created without options changing how values are resolved or
+// compared, such as hooks and coercions.
+type TypedSelectorSource interface {
+	SelectorSource
+	// GetTypedPath returns the value found at the selector path, which must
+	// be the value GetPath returns for the path. The second return value is
+	// false when there is no value at the path or when it is not a string, a
+	// boolean, an integer or a float64, GetPath resolving the path then.
+	GetTypedPath(path []string) (TypedValue, bool)
+}
+
+// TypedValue is a value returned by TypedSelectorSource.GetTypedPath
+type TypedValue struct {
+	value fastValue
+}
+
+// TypedString returns the TypedValue of a string
+func TypedString(s string) TypedValue {
+	return TypedValue{value: fastValue{kind: fastString, s: s}}
+}
+
+// TypedBool returns the TypedValue of a boolean
+func TypedBool(b bool) TypedValue {
+	return TypedValue{value: fastValue{kind: fastBool, b: b}}
+}
+
+// TypedInt returns the TypedValue of a signed integer
+func TypedInt(i int64) TypedValue {
+	return TypedValue{value: fastValue{kind: fastInt, i: i}}
+}
+
+// TypedUint returns the TypedValue of an unsigned integer
+func TypedUint(u uint64) TypedValue {
+	return TypedValue{value: fastValue{kind: fastUint, u: u}}
+}
+
+// TypedFloat returns the TypedValue of a float64. The float32 values are not
+// typed values, as they do not compare as the float64 they convert to.
+func TypedFloat(f float64) TypedValue {
+	return TypedValue{value: fastValue{kind: fastFloat, f: f}}
+}
+
+// LookupPath resolves the selector path in the value with reflection, the way
+// the selectors of datums which are not SelectorSources are resolved, for the
+// SelectorSource implementations resolving some values this way, such as the
+// accessors generated by bexpr-gen. The second return value is false for the
+// missing keys of maps. The options set how values are resolved, such as
+// WithTagName.
+func LookupPath(value interface{}, path []string, opts ...Option) (interface{}, bool, error) {
+	val, err := getBoundValue(value, path, opts...)
+	if err != nil {
+		var unknown *UnknownSelectorError
+		if errors.As(err, &unknown) {
+			// the source errors are wrapped when the selector is resolved
+			err = unknown.Err
+		}
+		return nil, false, err
+	}
+	if isUndefined(val) {
+		return nil, false, nil
+	}
+	return val, true, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testColumns is a SelectorSource backed by columns keyed by the dotted
+// selector path
+type testColumns struct {
+	columns map[string][]interface{}
+	row     int
+}
+
+func (c *testColumns) GetPath(path []string) (interface{}, bool, error) {
+	key := strings.Join(path, ".")
+	if key == "broken" {
+		return nil, false, errors.New("column is corrupt")
+	}
+	column, ok := c.columns[key]
+	if !ok || c.row >= len(column) {
+		return nil, false, nil
+	}
+	return column[c.row], true, nil
+}
+
+func TestSelectorSource(t *testing.T) {
+	t.Parallel()
+
+	columns := map[string][]interface{}{
+		"name":        {"web", "db"},
+		"meta.port":   {json.Number("8080"), json.Number("5432")},
+		"meta.weight": {1.5, nil},
+		"tags":        {[]string{"prod"}, []string{}},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		results    []bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"equality": {
+			expression: `name == "web"`,
+			results:    []bool{true, false},
+		},
+		"nested path": {
+			expression: `meta.port > 6000`,
+			results:    []bool{true, false},
+		},
+		"null": {
+			expression: `meta.weight is null`,
+			results:    []bool{false, true},
+		},
+		"collection": {
+			expression: `"prod" in tags`,
+			results:    []bool{true, false},
+		},
+		"missing": {
+			expression: `missing != "x" and missing is empty`,
+			results:    []bool{true, true},
+		},
+		"missing with unknown value": {
+			expression: `missing == "x"`,
+			opts:       []Option{WithUnknownValue("x")},
+			results:    []bool{true, true},
+		},
+		"missing with strict selectors": {
+			expression: `meta.missing != "x"`,
+			opts:       []Option{WithStrictSelectors()},
+			err:        `1:1 (0): meta.missing != "x": error finding value in datum: /meta/missing: couldn't find key`,
+		},
+		"error": {
+			expression: `broken == "x"`,
+			err:        `1:1 (0): broken == "x": error finding value in datum: column is corrupt`,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			for row := 0; row < 2; row++ {
+				result, err := eval.Evaluate(&testColumns{columns: columns, row: row})
+				if tcase.err != "" {
+					require.EqualError(t, err, tcase.err)
+					continue
+				}
+				require.NoError(t, err)
+				require.Equal(t, tcase.results[row], result, "row %d", row)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"strings"
+)
+
+// The nullable types of database/sql, such as sql.NullString, sql.NullInt64,
+// sql.NullTime or sql.Null[T], resolve to the value they hold when valid, and
+// are handled as missing values otherwise, so that rows scanned into structs
+// can be evaluated like the maps of their columns.
+
+// isSQLNullType reports whether the type is a nullable type of database/sql
+func isSQLNullType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() == "database/sql" &&
+		strings.HasPrefix(typ.Name(), "Null") && typ.NumField() == 2 &&
+		typ.Field(1).Name == "Valid" && typ.Field(1).Type.Kind() == reflect.Bool
+}
+
+// sqlNullHookFn resolves the valid nullable values of database/sql to the
+// value they hold, and the others to undefined.
+func sqlNullHookFn(v reflect.Value) reflect.Value {
+	e := reflect.Indirect(hookElem(v))
+	if !e.IsValid() || !isSQLNullType(e.Type()) {
+		return v
+	}
+	if !e.Field(1).Bool() {
+		return reflect.ValueOf(&undefined)
+	}
+	return e.Field(0)
+}
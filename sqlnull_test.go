@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLNull(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name    sql.NullString
+		Nick    sql.NullString
+		Age     sql.NullInt64
+		Score   *sql.NullFloat64
+		Active  sql.NullBool
+		Deleted sql.NullTime
+		Columns map[string]interface{}
+	}
+	datum := row{
+		Name:    sql.NullString{String: "alice", Valid: true},
+		Age:     sql.NullInt64{Int64: 42, Valid: true},
+		Score:   &sql.NullFloat64{Float64: 9.5, Valid: true},
+		Active:  sql.NullBool{Bool: true, Valid: true},
+		Deleted: sql.NullTime{Time: time.Now()},
+		Columns: map[string]interface{}{
+			"email": sql.NullString{String: "alice@example.com", Valid: true},
+			"phone": sql.NullString{},
+		},
+	}
+
+	type testCase struct {
+		expression string
+		opts       []Option
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"string":             {expression: `Name == "alice"`, result: true},
+		"integer":            {expression: `Age > 40`, result: true},
+		"pointer":            {expression: `Score >= 9.5`, result: true},
+		"bool":               {expression: `Active`, result: true},
+		"invalid":            {expression: `Nick == "al"`, result: false},
+		"invalid not equal":  {expression: `Nick != "al"`, result: true},
+		"invalid is empty":   {expression: `Deleted is empty`, result: true},
+		"invalid is missing": {expression: `Nick matches "a.*"`, result: false},
+		"in map":             {expression: `Columns.email endswith "@example.com"`, result: true},
+		"invalid in map":     {expression: `Columns.phone == "555"`, result: false},
+		"unknown value": {
+			expression: `Nick == "unknown"`,
+			opts:       []Option{WithUnknownValue("unknown")},
+			result:     true,
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, tcase.opts...)
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+}
+
+func TestSQLNull_Schema(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name  sql.NullString
+		Cnt   sql.NullInt64
+		Score *sql.NullFloat64
+		Seen  sql.NullTime
+	}
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"string":      {expression: `Name == "x" and Name matches "^x"`},
+		"integer":     {expression: `Cnt > 2`},
+		"pointer":     {expression: `Score >= 9.5`},
+		"unsupported": {expression: `Cnt matches "2"`, err: `Cnt: operator "Matches" cannot be used with values of type int64`},
+		"held fields": {expression: `Name.String == "x"`, err: `error finding value in schema: /Name/String: at part 1, invalid value kind: string`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(TypeSchema(row{})))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Stats counts, for the rules evaluated with WithStats, how often each rule
+// and each of its clauses matched, so that rules can be reordered or retired
+// based on real traffic. Stats is safe for concurrent use and implements
+// expvar.Var, so that it can be published with expvar.Publish.
+type Stats struct {
+	mu    sync.Mutex
+	rules map[string]*ruleStats
+}
+
+// RuleStats are the counters of a rule
+type RuleStats struct {
+	// Evaluations is the number of times the rule was evaluated, Matches
+	// and Errors the number of times it matched and failed.
+	Evaluations uint64
+	Matches     uint64
+	Errors      uint64
+	// Clauses are the counters of the clauses of the rule: its logical
+	// operators, let expressions and match expressions, in the order they
+	// are written.
+	Clauses []ClauseStats
+}
+
+// ClauseStats are the counters of a clause of a rule
+type ClauseStats struct {
+	// Clause is the clause in the bexpr syntax
+	Clause string
+	// Evaluations is the number of times the clause was evaluated, Matches
+	// and Errors the number of times it matched and failed.
+	Evaluations uint64
+	Matches     uint64
+	Errors      uint64
+	// ShortCircuited is the number of evaluations of the rule in which the
+	// clause was skipped, the outcome of a logical operator being known
+	// from its left operand or an error having been encountered.
+	ShortCircuited uint64
+}
+
+type ruleStats struct {
+	ast   grammar.Expression
+	stats RuleStats
+}
+
+// NewStats returns empty statistics
+func NewStats() *Stats {
+	return &Stats{rules: make(map[string]*ruleStats)}
+}
+
+// WithStats counts the evaluations of the expression and of its clauses in
+// stats, under the name of the rule. Each evaluator should be given its own
+// name: the counters of an expression evaluated under the name of another one
+// are not recorded. Counting the clauses records the trace of the evaluation,
+// see WithTrace. Evaluations in the three-valued mode of WithUnknownResult are
+// not counted.
+func WithStats(stats *Stats, rule string) Option {
+	return func(o *options) {
+		o.withStats = stats
+		o.withStatsRule = rule
+	}
+}
+
+// Rule returns a copy of the counters of the rule
+func (s *Stats) Rule(rule string) (RuleStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.rules[rule]
+	if !ok {
+		return RuleStats{}, false
+	}
+	return r.snapshot(), true
+}
+
+// Rules returns a copy of the counters of every rule, by name
+func (s *Stats) Rules() map[string]RuleStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rules := make(map[string]RuleStats, len(s.rules))
+	for name, r := range s.rules {
+		rules[name] = r.snapshot()
+	}
+	return rules
+}
+
+// Reset clears the counters of every rule
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = make(map[string]*ruleStats)
+}
+
+// String renders the counters of every rule as JSON, for expvar
+func (s *Stats) String() string {
+	b, err := json.Marshal(s.Rules())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+// record counts the evaluation of the rule traced
+func (s *Stats) record(rule string, ast grammar.Expression, trace *Trace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rules == nil {
+		s.rules = make(map[string]*ruleStats)
+	}
+	r, ok := s.rules[rule]
+	if !ok {
+		r = &ruleStats{ast: ast}
+		collectClauses(ast, &r.stats.Clauses)
+		s.rules[rule] = r
+	}
+	if r.ast != ast {
+		return
+	}
+
+	r.stats.Evaluations++
+	switch {
+	case trace.Err != nil:
+		r.stats.Errors++
+	case trace.Result:
+		r.stats.Matches++
+	}
+	next := 0
+	r.recordClause(ast, trace, &next)
+}
+
+func (r *ruleStats) snapshot() RuleStats {
+	stats := r.stats
+	stats.Clauses = append([]ClauseStats(nil), r.stats.Clauses...)
+	return stats
+}
+
+// recordClause counts the evaluation of the clause and of its operands, in the
+// order collectClauses lists them. trace is nil for skipped clauses.
+func (r *ruleStats) recordClause(ast grammar.Expression, trace *Trace, next *int) {
+	clause := &r.stats.Clauses[*next]
+	*next++
+	switch {
+	case trace == nil:
+		clause.ShortCircuited++
+	case trace.Err != nil:
+		clause.Evaluations++
+		clause.Errors++
+	case trace.Result:
+		clause.Evaluations++
+		clause.Matches++
+	default:
+		clause.Evaluations++
+	}
+
+	child := func(i int) *Trace {
+		if trace == nil || i >= len(trace.Children) {
+			return nil
+		}
+		return trace.Children[i]
+	}
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		r.recordClause(node.Operand, child(0), next)
+	case *grammar.BinaryExpression:
+		r.recordClause(node.Left, child(0), next)
+		r.recordClause(node.Right, child(1), next)
+	case *grammar.LetExpression:
+		r.recordClause(node.Body, child(0), next)
+	}
+}
+
+// collectClauses lists the clauses of the expression in the order they are
+// written
+func collectClauses(ast grammar.Expression, clauses *[]ClauseStats) {
+	*clauses = append(*clauses, ClauseStats{Clause: formatExpression(ast)})
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		collectClauses(node.Operand, clauses)
+	case *grammar.BinaryExpression:
+		collectClauses(node.Left, clauses)
+		collectClauses(node.Right, clauses)
+	case *grammar.LetExpression:
+		collectClauses(node.Body, clauses)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	stats := NewStats()
+	eval, err := CreateEvaluator(`Env == "prod" and (Port > 8000 or Role == "admin")`, WithStats(stats, "admin-or-high-port"))
+	require.NoError(t, err)
+
+	data := []interface{}{
+		map[string]interface{}{"Env": "prod", "Port": 8080, "Role": "user"},
+		map[string]interface{}{"Env": "prod", "Port": 80, "Role": "admin"},
+		map[string]interface{}{"Env": "prod", "Port": 80, "Role": "user"},
+		map[string]interface{}{"Env": "dev", "Port": 8080, "Role": "admin"},
+		map[string]interface{}{"Env": "prod", "Port": "http", "Role": "admin"},
+	}
+	for _, datum := range data {
+		_, _ = eval.Evaluate(datum)
+	}
+
+	rule, ok := stats.Rule("admin-or-high-port")
+	require.True(t, ok)
+	require.Equal(t, RuleStats{
+		Evaluations: 5,
+		Matches:     2,
+		Errors:      1,
+		Clauses: []ClauseStats{
+			{Clause: `Env == "prod" and (Port > 8000 or Role == "admin")`, Evaluations: 5, Matches: 2, Errors: 1},
+			{Clause: `Env == "prod"`, Evaluations: 5, Matches: 4},
+			{Clause: `Port > 8000 or Role == "admin"`, Evaluations: 4, Matches: 2, Errors: 1, ShortCircuited: 1},
+			{Clause: `Port > 8000`, Evaluations: 4, Matches: 1, Errors: 1, ShortCircuited: 1},
+			{Clause: `Role == "admin"`, Evaluations: 2, Matches: 1, ShortCircuited: 3},
+		},
+	}, rule)
+
+	_, ok = stats.Rule("other")
+	require.False(t, ok)
+
+	// another expression under the same name is not counted
+	other, err := CreateEvaluator(`Env == "dev"`, WithStats(stats, "admin-or-high-port"))
+	require.NoError(t, err)
+	_, err = other.Evaluate(data[0])
+	require.NoError(t, err)
+	rule, _ = stats.Rule("admin-or-high-port")
+	require.EqualValues(t, 5, rule.Evaluations)
+
+	var rules map[string]RuleStats
+	require.NoError(t, json.Unmarshal([]byte(stats.String()), &rules))
+	require.Equal(t, stats.Rules(), rules)
+
+	stats.Reset()
+	require.Empty(t, stats.Rules())
+}
+
+func TestStats_Trace(t *testing.T) {
+	t.Parallel()
+
+	stats := NewStats()
+	var trace *Trace
+	eval, err := CreateEvaluator(`let p = Port in not (p < 1024)`, WithStats(stats, "unprivileged"), WithTrace(func(tr *Trace) {
+		trace = tr
+	}))
+	require.NoError(t, err)
+
+	result, err := eval.Evaluate(map[string]interface{}{"Port": 8080})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+	require.NotNil(t, trace)
+
+	rule, ok := stats.Rule("unprivileged")
+	require.True(t, ok)
+	require.Equal(t, []ClauseStats{
+		{Clause: `let p = Port in not p < 1024`, Evaluations: 1, Matches: 1},
+		{Clause: `not p < 1024`, Evaluations: 1, Matches: 1},
+		{Clause: `p < 1024`, Evaluations: 1},
+	}, rule.Clauses)
+}
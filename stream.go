@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// FilterChannel evaluates the expression against the items received from the
+// channel, and sends the items which matched to the returned channel. The
+// items are sent unbuffered, so that a slow consumer slows the producer down.
+// Filtering stops once the input channel is closed, at the first error or
+// once the context is done: the returned channel is then closed, and the
+// error, if any, is sent to the error channel before it is closed too. The
+// error channel is only to be read once the items channel is closed.
+func (eval *Evaluator) FilterChannel(ctx context.Context, in <-chan interface{}) (<-chan interface{}, <-chan error) {
+	return eval.filterStream(ctx, func() (interface{}, bool, error) {
+		select {
+		case item, ok := <-in:
+			return item, ok, nil
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	})
+}
+
+// FilterDecoder filters the JSON values decoded from the decoder like
+// FilterChannel, until the decoder has no more values. Decoding a stream of
+// values, such as newline delimited JSON, filters each of them. Decoding the
+// elements of an array filters them once the opening bracket has been read
+// with the Token method of the decoder. The context is checked between
+// values, a value being decoded running to completion.
+func (eval *Evaluator) FilterDecoder(ctx context.Context, dec *json.Decoder) (<-chan interface{}, <-chan error) {
+	n := 0
+	return eval.filterStream(ctx, func() (interface{}, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		if !dec.More() {
+			return nil, false, nil
+		}
+		var item interface{}
+		if err := dec.Decode(&item); err != nil {
+			return nil, false, fmt.Errorf("error decoding item %d: %w", n, err)
+		}
+		n++
+		return item, true, nil
+	})
+}
+
+// filterStream sends the items returned by next which matched, until it
+// returns false or an error
+func (eval *Evaluator) filterStream(ctx context.Context, next func() (interface{}, bool, error)) (<-chan interface{}, <-chan error) {
+	out := make(chan interface{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(out)
+		for i := 0; ; i++ {
+			item, ok, err := next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !ok {
+				return
+			}
+			matched, err := eval.evaluateItem(ctx, item)
+			if err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					err = ctxErr
+				} else {
+					err = fmt.Errorf("error evaluating item %d: %w", i, err)
+				}
+				errs <- err
+				return
+			}
+			if !matched {
+				continue
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// evaluateItem evaluates the expression against an item of a stream
+func (eval *Evaluator) evaluateItem(ctx context.Context, item interface{}) (bool, error) {
+	result, err := eval.EvaluateContext(ctx, item)
+	if err != nil {
+		return false, err
+	}
+	matched, _ := CoerceBool(result)
+	return matched, nil
+}
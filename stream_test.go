@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// collect reads the items of a filtered stream, and then its error
+func collect(out <-chan interface{}, errs <-chan error) ([]interface{}, error) {
+	var items []interface{}
+	for item := range out {
+		items = append(items, item)
+	}
+	return items, <-errs
+}
+
+func TestFilterChannel(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Port > 80`)
+	require.NoError(t, err)
+
+	in := make(chan interface{})
+	go func() {
+		defer close(in)
+		for port := 79; port < 83; port++ {
+			in <- map[string]int{"Port": port}
+		}
+	}()
+	items, err := collect(eval.FilterChannel(context.Background(), in))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{map[string]int{"Port": 81}, map[string]int{"Port": 82}}, items)
+
+	in = make(chan interface{}, 3)
+	in <- map[string]int{"Port": 81}
+	in <- map[string]string{"Port": "81"}
+	in <- map[string]int{"Port": 82}
+	close(in)
+	items, err = collect(eval.FilterChannel(context.Background(), in))
+	require.ErrorIs(t, err, ErrUnsupportedOperator)
+	require.True(t, strings.HasPrefix(err.Error(), "error evaluating item 1: "), err.Error())
+	require.Equal(t, []interface{}{map[string]int{"Port": 81}}, items)
+}
+
+func TestFilterChannel_Context(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Port > 80`)
+	require.NoError(t, err)
+
+	// the consumer stops reading: the producer is not blocked forever
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan interface{}, 2)
+	in <- map[string]int{"Port": 81}
+	in <- map[string]int{"Port": 82}
+	out, errs := eval.FilterChannel(ctx, in)
+	require.Equal(t, map[string]int{"Port": 81}, <-out)
+	cancel()
+	_, err = collect(out, errs)
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestFilterDecoder(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator(`Meta.env == "prod"`)
+	require.NoError(t, err)
+
+	stream := `{"Meta": {"env": "prod"}, "Name": "a"}
+{"Meta": {"env": "dev"}, "Name": "b"}
+{"Meta": {"env": "prod"}, "Name": "c"}`
+	items, err := collect(eval.FilterDecoder(context.Background(), json.NewDecoder(strings.NewReader(stream))))
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	require.Equal(t, "c", items[1].(map[string]interface{})["Name"])
+
+	dec := json.NewDecoder(strings.NewReader(`[{"Meta": {"env": "prod"}}, {"Meta": {"env": "dev"}}]`))
+	_, err = dec.Token()
+	require.NoError(t, err)
+	items, err = collect(eval.FilterDecoder(context.Background(), dec))
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{map[string]interface{}{"Meta": map[string]interface{}{"env": "prod"}}}, items)
+
+	items, err = collect(eval.FilterDecoder(context.Background(), json.NewDecoder(strings.NewReader(`{"Meta": {"env": "prod"}} {"Meta": `))))
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "error decoding item 1: "), err.Error())
+	require.Len(t, items, 1)
+}
@@ -0,0 +1,288 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Subexpression is a subexpression repeated across a corpus of expressions
+type Subexpression struct {
+	// Expression is the subexpression in the bexpr syntax
+	Expression string
+	// Occurrences is the number of times it occurs in the corpus
+	Occurrences int
+	// Expressions are the indexes of the expressions it occurs in, in
+	// ascending order
+	Expressions []int
+}
+
+// subexprCandidate accumulates the occurrences of a subexpression
+type subexprCandidate struct {
+	ast         grammar.Expression
+	size        int
+	occurrences int
+	expressions map[int]struct{}
+}
+
+// FindCommonSubexpressions finds the subexpressions occurring at least
+// minOccurrences times, and at least twice, across the expressions, as
+// candidates for macros, see WithMacros. The subexpressions considered are
+// the match expressions, the negations and the chains of "and" and "or" made
+// of consecutive operands of a longer chain, such as the guards shared by
+// `Tenant == "acme" and Env == "prod" and Role == "admin"` and
+// `Tenant == "acme" and Env == "prod" and Port > 8000`. The bodies of let
+// expressions are not considered. Subexpressions only occurring within a
+// larger one are not reported. The most frequent subexpressions come first,
+// then the largest ones.
+func FindCommonSubexpressions(expressions []string, minOccurrences int) ([]Subexpression, error) {
+	asts, err := parseCorpus(expressions)
+	if err != nil {
+		return nil, err
+	}
+	var result []Subexpression
+	for _, key := range commonSubexpressions(asts, minOccurrences) {
+		cand := key.candidate
+		sub := Subexpression{Expression: key.expression, Occurrences: cand.occurrences}
+		for idx := range cand.expressions {
+			sub.Expressions = append(sub.Expressions, idx)
+		}
+		sort.Ints(sub.Expressions)
+		result = append(result, sub)
+	}
+	return result, nil
+}
+
+// ExtractMacros replaces the common subexpressions of the expressions, see
+// FindCommonSubexpressions, by references to macros. It returns the macros,
+// named macro1, macro2 and so on, and the rewritten expressions, which
+// evaluate the same way as the original ones when created with WithMacros.
+// The most frequent subexpressions are extracted first, and the macros can
+// reference each other.
+func ExtractMacros(expressions []string, minOccurrences int) (map[string]string, []string, error) {
+	asts, err := parseCorpus(expressions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// macro names must not shadow selectors
+	taken := make(map[string]bool)
+	for _, ast := range asts {
+		for _, sel := range collectSelectors(grammar.InlineLets(ast)) {
+			taken[sel.Path[0]] = true
+		}
+	}
+
+	macros := make(map[string]string)
+	for {
+		common := commonSubexpressions(asts, minOccurrences)
+		if len(common) == 0 {
+			break
+		}
+
+		var name string
+		for n := len(macros) + 1; name == "" || taken[name]; n++ {
+			name = fmt.Sprintf("macro%d", n)
+		}
+		ref := &grammar.ExpressionValue{Left: &grammar.MatchValue{
+			Type:     grammar.ValueTypeReflect,
+			Selector: grammar.Selector{Type: grammar.SelectorTypeBexpr, Path: []string{name}},
+		}}
+
+		replaced := 0
+		rewritten := make([]grammar.Expression, len(asts))
+		for i, ast := range asts {
+			rewritten[i] = replaceSubexpression(ast, common[0].expression, ref, &replaced)
+		}
+		if replaced < 2 {
+			// overlapping occurrences only
+			break
+		}
+		macros[name] = common[0].expression
+		taken[name] = true
+		asts = rewritten
+	}
+
+	result := make([]string, len(asts))
+	for i, ast := range asts {
+		result[i] = formatExpression(ast)
+	}
+	return macros, result, nil
+}
+
+func parseCorpus(expressions []string) ([]grammar.Expression, error) {
+	asts := make([]grammar.Expression, len(expressions))
+	for i, expression := range expressions {
+		ast, err := grammar.Parse("", []byte(expression))
+		if err != nil {
+			return nil, fmt.Errorf("expression %d: %w", i, err)
+		}
+		asts[i] = ast.(grammar.Expression)
+	}
+	return asts, nil
+}
+
+type keyedCandidate struct {
+	expression string
+	candidate  *subexprCandidate
+}
+
+// commonSubexpressions returns the subexpressions occurring at least
+// minOccurrences times which do not only occur within a larger one, in the
+// order FindCommonSubexpressions reports them.
+func commonSubexpressions(asts []grammar.Expression, minOccurrences int) []keyedCandidate {
+	if minOccurrences < 2 {
+		minOccurrences = 2
+	}
+	candidates := make(map[string]*subexprCandidate)
+	for i, ast := range asts {
+		collectSubexpressions(ast, i, candidates)
+	}
+
+	var common []keyedCandidate
+	for expression, cand := range candidates {
+		if cand.occurrences >= minOccurrences {
+			common = append(common, keyedCandidate{expression: expression, candidate: cand})
+		}
+	}
+
+	var result []keyedCandidate
+	for _, c := range common {
+		subsumed := false
+		for _, other := range common {
+			if other.candidate.size > c.candidate.size && other.candidate.occurrences == c.candidate.occurrences {
+				inner := make(map[string]*subexprCandidate)
+				collectSubexpressions(other.candidate.ast, 0, inner)
+				if _, ok := inner[c.expression]; ok {
+					subsumed = true
+					break
+				}
+			}
+		}
+		if !subsumed {
+			result = append(result, c)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].candidate, result[j].candidate
+		if a.occurrences != b.occurrences {
+			return a.occurrences > b.occurrences
+		}
+		if a.size != b.size {
+			return a.size > b.size
+		}
+		return result[i].expression < result[j].expression
+	})
+	return result
+}
+
+// collectSubexpressions records the subexpressions of the expression of the
+// given index.
+func collectSubexpressions(ast grammar.Expression, idx int, candidates map[string]*subexprCandidate) {
+	add := func(node grammar.Expression) {
+		expression := formatExpression(node)
+		cand, ok := candidates[expression]
+		if !ok {
+			cand = &subexprCandidate{ast: node, size: subexprSize(node), expressions: make(map[int]struct{})}
+			candidates[expression] = cand
+		}
+		cand.occurrences++
+		cand.expressions[idx] = struct{}{}
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		add(node)
+		collectSubexpressions(node.Operand, idx, candidates)
+	case *grammar.BinaryExpression:
+		operands := logicalOperands(node)
+		for start := 0; start < len(operands); start++ {
+			for end := start + 2; end <= len(operands); end++ {
+				add(logicalChain(node.Operator, operands[start:end]))
+			}
+			collectSubexpressions(operands[start], idx, candidates)
+		}
+	case *grammar.MatchExpression:
+		add(node)
+	}
+}
+
+// replaceSubexpression replaces the occurrences of the subexpression by the
+// reference, counting them.
+func replaceSubexpression(ast grammar.Expression, expression string, ref grammar.Expression, replaced *int) grammar.Expression {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		if formatExpression(node) == expression {
+			*replaced++
+			return ref
+		}
+		return &grammar.UnaryExpression{Operator: node.Operator, Operand: replaceSubexpression(node.Operand, expression, ref, replaced)}
+	case *grammar.BinaryExpression:
+		operands := logicalOperands(node)
+		var kept []grammar.Expression
+		for start := 0; start < len(operands); start++ {
+			end := start + 2
+			for ; end <= len(operands); end++ {
+				if formatExpression(logicalChain(node.Operator, operands[start:end])) == expression {
+					break
+				}
+			}
+			if end <= len(operands) {
+				*replaced++
+				kept = append(kept, ref)
+				start = end - 1
+				continue
+			}
+			kept = append(kept, replaceSubexpression(operands[start], expression, ref, replaced))
+		}
+		return logicalChain(node.Operator, kept)
+	case *grammar.MatchExpression:
+		if formatExpression(node) == expression {
+			*replaced++
+			return ref
+		}
+	}
+	return ast
+}
+
+// logicalOperands returns the operands of a chain of the same logical
+// operator, however it is nested.
+func logicalOperands(node *grammar.BinaryExpression) []grammar.Expression {
+	var operands []grammar.Expression
+	for _, operand := range []grammar.Expression{node.Left, node.Right} {
+		if chain, ok := operand.(*grammar.BinaryExpression); ok && chain.Operator == node.Operator {
+			operands = append(operands, logicalOperands(chain)...)
+		} else {
+			operands = append(operands, operand)
+		}
+	}
+	return operands
+}
+
+// logicalChain builds the chain of the operands nested to the right, the way
+// the parser builds them.
+func logicalChain(op grammar.BinaryOperator, operands []grammar.Expression) grammar.Expression {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return &grammar.BinaryExpression{Operator: op, Left: operands[0], Right: logicalChain(op, operands[1:])}
+}
+
+// subexprSize counts the match expressions, values, negations and let
+// expressions of the expression
+func subexprSize(ast grammar.Expression) int {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return 1 + subexprSize(node.Operand)
+	case *grammar.BinaryExpression:
+		return subexprSize(node.Left) + subexprSize(node.Right)
+	case *grammar.LetExpression:
+		return 1 + subexprSize(node.Body)
+	}
+	return 1
+}
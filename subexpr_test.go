@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var subexprCorpus = []string{
+	`Tenant == "acme" and Env == "prod" and Role == "admin"`,
+	`Tenant == "acme" and Env == "prod" and Role == "viewer" and Port > 8000`,
+	`Tenant == "acme" and Env == "prod" and (Role == "admin" or Port < 100)`,
+	`not Tags is empty and Role == "admin"`,
+	`Region == "eu" or not Tags is empty`,
+}
+
+func TestFindCommonSubexpressions(t *testing.T) {
+	t.Parallel()
+
+	common, err := FindCommonSubexpressions(subexprCorpus, 2)
+	require.NoError(t, err)
+	require.Equal(t, []Subexpression{
+		{Expression: `Tenant == "acme" and Env == "prod"`, Occurrences: 3, Expressions: []int{0, 1, 2}},
+		{Expression: `Role == "admin"`, Occurrences: 3, Expressions: []int{0, 2, 3}},
+		{Expression: `not Tags is empty`, Occurrences: 2, Expressions: []int{3, 4}},
+	}, common)
+
+	common, err = FindCommonSubexpressions(subexprCorpus, 3)
+	require.NoError(t, err)
+	require.Len(t, common, 2)
+
+	_, err = FindCommonSubexpressions([]string{`a ==`}, 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expression 0:")
+}
+
+func TestExtractMacros(t *testing.T) {
+	t.Parallel()
+
+	macros, rewritten, err := ExtractMacros(subexprCorpus, 2)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"macro1": `Tenant == "acme" and Env == "prod"`,
+		"macro2": `Role == "admin"`,
+		"macro3": `not Tags is empty`,
+	}, macros)
+	require.Equal(t, []string{
+		`macro1 and macro2`,
+		`macro1 and Role == "viewer" and Port > 8000`,
+		`macro1 and (macro2 or Port < 100)`,
+		`macro3 and macro2`,
+		`Region == "eu" or macro3`,
+	}, rewritten)
+
+	// the rewritten expressions evaluate the same way
+	datums := []map[string]interface{}{
+		{"Tenant": "acme", "Env": "prod", "Role": "admin", "Port": 8080, "Tags": []string{"a"}, "Region": "us"},
+		{"Tenant": "acme", "Env": "dev", "Role": "viewer", "Port": 80, "Tags": []string{}, "Region": "eu"},
+	}
+	for i, expression := range subexprCorpus {
+		original, err := CreateEvaluator(expression)
+		require.NoError(t, err)
+		extracted, err := CreateEvaluator(rewritten[i], WithMacros(macros))
+		require.NoError(t, err)
+		for _, datum := range datums {
+			expected, err := original.Evaluate(datum)
+			require.NoError(t, err)
+			result, err := extracted.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, expected, result, rewritten[i])
+		}
+	}
+
+	t.Run("names do not shadow selectors", func(t *testing.T) {
+		t.Parallel()
+
+		macros, rewritten, err := ExtractMacros([]string{`macro1 == 1 and a == 2`, `a == 2 or b == 3`}, 2)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"macro2": `a == 2`}, macros)
+		require.Equal(t, []string{`macro1 == 1 and macro2`, `macro2 or b == 3`}, rewritten)
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// maxSuggestions is the number of selectors suggested for an unknown one
+const maxSuggestions = 3
+
+// EnumerableSchema is a Schema listing the fields of the objects it
+// describes, so that the selectors which cannot be resolved against it come
+// with suggestions, see UnknownSelectorError. The schemas returned by
+// TypeSchema, JSONSchema and OpenAPISchema are enumerable.
+type EnumerableSchema interface {
+	Schema
+	// SelectorFields returns the names of the fields of the object found at
+	// the selector path, or nil when they are not known, such as for maps.
+	SelectorFields(path []string) []string
+}
+
+// SelectorFields returns the fields of the struct found at the path, named
+// as selectors name them.
+func (s *typeSchema) SelectorFields(path []string) []string {
+	typ, err := s.SelectorType(path)
+	if err != nil || typ == nil || derefType(typ).Kind() != reflect.Struct {
+		return nil
+	}
+	typ = derefType(typ)
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		switch tag := taggedName(field, s.fieldTagNames()); tag {
+		case "-":
+		case "":
+			fields = append(fields, field.Name)
+		default:
+			fields = append(fields, tag)
+		}
+	}
+	return fields
+}
+
+// SelectorFields returns the properties declared by the object schema found
+// at the path, including the ones of its subschemas.
+func (s *jsonSchema) SelectorFields(path []string) []string {
+	node, err := s.lookup(path)
+	if err != nil || node == nil {
+		return nil
+	}
+	var fields []string
+	var collect func(node map[string]interface{})
+	collect = func(node map[string]interface{}) {
+		node, err := s.resolve(node)
+		if err != nil {
+			return
+		}
+		if properties, ok := node["properties"].(map[string]interface{}); ok {
+			for name := range properties {
+				fields = append(fields, name)
+			}
+		}
+		for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+			for _, sub := range s.subschemas(node, keyword) {
+				collect(sub)
+			}
+		}
+	}
+	collect(node)
+	sort.Strings(fields)
+	return fields
+}
+
+func (s *docsSchema) SelectorFields(path []string) []string {
+	if enumerable, ok := s.Schema.(EnumerableSchema); ok {
+		return enumerable.SelectorFields(path)
+	}
+	return nil
+}
+
+// suggestSelectors returns the selectors of the schema closest to the one
+// which cannot be resolved against it, replacing the first part of the
+// selector which cannot be resolved with the closest field names. They are
+// written in the bexpr syntax.
+func suggestSelectors(schema Schema, sel grammar.Selector) []string {
+	enumerable, ok := schema.(EnumerableSchema)
+	if !ok {
+		return nil
+	}
+	for i := range sel.Path {
+		if _, err := schema.SelectorType(sel.Path[:i+1]); err == nil {
+			continue
+		}
+		var suggestions []string
+		for _, field := range closestNames(sel.Path[i], enumerable.SelectorFields(sel.Path[:i])) {
+			suggested := sel
+			suggested.Path = append(append(append([]string{}, sel.Path[:i]...), field), sel.Path[i+1:]...)
+			suggestions = append(suggestions, formatSelector(suggested))
+		}
+		return suggestions
+	}
+	return nil
+}
+
+// closestNames returns the names within an edit distance of a quarter of the
+// length of the name, or of one for short names, closest first. Differences
+// of case only count as one edit.
+func closestNames(name string, names []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	maxDistance := len([]rune(name)) / 4
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	var candidates []candidate
+	for _, n := range names {
+		if n == name {
+			continue
+		}
+		distance := editDistance(strings.ToLower(name), strings.ToLower(n))
+		if distance == 0 {
+			distance = 1
+		}
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{name: n, distance: distance})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+	result := make([]string, len(candidates))
+	for i, c := range candidates {
+		result[i] = c.name
+	}
+	return result
+}
+
+// editDistance returns the number of insertions, deletions, substitutions
+// and transpositions of adjacent runes turning a into b, the optimal string
+// alignment distance
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestions(t *testing.T) {
+	t.Parallel()
+
+	type meta struct {
+		Environment string `bexpr:"env"`
+		Region      string
+	}
+	type service struct {
+		Name     string
+		Node     string
+		Meta     meta
+		Labels   map[string]string
+		Tags     []string
+		Internal string `bexpr:"-"`
+	}
+
+	jsonSchema, err := JSONSchema([]byte(`{
+		"type": "object",
+		"properties": {"name": {"type": "string"}},
+		"allOf": [{"properties": {"port": {"type": "integer"}, "ports": {"type": "array"}}}]
+	}`))
+	require.NoError(t, err)
+
+	type testCase struct {
+		expression  string
+		schema      Schema
+		suggestions []string
+		err         string
+	}
+
+	tests := map[string]testCase{
+		"transposition": {expression: `Nmae == "web"`, schema: TypeSchema(service{}), suggestions: []string{"Name"}, err: `error finding value in schema: /Nmae at part 0: couldn't find key: struct field with name "Nmae" (did you mean Name?)`},
+		"case":          {expression: `name == "web"`, schema: TypeSchema(service{}), suggestions: []string{"Name"}},
+		"several":       {expression: `Nome == "web"`, schema: TypeSchema(service{}), suggestions: []string{"Name", "Node"}, err: `error finding value in schema: /Nome at part 0: couldn't find key: struct field with name "Nome" (did you mean Name or Node?)`},
+		"nested":        {expression: `Meta.Regoin.x == "eu"`, schema: TypeSchema(service{}), suggestions: []string{"Meta.Region.x"}},
+		"tag name":      {expression: `Meta.envv == "prod"`, schema: TypeSchema(service{}), suggestions: []string{"Meta.env"}},
+		"ignored field": {expression: `Internl == "x"`, schema: TypeSchema(service{})},
+		"too far":       {expression: `Host == "web"`, schema: TypeSchema(service{})},
+		"json schema":   {expression: `prot == 80`, schema: jsonSchema, suggestions: []string{"port"}},
+		"json pointer":  {expression: `"/Meta/Regin" == "eu"`, schema: TypeSchema(service{}), suggestions: []string{"Meta.Region"}},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithSchema(tcase.schema))
+			var selectorErr *UnknownSelectorError
+			require.True(t, errors.As(err, &selectorErr), "%v", err)
+			require.Equal(t, tcase.suggestions, selectorErr.Suggestions)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	for _, tcase := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"name", "name", 0},
+		{"name", "nmae", 1},
+		{"name", "names", 1},
+		{"name", "nam", 1},
+		{"kitten", "sitting", 3},
+		{"région", "region", 1},
+	} {
+		require.Equal(t, tcase.distance, editDistance(tcase.a, tcase.b), "%s %s", tcase.a, tcase.b)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/pointerstructure"
+)
+
+// WithTagNames names the fields of structs after the first of the tags given
+// they have, and after their Go name when they have none of them, so that
+// WithTagNames("bexpr", "json", "mapstructure") lets one expression select
+// both the fields of API structs and the keys of the maps decoded from
+// configurations. A field whose first tag is "-" is ignored. The last of
+// WithTagName and WithTagNames given applies, to the evaluations and to the
+// type schemas the evaluator validates the expression against alike.
+func WithTagNames(tagNames ...string) Option {
+	return func(o *options) {
+		o.withTagName = ""
+		if len(tagNames) > 0 {
+			o.withTagName = tagNames[0]
+		}
+		o.withTagNames = nil
+		if len(tagNames) > 1 {
+			o.withTagNames = tagNames
+		}
+	}
+}
+
+// taggedPointer is a pointer which resolves the fields of structs after
+// several tags, resolving the other values with pointerstructure
+type taggedPointer struct {
+	pointerstructure.Pointer
+	tagNames []string
+}
+
+// Get resolves the pointer like pointerstructure.Pointer.Get does, reporting
+// the same errors
+func (p taggedPointer) Get(v interface{}) (interface{}, error) {
+	if len(p.tagNames) < 2 {
+		return p.Pointer.Get(v)
+	}
+	hook := p.Config.ValueTransformationHook
+	current := reflect.ValueOf(v)
+	for i, part := range p.Parts {
+		for current.Kind() == reflect.Interface || current.Kind() == reflect.Ptr {
+			current = current.Elem()
+		}
+		switch current.Kind() {
+		case reflect.Struct:
+			field, err := taggedField(current.Type(), part, p.tagNames)
+			if err != nil {
+				return nil, fmt.Errorf("%s at part %d: %w", &p.Pointer, i, err)
+			}
+			current = current.Field(field)
+		case reflect.Map, reflect.Slice, reflect.Array:
+			elem := pointerstructure.Pointer{Parts: []string{part}}
+			val, err := elem.Get(current.Interface())
+			if err != nil {
+				// the error of the part, without the pointer of the part
+				return nil, fmt.Errorf("%s at part %d: %w", &p.Pointer, i, errors.Unwrap(err))
+			}
+			current = reflect.ValueOf(val)
+			if !current.IsValid() {
+				// the nil interfaces of maps and slices
+				current = reflect.Zero(reflect.TypeOf((*interface{})(nil)).Elem())
+			}
+		default:
+			return nil, fmt.Errorf("%s: at part %d, %w: %s", &p.Pointer, i, pointerstructure.ErrInvalidKind, current.Kind())
+		}
+		if hook != nil {
+			current = hook(current)
+			if current == reflect.ValueOf(nil) {
+				return nil, fmt.Errorf("%s at part %d: ValueTransformationHook returned the value of a nil interface", &p.Pointer, i)
+			}
+		}
+	}
+	return current.Interface(), nil
+}
+
+// taggedField returns the index of the field of the struct named by the part
+// after the tags, the fields named after a tag taking precedence over the
+// ones named after their Go name
+func taggedField(typ reflect.Type, part string, tagNames []string) (int, error) {
+	found, ignored := -1, false
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		switch tag := taggedName(field, tagNames); {
+		case tag == "-":
+			ignored = ignored || field.Name == part
+		case tag == part:
+			return i, nil
+		case tag == "" && field.Name == part:
+			found = i
+		}
+	}
+	switch {
+	case ignored && found == -1:
+		return -1, fmt.Errorf("struct field %q is ignored and cannot be used", part)
+	case found == -1:
+		return -1, fmt.Errorf("%w: struct field with name %q", pointerstructure.ErrNotFound, part)
+	}
+	return found, nil
+}
+
+// taggedName returns the name the first of the tags the field has gives it,
+// without its options, or "" when the field has none of them
+func taggedName(field reflect.StructField, tagNames []string) string {
+	tag := ""
+	for _, tagName := range tagNames {
+		if tag = field.Tag.Get(tagName); tag != "" {
+			break
+		}
+	}
+	if idx := strings.Index(tag, ","); idx != -1 {
+		tag = tag[0:idx]
+	}
+	return tag
+}
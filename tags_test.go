@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type taggedMeta struct {
+	Env    string `mapstructure:"environment"`
+	Region string
+}
+
+type taggedService struct {
+	Name     string            `bexpr:"name" json:"service_name"`
+	Port     int               `json:"port"`
+	Meta     *taggedMeta       `json:"meta"`
+	Secret   string            `bexpr:"-" json:"secret"`
+	Labels   map[string]string `json:"labels"`
+	Replicas []interface{}     `json:"replicas"`
+	internal string
+}
+
+func TestWithTagNames(t *testing.T) {
+	t.Parallel()
+
+	service := &taggedService{
+		Name:     "web",
+		Port:     80,
+		Meta:     &taggedMeta{Env: "prod", Region: "eu"},
+		Secret:   "s3cr3t",
+		Labels:   map[string]string{"tier": "front"},
+		Replicas: []interface{}{map[string]interface{}{"zone": "a"}, nil},
+		internal: "x",
+	}
+	config := map[string]interface{}{
+		"name": "web",
+		"port": 80,
+		"meta": map[string]interface{}{"environment": "prod", "Region": "eu"},
+	}
+
+	type testCase struct {
+		expression string
+		result     bool
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"first tag":      {expression: `name == "web"`, result: true},
+		"second tag":     {expression: `port == 80 and labels.tier == "front"`, result: true},
+		"third tag":      {expression: `meta.environment == "prod"`, result: true},
+		"go name":        {expression: `meta.Region == "eu"`, result: true},
+		"slice":          {expression: `replicas.0.zone == "a" and replicas.1 == null`, result: true},
+		"shadowed tag":   {expression: `service_name == "web"`, err: `1:1 (0): service_name == "web": error finding value in datum: /service_name at part 0: couldn't find key: struct field with name "service_name"`},
+		"shadowed name":  {expression: `Port == 80`, err: `1:1 (0): Port == 80: error finding value in datum: /Port at part 0: couldn't find key: struct field with name "Port"`},
+		"ignored":        {expression: `Secret == "s3cr3t"`, err: `1:1 (0): Secret == "s3cr3t": error finding value in datum: /Secret at part 0: struct field "Secret" is ignored and cannot be used`},
+		"ignored tag":    {expression: `secret == "s3cr3t"`, err: `1:1 (0): secret == "s3cr3t": error finding value in datum: /secret at part 0: couldn't find key: struct field with name "secret"`},
+		"unexported":     {expression: `internal == "x"`, err: `1:1 (0): internal == "x": error finding value in datum: /internal at part 0: couldn't find key: struct field with name "internal"`},
+		"missing key":    {expression: `labels.env == "prod"`, result: false},
+		"invalid kind":   {expression: `port.value == 80`, err: `1:1 (0): port.value == 80: error finding value in datum: /port/value: at part 1, invalid value kind: int`},
+		"out of range":   {expression: `replicas.5 == null`, err: `1:1 (0): replicas.5 == null: error finding value in datum: /replicas/5 at part 1: index 5 is out of range (length = 2)`},
+		"nested missing": {expression: `meta.zone == "a"`, err: `1:1 (0): meta.zone == "a": error finding value in datum: /meta/zone at part 1: couldn't find key: struct field with name "zone"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, WithTagNames("bexpr", "json", "mapstructure"))
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(service)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tcase.result, result)
+		})
+	}
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+
+		eval, err := CreateEvaluator(`name == "web" and port == 80 and meta.environment == "prod"`,
+			WithTagNames("bexpr", "json", "mapstructure"))
+		require.NoError(t, err)
+
+		for _, datum := range []interface{}{service, config} {
+			result, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, true, result)
+		}
+	})
+}
+
+func TestWithTagNames_Options(t *testing.T) {
+	t.Parallel()
+
+	datum := taggedService{Name: "web", Port: 80}
+
+	eval, err := CreateEvaluator(`port == 80`, WithTagNames("json"), WithTagNames("bexpr", "json"))
+	require.NoError(t, err)
+	result, err := eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	// the last of WithTagName and WithTagNames applies
+	eval, err = CreateEvaluator(`Port == 80`, WithTagNames("bexpr", "json"), WithTagName("bexpr"))
+	require.NoError(t, err)
+	result, err = eval.Evaluate(datum)
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	_, err = CreateEvaluator(`port == 80`, WithTagNames())
+	require.EqualError(t, err, `invalid options: the tag name cannot be empty`)
+	_, err = CreateEvaluator(`port == 80`, WithTagNames("bexpr", ""))
+	require.EqualError(t, err, `invalid options: the tag names cannot be empty`)
+}
+
+func TestWithTagNames_Schema(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"first tag":     {expression: `name == "web"`},
+		"second tag":    {expression: `port == 80 and labels.tier == "front"`},
+		"third tag":     {expression: `meta.environment == "prod"`},
+		"go name":       {expression: `meta.Region == "eu"`},
+		"shadowed tag":  {expression: `service_name == "web"`, err: `error finding value in schema: /service_name at part 0: couldn't find key: struct field with name "service_name"`},
+		"shadowed name": {expression: `Port == 80`, err: `error finding value in schema: /Port at part 0: couldn't find key: struct field with name "Port" (did you mean port?)`},
+		"ignored":       {expression: `Secret == "s3cr3t"`, err: `error finding value in schema: /Secret at part 0: struct field "Secret" is ignored and cannot be used`},
+		"ignored tag":   {expression: `secret == "s3cr3t"`, err: `error finding value in schema: /secret at part 0: couldn't find key: struct field with name "secret"`},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := CreateEvaluator(tcase.expression, WithTagNames("bexpr", "json", "mapstructure"),
+				WithSchema(TypeSchema(taggedService{})))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			eval, err := CreateEvaluator(tcase.expression, WithTagNames("bexpr", "json", "mapstructure"))
+			require.NoError(t, err)
+			err = eval.Validate(TypeSchema(&taggedService{}))
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Trace records how a node of the expression was evaluated, to explain why a
+// datum was matched or not.
+type Trace struct {
+	// Expression is the AST node which was evaluated
+	Expression grammar.Expression
+	// Left and Right are the values the operands of a match expression
+	// resolved to, Left being the value bound by a let expression. Selectors
+	// which were not found are reported as nil.
+	Left  interface{}
+	Right interface{}
+	// Result is the outcome of the node. It is false when Err is set.
+	Result bool
+	Err    error
+	// Children are the traces of the operands of logical operators, in the
+	// order they were evaluated, or of the body of a let expression. Operands
+	// skipped by short-circuiting are not included.
+	Children []*Trace
+
+	// deterministic renders the values without addresses, see
+	// WithDeterministic
+	deterministic bool
+}
+
+// String renders the trace as an indented tree, one node per line.
+func (t *Trace) String() string {
+	var b strings.Builder
+	_ = t.Dump(&b, grammar.DumpConfig{})
+	return b.String()
+}
+
+// Events flattens the trace into the list of its nodes in the order they were
+// evaluated, parents first, see api.TraceEvent.
+func (t *Trace) Events() []api.TraceEvent {
+	var events []api.TraceEvent
+	var walk func(t *Trace, depth int)
+	walk = func(t *Trace, depth int) {
+		events = append(events, api.TraceEvent{
+			Depth:      depth,
+			Expression: formatExpression(t.Expression),
+			Left:       t.Left,
+			Right:      t.Right,
+			Result:     t.Result,
+			Err:        t.Err,
+		})
+		for _, child := range t.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(t, 0)
+	return events
+}
+
+// Dump writes the trace to w the way String renders it, streaming it rather
+// than buffering it, with the indentation of the configuration and the
+// expressions and values cut to its width. It returns the first error
+// writing to w, the rest of the trace being skipped.
+func (t *Trace) Dump(w io.Writer, cfg grammar.DumpConfig) error {
+	if cfg.Indent == "" {
+		cfg.Indent = "   "
+	}
+	d := &traceDumper{w: w, cfg: cfg}
+	d.dump(t, 0)
+	return d.err
+}
+
+// traceDumper writes traces, keeping the first error
+type traceDumper struct {
+	w   io.Writer
+	cfg grammar.DumpConfig
+	err error
+}
+
+func (d *traceDumper) write(s ...string) {
+	for _, part := range s {
+		if d.err != nil {
+			return
+		}
+		_, d.err = io.WriteString(d.w, part)
+	}
+}
+
+// value returns the value cut to the configured width
+func (d *traceDumper) value(s string) string {
+	if d.cfg.Width <= 0 || utf8.RuneCountInString(s) <= d.cfg.Width {
+		return s
+	}
+	n := 0
+	for i := range s {
+		if n == d.cfg.Width {
+			return s[:i] + "..."
+		}
+		n++
+	}
+	return s
+}
+
+func (d *traceDumper) dump(t *Trace, level int) {
+	if d.err != nil {
+		return
+	}
+	for i := 0; i < level; i++ {
+		d.write(d.cfg.Indent)
+	}
+	outcome := "=> " + strconv.FormatBool(t.Result) + "\n"
+	if t.Err != nil {
+		outcome = "=> error: " + t.Err.Error() + "\n"
+	}
+
+	switch node := t.Expression.(type) {
+	case *grammar.UnaryExpression:
+		d.write(node.Operator.String(), " ", outcome)
+	case *grammar.BinaryExpression:
+		d.write(node.Operator.String(), " ", outcome)
+	case *grammar.LetExpression:
+		d.write("let ", node.Name, " = ", d.value(node.Value.String()), " [", d.value(d.traceValue(t, t.Left)), "] ", outcome)
+	case *grammar.MatchExpression:
+		if node.Right == nil {
+			d.write(d.value(node.Left.String()), " ", node.Operator.String(), " [", d.value(d.traceValue(t, t.Left)), "] ", outcome)
+		} else {
+			d.write(d.value(node.Left.String()), " ", node.Operator.String(), " ", d.value(node.Right.String()),
+				" [", d.value(d.traceValue(t, t.Left)), ", ", d.value(d.traceValue(t, t.Right)), "] ", outcome)
+		}
+	default:
+		d.write(fmt.Sprintf("%T ", node), outcome)
+	}
+
+	for _, child := range t.Children {
+		d.dump(child, level+1)
+	}
+}
+
+// traceValue renders a value of the trace
+func (d *traceDumper) traceValue(t *Trace, value interface{}) string {
+	if t.deterministic {
+		return deterministicValue(value)
+	}
+	return traceValue(value)
+}
+
+func traceValue(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprintf("%#v", value)
+}
+
+// setDeterministic renders the values of the trace and of its children
+// without addresses
+func (t *Trace) setDeterministic() {
+	t.deterministic = true
+	for _, child := range t.Children {
+		child.setDeterministic()
+	}
+}
+
+// evaluateTrace evaluates the AST the same way evaluateContext does while
+// recording the trace of every node evaluated.
+func evaluateTrace(ctx context.Context, ast grammar.Expression, datum interface{}, opt ...Option) *Trace {
+	trace := &Trace{Expression: ast}
+	if err := ctx.Err(); err != nil {
+		trace.Err = err
+		return trace
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		operand := evaluateTrace(ctx, node.Operand, datum, opt...)
+		trace.Children = []*Trace{operand}
+		trace.Err = operand.Err
+		trace.Result = operand.Err == nil && !operand.Result
+	case *grammar.BinaryExpression:
+		left := evaluateTrace(ctx, node.Left, datum, opt...)
+		trace.Children = []*Trace{left}
+		trace.Result, trace.Err = left.Result, left.Err
+		shortCircuit := left.Err != nil ||
+			(node.Operator == grammar.BinaryOpAnd && !left.Result) ||
+			(node.Operator == grammar.BinaryOpOr && left.Result)
+		if !shortCircuit {
+			right := evaluateTrace(ctx, node.Right, datum, opt...)
+			trace.Children = append(trace.Children, right)
+			trace.Result, trace.Err = right.Result, right.Err
+		}
+	case *grammar.LetExpression:
+		value, err := getExprValue(node.Value, datum, opt...)
+		if err != nil {
+			trace.Err = letError(node, err)
+			break
+		}
+		if !isUndefined(value) {
+			trace.Left = value
+		}
+		body := evaluateTrace(ctx, node.Body, datum, append(opt[:len(opt):len(opt)], withBinding(node.Name, value))...)
+		trace.Children = []*Trace{body}
+		trace.Result, trace.Err = body.Result, body.Err
+	case *grammar.MatchExpression:
+		trace.Left = traceOperand(node.Left, datum, opt...)
+		trace.Right = traceOperand(node.Right, datum, opt...)
+		trace.Result, trace.Err = evaluateMatchExpression(node, datum, opt...)
+		trace.Err = matchError(node, trace.Err)
+	default:
+		result, err := evaluateContext(ctx, ast, datum, opt...)
+		trace.Err = err
+		trace.Result, _ = result.(bool)
+	}
+
+	if trace.Err != nil {
+		trace.Result = false
+	}
+	return trace
+}
+
+func traceOperand(expr *grammar.ExpressionValue, datum interface{}, opt ...Option) interface{} {
+	if expr == nil {
+		return nil
+	}
+	value, err := getExprValue(expr, datum, opt...)
+	if err != nil || isUndefined(value) {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTrace(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		datum      interface{}
+		result     bool
+		err        string
+		trace      string
+	}
+
+	tests := map[string]testCase{
+		"match": {
+			expression: `Name == "web"`,
+			datum:      map[string]interface{}{"Name": "db"},
+			result:     false,
+			trace:      "Name Equal web [\"db\", \"web\"] => false\n",
+		},
+		"short circuit": {
+			expression: `Name == "web" and Port > 8000`,
+			datum:      map[string]interface{}{"Name": "db", "Port": 8080},
+			result:     false,
+			trace: "And => false\n" +
+				"   Name Equal web [\"db\", \"web\"] => false\n",
+		},
+		"nested": {
+			expression: `(Name == "web" or Port > 8000) and not Tags is empty`,
+			datum:      map[string]interface{}{"Name": "db", "Port": 8080, "Tags": []string{"prod"}},
+			result:     true,
+			trace: "And => true\n" +
+				"   Or => true\n" +
+				"      Name Equal web [\"db\", \"web\"] => false\n" +
+				"      Port Higher 8000 [8080, 8000] => true\n" +
+				"   Not => true\n" +
+				"      Tags Is Empty [[]string{\"prod\"}] => false\n",
+		},
+		"let": {
+			expression: `let t = Meta.tier in t == "gold" or t == "platinum"`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{"tier": "platinum"}},
+			result:     true,
+			trace: "let t = Meta.tier [\"platinum\"] => true\n" +
+				"   Or => true\n" +
+				"      t Equal gold [\"platinum\", \"gold\"] => false\n" +
+				"      t Equal platinum [\"platinum\", \"platinum\"] => true\n",
+		},
+		"missing and null": {
+			expression: `Meta.owner is null or Meta.name == null`,
+			datum:      map[string]interface{}{"Meta": map[string]interface{}{"name": nil}},
+			result:     true,
+			trace: "Or => true\n" +
+				"   Meta.owner Is Null [null] => true\n",
+		},
+		"error": {
+			expression: `Name == "web" or Name.first == "x"`,
+			datum:      map[string]interface{}{"Name": "db"},
+			err:        `1:18 (17): Name.first == "x": error finding value in datum: /Name/first: at part 1, invalid value kind: string`,
+			trace: "Or => error: 1:18 (17): Name.first == \"x\": error finding value in datum: /Name/first: at part 1, invalid value kind: string\n" +
+				"   Name Equal web [\"db\", \"web\"] => false\n" +
+				"   Name.first Equal x [null, \"x\"] => error: 1:18 (17): Name.first == \"x\": error finding value in datum: /Name/first: at part 1, invalid value kind: string\n",
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var trace *Trace
+			eval, err := CreateEvaluator(tcase.expression, WithTrace(func(tr *Trace) { trace = tr }))
+			require.NoError(t, err)
+
+			result, err := eval.Evaluate(tcase.datum)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tcase.result, result)
+			}
+			require.NotNil(t, trace)
+			require.Equal(t, tcase.trace, trace.String())
+
+			// tracing must not change the outcome
+			untraced, err := CreateEvaluator(tcase.expression)
+			require.NoError(t, err)
+			expected, err := untraced.Evaluate(tcase.datum)
+			if tcase.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, expected, result)
+			}
+		})
+	}
+}
+
+func TestWithTrace_Operands(t *testing.T) {
+	t.Parallel()
+
+	var trace *Trace
+	eval, err := CreateEvaluator(`"prod" in Tags`, WithTrace(func(tr *Trace) { trace = tr }))
+	require.NoError(t, err)
+
+	result, err := eval.Evaluate(map[string]interface{}{"Tags": []string{"dev"}})
+	require.NoError(t, err)
+	require.Equal(t, false, result)
+
+	require.IsType(t, &grammar.MatchExpression{}, trace.Expression)
+	require.Equal(t, []string{"dev"}, trace.Left)
+	require.Equal(t, "prod", trace.Right)
+	require.False(t, trace.Result)
+	require.Empty(t, trace.Children)
+}
+
+func TestTrace_Dump(t *testing.T) {
+	t.Parallel()
+
+	var trace *Trace
+	eval, err := CreateEvaluator(`not Name == "a-very-long-name"`, WithTrace(func(tr *Trace) { trace = tr }))
+	require.NoError(t, err)
+	_, err = eval.Evaluate(map[string]interface{}{"Name": "web"})
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, trace.Dump(&b, grammar.DumpConfig{Indent: "\t", Width: 8}))
+	require.Equal(t, "Not => true\n\tName Equal a-very-l... [\"web\", \"a-very-...] => false\n", b.String())
+}
+
+func TestTrace_Events(t *testing.T) {
+	t.Parallel()
+
+	var trace *Trace
+	eval, err := CreateEvaluator(`Name == "web" or not Port > 8000`, WithTrace(func(tr *Trace) { trace = tr }))
+	require.NoError(t, err)
+	result, err := eval.Evaluate(map[string]interface{}{"Name": "db", "Port": 80})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+
+	require.Equal(t, []api.TraceEvent{
+		{Depth: 0, Expression: `Name == "web" or not Port > 8000`, Result: true},
+		{Depth: 1, Expression: `Name == "web"`, Left: "db", Right: "web"},
+		{Depth: 1, Expression: `not Port > 8000`, Result: true},
+		{Depth: 2, Expression: `Port > 8000`, Left: 80, Right: int64(8000)},
+	}, trace.Events())
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// The names of the spans and of their attributes, see WithTracer
+const (
+	SpanParse    = "bexpr.Parse"
+	SpanEvaluate = "bexpr.Evaluate"
+
+	// AttributeHash is the hash of the expression, see Evaluator.Hash
+	AttributeHash = "bexpr.expression.hash"
+	// AttributeNodes is the number of not, and, or, let and match
+	// expressions of the expression
+	AttributeNodes = "bexpr.expression.nodes"
+	// AttributeResult is the outcome of the evaluation
+	AttributeResult = "bexpr.result"
+)
+
+// Tracer starts the spans of the evaluators created with WithTracer. The
+// package does not depend on OpenTelemetry: an adapter of its trace.Tracer,
+// such as the following one, implements Tracer.
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, bexpr.Span) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ span trace.Span }
+//
+//	func (s otelSpan) SetAttribute(key string, value interface{}) {
+//		switch v := value.(type) {
+//		case string:
+//			s.span.SetAttributes(attribute.String(key, v))
+//		case int:
+//			s.span.SetAttributes(attribute.Int(key, v))
+//		case bool:
+//			s.span.SetAttributes(attribute.Bool(key, v))
+//		}
+//	}
+//
+//	func (s otelSpan) RecordError(err error) {
+//		s.span.RecordError(err)
+//		s.span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s otelSpan) End() { s.span.End() }
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer. The values of the attributes are
+// strings, ints and bools.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// WithTracer traces the creation of the evaluators in SpanParse spans, and
+// their evaluations made with Evaluate or EvaluateContext, and the methods
+// built on them, in SpanEvaluate spans, children of the span of the context
+// given to EvaluateContext, so that slow expressions show up in distributed
+// traces. The spans have the AttributeHash and AttributeNodes attributes of
+// the expression, the SpanEvaluate spans the AttributeResult of the
+// evaluation too, and the errors are recorded. Evaluators being created
+// without a context, the SpanParse spans are root spans.
+func WithTracer(tracer Tracer) Option {
+	return func(o *options) {
+		o.withTracer = tracer
+	}
+}
+
+// spanAttributes are the attributes of the expression of an evaluator,
+// computed once
+type spanAttributes struct {
+	hash  string
+	nodes int
+}
+
+func newSpanAttributes(eval *Evaluator) spanAttributes {
+	return spanAttributes{hash: eval.Hash(), nodes: nodeCount(eval.ast)}
+}
+
+func (a spanAttributes) set(span Span) {
+	span.SetAttribute(AttributeHash, a.hash)
+	span.SetAttribute(AttributeNodes, a.nodes)
+}
+
+// endParseSpan ends the span of the creation of the evaluator
+func endParseSpan(span Span, eval *Evaluator, err error) {
+	if err != nil {
+		span.RecordError(err)
+	} else {
+		eval.spanAttributes.set(span)
+	}
+	span.End()
+}
+
+// endEvaluateSpan ends the span of an evaluation
+func (eval *Evaluator) endEvaluateSpan(span Span, result interface{}, err error) {
+	eval.spanAttributes.set(span)
+	matched, _ := result.(bool)
+	span.SetAttribute(AttributeResult, matched && err == nil)
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// nodeCount counts the not, and, or, let and match expressions of the
+// expression
+func nodeCount(ast grammar.Expression) int {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return 1 + nodeCount(node.Operand)
+	case *grammar.BinaryExpression:
+		return 1 + nodeCount(node.Left) + nodeCount(node.Right)
+	case *grammar.LetExpression:
+		return 1 + nodeCount(node.Body)
+	}
+	return 1
+}
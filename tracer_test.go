@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSpanKey struct{}
+
+// testSpan records the attributes and the errors of a span
+type testSpan struct {
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	errs       []error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+// testTracer records the spans started
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestWithTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	eval, err := CreateEvaluator(`Name == "web" and not Port < 1024`, WithTracer(tracer))
+	require.NoError(t, err)
+
+	root := &testSpan{name: "request"}
+	ctx := context.WithValue(context.Background(), testSpanKey{}, root)
+	result, err := eval.EvaluateContext(ctx, map[string]interface{}{"Name": "web", "Port": 8080})
+	require.NoError(t, err)
+	require.Equal(t, true, result)
+	_, err = eval.Evaluate(map[string]interface{}{"Name": "web", "Port": "80"})
+	require.Error(t, err)
+
+	attributes := map[string]interface{}{
+		AttributeHash:  eval.Hash(),
+		AttributeNodes: 4,
+	}
+	require.Len(t, tracer.spans, 3)
+
+	parse := tracer.spans[0]
+	require.Equal(t, SpanParse, parse.name)
+	require.Nil(t, parse.parent)
+	require.Equal(t, attributes, parse.attributes)
+	require.True(t, parse.ended)
+
+	attributes[AttributeResult] = true
+	matched := tracer.spans[1]
+	require.Equal(t, SpanEvaluate, matched.name)
+	require.Equal(t, root, matched.parent)
+	require.Equal(t, attributes, matched.attributes)
+	require.Empty(t, matched.errs)
+	require.True(t, matched.ended)
+
+	attributes[AttributeResult] = false
+	failed := tracer.spans[2]
+	require.Nil(t, failed.parent)
+	require.Equal(t, attributes, failed.attributes)
+	require.Equal(t, []error{err}, failed.errs)
+	require.True(t, failed.ended)
+}
+
+func TestWithTracer_ParseError(t *testing.T) {
+	t.Parallel()
+
+	tracer := &testTracer{}
+	_, err := CreateEvaluator(`Name ==`, WithTracer(tracer))
+	require.Error(t, err)
+	require.Len(t, tracer.spans, 1)
+	require.Equal(t, SpanParse, tracer.spans[0].name)
+	require.Empty(t, tracer.spans[0].attributes)
+	require.Equal(t, []error{err}, tracer.spans[0].errs)
+	require.True(t, tracer.spans[0].ended)
+}
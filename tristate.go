@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gterranova/go-bexpr/api"
+	"github.com/gterranova/go-bexpr/grammar"
+	"github.com/mitchellh/pointerstructure"
+)
+
+// Truth is the outcome of an expression evaluated with three-valued logic
+type Truth = api.Truth
+
+const (
+	False = api.False
+	True  = api.True
+	// Unknown is the outcome of a match expression whose selectors are
+	// missing, and of the logical operators it cannot be decided without.
+	Unknown = api.Unknown
+)
+
+func truthOf(b bool) Truth {
+	if b {
+		return True
+	}
+	return False
+}
+
+// EvaluateTristate evaluates the expression with three-valued logic. Instead
+// of falling back to the NotPresentDisposition of their operator, match
+// expressions whose selector is missing or null are Unknown, except "is null"
+// and "is not null" which are decided. Logical operators follow the semantics
+// of SQL: "not" keeps Unknown, "and" is False when either operand is False
+// and "or" is True when either operand is True, the outcome being Unknown
+// otherwise when either operand is Unknown. The options apply to this
+// evaluation only, as with Evaluate.
+func (eval *Evaluator) EvaluateTristate(datum interface{}, opts ...Option) (Truth, error) {
+	return evaluateTristate(context.Background(), eval.ast, datum, append(eval.evaluateOpts(), opts...)...)
+}
+
+func evaluateTristate(ctx context.Context, ast grammar.Expression, datum interface{}, opt ...Option) (Truth, error) {
+	if err := ctx.Err(); err != nil {
+		return False, err
+	}
+
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		switch node.Operator {
+		case grammar.UnaryOpNot:
+			result, err := evaluateTristate(ctx, node.Operand, datum, opt...)
+			if err != nil {
+				return False, err
+			}
+			return result.Not(), nil
+		}
+	case *grammar.BinaryExpression:
+		// the value of the operand deciding the outcome on its own
+		var absorbing Truth
+		switch node.Operator {
+		case grammar.BinaryOpAnd:
+			absorbing = False
+		case grammar.BinaryOpOr:
+			absorbing = True
+		default:
+			return False, fmt.Errorf("invalid AST node")
+		}
+
+		left, err := evaluateTristate(ctx, node.Left, datum, opt...)
+		if err != nil || left == absorbing {
+			return left, err
+		}
+		right, err := evaluateTristate(ctx, node.Right, datum, opt...)
+		if err != nil || right == absorbing {
+			return right, err
+		}
+		if left == Unknown || right == Unknown {
+			return Unknown, nil
+		}
+		return right, nil
+	case *grammar.LetExpression:
+		value, err := getExprValue(node.Value, datum, opt...)
+		if isNotFound(err) {
+			value, err = &undefined, nil
+		}
+		if err != nil {
+			return False, letError(node, err)
+		}
+		return evaluateTristate(ctx, node.Body, datum, append(opt[:len(opt):len(opt)], withBinding(node.Name, value))...)
+	case *grammar.MatchExpression:
+		leftValue, err := getExprValue(node.Left, datum, opt...)
+		if isNotFound(err) {
+			leftValue, err = &undefined, nil
+		}
+		if err != nil {
+			return False, matchError(node, err)
+		}
+		switch {
+		case node.Operator == grammar.MatchIsNull, node.Operator == grammar.MatchIsNotNull:
+			if isUndefined(leftValue) {
+				return truthOf(node.Operator.NotPresentDisposition()), nil
+			}
+		case notPresent(node.Operator, leftValue):
+			return Unknown, nil
+		}
+		result, err := evaluateMatchExpression(node, datum, opt...)
+		if isNotFound(err) {
+			return Unknown, nil
+		}
+		if err != nil {
+			return False, matchError(node, err)
+		}
+		return truthOf(result), nil
+	case *grammar.ExpressionValue:
+		value, err := getExprValue(node, datum, opt...)
+		if isNotFound(err) {
+			return Unknown, nil
+		}
+		if err != nil {
+			return False, err
+		}
+		return valueTruth(value)
+	}
+	return False, fmt.Errorf("invalid AST node")
+}
+
+// isNotFound reports whether the error is about a selector missing from the
+// datum
+func isNotFound(err error) bool {
+	return errors.Is(err, pointerstructure.ErrNotFound)
+}
+
+// valueTruth returns the outcome of an expression made of a single value
+func valueTruth(value interface{}) (Truth, error) {
+	if isUndefined(value) || isNull(value) {
+		return Unknown, nil
+	}
+	b, ok := indirect(value).(bool)
+	if !ok {
+		return False, fmt.Errorf("value of type %T is not a boolean", value)
+	}
+	return truthOf(b), nil
+}
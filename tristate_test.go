@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateTristate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		result     Truth
+		err        string
+	}
+
+	datum := map[string]interface{}{
+		"Name":  "web",
+		"Port":  8080,
+		"Owner": nil,
+	}
+
+	tests := map[string]testCase{
+		"known":                  {expression: `Name == "web"`, result: True},
+		"missing equal":          {expression: `Zone == "a"`, result: Unknown},
+		"missing not equal":      {expression: `Zone != "a"`, result: Unknown},
+		"missing in":             {expression: `"a" in Tags`, result: Unknown},
+		"missing is empty":       {expression: `Tags is empty`, result: Unknown},
+		"null lower":             {expression: `Owner < 5`, result: Unknown},
+		"null equal":             {expression: `Owner == null`, result: True},
+		"missing is null":        {expression: `Zone is null`, result: True},
+		"missing is not null":    {expression: `Zone is not null`, result: False},
+		"literal null lower":     {expression: `null < 5`, result: Unknown},
+		"not unknown":            {expression: `not Zone == "a"`, result: Unknown},
+		"not known":              {expression: `not Port == 8080`, result: False},
+		"and false unknown":      {expression: `Port == 80 and Zone == "a"`, result: False},
+		"and unknown false":      {expression: `Zone == "a" and Port == 80`, result: False},
+		"and true unknown":       {expression: `Port == 8080 and Zone == "a"`, result: Unknown},
+		"and true true":          {expression: `Port == 8080 and Name == "web"`, result: True},
+		"or true unknown":        {expression: `Port == 8080 or Zone == "a"`, result: True},
+		"or unknown true":        {expression: `Zone == "a" or Port == 8080`, result: True},
+		"or false unknown":       {expression: `Port == 80 or Zone == "a"`, result: Unknown},
+		"or false false":         {expression: `Port == 80 or Name == "db"`, result: False},
+		"unknown or unknown":     {expression: `Zone == "a" or Region == "b"`, result: Unknown},
+		"error":                  {expression: `Name < true`, err: `cannot be used`},
+		"not decided by literal": {expression: `Zone == "a" or true`, result: True},
+		"missing right operand":  {expression: `Port == Zone`, result: Unknown},
+		"missing nested":         {expression: `Meta.zone == "a"`, result: Unknown},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tc.expression)
+			require.NoError(t, err)
+
+			result, err := eval.EvaluateTristate(datum)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.result, result)
+		})
+	}
+}
+
+func TestWithUnknownResult(t *testing.T) {
+	t.Parallel()
+
+	datum := map[string]interface{}{"Port": 8080}
+
+	for _, unknownResult := range []bool{true, false} {
+		for expression, result := range map[string]interface{}{
+			// without three-valued logic, both would be true
+			`Zone != "a"`:     unknownResult,
+			`not Zone == "a"`: unknownResult,
+			`Port == 8080`:    true,
+			`Zone is null`:    true,
+		} {
+			eval, err := CreateEvaluator(expression, WithUnknownResult(unknownResult))
+			require.NoError(t, err)
+
+			match, err := eval.Evaluate(datum)
+			require.NoError(t, err)
+			require.Equal(t, result, match, "%s with unknown as %t", expression, unknownResult)
+		}
+	}
+}
+
+func TestTruth(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "true", True.String())
+	require.Equal(t, "false", False.String())
+	require.Equal(t, "unknown", Unknown.String())
+
+	require.True(t, True.Bool(false))
+	require.False(t, False.Bool(true))
+	require.True(t, Unknown.Bool(true))
+	require.False(t, Unknown.Bool(false))
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package bexpr
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// TypedEvaluator evaluates an expression against values of type T
+type TypedEvaluator[T any] struct {
+	eval *Evaluator
+}
+
+// Compile creates an evaluator for the values of type T. The expression is
+// validated against T the way WithSchema validates it, so that selectors T
+// cannot resolve and operators which cannot apply to the type of the values
+// they select fail the creation rather than the evaluations.
+func Compile[T any](expression string, opts ...Option) (*TypedEvaluator[T], error) {
+	schema := &typeSchema{typ: reflect.TypeOf((*T)(nil)).Elem()}
+	eval, err := CreateEvaluator(expression, append(opts[:len(opts):len(opts)], WithSchema(schema))...)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedEvaluator[T]{eval: eval}, nil
+}
+
+// Evaluator returns the underlying evaluator
+func (te *TypedEvaluator[T]) Evaluator() *Evaluator {
+	return te.eval
+}
+
+// Evaluate evaluates the expression against the value, see
+// Evaluator.Evaluate
+func (te *TypedEvaluator[T]) Evaluate(value T, opts ...Option) (bool, error) {
+	result, err := te.eval.Evaluate(value, opts...)
+	if err != nil {
+		return false, err
+	}
+	match, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %v rather than a boolean", result)
+	}
+	return match, nil
+}
+
+// Filter returns the values of seq matching the expression, see FilterSeq.
+// The values the evaluation fails on are left out like the ones which do not
+// match, see FilterErr to stop at their errors instead.
+func (te *TypedEvaluator[T]) Filter(seq iter.Seq[T]) iter.Seq[T] {
+	return FilterSeq(seq, te.eval)
+}
+
+// FilterErr returns the values of seq matching the expression, paired with a
+// nil error, the first value the evaluation fails on ending the sequence
+// paired with its error, see FilterSeqErr.
+func (te *TypedEvaluator[T]) FilterErr(seq iter.Seq[T]) iter.Seq2[T, error] {
+	return FilterSeqErr(seq, te.eval)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build go1.23
+
+package bexpr
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string
+		Port int
+		Tags []string
+	}
+
+	type testCase struct {
+		expression string
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"valid":            {expression: `Port > 1024 and "web" in Tags`},
+		"unknown selector": {expression: `Address == "10.0.0.1"`, err: `error finding value in schema: /Address at part 0: couldn't find key: struct field with name "Address"`},
+		"invalid operator": {expression: `Name > 3`, err: `Name: operator "Higher" cannot be used with values of type string`},
+		"syntax":           {expression: `Port >`, err: "1:7 (6): no match found"},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Compile[service](tcase.expression)
+			if tcase.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tcase.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTypedEvaluator(t *testing.T) {
+	t.Parallel()
+
+	type service struct {
+		Name string
+		Port int
+	}
+	services := []service{
+		{Name: "web", Port: 8080},
+		{Name: "ssh", Port: 22},
+		{Name: "api", Port: 9090},
+	}
+
+	eval, err := Compile[service](`Port > 1024`)
+	require.NoError(t, err)
+
+	match, err := eval.Evaluate(services[0])
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = eval.Evaluate(services[1])
+	require.NoError(t, err)
+	require.False(t, match)
+
+	require.Equal(t, []service{services[0], services[2]}, slices.Collect(eval.Filter(slices.Values(services))))
+
+	// stopping early
+	for s := range eval.Filter(slices.Values(services)) {
+		require.Equal(t, "web", s.Name)
+		break
+	}
+
+	// interface types are only validated at evaluation time
+	dynamic, err := Compile[map[string]interface{}](`Port > 1024`)
+	require.NoError(t, err)
+	match, err = dynamic.Evaluate(map[string]interface{}{"Port": 8080})
+	require.NoError(t, err)
+	require.True(t, match)
+
+	// the values the evaluation fails on are left out by Filter, and end the
+	// sequence of FilterErr
+	values := []map[string]interface{}{{"Port": 8080}, {"Port": "ssh"}, {"Port": 9090}}
+	require.Equal(t, []map[string]interface{}{values[0], values[2]}, slices.Collect(dynamic.Filter(slices.Values(values))))
+	var matched []map[string]interface{}
+	var errs []error
+	for value, err := range dynamic.FilterErr(slices.Values(values)) {
+		matched = append(matched, value)
+		errs = append(errs, err)
+	}
+	require.Equal(t, values[:2], matched)
+	require.NoError(t, errs[0])
+	require.Error(t, errs[1])
+}
+
+func TestCompile_SQLNull(t *testing.T) {
+	t.Parallel()
+
+	type row struct {
+		Name sql.NullString
+		Cnt  sql.NullInt64
+	}
+
+	eval, err := Compile[row](`Name == "x" and Cnt > 2`)
+	require.NoError(t, err)
+	match, err := eval.Evaluate(row{Name: sql.NullString{String: "x", Valid: true}, Cnt: sql.NullInt64{Int64: 3, Valid: true}})
+	require.NoError(t, err)
+	require.True(t, match)
+	match, err = eval.Evaluate(row{Name: sql.NullString{String: "x", Valid: true}})
+	require.NoError(t, err)
+	require.False(t, match)
+}
+
+func TestCompile_BSONObjectID(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		ID testObjectID
+	}
+
+	eval, err := Compile[record](`ID == "deadbeef0000000000000000"`)
+	require.NoError(t, err)
+	match, err := eval.Evaluate(record{ID: testObjectID{0xde, 0xad, 0xbe, 0xef}})
+	require.NoError(t, err)
+	require.True(t, match)
+}
+
+func TestCompile_TagNames(t *testing.T) {
+	t.Parallel()
+
+	eval, err := Compile[taggedService](`name == "web" and port == 80`, WithTagNames("bexpr", "json"))
+	require.NoError(t, err)
+	match, err := eval.Evaluate(taggedService{Name: "web", Port: 80})
+	require.NoError(t, err)
+	require.True(t, match)
+
+	_, err = Compile[taggedService](`Port == 80`, WithTagNames("bexpr", "json"))
+	require.EqualError(t, err, `error finding value in schema: /Port at part 0: couldn't find key: struct field with name "Port" (did you mean port?)`)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gterranova/go-bexpr/grammar"
+)
+
+// Validate checks the expression against the schema. It reports selectors
+// which cannot be resolved and operator/type combinations which are
+// guaranteed to fail at evaluation time.
+func (eval *Evaluator) Validate(schema Schema) error {
+	schema = eval.schemaWithTagName(schema)
+	if eval.fieldDocs != nil {
+		schema = &docsSchema{Schema: schema, docs: eval.fieldDocs}
+	}
+	if errs := validate(grammar.InlineLets(eval.ast), schema, false); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateAll checks the expression against the schema like Validate, but
+// reports every problem found rather than the first one, so that they can all
+// be shown at once. The error returned, if any, is a ValidationErrors.
+func (eval *Evaluator) ValidateAll(schema Schema) error {
+	schema = eval.schemaWithTagName(schema)
+	if eval.fieldDocs != nil {
+		schema = &docsSchema{Schema: schema, docs: eval.fieldDocs}
+	}
+	errs := validate(grammar.InlineLets(eval.ast), schema, true)
+	if len(errs) == 0 {
+		return nil
+	}
+	return ValidationErrors(errs)
+}
+
+// ValidationErrors lists the problems ValidateAll found, in the order of the
+// expression. The problems of match expressions are *EvaluationError errors,
+// locating the match expression within the expression.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the problems, for errors.Is and errors.As
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// schemaWithTagName configures type schemas to resolve struct fields the same
+// way the evaluator does.
+func (eval *Evaluator) schemaWithTagName(schema Schema) Schema {
+	if ts, ok := schema.(*typeSchema); ok && ts.tagName == "" && ts.tagNames == nil {
+		return &typeSchema{typ: ts.typ, tagName: eval.tagName, tagNames: eval.tagNames}
+	}
+	return schema
+}
+
+// validate returns the problems of the expression: the first one only unless
+// all is set, when the problems of match expressions are located too
+func validate(ast interface{}, schema Schema, all bool) []error {
+	switch node := ast.(type) {
+	case *grammar.UnaryExpression:
+		return validate(node.Operand, schema, all)
+	case *grammar.BinaryExpression:
+		errs := validate(node.Left, schema, all)
+		if len(errs) > 0 && !all {
+			return errs
+		}
+		return append(errs, validate(node.Right, schema, all)...)
+	case *grammar.MatchExpression:
+		errs := validateMatchExpression(node, schema)
+		if !all {
+			if len(errs) > 1 {
+				errs = errs[:1]
+			}
+			return errs
+		}
+		for i, err := range errs {
+			errs[i] = matchError(node, err)
+		}
+		return errs
+	case *grammar.ExpressionValue:
+		if _, err := valueType(node, schema); err != nil {
+			return []error{err}
+		}
+	}
+	return nil
+}
+
+// validateMatchExpression returns the problems of the match expression: the
+// problems of both its operands, or else the problem of its operator
+func validateMatchExpression(expression *grammar.MatchExpression, schema Schema) []error {
+	var errs []error
+	leftType, err := valueType(expression.Left, schema)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	rightType, err := valueType(expression.Right, schema)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	if err := validateOperator(expression, leftType, rightType, schema); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+func validateOperator(expression *grammar.MatchExpression, leftType, rightType reflect.Type, schema Schema) error {
+
+	switch expression.Operator {
+	case grammar.MatchEqual, grammar.MatchNotEqual:
+		if isNullValue(expression.Right) {
+			return nil
+		}
+	}
+	if !SupportsOperator(expression.Operator, leftType) {
+		err := &UnsupportedOperatorError{Operator: expression.Operator, Type: derefType(leftType)}
+		if doc, ok := selectorDoc(expression.Left, schema); ok {
+			return fmt.Errorf("%s: %w (%s)", expression.Left, err, doc)
+		}
+		return fmt.Errorf("%s: %w", expression.Left, err)
+	}
+
+	switch expression.Operator {
+	case grammar.MatchMatches, grammar.MatchNotMatches:
+		if rightType != nil && rightType.Kind() == reflect.String && isConstant(expression.Right) {
+			pattern, err := getExprValue(expression.Right, nil)
+			if err != nil {
+				return err
+			}
+			if _, err := regexp.Compile(pattern.(string)); err != nil {
+				return fmt.Errorf("failed to compile regular expression %q: %v", pattern, err)
+			}
+		}
+	case grammar.MatchLike, grammar.MatchNotLike:
+		if rightType != nil && rightType.Kind() == reflect.String && isConstant(expression.Right) {
+			pattern, err := getExprValue(expression.Right, nil)
+			if err != nil {
+				return err
+			}
+			if _, err := path.Match(pattern.(string), ""); err != nil {
+				return fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// valueType returns the type an expression value will have at evaluation
+// time. A nil type is returned when it cannot be determined statically.
+func valueType(ast interface{}, schema Schema) (reflect.Type, error) {
+	switch node := ast.(type) {
+	case *grammar.ExpressionValue:
+		if node == nil {
+			return nil, nil
+		}
+		leftType, err := valueType(node.Left, schema)
+		if err != nil {
+			return nil, err
+		}
+		if node.Operator == grammar.MathOpValue {
+			return leftType, nil
+		}
+		if _, err := valueType(node.Right, schema); err != nil {
+			return nil, err
+		}
+		if isConstant(node) {
+			// math on literals, such as 1 / 0, fails at every evaluation
+			if _, err := getExprValue(node, nil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case *grammar.MatchValue:
+		switch node.Type {
+		case grammar.ValueTypeBool:
+			return reflect.TypeOf(false), nil
+		case grammar.ValueTypeInt:
+			return reflect.TypeOf(int64(0)), nil
+		case grammar.ValueTypeUint:
+			return reflect.TypeOf(uint64(0)), nil
+		case grammar.ValueTypeFloat64:
+			return reflect.TypeOf(float64(0)), nil
+		case grammar.ValueTypeString:
+			return reflect.TypeOf(""), nil
+		case grammar.ValueTypeCall:
+			return callType(node, schema)
+		case grammar.ValueTypeReflect:
+			if len(node.Selector.Ranges) > 0 {
+				typ, err := rangeSelectorType(schema, node.Selector)
+				if err != nil {
+					return nil, &UnknownSelectorError{Selector: node.Selector.String(), Schema: true, Err: err}
+				}
+				return typ, nil
+			}
+			typ, err := schema.SelectorType(node.Selector.Path)
+			if err != nil {
+				return nil, &UnknownSelectorError{
+					Selector:    node.Selector.String(),
+					Schema:      true,
+					Suggestions: suggestSelectors(schema, node.Selector),
+					Err:         err,
+				}
+			}
+			return typ, nil
+		}
+	}
+	return nil, nil
+}
+
+// selectorDoc returns the documentation of the selector the expression value
+// consists of, if any.
+func selectorDoc(expr *grammar.ExpressionValue, schema Schema) (FieldDoc, bool) {
+	documented, ok := schema.(DocumentedSchema)
+	if !ok || expr == nil || expr.Operator != grammar.MathOpValue {
+		return FieldDoc{}, false
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	if !ok || value.Type != grammar.ValueTypeReflect {
+		return FieldDoc{}, false
+	}
+	return documented.SelectorDoc(value.Selector.Path)
+}
+
+func isNullValue(expr *grammar.ExpressionValue) bool {
+	if expr == nil || expr.Operator != grammar.MathOpValue {
+		return false
+	}
+	value, ok := expr.Left.(*grammar.MatchValue)
+	return ok && value.Type == grammar.ValueTypeNull
+}
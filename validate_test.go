@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package bexpr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	type testCase struct {
+		expression string
+		schema     Schema
+		opts       []Option
+		err        string
+	}
+
+	tests := map[string]testCase{
+		"valid flat struct": {
+			expression: "Int == 3 and String matches `^a` and Bool != false and Float32 < 1.5",
+			schema:     TypeSchema(testFlatStruct{}),
+		},
+		"valid nested": {
+			expression: `Nested.Map.foo == "bar" and 3 in Nested.SliceOfInts and Nested.MapOfStructs.one.Foo > 1 and Nested.SliceOfStructs.0.X == 1 and Nested.MapInfInf is not empty`,
+			schema:     TypeSchema(testNestedTypes{}),
+		},
+		"valid interface values": {
+			expression: `Nested.SliceOfInfs.0 matches "x" and Nested.MapInfInf.foo.bar is empty`,
+			schema:     TypeSchema(&testNestedTypes{}),
+		},
+		"valid null comparison": {
+			expression: "Nested.Map == null",
+			schema:     TypeSchema(testNestedTypes{}),
+		},
+		"custom tag name": {
+			expression: `slash/value == "x"`,
+			schema:     TypeSchema(testFlatStruct{}),
+		},
+		"unknown field": {
+			expression: "Nested.Nope == 3",
+			schema:     TypeSchema(testNestedTypes{}),
+			err:        `error finding value in schema: /Nested/Nope at part 1: couldn't find key: struct field with name "Nope"`,
+		},
+		"hidden field": {
+			expression: "Hidden == true",
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `error finding value in schema: /Hidden at part 0: struct field "Hidden" is ignored and cannot be used`,
+		},
+		"unexported field": {
+			expression: `unexported == "x"`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `error finding value in schema: /unexported at part 0: couldn't find key: struct field with name "unexported"`,
+		},
+		"selector through primitive": {
+			expression: "Int.foo == 3",
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        "error finding value in schema: /Int/foo: at part 1, invalid value kind: int",
+		},
+		"matches on number": {
+			expression: `Int matches "1"`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `Int: operator "Matches" cannot be used with values of type int`,
+		},
+		"invalid regular expression": {
+			expression: `String matches "("`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        "failed to compile regular expression \"(\": error parsing regexp: missing closing ): `(`",
+		},
+		"like on number": {
+			expression: `Int like "1*"`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `Int: operator "Like" cannot be used with values of type int`,
+		},
+		"invalid glob pattern": {
+			expression: `String like "["`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `invalid glob pattern "[": syntax error in pattern`,
+		},
+		"is empty on bool": {
+			expression: "Bool is empty",
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `Bool: operator "Is Empty" cannot be used with values of type bool`,
+		},
+		"in on struct": {
+			expression: `"Map" in Nested`,
+			schema:     TypeSchema(testNestedTypes{}),
+			err:        `Nested: operator "In" cannot be used with values of type bexpr.testNestedLevel1`,
+		},
+		"equality on slice": {
+			expression: "Nested.SliceOfInts == 3",
+			schema:     TypeSchema(testNestedTypes{}),
+			err:        `Nested.SliceOfInts: operator "Equal" cannot be used with values of type []int`,
+		},
+		"startswith on number": {
+			expression: `Float64 startswith "1"`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `Float64: operator "Starts With" cannot be used with values of type float64`,
+		},
+		"ordering on string": {
+			expression: `String < "b"`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `String: operator "Lower" cannot be used with values of type string`,
+		},
+		"error in negated branch": {
+			expression: "not (Int == 1 or Bool is not empty)",
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `Bool: operator "Is Not Empty" cannot be used with values of type bool`,
+		},
+		"math on literals": {
+			expression: `Int == 60 / 0`,
+			schema:     TypeSchema(testFlatStruct{}),
+			err:        `integer division by zero`,
+		},
+		"json tag": {
+			expression: `"/jname" == "x"`,
+			schema: TypeSchema(struct {
+				Name string `json:"jname"`
+			}{}),
+			opts: []Option{WithTagName("json")},
+		},
+	}
+
+	for name, tcase := range tests {
+		tcase := tcase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			eval, err := CreateEvaluator(tcase.expression, append(tcase.opts, WithSchema(tcase.schema))...)
+			if tcase.err != "" {
+				require.EqualError(t, err, tcase.err)
+				require.Nil(t, eval)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, eval)
+			}
+		})
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	t.Parallel()
+
+	eval, err := CreateEvaluator("Int == 3 and Nope.a == Nope.b or\n" +
+		"not (String < \"b\" or Bool is not empty) and\n" +
+		"String matches \"(\" and Int + 1 > 2 / 0 and Float32 > 1")
+	require.NoError(t, err)
+
+	err = eval.ValidateAll(TypeSchema(testFlatStruct{}))
+	var errs ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	require.Equal(t, []string{
+		`1:14 (13): Nope.a == Nope.b: error finding value in schema: /Nope/a at part 0: couldn't find key: struct field with name "Nope"`,
+		`1:14 (13): Nope.a == Nope.b: error finding value in schema: /Nope/b at part 0: couldn't find key: struct field with name "Nope"`,
+		`2:6 (38): String < "b": String: operator "Lower" cannot be used with values of type string`,
+		`2:22 (54): Bool is not empty: Bool: operator "Is Not Empty" cannot be used with values of type bool`,
+		"3:1 (77): String matches \"(\": failed to compile regular expression \"(\": error parsing regexp: missing closing ): `(`",
+		`3:24 (100): Int + 1 > 2 / 0: integer division by zero`,
+	}, errorMessages(errs))
+	require.ErrorIs(t, err, ErrUnknownSelector)
+	require.ErrorIs(t, err, ErrUnsupportedOperator)
+
+	var evalErr *EvaluationError
+	require.True(t, errors.As(errs[2], &evalErr))
+	require.Equal(t, 2, evalErr.Position.Line)
+
+	// Validate reports the first problem only
+	require.EqualError(t, eval.Validate(TypeSchema(testFlatStruct{})), `error finding value in schema: /Nope/a at part 0: couldn't find key: struct field with name "Nope"`)
+
+	eval, err = CreateEvaluator(`Int == 3 and String matches "a"`)
+	require.NoError(t, err)
+	require.NoError(t, eval.ValidateAll(TypeSchema(testFlatStruct{})))
+}
+
+func errorMessages(errs []error) []string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return msgs
+}